@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/Zubimendi/solsec/internal/abi"
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/Zubimendi/solsec/internal/source"
+	"github.com/spf13/cobra"
+)
+
+var abiDiffCmd = &cobra.Command{
+	Use:   "abi-diff <old> <new>",
+	Short: "Report changes to the external functions and events between two versions",
+	Long: `Compile two versions of the contracts with solc and report the external
+functions and events that were added, removed or changed (parameters,
+return types, mutability or indexed event parameters). Contracts are matched
+by name.
+
+Every change to the public surface deserves a security review, so any
+change not listed with --allow makes the command fail. Allow expected
+changes as Contract.signature, e.g. --allow "Vault.mint(address,uint256)".
+
+solc must be on PATH. Remappings are read from remappings.txt.
+
+Examples:
+  solsec abi-diff ../v1/contracts ./contracts
+  solsec abi-diff ../v1/contracts ./contracts --allow "Vault.mint(address,uint256)" -f json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		allow, _ := cmd.Flags().GetStringSlice("allow")
+		contract, _ := cmd.Flags().GetString("contract")
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported format %q (use table or json)", format)
+		}
+
+		old, err := compileABI(cmd, args[0], contract)
+		if err != nil {
+			return err
+		}
+		upgraded, err := compileABI(cmd, args[1], contract)
+		if err != nil {
+			return err
+		}
+		changes := abi.Diff(old, upgraded)
+
+		if format == "json" {
+			if err := writeJSON(os.Stdout, changes); err != nil {
+				return err
+			}
+		} else {
+			printABIChanges(os.Stdout, changes, allow)
+		}
+		unexpected := 0
+		for _, c := range changes {
+			if !slices.Contains(allow, c.ID()) {
+				unexpected++
+			}
+		}
+		if unexpected > 0 {
+			fmt.Fprintf(os.Stderr, "FAIL: %d unexpected change(s) to the external surface\n", unexpected)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// compileABI returns the ABI entries of the contracts in target, or only of
+// contract when it is set.
+func compileABI(cmd *cobra.Command, target, contract string) ([]abi.Entry, error) {
+	files, err := source.SolidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Solidity files in %s", target)
+	}
+	out, err := runner.SolcCombinedJSON(cmd.Context(), "abi", files)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := abi.FromCombinedJSON(out)
+	if err != nil || contract == "" {
+		return entries, err
+	}
+	var kept []abi.Entry
+	for _, e := range entries {
+		if e.Contract == contract {
+			kept = append(kept, e)
+		}
+	}
+	return kept, nil
+}
+
+func printABIChanges(w io.Writer, changes []abi.Change, allow []string) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "✅ No changes to external functions or events")
+		return
+	}
+	marks := map[string]string{abi.Added: "+", abi.Removed: "-", abi.Changed: "~"}
+	for _, c := range changes {
+		note := ""
+		if slices.Contains(allow, c.ID()) {
+			note = " (allowed)"
+		}
+		fmt.Fprintf(w, "  %s %-8s %s%s\n", marks[c.Change], c.Kind, c.ID(), note)
+		if c.Detail != "" {
+			fmt.Fprintf(w, "             %s\n", c.Detail)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(abiDiffCmd)
+
+	f := abiDiffCmd.Flags()
+	f.StringP("format", "f", "table", "Output format: table, json")
+	f.StringSlice("allow", nil, "Expected change, as Contract.signature (repeatable)")
+	f.String("contract", "", "Only compare this contract")
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/admins"
+	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/explorer"
+	"github.com/Zubimendi/solsec/internal/impact"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/spf13/cobra"
+)
+
+var adminsCmd = &cobra.Command{
+	Use:   "admins --rpc <url>",
+	Short: "Check that deployed contracts are administered by multisigs, not single keys",
+	Long: `Check who holds the admin rights of deployed contracts. For each contract
+with a known address, admins reads its owner() and its EIP-1967 proxy admin
+over JSON-RPC, and the owner of that admin when it is a ProxyAdmin contract.
+Each address is classified as an EOA, a Safe multisig (with its threshold), a
+timelock or another contract. Addresses declared with --admin are classified
+too.
+
+Admins that a single key controls, an EOA or a 1-of-n Safe, are added to the
+report as findings: High for a proxy admin or for an owner of high-value
+functions (upgrades, minting, withdrawals, pausing, fee and oracle setters),
+Medium otherwise. The guarded functions they can call come from the report's
+access-control matrix.
+
+Deployed addresses come from the report of "solsec system", from --address,
+or from a --deployment record: a Foundry broadcast (run-latest.json) or a
+hardhat-deploy network directory.
+
+Examples:
+  solsec admins --rpc $ETH_RPC_URL --address Vault=0x...
+  solsec admins --rpc $ETH_RPC_URL --deployment broadcast/Deploy.s.sol/1/run-latest.json
+  solsec admins --rpc $ETH_RPC_URL --deployment deployments/mainnet --admin Vault=0x... -o admins.html`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpc, _ := cmd.Flags().GetString("rpc")
+		reportPath, _ := cmd.Flags().GetString("report")
+		outputPath, _ := cmd.Flags().GetString("output")
+		addressSpecs, _ := cmd.Flags().GetStringSlice("address")
+		deployment, _ := cmd.Flags().GetString("deployment")
+		adminSpecs, _ := cmd.Flags().GetStringSlice("admin")
+		block, _ := cmd.Flags().GetUint64("block")
+		if outputPath == "" {
+			outputPath = reportPath
+		}
+		streamToStdout(outputPath)
+		format := strings.TrimPrefix(filepath.Ext(outputPath), ".")
+
+		declared, err := parseAdmins(adminSpecs)
+		if err != nil {
+			return err
+		}
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		addresses, err := deployedAddresses(report, addressSpecs)
+		if err != nil {
+			return err
+		}
+		if deployment != "" {
+			deployed, err := admins.LoadDeployment(deployment)
+			if err != nil {
+				return fmt.Errorf("reading deployment %s: %w", deployment, err)
+			}
+			// Addresses passed on the command line win over the record.
+			for name, address := range deployed {
+				if _, ok := addresses[name]; !ok {
+					addresses[name] = address
+				}
+			}
+		}
+		if len(addresses) == 0 {
+			return fmt.Errorf("no deployed addresses: pass --address, --deployment or a report of \"solsec system\"")
+		}
+
+		names := make([]string, 0, len(addresses))
+		for name := range addresses {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		inspector := &admins.Inspector{Client: &impact.Client{URL: rpc, Block: block}}
+		var findings []parser.Finding
+		fmt.Println()
+		for _, name := range names {
+			fmt.Printf("🔑 Reading the admins of %s at %s...\n", name, addresses[name])
+			found, err := inspector.Admins(cmd.Context(), addresses[name], declared[name])
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if len(found) == 0 {
+				fmt.Println("  no owner() or proxy admin")
+			}
+			for _, a := range found {
+				marker := "✓"
+				if a.SingleSigner() {
+					marker = "✗"
+				}
+				fmt.Printf("  %s %-18s %s\n", marker, a.Role, a)
+			}
+			findings = append(findings, admins.Findings(name, found, report.AccessMatrix)...)
+		}
+		// IDs are numbered per contract; renumber them across the report.
+		for i := range findings {
+			findings[i].ID = fmt.Sprintf("CUSTOM-ADMIN-%d", i+1)
+		}
+		// Replace the findings of an earlier run instead of repeating them.
+		kept := report.Findings[:0]
+		for _, f := range report.Findings {
+			if f.Check != "custom-eoa-admin" {
+				kept = append(kept, f)
+			}
+		}
+		report.Findings = append(kept, findings...)
+		if err := analyzer.Process(report, analyzer.Options{Pipeline: []string{}}); err != nil {
+			return err
+		}
+
+		score := scorer.Score(report)
+		if err := writeReport(report, score, format, outputPath, reportOptionsFromFlags(cmd)); err != nil {
+			return err
+		}
+		fmt.Printf("\n📊 %d admin(s) held by a single key across %d contract(s) — written to %s\n",
+			len(findings), len(names), displayPath(outputPath))
+		return nil
+	},
+}
+
+// parseAdmins reads Contract=0x... admin flags.
+func parseAdmins(specs []string) (map[string][]string, error) {
+	declared := map[string][]string{}
+	for _, spec := range specs {
+		name, address, ok := strings.Cut(spec, "=")
+		if !ok || name == "" || !explorer.IsAddress(address) {
+			return nil, fmt.Errorf("invalid --admin %q: want Contract=0x followed by 40 hex digits", spec)
+		}
+		declared[name] = append(declared[name], address)
+	}
+	return declared, nil
+}
+
+func init() {
+	rootCmd.AddCommand(adminsCmd)
+
+	f := adminsCmd.Flags()
+	f.String("rpc", "", "RPC URL of the chain the contracts are deployed on (required)")
+	f.String("report", "solsec-report.json", "JSON report to add admin findings to")
+	f.StringP("output", "o", "", "Output file path; the format follows the extension, or - for HTML on stdout (default: update --report in place)")
+	f.StringSlice("address", nil, "Deployed address of a contract, as Contract=0x... (repeatable)")
+	f.String("deployment", "", "Foundry broadcast file or hardhat-deploy directory to read deployed addresses from")
+	f.StringSlice("admin", nil, "Address declared to administer a contract, as Contract=0x... (repeatable)")
+	f.Uint64("block", 0, "Read admins at this block number instead of the latest")
+	f.BoolP("verbose", "v", false, "Include finding evidence in HTML and SARIF output")
+	f.Int("page-size", reporter.DefaultPageSize, "Paginate the HTML findings table above this many findings (0 disables paging)")
+	f.Bool("deterministic", false, "Omit the generation time (or take it from SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports")
+	f.String("repo-root", "", "Repository root that SARIF, GitLab and Code Climate file paths are relative to (default: the enclosing git repository)")
+	f.String("template", "", "Custom HTML report template: a complete template, or {{define}}s of its head, logo, disclaimer and footer blocks")
+	_ = adminsCmd.MarkFlagRequired("rpc")
+}
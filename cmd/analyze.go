@@ -1,33 +1,62 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/baseline"
+	"github.com/Zubimendi/solsec/internal/cache"
+	"github.com/Zubimendi/solsec/internal/ci"
+	"github.com/Zubimendi/solsec/internal/config"
+	"github.com/Zubimendi/solsec/internal/history"
+	"github.com/Zubimendi/solsec/internal/lock"
 	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/policy"
 	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/rules"
 	"github.com/Zubimendi/solsec/internal/runner"
 	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/Zubimendi/solsec/internal/source"
+	"github.com/Zubimendi/solsec/internal/triage"
 )
 
 var analyzeCmd = &cobra.Command{
-	Use:   "analyze <target>",
+	Use:   "analyze [target]",
 	Short: "Analyze a Solidity contract or directory for security vulnerabilities",
 	Long: `Run security analysis on a Solidity file or directory.
 
 Combines Slither's detector engine with custom Go checks for reentrancy,
 access control gaps, and integer overflow patterns.
 
+Profiles select engines, detectors, custom checks and timeouts in one flag:
+  quick     custom vulnerability checks only, no Slither or hygiene checks
+  standard  Slither without style detectors + default custom checks (default)
+  deep      all Slither detectors + Mythril + every custom check, opt-in
+            ones included, extended timeouts
+
 Examples:
   solsec analyze ./contracts/Token.sol
   solsec analyze ./contracts --format html --output report.html
   solsec analyze ./contracts --format sarif --output results.sarif
-  solsec analyze ./contracts --fail-on high --ci`,
-	Args: cobra.ExactArgs(1),
+  solsec analyze ./contracts --profile quick
+  solsec analyze ./contracts --contract Token --function mint,burn
+  solsec analyze ./contracts --fail-on high --ci
+  solsec analyze ./contracts --fail-on medium --max-medium 3 --ci
+  solsec analyze ./contracts -f json --stdout-summary
+  solsec analyze --workspace`,
+	Args: cobra.RangeArgs(0, 1),
 	RunE: runAnalyze,
 }
 
@@ -35,139 +64,977 @@ func init() {
 	rootCmd.AddCommand(analyzeCmd)
 
 	f := analyzeCmd.Flags()
-	f.StringP("format", "f", "html", "Output format: json | html | sarif")
-	f.StringP("output", "o", "", "Output file path (default: solsec-report.<format>)")
+	f.StringP("format", "f", "html", "Output format: json | ndjson | html | sarif | pdf | csv | junit | gitlab | codeclimate")
+	f.StringP("output", "o", "", "Output file path, or - for stdout with progress on stderr (default: solsec-report.<format>)")
 	f.StringP("fail-on", "", "high", "Exit with code 1 if findings at this severity or above are found: critical | high | medium | low | none")
-	f.BoolP("ci", "", false, "CI mode: minimal output, exit code reflects findings")
+	f.Int("max-critical", 0, "Exit with code 1 if more than this many Critical findings are found, overriding --fail-on for Critical")
+	f.Int("max-high", 0, "Exit with code 1 if more than this many High findings are found, overriding --fail-on for High")
+	f.Int("max-medium", 0, "Exit with code 1 if more than this many Medium findings are found, overriding --fail-on for Medium")
+	f.BoolP("quiet", "q", false, "Print nothing but errors (to stderr); the exit code reflects findings")
+	f.Bool("stdout-summary", false, "Print exactly one JSON summary document (score, grade, counts, pass/fail) on stdout and nothing else")
+	f.BoolP("ci", "", false, "CI mode: minimal output, exit code reflects findings (default: on under GitHub Actions, GitLab CI, CircleCI, Jenkins)")
 	f.StringSlice("exclude", nil, "Slither detector names to exclude e.g. --exclude timestamp,tautology")
+	f.StringSlice("exclude-paths", nil, "Leave out paths matching this gitignore-style glob, relative to the working directory, from Slither and custom checks (repeatable) e.g. --exclude-paths 'lib/**'")
+	f.StringSlice("only", nil, "Run only these Slither detectors e.g. --only reentrancy-eth,tx-origin")
 	f.String("solc", "", "Pin a specific solc version e.g. --solc 0.8.24")
 	f.Bool("no-slither", false, "Skip Slither, run only custom Go checks")
+	f.StringSlice("checks", nil, "Custom checks to run e.g. --checks reentrancy,access-control (default: all but secrets)")
+	f.Bool("scan-secrets", false, "Also scan every file in the repository for committed private keys, mnemonics, keyed RPC URLs and .env files")
+	f.StringSlice("include-tags", nil, "Only report findings with one of these tags e.g. --include-tags reentrancy,defi")
+	f.StringSlice("exclude-tags", nil, "Drop findings with any of these tags e.g. --exclude-tags gas,code-quality")
+	f.String("min-confidence", "", "Drop findings below this confidence from the report and score: high | medium | low")
+	f.String("min-severity", "", "Drop findings below this severity from the report and score: critical | high | medium | low | informational")
+	f.StringSlice("contract", nil, "Only report findings inside these contracts e.g. --contract Token")
+	f.StringSlice("function", nil, "Only report findings inside these functions e.g. --function mint,burn")
+	f.String("profile", "standard", "Analysis profile: quick | standard | deep")
+	f.String("archetype", "", "Tune checks, severities and the report to a kind of contract: "+strings.Join(analyzer.ArchetypeNames(), " | "))
+	f.Bool("mythril", false, "Also run Mythril symbolic execution (enabled by --profile deep)")
+	f.Duration("timeout", 0, "Slither timeout e.g. --timeout 10m (default: set by profile)")
+	f.Duration("check-timeout", analyzer.DefaultCheckTimeout, "Give up on a custom check running longer than this; the report notes it as degraded")
+	f.Bool("workspace", false, "Analyze every package defined under workspace.packages in .solsec.yaml")
+	f.Bool("no-cache", false, "Always run Slither instead of reusing cached results for unchanged inputs")
+	f.Duration("cache-ttl", cache.DefaultTTL, "Maximum age of cached Slither results e.g. --cache-ttl 1h")
+	f.Bool("no-cluster", false, "List every finding separately instead of grouping those that share a root cause")
+	f.Bool("write-lock", false, "Record this run's toolchain (solsec, rules DB, Slither, detectors, solc) in "+lock.DefaultPath)
+	f.Bool("locked", false, "Refuse to run when the toolchain differs from "+lock.DefaultPath)
+	f.Bool("locked-warn", false, "Like --locked, but only warn about differences")
+	f.String("max-file-size", "", "Skip files larger than this in custom checks e.g. --max-file-size 2MB, 0 for no limit (default: 1MB)")
+	f.Bool("partial-compile", false, "On compile errors, report them as findings and analyze the files that compile")
+	f.Bool("suggest-config", false, "Print a noise budget per detector and suggested .solsec.yaml tuning snippets")
+	f.Bool("suggest-invariants", false, "Write Foundry invariant test stubs for the target's contracts to test/invariants/")
+	f.BoolP("verbose", "v", false, "Include each custom finding's evidence (matched pattern and rule trace) in HTML and SARIF reports")
+	f.Int("page-size", reporter.DefaultPageSize, "Paginate the HTML findings table above this many findings (0 disables paging)")
+	f.Bool("deterministic", false, "Omit the generation time (or take it from SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports")
+	f.String("repo-root", "", "Repository root that SARIF, GitLab and Code Climate file paths are relative to (default: the enclosing git repository)")
+	f.String("template", "", "Custom HTML report template: a complete template, or {{define}}s of its head, logo, disclaimer and footer blocks")
+	f.String("triage-database", triage.DefaultPath, "Slither triage database; findings recorded in it are hidden (see solsec triage)")
+	f.String("baseline", "", "Baseline file of known findings to leave out, so only new ones are reported and fail the run (see solsec baseline create)")
+	f.String("history", "", "Run history store; embeds severity trends in the report (default: "+history.DefaultPath+" if it exists)")
+
+	_ = analyzeCmd.RegisterFlagCompletionFunc("format", completeFormats)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("fail-on", completeSeverities)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("exclude", completeDetectors)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("only", completeDetectors)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("checks", completeChecks)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("include-tags", completeTags)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("exclude-tags", completeTags)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("min-confidence", completeConfidences)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("min-severity", completeMinSeverities)
+	_ = analyzeCmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var out []string
+		for _, name := range profileNames() {
+			out = append(out, name+"\t"+profiles[name].Description)
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = analyzeCmd.RegisterFlagCompletionFunc("archetype", completeArchetypes)
+}
+
+// analysisConfig is everything the analysis pipeline needs. runAnalyze builds
+// it from flags and the selected profile; other commands build it directly.
+type analysisConfig struct {
+	Target         string
+	Exclude        []string
+	ExcludePaths   []string
+	Only           []string
+	SolcVersion    string
+	Framework      string
+	NoSlither      bool
+	Mythril        bool
+	SlitherTimeout time.Duration
+	MythrilTimeout time.Duration
+	Checks         []string
+	PartialCompile bool
+
+	// Scope restricts reported findings to named contracts and functions.
+	Scope *parser.Scope
+
+	// IncludeTags and ExcludeTags filter findings by category.
+	IncludeTags []string
+	ExcludeTags []string
+
+	// MinConfidence drops findings less certain than this level; see
+	// analyzer.ParseConfidence.
+	MinConfidence string
+
+	// MinSeverity drops findings less severe than this level; see
+	// analyzer.ParseMinSeverity.
+	MinSeverity string
+
+	// NoCluster keeps findings that share a root cause separate.
+	NoCluster bool
+
+	// Pipeline orders the report post-processing stages; nil means
+	// analyzer.DefaultPipeline.
+	Pipeline []string
+
+	// SeverityOverrides maps rule names to the severity their findings
+	// get; see analyzer.ParseSeverityOverrides.
+	SeverityOverrides map[string]string
+
+	// PathSeverity shifts the severity of findings by the files they are
+	// in; see analyzer.AdjustPathSeverities.
+	PathSeverity []analyzer.PathSeverity
+
+	// Limits guard custom checks against oversized and binary files.
+	Limits source.Limits
+
+	// CheckTimeout bounds each custom check; zero means
+	// analyzer.DefaultCheckTimeout.
+	CheckTimeout time.Duration
+
+	// Archetype names the kind of contract being analyzed; its checks are
+	// already in Checks.
+	Archetype string
+
+	// NoCache bypasses the engine results cache; CacheTTL bounds entry age
+	// (zero means cache.DefaultTTL).
+	NoCache  bool
+	CacheTTL time.Duration
+
+	// Locked is the environment pinned by solsec.lock; the run is refused
+	// when its own differs, or only warned about under LockWarn.
+	Locked   *parser.Environment
+	LockWarn bool
+
+	// Triage holds the findings reviewed and hidden in slither.db.json;
+	// nil applies none.
+	Triage *triage.DB
+
+	// Baseline holds the known findings left out of the report; nil
+	// applies none.
+	Baseline *baseline.Baseline
+
+	// Quiet suppresses progress output (CI mode).
+	Quiet bool
+}
+
+func (c analysisConfig) logf(format string, args ...any) {
+	if !c.Quiet {
+		fmt.Printf(format, args...)
+	}
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) error {
-	target := args[0]
 	format, _ := cmd.Flags().GetString("format")
 	outputPath, _ := cmd.Flags().GetString("output")
-	failOn, _ := cmd.Flags().GetString("fail-on")
-	ciMode, _ := cmd.Flags().GetBool("ci")
-	exclude, _ := cmd.Flags().GetStringSlice("exclude")
-	solcVersion, _ := cmd.Flags().GetString("solc")
-	noSlither, _ := cmd.Flags().GetBool("no-slither")
+	failPol, err := failPolicyFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	workspace, _ := cmd.Flags().GetBool("workspace")
+	reportOpts := reportOptionsFromFlags(cmd)
 
+	var mode outputMode
+	mode.CI, _ = cmd.Flags().GetBool("ci")
+	mode.Quiet, _ = cmd.Flags().GetBool("quiet")
+	mode.StdoutSummary, _ = cmd.Flags().GetBool("stdout-summary")
+
+	// Under a recognized CI provider, default to CI mode, annotate findings
+	// in the provider's format and write the report into its workspace.
+	mode.Provider = ci.Detect(os.Getenv)
+	if mode.Provider != nil && !cmd.Flags().Changed("ci") {
+		mode.CI = true
+	}
+
+	// Fail before a long analysis rather than when writing the report.
+	if strings.EqualFold(format, "pdf") {
+		if _, err := reporter.DetectPDFEngine(); err != nil {
+			return err
+		}
+	}
+	if reportOpts.Template != "" {
+		if err := reporter.ValidateTemplate(reportOpts.Template); err != nil {
+			return err
+		}
+	}
+
+	if outputPath == stdoutPath {
+		if mode.StdoutSummary {
+			return fmt.Errorf("--stdout-summary and -o - both write to stdout; use one")
+		}
+		if workspace {
+			return fmt.Errorf("--workspace writes a report per package; -o - cannot hold them, give a file path")
+		}
+		streamToStdout(outputPath)
+	}
 	if outputPath == "" {
 		outputPath = fmt.Sprintf("solsec-report.%s", format)
+		if mode.Provider != nil {
+			outputPath = mode.Provider.ReportPath(outputPath)
+		}
+	}
+
+	if workspace {
+		if len(args) > 0 {
+			return fmt.Errorf("--workspace analyzes the configured packages; do not pass a target")
+		}
+		if cmd.Flags().Changed("contract") || cmd.Flags().Changed("function") {
+			return fmt.Errorf("--contract and --function scope a single target; they cannot be combined with --workspace")
+		}
+		if writeLock, _ := cmd.Flags().GetBool("write-lock"); writeLock {
+			return fmt.Errorf("--write-lock records a single target's toolchain; it cannot be combined with --workspace")
+		}
+		return runWorkspace(cmd, format, outputPath, failPol, reportOpts, mode)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("requires a target (a .sol file or directory), or --workspace")
 	}
 
+	profileName, _ := cmd.Flags().GetString("profile")
+	cfg, err := analysisConfigFromFlags(cmd, args[0], profileName)
+	if err != nil {
+		return err
+	}
+	cfg.Quiet = mode.silent()
+
+	report, err := analyzeTarget(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+
+	// Step 5: Score
+	score := scorer.Score(report)
+	grade := scorer.Grade(score)
+	verdict := scorer.Verdict(score)
+
+	if err := attachHistory(cmd, report, score); err != nil {
+		return err
+	}
+	if writeLock, _ := cmd.Flags().GetBool("write-lock"); writeLock && !report.Interrupted {
+		if err := lock.Write(lock.DefaultPath, report.Environment); err != nil {
+			return err
+		}
+		cfg.logf("   🔒 Toolchain recorded in %s\n", lock.DefaultPath)
+	}
+
+	// Step 6: Write report
+	if err := writeReport(report, score, format, outputPath, reportOpts); err != nil {
+		return err
+	}
+
+	if report.Interrupted {
+		return interruptedError(outputPath)
+	}
+
+	// Step 7: Print summary
+	if !mode.silent() {
+		printSummary(report, score, grade, verdict, outputPath)
+	}
+	if suggest, _ := cmd.Flags().GetBool("suggest-config"); suggest {
+		printNoiseBudget(mode.textOut(), args[0], report)
+	}
+	if suggest, _ := cmd.Flags().GetBool("suggest-invariants"); suggest {
+		if err := writeInvariantSuggestions(mode.textOut(), args[0]); err != nil {
+			return err
+		}
+	}
+	mode.annotate(report.Findings)
+
+	// Step 8: Exit code for CI
+	failures := failPol.failures(report, score)
+	if mode.StdoutSummary {
+		if err := writeRunSummary(os.Stdout, report, score, outputPath, failPol, failures); err != nil {
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		if mode.failText() {
+			fmt.Printf("FAIL: %s\n", strings.Join(failures, "; "))
+		}
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// attachHistory embeds the score/severity trend from the history store in
+// report and records this run. It is a no-op unless the store exists or
+// --history names one explicitly.
+func attachHistory(cmd *cobra.Command, report *parser.AnalysisReport, score int) error {
+	path, _ := cmd.Flags().GetString("history")
+	if path == "" {
+		if !history.Exists(history.DefaultPath) {
+			return nil
+		}
+		path = history.DefaultPath
+	}
+
+	previous, err := history.Load(path)
+	if err != nil {
+		return err
+	}
+	current := parser.TrendPoint{
+		GeneratedAt: report.GeneratedAt,
+		Target:      report.Target,
+		Score:       score,
+		Summary:     report.Summary,
+	}
+	report.Trend = history.Trend(previous, current)
+	return history.Append(path, current)
+}
+
+// failPolicy decides whether a run fails. Findings at or above FailOn fail
+// it, except that a severity with a limit in Max fails it only when there
+// are more findings of that severity than the limit allows. Limits apply
+// below FailOn too. Every rule in Rules must also hold.
+type failPolicy struct {
+	FailOn string
+	Max    map[parser.Severity]int
+	Rules  []policy.Rule
+}
+
+// failPolicyFromFlags reads --fail-on and the --max-* limits, which default
+// to the max_* settings of .solsec.yaml, and compiles the policy: rules of
+// .solsec.yaml. A configured policy replaces the default --fail-on high, so
+// only the rules and explicit limits gate the run.
+func failPolicyFromFlags(cmd *cobra.Command) (failPolicy, error) {
+	flags := cmd.Flags()
+	fileCfg, err := config.Load(viper.GetViper())
+	if err != nil {
+		return failPolicy{}, err
+	}
+	p := failPolicy{Max: map[parser.Severity]int{}}
+	p.FailOn, _ = flags.GetString("fail-on")
+	for i, r := range fileCfg.Policy {
+		rule, err := policy.Compile(r.Name, r.Expr)
+		if err != nil {
+			return failPolicy{}, fmt.Errorf("policy rule #%d in .solsec.yaml: %w", i+1, err)
+		}
+		p.Rules = append(p.Rules, rule)
+	}
+	if len(p.Rules) > 0 && !flags.Changed("fail-on") {
+		p.FailOn = "none"
+	}
+	limits := []struct {
+		flag     string
+		severity parser.Severity
+		fromFile *int
+	}{
+		{"max-critical", parser.SeverityCritical, fileCfg.MaxCritical},
+		{"max-high", parser.SeverityHigh, fileCfg.MaxHigh},
+		{"max-medium", parser.SeverityMedium, fileCfg.MaxMedium},
+	}
+	for _, l := range limits {
+		switch {
+		case flags.Changed(l.flag):
+			n, _ := flags.GetInt(l.flag)
+			if n < 0 {
+				return failPolicy{}, fmt.Errorf("--%s must not be negative", l.flag)
+			}
+			p.Max[l.severity] = n
+		case l.fromFile != nil:
+			if *l.fromFile < 0 {
+				return failPolicy{}, fmt.Errorf("%s in .solsec.yaml must not be negative", strings.ReplaceAll(l.flag, "-", "_"))
+			}
+			p.Max[l.severity] = *l.fromFile
+		}
+	}
+	return p, nil
+}
+
+// withFailOn returns the policy with another fail-on severity and the same
+// limits.
+func (p failPolicy) withFailOn(failOn string) failPolicy {
+	p.FailOn = failOn
+	return p
+}
+
+// forPackage returns the policy of a workspace package: its fail_on and
+// max_* limits replace the run's unless the flags are set, and its policy
+// rules must hold too. Like a top-level policy, package rules replace the
+// default --fail-on high when the package sets no fail_on.
+func (p failPolicy) forPackage(cmd *cobra.Command, pkg config.Package) (failPolicy, error) {
+	flags := cmd.Flags()
+	if pkg.FailOn != "" && !flags.Changed("fail-on") {
+		p = p.withFailOn(pkg.FailOn)
+	}
+	rules := slices.Clone(p.Rules)
+	for i, r := range pkg.Policy {
+		rule, err := policy.Compile(r.Name, r.Expr)
+		if err != nil {
+			return failPolicy{}, fmt.Errorf("policy rule #%d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	p.Rules = rules
+	if len(pkg.Policy) > 0 && pkg.FailOn == "" && !flags.Changed("fail-on") {
+		p.FailOn = "none"
+	}
+	limits := maps.Clone(p.Max)
+	for _, l := range []struct {
+		flag     string
+		severity parser.Severity
+		limit    *int
+	}{
+		{"max-critical", parser.SeverityCritical, pkg.MaxCritical},
+		{"max-high", parser.SeverityHigh, pkg.MaxHigh},
+		{"max-medium", parser.SeverityMedium, pkg.MaxMedium},
+	} {
+		if l.limit == nil || flags.Changed(l.flag) {
+			continue
+		}
+		if *l.limit < 0 {
+			return failPolicy{}, fmt.Errorf("%s must not be negative", strings.ReplaceAll(l.flag, "-", "_"))
+		}
+		limits[l.severity] = *l.limit
+	}
+	p.Max = limits
+	return p, nil
+}
+
+// failures describes each way a report with the given score violates the
+// policy; none means the run passes.
+func (p failPolicy) failures(report *parser.AnalysisReport, score int) []string {
+	// Clusters are scored once but every occurrence counts toward the gates.
+	findings := parser.Flatten(report.Findings)
+	var failures []string
+	if p.FailOn != "none" {
+		threshold := parser.Severity(capitalize(p.FailOn))
+		n := 0
+		for _, f := range findings {
+			if _, limited := p.Max[f.Severity]; !limited && parser.SeverityRank(f.Severity) <= parser.SeverityRank(threshold) {
+				n++
+			}
+		}
+		if n > 0 {
+			failures = append(failures, fmt.Sprintf("%d finding(s) at %s severity or above", n, p.FailOn))
+		}
+	}
+	for _, s := range []parser.Severity{parser.SeverityCritical, parser.SeverityHigh, parser.SeverityMedium} {
+		limit, ok := p.Max[s]
+		if !ok {
+			continue
+		}
+		if n := countSeverity(findings, s); n > limit {
+			failures = append(failures, fmt.Sprintf("%d %s finding(s), more than the %d allowed", n, s, limit))
+		}
+	}
+	if len(p.Rules) > 0 {
+		env := policy.NewEnv(report, score)
+		for _, r := range p.Rules {
+			name := "policy"
+			if r.Name != "" {
+				name += " " + r.Name
+			}
+			switch ok, err := r.Eval(env); {
+			case err != nil:
+				failures = append(failures, fmt.Sprintf("%s: %s: %v", name, r.Expr, err))
+			case !ok:
+				failures = append(failures, fmt.Sprintf("%s failed: %s", name, r.Expr))
+			}
+		}
+	}
+	return failures
+}
+
+// limits returns the Max limits keyed by lowercase severity, for JSON.
+func (p failPolicy) limits() map[string]int {
+	if len(p.Max) == 0 {
+		return nil
+	}
+	limits := make(map[string]int, len(p.Max))
+	for s, n := range p.Max {
+		limits[strings.ToLower(string(s))] = n
+	}
+	return limits
+}
+
+// analysisConfigFromFlags resolves the named profile and overlays any flags the
+// user set explicitly.
+func analysisConfigFromFlags(cmd *cobra.Command, target, profileName string) (analysisConfig, error) {
+	flags := cmd.Flags()
+	p, err := lookupProfile(profileName)
+	if err != nil {
+		return analysisConfig{}, err
+	}
+
+	cfg := analysisConfig{
+		Target:         target,
+		NoSlither:      p.NoSlither,
+		Mythril:        p.Mythril,
+		SlitherTimeout: p.SlitherTimeout,
+		MythrilTimeout: p.MythrilTimeout,
+	}
+	cfg.Exclude, _ = flags.GetStringSlice("exclude")
+	cfg.ExcludePaths, _ = flags.GetStringSlice("exclude-paths")
+	cfg.Only, _ = flags.GetStringSlice("only")
+	cfg.SolcVersion, _ = flags.GetString("solc")
+	cfg.Checks, _ = flags.GetStringSlice("checks")
+
+	// .solsec.yaml supplies defaults for flags left unset.
+	fileCfg, err := config.Load(viper.GetViper())
+	if err != nil {
+		return analysisConfig{}, err
+	}
+	if !flags.Changed("exclude") {
+		cfg.Exclude = fileCfg.Exclude
+		if cfg.Exclude == nil && len(cfg.Only) == 0 {
+			cfg.Exclude = p.Exclude
+		}
+	}
+	if !flags.Changed("exclude-paths") {
+		cfg.ExcludePaths = fileCfg.ExcludePaths
+	}
+	if !flags.Changed("checks") {
+		cfg.Checks = fileCfg.Checks
+		if cfg.Checks == nil {
+			cfg.Checks = p.Checks
+		}
+	}
+	if scan, _ := flags.GetBool("scan-secrets"); scan {
+		cfg.Checks = withChecks(cfg.Checks, "secrets")
+	}
+	if flags.Lookup("archetype") != nil {
+		name, _ := flags.GetString("archetype")
+		if name != "" {
+			a, err := analyzer.LookupArchetype(name)
+			if err != nil {
+				return analysisConfig{}, err
+			}
+			cfg.Archetype = a.Name
+			cfg.Checks = withChecks(cfg.Checks, a.Checks...)
+		}
+	}
+	cfg.Pipeline = fileCfg.Pipeline
+	cfg.SeverityOverrides = fileCfg.SeverityOverrides
+	cfg.PathSeverity = pathSeverity(fileCfg.PathSeverity)
+	if cfg.Limits, err = resolveLimits(cmd, fileCfg); err != nil {
+		return analysisConfig{}, err
+	}
+	cfg.PartialCompile, _ = flags.GetBool("partial-compile")
+	cfg.NoCluster, _ = flags.GetBool("no-cluster")
+	if flags.Lookup("include-tags") != nil {
+		cfg.IncludeTags, _ = flags.GetStringSlice("include-tags")
+		cfg.ExcludeTags, _ = flags.GetStringSlice("exclude-tags")
+	}
+	if flags.Lookup("min-confidence") != nil {
+		cfg.MinConfidence, _ = flags.GetString("min-confidence")
+		cfg.MinSeverity, _ = flags.GetString("min-severity")
+	}
+	if flags.Lookup("contract") != nil {
+		scope := &parser.Scope{}
+		scope.Contracts, _ = flags.GetStringSlice("contract")
+		scope.Functions, _ = flags.GetStringSlice("function")
+		if !scope.Empty() {
+			cfg.Scope = scope
+		}
+	}
+	if flags.Lookup("triage-database") != nil {
+		path, _ := flags.GetString("triage-database")
+		if cfg.Triage, err = triage.Load(path); err != nil {
+			return analysisConfig{}, err
+		}
+	}
+	if flags.Lookup("baseline") != nil {
+		if path, _ := flags.GetString("baseline"); path != "" {
+			if cfg.Baseline, err = baseline.Load(path); err != nil {
+				return analysisConfig{}, err
+			}
+		}
+	}
+	if flags.Lookup("locked") != nil {
+		locked, _ := flags.GetBool("locked")
+		cfg.LockWarn, _ = flags.GetBool("locked-warn")
+		if locked || cfg.LockWarn {
+			if cfg.Locked, err = lock.Load(lock.DefaultPath); err != nil {
+				return analysisConfig{}, err
+			}
+		}
+	}
+	cfg.NoCache, _ = flags.GetBool("no-cache")
+	cfg.CacheTTL, _ = flags.GetDuration("cache-ttl")
+	cfg.CheckTimeout, _ = flags.GetDuration("check-timeout")
+	if flags.Changed("no-slither") {
+		cfg.NoSlither, _ = flags.GetBool("no-slither")
+	}
+	if flags.Changed("mythril") {
+		cfg.Mythril, _ = flags.GetBool("mythril")
+	}
+	if flags.Changed("timeout") {
+		cfg.SlitherTimeout, _ = flags.GetDuration("timeout")
+	}
+	return cfg, nil
+}
+
+// withChecks adds checks (opt-in ones included) to the selected custom
+// checks, keeping the default selection when none were named.
+func withChecks(selected []string, extra ...string) []string {
+	if len(selected) == 0 {
+		for _, c := range checks.Select(nil) {
+			selected = append(selected, c.Name)
+		}
+	}
+	selected = slices.Clone(selected)
+	for _, name := range extra {
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// analyzeTarget runs the engines and custom checks selected by cfg and returns
+// the merged report. If ctx is cancelled midway, whatever finished is still
+// returned as a report marked Interrupted.
+func analyzeTarget(ctx context.Context, cfg analysisConfig) (*parser.AnalysisReport, error) {
+	target := cfg.Target
+
 	// Validate target
 	if err := runner.ValidateTarget(target); err != nil {
-		return err
+		return nil, err
+	}
+	if len(cfg.Checks) > 0 && len(checks.Select(cfg.Checks)) == 0 {
+		return nil, fmt.Errorf("no custom checks match %v (available: %s)",
+			cfg.Checks, strings.Join(checks.Names(), ", "))
 	}
 
-	if !ciMode {
-		fmt.Printf("🔍 Analyzing: %s\n", target)
+	if err := validateTags(append(append([]string{}, cfg.IncludeTags...), cfg.ExcludeTags...)); err != nil {
+		return nil, err
+	}
+	if err := analyzer.ValidateScope(target, cfg.Scope); err != nil {
+		return nil, err
+	}
+	if err := analyzer.ValidatePipeline(cfg.Pipeline); err != nil {
+		return nil, err
+	}
+	overrides, err := analyzer.ParseSeverityOverrides(cfg.SeverityOverrides)
+	if err != nil {
+		return nil, err
+	}
+	if err := analyzer.ValidatePathSeverity(cfg.PathSeverity); err != nil {
+		return nil, err
+	}
+	minConfidence, err := analyzer.ParseConfidence(cfg.MinConfidence)
+	if err != nil {
+		return nil, fmt.Errorf("--min-confidence: %w", err)
+	}
+	minSeverity, err := analyzer.ParseMinSeverity(cfg.MinSeverity)
+	if err != nil {
+		return nil, fmt.Errorf("--min-severity: %w", err)
+	}
+	if err := offlinePreflight(cfg); err != nil {
+		return nil, err
 	}
 
-	var slitherFindings []parser.Finding
+	cfg.logf("🔍 Analyzing: %s\n", target)
 
-	if !noSlither {
-		// Step 1: Detect environment
-		if !ciMode {
-			fmt.Println("   Checking environment...")
+	var (
+		engineFindings []parser.Finding
+		engines        []string
+		environment    *parser.Environment
+		analyzeOpts    = analyzer.Options{
+			Scope:             cfg.Scope,
+			IncludeTags:       cfg.IncludeTags,
+			ExcludeTags:       cfg.ExcludeTags,
+			MinConfidence:     minConfidence,
+			MinSeverity:       minSeverity,
+			Limits:            &cfg.Limits,
+			Pipeline:          pipeline(cfg.Pipeline, cfg.NoCluster),
+			CheckTimeout:      cfg.CheckTimeout,
+			Archetype:         cfg.Archetype,
+			ExcludePaths:      cfg.ExcludePaths,
+			SeverityOverrides: overrides,
+			PathSeverities:    cfg.PathSeverity,
+			Triage:            cfg.Triage,
+			Baseline:          cfg.Baseline,
 		}
+	)
+	if cfg.Quiet {
+		analyzeOpts.Log = io.Discard
+	}
+
+	if !cfg.NoSlither {
+		// Step 1: Detect environment
+		cfg.logf("   Checking environment...\n")
 		env, err := runner.DetectEnvironment()
 		if err != nil {
-			return fmt.Errorf("environment check failed:\n%w", err)
+			return nil, fmt.Errorf("environment check failed:\n%w", err)
 		}
-		if !ciMode {
-			fmt.Printf("   ✅ %s | Slither %s\n", env.PythonVersion, env.SlitherVersion)
+		cfg.logf("   ✅ %s | Slither %s\n", env.PythonVersion, env.SlitherVersion)
+		environment = analysisEnvironment(cfg, env)
+		if err := checkLock(cfg, environment); err != nil {
+			return nil, err
 		}
 
-		// Step 2: Run Slither
-		if !ciMode {
-			fmt.Println("   Running Slither analysis...")
+		// Catch detector-name typos before they are silently ignored by Slither
+		if len(cfg.Exclude) > 0 || len(cfg.Only) > 0 {
+			if known, err := runner.ListDetectors(); err == nil {
+				if err := runner.ValidateDetectors(append(append([]string{}, cfg.Exclude...), cfg.Only...), known); err != nil {
+					return nil, err
+				}
+			}
 		}
-		tmpJSON := filepath.Join(os.TempDir(), "solsec-slither-output.json")
-		result, err := runner.Run(env, runner.Options{
-			Target:           target,
-			OutputPath:       tmpJSON,
-			ExcludeDetectors: exclude,
-			SolcVersion:      solcVersion,
-		})
-		if err != nil {
-			return fmt.Errorf("slither execution failed: %w", err)
+
+		// Step 2: Run Slither and parse its output
+		cfg.logf("   Running Slither analysis...\n")
+		slitherFindings, duration, cached, err := runSlitherCached(ctx, cfg, env, target)
+		var skipped map[string]string
+		if err != nil && ctx.Err() != nil {
+			// Slither was killed; leave it out of the scope manifest.
+			cfg.logf("   ⚠️  Interrupted; writing a partial report...\n")
+			report, err := analyzer.Analyze(ctx, target, nil, analyzeOpts)
+			if err != nil {
+				return nil, err
+			}
+			report.Environment = environment
+			return report, nil
+		} else if err != nil {
+			compileErrs := runner.ParseCompileErrors(err.Error())
+			if len(compileErrs) == 0 {
+				return nil, err
+			}
+			if !cfg.PartialCompile {
+				return nil, fmt.Errorf("compilation failed:\n%s\n\nRe-run with --partial-compile to analyze the files that do compile",
+					formatCompileErrors(compileErrs))
+			}
+			cfg.logf("   ⚠️  Compilation failed in %d place(s); analyzing the files that compile...\n", len(compileErrs))
+			slitherFindings, skipped = partialSlither(ctx, cfg, env, compileErrs)
+		} else if cached {
+			cfg.logf("   ✅ Slither results loaded from cache (inputs unchanged; --no-cache to re-run)\n")
+		} else {
+			cfg.logf("   ✅ Slither completed in %s\n", duration.Round(time.Millisecond))
 		}
-		if !ciMode {
-			fmt.Printf("   ✅ Slither completed in %s\n", result.Duration.Round(1000000))
+		engineFindings = append(engineFindings, slitherFindings...)
+		engines = append(engines, "slither")
+		analyzeOpts.Skipped = skipped
+	}
+
+	if cfg.Mythril && ctx.Err() == nil {
+		if mythrilFindings, ran := runMythril(ctx, cfg); ran {
+			engineFindings = append(engineFindings, mythrilFindings...)
+			engines = append(engines, "mythril")
 		}
-		defer os.Remove(tmpJSON)
+	}
 
-		// Step 3: Parse Slither output
-		slitherFindings, err = parser.Parse(tmpJSON)
-		if err != nil {
-			return fmt.Errorf("parsing slither output: %w", err)
+	if environment == nil {
+		environment = analysisEnvironment(cfg, nil)
+		if err := checkLock(cfg, environment); err != nil {
+			return nil, err
 		}
 	}
 
 	// Step 4: Run custom checks + merge
-	if !ciMode {
-		fmt.Println("   Running custom security checks...")
+	cfg.logf("   Running custom security checks...\n")
+	analyzeOpts.Checks = cfg.Checks
+	analyzeOpts.Engines = engines
+	if slices.Contains(engines, "slither") {
+		analyzeOpts.EngineChecks = environment.Detectors
+	}
+	report, err := analyzer.Analyze(ctx, target, engineFindings, analyzeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+	report.Interrupted = report.Interrupted || ctx.Err() != nil
+	report.Environment = environment
+	if report.Triaged > 0 {
+		cfg.logf("   %d triaged finding(s) hidden\n", report.Triaged)
+	}
+	if report.Baselined > 0 {
+		cfg.logf("   %d baselined finding(s) hidden\n", report.Baselined)
+	}
+	for _, f := range report.AnalyzedFiles {
+		if f.Excluded != "" {
+			cfg.logf("   ⚠️  Custom checks skipped %s: %s\n", f.Path, f.Excluded)
+		}
+	}
+	return report, nil
+}
+
+// pipeline returns the post-processing stages to run: names, or the default
+// pipeline when unset, without clustering when noCluster is set.
+func pipeline(names []string, noCluster bool) []string {
+	if names == nil {
+		names = analyzer.DefaultPipeline
+	}
+	if noCluster {
+		names = slices.DeleteFunc(slices.Clone(names), func(name string) bool { return name == "cluster" })
+	}
+	return names
+}
+
+// validateTags rejects tag names the rules bundle does not use, which would
+// otherwise filter out every finding or none.
+func validateTags(tags []string) error {
+	known := rules.Default().AllTags()
+	for _, t := range tags {
+		if !slices.Contains(known, t) {
+			return fmt.Errorf("unknown tag %q (available: %s)", t, strings.Join(known, ", "))
+		}
 	}
-	report, err := analyzer.Analyze(target, slitherFindings)
+	return nil
+}
+
+// runMythril runs Mythril over each file in the target. Mythril is optional:
+// when it is missing or fails on a file, analysis continues without it.
+// ran reports whether Mythril was available at all.
+func runMythril(ctx context.Context, cfg analysisConfig) (findings []parser.Finding, ran bool) {
+	mythPath, err := runner.DetectMythril()
+	if err != nil {
+		cfg.logf("   ⚠️  Skipping Mythril: %v\n", err)
+		return nil, false
+	}
+	files, err := source.SolidityFiles(cfg.Target)
 	if err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
+		cfg.logf("   ⚠️  Skipping Mythril: %v\n", err)
+		return nil, false
 	}
+	files = source.NewExcluder(cfg.ExcludePaths).Filter(files)
 
-	// Step 5: Score
-	score := scorer.Score(report)
-	grade := scorer.Grade(score)
-	verdict := scorer.Verdict(score)
+	cfg.logf("   Running Mythril on %d file(s)...\n", len(files))
+	for _, file := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		out, err := runner.RunMythril(ctx, mythPath, runner.MythrilOptions{
+			File:        file,
+			Timeout:     cfg.MythrilTimeout,
+			SolcVersion: cfg.SolcVersion,
+		})
+		if err == nil {
+			var parsed []parser.Finding
+			if parsed, err = parser.ParseMythril(out); err == nil {
+				findings = append(findings, parsed...)
+				continue
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		cfg.logf("   ⚠️  Mythril failed on %s: %v\n", file, err)
+	}
+	return findings, true
+}
 
-	// Step 6: Write report
+// interruptedError reports that a partial report was written. It wraps
+// context.Canceled so Execute exits with the conventional status 130.
+func interruptedError(outputPath string) error {
+	return fmt.Errorf("analysis interrupted; partial report written to %s: %w", displayPath(outputPath), context.Canceled)
+}
+
+// reportOptions tune how reports are rendered.
+type reportOptions struct {
+	// Verbose adds finding evidence to formats that hide it by default
+	// (JSON always includes it).
+	Verbose bool
+	// PageSize paginates the HTML findings table; 0 disables paging.
+	PageSize int
+	// Deterministic drops the generation time (or takes it from
+	// SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports.
+	Deterministic bool
+	// RepoRoot is the repository root SARIF, GitLab and Code Climate paths
+	// are relative to; empty detects it from the report's target.
+	RepoRoot string
+	// Template replaces or brands the HTML and PDF report template.
+	Template string
+}
+
+func reportOptionsFromFlags(cmd *cobra.Command) reportOptions {
+	var opts reportOptions
+	opts.Verbose, _ = cmd.Flags().GetBool("verbose")
+	opts.PageSize, _ = cmd.Flags().GetInt("page-size")
+	opts.Deterministic, _ = cmd.Flags().GetBool("deterministic")
+	opts.RepoRoot, _ = cmd.Flags().GetString("repo-root")
+	opts.Template, _ = cmd.Flags().GetString("template")
+	return opts
+}
+
+// sourceDateEpoch returns the report timestamp for deterministic output:
+// SOURCE_DATE_EPOCH (https://reproducible-builds.org/specs/source-date-epoch/)
+// as RFC 3339, or "" when it is unset.
+func sourceDateEpoch() (string, error) {
+	v := os.Getenv("SOURCE_DATE_EPOCH")
+	if v == "" {
+		return "", nil
+	}
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("SOURCE_DATE_EPOCH=%q is not a Unix timestamp", v)
+	}
+	return time.Unix(sec, 0).UTC().Format(time.RFC3339), nil
+}
+
+// writeReport renders the report in the given format.
+func writeReport(report *parser.AnalysisReport, score int, format, outputPath string, opts reportOptions) error {
 	var rep reporter.Reporter
 	switch strings.ToLower(format) {
 	case "json":
 		rep = &reporter.JSONReporter{}
+	case "ndjson":
+		rep = &reporter.NDJSONReporter{}
 	case "sarif":
-		rep = &reporter.SARIFReporter{}
+		root, err := repoRoot(report, opts)
+		if err != nil {
+			return err
+		}
+		rep = &reporter.SARIFReporter{Verbose: opts.Verbose, RepoRoot: root}
+	case "pdf":
+		rep = &reporter.PDFReporter{Verbose: opts.Verbose, Template: opts.Template}
+	case "csv":
+		rep = &reporter.CSVReporter{}
+	case "junit", "xml":
+		rep = &reporter.JUnitReporter{}
+	case "gitlab":
+		root, err := repoRoot(report, opts)
+		if err != nil {
+			return err
+		}
+		rep = &reporter.GitLabReporter{RepoRoot: root}
+	case "codeclimate":
+		root, err := repoRoot(report, opts)
+		if err != nil {
+			return err
+		}
+		rep = &reporter.CodeClimateReporter{RepoRoot: root}
 	default:
-		rep = &reporter.HTMLReporter{}
+		rep = &reporter.HTMLReporter{Verbose: opts.Verbose, PageSize: opts.PageSize, Template: opts.Template}
 	}
 
-	if err := rep.Write(report, score, outputPath); err != nil {
-		return fmt.Errorf("writing report: %w", err)
+	if opts.Deterministic {
+		generated, err := sourceDateEpoch()
+		if err != nil {
+			return err
+		}
+		report.GeneratedAt = generated
 	}
 
-	// Step 7: Print summary
-	if !ciMode {
-		fmt.Printf("\n%s\n", strings.Repeat("─", 60))
-		fmt.Printf("  Grade: %s   Score: %d/100\n", grade, score)
-		fmt.Printf("  %s\n", verdict)
-		fmt.Printf("  Findings: %d total (%d critical, %d high, %d medium, %d low)\n",
-			report.Summary.Total,
-			report.Summary.Critical,
-			report.Summary.High,
-			report.Summary.Medium,
-			report.Summary.Low,
-		)
-		fmt.Printf("  Report: %s\n", outputPath)
-		fmt.Printf("%s\n\n", strings.Repeat("─", 60))
+	var err error
+	if outputPath == stdoutPath {
+		err = streamReport(rep, report, score)
+	} else {
+		err = rep.Write(report, score, outputPath)
 	}
+	if err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
 
-	// Step 8: Exit code for CI
-	if failOn != "none" {
-		failSeverity := parser.Severity(capitalize(failOn))
-		for _, f := range report.Findings {
-			if parser.SeverityRank(f.Severity) <= parser.SeverityRank(failSeverity) {
-				if ciMode {
-					fmt.Printf("FAIL: %d finding(s) at %s severity or above\n",
-						countAtOrAbove(report.Findings, failSeverity), failOn)
-				}
-				os.Exit(1)
-			}
-		}
+// repoRoot is the root that report file paths are made relative to:
+// --repo-root, else the git repository enclosing the target.
+func repoRoot(report *parser.AnalysisReport, opts reportOptions) (string, error) {
+	if opts.RepoRoot != "" {
+		return filepath.Abs(opts.RepoRoot)
 	}
+	return source.RepoRoot(report.Target), nil
+}
 
-	return nil
+func printSummary(report *parser.AnalysisReport, score int, grade, verdict, outputPath string) {
+	fmt.Printf("\n%s\n", strings.Repeat("─", 60))
+	fmt.Printf("  Grade: %s   Score: %d/100\n", grade, score)
+	fmt.Printf("  %s\n", verdict)
+	fmt.Printf("  Findings: %d total (%d critical, %d high, %d medium, %d low)\n",
+		report.Summary.Total,
+		report.Summary.Critical,
+		report.Summary.High,
+		report.Summary.Medium,
+		report.Summary.Low,
+	)
+	if n := len(report.Warnings); n > 0 {
+		fmt.Printf("  Warnings: %d (listed in the report)\n", n)
+	}
+	fmt.Printf("  Report: %s\n", displayPath(outputPath))
+	fmt.Printf("%s\n\n", strings.Repeat("─", 60))
 }
 
 func capitalize(s string) string {
@@ -177,12 +1044,43 @@ func capitalize(s string) string {
 	return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
 }
 
-func countAtOrAbove(findings []parser.Finding, threshold parser.Severity) int {
+func countSeverity(findings []parser.Finding, severity parser.Severity) int {
 	count := 0
 	for _, f := range findings {
-		if parser.SeverityRank(f.Severity) <= parser.SeverityRank(threshold) {
+		if f.Severity == severity {
 			count++
 		}
 	}
 	return count
-}
\ No newline at end of file
+}
+
+// pathSeverity converts the path_severity rules of .solsec.yaml.
+func pathSeverity(rules []config.PathSeverity) []analyzer.PathSeverity {
+	var out []analyzer.PathSeverity
+	for _, r := range rules {
+		out = append(out, analyzer.PathSeverity(r))
+	}
+	return out
+}
+
+// resolveLimits builds the custom-check file guards from --max-file-size and
+// .solsec.yaml, falling back to source.DefaultLimits.
+func resolveLimits(cmd *cobra.Command, fileCfg *config.Config) (source.Limits, error) {
+	flags := cmd.Flags()
+	limits := source.DefaultLimits
+	size := fileCfg.MaxFileSize
+	if flags.Lookup("max-file-size") != nil && flags.Changed("max-file-size") {
+		size, _ = flags.GetString("max-file-size")
+	}
+	if size != "" {
+		n, err := source.ParseSize(size)
+		if err != nil {
+			return source.Limits{}, fmt.Errorf("max file size: %w", err)
+		}
+		limits.MaxFileSize = n
+	}
+	if fileCfg.MaxLineLength != nil {
+		limits.MaxLineLength = *fileCfg.MaxLineLength
+	}
+	return limits, nil
+}
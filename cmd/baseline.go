@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Zubimendi/solsec/internal/baseline"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Accept the known findings of an existing codebase",
+}
+
+var baselineCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Record the findings of a JSON report in a baseline file",
+	Long: `Record every finding of a JSON report in a baseline file, by fingerprint.
+Analyses run with --baseline leave the recorded findings out of the report,
+so only new findings are reported and fail CI. This lets solsec gate a
+codebase with legacy findings from day one; fix them over time and
+re-create the baseline to shrink it.
+
+Fingerprints survive line shifts and unrelated edits, but not changes to the
+flagged line itself, so a baselined finding whose code changes is reported
+again for review. Commit the baseline file next to .solsec.yaml.
+
+Examples:
+  solsec analyze . -f json -o report.json
+  solsec baseline create --report report.json
+  solsec analyze . --baseline solsec-baseline.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportPath, _ := cmd.Flags().GetString("report")
+		outputPath, _ := cmd.Flags().GetString("output")
+
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		b, err := baseline.New(report)
+		if err != nil {
+			return err
+		}
+		if err := b.Write(outputPath); err != nil {
+			return err
+		}
+		fmt.Printf("📝 Baseline of %d finding(s) written to %s\n", len(b.Findings), outputPath)
+		if report.Baselined > 0 {
+			fmt.Printf("   ⚠️  The report was itself filtered by a baseline; its %d baselined finding(s) are not recorded\n", report.Baselined)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.AddCommand(baselineCreateCmd)
+
+	f := baselineCreateCmd.Flags()
+	f.String("report", "solsec-report.json", "JSON report whose findings to accept")
+	f.StringP("output", "o", baseline.DefaultPath, "Baseline file to write")
+}
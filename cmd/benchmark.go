@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/benchmark"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// bundledCorpus holds the benchmark corpus embedded in the binary.
+var bundledCorpus fs.FS
+
+// SetBenchmarkCorpus installs the bundled corpus used by `solsec benchmark`.
+func SetBenchmarkCorpus(fsys fs.FS) { bundledCorpus = fsys }
+
+var benchmarkCmd = &cobra.Command{
+	Use:   "benchmark",
+	Short: "Measure detection rates against a corpus of known-vulnerable contracts",
+	Long: `Analyze every contract in a corpus of known-vulnerable contracts and report
+the detection rate per vulnerability category, to quantify what solsec does
+and doesn't catch with a given profile.
+
+Vulnerable lines are annotated SmartBugs-style ("// <yes> <report> REENTRANCY"
+on the line before). A small corpus is bundled; for a larger one, clone
+SmartBugs-curated and point --corpus at its dataset:
+
+  git clone https://github.com/smartbugs/smartbugs-curated
+  solsec benchmark --corpus smartbugs-curated/dataset`,
+	Args: cobra.NoArgs,
+	RunE: runBenchmark,
+}
+
+func init() {
+	rootCmd.AddCommand(benchmarkCmd)
+	f := benchmarkCmd.Flags()
+	f.String("corpus", "", "Corpus directory (default: the bundled corpus)")
+	f.String("profile", "standard", "Analysis profile: "+strings.Join(profileNames(), ", "))
+	f.Bool("no-slither", false, "Skip Slither and benchmark the custom checks only")
+	f.StringSlice("checks", nil, "Only run these custom checks")
+	f.Duration("timeout", 0, "Slither timeout per contract (overrides the profile)")
+	f.StringP("format", "f", "text", "Output format: text, json")
+	f.StringP("output", "o", "", "Write results to a file instead of stdout")
+	f.BoolP("verbose", "v", false, "List every missed vulnerability")
+	_ = benchmarkCmd.MarkFlagDirname("corpus")
+	_ = benchmarkCmd.RegisterFlagCompletionFunc("checks", completeChecks)
+	_ = benchmarkCmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return profileNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runBenchmark(cmd *cobra.Command, args []string) error {
+	corpusDir, _ := cmd.Flags().GetString("corpus")
+	profileName, _ := cmd.Flags().GetString("profile")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unsupported format %q (use text or json)", format)
+	}
+
+	corpusName := corpusDir
+	if corpusDir == "" {
+		corpusName = "bundled"
+		if bundledCorpus == nil {
+			return fmt.Errorf("no bundled corpus in this build; use --corpus")
+		}
+		dir, err := os.MkdirTemp("", "solsec-benchmark-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		if err := os.CopyFS(dir, bundledCorpus); err != nil {
+			return fmt.Errorf("extracting bundled corpus: %w", err)
+		}
+		corpusDir = dir
+	}
+
+	corpus, err := benchmark.LoadCorpus(corpusDir)
+	if err != nil {
+		return fmt.Errorf("loading corpus: %w", err)
+	}
+	if len(corpus.Vulns) == 0 {
+		return fmt.Errorf("no annotated vulnerabilities found in %s", corpusDir)
+	}
+
+	// Fail once up front rather than once per contract.
+	if cfg, err := analysisConfigFromFlags(cmd, corpusDir, profileName); err != nil {
+		return err
+	} else if !cfg.NoSlither {
+		if _, err := runner.DetectEnvironment(); err != nil {
+			return fmt.Errorf("environment check failed (use --no-slither to benchmark custom checks only):\n%w", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "📏 Benchmarking %d contract(s), %d annotated vulnerabilities (profile: %s)...\n",
+		len(corpus.Files), len(corpus.Vulns), profileName)
+
+	findings := map[string][]parser.Finding{}
+	errs := map[string]string{}
+	for i, file := range corpus.Files {
+		fmt.Fprintf(os.Stderr, "   [%d/%d] %s\n", i+1, len(corpus.Files), corpus.RelPath(file))
+		cfg, err := analysisConfigFromFlags(cmd, file, profileName)
+		if err != nil {
+			return err
+		}
+		cfg.Quiet = true
+		cfg.NoCluster = true // every occurrence counts towards detection
+		report, err := analyzeTarget(cmd.Context(), cfg)
+		if err := cmd.Context().Err(); err != nil {
+			return fmt.Errorf("benchmark interrupted: %w", err)
+		}
+		if err != nil {
+			errs[corpus.RelPath(file)] = err.Error()
+			continue
+		}
+		findings[file] = report.Findings
+	}
+
+	res := benchmark.Score(corpus, findings)
+	res.Corpus = corpusName
+	for i := range res.Missed {
+		res.Missed[i].File = corpus.RelPath(res.Missed[i].File)
+	}
+	if len(errs) > 0 {
+		res.Errors = errs
+	}
+
+	out := io.Writer(os.Stdout)
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	printBenchmark(out, res, verbose)
+	return nil
+}
+
+func printBenchmark(w io.Writer, res benchmark.Result, verbose bool) {
+	fmt.Fprintf(w, "\n%-24s %6s %9s %7s\n", "Category", "Vulns", "Detected", "Rate")
+	fmt.Fprintln(w, strings.Repeat("─", 49))
+	for _, s := range res.Categories {
+		fmt.Fprintf(w, "%-24s %6d %9d %6.0f%%\n", s.Category, s.Total, s.Detected, s.Rate()*100)
+	}
+	fmt.Fprintln(w, strings.Repeat("─", 49))
+	fmt.Fprintf(w, "%-24s %6d %9d %6.0f%%\n", "TOTAL", res.Overall.Total, res.Overall.Detected, res.Overall.Rate()*100)
+
+	if len(res.Errors) > 0 {
+		fmt.Fprintf(w, "\n⚠️  %d contract(s) could not be analyzed (their vulnerabilities count as missed):\n", len(res.Errors))
+		files := make([]string, 0, len(res.Errors))
+		for file := range res.Errors {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			first, _, _ := strings.Cut(res.Errors[file], "\n")
+			fmt.Fprintf(w, "   %s: %s\n", file, first)
+		}
+	}
+	if verbose && len(res.Missed) > 0 {
+		fmt.Fprintln(w, "\nMissed:")
+		for _, v := range res.Missed {
+			fmt.Fprintf(w, "   %-20s %s:%d\n", v.Category, v.File, v.Line)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/cache"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/runner"
+)
+
+// runSlitherCached serves Slither results from the cache when nothing that
+// affects them changed, and caches fresh successful runs. hit reports a
+// cache hit.
+func runSlitherCached(ctx context.Context, cfg analysisConfig, env *runner.Environment, target string) (findings []parser.Finding, duration time.Duration, hit bool, err error) {
+	if cfg.NoCache {
+		findings, duration, err = runSlither(ctx, cfg, env, target)
+		return findings, duration, false, err
+	}
+
+	store := cache.Store{Dir: cache.DefaultDir(), TTL: cfg.CacheTTL}
+	key, keyErr := slitherCacheKey(cfg, env, target)
+	if keyErr == nil {
+		if cached, ok := store.Get(key); ok {
+			return cached, 0, true, nil
+		}
+	}
+
+	findings, duration, err = runSlither(ctx, cfg, env, target)
+	if err == nil && keyErr == nil {
+		_ = store.Put(key, findings) // best effort
+	}
+	return findings, duration, false, err
+}
+
+func slitherCacheKey(cfg analysisConfig, env *runner.Environment, target string) (cache.Key, error) {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return cache.Key{}, err
+	}
+	inputs, err := cache.InputsHash(target)
+	if err != nil {
+		return cache.Key{}, err
+	}
+	return cache.Key{
+		Engine:        "slither",
+		EngineVersion: env.SlitherVersion,
+		Detectors:     sortedCopy(cfg.Only),
+		Exclude:       sortedCopy(cfg.Exclude),
+		SolcVersion:   cfg.SolcVersion,
+		Framework:     cfg.Framework,
+		FilterPaths:   slitherFilters(cfg, target),
+		Target:        abs,
+		Inputs:        inputs,
+	}, nil
+}
+
+func sortedCopy(s []string) []string {
+	out := append([]string(nil), s...)
+	sort.Strings(out)
+	return out
+}
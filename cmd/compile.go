@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// runSlither runs Slither on target and parses its findings. Compilation
+// failures surface as errors whose text includes crytic-compile's output.
+func runSlither(ctx context.Context, cfg analysisConfig, env *runner.Environment, target string) ([]parser.Finding, time.Duration, error) {
+	tmpDir, err := os.MkdirTemp("", "solsec-slither-*")
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	result, err := runner.Run(ctx, env, runner.Options{
+		Target:           target,
+		OutputPath:       filepath.Join(tmpDir, "slither-output.json"),
+		Timeout:          cfg.SlitherTimeout,
+		ExcludeDetectors: cfg.Exclude,
+		Detectors:        cfg.Only,
+		SolcVersion:      cfg.SolcVersion,
+		Framework:        cfg.Framework,
+		FilterPaths:      slitherFilters(cfg, target),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("slither execution failed: %w", err)
+	}
+
+	findings, err := parser.Parse(result.JSONOutputPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parsing slither output: %w\n%s", err, result.Stderr)
+	}
+	return findings, result.Duration, nil
+}
+
+// slitherFilters are the paths whose Slither findings are dropped: those
+// .solsecignore and --exclude-paths exclude, which custom checks skip too.
+func slitherFilters(cfg analysisConfig, target string) []string {
+	return append(source.SlitherFilters(target), source.NewExcluder(cfg.ExcludePaths).SlitherFilters()...)
+}
+
+// partialSlither re-runs Slither file by file, skipping files with compile
+// errors (and files that fail because they import one). Every compile error
+// becomes an Informational finding; skipped maps each file left out to why.
+func partialSlither(ctx context.Context, cfg analysisConfig, env *runner.Environment, compileErrs []runner.CompileError) (findings []parser.Finding, skipped map[string]string) {
+	files, err := source.SolidityFiles(cfg.Target)
+	if err != nil {
+		return nil, nil
+	}
+	files = source.NewExcluder(cfg.ExcludePaths).Filter(files)
+
+	skipped = map[string]string{}
+	var allErrs []runner.CompileError
+	markBroken := func(errs []runner.CompileError) {
+		for _, ce := range errs {
+			if path := matchSourceFile(ce.File, files); path != "" {
+				if _, ok := skipped[path]; !ok {
+					skipped[path] = fmt.Sprintf("compilation failed: %s: %s", ce.Kind, ce.Message)
+				}
+			}
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	markBroken(compileErrs)
+
+	for _, file := range files {
+		if _, broken := skipped[file]; broken {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		fileFindings, _, err := runSlither(ctx, cfg, env, file)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			errs := runner.ParseCompileErrors(err.Error())
+			markBroken(errs)
+			if _, ok := skipped[file]; !ok {
+				// Fails without an error of its own, e.g. through an import.
+				skipped[file] = "compilation failed (see compilation-error findings)"
+			}
+			continue
+		}
+		findings = append(findings, fileFindings...)
+	}
+
+	for i, ce := range allErrs {
+		file := matchSourceFile(ce.File, files)
+		if file == "" {
+			file = ce.File
+		}
+		findings = append(findings, parser.Finding{
+			ID:          fmt.Sprintf("COMPILE-%03d", i+1),
+			Source:      "solc",
+			Check:       "compilation-error",
+			Title:       fmt.Sprintf("Compilation %s in %s", ce.Kind, filepath.Base(file)),
+			Description: fmt.Sprintf("%s:%d:%d — %s: %s", file, ce.Line, ce.Column, ce.Kind, ce.Message),
+			Severity:    parser.SeverityInformational,
+			Confidence:  "High",
+			File:        file,
+			Lines:       []int{ce.Line},
+			Remediation: "Fix the compiler error so Slither can analyze this file. Until then it is covered by custom checks only.",
+		})
+	}
+	return findings, skipped
+}
+
+// matchSourceFile maps a path as printed by solc (often relative to the
+// project root) onto one of the discovered source files.
+func matchSourceFile(reported string, files []string) string {
+	reported = filepath.ToSlash(filepath.Clean(reported))
+	for _, f := range files {
+		slash := filepath.ToSlash(f)
+		if slash == reported || strings.HasSuffix(slash, "/"+reported) || strings.HasSuffix(reported, "/"+slash) {
+			return f
+		}
+		if abs, err := filepath.Abs(f); err == nil && filepath.ToSlash(abs) == reported {
+			return f
+		}
+	}
+	return ""
+}
+
+func formatCompileErrors(errs []runner.CompileError) string {
+	lines := make([]string, 0, len(errs))
+	for _, e := range errs {
+		lines = append(lines, fmt.Sprintf("  %s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Kind, e.Message))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script for solsec.
+
+  Bash:        source <(solsec completion bash)
+  Zsh:         solsec completion zsh > "${fpath[1]}/_solsec"
+  Fish:        solsec completion fish > ~/.config/fish/completions/solsec.fish
+  PowerShell:  solsec completion powershell | Out-String | Invoke-Expression
+
+Completions include output formats, severities, Slither detector names for
+--exclude, and custom check names for --checks.`,
+	Args:                  cobra.ExactValidArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(out)
+		}
+		return fmt.Errorf("unsupported shell: %s", args[0])
+	},
+}
+
+var manCmd = &cobra.Command{
+	Use:   "man",
+	Short: "Generate man pages for solsec and all subcommands",
+	Long: `Generate troff man pages (section 1) for solsec and every subcommand.
+
+  solsec man --dir ./man
+  man ./man/solsec-analyze.1`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("creating man page directory: %w", err)
+		}
+		header := &doc.GenManHeader{
+			Title:   strings.ToUpper(appName),
+			Section: "1",
+			Source:  fmt.Sprintf("%s %s", appName, appVersion),
+		}
+		if err := doc.GenManTree(rootCmd, header, dir); err != nil {
+			return fmt.Errorf("generating man pages: %w", err)
+		}
+		fmt.Printf("✅ Man pages written to %s\n", dir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(manCmd)
+	manCmd.Flags().String("dir", "man", "Directory to write man pages into")
+	_ = manCmd.MarkFlagDirname("dir")
+}
+
+// completeFormats completes --format values.
+func completeFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		"html\tStandalone HTML report",
+		"json\tMachine-readable JSON",
+		"ndjson\tNewline-delimited JSON, one finding per line",
+		"sarif\tSARIF 2.1.0 for code scanning",
+		"pdf\tPrintable PDF of the HTML report (needs a headless browser)",
+		"csv\tOne row per finding, for spreadsheets and ticket trackers",
+		"junit\tJUnit XML, one failed test case per finding, for CI test dashboards",
+		"gitlab\tGitLab SAST report for merge request security widgets",
+		"codeclimate\tCode Climate issues, for reviewdog and GitLab Code Quality",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSeverities completes --fail-on values.
+func completeSeverities(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{"critical", "high", "medium", "low", "none"}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfidences completes --min-confidence values.
+func completeConfidences(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var out []string
+	for _, c := range analyzer.Confidences {
+		out = append(out, strings.ToLower(c))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeMinSeverities completes --min-severity values.
+func completeMinSeverities(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{"critical", "high", "medium", "low", "informational"}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDetectors completes Slither detector names. The installed Slither is
+// queried (and cached); without it, names known to the rules database are used.
+func completeDetectors(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	if detectors, err := runner.ListDetectors(); err == nil {
+		for _, d := range detectors {
+			names = append(names, d.Check+"\t"+d.Impact+": "+d.Title)
+		}
+	} else {
+		for check := range rules.Default().Rules {
+			if !strings.HasPrefix(check, "custom-") {
+				names = append(names, check)
+			}
+		}
+	}
+	sort.Strings(names)
+	return completeList(names, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeChecks completes custom check names for --checks.
+func completeChecks(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeList(checks.Names(), toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeArchetypes completes --archetype values with their descriptions.
+func completeArchetypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var out []string
+	for _, name := range analyzer.ArchetypeNames() {
+		a, _ := analyzer.LookupArchetype(name)
+		out = append(out, name+"\t"+a.Description)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTags completes finding tags for --include-tags and --exclude-tags.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeList(rules.Default().AllTags(), toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeList completes the last element of a comma-separated flag value,
+// keeping already-typed elements as the prefix and skipping duplicates.
+// Candidates may carry a tab-separated description.
+func completeList(candidates []string, toComplete string) []string {
+	prefix := ""
+	current := toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+		current = toComplete[i+1:]
+	}
+	used := map[string]bool{}
+	for _, p := range strings.Split(prefix, ",") {
+		used[p] = true
+	}
+
+	var out []string
+	for _, c := range candidates {
+		name, _, _ := strings.Cut(c, "\t")
+		if !used[name] && strings.HasPrefix(name, current) {
+			out = append(out, prefix+c)
+		}
+	}
+	return out
+}
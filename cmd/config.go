@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/config"
+	"github.com/Zubimendi/solsec/internal/policy"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/Zubimendi/solsec/internal/source"
+	"github.com/Zubimendi/solsec/internal/suggest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Check and inspect the .solsec.yaml configuration",
+	// The config commands inspect a config that may not load, so they skip
+	// the root command's check.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for unknown keys, bad values and conflicts",
+	Long: `Check the config file (./.solsec.yaml, $HOME/.solsec.yaml or --config) and
+the extends: chain it inherits. Reported errors stop analyze from running
+or change what it does:
+
+  - YAML that does not parse, or values of the wrong type
+  - unknown check names, profiles, pipeline stages, fail_on severities and
+    severity_overrides severities
+  - invalid grading bands, max_file_size or max_line_length
+  - negative max_critical, max_high or max_medium limits, including those
+    of workspace packages
+  - path_severity rules without paths or with an adjust outside -4..4
+  - policy rules, top-level or per package, whose expression does not parse
+    or type-check
+  - workspace packages without a name, or whose path does not exist
+  - offline: true with a remote extends:
+
+Warnings point at settings that are ignored or likely mistakes: unknown keys
+(with "did you mean" hints), deprecated frameworks, local overrides the base
+config rejects and workspace packages whose paths overlap.
+
+The command exits 1 when there are errors, or warnings with --strict.
+
+Examples:
+  solsec config validate
+  solsec config validate --config ci/solsec.yaml --strict -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		strict, _ := cmd.Flags().GetBool("strict")
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported format %q (use table or json)", format)
+		}
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		issues := lintConfig(cmd, path)
+
+		if format == "json" {
+			if issues == nil {
+				issues = []config.Issue{}
+			}
+			if err := writeJSON(os.Stdout, issues); err != nil {
+				return err
+			}
+		} else {
+			printConfigIssues(os.Stdout, path, issues)
+		}
+		errs, warnings := 0, 0
+		for _, i := range issues {
+			if i.Level == config.LevelError {
+				errs++
+			} else {
+				warnings++
+			}
+		}
+		if errs > 0 || (strict && warnings > 0) {
+			fmt.Fprintf(os.Stderr, "FAIL: %d error(s) and %d warning(s) in %s\n", errs, warnings, path)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the config file, or with --effective the configuration in force",
+	Long: `Print the settings of the config file. With --effective, print every
+setting as analyze applies it, after extends:, environment variables (the key
+in upper case, e.g. OFFLINE=true) and the --offline flag, with where each
+value comes from: file, extends, env, flag or default.
+
+Flags given to analyze (--checks, --exclude, --max-file-size) still override
+these settings for that run.
+
+Examples:
+  solsec config show
+  solsec config show --effective
+  solsec config show --effective -f json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		effective, _ := cmd.Flags().GetBool("effective")
+		if format != "yaml" && format != "json" {
+			return fmt.Errorf("unsupported format %q (use yaml or json)", format)
+		}
+
+		if !effective {
+			path, err := configPath()
+			if err != nil {
+				return err
+			}
+			settings, err := readConfigFile(path)
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				return writeJSON(os.Stdout, settings)
+			}
+			enc := yaml.NewEncoder(os.Stdout)
+			enc.SetIndent(2)
+			return enc.Encode(settings)
+		}
+
+		if configErr != nil {
+			return configErr
+		}
+		settings, err := effectiveConfig()
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			return writeJSON(os.Stdout, settings)
+		}
+		return printEffectiveConfig(os.Stdout, settings)
+	},
+}
+
+// configPath returns the config file in use.
+func configPath() (string, error) {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return "", fmt.Errorf("no config file found (looked for ./.solsec.yaml and $HOME/.solsec.yaml; use --config)")
+	}
+	return path, nil
+}
+
+// readConfigFile returns the settings the config file at path sets itself,
+// before extends: is applied.
+func readConfigFile(path string) (map[string]any, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return v.AllSettings(), nil
+}
+
+// lintConfig loads the config file at path the way solsec does at startup and
+// returns every problem found on the way.
+func lintConfig(cmd *cobra.Command, path string) []config.Issue {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return []config.Issue{{Level: config.LevelError, Message: err.Error()}}
+	}
+	settings := v.AllSettings()
+
+	var issues []config.Issue
+	warnings, err := config.Inherit(cmd.Context(), v)
+	if err != nil {
+		issues = append(issues, config.Issue{Key: "extends", Level: config.LevelError, Message: err.Error()})
+	}
+	for _, w := range warnings {
+		issues = append(issues, config.Issue{Key: "extends", Level: config.LevelWarning, Message: w})
+	}
+	cfg, err := config.Load(v)
+	if err != nil {
+		// Type errors leave nothing to check values against.
+		issues = append(issues, config.Issue{Level: config.LevelError, Message: err.Error()})
+		return append(issues, config.Lint(settings, &config.Config{})...)
+	}
+	issues = append(issues, config.Lint(settings, cfg)...)
+	return append(issues, lintValues(cfg)...)
+}
+
+// lintValues checks the settings that name solsec's checks, pipeline stages,
+// profiles and severities, the size limits, grading scale and policy rules.
+func lintValues(cfg *config.Config) []config.Issue {
+	var issues []config.Issue
+	errorf := func(key, format string, args ...any) {
+		issues = append(issues, config.Issue{Key: key, Level: config.LevelError, Message: fmt.Sprintf(format, args...)})
+	}
+
+	names := checks.Names()
+	for _, name := range cfg.Checks {
+		if len(checks.Select([]string{name})) == 0 {
+			msg := fmt.Sprintf("unknown custom check %q", name)
+			if s := suggest.Closest(name, names); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+			errorf("checks", "%s", msg)
+		}
+	}
+	if err := analyzer.ValidatePipeline(cfg.Pipeline); err != nil {
+		errorf("pipeline", "%v", err)
+	}
+	if _, err := analyzer.ParseSeverityOverrides(cfg.SeverityOverrides); err != nil {
+		errorf("severity_overrides", "%v", err)
+	}
+	for i, r := range pathSeverity(cfg.PathSeverity) {
+		if err := r.Validate(); err != nil {
+			errorf(fmt.Sprintf("path_severity[%d]", i), "%v", err)
+		}
+	}
+	if cfg.MaxFileSize != "" {
+		if _, err := source.ParseSize(cfg.MaxFileSize); err != nil {
+			errorf("max_file_size", "%v", err)
+		}
+	}
+	if _, err := gradingScale(cfg.Grading); err != nil {
+		errorf("grading", "%v", err)
+	}
+	limits := []struct {
+		key   string
+		limit *int
+	}{{"max_critical", cfg.MaxCritical}, {"max_high", cfg.MaxHigh}, {"max_medium", cfg.MaxMedium}}
+	for _, l := range limits {
+		if l.limit != nil && *l.limit < 0 {
+			errorf(l.key, "must not be negative, got %d", *l.limit)
+		}
+	}
+	for i, r := range cfg.Policy {
+		if _, err := policy.Compile(r.Name, r.Expr); err != nil {
+			errorf(fmt.Sprintf("policy[%d]", i), "%v", err)
+		}
+	}
+	severities, _ := completeSeverities(nil, nil, "")
+	for i, p := range cfg.Workspace.Packages {
+		key := fmt.Sprintf("workspace.packages[%d]", i)
+		if p.Profile != "" {
+			if _, err := lookupProfile(p.Profile); err != nil {
+				errorf(key+".profile", "%v", err)
+			}
+		}
+		if p.FailOn != "" && !slices.Contains(severities, strings.ToLower(p.FailOn)) {
+			errorf(key+".fail_on", "unknown severity %q (use %s)", p.FailOn, strings.Join(severities, ", "))
+		}
+		limits := []struct {
+			key   string
+			limit *int
+		}{{"max_critical", p.MaxCritical}, {"max_high", p.MaxHigh}, {"max_medium", p.MaxMedium}}
+		for _, l := range limits {
+			if l.limit != nil && *l.limit < 0 {
+				errorf(key+"."+l.key, "must not be negative, got %d", *l.limit)
+			}
+		}
+		for j, r := range p.Policy {
+			if _, err := policy.Compile(r.Name, r.Expr); err != nil {
+				errorf(fmt.Sprintf("%s.policy[%d]", key, j), "%v", err)
+			}
+		}
+	}
+	return issues
+}
+
+func printConfigIssues(w io.Writer, path string, issues []config.Issue) {
+	if len(issues) == 0 {
+		fmt.Fprintf(w, "✅ %s is valid\n", path)
+		return
+	}
+	width := 1
+	for _, i := range issues {
+		width = max(width, len(i.Key))
+	}
+	fmt.Fprintln(w, path)
+	for _, i := range issues {
+		key := i.Key
+		if key == "" {
+			key = "-"
+		}
+		fmt.Fprintf(w, "  %-8s %-*s  %s\n", i.Level, width, key, i.Message)
+	}
+}
+
+// configSetting is one top-level setting of the effective configuration.
+type configSetting struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Source string `json:"source"` // file, extends <ref>, env <NAME>, flag --<name> or default
+}
+
+// effectiveConfig returns the value analyze uses for every config key and
+// where it comes from.
+func effectiveConfig() ([]configSetting, error) {
+	local := map[string]any{}
+	if path := viper.ConfigFileUsed(); path != "" {
+		var err error
+		if local, err = readConfigFile(path); err != nil {
+			return nil, err
+		}
+	}
+	defaults := configDefaults()
+
+	var settings []configSetting
+	for _, key := range config.Keys() {
+		env := strings.ToUpper(key)
+		_, envSet := os.LookupEnv(env)
+		s := configSetting{Key: key}
+		switch {
+		case key == "offline" && offlineMode:
+			s.Value, s.Source = true, "flag --offline"
+		// Environment variables override file settings; offline is also
+		// read from the environment when the file leaves it unset.
+		case envSet && (viper.InConfig(key) || key == "offline"):
+			s.Value, s.Source = viper.Get(key), "env "+env
+		case viper.InConfig(key):
+			s.Value, s.Source = viper.Get(key), "file"
+			if lv, ok := local[key]; !ok || !reflect.DeepEqual(lv, s.Value) {
+				s.Source = "extends " + viper.GetString("extends")
+			}
+		default:
+			s.Value, s.Source = defaults[key], "default"
+		}
+		if s.Value == nil {
+			continue // unset, and nothing applies in its place
+		}
+		settings = append(settings, s)
+	}
+	return settings, nil
+}
+
+// configDefaults returns what applies for each config key left unset.
+func configDefaults() map[string]any {
+	var selected []string
+	for _, c := range checks.Select(nil) {
+		selected = append(selected, c.Name)
+	}
+	var bands []map[string]any
+	for _, b := range scorer.DefaultScale.Bands {
+		band := map[string]any{"grade": b.Grade, "verdict": b.Verdict}
+		if b.Below > 0 {
+			band["below"] = b.Below
+		}
+		bands = append(bands, band)
+	}
+	return map[string]any{
+		"exclude":            []string{},
+		"exclude_paths":      []string{},
+		"checks":             selected,
+		"max_file_size":      source.FormatSize(source.DefaultLimits.MaxFileSize),
+		"max_line_length":    source.DefaultLimits.MaxLineLength,
+		"pipeline":           analyzer.DefaultPipeline,
+		"severity_overrides": map[string]string{},
+		"offline":            false,
+		"grading":            map[string]any{"bands": bands, "emoji": true},
+	}
+}
+
+// printEffectiveConfig writes settings as YAML, each key preceded by a
+// comment naming its source.
+func printEffectiveConfig(w io.Writer, settings []configSetting) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range settings {
+		value := &yaml.Node{}
+		if err := value.Encode(s.Value); err != nil {
+			return fmt.Errorf("encoding %s: %w", s.Key, err)
+		}
+		doc.Content = append(doc.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: s.Key, HeadComment: s.Source}, value)
+	}
+	if path := viper.ConfigFileUsed(); path != "" {
+		doc.HeadComment = "config: " + path
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd, configShowCmd)
+
+	f := configValidateCmd.Flags()
+	f.StringP("format", "f", "table", "Output format: table, json")
+	f.Bool("strict", false, "Also fail on warnings")
+
+	f = configShowCmd.Flags()
+	f.StringP("format", "f", "yaml", "Output format: yaml, json")
+	f.Bool("effective", false, "Print the configuration in force, with defaults, inherited, env and flag values and their sources")
+}
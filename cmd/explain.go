@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Zubimendi/solsec/internal/explain"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+var explainFindingCmd = &cobra.Command{
+	Use:   "explain-finding <fingerprint|id>",
+	Short: "Explain a finding from a JSON report with an exploit walk-through",
+	Long: `Print an extended narrative for one finding in a JSON report: what the
+pattern means, the flagged code, a concrete attack sequence against it, and
+step-by-step remediation, drawn from the rules database.
+
+The finding is selected by its fingerprint (any unique prefix of at least 4
+characters) or its report ID.
+
+Examples:
+  solsec explain-finding 3f9a1c2e --report report.json
+  solsec explain-finding CUSTOM-ACCESS-1 --report report.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportPath, _ := cmd.Flags().GetString("report")
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		f, err := explain.Find(report, args[0])
+		if err != nil {
+			return err
+		}
+		explain.Write(os.Stdout, f, rules.Default())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainFindingCmd)
+	explainFindingCmd.Flags().String("report", "solsec-report.json", "JSON report containing the finding")
+}
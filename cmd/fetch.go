@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Zubimendi/solsec/internal/explorer"
+)
+
+// fetchSource downloads the verified source of address on chain (a --chain
+// value) into the user's source cache, following a proxy to its
+// implementation. It returns the contract and, for a proxy, its
+// implementation; token-review and system analyze both.
+func fetchSource(ctx context.Context, chain, address string) ([]*explorer.Contract, error) {
+	chainID, err := explorer.ParseChain(chain)
+	if err != nil {
+		return nil, fmt.Errorf("--chain: %w", err)
+	}
+	fetcher := explorer.Fetcher{
+		Client: &explorer.Client{APIKey: os.Getenv(explorer.APIKeyEnv)},
+		Dir:    explorer.DefaultDir(),
+	}
+	fetch := func(address string) (*explorer.Contract, error) {
+		c, err := fetcher.Fetch(ctx, chainID, address)
+		if err != nil {
+			return nil, fmt.Errorf("fetching the verified source of %s: %w", address, err)
+		}
+		fmt.Printf("🌐 Verified source of %s (%s) in %s\n", c.Name, c.Address, c.Dir)
+		return c, nil
+	}
+	c, err := fetch(address)
+	if err != nil {
+		return nil, err
+	}
+	if c.Implementation == "" {
+		return []*explorer.Contract{c}, nil
+	}
+	impl, err := fetch(c.Implementation)
+	if err != nil {
+		return nil, err
+	}
+	return []*explorer.Contract{c, impl}, nil
+}
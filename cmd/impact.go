@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/explorer"
+	"github.com/Zubimendi/solsec/internal/impact"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/spf13/cobra"
+)
+
+var impactCmd = &cobra.Command{
+	Use:   "impact --rpc <url>",
+	Short: "Estimate the value at risk of each finding in deployed contracts",
+	Long: `Estimate the economic impact of the findings in a JSON report. For each
+contract with a known address, impact reads what it holds over JSON-RPC: its
+native balance and its balance of every --token. Each finding in the contract
+is annotated with those holdings and their value, and the report is re-sorted
+by value at risk, highest first, instead of by severity alone. Findings in
+contracts without an address come last, most severe first.
+
+Holdings are valued with --price. Without any price, value is counted in the
+native currency and tokens are listed unvalued. Deployed addresses come from
+the report of "solsec system" or from --address; findings are matched to them
+by contract name.
+
+Examples:
+  solsec impact --rpc $ETH_RPC_URL --address Vault=0x...
+  solsec impact --rpc $ETH_RPC_URL --report system.json \
+    --token USDC=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48 --price ETH=3000 --price USDC=1`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpc, _ := cmd.Flags().GetString("rpc")
+		reportPath, _ := cmd.Flags().GetString("report")
+		outputPath, _ := cmd.Flags().GetString("output")
+		addressSpecs, _ := cmd.Flags().GetStringSlice("address")
+		tokenSpecs, _ := cmd.Flags().GetStringSlice("token")
+		priceSpecs, _ := cmd.Flags().GetStringSlice("price")
+		native, _ := cmd.Flags().GetString("native")
+		currency, _ := cmd.Flags().GetString("currency")
+		block, _ := cmd.Flags().GetUint64("block")
+		if outputPath == "" {
+			outputPath = reportPath
+		}
+		streamToStdout(outputPath)
+		format := strings.TrimPrefix(filepath.Ext(outputPath), ".")
+
+		tokens, err := parseTokens(tokenSpecs)
+		if err != nil {
+			return err
+		}
+		prices, err := parsePrices(priceSpecs)
+		if err != nil {
+			return err
+		}
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		addresses, err := deployedAddresses(report, addressSpecs)
+		if err != nil {
+			return err
+		}
+		if len(addresses) == 0 {
+			return fmt.Errorf("no deployed addresses: pass --address or a report of \"solsec system\"")
+		}
+
+		assessor := &impact.Assessor{
+			Client:   &impact.Client{URL: rpc, Block: block},
+			Native:   native,
+			Tokens:   tokens,
+			Prices:   prices,
+			Currency: currency,
+		}
+		// Only query the contracts findings are in, once each.
+		impacts := map[string]*parser.Impact{}
+		for _, f := range report.Findings {
+			address, ok := addresses[f.Contract]
+			if _, done := impacts[f.Contract]; !ok || done {
+				continue
+			}
+			fmt.Printf("💰 Reading the holdings of %s at %s...\n", f.Contract, address)
+			i, err := assessor.Assess(cmd.Context(), address)
+			if err != nil {
+				return err
+			}
+			impacts[f.Contract] = i
+		}
+		if len(impacts) == 0 {
+			return fmt.Errorf("no finding is in a contract with a known address (see --address)")
+		}
+		assessed := 0
+		for i := range report.Findings {
+			if im, ok := impacts[report.Findings[i].Contract]; ok {
+				report.Findings[i].Impact = im
+				assessed++
+			}
+		}
+		impact.Sort(report.Findings)
+
+		score := scorer.Score(report)
+		if err := writeReport(report, score, format, outputPath, reportOptionsFromFlags(cmd)); err != nil {
+			return err
+		}
+		printImpacts(impacts)
+		fmt.Printf("\n📊 %d of %d finding(s) assessed, report sorted by value at risk — written to %s\n",
+			assessed, len(report.Findings), displayPath(outputPath))
+		return nil
+	},
+}
+
+// printImpacts lists the assessed contracts, most valuable first.
+func printImpacts(impacts map[string]*parser.Impact) {
+	names := make([]string, 0, len(impacts))
+	for name := range impacts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if a, b := impacts[names[i]].Value, impacts[names[j]].Value; a != b {
+			return a > b
+		}
+		return names[i] < names[j]
+	})
+	fmt.Println()
+	for _, name := range names {
+		im := impacts[name]
+		var holdings []string
+		for _, h := range im.Holdings {
+			holdings = append(holdings, h.Amount+" "+h.Asset)
+		}
+		fmt.Printf("  %-24s %20s  (%s)\n", name, im, strings.Join(holdings, ", "))
+	}
+}
+
+// parseTokens reads Symbol=0x... token flags.
+func parseTokens(specs []string) ([]impact.Token, error) {
+	var tokens []impact.Token
+	for _, spec := range specs {
+		symbol, address, ok := strings.Cut(spec, "=")
+		if !ok || symbol == "" || !explorer.IsAddress(address) {
+			return nil, fmt.Errorf("invalid --token %q: want Symbol=0x followed by 40 hex digits", spec)
+		}
+		tokens = append(tokens, impact.Token{Symbol: symbol, Address: address})
+	}
+	return tokens, nil
+}
+
+// parsePrices reads Symbol=price flags.
+func parsePrices(specs []string) (map[string]float64, error) {
+	prices := map[string]float64{}
+	for _, spec := range specs {
+		symbol, value, ok := strings.Cut(spec, "=")
+		price, err := strconv.ParseFloat(value, 64)
+		if !ok || symbol == "" || err != nil || price < 0 {
+			return nil, fmt.Errorf("invalid --price %q: want Symbol=price, e.g. ETH=3000", spec)
+		}
+		prices[symbol] = price
+	}
+	return prices, nil
+}
+
+func init() {
+	rootCmd.AddCommand(impactCmd)
+
+	f := impactCmd.Flags()
+	f.String("rpc", "", "RPC URL of the chain the contracts are deployed on (required)")
+	f.String("report", "solsec-report.json", "JSON report whose findings to assess")
+	f.StringP("output", "o", "", "Output file path; the format follows the extension, or - for HTML on stdout (default: update --report in place)")
+	f.StringSlice("address", nil, "Deployed address of a contract, as Contract=0x... (repeatable)")
+	f.StringSlice("token", nil, "ERC-20 token whose balance counts, as Symbol=0x... (repeatable)")
+	f.StringSlice("price", nil, "Price of an asset in --currency, as Symbol=price, e.g. ETH=3000 (repeatable)")
+	f.String("native", "ETH", "Symbol of the chain's native currency")
+	f.String("currency", "USD", "Currency that --price values are in")
+	f.Uint64("block", 0, "Read balances at this block number instead of the latest")
+	f.BoolP("verbose", "v", false, "Include finding evidence in HTML and SARIF output")
+	f.Int("page-size", reporter.DefaultPageSize, "Paginate the HTML findings table above this many findings (0 disables paging)")
+	f.Bool("deterministic", false, "Omit the generation time (or take it from SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports")
+	f.String("repo-root", "", "Repository root that SARIF, GitLab and Code Climate file paths are relative to (default: the enclosing git repository)")
+	f.String("template", "", "Custom HTML report template: a complete template, or {{define}}s of its head, logo, disclaimer and footer blocks")
+	_ = impactCmd.MarkFlagRequired("rpc")
+}
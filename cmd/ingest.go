@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/config"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ingestFormats maps each --format of ingest to its parser.
+var ingestFormats = map[string]func([]byte) ([]parser.Finding, error){
+	"sarif":   parser.ParseSARIF,
+	"slither": parser.ParseBytes,
+	"mythril": parser.ParseMythril,
+	"semgrep": parser.ParseSemgrep,
+}
+
+var ingestCmd = &cobra.Command{
+	Use:   "ingest <file>...",
+	Short: "Import findings from other tools' output into a scored solsec report",
+	Long: `Convert the output of other security tools into solsec findings and write
+them as a solsec report, optionally merged into an existing JSON report, so
+everything a team runs ends up in one scored document. Imported findings are
+deduplicated and clustered like solsec's own.
+
+Supported formats:
+  sarif    any SARIF 2.1.0 log (Aderyn, Semgrep, CodeQL, ...)
+  slither  slither --json output
+  mythril  myth analyze -o json output
+  semgrep  semgrep --json output
+
+Examples:
+  solsec ingest --format semgrep semgrep.json --report report.json -o combined.json
+  solsec ingest --format sarif aderyn.sarif -o aderyn.html`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFormat, _ := cmd.Flags().GetString("format")
+		basePath, _ := cmd.Flags().GetString("report")
+		outputPath, _ := cmd.Flags().GetString("output")
+		streamToStdout(outputPath)
+		target, _ := cmd.Flags().GetString("target")
+
+		parse, ok := ingestFormats[strings.ToLower(inputFormat)]
+		if !ok {
+			return fmt.Errorf("unknown --format %q (available: mythril, sarif, semgrep, slither)", inputFormat)
+		}
+		format := strings.TrimPrefix(filepath.Ext(outputPath), ".")
+		if format == "" {
+			format = "json"
+		}
+
+		var findings []parser.Finding
+		for _, path := range args {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", path, err)
+			}
+			parsed, err := parse(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			findings = append(findings, parsed...)
+		}
+
+		var base *parser.AnalysisReport
+		if basePath != "" {
+			var err error
+			if base, err = parser.LoadReport(basePath); err != nil {
+				return err
+			}
+			if target == "" {
+				target = base.Target
+			}
+		}
+		if target == "" {
+			target = "."
+		}
+
+		report := analyzer.Ingest(target, findings)
+		if base != nil {
+			report = analyzer.Merge(target, base, report)
+		}
+		fileCfg, err := config.Load(viper.GetViper())
+		if err != nil {
+			return err
+		}
+		overrides, err := analyzer.ParseSeverityOverrides(fileCfg.SeverityOverrides)
+		if err != nil {
+			return err
+		}
+		paths := pathSeverity(fileCfg.PathSeverity)
+		if err := analyzer.ValidatePathSeverity(paths); err != nil {
+			return err
+		}
+		noCluster, _ := cmd.Flags().GetBool("no-cluster")
+		if err := analyzer.Process(report, analyzer.Options{
+			Pipeline:          pipeline(fileCfg.Pipeline, noCluster),
+			SeverityOverrides: overrides,
+			PathSeverities:    paths,
+		}); err != nil {
+			return err
+		}
+
+		score := scorer.Score(report)
+		if err := writeReport(report, score, format, outputPath, reportOptionsFromFlags(cmd)); err != nil {
+			return err
+		}
+		fmt.Printf("📥 Imported %d %s finding(s) from %d file(s)\n", len(findings), inputFormat, len(args))
+		printSummary(report, score, scorer.Grade(score), scorer.Verdict(score), outputPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ingestCmd)
+
+	f := ingestCmd.Flags()
+	f.String("format", "", "Input format: sarif | slither | mythril | semgrep")
+	f.String("report", "", "solsec JSON report to merge the imported findings into")
+	f.StringP("output", "o", "solsec-report.json", "Output file path; the format follows the extension (json | ndjson | html | sarif | pdf | csv; .xml is JUnit), or - for JSON on stdout")
+	f.String("target", "", "Project root the imported paths are relative to (default: the --report target, else .)")
+	f.BoolP("verbose", "v", false, "Include finding evidence in HTML and SARIF output")
+	f.Int("page-size", reporter.DefaultPageSize, "Paginate the HTML findings table above this many findings (0 disables paging)")
+	f.Bool("deterministic", false, "Omit the generation time (or take it from SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports")
+	f.String("repo-root", "", "Repository root that SARIF, GitLab and Code Climate file paths are relative to (default: the enclosing git repository)")
+	f.String("template", "", "Custom HTML report template: a complete template, or {{define}}s of its head, logo, disclaimer and footer blocks")
+	f.Bool("no-cluster", false, "List every finding separately instead of grouping those that share a root cause")
+	_ = ingestCmd.MarkFlagRequired("format")
+	_ = ingestCmd.RegisterFlagCompletionFunc("format", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"sarif", "slither", "mythril", "semgrep"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/poc"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// writeInvariantSuggestions writes Foundry invariant test stubs for the
+// contracts in target under the project's test/invariants directory and
+// reports what it wrote to w. Files without the generated marker were
+// written or edited by hand and are left alone.
+func writeInvariantSuggestions(w io.Writer, target string) error {
+	paths, err := source.SolidityFiles(target)
+	if err != nil {
+		return fmt.Errorf("listing Solidity files: %w", err)
+	}
+	var files []*solidity.File
+	for _, path := range paths {
+		// Tests and scripts are not deployment targets.
+		if strings.HasSuffix(path, ".t.sol") || strings.HasSuffix(path, ".s.sol") {
+			continue
+		}
+		f, err := solidity.ParseFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+	}
+
+	suites := poc.SuggestInvariants(files)
+	if len(suites) == 0 {
+		fmt.Fprintf(w, "🧭 No invariant suggestions: no contract exposes supply, balance or privileged-role state.\n\n")
+		return nil
+	}
+	root := source.ProjectRoot(target)
+	fmt.Fprintf(w, "🧭 Invariant suggestions for %d contract(s):\n", len(suites))
+	for _, s := range suites {
+		out := poc.InvariantPath(root, s.Contract.Name)
+		if existing, err := os.ReadFile(out); err == nil && !bytes.HasPrefix(existing, []byte(poc.GeneratedMarker)) {
+			fmt.Fprintf(w, "   %-28s skipped: %s was edited by hand\n", s.Contract.Name, out)
+			continue
+		}
+		code, err := poc.RenderInvariants(s, out)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(out), 0750); err != nil {
+			return fmt.Errorf("creating invariants directory: %w", err)
+		}
+		if err := os.WriteFile(out, code, 0640); err != nil {
+			return fmt.Errorf("writing invariants: %w", err)
+		}
+		fmt.Fprintf(w, "   %-28s %d invariant(s) → %s\n", s.Contract.Name, len(s.Invariants), out)
+	}
+	fmt.Fprintf(w, "   Fill in the TODOs, then run: forge test --match-path 'test/invariants/*'\n\n")
+	return nil
+}
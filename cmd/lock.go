@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/lock"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/project"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// analysisEnvironment records the toolchain cfg runs with. env is nil when
+// Slither is skipped.
+func analysisEnvironment(cfg analysisConfig, env *runner.Environment) *parser.Environment {
+	e := &parser.Environment{Solsec: appVersion, RulesDB: rules.Default().Version}
+	if env == nil {
+		return e
+	}
+	e.Slither = env.SlitherVersion
+	if known, err := runner.ListDetectors(); err == nil {
+		e.Detectors = enabledDetectors(known, cfg.Only, cfg.Exclude)
+	}
+	e.Solc = resolveSolc(cfg)
+	return e
+}
+
+// enabledDetectors is the sorted set of detectors Slither runs given --only
+// and --exclude.
+func enabledDetectors(known []runner.Detector, only, exclude []string) []string {
+	var out []string
+	for _, d := range known {
+		if len(only) > 0 && !slices.Contains(only, d.Check) {
+			continue
+		}
+		if slices.Contains(exclude, d.Check) {
+			continue
+		}
+		out = append(out, d.Check)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// resolveSolc is the compiler the run uses: the --solc pin, else the
+// project's configured version, else the solc on PATH.
+func resolveSolc(cfg analysisConfig) string {
+	if cfg.SolcVersion != "" {
+		return cfg.SolcVersion
+	}
+	if s, err := project.Load(source.ProjectRoot(cfg.Target)); err == nil && s != nil && len(s.SolcVersions) > 0 {
+		return strings.Join(s.SolcVersions, ", ")
+	}
+	return runner.DetectSolc()
+}
+
+// checkLock compares current with the environment pinned by --locked. A
+// mismatch is an error, or only a warning under --locked-warn.
+func checkLock(cfg analysisConfig, current *parser.Environment) error {
+	if cfg.Locked == nil {
+		return nil
+	}
+	diffs := lock.Diff(cfg.Locked, current)
+	if len(diffs) == 0 {
+		return nil
+	}
+	if cfg.LockWarn {
+		fmt.Fprintf(os.Stderr, "⚠️  Environment differs from %s; results may not reproduce:\n", lock.DefaultPath)
+		for _, d := range diffs {
+			fmt.Fprintf(os.Stderr, "   %s\n", d)
+		}
+		return nil
+	}
+	return fmt.Errorf("environment differs from %s:\n  %s\n\nInstall the locked versions, re-record the lock with --write-lock, or use --locked-warn to run anyway",
+		lock.DefaultPath, strings.Join(diffs, "\n  "))
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/config"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <report.json>...",
+	Short: "Merge multiple JSON reports into one deduplicated, re-scored report",
+	Long: `Combine JSON reports from CI shards, workspace packages, or different
+engines into a single report. Findings are deduplicated and the score, grade
+and summary are recomputed over the combined set.
+
+Examples:
+  solsec merge core.json periphery.json -o combined.html
+  solsec merge shards/*.json -o combined.sarif`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputPath, _ := cmd.Flags().GetString("output")
+		streamToStdout(outputPath)
+		format, _ := cmd.Flags().GetString("format")
+		target, _ := cmd.Flags().GetString("target")
+
+		if format == "" {
+			format = strings.TrimPrefix(filepath.Ext(outputPath), ".")
+		}
+		if format == "" {
+			format = "html"
+		}
+
+		reports := make([]*parser.AnalysisReport, 0, len(args))
+		for _, path := range args {
+			r, err := parser.LoadReport(path)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, r)
+		}
+
+		if target == "" {
+			targets := make([]string, 0, len(reports))
+			for _, r := range reports {
+				targets = append(targets, r.Target)
+			}
+			target = strings.Join(targets, ", ")
+		}
+
+		fileCfg, err := config.Load(viper.GetViper())
+		if err != nil {
+			return err
+		}
+		merged := analyzer.Merge(target, reports...)
+		noCluster, _ := cmd.Flags().GetBool("no-cluster")
+		if err := analyzer.Process(merged, analyzer.Options{Pipeline: pipeline(fileCfg.Pipeline, noCluster)}); err != nil {
+			return err
+		}
+		score := scorer.Score(merged)
+		if err := writeReport(merged, score, format, outputPath, reportOptionsFromFlags(cmd)); err != nil {
+			return err
+		}
+
+		fmt.Printf("🔗 Merged %d report(s)\n", len(reports))
+		printSummary(merged, score, scorer.Grade(score), scorer.Verdict(score), outputPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+
+	f := mergeCmd.Flags()
+	f.StringP("output", "o", "solsec-merged.html", "Output file path, or - for stdout (HTML unless --format is given)")
+	f.StringP("format", "f", "", "Output format: json | ndjson | html | sarif | pdf | csv | junit | gitlab | codeclimate (default: from --output extension)")
+	f.String("target", "", "Target name for the merged report (default: the input targets)")
+	f.BoolP("verbose", "v", false, "Include finding evidence in HTML and SARIF output")
+	f.Int("page-size", reporter.DefaultPageSize, "Paginate the HTML findings table above this many findings (0 disables paging)")
+	f.Bool("deterministic", false, "Omit the generation time (or take it from SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports")
+	f.String("repo-root", "", "Repository root that SARIF, GitLab and Code Climate file paths are relative to (default: the enclosing git repository)")
+	f.String("template", "", "Custom HTML report template: a complete template, or {{define}}s of its head, logo, disclaimer and footer blocks")
+	f.Bool("no-cluster", false, "List every finding separately instead of grouping those that share a root cause")
+	_ = mergeCmd.RegisterFlagCompletionFunc("format", completeFormats)
+}
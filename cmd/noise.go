@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/noise"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// maxBudgetRows caps the per-detector table printed by --suggest-config.
+const maxBudgetRows = 10
+
+// printNoiseBudget prints which detectors dominate the report and the
+// .solsec.yaml snippets that would tune them.
+func printNoiseBudget(w io.Writer, target string, report *parser.AnalysisReport) {
+	b := noise.Analyze(target, report.Findings, noise.Options{})
+	fmt.Fprintf(w, "📢 Noise budget: %d finding(s) from %d detector(s)\n", b.Total, len(b.Detectors))
+	for i, d := range b.Detectors {
+		if i == maxBudgetRows {
+			fmt.Fprintf(w, "   … %d more\n", len(b.Detectors)-maxBudgetRows)
+			break
+		}
+		marker := ""
+		if d.OverBudget {
+			marker = "  ⚠️  over budget"
+		}
+		fmt.Fprintf(w, "   %-36s %4d  %3.0f%%%s\n", d.Check, d.Count, d.Share*100, marker)
+	}
+
+	if len(b.Suggestions) == 0 {
+		fmt.Fprintf(w, "\n✅ No detector exceeds the noise budget (%d+ findings and %.0f%%+ of the report).\n\n",
+			noise.DefaultMinFindings, noise.DefaultMaxShare*100)
+		return
+	}
+	fmt.Fprintf(w, "\n💡 Suggested .solsec.yaml tuning:\n")
+	for _, s := range b.Suggestions {
+		fmt.Fprintf(w, "\n   # %s\n", s.Reason)
+		for _, line := range strings.Split(strings.TrimRight(s.Snippet, "\n"), "\n") {
+			fmt.Fprintf(w, "   %s\n", line)
+		}
+	}
+	fmt.Fprintln(w)
+}
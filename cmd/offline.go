@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/offline"
+	"github.com/Zubimendi/solsec/internal/project"
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// offlinePreflight fails fast, under --offline, when anything cfg needs is
+// not available locally, listing every missing piece at once rather than
+// letting a tool fail midway trying to download it.
+func offlinePreflight(cfg analysisConfig) error {
+	if !offline.Enabled() || cfg.NoSlither {
+		// Custom checks and the embedded rules database are always local.
+		return nil
+	}
+	var missing []string
+	if _, err := runner.DetectEnvironment(); err != nil {
+		missing = append(missing, "slither: "+strings.SplitN(err.Error(), "\n", 2)[0])
+	}
+
+	var versions []string
+	if cfg.SolcVersion != "" {
+		versions = []string{cfg.SolcVersion}
+	} else if s, err := project.Load(source.ProjectRoot(cfg.Target)); err == nil && s != nil {
+		versions = s.SolcVersions
+	}
+	for _, v := range versions {
+		if !runner.SolcInstalled(v) {
+			missing = append(missing, fmt.Sprintf("solc %s: not installed (solc-select install %s)", v, v))
+		}
+	}
+	if len(versions) == 0 && runner.DetectSolc() == "" {
+		missing = append(missing, "solc: none on PATH and the project pins no version (use --solc with an installed version)")
+	}
+
+	missing = append(missing, project.MissingDependencies(source.ProjectRoot(cfg.Target))...)
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("offline mode: required data is not available locally:\n  - %s\n\nInstall it while online, or re-run with --no-slither to use only the custom checks",
+		strings.Join(missing, "\n  - "))
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Zubimendi/solsec/internal/ci"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/policy"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/scorer"
+)
+
+// outputMode decides what analyze prints besides the report file.
+//
+//	default           progress, summary box
+//	--ci              FAIL line only (plus provider annotations)
+//	--quiet           nothing but errors on stderr
+//	--stdout-summary  exactly one JSON document on stdout; any other text
+//	                  (annotations, --suggest-config) goes to stderr
+type outputMode struct {
+	CI            bool
+	Quiet         bool
+	StdoutSummary bool
+	Provider      *ci.Provider // detected CI provider, or nil
+}
+
+// silent reports whether progress and the summary box are suppressed.
+func (m outputMode) silent() bool {
+	return m.CI || m.Quiet || m.StdoutSummary
+}
+
+// textOut is where requested human-readable extras are written, keeping
+// stdout clean when it is reserved for machine output or errors only.
+func (m outputMode) textOut() io.Writer {
+	if m.Quiet || m.StdoutSummary {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// annotate prints CI provider annotations for findings, unless quiet.
+func (m outputMode) annotate(findings []parser.Finding) {
+	if m.Provider != nil && !m.Quiet {
+		m.Provider.Annotate(m.textOut(), findings)
+	}
+}
+
+// failText reports whether the plain-text FAIL line is printed.
+func (m outputMode) failText() bool {
+	return m.CI && !m.Quiet && !m.StdoutSummary
+}
+
+// runSummary is the single JSON document --stdout-summary prints.
+type runSummary struct {
+	Target      string         `json:"target"`
+	Score       int            `json:"score"`
+	Grade       string         `json:"grade"`
+	Verdict     string         `json:"verdict"`
+	Summary     parser.Summary `json:"summary"`
+	Report      string         `json:"report"`
+	FailOn      string         `json:"fail_on"`
+	MaxFindings map[string]int `json:"max_findings,omitempty"`
+	Policy      []policy.Rule  `json:"policy,omitempty"`
+	Failures    []string       `json:"failures"`
+	Passed      bool           `json:"passed"`
+}
+
+func writeRunSummary(w io.Writer, report *parser.AnalysisReport, score int, outputPath string, policy failPolicy, failures []string) error {
+	if failures == nil {
+		failures = []string{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(runSummary{
+		Target:      report.Target,
+		Score:       score,
+		Grade:       scorer.Grade(score),
+		Verdict:     scorer.Verdict(score),
+		Summary:     report.Summary,
+		Report:      outputPath,
+		FailOn:      policy.FailOn,
+		MaxFindings: policy.limits(),
+		Policy:      policy.Rules,
+		Failures:    failures,
+		Passed:      len(failures) == 0,
+	})
+}
+
+// stdoutPath as the output path (-o -) streams the report to stdout.
+const stdoutPath = "-"
+
+// reportOut is the real stdout while a report is streamed to it; see
+// streamToStdout.
+var reportOut *os.File
+
+// streamToStdout diverts everything but the report to stderr when
+// outputPath is stdoutPath, so stdout can be piped into jq or an upload
+// script. Call it before any progress output.
+func streamToStdout(outputPath string) {
+	if outputPath == stdoutPath && reportOut == nil {
+		reportOut = os.Stdout
+		os.Stdout = os.Stderr
+	}
+}
+
+// streamReport renders report with rep into a temp file and copies it to
+// stdout. Reporters write to files (PDF through an external renderer), so
+// this works for every format.
+func streamReport(rep reporter.Reporter, report *parser.AnalysisReport, score int) error {
+	dir, err := os.MkdirTemp("", "solsec-report-")
+	if err != nil {
+		return fmt.Errorf("creating report workspace: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "report."+rep.Name())
+	if err := rep.Write(report, score, path); err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	out := reportOut
+	if out == nil {
+		out = os.Stdout
+	}
+	_, err = io.Copy(out, f)
+	return err
+}
+
+// displayPath names outputPath for messages.
+func displayPath(outputPath string) string {
+	if outputPath == stdoutPath {
+		return "stdout"
+	}
+	return outputPath
+}
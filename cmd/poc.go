@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Zubimendi/solsec/internal/explain"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/poc"
+	"github.com/spf13/cobra"
+)
+
+var pocCmd = &cobra.Command{
+	Use:   "poc <fingerprint|id>",
+	Short: "Generate a Foundry proof-of-concept test scaffold for a finding",
+	Long: `Generate a Foundry test skeleton for one finding in a JSON report: an
+attacker contract and a test asserting the exploit against the flagged
+function. Fill in the TODOs and run it to confirm or refute the finding.
+
+Templates exist for reentrancy and access-control findings. The finding is
+selected by its fingerprint (any unique prefix of at least 4 characters) or
+its report ID.
+
+Examples:
+  solsec poc 49a40541 --report report.json
+  solsec poc CUSTOM-ACCESS-1 --report report.json -o test/MintPoC.t.sol`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportPath, _ := cmd.Flags().GetString("report")
+		outputPath, _ := cmd.Flags().GetString("output")
+		force, _ := cmd.Flags().GetBool("force")
+
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		f, err := explain.Find(report, args[0])
+		if err != nil {
+			return err
+		}
+		if outputPath == "" {
+			outputPath = poc.DefaultPath(f)
+		}
+		if _, err := os.Stat(outputPath); err == nil && !force {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", outputPath)
+		}
+
+		code, err := poc.Generate(f, outputPath)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0750); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+		if err := os.WriteFile(outputPath, code, 0640); err != nil {
+			return fmt.Errorf("writing PoC: %w", err)
+		}
+		fmt.Printf("🧪 PoC scaffold for %s written to %s\n", f.Title, outputPath)
+		fmt.Printf("   Fill in the TODOs, then run: forge test --match-path %s -vvvv\n", outputPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pocCmd)
+
+	f := pocCmd.Flags()
+	f.String("report", "solsec-report.json", "JSON report containing the finding")
+	f.StringP("output", "o", "", "Test file to write (default: test/poc/<Contract>_<function>_<Kind>.t.sol)")
+	f.Bool("force", false, "Overwrite an existing file")
+}
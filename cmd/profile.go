@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+)
+
+// profile is a named preset of engines, detector sets, custom checks and
+// timeouts, so users don't need to memorize flag combinations. Explicit
+// flags, then .solsec.yaml, always win.
+type profile struct {
+	Description    string
+	NoSlither      bool
+	Mythril        bool
+	SlitherTimeout time.Duration
+	MythrilTimeout time.Duration
+
+	// Exclude lists the Slither detectors the profile skips; nil runs
+	// them all. It does not apply when --only selects detectors.
+	Exclude []string
+
+	// Checks selects the custom checks (see checks.Select); nil runs the
+	// default ones.
+	Checks []string
+}
+
+// styleDetectors are Slither's naming, pragma and literal style detectors:
+// worth a deep review, noise in everyday runs.
+var styleDetectors = []string{"naming-convention", "similar-names", "too-many-digits", "solc-version", "pragma", "constable-states"}
+
+// hygieneChecks are the custom checks for code quality rather than
+// exploitable bugs, which the quick profile leaves out.
+var hygieneChecks = []string{"unused-contract", "compiler-settings", "license", "comments", "dead-code", "misleading-names"}
+
+var profiles = map[string]profile{
+	"quick": {
+		Description: "Custom vulnerability checks only, no hygiene checks — seconds, no Python toolchain needed",
+		NoSlither:   true,
+		Checks:      defaultChecksExcept(hygieneChecks),
+	},
+	"standard": {
+		Description:    "Slither without its style detectors + the default custom checks (the default)",
+		SlitherTimeout: 5 * time.Minute,
+		Exclude:        styleDetectors,
+	},
+	"deep": {
+		Description:    "All Slither detectors + Mythril symbolic execution + every custom check including opt-in ones, extended timeouts",
+		Mythril:        true,
+		SlitherTimeout: 20 * time.Minute,
+		MythrilTimeout: 15 * time.Minute,
+		Checks:         checks.Names(),
+	},
+}
+
+// defaultChecksExcept names the default custom checks other than skip.
+func defaultChecksExcept(skip []string) []string {
+	var names []string
+	for _, c := range checks.Select(nil) {
+		if !slices.Contains(skip, c.Name) {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+func lookupProfile(name string) (profile, error) {
+	p, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return profile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(profileNames(), ", "))
+	}
+	return p, nil
+}
+
+func profileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for n := range profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Render a JSON report as a deliverable",
+}
+
+var reportAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Render findings into a Markdown audit report skeleton",
+	Long: `Render the findings of a JSON report into a Markdown audit report that
+auditors finish by hand. It has the sections of a professional audit:
+
+  - a cover with the project, auditors, date and commit
+  - an executive summary with the findings by severity and the risk score
+  - the scope: every analyzed file with its SLOC and SHA-256
+  - the methodology: engines and tool versions, and severity definitions
+  - a section per finding, numbered by severity (H-01, M-01...), with its
+    location, code excerpt, description, severity rationale, recommendation
+    and references
+
+Everything solsec cannot know (the engagement summary, manual review notes,
+confirmed severities and client responses) is marked "TODO:".
+
+Examples:
+  solsec report audit
+  solsec report audit --report report.json --project "Acme Vault" --auditor "Jane Doe" -o AUDIT.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportPath, _ := cmd.Flags().GetString("report")
+		outputPath, _ := cmd.Flags().GetString("output")
+		project, _ := cmd.Flags().GetString("project")
+		auditors, _ := cmd.Flags().GetStringSlice("auditor")
+		streamToStdout(outputPath)
+
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		rep := &reporter.AuditReporter{Project: project, Auditors: auditors}
+		score := scorer.Score(report)
+		if outputPath == stdoutPath {
+			err = streamReport(rep, report, score)
+		} else {
+			err = rep.Write(report, score, outputPath)
+		}
+		if err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		if outputPath != stdoutPath {
+			fmt.Printf("📝 Audit report skeleton written to %s\n", outputPath)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportAuditCmd)
+
+	f := reportAuditCmd.Flags()
+	f.String("report", "solsec-report.json", "JSON report containing the findings")
+	f.StringP("output", "o", "solsec-audit.md", "Output file path, or - for stdout")
+	f.String("project", "", "Project name for the title (default: the target's name)")
+	f.StringSlice("auditor", nil, "Auditor to list on the cover (repeatable)")
+}
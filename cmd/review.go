@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/explain"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/triage"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Spot-check a sample of findings and estimate the false-positive rate",
+	Long: `Walk through a stratified sample of the findings in a JSON report and record
+a verdict on each. The sample covers every severity, with the rest shared in
+proportion to how many findings each severity has, and is spread over as many
+checks and files as possible.
+
+For each finding, answer:
+
+  a  accept: a true positive
+  r  reject: a false positive, triaged in slither.db.json so later analyses
+     hide it
+  s  skip it
+  q  stop reviewing
+
+and optionally add a note. Verdicts are recorded in solsec-review.json, and
+findings with a verdict are not sampled again. At the end, solsec estimates
+the false-positive rate of the whole report from every verdict recorded for
+its findings, with a 95% confidence margin.
+
+Examples:
+  solsec review --sample 20
+  solsec review --sample 50 --report report.json --seed 7`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportPath, _ := cmd.Flags().GetString("report")
+		dbPath, _ := cmd.Flags().GetString("database")
+		logPath, _ := cmd.Flags().GetString("log")
+		n, _ := cmd.Flags().GetInt("sample")
+		seed, _ := cmd.Flags().GetUint64("seed")
+		if n < 1 {
+			return fmt.Errorf("--sample must be at least 1")
+		}
+
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		db, err := triage.Load(dbPath)
+		if err != nil {
+			return err
+		}
+		reviews, err := triage.LoadReviews(logPath)
+		if err != nil {
+			return err
+		}
+
+		var candidates []parser.Finding
+		for _, f := range report.Findings {
+			if !db.Hidden(f) && !triage.Reviewed(reviews, f) {
+				candidates = append(candidates, f)
+			}
+		}
+		if seed == 0 {
+			seed = uint64(time.Now().UnixNano())
+		}
+		sample := triage.Sample(candidates, n, rand.New(rand.NewPCG(seed, seed)))
+		if len(sample) == 0 {
+			fmt.Println("Every finding in the report is already reviewed or triaged.")
+		}
+
+		in := bufio.NewScanner(os.Stdin)
+		accepted, rejected := 0, 0
+		for i, f := range sample {
+			fmt.Printf("\n[%d/%d] ", i+1, len(sample))
+			explain.WriteBrief(os.Stdout, &f)
+
+			verdict, ok := promptVerdict(os.Stdout, in)
+			if !ok {
+				break
+			}
+			if verdict == "" {
+				continue
+			}
+			fmt.Print("Note (Enter for none): ")
+			note := ""
+			if in.Scan() {
+				note = strings.TrimSpace(in.Text())
+			}
+
+			if verdict == triage.VerdictRejected {
+				if _, err := db.Add(f); err != nil {
+					return err
+				}
+				rejected++
+			} else {
+				accepted++
+			}
+			line := 0
+			if len(f.Lines) > 0 {
+				line = f.Lines[0]
+			}
+			reviews = append(reviews, triage.Review{
+				Fingerprint: f.Fingerprint,
+				ID:          f.ID,
+				Check:       f.Check,
+				Severity:    f.Severity,
+				File:        f.File,
+				Line:        line,
+				Verdict:     verdict,
+				Note:        note,
+				ReviewedAt:  time.Now().UTC().Format(time.RFC3339),
+			})
+			// Save as we go, so an interrupted session keeps its verdicts.
+			if err := triage.WriteReviews(logPath, reviews); err != nil {
+				return err
+			}
+			if verdict == triage.VerdictRejected {
+				if err := db.Write(dbPath); err != nil {
+					return err
+				}
+			}
+		}
+
+		fmt.Printf("\n%s\n", strings.Repeat("─", 60))
+		fmt.Printf("  Reviewed %d finding(s) this session: %d accepted, %d rejected\n", accepted+rejected, accepted, rejected)
+		if rejected > 0 {
+			fmt.Printf("  Rejected findings are triaged in %s\n", dbPath)
+		}
+		if e, ok := triage.EstimateFalsePositives(report.Findings, reviews); ok {
+			fmt.Printf("  Estimated false-positive rate: %.0f%% ± %.0f%% (95%% confidence, %d verdict(s))\n",
+				100*e.Rate, 100*e.Margin, e.Reviewed)
+			if e.Covered < 1 {
+				fmt.Printf("  Covers %.0f%% of the findings; severities without a verdict are not estimated\n", 100*e.Covered)
+			}
+		}
+		fmt.Printf("%s\n", strings.Repeat("─", 60))
+		return nil
+	},
+}
+
+// promptVerdict asks for a verdict until it gets one. It returns "" for a
+// skipped finding, and false when the reviewer quits or input ends.
+func promptVerdict(w io.Writer, in *bufio.Scanner) (string, bool) {
+	for {
+		fmt.Fprint(w, "\n[a]ccept  [r]eject as false positive  [s]kip  [q]uit: ")
+		if !in.Scan() {
+			fmt.Fprintln(w)
+			return "", false
+		}
+		switch strings.ToLower(strings.TrimSpace(in.Text())) {
+		case "a", "accept":
+			return triage.VerdictAccepted, true
+		case "r", "reject":
+			return triage.VerdictRejected, true
+		case "s", "skip":
+			return "", true
+		case "q", "quit":
+			return "", false
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	f := reviewCmd.Flags()
+	f.String("report", "solsec-report.json", "JSON report containing the findings")
+	f.Int("sample", 20, "Number of findings to review")
+	f.String("database", triage.DefaultPath, "Triage database that rejected findings are added to")
+	f.String("log", triage.DefaultReviewPath, "Review log recording every verdict")
+	f.Uint64("seed", 0, "Seed for the sample, to draw the same one again (0 picks a random seed)")
+}
@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/Zubimendi/solsec/internal/config"
+	"github.com/Zubimendi/solsec/internal/offline"
+	"github.com/Zubimendi/solsec/internal/scorer"
 )
 
 const (
@@ -13,7 +20,16 @@ const (
 	appVersion = "1.0.0"
 )
 
-var cfgFile string
+var (
+	cfgFile     string
+	offlineMode bool
+
+	// configErr is why the config file could not be applied, and
+	// configWarnings the overrides its base config rejected. Commands report
+	// them before running, except the config commands, which inspect them.
+	configErr      error
+	configWarnings []string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   appName,
@@ -30,30 +46,103 @@ Security-first Solidity static analysis.
 Wraps Slither with opinionated output, custom checks, and
 severity-ranked reports in JSON, HTML, and SARIF formats.
 `,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		for _, w := range configWarnings {
+			fmt.Fprintf(os.Stderr, "⚠️  config: %s\n", w)
+		}
+		if configErr != nil {
+			cmd.SilenceUsage = true
+		}
+		return configErr
+	},
 }
 
+// Execute runs the root command. SIGINT/SIGTERM cancel the command's context
+// so engines are stopped and partial results written; a second signal
+// terminates immediately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, context.Canceled) {
+			os.Exit(130)
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: $HOME/.solsec.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: ./.solsec.yaml, then $HOME/.solsec.yaml)")
 	_ = viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
+	rootCmd.PersistentFlags().BoolVar(&offlineMode, "offline", false, "Guarantee no network access; fail fast listing any required data that is not available locally")
 }
 
 func initConfig() {
+	if offlineMode {
+		offline.Enable()
+	}
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
 	} else {
+		// A project-local .solsec.yaml takes precedence over the user's.
 		home, _ := os.UserHomeDir()
+		viper.AddConfigPath(".")
 		viper.AddConfigPath(home)
 		viper.SetConfigType("yaml")
 		viper.SetConfigName(".solsec")
 	}
 	viper.AutomaticEnv()
-	_ = viper.ReadInConfig()
+	if err := viper.ReadInConfig(); err != nil {
+		return
+	}
+	// Honor offline: true before extends: could reach the network.
+	if viper.GetBool("offline") {
+		offline.Enable()
+	}
+
+	// Layer the project config over the shared config it extends.
+	configWarnings, configErr = config.Inherit(context.Background(), viper.GetViper())
+	if configErr != nil {
+		return
+	}
+
+	cfg, err := config.Load(viper.GetViper())
+	if err != nil {
+		configErr = err
+		return
+	}
+	configErr = applyGrading(cfg.Grading)
+}
+
+// applyGrading installs the configured grade scale for every report.
+func applyGrading(g config.Grading) error {
+	scale, err := gradingScale(g)
+	if err != nil {
+		return err
+	}
+	scorer.SetScale(scale)
+	return nil
+}
+
+// gradingScale builds the grade scale g configures.
+func gradingScale(g config.Grading) (scorer.Scale, error) {
+	scale := scorer.DefaultScale
+	if len(g.Bands) > 0 {
+		scale.Bands = nil
+		for _, b := range g.Bands {
+			scale.Bands = append(scale.Bands, scorer.Band{Grade: b.Grade, Below: b.Below, Verdict: b.Verdict})
+		}
+	}
+	scale.NoEmoji = g.Emoji != nil && !*g.Emoji
+	if err := scale.Validate(); err != nil {
+		return scorer.Scale{}, fmt.Errorf("config grading: %w", err)
+	}
+	return scale, nil
 }
\ No newline at end of file
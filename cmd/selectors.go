@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/abi"
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/Zubimendi/solsec/internal/source"
+	"github.com/spf13/cobra"
+)
+
+var selectorsCmd = &cobra.Command{
+	Use:   "selectors <target>",
+	Short: "List the function selectors, event topics and error selectors of compiled contracts",
+	Long: `Compile the target with solc and list, for every contract, the 4-byte
+selector of each function and custom error and the topic0 of each event.
+Useful in incident response to identify calls and logs in raw transactions,
+and to configure monitoring tools.
+
+solc must be on PATH. Remappings are read from remappings.txt.
+
+Examples:
+  solsec selectors ./contracts
+  solsec selectors ./contracts --format json -o selectors.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		outputPath, _ := cmd.Flags().GetString("output")
+		contract, _ := cmd.Flags().GetString("contract")
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported format %q (use table or json)", format)
+		}
+
+		files, err := source.SolidityFiles(args[0])
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no Solidity files in %s", args[0])
+		}
+		out, err := runner.SolcCombinedJSON(cmd.Context(), "abi", files)
+		if err != nil {
+			return err
+		}
+		entries, err := abi.FromCombinedJSON(out)
+		if err != nil {
+			return err
+		}
+		if contract != "" {
+			kept := entries[:0]
+			for _, e := range entries {
+				if e.Contract == contract {
+					kept = append(kept, e)
+				}
+			}
+			if len(kept) == 0 {
+				return fmt.Errorf("--contract %s: no such contract in %s", contract, args[0])
+			}
+			entries = kept
+		}
+
+		w := io.Writer(os.Stdout)
+		if outputPath != "" {
+			f, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("creating output file: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(entries)
+		}
+		printSelectors(w, entries)
+		return nil
+	},
+}
+
+func printSelectors(w io.Writer, entries []abi.Entry) {
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.Selector))
+	}
+	contract := ""
+	for _, e := range entries {
+		if e.File+":"+e.Contract != contract {
+			contract = e.File + ":" + e.Contract
+			fmt.Fprintf(w, "\n%s\n%s\n", contract, strings.Repeat("─", len(contract)))
+		}
+		fmt.Fprintf(w, "  %-8s  %-*s  %s\n", e.Kind, width, e.Selector, e.Signature)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(selectorsCmd)
+
+	f := selectorsCmd.Flags()
+	f.StringP("format", "f", "table", "Output format: table, json")
+	f.StringP("output", "o", "", "Write the selectors to a file instead of stdout")
+	f.String("contract", "", "Only list this contract")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/selftest"
+	"github.com/spf13/cobra"
+)
+
+// bundledFixtures holds the fixtures embedded in the binary (see SetFixtures).
+var bundledFixtures fs.FS
+
+// SetFixtures installs the bundled check fixtures used by `solsec selftest`.
+func SetFixtures(fsys fs.FS) { bundledFixtures = fsys }
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run the custom checks against their bundled fixtures",
+	Long: `Run every custom check against its fixture contracts and compare the
+findings with the expected results, to verify an installation or to give a new
+check regression coverage.
+
+Fixtures are bundled into the binary. When developing a check, point --dir at
+a fixtures tree (testdata/contracts) to pick up changes without rebuilding:
+
+  testdata/contracts/<check>/vulnerable/*.sol   must be flagged
+  testdata/contracts/<check>/safe/*.sol         must not be flagged
+  testdata/contracts/<check>/expected.yaml      exact expected findings`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		names, _ := cmd.Flags().GetStringSlice("checks")
+
+		fsys := bundledFixtures
+		if dir != "" {
+			fsys = os.DirFS(dir)
+		}
+		if fsys == nil {
+			return fmt.Errorf("no bundled fixtures in this build; use --dir")
+		}
+		selected := checks.Select(names)
+		if len(selected) == 0 {
+			return fmt.Errorf("no checks match %v (available: %v)", names, checks.Names())
+		}
+
+		failed := 0
+		for _, r := range selftest.Run(fsys, selected) {
+			if r.Passed() {
+				fmt.Printf("  ✅ %s\n", r.Check)
+				continue
+			}
+			failed++
+			fmt.Printf("  ❌ %s\n", r.Check)
+			if r.Err != nil {
+				fmt.Printf("       error: %v\n", r.Err)
+			}
+			for _, m := range r.Missing {
+				fmt.Printf("       missing:    %s\n", m)
+			}
+			for _, u := range r.Unexpected {
+				fmt.Printf("       unexpected: %s\n", u)
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d check(s) failed self-test", failed, len(selected))
+		}
+		fmt.Printf("\n✅ All %d check(s) passed self-test\n", len(selected))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+	selftestCmd.Flags().String("dir", "", "Fixtures directory to use instead of the bundled fixtures")
+	selftestCmd.Flags().StringSlice("checks", nil, "Only self-test these checks")
+	_ = selftestCmd.MarkFlagDirname("dir")
+	_ = selftestCmd.RegisterFlagCompletionFunc("checks", completeChecks)
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/Zubimendi/solsec/internal/source"
+	"github.com/Zubimendi/solsec/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var storageCmd = &cobra.Command{
+	Use:   "storage <target>",
+	Short: "Print contract storage layouts, or diff them against an earlier version",
+	Long: `Compile the target with solc and print the storage layout of every contract
+with state variables: the slot, offset, size and type of each variable.
+
+With --compare, the target is treated as an upgrade of the contracts in the
+given directory (e.g. a checkout of the deployed release) and each contract's
+layout is diffed against its earlier version. Upgrades must keep every
+variable at its slot and offset with its type; new variables may only use
+unused storage or space reserved by a __gap array. Unsafe changes (moved,
+removed, retyped or overlapping variables) make the command fail, so it can
+gate CI before an upgrade is deployed.
+
+solc must be on PATH. Remappings are read from remappings.txt.
+
+Examples:
+  solsec storage ./contracts --contract Vault
+  solsec storage ./contracts --compare ../vault-v1/contracts`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		comparePath, _ := cmd.Flags().GetString("compare")
+		contract, _ := cmd.Flags().GetString("contract")
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported format %q (use table or json)", format)
+		}
+
+		layouts, err := compileLayouts(cmd, args[0], contract)
+		if err != nil {
+			return err
+		}
+		if comparePath == "" {
+			if format == "json" {
+				return writeJSON(os.Stdout, layouts)
+			}
+			for _, l := range layouts {
+				printLayout(os.Stdout, l)
+			}
+			return nil
+		}
+
+		old, err := compileLayouts(cmd, comparePath, contract)
+		if err != nil {
+			return err
+		}
+		diffs := diffLayouts(old, layouts)
+		if format == "json" {
+			if err := writeJSON(os.Stdout, diffs); err != nil {
+				return err
+			}
+		} else {
+			printLayoutDiffs(os.Stdout, diffs)
+		}
+		unsafe := 0
+		for _, d := range diffs {
+			for _, c := range d.Changes {
+				if c.Unsafe {
+					unsafe++
+				}
+			}
+		}
+		if unsafe > 0 {
+			// stderr keeps JSON on stdout parseable.
+			fmt.Fprintf(os.Stderr, "FAIL: %d unsafe storage layout change(s)\n", unsafe)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// layoutDiff is the comparison of one contract's two layouts.
+type layoutDiff struct {
+	Contract string           `json:"contract"`
+	File     string           `json:"file"`
+	Status   string           `json:"status"` // "changed", "unchanged", "added" or "removed"
+	Changes  []storage.Change `json:"changes,omitempty"`
+}
+
+// compileLayouts returns the storage layouts of the contracts in target,
+// or only of contract when it is set.
+func compileLayouts(cmd *cobra.Command, target, contract string) ([]storage.Layout, error) {
+	files, err := source.SolidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Solidity files in %s", target)
+	}
+	out, err := runner.SolcCombinedJSON(cmd.Context(), "storage-layout", files)
+	if err != nil {
+		return nil, err
+	}
+	layouts, err := storage.FromCombinedJSON(out)
+	if err != nil || contract == "" {
+		return layouts, err
+	}
+	for _, l := range layouts {
+		if l.Contract == contract {
+			return []storage.Layout{l}, nil
+		}
+	}
+	return nil, fmt.Errorf("--contract %s: no contract with state variables in %s", contract, target)
+}
+
+// diffLayouts pairs contracts by name. A contract only in one version has
+// no upgrade to check.
+func diffLayouts(old, upgraded []storage.Layout) []layoutDiff {
+	oldByName := map[string]storage.Layout{}
+	for _, l := range old {
+		oldByName[l.Contract] = l
+	}
+	var diffs []layoutDiff
+	for _, l := range upgraded {
+		o, ok := oldByName[l.Contract]
+		delete(oldByName, l.Contract)
+		d := layoutDiff{Contract: l.Contract, File: l.File, Status: "added"}
+		if ok {
+			d.Changes = storage.Diff(o, l)
+			d.Status = "unchanged"
+			if len(d.Changes) > 0 {
+				d.Status = "changed"
+			}
+		}
+		diffs = append(diffs, d)
+	}
+	for _, l := range old {
+		if _, ok := oldByName[l.Contract]; ok {
+			diffs = append(diffs, layoutDiff{Contract: l.Contract, File: l.File, Status: "removed"})
+		}
+	}
+	return diffs
+}
+
+func printLayout(w io.Writer, l storage.Layout) {
+	title := l.File + ":" + l.Contract
+	fmt.Fprintf(w, "\n%s\n%s\n", title, strings.Repeat("─", len(title)))
+	fmt.Fprintf(w, "  %-6s %-6s %-6s %-32s %s\n", "Slot", "Offset", "Bytes", "Type", "Variable")
+	for _, v := range l.Vars {
+		fmt.Fprintf(w, "  %-6s %-6d %-6d %-32s %s\n", v.Slot, v.Offset, v.Bytes, v.Type, v.Label)
+	}
+}
+
+func printLayoutDiffs(w io.Writer, diffs []layoutDiff) {
+	for _, d := range diffs {
+		fmt.Fprintf(w, "\n%s:%s — %s\n", d.File, d.Contract, d.Status)
+		for _, c := range d.Changes {
+			mark := "✅"
+			if c.Unsafe {
+				mark = "❌"
+			}
+			fmt.Fprintf(w, "  %s %-13s slot %s offset %d: %s\n", mark, c.Kind, c.Slot, c.Offset, c.Detail)
+		}
+	}
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func init() {
+	rootCmd.AddCommand(storageCmd)
+
+	f := storageCmd.Flags()
+	f.String("compare", "", "Diff against the contracts in this directory, the version being upgraded from")
+	f.StringP("format", "f", "table", "Output format: table, json")
+	f.String("contract", "", "Only show this contract")
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/config"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/Zubimendi/solsec/internal/source"
+	"github.com/Zubimendi/solsec/internal/system"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var systemCmd = &cobra.Command{
+	Use:   "system <path@address|address>...",
+	Short: "Analyze a protocol deployed as several contracts as one system",
+	Long: `Analyze the components of a deployed protocol (e.g. router, factory and
+vault) together. Each component is analyzed on its own, then address
+constants in each component's source are resolved to the other components'
+addresses. The system report holds every component's findings plus trust
+findings:
+
+  custom-inherited-trust        a component hardcodes another component
+                                that privileged callers can change
+  custom-unresolved-dependency  a component hardcodes an address outside
+                                the system, whose code was not analyzed
+
+Each component is its verified source, given as path@address, or its
+deployed address on --chain, whose verified source is fetched as for
+token-review. The address may be left out of path@address for components
+nothing refers to. A fetched proxy brings its implementation in as another
+component.
+
+Examples:
+  solsec system 0x7a250d5630b4cf539739df2c5dacb4c659f2488d 0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f
+  solsec system ./router@0x7a250d5630b4cf539739df2c5dacb4c659f2488d ./vault@0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputPath, _ := cmd.Flags().GetString("output")
+		streamToStdout(outputPath)
+		format := strings.TrimPrefix(filepath.Ext(outputPath), ".")
+
+		chain, _ := cmd.Flags().GetString("chain")
+		var components []parser.Component
+		names := map[string]bool{}
+		for _, spec := range args {
+			c, err := system.ParseComponent(spec)
+			if err != nil {
+				return err
+			}
+			if c.Path != "" {
+				components = append(components, c)
+				names[c.Name] = true
+				continue
+			}
+			contracts, err := fetchSource(cmd.Context(), chain, c.Address)
+			if err != nil {
+				return err
+			}
+			for _, fetched := range contracts {
+				// Deployed contracts often share a name (two proxies,
+				// say); tell them apart by address.
+				name := fetched.Name
+				if names[name] {
+					name += "-" + fetched.Address[2:8]
+				}
+				names[name] = true
+				components = append(components, parser.Component{Name: name, Address: fetched.Address, Path: fetched.Dir})
+			}
+		}
+
+		fileCfg, err := config.Load(viper.GetViper())
+		if err != nil {
+			return err
+		}
+		p, err := lookupProfile("standard")
+		if err != nil {
+			return err
+		}
+		noSlither, _ := cmd.Flags().GetBool("no-slither")
+
+		var reports []*parser.AnalysisReport
+		for _, c := range components {
+			cfg := analysisConfig{
+				Target:            c.Path,
+				NoSlither:         noSlither,
+				SlitherTimeout:    p.SlitherTimeout,
+				Checks:            fileCfg.Checks,
+				Exclude:           fileCfg.Exclude,
+				ExcludePaths:      fileCfg.ExcludePaths,
+				Pipeline:          fileCfg.Pipeline,
+				SeverityOverrides: fileCfg.SeverityOverrides,
+				PathSeverity:      pathSeverity(fileCfg.PathSeverity),
+				Limits:            source.DefaultLimits,
+			}
+			cfg.SolcVersion, _ = cmd.Flags().GetString("solc")
+			cfg.logf("\n🧩 Component: %s %s\n", c.Name, c.Address)
+			report, err := analyzeTarget(cmd.Context(), cfg)
+			if err != nil {
+				return fmt.Errorf("component %s: %w", c.Name, err)
+			}
+			reports = append(reports, report)
+			if report.Interrupted {
+				break
+			}
+		}
+
+		edges, err := system.Resolve(components)
+		if err != nil {
+			return err
+		}
+		findings, err := system.Findings(components, edges)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, analyzer.Ingest(".", findings))
+
+		combined := analyzer.Merge("system", reports...)
+		combined.System = &parser.System{Components: components, Edges: edges}
+		noCluster, _ := cmd.Flags().GetBool("no-cluster")
+		if err := analyzer.Process(combined, analyzer.Options{Pipeline: pipeline(fileCfg.Pipeline, noCluster)}); err != nil {
+			return err
+		}
+
+		score := scorer.Score(combined)
+		if err := writeReport(combined, score, format, outputPath, reportOptionsFromFlags(cmd)); err != nil {
+			return err
+		}
+		resolved := 0
+		for _, e := range edges {
+			if e.To != "" {
+				resolved++
+			}
+		}
+		fmt.Printf("\n🔗 %d component(s), %d address dependency(ies), %d within the system\n", len(components), len(edges), resolved)
+		printSummary(combined, score, scorer.Grade(score), scorer.Verdict(score), outputPath)
+		if combined.Interrupted {
+			return interruptedError(outputPath)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(systemCmd)
+
+	f := systemCmd.Flags()
+	f.StringP("output", "o", "solsec-system.html", "Output file path; the format follows the extension (json | ndjson | html | sarif | pdf | csv; .xml is JUnit), or - for HTML on stdout")
+	f.Bool("no-slither", false, "Skip Slither, run only custom Go checks")
+	f.String("chain", "mainnet", "Chain the component addresses are deployed on: a chain name or ID")
+	f.String("solc", "", "Pin a specific solc version e.g. --solc 0.8.24")
+	f.Bool("no-cluster", false, "List every finding separately instead of grouping those that share a root cause")
+	f.BoolP("verbose", "v", false, "Include finding evidence in HTML and SARIF output")
+	f.Int("page-size", reporter.DefaultPageSize, "Paginate the HTML findings table above this many findings (0 disables paging)")
+	f.Bool("deterministic", false, "Omit the generation time (or take it from SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports")
+	f.String("repo-root", "", "Repository root that SARIF, GitLab and Code Climate file paths are relative to (default: the enclosing git repository)")
+	f.String("template", "", "Custom HTML report template: a complete template, or {{define}}s of its head, logo, disclaimer and footer blocks")
+}
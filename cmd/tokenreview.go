@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/config"
+	"github.com/Zubimendi/solsec/internal/explorer"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/Zubimendi/solsec/internal/source"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// tokenReviewChecks are the custom checks relevant to integrating a token:
+// scam patterns and unguarded mint/burn/pause functions.
+var tokenReviewChecks = []string{"token-diligence", "access-control"}
+
+// tokenReviewDetectors are the Slither detectors for ERC-20 conformance and
+// token-handling hazards.
+var tokenReviewDetectors = []string{"erc20-interface", "erc20-indexed", "locked-ether", "incorrect-equality"}
+
+var tokenReviewCmd = &cobra.Command{
+	Use:   "token-review <path|address>",
+	Short: "Decide whether a third-party ERC-20 token is safe to integrate",
+	Long: `Review a token you do not control before integrating it. Unlike analyze,
+which audits your own code for deployment, token-review looks for what a
+token's owner can do to its holders: honeypot sell restrictions, uncapped
+fees, blacklists, balance rewrites and unguarded minting. Slither's ERC-20
+conformance detectors run too, unless --no-slither is given.
+
+The report is graded on an integration scale, and its verdict says whether
+to integrate the token. A single Critical finding advises against it.
+
+The target is the token's verified source, or its deployed address on
+--chain. The source of an address is fetched from the Etherscan API with
+the key in ETHERSCAN_API_KEY and kept in the user cache directory. When the
+explorer identifies the token as a proxy, its implementation is fetched and
+reviewed too.
+
+Examples:
+  solsec token-review 0x6b175474e89094c44da98b954eedeac495271d0f
+  solsec token-review 0x833589fcd6edb6e08f4c7c32d4f71b54bda02913 --chain base
+  solsec token-review ./vendor/token/Token.sol -o token-review.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputPath, _ := cmd.Flags().GetString("output")
+		streamToStdout(outputPath)
+		format := strings.TrimPrefix(filepath.Ext(outputPath), ".")
+
+		fileCfg, err := config.Load(viper.GetViper())
+		if err != nil {
+			return err
+		}
+		p, err := lookupProfile("standard")
+		if err != nil {
+			return err
+		}
+
+		targets := []string{args[0]}
+		if explorer.IsAddress(args[0]) {
+			chain, _ := cmd.Flags().GetString("chain")
+			contracts, err := fetchSource(cmd.Context(), chain, args[0])
+			if err != nil {
+				return err
+			}
+			targets = targets[:0]
+			for _, c := range contracts {
+				targets = append(targets, c.Dir)
+			}
+		}
+
+		var reports []*parser.AnalysisReport
+		for _, target := range targets {
+			cfg := analysisConfig{
+				Target:            target,
+				Only:              tokenReviewDetectors,
+				Checks:            tokenReviewChecks,
+				SlitherTimeout:    p.SlitherTimeout,
+				Exclude:           fileCfg.Exclude,
+				ExcludePaths:      fileCfg.ExcludePaths,
+				Pipeline:          fileCfg.Pipeline,
+				SeverityOverrides: fileCfg.SeverityOverrides,
+				PathSeverity:      pathSeverity(fileCfg.PathSeverity),
+				Limits:            source.DefaultLimits,
+			}
+			cfg.NoSlither, _ = cmd.Flags().GetBool("no-slither")
+			cfg.SolcVersion, _ = cmd.Flags().GetString("solc")
+			report, err := analyzeTarget(cmd.Context(), cfg)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, report)
+			if report.Interrupted {
+				break
+			}
+		}
+		report := reports[0]
+		if len(reports) > 1 {
+			// A proxy and its implementation are reviewed as one token.
+			report = analyzer.Merge(args[0], reports...)
+			if err := analyzer.Process(report, analyzer.Options{Pipeline: pipeline(fileCfg.Pipeline, false)}); err != nil {
+				return err
+			}
+		}
+
+		scale := scorer.TokenReviewScale
+		scale.NoEmoji = fileCfg.Grading.Emoji != nil && !*fileCfg.Grading.Emoji
+		scorer.SetScale(scale)
+
+		score := scorer.Score(report)
+		if err := writeReport(report, score, format, outputPath, reportOptionsFromFlags(cmd)); err != nil {
+			return err
+		}
+		printSummary(report, score, scorer.Grade(score), scorer.Verdict(score), outputPath)
+		if report.Interrupted {
+			return interruptedError(outputPath)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tokenReviewCmd)
+
+	f := tokenReviewCmd.Flags()
+	f.StringP("output", "o", "solsec-token-review.html", "Output file path; the format follows the extension (json | ndjson | html | sarif | pdf | csv; .xml is JUnit), or - for HTML on stdout")
+	f.Bool("no-slither", false, "Skip Slither's ERC-20 conformance detectors, run only the custom checks")
+	f.String("chain", "mainnet", "Chain the token address is deployed on: a chain name or ID")
+	f.String("solc", "", "Pin a specific solc version e.g. --solc 0.8.24")
+	f.BoolP("verbose", "v", false, "Include finding evidence in HTML and SARIF output")
+	f.Int("page-size", reporter.DefaultPageSize, "Paginate the HTML findings table above this many findings (0 disables paging)")
+	f.Bool("deterministic", false, "Omit the generation time (or take it from SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports")
+	f.String("repo-root", "", "Repository root that SARIF, GitLab and Code Climate file paths are relative to (default: the enclosing git repository)")
+	f.String("template", "", "Custom HTML report template: a complete template, or {{define}}s of its head, logo, disclaimer and footer blocks")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Zubimendi/solsec/internal/explain"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/triage"
+	"github.com/spf13/cobra"
+)
+
+var triageCmd = &cobra.Command{
+	Use:   "triage <fingerprint|id>...",
+	Short: "Hide reviewed findings by recording them in Slither's triage database",
+	Long: `Record findings from a JSON report in slither.db.json, Slither's own triage
+database. Later analyses leave them out of the report, and so does raw
+Slither for Slither findings, since they are recorded under Slither's result
+IDs. Findings triaged with "slither --triage-mode" are honoured by solsec
+the same way.
+
+Findings are selected by fingerprint (any unique prefix of at least 4
+characters) or report ID.
+
+Examples:
+  solsec triage 3f9a1c2e SLITHER-004 --report report.json
+  solsec triage --remove 3f9a1c2e --report report.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportPath, _ := cmd.Flags().GetString("report")
+		dbPath, _ := cmd.Flags().GetString("database")
+		remove, _ := cmd.Flags().GetBool("remove")
+
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		db, err := triage.Load(dbPath)
+		if err != nil {
+			return err
+		}
+
+		changed := 0
+		for _, ref := range args {
+			f, err := explain.Find(report, ref)
+			if err != nil {
+				return err
+			}
+			if remove {
+				if !db.Remove(*f) {
+					fmt.Printf("%s (%s) is not triaged\n", f.ID, f.Check)
+					continue
+				}
+				fmt.Printf("Un-triaged %s (%s)\n", f.ID, f.Check)
+				changed++
+				continue
+			}
+			added, err := db.Add(*f)
+			if err != nil {
+				return err
+			}
+			if !added {
+				fmt.Printf("%s (%s) is already triaged\n", f.ID, f.Check)
+				continue
+			}
+			fmt.Printf("Triaged %s (%s)\n", f.ID, f.Check)
+			changed++
+		}
+		if changed == 0 {
+			return nil
+		}
+		if err := db.Write(dbPath); err != nil {
+			return err
+		}
+		fmt.Printf("📝 %s now holds %d triaged result(s)\n", dbPath, db.Len())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(triageCmd)
+	f := triageCmd.Flags()
+	f.String("report", "solsec-report.json", "JSON report containing the findings")
+	f.String("database", triage.DefaultPath, "Triage database to update")
+	f.Bool("remove", false, "Un-triage the findings instead, so they are reported again")
+}
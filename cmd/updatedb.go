@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+var updateDBCmd = &cobra.Command{
+	Use:   "update-db",
+	Short: "Update the rules/remediation database without upgrading solsec",
+	Long: `Download the latest rules bundle (remediation guidance, SWC/CWE mappings,
+and custom check signatures) into the local cache.
+
+If the download fails, solsec keeps using the cached or embedded copy, so
+analysis never depends on network access.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url, _ := cmd.Flags().GetString("url")
+
+		before := rules.Default()
+		fmt.Printf("📦 Current rules database: v%d (%s)\n", before.Version, before.Origin)
+
+		db, err := rules.Update(cmd.Context(), url)
+		if err != nil {
+			return fmt.Errorf("updating rules database (still using v%d): %w", before.Version, err)
+		}
+		if db.Version == before.Version {
+			fmt.Println("   ✅ Already up to date")
+			return nil
+		}
+		fmt.Printf("   ✅ Updated to v%d (%s)\n", db.Version, db.Origin)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateDBCmd)
+	updateDBCmd.Flags().String("url", rules.DefaultUpdateURL, "URL of the rules bundle to download")
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/explorer"
+	"github.com/Zubimendi/solsec/internal/offline"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/poc"
+	"github.com/Zubimendi/solsec/internal/reporter"
+	"github.com/Zubimendi/solsec/internal/runner"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/spf13/cobra"
+)
+
+// validateParent is the Foundry test directory that holds the directory of
+// generated fork tests.
+const validateParent = "test"
+
+var validateCmd = &cobra.Command{
+	Use:   "validate --rpc <url>",
+	Short: "Confirm findings against deployed contracts with Foundry fork tests",
+	Long: `Validate findings in a JSON report dynamically. For each finding whose
+exploit is a single call (currently access-control findings), validate
+generates a Foundry test that forks the chain, calls the flagged function on
+the deployed contract from an arbitrary account and checks it succeeds.
+Findings whose test passes are marked confirmed; those whose call reverts are
+marked not reproducible. Other findings are left as they are.
+
+Run it from the root of a Foundry project with forge-std installed. Deployed
+addresses come from the report of "solsec system" or from --address. The RPC
+URL is passed to forge in the environment, never written to the tests.
+
+Examples:
+  solsec validate --rpc $ETH_RPC_URL --address Token=0x6b175474e89094c44da98b954eedeac495271d0f
+  solsec validate --rpc $ETH_RPC_URL --report system.json --block 19000000`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rpc, _ := cmd.Flags().GetString("rpc")
+		reportPath, _ := cmd.Flags().GetString("report")
+		outputPath, _ := cmd.Flags().GetString("output")
+		specs, _ := cmd.Flags().GetStringSlice("address")
+		block, _ := cmd.Flags().GetUint64("block")
+		keep, _ := cmd.Flags().GetBool("keep")
+		if outputPath == "" {
+			outputPath = reportPath
+		}
+		streamToStdout(outputPath)
+		format := strings.TrimPrefix(filepath.Ext(outputPath), ".")
+
+		report, err := parser.LoadReport(reportPath)
+		if err != nil {
+			return err
+		}
+		addresses, err := deployedAddresses(report, specs)
+		if err != nil {
+			return err
+		}
+		if err := offline.Guard("forking the chain over RPC"); err != nil {
+			return err
+		}
+		forge, err := runner.DetectForge()
+		if err != nil {
+			return err
+		}
+		validateDir, cleanup, err := makeValidateDir()
+		if err != nil {
+			return err
+		}
+
+		// Generate a test per applicable finding, remembering which is which.
+		tests := map[string]*parser.Finding{}
+		skipped := 0
+		for i := range report.Findings {
+			f := &report.Findings[i]
+			address, ok := addresses[f.Contract]
+			if !poc.ForkSupported(f) || !ok {
+				skipped++
+				continue
+			}
+			path := filepath.Join(validateDir, fmt.Sprintf("%s_%s.t.sol", f.Contract, f.Function))
+			code, err := poc.GenerateFork(f, path, address, block)
+			if err != nil {
+				fmt.Printf("  ⏭  %s %s: %v\n", f.ID, f.Title, err)
+				skipped++
+				continue
+			}
+			if err := os.WriteFile(path, code, 0640); err != nil {
+				return fmt.Errorf("writing fork test: %w", err)
+			}
+			tests[filepath.ToSlash(path)] = f
+		}
+		if len(tests) == 0 {
+			cleanup()
+			return fmt.Errorf("no finding could be validated: fork tests cover %s findings on contracts with a known address (see --address)", strings.Join(forkChecks(), ", "))
+		}
+		if !keep {
+			defer cleanup()
+		}
+
+		fmt.Printf("🍴 Running %d fork test(s) with forge...\n", len(tests))
+		results, err := runner.RunForgeTest(cmd.Context(), forge, runner.ForgeTestOptions{
+			Root:      ".",
+			MatchPath: filepath.ToSlash(validateDir) + "/*.t.sol",
+			Env:       []string{poc.ForkRPCEnv + "=" + rpc},
+		})
+		if err != nil {
+			return err
+		}
+
+		confirmed, notReproducible := 0, 0
+		for path, f := range tests {
+			passed, ran := testPassed(results, path)
+			switch {
+			case !ran:
+				fmt.Printf("  ⏭  %s %s: test did not run\n", f.ID, f.Title)
+				skipped++
+			case passed:
+				f.Validation = parser.ValidationConfirmed
+				confirmed++
+				fmt.Printf("  ✅ %s %s: confirmed on fork\n", f.ID, f.Title)
+			default:
+				f.Validation = parser.ValidationNotReproducible
+				notReproducible++
+				fmt.Printf("  ❌ %s %s: not reproducible on fork\n", f.ID, f.Title)
+			}
+		}
+
+		score := scorer.Score(report)
+		if err := writeReport(report, score, format, outputPath, reportOptionsFromFlags(cmd)); err != nil {
+			return err
+		}
+		fmt.Printf("\n🔬 %d confirmed, %d not reproducible, %d skipped — report written to %s\n", confirmed, notReproducible, skipped, displayPath(outputPath))
+		if keep {
+			fmt.Printf("   Fork tests kept in %s\n", validateDir)
+		}
+		return nil
+	},
+}
+
+// makeValidateDir creates a new directory for the fork tests under
+// validateParent, so files the project already has are never overwritten or
+// deleted. cleanup removes it, and validateParent too when it was created
+// for it and is left empty.
+func makeValidateDir() (dir string, cleanup func(), err error) {
+	_, statErr := os.Stat(validateParent)
+	created := errors.Is(statErr, fs.ErrNotExist)
+	if err := os.MkdirAll(validateParent, 0750); err != nil {
+		return "", nil, fmt.Errorf("creating %s: %w", validateParent, err)
+	}
+	dir, err = os.MkdirTemp(validateParent, "solsec-validate-")
+	if err != nil {
+		return "", nil, fmt.Errorf("creating fork test directory: %w", err)
+	}
+	return dir, func() {
+		_ = os.RemoveAll(dir)
+		if created {
+			_ = os.Remove(validateParent)
+		}
+	}, nil
+}
+
+// deployedAddresses maps contract names to deployed addresses, from the
+// report's system components and then Contract=0x... flags.
+func deployedAddresses(report *parser.AnalysisReport, specs []string) (map[string]string, error) {
+	addresses := map[string]string{}
+	if report.System != nil {
+		for _, c := range report.System.Components {
+			if c.Address != "" {
+				addresses[c.Name] = c.Address
+			}
+		}
+	}
+	for _, spec := range specs {
+		name, address, ok := strings.Cut(spec, "=")
+		if !ok || name == "" || !explorer.IsAddress(address) {
+			return nil, fmt.Errorf("invalid --address %q: want Contract=0x followed by 40 hex digits", spec)
+		}
+		addresses[name] = address
+	}
+	return addresses, nil
+}
+
+// testPassed looks up the result of the single test in the file at path.
+func testPassed(results map[string]bool, path string) (passed, ran bool) {
+	for name, ok := range results {
+		if strings.HasPrefix(name, path+":") {
+			return ok, true
+		}
+	}
+	return false, false
+}
+
+func forkChecks() []string {
+	var checks []string
+	for _, c := range poc.Supported() {
+		if poc.ForkSupported(&parser.Finding{Check: c}) {
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	f := validateCmd.Flags()
+	f.String("rpc", "", "RPC URL of the chain to fork (required)")
+	f.String("report", "solsec-report.json", "JSON report whose findings to validate")
+	f.StringP("output", "o", "", "Output file path; the format follows the extension, or - for HTML on stdout (default: update --report in place)")
+	f.StringSlice("address", nil, "Deployed address of a contract, as Contract=0x... (repeatable)")
+	f.Uint64("block", 0, "Fork at this block number instead of the latest")
+	f.Bool("keep", false, "Keep the generated fork tests (in a new test/solsec-validate-* directory)")
+	f.BoolP("verbose", "v", false, "Include finding evidence in HTML and SARIF output")
+	f.Int("page-size", reporter.DefaultPageSize, "Paginate the HTML findings table above this many findings (0 disables paging)")
+	f.Bool("deterministic", false, "Omit the generation time (or take it from SOURCE_DATE_EPOCH) so identical inputs give byte-identical reports")
+	f.String("repo-root", "", "Repository root that SARIF, GitLab and Code Climate file paths are relative to (default: the enclosing git repository)")
+	f.String("template", "", "Custom HTML report template: a complete template, or {{define}}s of its head, logo, disclaimer and footer blocks")
+	_ = validateCmd.MarkFlagRequired("rpc")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/Zubimendi/solsec/internal/runner"
 )
 
 var versionCmd = &cobra.Command{
@@ -10,6 +11,9 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version of solsec",
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Printf("%s v%s\n", appName, appVersion)
+		if v := runner.EmbeddedSlitherVersion(); v != "" {
+			fmt.Printf("embedded slither-analyzer %s\n", v)
+		}
 	},
 }
 
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/analyzer"
+	"github.com/Zubimendi/solsec/internal/baseline"
+	"github.com/Zubimendi/solsec/internal/config"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// runWorkspace analyzes every configured workspace package with its own
+// settings, writes a sub-report per package, and an aggregated report at
+// outputPath. Each package may bring its own baseline, and is gated by its
+// own fail_on, max_* limits and policy rules on top of the run's.
+func runWorkspace(cmd *cobra.Command, format, outputPath string, failPol failPolicy, reportOpts reportOptions, mode outputMode) error {
+	cfgFile, err := config.Load(viper.GetViper())
+	if err != nil {
+		return err
+	}
+	if err := cfgFile.Workspace.Validate(); err != nil {
+		return err
+	}
+
+	defaultProfile, _ := cmd.Flags().GetString("profile")
+
+	// Compile every package's policy first, so a bad rule fails at once
+	// rather than after the packages before it were analyzed.
+	policies := make([]failPolicy, len(cfgFile.Workspace.Packages))
+	for i, pkg := range cfgFile.Workspace.Packages {
+		if policies[i], err = failPol.forPackage(cmd, pkg); err != nil {
+			return fmt.Errorf("workspace package %q: %w", pkg.Name, err)
+		}
+	}
+
+	var (
+		reports  []*parser.AnalysisReport
+		failures []string
+	)
+	for i, pkg := range cfgFile.Workspace.Packages {
+		profileName := defaultProfile
+		if pkg.Profile != "" && !cmd.Flags().Changed("profile") {
+			profileName = pkg.Profile
+		}
+		cfg, err := analysisConfigFromFlags(cmd, cfgFile.Resolve(pkg.Path), profileName)
+		if err != nil {
+			return fmt.Errorf("workspace package %q: %w", pkg.Name, err)
+		}
+		cfg.Quiet = mode.silent()
+		if pkg.Solc != "" && !cmd.Flags().Changed("solc") {
+			cfg.SolcVersion = pkg.Solc
+		}
+		cfg.Framework = pkg.Framework
+		if pkg.Baseline != "" && !cmd.Flags().Changed("baseline") {
+			if cfg.Baseline, err = baseline.Load(cfgFile.Resolve(pkg.Baseline)); err != nil {
+				return fmt.Errorf("workspace package %q: %w", pkg.Name, err)
+			}
+		}
+
+		cfg.logf("\n📦 Package: %s\n", pkg.Name)
+		report, err := analyzeTarget(cmd.Context(), cfg)
+		if err != nil {
+			return fmt.Errorf("workspace package %q: %w", pkg.Name, err)
+		}
+		reports = append(reports, report)
+
+		score := scorer.Score(report)
+		subPath := packageReportPath(outputPath, pkg.Name)
+		if err := writeReport(report, score, format, subPath, reportOpts); err != nil {
+			return fmt.Errorf("workspace package %q: %w", pkg.Name, err)
+		}
+		cfg.logf("   Grade %s (%d/100), %d finding(s) → %s\n",
+			scorer.Grade(score), score, report.Summary.Total, subPath)
+
+		for _, failure := range policies[i].failures(report, score) {
+			failures = append(failures, fmt.Sprintf("%s: %s", pkg.Name, failure))
+		}
+		if report.Interrupted {
+			break
+		}
+	}
+
+	combined := analyzer.Merge("workspace", reports...)
+	noCluster, _ := cmd.Flags().GetBool("no-cluster")
+	if err := analyzer.Process(combined, analyzer.Options{Pipeline: pipeline(cfgFile.Pipeline, noCluster)}); err != nil {
+		return err
+	}
+	score := scorer.Score(combined)
+	if err := attachHistory(cmd, combined, score); err != nil {
+		return err
+	}
+	if err := writeReport(combined, score, format, outputPath, reportOpts); err != nil {
+		return err
+	}
+	if combined.Interrupted {
+		return interruptedError(outputPath)
+	}
+	if !mode.silent() {
+		printSummary(combined, score, scorer.Grade(score), scorer.Verdict(score), outputPath)
+	}
+	if suggest, _ := cmd.Flags().GetBool("suggest-config"); suggest {
+		printNoiseBudget(mode.textOut(), ".", combined)
+	}
+	mode.annotate(combined.Findings)
+
+	if mode.StdoutSummary {
+		if err := writeRunSummary(os.Stdout, combined, score, outputPath, failPol, failures); err != nil {
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		if mode.failText() {
+			fmt.Printf("FAIL:\n  %s\n", strings.Join(failures, "\n  "))
+		}
+		os.Exit(1)
+	}
+	return nil
+}
+
+// packageReportPath derives a package sub-report path from the aggregated
+// report path: solsec-report.html → solsec-report-core.html.
+func packageReportPath(outputPath, pkg string) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + "-" + pkg + ext
+}
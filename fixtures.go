@@ -0,0 +1,10 @@
+package main
+
+import "embed"
+
+// fixtures are the custom check fixtures and the benchmark corpus, bundled
+// into the binary so `solsec selftest` and `solsec benchmark` work without a
+// source checkout.
+//
+//go:embed all:testdata/contracts testdata/benchmark
+var fixtures embed.FS
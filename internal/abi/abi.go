@@ -0,0 +1,159 @@
+// Package abi derives function selectors, event topics and custom error
+// selectors from the ABI of compiled contracts.
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Entry kinds.
+const (
+	KindFunction = "function"
+	KindEvent    = "event"
+	KindError    = "error"
+)
+
+// Entry is one function, event or custom error of a contract.
+type Entry struct {
+	Contract  string `json:"contract"`
+	File      string `json:"file"`
+	Kind      string `json:"kind"`
+	Signature string `json:"signature"` // canonical, e.g. "transfer(address,uint256)"
+
+	// Selector is the 4-byte selector of functions and errors, or the full
+	// 32-byte topic0 of events, as 0x-prefixed hex.
+	Selector string `json:"selector"`
+
+	// Mutability and Outputs describe functions: "pure", "view",
+	// "nonpayable" or "payable", and the return types, e.g. "(uint256)".
+	Mutability string `json:"mutability,omitempty"`
+	Outputs    string `json:"outputs,omitempty"`
+
+	// Indexed lists the positions of an event's indexed parameters.
+	Indexed []int `json:"indexed,omitempty"`
+}
+
+// item is an ABI JSON element.
+type item struct {
+	Type            string  `json:"type"`
+	Name            string  `json:"name"`
+	Inputs          []param `json:"inputs"`
+	Outputs         []param `json:"outputs"`
+	StateMutability string  `json:"stateMutability"`
+	Anonymous       bool    `json:"anonymous"`
+}
+
+type param struct {
+	Type       string  `json:"type"`
+	Indexed    bool    `json:"indexed"`
+	Components []param `json:"components"`
+}
+
+// FromCombinedJSON reads the output of `solc --combined-json abi` and
+// returns the entries of every contract, sorted by file, contract, kind and
+// signature. Anonymous events have no topic0 and are left out.
+func FromCombinedJSON(data []byte) ([]Entry, error) {
+	var out struct {
+		Contracts map[string]struct {
+			ABI json.RawMessage `json:"abi"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing solc output: %w", err)
+	}
+
+	var entries []Entry
+	for key, c := range out.Contracts {
+		file, contract := key, key
+		if i := strings.LastIndex(key, ":"); i >= 0 {
+			file, contract = key[:i], key[i+1:]
+		}
+		items, err := parseABI(c.ABI)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		for _, it := range items {
+			e := Entry{Contract: contract, File: file, Kind: it.Type, Signature: signature(it)}
+			switch {
+			case it.Type == KindFunction:
+				e.Selector = Selector(e.Signature)
+				e.Mutability = it.StateMutability
+				e.Outputs = "(" + canonicalList(it.Outputs) + ")"
+			case it.Type == KindError:
+				e.Selector = Selector(e.Signature)
+			case it.Type == KindEvent && !it.Anonymous:
+				hash := Keccak256([]byte(e.Signature))
+				e.Selector = "0x" + hex.EncodeToString(hash[:])
+				for i, p := range it.Inputs {
+					if p.Indexed {
+						e.Indexed = append(e.Indexed, i)
+					}
+				}
+			default:
+				continue // constructor, fallback, receive or anonymous event
+			}
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Contract != b.Contract {
+			return a.Contract < b.Contract
+		}
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind // functions, then events, then errors
+		}
+		return a.Signature < b.Signature
+	})
+	return entries, nil
+}
+
+// Selector returns the 4-byte selector of a canonical function or error
+// signature such as "transfer(address,uint256)", as 0x-prefixed hex.
+func Selector(signature string) string {
+	hash := Keccak256([]byte(signature))
+	return "0x" + hex.EncodeToString(hash[:4])
+}
+
+// parseABI accepts the ABI as a JSON array, or as a string holding one as
+// solc releases before 0.8.10 emit it.
+func parseABI(raw json.RawMessage) ([]item, error) {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		raw = json.RawMessage(s)
+	}
+	var items []item
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("parsing ABI: %w", err)
+	}
+	return items, nil
+}
+
+// signature is the canonical signature the selector is hashed from: the
+// name and input types, with structs expanded to tuples.
+func signature(it item) string {
+	return it.Name + "(" + canonicalList(it.Inputs) + ")"
+}
+
+func canonicalList(params []param) string {
+	types := make([]string, len(params))
+	for i, p := range params {
+		types[i] = canonical(p)
+	}
+	return strings.Join(types, ",")
+}
+
+func canonical(p param) string {
+	if !strings.HasPrefix(p.Type, "tuple") {
+		return p.Type
+	}
+	// Keep array suffixes: tuple[] → (uint256,address)[].
+	return "(" + canonicalList(p.Components) + ")" + strings.TrimPrefix(p.Type, "tuple")
+}
@@ -0,0 +1,46 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromCombinedJSON(t *testing.T) {
+	out := `{"contracts": {
+  "src/Token.sol:Token": {"abi": [
+    {"type": "constructor", "inputs": [{"type": "uint256"}]},
+    {"type": "function", "name": "transfer", "inputs": [{"type": "address"}, {"type": "uint256"}], "outputs": [{"type": "bool"}], "stateMutability": "nonpayable"},
+    {"type": "event", "name": "Transfer", "inputs": [{"type": "address", "indexed": true}, {"type": "address", "indexed": true}, {"type": "uint256"}]},
+    {"type": "event", "name": "Hidden", "anonymous": true, "inputs": []},
+    {"type": "error", "name": "InsufficientBalance", "inputs": [{"type": "uint256"}, {"type": "uint256"}]},
+    {"type": "function", "name": "batch", "inputs": [{"type": "tuple[]", "components": [{"type": "address"}, {"type": "uint256"}]}], "outputs": [], "stateMutability": "payable"}
+  ]},
+  "src/Token.sol:IToken": {"abi": "[{\"type\":\"function\",\"name\":\"totalSupply\",\"inputs\":[],\"outputs\":[{\"type\":\"uint256\"}],\"stateMutability\":\"view\"}]"}
+}}`
+	entries, err := FromCombinedJSON([]byte(out))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Entry{
+		{Contract: "IToken", File: "src/Token.sol", Kind: KindFunction, Signature: "totalSupply()", Selector: "0x18160ddd",
+			Mutability: "view", Outputs: "(uint256)"},
+		{Contract: "Token", File: "src/Token.sol", Kind: KindFunction, Signature: "batch((address,uint256)[])", Selector: "0xf4af1f8e",
+			Mutability: "payable", Outputs: "()"},
+		{Contract: "Token", File: "src/Token.sol", Kind: KindFunction, Signature: "transfer(address,uint256)", Selector: "0xa9059cbb",
+			Mutability: "nonpayable", Outputs: "(bool)"},
+		{Contract: "Token", File: "src/Token.sol", Kind: KindEvent, Signature: "Transfer(address,address,uint256)",
+			Selector: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", Indexed: []int{0, 1}},
+		{Contract: "Token", File: "src/Token.sol", Kind: KindError, Signature: "InsufficientBalance(uint256,uint256)", Selector: "0xcf479181"},
+	}, entries)
+
+	_, err = FromCombinedJSON([]byte("Error: Source file requires different compiler version"))
+	assert.ErrorContains(t, err, "parsing solc output")
+}
+
+func TestSelector(t *testing.T) {
+	assert.Equal(t, "0xa9059cbb", Selector("transfer(address,uint256)"))
+	// The classic clash: an innocuous-looking function with the selector
+	// of burn(uint256).
+	assert.Equal(t, Selector("burn(uint256)"), Selector("collate_propagate_storage(bytes16)"))
+}
@@ -0,0 +1,133 @@
+package abi
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Surface change kinds.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is one difference in a contract's external surface.
+type Change struct {
+	Contract  string `json:"contract"`
+	Kind      string `json:"kind"` // KindFunction or KindEvent
+	Change    string `json:"change"`
+	Signature string `json:"signature"` // the new signature, or the old one when removed
+	Detail    string `json:"detail,omitempty"`
+}
+
+// ID names the change's function or event as "Contract.signature".
+func (c Change) ID() string { return c.Contract + "." + c.Signature }
+
+// Diff compares the functions and events of two versions of the compiled
+// contracts, matched by contract name. An entry whose parameters changed
+// while its name stayed unique in the contract is reported as changed
+// rather than as a removal and an addition.
+func Diff(old, upgraded []Entry) []Change {
+	type key struct{ contract, kind, signature string }
+	index := func(entries []Entry) map[key]Entry {
+		m := map[key]Entry{}
+		for _, e := range entries {
+			if e.Kind == KindFunction || e.Kind == KindEvent {
+				m[key{e.Contract, e.Kind, e.Signature}] = e
+			}
+		}
+		return m
+	}
+	oldIdx, newIdx := index(old), index(upgraded)
+
+	var changes []Change
+	removed := map[key]Entry{}
+	for k, o := range oldIdx {
+		n, ok := newIdx[k]
+		if !ok {
+			removed[k] = o
+			continue
+		}
+		if detail := attributeChanges(o, n); detail != "" {
+			changes = append(changes, Change{Contract: k.contract, Kind: k.kind, Change: Changed, Signature: k.signature, Detail: detail})
+		}
+	}
+	added := map[key]Entry{}
+	for k, n := range newIdx {
+		if _, ok := oldIdx[k]; !ok {
+			added[k] = n
+		}
+	}
+
+	// Pair the removal and addition of an entry whose name is not overloaded
+	// in either version.
+	named := func(m map[key]Entry, k key) []key {
+		var ks []key
+		for other := range m {
+			if other.contract == k.contract && other.kind == k.kind && name(other.signature) == name(k.signature) {
+				ks = append(ks, other)
+			}
+		}
+		return ks
+	}
+	for k, o := range removed {
+		news := named(added, k)
+		if len(named(oldIdx, k)) != 1 || len(named(newIdx, k)) != 1 || len(news) != 1 {
+			continue
+		}
+		n := added[news[0]]
+		detail := fmt.Sprintf("parameters %s → %s", params(o.Signature), params(n.Signature))
+		if more := attributeChanges(o, n); more != "" {
+			detail += "; " + more
+		}
+		changes = append(changes, Change{Contract: k.contract, Kind: k.kind, Change: Changed, Signature: n.Signature, Detail: detail})
+		delete(removed, k)
+		delete(added, news[0])
+	}
+	for k := range removed {
+		changes = append(changes, Change{Contract: k.contract, Kind: k.kind, Change: Removed, Signature: k.signature})
+	}
+	for k, n := range added {
+		changes = append(changes, Change{Contract: k.contract, Kind: k.kind, Change: Added, Signature: k.signature, Detail: n.Mutability})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Contract != b.Contract {
+			return a.Contract < b.Contract
+		}
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind // functions before events
+		}
+		return a.Signature < b.Signature
+	})
+	return changes
+}
+
+// attributeChanges describes what changed between two versions of an entry
+// besides its parameters, or returns "".
+func attributeChanges(o, n Entry) string {
+	var diffs []string
+	if o.Mutability != n.Mutability {
+		diffs = append(diffs, fmt.Sprintf("mutability %s → %s", o.Mutability, n.Mutability))
+	}
+	if o.Outputs != n.Outputs {
+		diffs = append(diffs, fmt.Sprintf("returns %s → %s", o.Outputs, n.Outputs))
+	}
+	if !slices.Equal(o.Indexed, n.Indexed) {
+		diffs = append(diffs, fmt.Sprintf("indexed parameters %v → %v", o.Indexed, n.Indexed))
+	}
+	return strings.Join(diffs, "; ")
+}
+
+func name(signature string) string {
+	n, _, _ := strings.Cut(signature, "(")
+	return n
+}
+
+func params(signature string) string {
+	return signature[len(name(signature)):]
+}
@@ -0,0 +1,40 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	fn := func(sig, mutability string) Entry {
+		return Entry{Contract: "Vault", Kind: KindFunction, Signature: sig, Mutability: mutability, Outputs: "()"}
+	}
+	old := []Entry{
+		fn("deposit()", "payable"),
+		fn("withdraw(uint256)", "nonpayable"),
+		fn("sweep(address)", "nonpayable"),
+		fn("swap(uint256)", "nonpayable"),
+		fn("swap(uint256,address)", "nonpayable"),
+		{Contract: "Vault", Kind: KindEvent, Signature: "Deposit(address,uint256)", Indexed: []int{0}},
+		{Contract: "Vault", Kind: KindError, Signature: "Paused()"},
+	}
+	upgraded := []Entry{
+		fn("deposit()", "nonpayable"),
+		fn("withdraw(uint256,address)", "nonpayable"),
+		fn("swap(uint256)", "nonpayable"),
+		fn("swap(uint256,address,uint256)", "nonpayable"),
+		fn("mint(address,uint256)", "nonpayable"),
+		{Contract: "Vault", Kind: KindEvent, Signature: "Deposit(address,uint256)", Indexed: []int{0, 1}},
+	}
+
+	assert.Equal(t, []Change{
+		{Contract: "Vault", Kind: KindFunction, Change: Changed, Signature: "deposit()", Detail: "mutability payable → nonpayable"},
+		{Contract: "Vault", Kind: KindFunction, Change: Added, Signature: "mint(address,uint256)", Detail: "nonpayable"},
+		{Contract: "Vault", Kind: KindFunction, Change: Removed, Signature: "swap(uint256,address)"},
+		{Contract: "Vault", Kind: KindFunction, Change: Added, Signature: "swap(uint256,address,uint256)", Detail: "nonpayable"},
+		{Contract: "Vault", Kind: KindFunction, Change: Removed, Signature: "sweep(address)"},
+		{Contract: "Vault", Kind: KindFunction, Change: Changed, Signature: "withdraw(uint256,address)", Detail: "parameters (uint256) → (uint256,address)"},
+		{Contract: "Vault", Kind: KindEvent, Change: Changed, Signature: "Deposit(address,uint256)", Detail: "indexed parameters [0] → [0 1]"},
+	}, Diff(old, upgraded), "overloaded swap is not paired; errors are ignored")
+}
@@ -0,0 +1,21 @@
+package abi
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeccak256(t *testing.T) {
+	hash := func(s string) string {
+		h := Keccak256([]byte(s))
+		return hex.EncodeToString(h[:])
+	}
+	assert.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hash(""))
+	assert.Equal(t, "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", hash("Transfer(address,address,uint256)"))
+	assert.Equal(t, "a9059cbb", hash("transfer(address,uint256)")[:8])
+	// Inputs longer than one block (136 bytes) take several permutations.
+	assert.Len(t, hash(strings.Repeat("a", 300)), 64)
+}
@@ -0,0 +1,339 @@
+// Package admins checks who holds the admin rights of deployed contracts.
+// Owners and proxy admins are read over JSON-RPC and classified as plain
+// accounts (EOAs), Safe multisigs, timelocks or other contracts, and
+// high-value functions an EOA or a single-signer Safe controls are
+// reported as findings.
+package admins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/impact"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+)
+
+// Selectors of the views queried.
+const (
+	ownerSelector     = "0x8da5cb5b" // owner()
+	thresholdSelector = "0xe75235b8" // getThreshold(), Safe
+	ownersSelector    = "0xa0e67e2b" // getOwners(), Safe
+	minDelaySelector  = "0xf27a0c92" // getMinDelay(), OpenZeppelin TimelockController
+)
+
+// adminSlot is the EIP-1967 storage slot of a proxy's admin.
+const adminSlot = "0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"
+
+// delegatedPrefix marks the code of an EIP-7702 account, which is still
+// controlled by its private key.
+const delegatedPrefix = "0xef0100"
+
+// Roles an admin can hold over a contract.
+const (
+	RoleOwner      = "owner()"
+	RoleProxyAdmin = "proxy admin"
+	// RoleProxyAdminOwner is the owner of a ProxyAdmin contract, which
+	// upgrades the proxies it administers.
+	RoleProxyAdminOwner = "proxy admin owner"
+	RoleDeclared        = "declared"
+)
+
+// Kind is what sits at an admin address.
+type Kind string
+
+const (
+	KindEOA      Kind = "EOA"
+	KindSafe     Kind = "Safe"
+	KindTimelock Kind = "timelock"
+	KindContract Kind = "contract"
+)
+
+// Admin is an address holding admin rights over a contract.
+type Admin struct {
+	Role    string
+	Address string
+	Kind    Kind
+
+	// Threshold and Owners describe a Safe; Delay is a timelock's minimum
+	// delay in seconds.
+	Threshold int
+	Owners    int
+	Delay     uint64
+}
+
+// SingleSigner reports whether one key controls the admin: an EOA or a
+// Safe with a threshold of one.
+func (a Admin) SingleSigner() bool {
+	return a.Kind == KindEOA || a.Kind == KindSafe && a.Threshold <= 1
+}
+
+func (a Admin) String() string {
+	switch a.Kind {
+	case KindSafe:
+		return fmt.Sprintf("%s (%d-of-%d Safe)", a.Address, a.Threshold, a.Owners)
+	case KindTimelock:
+		return fmt.Sprintf("%s (timelock, %ds delay)", a.Address, a.Delay)
+	}
+	return fmt.Sprintf("%s (%s)", a.Address, a.Kind)
+}
+
+// Inspector reads the admins of deployed contracts.
+type Inspector struct {
+	Client *impact.Client
+}
+
+// Admins returns the admins of the contract at address: its owner(), its
+// EIP-1967 proxy admin and, when that admin is a ProxyAdmin contract, the
+// admin's owner, followed by the declared addresses. Contracts that are not
+// Ownable or not proxies simply have fewer admins.
+func (in *Inspector) Admins(ctx context.Context, address string, declared []string) ([]Admin, error) {
+	var admins []Admin
+	add := func(role, addr string) error {
+		a, err := in.Classify(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", role, addr, err)
+		}
+		a.Role = role
+		admins = append(admins, a)
+		return nil
+	}
+
+	if owner, ok := in.owner(ctx, address); ok {
+		if err := add(RoleOwner, owner); err != nil {
+			return nil, err
+		}
+	}
+	word, err := in.Client.StorageAt(ctx, address, adminSlot)
+	if err != nil {
+		return nil, fmt.Errorf("reading the proxy admin of %s: %w", address, err)
+	}
+	if proxyAdmin, ok := wordAddress(word); ok {
+		if err := add(RoleProxyAdmin, proxyAdmin); err != nil {
+			return nil, err
+		}
+		if admins[len(admins)-1].Kind == KindContract {
+			if owner, ok := in.owner(ctx, proxyAdmin); ok {
+				if err := add(RoleProxyAdminOwner, owner); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, addr := range declared {
+		if err := add(RoleDeclared, strings.ToLower(addr)); err != nil {
+			return nil, err
+		}
+	}
+	return admins, nil
+}
+
+// owner returns the owner() of the contract at address; contracts without
+// one revert.
+func (in *Inspector) owner(ctx context.Context, address string) (string, bool) {
+	result, err := in.Client.Call(ctx, address, ownerSelector)
+	if err != nil {
+		return "", false
+	}
+	return wordAddress(result)
+}
+
+// Classify tells what sits at address: an account without code (or with an
+// EIP-7702 delegation), a Safe, a timelock or another contract.
+func (in *Inspector) Classify(ctx context.Context, address string) (Admin, error) {
+	a := Admin{Address: address}
+	code, err := in.Client.Code(ctx, address)
+	if err != nil {
+		return a, err
+	}
+	if code == "0x" || code == "" || strings.HasPrefix(code, delegatedPrefix) {
+		a.Kind = KindEOA
+		return a, nil
+	}
+	if threshold, ok := in.uint(ctx, address, thresholdSelector); ok {
+		a.Kind, a.Threshold = KindSafe, int(threshold)
+		if result, err := in.Client.Call(ctx, address, ownersSelector); err == nil {
+			// getOwners() returns an offset word, then the array length.
+			if words := strings.TrimPrefix(result, "0x"); len(words) >= 128 {
+				if n, ok := new(big.Int).SetString(words[64:128], 16); ok && n.IsInt64() {
+					a.Owners = int(n.Int64())
+				}
+			}
+		}
+		return a, nil
+	}
+	if delay, ok := in.uint(ctx, address, minDelaySelector); ok {
+		a.Kind, a.Delay = KindTimelock, delay
+		return a, nil
+	}
+	a.Kind = KindContract
+	return a, nil
+}
+
+// uint calls a view returning a single small unsigned integer; false means
+// the call reverted or returned something else.
+func (in *Inspector) uint(ctx context.Context, address, selector string) (uint64, bool) {
+	result, err := in.Client.Call(ctx, address, selector)
+	digits := strings.TrimPrefix(result, "0x")
+	if err != nil || len(digits) != 64 {
+		return 0, false
+	}
+	n, ok := new(big.Int).SetString(digits, 16)
+	if !ok || !n.IsUint64() {
+		return 0, false
+	}
+	return n.Uint64(), true
+}
+
+// wordAddress decodes the address in a 32-byte word; false for the zero
+// address and for words that do not hold an address.
+func wordAddress(word string) (string, bool) {
+	digits := strings.TrimPrefix(word, "0x")
+	if len(digits) != 64 || strings.Trim(digits[:24], "0") != "" || strings.Trim(digits[24:], "0") == "" {
+		return "", false
+	}
+	return "0x" + strings.ToLower(digits[24:]), true
+}
+
+// highValueRe matches functions that move funds, mint, upgrade, pause or
+// change who is in charge or what the protocol trusts.
+var highValueRe = regexp.MustCompile(`(?i)upgrade|mint|withdraw|sweep|rescue|recover|pause|burn|destruct|kill|migrate|execute|transferOwnership|grantRole|set\w*(fee|owner|admin|oracle|price|implementation|rate|treasury|minter|operator)`)
+
+// Findings reports the admins of contract that a single key controls,
+// listing the guarded functions from the report's access-control matrix
+// that they can call. A single-signer proxy admin can replace the code
+// outright, so it is High; so is a single-signer owner of high-value
+// functions. Other single-signer owners are Medium.
+func Findings(contract string, admins []Admin, matrix []parser.AccessEntry) []parser.Finding {
+	db := rules.Default()
+	var file string
+	var guarded, highValue []parser.AccessEntry
+	for _, e := range matrix {
+		if e.Contract != contract {
+			continue
+		}
+		if file == "" {
+			file = e.File
+		}
+		if len(e.CallableBy) == 0 || e.CallableBy[0] == parser.Anyone {
+			continue
+		}
+		guarded = append(guarded, e)
+		if highValueRe.MatchString(e.Function) {
+			highValue = append(highValue, e)
+		}
+	}
+
+	var findings []parser.Finding
+	for _, a := range admins {
+		if !a.SingleSigner() {
+			continue
+		}
+		severity, powers := parser.SeverityMedium, guarded
+		switch {
+		case a.Role == RoleProxyAdmin || a.Role == RoleProxyAdminOwner:
+			severity, powers = parser.SeverityHigh, nil
+		case len(highValue) > 0:
+			severity, powers = parser.SeverityHigh, highValue
+		}
+		holder := "an EOA"
+		if a.Kind == KindSafe {
+			holder = fmt.Sprintf("a %d-of-%d Safe", a.Threshold, a.Owners)
+		}
+
+		f := parser.Finding{
+			ID:          fmt.Sprintf("CUSTOM-ADMIN-%d", len(findings)+1),
+			Source:      "custom",
+			Check:       "custom-eoa-admin",
+			Title:       fmt.Sprintf("%s Admin Held by a Single Key: %s", contract, a.Role),
+			Severity:    severity,
+			Confidence:  "High",
+			Contract:    contract,
+			File:        file,
+			Remediation: db.Remediation("custom-eoa-admin"),
+			CWERef:      db.CWE("custom-eoa-admin"),
+			Evidence:    []string{fmt.Sprintf("%s of %s is %s", a.Role, contract, a)},
+		}
+		what := "every function it guards"
+		if a.Role == RoleProxyAdmin || a.Role == RoleProxyAdminOwner {
+			what = "the implementation, and with it every function and all funds,"
+		} else if len(powers) > 0 {
+			var names []string
+			for _, e := range powers {
+				names = append(names, e.Function+"()")
+			}
+			what = strings.Join(names, ", ")
+			f.Lines, f.Function = []int{powers[0].Line}, powers[0].Function
+			f.Evidence = append(f.Evidence, fmt.Sprintf("guarded functions it can call: %s", what))
+		}
+		f.Description = fmt.Sprintf(
+			"The %s of %s, %s, is %s. Whoever holds that one key controls %s with no second signer or delay, so a phished, leaked or lost key is enough to take over the contract.",
+			a.Role, contract, a.Address, holder, what,
+		)
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// LoadDeployment reads contract addresses from a deployment record: a
+// Foundry broadcast (run-latest.json), or a hardhat-deploy directory of
+// <Contract>.json files. It returns them by contract name.
+func LoadDeployment(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	addresses := map[string]string{}
+	if info.IsDir() {
+		files, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			var d struct {
+				Address string `json:"address"`
+			}
+			if err := readJSON(file, &d); err != nil {
+				return nil, err
+			}
+			if d.Address != "" {
+				addresses[strings.TrimSuffix(filepath.Base(file), ".json")] = strings.ToLower(d.Address)
+			}
+		}
+		return addresses, nil
+	}
+
+	var broadcast struct {
+		Transactions []struct {
+			Type     string `json:"transactionType"`
+			Contract string `json:"contractName"`
+			Address  string `json:"contractAddress"`
+		} `json:"transactions"`
+	}
+	if err := readJSON(path, &broadcast); err != nil {
+		return nil, err
+	}
+	for _, tx := range broadcast.Transactions {
+		if (tx.Type == "CREATE" || tx.Type == "CREATE2") && tx.Contract != "" && tx.Address != "" {
+			addresses[tx.Contract] = strings.ToLower(tx.Address)
+		}
+	}
+	return addresses, nil
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package admins
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/impact"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+const (
+	vault      = "0x1111111111111111111111111111111111111111"
+	proxyAdmin = "0x2222222222222222222222222222222222222222"
+	deployer   = "0x3333333333333333333333333333333333333333"
+	safe       = "0x4444444444444444444444444444444444444444"
+	timelock   = "0x5555555555555555555555555555555555555555"
+	delegated  = "0x6666666666666666666666666666666666666666"
+)
+
+func word(hex string) string {
+	return "0x" + strings.Repeat("0", 64-len(hex)) + hex
+}
+
+// fakeNode serves a proxied vault owned by a 2-of-3 Safe whose ProxyAdmin
+// is owned by the deployer EOA, next to a timelock and an EIP-7702 account.
+func fakeNode(t *testing.T) *httptest.Server {
+	code := map[string]string{
+		vault:      "0x6080",
+		proxyAdmin: "0x6080",
+		safe:       "0x6080",
+		timelock:   "0x6080",
+		delegated:  delegatedPrefix + "aa",
+	}
+	calls := map[string]string{
+		vault + ownerSelector:         word(strings.TrimPrefix(safe, "0x")),
+		proxyAdmin + ownerSelector:    word(strings.TrimPrefix(deployer, "0x")),
+		safe + thresholdSelector:      word("2"),
+		safe + ownersSelector:         word("20") + strings.TrimPrefix(word("3")+word("a1")+word("a2")+word("a3"), "0x"),
+		timelock + minDelaySelector:   word("2a300"),
+		delegated + thresholdSelector: "0x",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var result string
+		switch req.Method {
+		case "eth_getCode":
+			var address string
+			_ = json.Unmarshal(req.Params[0], &address)
+			if result = code[address]; result == "" {
+				result = "0x"
+			}
+		case "eth_getStorageAt":
+			var address, slot string
+			_ = json.Unmarshal(req.Params[0], &address)
+			_ = json.Unmarshal(req.Params[1], &slot)
+			assert.Equal(t, adminSlot, slot)
+			result = word("0")
+			if address == vault {
+				result = word(strings.TrimPrefix(proxyAdmin, "0x"))
+			}
+		case "eth_call":
+			var call struct{ To, Data string }
+			_ = json.Unmarshal(req.Params[0], &call)
+			var ok bool
+			if result, ok = calls[call.To+call.Data]; !ok {
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAdmins(t *testing.T) {
+	srv := fakeNode(t)
+	in := &Inspector{Client: &impact.Client{URL: srv.URL}}
+
+	admins, err := in.Admins(context.Background(), vault, []string{timelock, delegated})
+	require.NoError(t, err)
+	assert.Equal(t, []Admin{
+		{Role: RoleOwner, Address: safe, Kind: KindSafe, Threshold: 2, Owners: 3},
+		{Role: RoleProxyAdmin, Address: proxyAdmin, Kind: KindContract},
+		{Role: RoleProxyAdminOwner, Address: deployer, Kind: KindEOA},
+		{Role: RoleDeclared, Address: timelock, Kind: KindTimelock, Delay: 172800},
+		{Role: RoleDeclared, Address: delegated, Kind: KindEOA},
+	}, admins)
+	assert.Equal(t, "0x4444444444444444444444444444444444444444 (2-of-3 Safe)", admins[0].String())
+	assert.False(t, admins[0].SingleSigner())
+	assert.True(t, admins[2].SingleSigner())
+
+	// Neither Ownable nor a proxy: nothing to report.
+	admins, err = in.Admins(context.Background(), timelock, nil)
+	require.NoError(t, err)
+	assert.Empty(t, admins)
+}
+
+func TestFindings(t *testing.T) {
+	matrix := []parser.AccessEntry{
+		{Contract: "Vault", Function: "deposit", File: "src/Vault.sol", Line: 10, CallableBy: []string{parser.Anyone}},
+		{Contract: "Vault", Function: "setFee", File: "src/Vault.sol", Line: 20, CallableBy: []string{"owner"}},
+		{Contract: "Vault", Function: "setName", File: "src/Vault.sol", Line: 30, CallableBy: []string{"owner"}},
+		{Contract: "Other", Function: "withdraw", File: "src/Other.sol", Line: 5, CallableBy: []string{"owner"}},
+	}
+	admins := []Admin{
+		{Role: RoleOwner, Address: deployer, Kind: KindEOA},
+		{Role: RoleProxyAdminOwner, Address: safe, Kind: KindSafe, Threshold: 1, Owners: 2},
+		{Role: RoleDeclared, Address: timelock, Kind: KindTimelock, Delay: 86400},
+	}
+
+	findings := Findings("Vault", admins, matrix)
+	require.Len(t, findings, 2)
+
+	owner := findings[0]
+	assert.Equal(t, "custom-eoa-admin", owner.Check)
+	assert.Equal(t, parser.SeverityHigh, owner.Severity)
+	assert.Equal(t, "src/Vault.sol", owner.File)
+	assert.Equal(t, []int{20}, owner.Lines)
+	assert.Equal(t, "setFee", owner.Function)
+	assert.Contains(t, owner.Description, "is an EOA")
+	assert.Contains(t, owner.Evidence, "guarded functions it can call: setFee()")
+	assert.NotEmpty(t, owner.Remediation)
+
+	upgrader := findings[1]
+	assert.Equal(t, parser.SeverityHigh, upgrader.Severity)
+	assert.Empty(t, upgrader.Lines)
+	assert.Contains(t, upgrader.Description, "a 1-of-2 Safe")
+
+	// Without high-value functions an EOA owner is Medium.
+	findings = Findings("Vault", admins[:1], matrix[2:3])
+	require.Len(t, findings, 1)
+	assert.Equal(t, parser.SeverityMedium, findings[0].Severity)
+}
+
+func TestLoadDeployment(t *testing.T) {
+	dir := t.TempDir()
+	broadcast := filepath.Join(dir, "run-latest.json")
+	require.NoError(t, os.WriteFile(broadcast, []byte(`{"transactions": [
+		{"transactionType": "CREATE", "contractName": "Vault", "contractAddress": "0x1111111111111111111111111111111111111111"},
+		{"transactionType": "CALL", "contractName": "Vault", "contractAddress": "0x2222222222222222222222222222222222222222"},
+		{"transactionType": "CREATE2", "contractName": "Token", "contractAddress": "0xABCDEFabcdefABCDEFabcdefABCDEFabcdefABCD"}
+	]}`), 0o644))
+	addresses, err := LoadDeployment(broadcast)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Vault": vault,
+		"Token": "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+	}, addresses)
+
+	hardhat := filepath.Join(dir, "mainnet")
+	require.NoError(t, os.Mkdir(hardhat, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(hardhat, "Vault.json"), []byte(`{"address": "0x1111111111111111111111111111111111111111", "abi": []}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(hardhat, "Vault_Implementation.json"), []byte(`{"address": "0x2222222222222222222222222222222222222222"}`), 0o644))
+	addresses, err = LoadDeployment(hardhat)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Vault": vault, "Vault_Implementation": proxyAdmin}, addresses)
+
+	_, err = LoadDeployment(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+const sender = `(?:msg\.sender|_msgSender\(\))`
+
+// senderChecks and roleCheck match caller checks written in a function body
+// rather than as a modifier, e.g. require(msg.sender == owner).
+var (
+	senderChecks = []*regexp.Regexp{
+		regexp.MustCompile(sender + `\s*[!=]=\s*([\w.]+(?:\(\))?)`),
+		regexp.MustCompile(`([\w.]+(?:\(\))?)\s*[!=]=\s*` + sender),
+	}
+	roleCheck = regexp.MustCompile(`\b(?:hasRole|_checkRole)\(\s*(\w+)`)
+)
+
+// buildAccessMatrix lists every external or public state-changing function
+// in the target's scope, outside the files exclude drops, with the
+// modifiers and inline sender checks that restrict its callers.
+func buildAccessMatrix(target string, exclude *source.Excluder, scope *parser.Scope) ([]parser.AccessEntry, error) {
+	files, err := source.SolidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	files = exclude.Filter(files)
+	var matrix []parser.AccessEntry
+	for _, path := range files {
+		f, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range f.Contracts {
+			if c.Kind != "contract" {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if !stateChanging(fn) || !scope.Empty() && !inScopeFunction(scope, c.Name, fn.Name) {
+					continue
+				}
+				decl, hasBody := declaration(f, fn)
+				if !hasBody {
+					continue
+				}
+				matrix = append(matrix, parser.AccessEntry{
+					Contract:   c.Name,
+					Function:   fn.Name,
+					File:       path,
+					Line:       fn.Line,
+					Payable:    fn.Payable,
+					CallableBy: callers(f, fn, decl),
+				})
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// stateChanging reports whether fn can be called from outside the contract
+// and may modify state. Unstated visibility is public (Solidity < 0.5).
+func stateChanging(fn solidity.Function) bool {
+	if fn.Name == "constructor" || fn.Mutability != "" {
+		return false
+	}
+	return fn.Visibility == "external" || fn.Visibility == "public" || fn.Visibility == ""
+}
+
+// declaration returns fn's header up to its body, and whether it has one.
+func declaration(f *solidity.File, fn solidity.Function) (string, bool) {
+	var decl strings.Builder
+	for n := fn.Line; n <= fn.End; n++ {
+		line := f.Line(n)
+		if i := strings.IndexAny(line, "{;"); i >= 0 {
+			decl.WriteString(line[:i])
+			return decl.String(), line[i] == '{'
+		}
+		decl.WriteString(line + " ")
+	}
+	return decl.String(), false
+}
+
+// callers lists the guards on fn, or parser.Anyone when it has none.
+func callers(f *solidity.File, fn solidity.Function, decl string) []string {
+	accessModifiers := rules.Default().Signature("access-modifier")
+	var guards []string
+	add := func(g string) {
+		if !slices.Contains(guards, g) {
+			guards = append(guards, g)
+		}
+	}
+	for _, m := range fn.Modifiers {
+		if !strings.HasPrefix(m, "only") && !slices.Contains(accessModifiers, m) {
+			continue // e.g. nonReentrant or whenNotPaused
+		}
+		// Keep the arguments, which name the role: onlyRole(MINTER_ROLE).
+		if args := regexp.MustCompile(`\b` + regexp.QuoteMeta(m) + `\s*\(([^)]*)\)`).FindStringSubmatch(decl); args != nil {
+			m += "(" + strings.TrimSpace(args[1]) + ")"
+		}
+		add(m)
+	}
+	for n := fn.Line + 1; n <= fn.End; n++ {
+		line := f.Line(n)
+		for _, re := range senderChecks {
+			for _, m := range re.FindAllStringSubmatch(line, -1) {
+				add("msg.sender == " + m[1])
+			}
+		}
+		for _, m := range roleCheck.FindAllStringSubmatch(line, -1) {
+			add("hasRole(" + m[1] + ")")
+		}
+	}
+	if len(guards) == 0 {
+		return []string{parser.Anyone}
+	}
+	return guards
+}
+
+// mergeAccessMatrices unions the matrices of several reports.
+func mergeAccessMatrices(reports []*parser.AnalysisReport) []parser.AccessEntry {
+	var out []parser.AccessEntry
+	type key struct {
+		file, contract, function string
+		line                     int
+	}
+	seen := map[key]bool{}
+	for _, r := range reports {
+		for _, e := range r.AccessMatrix {
+			k := key{e.File, e.Contract, e.Function, e.Line}
+			if !seen[k] {
+				seen[k] = true
+				out = append(out, e)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].File != out[j].File {
+			return out[i].File < out[j].File
+		}
+		return out[i].Line < out[j].Line
+	})
+	return out
+}
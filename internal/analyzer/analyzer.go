@@ -1,66 +1,260 @@
 package analyzer
 
 import (
+	"context"
 	"fmt"
-	"sort"
+	"io"
+	"os"
 	"time"
 
 	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/baseline"
 	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/source"
+	"github.com/Zubimendi/solsec/internal/triage"
 )
 
-// Analyze runs all custom Go checks against the target and merges the results
-// with already-parsed Slither findings into a complete AnalysisReport.
-func Analyze(target string, slitherFindings []parser.Finding) (*parser.AnalysisReport, error) {
+// Options controls which custom checks run during analysis.
+type Options struct {
+	// Checks restricts custom checks to the named ones (see checks.Names).
+	// Empty means all checks run.
+	Checks []string
+
+	// Engines names the external engines (e.g. "slither") whose findings are
+	// being merged, recorded per file in the report's scope manifest.
+	Engines []string
+
+	// EngineChecks lists the engine detectors that ran (e.g. the enabled
+	// Slither detectors), for the report's compliance matrix.
+	EngineChecks []string
+
+	// Skipped maps files the engines could not cover to the reason why.
+	Skipped map[string]string
+
+	// Scope, when non-empty, drops engine and custom findings outside the
+	// named contracts and functions.
+	Scope *parser.Scope
+
+	// IncludeTags keeps only findings with at least one of these tags;
+	// ExcludeTags drops findings with any of them.
+	IncludeTags []string
+	ExcludeTags []string
+
+	// MinConfidence, when set, makes the "min-confidence" stage drop
+	// findings less certain than this level (see ParseConfidence).
+	MinConfidence string
+
+	// MinSeverity, when set, makes the "min-severity" stage drop findings
+	// less severe than this level (see ParseMinSeverity); by default it runs
+	// after the stages that adjust severities.
+	MinSeverity parser.Severity
+
+	// ExcludePaths are gitignore-syntax patterns, relative to the working
+	// directory, of files left out of the custom checks, the scope manifest,
+	// the import graph and the access-control matrix.
+	ExcludePaths []string
+
+	// Limits guard custom checks against oversized and binary files; nil
+	// means source.DefaultLimits.
+	Limits *source.Limits
+
+	// Pipeline names the post-processing stages run over the merged
+	// findings, in order (see Register); nil means DefaultPipeline. The
+	// settings below only take effect through their stages.
+	Pipeline []string
+
+	// CheckTimeout bounds each custom check; zero means
+	// DefaultCheckTimeout.
+	CheckTimeout time.Duration
+
+	// Archetype, when set, names the kind of contract being analyzed (see
+	// LookupArchetype): the "escalate" stage raises its key findings and
+	// the report reviews its risks. Its checks must already be in Checks.
+	Archetype string
+
+	// SeverityOverrides makes the "severity-overrides" stage set the
+	// severity of every finding of the named rules (see
+	// ParseSeverityOverrides).
+	SeverityOverrides map[string]parser.Severity
+
+	// PathSeverities make the "path-severity" stage shift the severity of
+	// findings by the files they are in (see AdjustPathSeverities).
+	PathSeverities []PathSeverity
+
+	// Triage makes the "triage" stage hide the findings reviewed in it.
+	Triage *triage.DB
+
+	// Baseline makes the "baseline" stage drop the known findings it
+	// records, so only new ones are reported.
+	Baseline *baseline.Baseline
+
+	// Log receives warnings about custom checks that fail or time out;
+	// nil means os.Stderr, keeping stdout for the report. The report's
+	// Degraded and Warnings record them either way.
+	Log io.Writer
+}
+
+// Analyze runs the selected custom Go checks against the target and merges the
+// results with already-parsed Slither findings into a complete AnalysisReport.
+// When ctx is cancelled the remaining checks are skipped and the report is
+// marked Interrupted.
+func Analyze(ctx context.Context, target string, slitherFindings []parser.Finding, opts Options) (*parser.AnalysisReport, error) {
+	if err := ValidatePipeline(opts.Pipeline); err != nil {
+		return nil, err
+	}
+	var archetype *Archetype
+	if opts.Archetype != "" {
+		a, err := LookupArchetype(opts.Archetype)
+		if err != nil {
+			return nil, err
+		}
+		archetype = &a
+	}
 	allFindings := make([]parser.Finding, 0, len(slitherFindings))
 	allFindings = append(allFindings, slitherFindings...)
 
+	limits := source.DefaultLimits
+	if opts.Limits != nil {
+		limits = *opts.Limits
+	}
+	exclude := source.NewExcluder(opts.ExcludePaths)
+	filter := checks.Filter{Limits: limits, Exclude: exclude}
+
+	log := opts.Log
+	if log == nil {
+		log = os.Stderr
+	}
+
+	timeout := opts.CheckTimeout
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+
 	// Run each custom check
-	type checkFn func(string) ([]parser.Finding, error)
-	customChecks := []struct {
-		name string
-		fn   checkFn
-	}{
-		{"reentrancy", checks.CheckReentrancy},
-		{"access-control", checks.CheckAccessControl},
-		{"integer-overflow", checks.CheckIntegerOverflow},
-	}
-
-	for _, c := range customChecks {
-		findings, err := c.fn(target)
+	interrupted := false
+	var degraded []parser.DegradedCheck
+	var warnings []parser.Warning
+	for _, c := range checks.Select(opts.Checks) {
+		if ctx.Err() != nil {
+			interrupted = true
+			break
+		}
+		findings, failed, err := runCheck(ctx, c, target, filter, timeout)
+		if ctx.Err() != nil {
+			interrupted = true
+			break
+		}
+		if failed != nil {
+			fmt.Fprintf(log, "⚠️  Custom check '%s' %s; its findings are missing from the report\n", c.Name, failed.Reason)
+			degraded = append(degraded, *failed)
+			continue
+		}
 		if err != nil {
 			// Non-fatal: log and continue rather than aborting the whole analysis
-			fmt.Printf("⚠️  Custom check '%s' encountered an error: %v\n", c.name, err)
+			fmt.Fprintf(log, "⚠️  Custom check '%s' encountered an error: %v\n", c.Name, err)
+			warnings = append(warnings, parser.Warning{Kind: parser.WarningCheckError, Check: c.Name, Message: err.Error()})
 			continue
 		}
 		allFindings = append(allFindings, findings...)
 	}
 
-	// Deduplicate: remove custom findings that duplicate Slither findings
-	// (same file + overlapping lines + same SWC reference)
-	allFindings = deduplicate(allFindings)
+	locate(target, allFindings)
+	tag(allFindings)
+	link(target, allFindings)
+	allFindings = inScope(allFindings, opts.Scope)
+	allFindings = filterTags(allFindings, opts.IncludeTags, opts.ExcludeTags)
+	evaluated := append(customRules(opts.Checks), opts.EngineChecks...)
+	report := newReport(target, allFindings)
+	report.Warnings = warnings
+	report.Compliance = complianceMatrix(evaluated)
+	if archetype != nil {
+		report.Archetype = archetypeReview(*archetype, evaluated)
+	}
+	if err := Process(report, opts); err != nil {
+		return nil, err
+	}
+	report.Interrupted = interrupted
+	report.Degraded = degraded
+	if !opts.Scope.Empty() {
+		report.Scope = opts.Scope
+	}
+	manifest, err := buildManifest(target, exclude, opts.Engines, opts.Skipped, limits)
+	if err != nil {
+		return nil, fmt.Errorf("building scope manifest: %w", err)
+	}
+	report.AnalyzedFiles = manifest
+	report.Warnings = append(report.Warnings, manifestWarnings(manifest)...)
+
+	graph, err := buildImportGraph(target, exclude)
+	if err != nil {
+		return nil, fmt.Errorf("building import graph: %w", err)
+	}
+	report.ImportGraph = graph
+
+	matrix, err := buildAccessMatrix(target, exclude, opts.Scope)
+	if err != nil {
+		return nil, fmt.Errorf("building access-control matrix: %w", err)
+	}
+	report.AccessMatrix = matrix
+	return report, nil
+}
 
-	// Sort: most severe first
-	sort.Slice(allFindings, func(i, j int) bool {
-		ri := parser.SeverityRank(allFindings[i].Severity)
-		rj := parser.SeverityRank(allFindings[j].Severity)
-		if ri != rj {
-			return ri < rj
+// Merge combines several reports (from workspace packages, CI shards, or
+// different engines) into one report for target. Findings are re-sorted and
+// re-summarized, and clusters are expanded; call Process on the result to
+// deduplicate and regroup them as a single analysis would.
+func Merge(target string, reports ...*parser.AnalysisReport) *parser.AnalysisReport {
+	var all []parser.Finding
+	for _, r := range reports {
+		all = append(all, parser.Flatten(r.Findings)...)
+	}
+	merged := newReport(target, all)
+	merged.Compliance = mergeCompliance(reports)
+	merged.Archetype = mergeArchetype(reports)
+	finalize(merged)
+	merged.AnalyzedFiles = mergeManifests(reports)
+	merged.ImportGraph = mergeImportGraphs(reports)
+	merged.AccessMatrix = mergeAccessMatrices(reports)
+	for _, r := range reports {
+		merged.Interrupted = merged.Interrupted || r.Interrupted
+		merged.Degraded = append(merged.Degraded, r.Degraded...)
+		merged.Warnings = append(merged.Warnings, r.Warnings...)
+		merged.Triaged += r.Triaged
+		merged.Baselined += r.Baselined
+		if merged.Scope == nil {
+			merged.Scope = r.Scope
+		}
+		if merged.Environment == nil {
+			merged.Environment = r.Environment
 		}
-		return allFindings[i].File < allFindings[j].File
-	})
+	}
+	return merged
+}
 
-	report := &parser.AnalysisReport{
+// Ingest builds a report for target from findings imported from other
+// tools' output. They are located and tagged like engine findings; run
+// Process on the result, or Merge it into an existing report.
+func Ingest(target string, findings []parser.Finding) *parser.AnalysisReport {
+	locate(target, findings)
+	tag(findings)
+	link(target, findings)
+	report := newReport(target, findings)
+	finalize(report)
+	return report
+}
+
+func newReport(target string, allFindings []parser.Finding) *parser.AnalysisReport {
+	return &parser.AnalysisReport{
 		Target:      target,
 		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
 		Findings:    allFindings,
-		Summary:     buildSummary(allFindings),
 	}
-
-	return report, nil
 }
 
+// buildSummary counts findings by severity, including the ones clustered
+// under others.
 func buildSummary(findings []parser.Finding) parser.Summary {
+	findings = parser.Flatten(findings)
 	s := parser.Summary{Total: len(findings)}
 	for _, f := range findings {
 		switch f.Severity {
@@ -81,25 +275,45 @@ func buildSummary(findings []parser.Finding) parser.Summary {
 	return s
 }
 
-// deduplicate removes custom findings that overlap significantly with Slither findings.
-func deduplicate(findings []parser.Finding) []parser.Finding {
-	seen := map[string]bool{}
+// dedupKey identifies what a finding reports: its weakness and location
+// (SWC ref + file + first line). Without an SWC reference the CWE, then the
+// check, stands in for it. Keying on an empty SWC reference made every
+// finding without one a duplicate of the first on its line, so two deploy
+// script checks flagging the same deployment dropped each other.
+func dedupKey(f parser.Finding) string {
+	ref := f.SWCRef
+	if ref == "" {
+		ref = f.CWERef
+	}
+	if ref == "" {
+		ref = f.Check
+	}
+	key := ref + "|" + f.File
+	if len(f.Lines) > 0 {
+		key += fmt.Sprintf("|%d", f.Lines[0])
+	}
+	return key
+}
+
+// deduplicate removes custom findings that overlap significantly with Slither
+// findings, and returns a warning for each one dropped.
+func deduplicate(findings []parser.Finding) ([]parser.Finding, []parser.Warning) {
+	seen := map[string]parser.Finding{}
+	var dropped []parser.Warning
 	result := make([]parser.Finding, 0, len(findings))
 
 	for _, f := range findings {
-		// Key: SWC ref + file + first line
-		key := f.SWCRef + "|" + f.File
-		if len(f.Lines) > 0 {
-			key += fmt.Sprintf("|%d", f.Lines[0])
-		}
+		key := dedupKey(f)
 
 		// If we've already seen a finding with the same key from a different source, skip
-		if seen[key] {
+		if kept, ok := seen[key]; ok {
+			dropped = append(dropped, parser.Warning{Kind: parser.WarningDedup, Check: f.Check, File: f.File,
+				Message: fmt.Sprintf("%s dropped as a duplicate of %s (%s)", f.ID, kept.ID, kept.Check)})
 			continue
 		}
-		seen[key] = true
+		seen[key] = f
 		result = append(result, f)
 	}
 
-	return result
+	return result, dropped
 }
\ No newline at end of file
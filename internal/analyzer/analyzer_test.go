@@ -1,13 +1,21 @@
 package analyzer
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
 	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/source"
 )
 
 func TestAnalyze(t *testing.T) {
@@ -36,7 +44,7 @@ contract X {
 		},
 	}
 
-	report, err := Analyze(tmpFile, slitherFindings)
+	report, err := Analyze(context.Background(), tmpFile, slitherFindings, Options{})
 	require.NoError(t, err)
 
 	assert.NotNil(t, report)
@@ -44,3 +52,543 @@ contract X {
 	// Should have at least the slither finding + custom access control finding for mint()
 	assert.GreaterOrEqual(t, len(report.Findings), 2)
 }
+
+func TestAnalyze_ChecksSelection(t *testing.T) {
+	content := `
+pragma solidity 0.7.0;
+contract X {
+    function mint() public {}
+}
+`
+	tmpDir, err := os.MkdirTemp("", "solsec-test-*")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	tmpFile := filepath.Join(tmpDir, "test.sol")
+	err = os.WriteFile(tmpFile, []byte(content), 0644)
+	require.NoError(t, err)
+
+	report, err := Analyze(context.Background(), tmpFile, nil, Options{Checks: []string{"reentrancy", "integer-overflow"}})
+	require.NoError(t, err)
+
+	for _, f := range report.Findings {
+		assert.NotEqual(t, "custom-missing-access-control", f.Check)
+	}
+}
+
+func TestAnalyze_Cancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("contract A { function mint() public {} }\n"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	engine := []parser.Finding{{ID: "S-1", Source: "slither", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{1}}}
+	report, err := Analyze(ctx, tmpFile, engine, Options{})
+	require.NoError(t, err)
+
+	assert.True(t, report.Interrupted)
+	require.Len(t, report.Findings, 1, "no checks run after cancellation; engine findings are kept")
+	assert.Equal(t, "S-1", report.Findings[0].ID)
+	assert.Len(t, report.AnalyzedFiles, 1)
+}
+
+func TestMerge(t *testing.T) {
+	a := &parser.AnalysisReport{Findings: []parser.Finding{
+		{ID: "A-1", Severity: parser.SeverityLow, File: "a.sol", Lines: []int{3}, SWCRef: "SWC-101"},
+		{ID: "A-2", Severity: parser.SeverityHigh, File: "a.sol", Lines: []int{9}, SWCRef: "SWC-107"},
+	}}
+	b := &parser.AnalysisReport{Findings: []parser.Finding{
+		// Same location and SWC as A-2 — reported by another shard
+		{ID: "B-1", Severity: parser.SeverityHigh, File: "a.sol", Lines: []int{9}, SWCRef: "SWC-107"},
+		{ID: "B-2", Severity: parser.SeverityCritical, File: "b.sol", Lines: []int{1}, SWCRef: "SWC-105"},
+	}}
+
+	merged := Merge("combined", a, b)
+	assert.Len(t, merged.Findings, 4, "Merge itself keeps every finding")
+	require.NoError(t, Process(merged, Options{Pipeline: []string{"dedup"}}))
+
+	assert.Equal(t, "combined", merged.Target)
+	require.Len(t, merged.Findings, 3)
+	assert.Equal(t, "B-2", merged.Findings[0].ID, "most severe first")
+	assert.Equal(t, 3, merged.Summary.Total)
+	assert.Equal(t, 1, merged.Summary.Critical)
+	assert.Equal(t, 1, merged.Summary.High)
+	assert.Equal(t, 1, merged.Summary.Low)
+}
+
+func TestAnalyze_Manifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	good := filepath.Join(tmpDir, "Good.sol")
+	broken := filepath.Join(tmpDir, "Broken.sol")
+	require.NoError(t, os.WriteFile(good, []byte("pragma solidity ^0.8.24;\ncontract Good {}\n"), 0644))
+	require.NoError(t, os.WriteFile(broken, []byte("pragma solidity 0.8.0;\ncontract Broken {\n"), 0644))
+
+	report, err := Analyze(context.Background(), tmpDir, nil, Options{
+		Engines: []string{"slither"},
+		Skipped: map[string]string{broken: "compilation failed"},
+	})
+	require.NoError(t, err)
+	require.Len(t, report.AnalyzedFiles, 2)
+
+	byPath := map[string]parser.AnalyzedFile{}
+	for _, f := range report.AnalyzedFiles {
+		byPath[f.Path] = f
+	}
+	assert.Equal(t, "^0.8.24", byPath[good].Pragma)
+	assert.Equal(t, 2, byPath[good].SLOC)
+	assert.Len(t, byPath[good].SHA256, 64)
+	assert.Equal(t, []string{"custom", "slither"}, byPath[good].Engines)
+
+	assert.True(t, byPath[broken].Skipped)
+	assert.Equal(t, []string{"custom"}, byPath[broken].Engines)
+}
+
+func TestAnalyze_Limits(t *testing.T) {
+	tmpDir := t.TempDir()
+	small := filepath.Join(tmpDir, "Small.sol")
+	flat := filepath.Join(tmpDir, "Flat.sol")
+	mint := "pragma solidity ^0.8.24;\ncontract %s {\n    function mint() public {}\n}\n"
+	require.NoError(t, os.WriteFile(small, []byte(fmt.Sprintf(mint, "Small")), 0644))
+	require.NoError(t, os.WriteFile(flat, []byte(fmt.Sprintf(mint, "Flat")+strings.Repeat("// padding\n", 100)), 0644))
+
+	report, err := Analyze(context.Background(), tmpDir, nil, Options{
+		Engines: []string{"slither"},
+		Checks:  []string{"access-control"},
+		Limits:  &source.Limits{MaxFileSize: 512},
+	})
+	require.NoError(t, err)
+	for _, f := range report.Findings {
+		assert.Equal(t, small, f.File, "the oversized file is not read by custom checks")
+	}
+	require.Len(t, report.AnalyzedFiles, 2)
+	for _, f := range report.AnalyzedFiles {
+		if f.Path == flat {
+			assert.Equal(t, "1.1 KB exceeds the 512 B size limit", f.Excluded)
+			assert.Equal(t, []string{"slither"}, f.Engines)
+		} else {
+			assert.Empty(t, f.Excluded)
+		}
+	}
+}
+
+func TestAnalyze_Scope(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte(`pragma solidity 0.8.20;
+contract Token {
+    function mint(address to) public {}
+    function burn(address from) public {}
+}
+contract Vault {
+    function mint(address to) public {}
+}
+`), 0644))
+
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "a", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{1}},
+		{ID: "S-2", Source: "slither", Check: "b", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{3}},
+		{ID: "S-3", Source: "slither", Check: "c", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{4}},
+		{ID: "S-4", Source: "slither", Check: "d", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{7}},
+	}
+	scope := &parser.Scope{Contracts: []string{"Token"}, Functions: []string{"mint"}}
+	report, err := Analyze(context.Background(), tmpFile, engine, Options{Scope: scope})
+	require.NoError(t, err)
+
+	assert.Equal(t, scope, report.Scope)
+	require.NotEmpty(t, report.Findings)
+	for _, f := range report.Findings {
+		assert.Equal(t, "Token", f.Contract, f.ID)
+		assert.Equal(t, "mint", f.Function, f.ID)
+	}
+	ids := map[string]bool{}
+	for _, f := range report.Findings {
+		ids[f.ID] = true
+	}
+	assert.True(t, ids["S-2"])
+	assert.False(t, ids["S-1"], "file-level findings are outside any scope")
+	assert.False(t, ids["S-4"], "Vault.mint is outside --contract Token")
+}
+
+func TestValidateScope(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("contract Token { function mint() public {} }\ncontract Vault { function sweep() public {} }\n"), 0644))
+
+	assert.NoError(t, ValidateScope(tmpFile, nil))
+	assert.NoError(t, ValidateScope(tmpFile, &parser.Scope{Contracts: []string{"Token"}, Functions: []string{"mint"}}))
+	assert.ErrorContains(t, ValidateScope(tmpFile, &parser.Scope{Contracts: []string{"Tokne"}}), "no such contract")
+	assert.ErrorContains(t, ValidateScope(tmpFile, &parser.Scope{Contracts: []string{"Token"}, Functions: []string{"sweep"}}), "no such function")
+}
+
+func TestAnalyze_Tags(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("pragma solidity 0.8.20;\ncontract A {\n}\n"), 0644))
+
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "reentrancy-eth", Severity: parser.SeverityHigh, File: tmpFile, Lines: []int{1}},
+		{ID: "S-2", Source: "slither", Check: "tx-origin", Severity: parser.SeverityMedium, File: tmpFile, Lines: []int{2}},
+		{ID: "S-3", Source: "slither", Check: "boolean-equality", Severity: parser.SeverityInformational, File: tmpFile, Lines: []int{3}},
+	}
+	ids := func(opts Options) []string {
+		opts.Checks = []string{"reentrancy"} // keep custom checks out of the way
+		report, err := Analyze(context.Background(), tmpFile, append([]parser.Finding{}, engine...), opts)
+		require.NoError(t, err)
+		var out []string
+		for _, f := range report.Findings {
+			out = append(out, f.ID)
+			assert.NotEmpty(t, f.Tags, f.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"S-1", "S-2", "S-3"}, ids(Options{}))
+	assert.Equal(t, []string{"S-1"}, ids(Options{IncludeTags: []string{"defi"}}))
+	assert.Equal(t, []string{"S-1", "S-2"}, ids(Options{ExcludeTags: []string{"gas"}}))
+	assert.Equal(t, []string{"S-2"}, ids(Options{IncludeTags: []string{"access-control", "defi"}, ExcludeTags: []string{"reentrancy"}}))
+}
+
+func TestAnalyze_Compliance(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("contract A {\n    function f() public {}\n}\n"), 0644))
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "tx-origin", Severity: parser.SeverityMedium, File: tmpFile, Lines: []int{2}},
+	}
+	report, err := Analyze(context.Background(), tmpFile, engine, Options{
+		Checks:       []string{"reentrancy"},
+		EngineChecks: []string{"tx-origin", "suicidal"},
+	})
+	require.NoError(t, err)
+
+	controls := map[string]parser.ComplianceControl{}
+	for _, c := range report.Compliance {
+		controls[c.Framework+" "+c.Control] = c
+	}
+	assert.Equal(t, 1, controls["EthTrust [S] No tx.origin"].Findings)
+	assert.Equal(t, 0, controls["EthTrust [S] No selfdestruct()"].Findings, "evaluated and passed")
+	assert.Equal(t, []string{"custom-reentrancy-ordering"}, controls["EthTrust [S] Use Check-Effects-Interaction"].Checks)
+	assert.Equal(t, []string{"suicidal", "tx-origin"}, controls["SCSVS SCSVS-AUTH"].Checks)
+	assert.Equal(t, 1, controls["SCSVS SCSVS-AUTH"].Findings)
+	assert.NotContains(t, controls, "EthTrust [M] Sources of Randomness", "weak-prng did not run")
+
+	other := &parser.AnalysisReport{Compliance: []parser.ComplianceControl{
+		{Framework: FrameworkEthTrust, Control: "[M] Sources of Randomness", Checks: []string{"weak-prng"}},
+	}}
+	merged := Merge("m", report, other)
+	assert.Len(t, merged.Compliance, len(report.Compliance)+2, "weak-prng adds its EthTrust and SCSVS controls")
+}
+
+func TestAnalyze_Archetype(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("contract A {\n    function f() public {}\n}\n"), 0644))
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "weak-prng", Severity: parser.SeverityHigh, File: tmpFile, Lines: []int{2}},
+		{ID: "S-2", Source: "slither", Check: "timestamp", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{2}},
+	}
+	report, err := Analyze(context.Background(), tmpFile, engine, Options{
+		Checks:       []string{"access-control", "reentrancy"},
+		EngineChecks: []string{"weak-prng", "timestamp"},
+		Archetype:    "NFT",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, parser.SeverityCritical, report.Findings[0].Severity, "weak-prng is escalated for NFTs")
+	assert.Contains(t, report.Findings[0].Evidence, "severity raised from High to Critical: weak-prng is a key risk for nft contracts")
+	assert.Equal(t, parser.SeverityLow, report.Findings[1].Severity)
+	assert.Equal(t, 1, report.Summary.Critical)
+
+	require.NotNil(t, report.Archetype)
+	assert.Equal(t, "nft", report.Archetype.Name)
+	risks := map[string]parser.ArchetypeRisk{}
+	for _, r := range report.Archetype.Risks {
+		risks[r.Title] = r
+	}
+	assert.Equal(t, 2, risks["Predictable randomness in mints and reveals"].Findings)
+	assert.Empty(t, risks["Mint payments in loops"].Checks, "msg-value-loop did not run")
+	assert.Equal(t, []string{"custom-reentrancy-ordering"}, risks["Reentrancy through onERC721Received callbacks"].Checks)
+
+	_, err = Analyze(context.Background(), tmpFile, nil, Options{Archetype: "lending"})
+	assert.ErrorContains(t, err, `unknown archetype "lending"`)
+}
+
+func TestArchetypes_NameKnownChecksAndRules(t *testing.T) {
+	db := rules.Default()
+	for _, name := range ArchetypeNames() {
+		a, err := LookupArchetype(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, a.Name)
+		for _, c := range a.Checks {
+			assert.Len(t, checks.Select([]string{c}), 1, "%s: check %s", name, c)
+		}
+		for _, r := range a.Risks {
+			for _, rule := range r.Rules {
+				assert.Contains(t, db.Rules, rule, "%s: %s", name, r.Title)
+			}
+		}
+		for _, rule := range a.Escalate {
+			assert.Contains(t, db.Rules, rule, name)
+		}
+	}
+}
+
+func TestAnalyze_AccessMatrix(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte(`abstract contract Base {
+    function hook() external virtual;
+}
+contract Vault is Base {
+    address owner;
+    function hook() external override {}
+    function sweep() external onlyOwner nonReentrant {}
+    function mint() external onlyRole(MINTER_ROLE) {}
+    function rescue() public {
+        require(owner == msg.sender);
+    }
+    function grant() external {
+        if (!hasRole(ADMIN_ROLE, _msgSender())) revert();
+    }
+    function peek() external view returns (uint256) { return 1; }
+    function _burn() internal {}
+}
+`), 0644))
+
+	report, err := Analyze(context.Background(), tmpFile, nil, Options{Checks: []string{"reentrancy"}})
+	require.NoError(t, err)
+	callers := map[string][]string{}
+	for _, e := range report.AccessMatrix {
+		callers[e.Contract+"."+e.Function] = e.CallableBy
+	}
+	assert.Equal(t, map[string][]string{
+		"Vault.hook":   {parser.Anyone},
+		"Vault.sweep":  {"onlyOwner"},
+		"Vault.mint":   {"onlyRole(MINTER_ROLE)"},
+		"Vault.rescue": {"msg.sender == owner"},
+		"Vault.grant":  {"hasRole(ADMIN_ROLE)"},
+	}, callers)
+
+	scoped, err := Analyze(context.Background(), tmpFile, nil, Options{Checks: []string{"reentrancy"}, Scope: &parser.Scope{Functions: []string{"mint"}}})
+	require.NoError(t, err)
+	require.Len(t, scoped.AccessMatrix, 1)
+	assert.Equal(t, "mint", scoped.AccessMatrix[0].Function)
+
+	merged := Merge("m", report, report)
+	assert.Len(t, merged.AccessMatrix, len(report.AccessMatrix), "duplicates are merged")
+}
+
+func TestCluster(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte(`contract Token {
+    function a() public {}
+    function b() public {}
+    function c() public {}
+}
+contract Vault {
+    function d() public {}
+}
+`), 0644))
+
+	cause := "balances written after an external call"
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "x", Severity: parser.SeverityMedium, File: tmpFile, Lines: []int{2}, RootCause: cause},
+		{ID: "S-2", Source: "slither", Check: "x", Severity: parser.SeverityHigh, File: tmpFile, Lines: []int{3}, RootCause: cause},
+		{ID: "S-3", Source: "slither", Check: "x", Severity: parser.SeverityMedium, File: tmpFile, Lines: []int{4}, RootCause: cause},
+		{ID: "S-4", Source: "slither", Check: "x", Severity: parser.SeverityMedium, File: tmpFile, Lines: []int{7}, RootCause: cause},
+		{ID: "S-5", Source: "slither", Check: "x", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{5}, RootCause: "shares written after an external call"},
+		{ID: "S-6", Source: "slither", Check: "y", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{2}},
+		{ID: "S-7", Source: "slither", Check: "y", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{3}},
+	}
+	// The default pipeline clusters.
+	report, err := Analyze(context.Background(), tmpFile, engine, Options{Checks: []string{"reentrancy"}})
+	require.NoError(t, err)
+
+	require.Len(t, report.Findings, 5)
+	parent := report.Findings[0]
+	assert.Equal(t, "S-2", parent.ID, "the most severe member leads the cluster")
+	require.Len(t, parent.Related, 2)
+	assert.Equal(t, "Token", parent.Contract)
+	assert.Equal(t, "S-4", report.Findings[1].ID, "a single finding in Vault stays on its own")
+	for _, f := range report.Findings[2:] {
+		assert.Empty(t, f.Related, "%s: a different root cause, or none, is not clustered", f.ID)
+	}
+	assert.Equal(t, 7, report.Summary.Total, "the summary counts every finding of a cluster")
+	assert.Equal(t, 3, report.Summary.Medium)
+
+	// Merging expands clusters so they can be regrouped over the combined set.
+	merged := Merge("m", report)
+	assert.Len(t, merged.Findings, 7)
+	require.NoError(t, Process(merged, Options{}))
+	assert.Len(t, merged.Findings, 5)
+}
+
+func TestAnalyze_FingerprintSurvivesLineShifts(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.sol")
+	body := "contract A {\n    function mint() public {}\n}\n"
+	fingerprintOf := func(content string, line int) string {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		engine := []parser.Finding{{ID: "S-1", Source: "slither", Check: "x", Severity: parser.SeverityLow, File: path, Lines: []int{line}}}
+		report, err := Analyze(context.Background(), dir, engine, Options{Checks: []string{"reentrancy"}})
+		require.NoError(t, err)
+		require.Len(t, report.Findings, 1)
+		return report.Findings[0].Fingerprint
+	}
+
+	before := fingerprintOf(body, 2)
+	assert.Len(t, before, 16)
+	assert.Equal(t, before, fingerprintOf("// moved\n\n"+body, 4))
+	assert.NotEqual(t, before, fingerprintOf(body, 1))
+}
+
+func TestAnalyze_SeverityOverrides(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("contract A {\n    function f() public {}\n}\n"), 0644))
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "weak-prng", Severity: parser.SeverityHigh, File: tmpFile, Lines: []int{2}},
+		{ID: "S-2", Source: "slither", Check: "timestamp", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{2}},
+	}
+	overrides, err := ParseSeverityOverrides(map[string]string{"weak-prng": "medium", "timestamp": "Low"})
+	require.NoError(t, err)
+	report, err := Analyze(context.Background(), tmpFile, engine, Options{
+		Checks:            []string{"reentrancy"},
+		Archetype:         "nft",
+		SeverityOverrides: overrides,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, "S-1", report.Findings[0].ID)
+	assert.Equal(t, parser.SeverityMedium, report.Findings[0].Severity, "the override wins over archetype escalation")
+	assert.Contains(t, report.Findings[0].Evidence, "severity changed from Critical to Medium by severity_overrides")
+	assert.Empty(t, report.Findings[1].Evidence, "an override to the same severity is not recorded")
+	assert.Equal(t, 1, report.Summary.Medium)
+
+	_, err = ParseSeverityOverrides(map[string]string{"timestamp": "Severe"})
+	assert.ErrorContains(t, err, `severity override for timestamp: unknown severity "Severe"`)
+}
+
+func TestAnalyze_MinConfidence(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("contract A {\n    function f() public {}\n}\n"), 0644))
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "weak-prng", Severity: parser.SeverityHigh, Confidence: "Medium", File: tmpFile, Lines: []int{2}},
+		{ID: "S-2", Source: "slither", Check: "timestamp", Severity: parser.SeverityLow, Confidence: "Low", File: tmpFile, Lines: []int{2}},
+		{ID: "S-3", Source: "slither", Check: "solc-version", Severity: parser.SeverityInformational, File: tmpFile, Lines: []int{1}},
+	}
+	min, err := ParseConfidence("medium")
+	require.NoError(t, err)
+	report, err := Analyze(context.Background(), tmpFile, engine, Options{
+		Checks:        []string{"reentrancy"},
+		MinConfidence: min,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, report.Findings, 2, "findings without a confidence are kept")
+	assert.Equal(t, "S-1", report.Findings[0].ID)
+	assert.Equal(t, "S-3", report.Findings[1].ID)
+	assert.Equal(t, 0, report.Summary.Low)
+
+	_, err = ParseConfidence("certain")
+	assert.ErrorContains(t, err, `unknown confidence "certain" (use high, medium, low)`)
+}
+
+func TestAnalyze_MinSeverity(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("contract A {\n    function f() public {}\n}\n"), 0644))
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "weak-prng", Severity: parser.SeverityHigh, File: tmpFile, Lines: []int{2}},
+		{ID: "S-2", Source: "slither", Check: "timestamp", Severity: parser.SeverityLow, File: tmpFile, Lines: []int{2}},
+		{ID: "S-3", Source: "slither", Check: "solc-version", Severity: parser.SeverityInformational, File: tmpFile, Lines: []int{1}},
+		{ID: "S-4", Source: "slither", Check: "constable-states", Severity: parser.SeverityOptimization, File: tmpFile, Lines: []int{1}},
+	}
+	min, err := ParseMinSeverity("LOW")
+	require.NoError(t, err)
+	report, err := Analyze(context.Background(), tmpFile, engine, Options{
+		Checks:            []string{"reentrancy"},
+		MinSeverity:       min,
+		SeverityOverrides: map[string]parser.Severity{"solc-version": parser.SeverityMedium},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, report.Findings, 3, "the threshold applies to overridden severities")
+	for _, f := range report.Findings {
+		assert.NotEqual(t, "S-4", f.ID)
+	}
+	assert.Equal(t, 0, report.Summary.Informational)
+
+	_, err = ParseMinSeverity("severe")
+	assert.ErrorContains(t, err, `unknown severity "severe" (use critical, high, medium, low, informational, optimization)`)
+}
+
+func TestAnalyze_CheckWarningsLog(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.sol")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("contract A {\n    function f() public {}\n}\n"), 0644))
+	var log bytes.Buffer
+	report, err := Analyze(context.Background(), tmpFile, nil, Options{
+		Checks:       []string{"reentrancy"},
+		CheckTimeout: time.Nanosecond,
+		Log:          &log,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, report.Degraded, 1)
+	assert.Contains(t, log.String(), "Custom check 'reentrancy' timed out", "warnings go to Options.Log, not stdout")
+}
+
+func TestAnalyze_PathSeverity(t *testing.T) {
+	// Path patterns are relative to the working directory, like .solsecignore.
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	contract := []byte("contract A {\n    function f() public {}\n}\n")
+	files := map[string]string{}
+	for _, name := range []string{"contracts/core/Vault.sol", "contracts/core/mocks/MockVault.sol", "contracts/periphery/Router.sol", "contracts/Lib.sol"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, contract, 0644))
+		files[name] = path
+	}
+	engine := []parser.Finding{
+		{ID: "S-1", Source: "slither", Check: "weak-prng", Severity: parser.SeverityMedium, File: files["contracts/core/Vault.sol"], Lines: []int{2}},
+		{ID: "S-2", Source: "slither", Check: "timestamp", Severity: parser.SeverityCritical, File: files["contracts/core/Vault.sol"], Lines: []int{2}},
+		{ID: "S-3", Source: "slither", Check: "weak-prng", Severity: parser.SeverityMedium, File: files["contracts/core/mocks/MockVault.sol"], Lines: []int{2}},
+		{ID: "S-4", Source: "slither", Check: "weak-prng", Severity: parser.SeverityMedium, File: files["contracts/periphery/Router.sol"], Lines: []int{2}},
+		{ID: "S-5", Source: "slither", Check: "weak-prng", Severity: parser.SeverityMedium, File: files["contracts/Lib.sol"], Lines: []int{2}},
+		{ID: "S-6", Source: "slither", Check: "constable-states", Severity: parser.SeverityOptimization, File: files["contracts/periphery/Router.sol"], Lines: []int{1}},
+	}
+	rules := []PathSeverity{
+		{Paths: []string{"contracts/core/mocks/**"}},
+		{Paths: []string{"contracts/core/**"}, Adjust: 1},
+		{Paths: []string{"contracts/periphery/**"}, Adjust: -1},
+	}
+	require.NoError(t, ValidatePathSeverity(rules))
+	report, err := Analyze(context.Background(), dir, engine, Options{
+		Checks:            []string{"reentrancy"},
+		SeverityOverrides: map[string]parser.Severity{"timestamp": parser.SeverityHigh},
+		PathSeverities:    rules,
+	})
+	require.NoError(t, err)
+
+	severity := map[string]parser.Severity{}
+	for _, f := range report.Findings {
+		severity[f.ID] = f.Severity
+	}
+	assert.Equal(t, map[string]parser.Severity{
+		"S-1": parser.SeverityHigh,
+		"S-2": parser.SeverityCritical,
+		"S-3": parser.SeverityMedium,
+		"S-4": parser.SeverityLow,
+		"S-5": parser.SeverityMedium,
+		"S-6": parser.SeverityOptimization,
+	}, severity, "the first matching rule wins and adjustments apply after overrides")
+	for _, f := range report.Findings {
+		if f.ID == "S-1" {
+			assert.Contains(t, f.Evidence, "severity changed from Medium to High by path_severity (contracts/core/**)")
+		}
+	}
+
+	err = ValidatePathSeverity([]PathSeverity{{Paths: []string{"core/**"}, Adjust: 5}})
+	assert.EqualError(t, err, "path_severity[0] in .solsec.yaml: adjust 5 is out of range (use -4 to 4)")
+	assert.EqualError(t, PathSeverity{Adjust: 1}.Validate(), "no paths")
+}
@@ -0,0 +1,231 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// Archetype is a kind of contract system with its own threat model. It
+// tunes an analysis to that model: extra checks run, the findings that
+// matter most for it are escalated one severity level, and the report
+// answers its key risks.
+type Archetype struct {
+	Name        string
+	Description string
+
+	// Checks are custom checks (opt-in ones included) run on top of the
+	// selected ones.
+	Checks []string
+
+	// Escalate lists the rules whose findings are raised one severity
+	// level, so the score reflects what is critical for the archetype.
+	Escalate []string
+
+	// Risks are the questions the report's archetype section answers, each
+	// with the rules that evaluate it.
+	Risks []ArchetypeRisk
+}
+
+// ArchetypeRisk is one key risk of an archetype.
+type ArchetypeRisk struct {
+	Title string
+	Rules []string
+}
+
+var archetypes = map[string]Archetype{
+	"erc20": {
+		Name:        "erc20",
+		Description: "Fungible tokens: supply, transfer restrictions and fees",
+		Checks:      []string{"token-diligence", "access-control"},
+		Escalate:    []string{"custom-unbounded-fee", "custom-blacklist", "unchecked-transfer"},
+		Risks: []ArchetypeRisk{
+			{"Unrestricted minting and burning", []string{"custom-missing-access-control"}},
+			{"Honeypot and blacklist transfer restrictions", []string{"custom-honeypot-sell-restriction", "custom-blacklist"}},
+			{"Owner-controlled fees", []string{"custom-unbounded-fee"}},
+			{"Balance writes outside transfer, mint and burn", []string{"custom-balance-rewrite"}},
+			{"Unchecked token transfers", []string{"unchecked-transfer"}},
+		},
+	},
+	"nft": {
+		Name:        "nft",
+		Description: "NFT collections: mints, reveals and safe-transfer callbacks",
+		Checks:      []string{"nft-centralization", "erc165", "access-control", "reentrancy"},
+		Escalate:    []string{"weak-prng", "reentrancy-no-eth", "custom-reentrancy-ordering"},
+		Risks: []ArchetypeRisk{
+			{"Unrestricted minting", []string{"custom-missing-access-control"}},
+			{"Reentrancy through onERC721Received callbacks", []string{"reentrancy-eth", "reentrancy-no-eth", "custom-reentrancy-ordering"}},
+			{"Predictable randomness in mints and reveals", []string{"weak-prng", "timestamp"}},
+			{"Mint payments in loops", []string{"msg-value-loop"}},
+			{"Owner control over royalties and metadata", []string{"custom-nft-royalty-bound", "custom-nft-mutable-metadata", "custom-nft-token-uri-override"}},
+			{"Wrong ERC-165 interface support", []string{"custom-erc165-always-true", "custom-erc165-wrong-id", "custom-erc165-missing-super"}},
+		},
+	},
+	"vault": {
+		Name:        "vault",
+		Description: "ERC-4626 and share-based vaults: share inflation, rounding and withdrawals",
+		Checks:      []string{"vault-inflation", "erc4626", "reentrancy", "access-control"},
+		Escalate:    []string{"divide-before-multiply", "reentrancy-no-eth"},
+		Risks: []ArchetypeRisk{
+			{"First-depositor share inflation", []string{"custom-first-depositor-inflation"}},
+			{"Rounding in share conversions", []string{"custom-erc4626-rounding", "divide-before-multiply"}},
+			{"max* views that ignore pauses and caps", []string{"custom-erc4626-max-limit"}},
+			{"Reentrancy on deposit and withdrawal", []string{"reentrancy-eth", "reentrancy-no-eth", "custom-reentrancy-ordering"}},
+			{"Unrestricted withdrawal of vault funds", []string{"custom-missing-access-control", "arbitrary-send-eth"}},
+		},
+	},
+	"amm": {
+		Name:        "amm",
+		Description: "AMMs and DEXes: swap and liquidity math, reentrancy and token handling",
+		Checks:      []string{"amm-math", "reentrancy", "integer-overflow"},
+		Escalate:    []string{"divide-before-multiply", "reentrancy-no-eth", "custom-unchecked-arithmetic", "custom-amm-skim-sync"},
+		Risks: []ArchetypeRisk{
+			{"Constant-product invariant and fee math in swaps", []string{"custom-amm-k-invariant", "custom-amm-fee-order"}},
+			{"Public skim and sync", []string{"custom-amm-skim-sync"}},
+			{"Reentrancy in swaps and liquidity changes", []string{"reentrancy-eth", "reentrancy-no-eth", "custom-reentrancy-ordering"}},
+			{"Precision loss in pricing math", []string{"divide-before-multiply", "custom-unchecked-arithmetic", "custom-integer-overflow"}},
+			{"Unchecked token transfers", []string{"unchecked-transfer"}},
+			{"Block-timestamp dependence (deadlines, TWAPs)", []string{"timestamp"}},
+		},
+	},
+	"bridge": {
+		Name:        "bridge",
+		Description: "Bridges and cross-chain apps: message authentication and privileged relayers",
+		Checks:      []string{"bridge-sender", "access-control", "high-risk-function"},
+		Escalate:    []string{"custom-missing-access-control", "arbitrary-send-eth", "controlled-delegatecall"},
+		Risks: []ArchetypeRisk{
+			{"Unauthenticated cross-domain messages", []string{"custom-cross-domain-sender"}},
+			{"Unrestricted relayer and admin functions", []string{"custom-missing-access-control", "custom-high-risk-function"}},
+			{"Arbitrary calls with bridged funds", []string{"arbitrary-send-eth", "controlled-delegatecall"}},
+			{"Unprotected upgrades", []string{"unprotected-upgrade"}},
+		},
+	},
+	"dao": {
+		Name:        "dao",
+		Description: "Governance: proposal execution, privileged paths around votes and upgrades",
+		Checks:      []string{"access-control", "high-risk-function"},
+		Escalate:    []string{"controlled-delegatecall", "suicidal", "custom-missing-access-control", "backdoor"},
+		Risks: []ArchetypeRisk{
+			{"Privileged actions that bypass governance", []string{"custom-missing-access-control", "backdoor"}},
+			{"Arbitrary proposal execution", []string{"controlled-delegatecall", "arbitrary-send-eth", "custom-high-risk-function"}},
+			{"Unprotected upgrades and self-destruct", []string{"unprotected-upgrade", "suicidal"}},
+			{"Block-timestamp dependence in voting periods", []string{"timestamp"}},
+		},
+	},
+}
+
+// LookupArchetype returns the named archetype.
+func LookupArchetype(name string) (Archetype, error) {
+	a, ok := archetypes[strings.ToLower(name)]
+	if !ok {
+		return Archetype{}, fmt.Errorf("unknown archetype %q (available: %s)", name, strings.Join(ArchetypeNames(), ", "))
+	}
+	return a, nil
+}
+
+// ArchetypeNames returns the archetype names, sorted.
+func ArchetypeNames() []string {
+	names := make([]string, 0, len(archetypes))
+	for n := range archetypes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// escalate raises the findings of the archetype's critical rules one
+// severity level, recording why in their evidence.
+func escalate(findings []parser.Finding, a Archetype) {
+	for i := range findings {
+		f := &findings[i]
+		if !slices.Contains(a.Escalate, f.Check) {
+			continue
+		}
+		raised := raiseSeverity(f.Severity)
+		if raised == f.Severity {
+			continue
+		}
+		f.Evidence = append(f.Evidence, fmt.Sprintf("severity raised from %s to %s: %s is a key risk for %s contracts", f.Severity, raised, f.Check, a.Name))
+		f.Severity = raised
+	}
+}
+
+func raiseSeverity(s parser.Severity) parser.Severity {
+	switch s {
+	case parser.SeverityHigh:
+		return parser.SeverityCritical
+	case parser.SeverityMedium:
+		return parser.SeverityHigh
+	case parser.SeverityLow:
+		return parser.SeverityMedium
+	case parser.SeverityInformational, parser.SeverityOptimization:
+		return parser.SeverityLow
+	}
+	return s
+}
+
+// archetypeReview lists the archetype's risks with the rules that ran and
+// evaluate each, with no findings counted yet (see countArchetype).
+func archetypeReview(a Archetype, evaluated []string) *parser.ArchetypeReview {
+	review := &parser.ArchetypeReview{Name: a.Name, Description: a.Description}
+	for _, r := range a.Risks {
+		risk := parser.ArchetypeRisk{Title: r.Title}
+		for _, rule := range r.Rules {
+			if slices.Contains(evaluated, rule) {
+				risk.Checks = append(risk.Checks, rule)
+			}
+		}
+		review.Risks = append(review.Risks, risk)
+	}
+	return review
+}
+
+// countArchetype recounts the findings against each archetype risk,
+// including clustered ones, so the section follows every pipeline stage.
+func countArchetype(report *parser.AnalysisReport) {
+	if report.Archetype == nil {
+		return
+	}
+	perCheck := map[string]int{}
+	for _, f := range parser.Flatten(report.Findings) {
+		perCheck[f.Check]++
+	}
+	for i := range report.Archetype.Risks {
+		r := &report.Archetype.Risks[i]
+		r.Findings = 0
+		for _, check := range r.Checks {
+			r.Findings += perCheck[check]
+		}
+	}
+}
+
+// mergeArchetype keeps the archetype of the first report that has one,
+// with the union of the rules each report evaluated per risk.
+func mergeArchetype(reports []*parser.AnalysisReport) *parser.ArchetypeReview {
+	var merged *parser.ArchetypeReview
+	for _, r := range reports {
+		if r.Archetype == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &parser.ArchetypeReview{Name: r.Archetype.Name, Description: r.Archetype.Description}
+			for _, risk := range r.Archetype.Risks {
+				merged.Risks = append(merged.Risks, parser.ArchetypeRisk{Title: risk.Title})
+			}
+		}
+		if r.Archetype.Name != merged.Name || len(r.Archetype.Risks) != len(merged.Risks) {
+			continue
+		}
+		for i, risk := range r.Archetype.Risks {
+			for _, check := range risk.Checks {
+				if !slices.Contains(merged.Risks[i].Checks, check) {
+					merged.Risks[i].Checks = append(merged.Risks[i].Checks, check)
+				}
+			}
+		}
+	}
+	return merged
+}
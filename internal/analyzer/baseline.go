@@ -0,0 +1,17 @@
+package analyzer
+
+import (
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// applyBaseline drops the findings recorded in opts.Baseline, counting them
+// in report.Baselined.
+func applyBaseline(report *parser.AnalysisReport, opts Options) error {
+	if opts.Baseline == nil {
+		return nil
+	}
+	var n int
+	report.Findings, n = opts.Baseline.Filter(report.Findings)
+	report.Baselined += n
+	return nil
+}
@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// DefaultCheckTimeout bounds each custom check when Options.CheckTimeout is
+// unset. Checks finish in seconds on real projects; one running for minutes
+// is stuck on pathological input.
+const DefaultCheckTimeout = 2 * time.Minute
+
+// runCheck runs c on the files of target that filter selects, in its own
+// goroutine, so a check that panics or hangs degrades the analysis instead
+// of crashing or stalling it. A panic or timeout is returned as degraded,
+// with no findings. A check that times out cannot be stopped and is left to
+// finish in the background; its results are discarded, and since it only
+// reads its own arguments it cannot disturb later runs. When ctx is cancelled first, ctx's error is returned.
+func runCheck(ctx context.Context, c checks.Check, target string, filter checks.Filter, timeout time.Duration) (findings []parser.Finding, degraded *parser.DegradedCheck, err error) {
+	type result struct {
+		findings []parser.Finding
+		err      error
+		panic    any
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- result{panic: p}
+			}
+		}()
+		findings, err := c.Run(target, filter)
+		done <- result{findings: findings, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		if r.panic != nil {
+			return nil, &parser.DegradedCheck{Check: c.Name, Reason: fmt.Sprintf("panic: %v", r.panic)}, nil
+		}
+		return r.findings, nil, r.err
+	case <-timer.C:
+		return nil, &parser.DegradedCheck{Check: c.Name, Reason: fmt.Sprintf("timed out after %s", timeout)}, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
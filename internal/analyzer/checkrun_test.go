@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+func TestRunCheck(t *testing.T) {
+	filter := checks.Filter{Limits: source.Limits{MaxFileSize: 10}}
+	ok := checks.Check{Name: "ok", Run: func(_ string, got checks.Filter) ([]parser.Finding, error) {
+		assert.Equal(t, filter, got, "the filter is passed to the check, not shared")
+		return []parser.Finding{{ID: "C-1"}}, nil
+	}}
+	findings, degraded, err := runCheck(context.Background(), ok, ".", filter, time.Second)
+	require.NoError(t, err)
+	assert.Nil(t, degraded)
+	assert.Len(t, findings, 1)
+
+	failing := checks.Check{Name: "failing", Run: func(string, checks.Filter) ([]parser.Finding, error) {
+		return nil, errors.New("unreadable")
+	}}
+	_, degraded, err = runCheck(context.Background(), failing, ".", checks.DefaultFilter, time.Second)
+	assert.EqualError(t, err, "unreadable")
+	assert.Nil(t, degraded, "errors are not degradation")
+
+	panicking := checks.Check{Name: "panicking", Run: func(string, checks.Filter) ([]parser.Finding, error) {
+		var lines []string
+		_ = lines[3]
+		return nil, nil
+	}}
+	findings, degraded, err = runCheck(context.Background(), panicking, ".", checks.DefaultFilter, time.Second)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+	require.NotNil(t, degraded)
+	assert.Equal(t, "panicking", degraded.Check)
+	assert.Contains(t, degraded.Reason, "panic: runtime error: index out of range")
+
+	release := make(chan struct{})
+	defer close(release)
+	hanging := checks.Check{Name: "hanging", Run: func(string, checks.Filter) ([]parser.Finding, error) {
+		<-release
+		return nil, nil
+	}}
+	_, degraded, err = runCheck(context.Background(), hanging, ".", checks.DefaultFilter, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, &parser.DegradedCheck{Check: "hanging", Reason: "timed out after 10ms"}, degraded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, degraded, err = runCheck(ctx, hanging, ".", checks.DefaultFilter, time.Minute)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, degraded)
+}
@@ -7,12 +7,13 @@ import (
 	"strings"
 
 	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
 )
 
 // CheckAccessControl scans for mint, burn, pause, and upgrade functions
 // that lack any access control modifier.
-func CheckAccessControl(target string) ([]parser.Finding, error) {
-	files, err := solidityFiles(target)
+func CheckAccessControl(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
 	if err != nil {
 		return nil, err
 	}
@@ -46,19 +47,6 @@ var sensitivePatterns = []struct {
 	{"selfdestruct", parser.SeverityCritical, "Unrestricted selfdestruct permanently destroys the contract."},
 }
 
-// accessModifiers are known Solidity/OpenZeppelin access guard signals.
-var accessModifiers = []string{
-	"onlyOwner",
-	"onlyRole",
-	"onlyAdmin",
-	"onlyMinter",
-	"onlyPauser",
-	"requiresAuth",
-	"restricted",
-	"auth",
-	"isOwner",
-}
-
 func checkAccessControlInFile(path string) ([]parser.Finding, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -68,6 +56,9 @@ func checkAccessControlInFile(path string) ([]parser.Finding, error) {
 
 	var findings []parser.Finding
 	lineNum := 0
+	// guard is the access modifier the file's other functions use, which
+	// the flagged ones are missing.
+	guard := ""
 
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
@@ -84,6 +75,9 @@ func checkAccessControlInFile(path string) ([]parser.Finding, error) {
 		if !strings.Contains(trimmed, "function ") {
 			continue
 		}
+		if guard == "" {
+			guard = accessModifier(trimmed)
+		}
 
 		// Check each sensitive function pattern
 		for _, sp := range sensitivePatterns {
@@ -121,14 +115,29 @@ func checkAccessControlInFile(path string) ([]parser.Finding, error) {
 					extractFunctionName(trimmed),
 				),
 				SWCRef: "SWC-105",
+				CWERef: rules.Default().CWE("custom-missing-access-control"),
 				References: []string{
 					"https://swcregistry.io/docs/SWC-105",
 					"https://docs.openzeppelin.com/contracts/4.x/access-control",
 				},
+				Evidence: []string{
+					fmt.Sprintf("function name matches sensitive pattern `%s` at line %d col %d",
+						sp.keyword, lineNum, strings.Index(strings.ToLower(line), "function "+sp.keyword)+1),
+					fmt.Sprintf("no access modifier on the declaration (looked for %s)",
+						strings.Join(rules.Default().Signature("access-modifier"), ", ")),
+					"visibility is not internal or private",
+				},
 			})
 		}
 	}
 
+	cause := "no access control modifier"
+	if guard != "" {
+		cause = "missing " + guard
+	}
+	for i := range findings {
+		findings[i].RootCause = cause
+	}
 	return findings, scanner.Err()
 }
 
@@ -145,12 +154,19 @@ func containsFunctionNamed(line, keyword string) bool {
 		(len(after) > 0 && after[0] >= 'A' && after[0] <= 'Z')
 }
 
+// hasAccessModifier reports whether the line carries a known Solidity/OpenZeppelin
+// access guard, as listed in the rules bundle's "access-modifier" signatures.
 func hasAccessModifier(line string) bool {
+	return accessModifier(line) != ""
+}
+
+// accessModifier returns the access guard on the line, or "".
+func accessModifier(line string) string {
 	lower := strings.ToLower(line)
-	for _, mod := range accessModifiers {
+	for _, mod := range rules.Default().Signature("access-modifier") {
 		if strings.Contains(lower, strings.ToLower(mod)) {
-			return true
+			return mod
 		}
 	}
-	return false
+	return ""
 }
\ No newline at end of file
@@ -33,10 +33,11 @@ contract Improper {
 	err = os.WriteFile(tmpFile, []byte(content), 0644)
 	require.NoError(t, err)
 
-	findings, err := CheckAccessControl(tmpFile)
+	findings, err := CheckAccessControl(tmpFile, DefaultFilter)
 	require.NoError(t, err)
 
 	assert.Len(t, findings, 1)
 	assert.Equal(t, "custom-missing-access-control", findings[0].Check)
 	assert.Contains(t, findings[0].Title, "mint")
+	assert.Equal(t, "missing onlyOwner", findings[0].RootCause, "the guard burn has is the one mint lacks")
 }
@@ -0,0 +1,216 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	// reservesRe matches the cached reserves of a constant-product pair, as
+	// in Uniswap V2's reserve0/reserve1 and getReserves().
+	reservesRe = regexp.MustCompile(`\b_?reserve[01AB]\b|\bgetReserves\s*\(`)
+	// amountOutRe matches swap parameters through which the caller names
+	// the output, which the pair then has to verify.
+	amountOutRe = regexp.MustCompile(`(?i)^amount\w*out$`)
+	// kProductRe matches a product of balances or reserves, the two sides
+	// of balance0Adjusted * balance1Adjusted >= reserve0 * reserve1.
+	kProductRe = regexp.MustCompile(`(?i)(balance|reserve)\w*\s*\)?\s*(\*|\.mul\()`)
+	// kNamedRe matches invariant checks through a named value or helper.
+	kNamedRe = regexp.MustCompile(`\b(_?[kK]|kLast|invariant|_?checkInvariant|_?checkK)\b`)
+	// kConditionRe matches the statements that enforce the invariant.
+	kConditionRe = regexp.MustCompile(`\b(require|assert|if)\s*\(`)
+	// feeMathRe matches the fee adjustment of a swap, e.g.
+	// balance0.mul(1000).sub(amount0In.mul(3)) or amountIn * (10000 - fee).
+	feeMathRe = regexp.MustCompile(`(?i)(\*|\.mul\()\s*\(?\s*(\d{3,5}|\w*fee\w*)\b|\b\w*fee\w*\s*\*`)
+	// reserveUpdateRe matches writing the stored reserves, directly or
+	// through Uniswap V2's _update; local copies such as _reserve0 are not.
+	reserveUpdateRe = regexp.MustCompile(`\b_update\s*\(|\breserve[01AB]\s*=[^=]`)
+	// reentrancyLockRe matches the reentrancy guards pairs use.
+	reentrancyLockRe = regexp.MustCompile(`^(lock|nonReentrant|noReentrancy)$`)
+)
+
+// CheckAMMMath flags constant-product pairs, typically Uniswap V2 forks,
+// whose swap math no longer holds the pool to its invariant: swaps that pay
+// out caller-chosen amounts without checking x * y >= k, fee adjustments
+// computed after the reserves are written, and skim and sync functions left
+// callable by anyone without that being a deliberate choice. Only contracts
+// that keep reserves are checked.
+func CheckAMMMath(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []parser.Finding
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" || !keepsReserves(file, c) {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if fn.Mutability != "" || fn.End <= fn.Line {
+					continue
+				}
+				if fn.Visibility != "external" && fn.Visibility != "public" && fn.Visibility != "" {
+					continue
+				}
+				switch {
+				case fn.Name == "swap":
+					findings = append(findings, swapFindings(file, path, c, fn, len(findings))...)
+				case fn.Name == "skim" || fn.Name == "sync":
+					if f, ok := skimSyncFinding(file, path, c, fn, len(findings)); ok {
+						findings = append(findings, f)
+					}
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// keepsReserves reports whether c caches pool reserves.
+func keepsReserves(file *solidity.File, c solidity.Contract) bool {
+	for n := c.Line; n <= c.End; n++ {
+		if reservesRe.MatchString(file.Line(n)) {
+			return true
+		}
+	}
+	return false
+}
+
+// swapFindings reports a swap that does not verify the invariant, and one
+// that applies its fee after writing the reserves.
+func swapFindings(file *solidity.File, path string, c solidity.Contract, fn solidity.Function, n int) []parser.Finding {
+	db := rules.Default()
+	var findings []parser.Finding
+
+	callerOutput := slices.ContainsFunc(fn.Params, func(p solidity.Param) bool { return amountOutRe.MatchString(p.Name) })
+	kChecked, update, fee := false, 0, 0
+	for l := fn.Line; l <= fn.End; l++ {
+		code := file.Line(l)
+		if kConditionRe.MatchString(code) && (kProductRe.MatchString(code) || kNamedRe.MatchString(code)) {
+			kChecked = true
+		}
+		if update == 0 && reserveUpdateRe.MatchString(code) {
+			update = l
+		}
+		if fee == 0 && feeMathRe.MatchString(code) {
+			fee = l
+		}
+	}
+
+	if callerOutput && !kChecked {
+		findings = append(findings, parser.Finding{
+			ID:     fmt.Sprintf("CUSTOM-AMM-%d", n+len(findings)+1),
+			Source: "custom",
+			Check:  "custom-amm-k-invariant",
+			Title:  fmt.Sprintf("Swap Without K-Invariant Check in %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — '%s' pays out output amounts chosen by the caller but never checks that the fee-adjusted balances keep x * y >= k. Anyone can swap for more than they put in and drain both reserves.",
+				path, fn.Line, fn.Name,
+			),
+			Severity:    parser.SeverityCritical,
+			Confidence:  "Medium",
+			File:        path,
+			Lines:       []int{fn.Line},
+			Remediation: db.Remediation("custom-amm-k-invariant"),
+			CWERef:      db.CWE("custom-amm-k-invariant"),
+			Evidence: []string{
+				"output amounts are parameters (" + outputParams(fn) + ")",
+				"no require, assert or if comparing a product of balances or reserves, k or kLast in the body",
+			},
+		})
+	}
+
+	if update != 0 && fee != 0 && fee > update {
+		findings = append(findings, parser.Finding{
+			ID:     fmt.Sprintf("CUSTOM-AMM-%d", n+len(findings)+1),
+			Source: "custom",
+			Check:  "custom-amm-fee-order",
+			Title:  fmt.Sprintf("Swap Fee Applied After Reserve Update in %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — '%s' writes the reserves at line %d before computing the fee at line %d. The fee-adjusted balances are then compared against reserves that already include the swap, so the fee is not charged and the invariant check passes for any trade.",
+				path, fee, fn.Name, update, fee,
+			),
+			Severity:    parser.SeverityHigh,
+			Confidence:  "Low",
+			File:        path,
+			Lines:       []int{fee},
+			Remediation: db.Remediation("custom-amm-fee-order"),
+			CWERef:      db.CWE("custom-amm-fee-order"),
+			Evidence: []string{
+				fmt.Sprintf("reserves written at line %d: `%s`", update, strings.TrimSpace(file.Line(update))),
+				fmt.Sprintf("fee math at line %d: `%s`", fee, strings.TrimSpace(file.Line(fee))),
+			},
+		})
+	}
+	return findings
+}
+
+// outputParams lists fn's output amount parameters.
+func outputParams(fn solidity.Function) string {
+	var names []string
+	for _, p := range fn.Params {
+		if amountOutRe.MatchString(p.Name) {
+			names = append(names, p.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// skimSyncFinding reports a skim or sync anyone can call. Uniswap V2 leaves
+// both public on purpose, which is only safe while the pool's balances
+// cannot change outside swaps and liquidity changes; forks that hold
+// rebasing or fee-on-transfer tokens, or price from balances, must decide
+// who may call them. skim pays out to any address, so it is Medium when it
+// also lacks a reentrancy lock.
+func skimSyncFinding(file *solidity.File, path string, c solidity.Contract, fn solidity.Function, n int) (parser.Finding, bool) {
+	if guarded(file, fn) {
+		return parser.Finding{}, false
+	}
+	db := rules.Default()
+
+	locked := slices.ContainsFunc(fn.Modifiers, reentrancyLockRe.MatchString)
+	effect := "sets the reserves to the current balances, so donations, rebases and transfer fees move the price"
+	if fn.Name == "skim" {
+		effect = "sends any balance above the reserves to an address of the caller's choosing"
+	}
+	evidence := []string{"no access modifier or msg.sender check: anyone can call " + fn.Name + "()"}
+	severity := parser.SeverityLow
+	if locked {
+		evidence = append(evidence, "guarded against reentrancy by a lock modifier")
+	} else {
+		evidence = append(evidence, "no lock or nonReentrant modifier")
+		if fn.Name == "skim" {
+			severity = parser.SeverityMedium
+		}
+	}
+
+	return parser.Finding{
+		ID:     fmt.Sprintf("CUSTOM-AMM-%d", n+1),
+		Source: "custom",
+		Check:  "custom-amm-skim-sync",
+		Title:  fmt.Sprintf("Unrestricted %s.%s()", c.Name, fn.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — '%s' can be called by anyone and %s. This is safe in Uniswap V2 only because its balances change through swaps and liquidity alone; confirm it holds for the tokens and pricing of this pool.",
+			path, fn.Line, fn.Name, effect,
+		),
+		Severity:    severity,
+		Confidence:  "Low",
+		File:        path,
+		Lines:       []int{fn.Line},
+		Remediation: db.Remediation("custom-amm-skim-sync"),
+		CWERef:      db.CWE("custom-amm-skim-sync"),
+		Evidence:    evidence,
+	}, true
+}
@@ -0,0 +1,97 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckAMMMath(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Pair {
+    uint112 private reserve0;
+    uint112 private reserve1;
+
+    function swap(uint amount0Out, uint amount1Out, address to, bytes calldata data) external lock {
+        _pay(amount0Out, amount1Out, to);
+        uint balance0Adjusted = _balance0() * 1000 - _in0() * 3;
+        uint balance1Adjusted = _balance1() * 1000 - _in1() * 3;
+        require(balance0Adjusted * balance1Adjusted >= uint(reserve0) * reserve1 * 1000**2, "UniswapV2: K");
+        _update(_balance0(), _balance1());
+    }
+
+    function skim(address to) external lock {
+        _pay(_balance0() - reserve0, _balance1() - reserve1, to);
+    }
+
+    function sync() external {
+        _update(_balance0(), _balance1());
+    }
+}
+
+contract Router {
+    function swap(uint amountOut, address to) external {
+        pair.swap(0, amountOut, to, "");
+    }
+
+    function skim(address to) external {
+        pair.skim(to);
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pair.sol"), []byte(content), 0644))
+
+	findings, err := CheckAMMMath(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "the K check and fee order hold, and Router keeps no reserves")
+
+	skim := findings[0]
+	assert.Equal(t, "custom-amm-skim-sync", skim.Check)
+	assert.Equal(t, parser.SeverityLow, skim.Severity, "skim is locked")
+	assert.Equal(t, []int{15}, skim.Lines)
+	assert.Contains(t, skim.Evidence, "guarded against reentrancy by a lock modifier")
+
+	sync := findings[1]
+	assert.Equal(t, "Unrestricted Pair.sync()", sync.Title)
+	assert.Equal(t, parser.SeverityLow, sync.Severity)
+	assert.Contains(t, sync.Description, "donations, rebases and transfer fees move the price")
+}
+
+func TestCheckAMMMath_Swap(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Pair {
+    uint112 private reserve0;
+    uint112 private reserve1;
+
+    function swap(uint amount0Out, uint amount1Out, address to) external {
+        _pay(amount0Out, amount1Out, to);
+        reserve0 = uint112(_balance0());
+        reserve1 = uint112(_balance1());
+        uint fee0 = _in0() * swapFee / 10000;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pair.sol"), []byte(content), 0644))
+
+	findings, err := CheckAMMMath(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	k := findings[0]
+	assert.Equal(t, "custom-amm-k-invariant", k.Check)
+	assert.Equal(t, parser.SeverityCritical, k.Severity)
+	assert.Equal(t, "output amounts are parameters (amount0Out, amount1Out)", k.Evidence[0])
+
+	fee := findings[1]
+	assert.Equal(t, "custom-amm-fee-order", fee.Check)
+	assert.Equal(t, []int{11}, fee.Lines)
+	assert.Equal(t, "reserves written at line 9: `reserve0 = uint112(_balance0());`", fee.Evidence[0])
+}
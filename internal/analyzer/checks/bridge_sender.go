@@ -0,0 +1,128 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+// messageHandlers are the entry points through which bridges and messaging
+// protocols deliver cross-domain messages: LayerZero, Chainlink CCIP,
+// Hyperlane, Wormhole, Connext, Polygon's FxPortal and the OP Stack and
+// Arbitrum token bridges. Internal overrides such as _lzReceive are left
+// out, since the base contract authenticates before calling them.
+var messageHandlers = map[string]bool{
+	"lzReceive":                true,
+	"ccipReceive":              true,
+	"handle":                   true,
+	"receiveMessage":           true,
+	"receiveWormholeMessages":  true,
+	"xReceive":                 true,
+	"onMessageReceived":        true,
+	"processMessageFromRoot":   true,
+	"processMessageFromChild":  true,
+	"finalizeDeposit":          true,
+	"finalizeInboundTransfer":  true,
+	"finalizeBridgeERC20":      true,
+	"finalizeBridgeETH":        true,
+	"relayMessage":             true,
+	"executeMessage":           true,
+	"receiveCrossChainMessage": true,
+}
+
+var (
+	// originCheckRe matches reading or comparing the sender on the source
+	// domain: the OP Stack's xDomainMessageSender(), LayerZero's trusted
+	// remotes and peers, a decoded sender compared to a trusted one, or an
+	// allowlist lookup.
+	originCheckRe = regexp.MustCompile(`(?i)(xDomainMessageSender|crossDomainMessageSender|l2ToL1Sender)\s*\(|trustedRemote|peers?\s*\[|_?origin\.sender|\w*sender\s*[!=]=|[!=]=\s*\w*sender\b|\b(isTrusted|isAllowed|allowlisted|whitelisted)\w*\s*[\[(]`)
+	// originModifierRe matches modifiers that authenticate the cross-domain
+	// sender, e.g. OpenZeppelin's onlyFromCrossDomainAccount.
+	originModifierRe = regexp.MustCompile(`(?i)crossdomain|crosschain|trusted|peer|remote|sender`)
+)
+
+// CheckBridgeSenders flags cross-domain message handlers that do not
+// authenticate both hops of a message: the local messenger calling them
+// (msg.sender is the endpoint, router or messenger contract) and the sender
+// on the source domain (the counterpart contract that sent the message).
+// Without the first anyone can call the handler with a forged message;
+// without the second anyone can send one through the bridge.
+func CheckBridgeSenders(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if !messageHandlers[fn.Name] || fn.Mutability != "" || fn.End <= fn.Line {
+					continue
+				}
+				if fn.Visibility != "external" && fn.Visibility != "public" && fn.Visibility != "" {
+					continue
+				}
+				messenger, origin := guarded(file, fn), originChecked(file, fn)
+				if messenger && origin {
+					continue
+				}
+
+				var missing, evidence []string
+				severity := parser.SeverityHigh
+				if !messenger {
+					severity = parser.SeverityCritical
+					missing = append(missing, "the messenger (msg.sender)")
+					evidence = append(evidence, "no messenger check: no guard modifier and no msg.sender comparison, so anyone can call the handler directly")
+				}
+				if !origin {
+					missing = append(missing, "the cross-domain sender")
+					evidence = append(evidence, "no cross-domain sender check: no xDomainMessageSender(), trusted remote, peer or sender comparison in the body")
+				}
+				findings = append(findings, parser.Finding{
+					ID:     fmt.Sprintf("CUSTOM-XDOMAIN-%d", len(findings)+1),
+					Source: "custom",
+					Check:  "custom-cross-domain-sender",
+					Title:  fmt.Sprintf("Unauthenticated Cross-Domain Message in %s.%s()", c.Name, fn.Name),
+					Description: fmt.Sprintf(
+						"%s:%d — Message handler '%s' does not authenticate %s. Forged messages can mint, release or unlock funds on this domain.",
+						path, fn.Line, fn.Name, strings.Join(missing, " or "),
+					),
+					Severity:    severity,
+					Confidence:  "Medium",
+					File:        path,
+					Lines:       []int{fn.Line},
+					Remediation: db.Remediation("custom-cross-domain-sender"),
+					CWERef:      db.CWE("custom-cross-domain-sender"),
+					Evidence:    evidence,
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// originChecked reports whether fn authenticates the sender on the source
+// domain, by a modifier or in its body. The signature is skipped, as
+// handlers take the source sender as a parameter.
+func originChecked(file *solidity.File, fn solidity.Function) bool {
+	for _, m := range fn.Modifiers {
+		if originModifierRe.MatchString(m) {
+			return true
+		}
+	}
+	body := strings.NewReplacer("msg.sender", "", "_msgSender()", "").Replace(functionBody(file, fn))
+	return originCheckRe.MatchString(body)
+}
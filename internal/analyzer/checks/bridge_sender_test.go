@@ -0,0 +1,65 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckBridgeSenders(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Receiver {
+    address public endpoint;
+    address public remote;
+
+    function lzReceive(uint16 srcChainId, bytes calldata srcAddress, uint64 nonce, bytes calldata payload) external {
+        _credit(payload);
+    }
+
+    function ccipReceive(Any2EVMMessage calldata message) external {
+        require(msg.sender == endpoint, "not router");
+        _credit(message.data);
+    }
+
+    function handle(uint32 origin, bytes32 sender, bytes calldata body) external {
+        require(msg.sender == endpoint, "not mailbox");
+        require(sender == bytes32(uint256(uint160(remote))), "not remote");
+        _credit(body);
+    }
+
+    function processMessageFromRoot(uint256 stateId, address rootMessageSender, bytes calldata data) external onlyFxChild validateSender(rootMessageSender) {
+        _credit(data);
+    }
+
+    function _lzReceive(bytes calldata payload) internal {
+        _credit(payload);
+    }
+
+    function _credit(bytes calldata data) internal {}
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Receiver.sol"), []byte(content), 0644))
+
+	findings, err := CheckBridgeSenders(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "handle and processMessageFromRoot check both hops, _lzReceive is internal")
+
+	lz := findings[0]
+	assert.Equal(t, "custom-cross-domain-sender", lz.Check)
+	assert.Equal(t, parser.SeverityCritical, lz.Severity, "anyone can call it directly")
+	assert.Equal(t, []int{7}, lz.Lines)
+	assert.Contains(t, lz.Description, "does not authenticate the messenger (msg.sender) or the cross-domain sender")
+	assert.Len(t, lz.Evidence, 2, "srcAddress in the signature is not a check")
+
+	ccip := findings[1]
+	assert.Equal(t, parser.SeverityHigh, ccip.Severity)
+	assert.Equal(t, []int{11}, ccip.Lines)
+	assert.Contains(t, ccip.Description, "does not authenticate the cross-domain sender")
+	assert.NotEmpty(t, ccip.Remediation)
+}
@@ -0,0 +1,217 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// minCommentedCodeLines is how many code-like lines a comment block needs
+// before it is reported as commented-out code.
+const minCommentedCodeLines = 5
+
+var (
+	todoRe     = regexp.MustCompile(`\b(?i:TODO|FIXME|HACK)\b|\bXXX\b`)
+	codeLikeRe = regexp.MustCompile(`^(function\s|modifier\s|event\s|if\s*\(|}?\s*else\b|for\s*\(|while\s*\(|require\(|assert\(|revert\b|emit\s|return\b|u?int\d*\s|address\s|bool\s|bytes\d*\s|mapping\s*\(|contract\s|import\s|pragma\s)|[;{}]$`)
+)
+
+// commentLine is the comment text found on one source line.
+type commentLine struct {
+	Line    int
+	Text    string
+	Whole   bool // the line holds nothing but comment
+	NatSpec bool // ///, /** documentation comments
+}
+
+// CheckComments surfaces TODO/FIXME/HACK markers and large commented-out code
+// blocks in production contracts. Both often mark security logic that is known
+// to be unfinished or was disabled during development and never restored.
+// Tests and deployment scripts are skipped.
+func CheckComments(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	root := source.ProjectRoot(target)
+	db := rules.Default()
+
+	var findings []parser.Finding
+	for _, path := range files {
+		if isTestOrScript(root, path) {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		comments := extractComments(string(data))
+
+		for _, c := range comments {
+			m := todoRe.FindStringIndex(c.Text)
+			if m == nil || (m[1] < len(c.Text) && !strings.ContainsAny(c.Text[m[1]:m[1]+1], ": \t(")) {
+				continue
+			}
+			marker := strings.ToUpper(c.Text[m[0]:m[1]])
+			note := strings.TrimLeft(strings.TrimSpace(c.Text[m[1]:]), ":-) ")
+			title := marker + " Comment"
+			if note != "" {
+				title += ": " + truncate(note, 60)
+			}
+			findings = append(findings, parser.Finding{
+				ID:          fmt.Sprintf("CUSTOM-COMMENT-%d", len(findings)+1),
+				Source:      "custom",
+				Check:       "custom-todo-comment",
+				Title:       title,
+				Description: fmt.Sprintf("%s line %d: %q. Unfinished-work markers in production code frequently flag missing validation or known bugs.", path, c.Line, strings.TrimSpace(c.Text)),
+				Severity:    parser.SeverityInformational,
+				Confidence:  "High",
+				File:        path,
+				Lines:       []int{c.Line},
+				Remediation: db.Remediation("custom-todo-comment"),
+				CWERef:      db.CWE("custom-todo-comment"),
+				Evidence:    []string{fmt.Sprintf("marker `%s` in comment at line %d", c.Text[m[0]:m[1]], c.Line)},
+			})
+		}
+
+		for _, block := range commentedOutCode(comments) {
+			codeLines := block[2]
+			findings = append(findings, parser.Finding{
+				ID:     fmt.Sprintf("CUSTOM-COMMENT-%d", len(findings)+1),
+				Source: "custom",
+				Check:  "custom-commented-out-code",
+				Title:  fmt.Sprintf("Commented-Out Code (%d lines)", block[1]-block[0]+1),
+				Description: fmt.Sprintf("%s lines %d-%d contain commented-out code. Disabled logic (often checks or "+
+					"modifiers) obscures what actually ships and may be restored without review.", path, block[0], block[1]),
+				Severity:    parser.SeverityInformational,
+				Confidence:  "Medium",
+				File:        path,
+				Lines:       []int{block[0], block[1]},
+				Remediation: db.Remediation("custom-commented-out-code"),
+				CWERef:      db.CWE("custom-commented-out-code"),
+				Evidence: []string{
+					fmt.Sprintf("%d consecutive comment lines, %d of them code-like (statements, declarations, braces)",
+						block[1]-block[0]+1, codeLines),
+				},
+			})
+		}
+	}
+	return findings, nil
+}
+
+// commentedOutCode returns [first, last, code-like lines] for each run of
+// consecutive whole-line, non-NatSpec comments containing at least
+// minCommentedCodeLines code-like lines, with code making up at least half
+// of the block.
+func commentedOutCode(comments []commentLine) [][3]int {
+	var blocks [][3]int
+	start, last, code, total := 0, 0, 0, 0
+	flush := func() {
+		if code >= minCommentedCodeLines && code*2 >= total {
+			blocks = append(blocks, [3]int{start, last, code})
+		}
+		start, code, total = 0, 0, 0
+	}
+	for _, c := range comments {
+		if !c.Whole || c.NatSpec {
+			flush()
+			continue
+		}
+		if start != 0 && c.Line != last+1 {
+			flush()
+		}
+		if start == 0 {
+			start = c.Line
+		}
+		last = c.Line
+		text := strings.TrimSpace(c.Text)
+		if text == "" {
+			continue
+		}
+		total++
+		if codeLikeRe.MatchString(text) {
+			code++
+		}
+	}
+	flush()
+	return blocks
+}
+
+// extractComments returns the comment text on each line of Solidity source,
+// tracking block comments across lines and ignoring comment markers inside
+// string literals.
+func extractComments(text string) []commentLine {
+	var out []commentLine
+	inBlock, blockNatSpec := false, false
+	for i, line := range strings.Split(text, "\n") {
+		lineNum := i + 1
+		rest := line
+		codeBefore := false
+		for rest != "" {
+			if inBlock {
+				body, after, closed := strings.Cut(rest, "*/")
+				body = strings.TrimPrefix(strings.TrimSpace(body), "*")
+				out = append(out, commentLine{Line: lineNum, Text: body, NatSpec: blockNatSpec,
+					Whole: !codeBefore && (!closed || strings.TrimSpace(after) == "")})
+				if !closed {
+					break
+				}
+				inBlock = false
+				rest = after
+				continue
+			}
+			idx, kind := commentStart(rest)
+			if idx < 0 {
+				break
+			}
+			if strings.TrimSpace(rest[:idx]) != "" {
+				codeBefore = true
+			}
+			if kind == "//" {
+				body := rest[idx+2:]
+				out = append(out, commentLine{Line: lineNum, Text: strings.TrimPrefix(body, "/"),
+					Whole: !codeBefore, NatSpec: strings.HasPrefix(body, "/")})
+				break
+			}
+			inBlock = true
+			blockNatSpec = strings.HasPrefix(rest[idx+2:], "*")
+			rest = strings.TrimPrefix(rest[idx+2:], "*")
+		}
+	}
+	return out
+}
+
+// commentStart finds the first // or /* outside a string literal.
+func commentStart(s string) (int, string) {
+	var quote byte
+	for i := 0; i < len(s)-1; i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '/' && s[i+1] == '/':
+			return i, "//"
+		case c == '/' && s[i+1] == '*':
+			return i, "/*"
+		}
+	}
+	return -1, ""
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
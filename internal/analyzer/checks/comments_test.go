@@ -0,0 +1,52 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckComments(t *testing.T) {
+	content := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+/// @notice TODO-free NatSpec: returns the balance; see docs { }
+contract Vault {
+    string constant URL = "http://example.com // TODO not a comment";
+
+    function withdraw(uint256 amount) external {
+        // TODO: check caller is owner
+        // function oldWithdraw(uint256 amount) external {
+        //     require(balances[msg.sender] >= amount);
+        //     balances[msg.sender] -= amount;
+        //     (bool ok, ) = msg.sender.call{value: amount}("");
+        //     require(ok);
+        // }
+        payable(msg.sender).transfer(amount); // FIXME reentrancy?
+    }
+
+    /*
+     * This explains the design in prose.
+     * Nothing here is code.
+     */
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Vault.sol")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	findings, err := CheckComments(path, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 3)
+
+	assert.Equal(t, "custom-todo-comment", findings[0].Check)
+	assert.Equal(t, "TODO Comment: check caller is owner", findings[0].Title)
+	assert.Equal(t, []int{9}, findings[0].Lines)
+	assert.Equal(t, "FIXME Comment: reentrancy?", findings[1].Title)
+
+	assert.Equal(t, "custom-commented-out-code", findings[2].Check)
+	assert.Equal(t, []int{9, 15}, findings[2].Lines)
+}
@@ -0,0 +1,146 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/project"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// maxSaneOptimizerRuns is the point past which a larger runs value no longer
+// changes solc's output meaningfully but still signals a copy-paste mistake.
+const maxSaneOptimizerRuns = 1_000_000
+
+// stackTooDeepRe matches comments and identifiers left behind by manual
+// stack-too-deep workarounds (scoping blocks, packed structs, helper splits).
+var stackTooDeepRe = regexp.MustCompile(`(?i)stack[\s_-]*too[\s_-]*deep`)
+
+// CheckCompilerSettings reviews the project's compiler configuration
+// (foundry.toml or hardhat.config) against its sources: unusual optimizer runs,
+// stack-too-deep workarounds without viaIR, and pragmas the configured solc
+// version cannot satisfy. Projects without a framework config are skipped.
+func CheckCompilerSettings(target string, filter Filter) ([]parser.Finding, error) {
+	settings, err := project.Load(source.ProjectRoot(target))
+	if err != nil || settings == nil {
+		return nil, err
+	}
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+
+	db := rules.Default()
+	var findings []parser.Finding
+	add := func(check, title, description string, severity parser.Severity, file string, line int, evidence ...string) {
+		f := parser.Finding{
+			ID:          fmt.Sprintf("CUSTOM-COMPILER-%d", len(findings)+1),
+			Source:      "custom",
+			Check:       check,
+			Title:       title,
+			Description: description,
+			Severity:    severity,
+			Confidence:  "High",
+			File:        file,
+			Remediation: db.Remediation(check),
+			SWCRef:      db.SWC(check),
+			CWERef:      db.CWE(check),
+			Evidence:    evidence,
+		}
+		if line > 0 {
+			f.Lines = []int{line}
+		}
+		findings = append(findings, f)
+	}
+
+	runs := settings.OptimizerRuns
+	switch {
+	case settings.OptimizerEnabled && (runs == 0 || runs > maxSaneOptimizerRuns):
+		add("custom-optimizer-runs", "Unusual Optimizer Runs Value",
+			fmt.Sprintf("%s sets optimizer runs to %d. Typical values are 200 (balanced) up to 10,000+ for "+
+				"frequently called contracts; 0 or values above %d are usually a mistake and skew gas trade-offs.",
+				settings.ConfigFile, runs, maxSaneOptimizerRuns),
+			parser.SeverityInformational, settings.ConfigFile, settings.Lines["optimizer_runs"],
+			fmt.Sprintf("optimizer runs = %d at %s:%d", runs, settings.ConfigFile, settings.Lines["optimizer_runs"]),
+			fmt.Sprintf("expected 1..%d", maxSaneOptimizerRuns))
+	case !settings.OptimizerEnabled && runs > 0:
+		add("custom-optimizer-runs", "Optimizer Runs Set But Optimizer Disabled",
+			fmt.Sprintf("%s sets optimizer runs to %d but the optimizer is disabled, so the value has no effect. "+
+				"Confirm the deployed bytecode is meant to be unoptimized.", settings.ConfigFile, runs),
+			parser.SeverityInformational, settings.ConfigFile, settings.Lines["optimizer_runs"],
+			fmt.Sprintf("optimizer runs = %d at %s:%d", runs, settings.ConfigFile, settings.Lines["optimizer_runs"]),
+			"optimizer disabled")
+	}
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		text := string(data)
+
+		if !settings.ViaIR {
+			if loc := stackTooDeepRe.FindStringIndex(text); loc != nil {
+				add("custom-missing-via-ir", "Stack-Too-Deep Workaround Without viaIR",
+					fmt.Sprintf("%s references a stack-too-deep workaround, but %s does not enable viaIR. "+
+						"The IR pipeline removes most stack limits, so manual restructuring that obscures the "+
+						"code may be unnecessary.", path, settings.ConfigFile),
+					parser.SeverityInformational, path, lineOf(text, loc[0]),
+					fmt.Sprintf("pattern `%s` at line %d", text[loc[0]:loc[1]], lineOf(text, loc[0])),
+					fmt.Sprintf("viaIR not enabled in %s", settings.ConfigFile))
+			}
+		}
+
+		if len(settings.SolcVersions) > 0 {
+			if constraint := source.Pragma(text); constraint != "" && !anySatisfies(settings.SolcVersions, constraint) {
+				add("custom-pragma-mismatch", "Pragma Incompatible With Configured Compiler",
+					fmt.Sprintf("%s requires solidity %s, but %s configures solc %v. The file cannot compile "+
+						"with the configured toolchain, or is being built with a compiler it was not written for.",
+						path, constraint, settings.ConfigFile, settings.SolcVersions),
+					parser.SeverityLow, path, pragmaLine(text),
+					fmt.Sprintf("pragma solidity %s at line %d", constraint, pragmaLine(text)),
+					fmt.Sprintf("configured solc %v at %s:%d", settings.SolcVersions, settings.ConfigFile, settings.Lines["solc"]))
+			}
+		}
+	}
+	return findings, nil
+}
+
+// anySatisfies reports whether any configured version meets the constraint.
+// Unparseable constraints are given the benefit of the doubt.
+func anySatisfies(versions []string, constraint string) bool {
+	for _, s := range versions {
+		v, err := solidity.ParseVersion(s)
+		if err != nil {
+			return true
+		}
+		ok, err := v.Satisfies(constraint)
+		if err != nil || ok {
+			return true
+		}
+	}
+	return false
+}
+
+var pragmaRe = regexp.MustCompile(`(?m)^\s*pragma\s+solidity`)
+
+func pragmaLine(text string) int {
+	if loc := pragmaRe.FindStringIndex(text); loc != nil {
+		return lineOf(text, loc[0])
+	}
+	return 0
+}
+
+func lineOf(text string, offset int) int {
+	line := 1
+	for _, c := range text[:offset] {
+		if c == '\n' {
+			line++
+		}
+	}
+	return line
+}
@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCompilerSettings(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foundry.toml"), []byte(`[profile.default]
+solc = "0.8.24"
+optimizer = true
+optimizer_runs = 0
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "Old.sol"), []byte(`pragma solidity ^0.7.6;
+contract Old {}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "Pool.sol"), []byte(`pragma solidity ^0.8.20;
+contract Pool {
+    function swap() external {
+        { // scope to avoid stack too deep
+        }
+    }
+}
+`), 0644))
+
+	findings, err := CheckCompilerSettings(filepath.Join(dir, "src"), DefaultFilter)
+	require.NoError(t, err)
+
+	byCheck := map[string]int{}
+	for _, f := range findings {
+		byCheck[f.Check]++
+	}
+	assert.Equal(t, map[string]int{
+		"custom-optimizer-runs":  1,
+		"custom-missing-via-ir":  1,
+		"custom-pragma-mismatch": 1,
+	}, byCheck)
+}
+
+func TestCheckCompilerSettings_NoProject(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "A.sol"), []byte("pragma solidity 0.4.24;\ncontract A {}\n"), 0644))
+
+	findings, err := CheckCompilerSettings(dir, DefaultFilter)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
@@ -0,0 +1,468 @@
+package checks
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	// terminatorRe matches statements after which nothing in the same
+	// block runs.
+	terminatorRe  = regexp.MustCompile(`^(return\b|revert\b|throw\b|break\b|continue\b|selfdestruct\s*\(|assert\s*\(\s*false\s*\)|require\s*\(\s*false\b)`)
+	placeholderRe = regexp.MustCompile(`(^|[^\w$])_$`)
+	constantDefRe = regexp.MustCompile(`\bconstant\s+([A-Za-z_$][\w$]*)\s*=\s*([^;]+);`)
+	numberRe      = regexp.MustCompile(`^(0x[0-9a-fA-F_]+|[0-9_]+(?:e[0-9]+)?)(?:\s+(wei|gwei|ether|seconds|minutes|hours|days|weeks))?$`)
+	comparisonRe  = regexp.MustCompile(`^([\w$.]+)\s*(==|!=|<=|>=|<|>)\s*([\w$.]+)$`)
+	controlRe     = regexp.MustCompile(`^(?:else\s+)?(if|while|require|assert)\s*\(`)
+)
+
+var units = map[string]int64{
+	"": 1, "wei": 1, "gwei": 1e9, "ether": 1e18,
+	"seconds": 1, "minutes": 60, "hours": 3600, "days": 86400, "weeks": 604800,
+}
+
+// CheckDeadCode flags code that can never run or never matters: statements
+// after an unconditional return, revert, break or continue in the same
+// block; if, while, require and assert conditions that are always true or
+// always false given the contract's constants (or an unsigned value
+// compared with zero); and modifiers that never reach "_;", which make the
+// functions they guard succeed without running their bodies.
+func CheckDeadCode(target string, filter Filter) ([]parser.Finding, error) {
+	paths, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-DEAD-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	var files []*solidity.File
+	// Functions applying each modifier, across the target.
+	appliedBy := map[string][]string{}
+	for _, path := range paths {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		files = append(files, file)
+		for _, c := range file.Contracts {
+			for _, fn := range c.Functions {
+				for _, m := range fn.Modifiers {
+					label := c.Name + "." + fn.Name + "()"
+					if fn.Payable {
+						label += " (payable)"
+					}
+					appliedBy[m] = append(appliedBy[m], label)
+				}
+			}
+		}
+	}
+
+	for _, file := range files {
+		for _, c := range file.Contracts {
+			if c.Kind == "interface" {
+				continue
+			}
+			constants := constantValues(file, c)
+			for _, fn := range c.Functions {
+				if dead, after, ok := unreachable(file, fn); ok {
+					add(parser.Finding{
+						Check: "custom-unreachable-code",
+						Title: fmt.Sprintf("Unreachable Code: %s.%s", c.Name, fn.Name),
+						Description: fmt.Sprintf(
+							"%s:%d — `%s` can never run: it follows `%s` (line %d) in the same block, which always leaves it. The code is either left over or meant to run before the exit.",
+							file.Path, dead.Line, oneLine(dead.Text), oneLine(after.Text), after.Line,
+						),
+						Severity:   parser.SeverityLow,
+						Confidence: "High",
+						File:       file.Path,
+						Lines:      []int{dead.Line},
+						Function:   fn.Name,
+						Evidence:   []string{fmt.Sprintf("line %d: %s", after.Line, oneLine(after.Text))},
+					})
+				}
+				for _, f := range constantConditions(file, c, fn, constants) {
+					add(f)
+				}
+			}
+			for _, mod := range c.Modifiers {
+				if f, ok := missingPlaceholder(file, c, mod, appliedBy[mod.Name]); ok {
+					add(f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// scanBlocks calls visit with each statement of fn's body and the depth of
+// the block it is in (1 for the body itself). A statement ends at ";", or
+// at the "{" or "}" after it, which is passed as end. String literals are
+// skipped, so braces and semicolons inside them are ignored.
+func scanBlocks(file *solidity.File, fn solidity.Function, visit func(s statement, depth int, end rune)) {
+	var cur strings.Builder
+	depth, start := 0, 0
+	var quote rune
+	for n := fn.Line; n <= fn.End; n++ {
+		line := []rune(file.Line(n) + "\n")
+		for i := 0; i < len(line); i++ {
+			r := line[i]
+			switch {
+			case quote != 0:
+				if r == '\\' && i+1 < len(line) {
+					cur.WriteRune(r)
+					i++
+					r = line[i]
+				} else if r == quote {
+					quote = 0
+				}
+			case r == '"' || r == '\'':
+				quote = r
+			case r == ';' || r == '{' || r == '}':
+				if text := strings.TrimSpace(cur.String()); depth > 0 {
+					visit(statement{Text: text, Line: start}, depth, r)
+				}
+				cur.Reset()
+				switch r {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				}
+				continue
+			}
+			if strings.TrimSpace(cur.String()) == "" && !isSpace(r) {
+				start = n
+			}
+			cur.WriteRune(r)
+		}
+	}
+}
+
+func isSpace(r rune) bool { return r == ' ' || r == '\t' || r == '\n' || r == '\r' }
+
+// unreachable returns the first statement of fn that follows an
+// unconditional exit in the same block, and that exit.
+func unreachable(file *solidity.File, fn solidity.Function) (dead, after statement, found bool) {
+	exits := map[int]statement{}
+	scanBlocks(file, fn, func(s statement, depth int, end rune) {
+		if found {
+			return
+		}
+		if exit, ok := exits[depth]; ok && s.Text != "" {
+			dead, after, found = s, exit, true
+			return
+		}
+		if end == ';' && terminatorRe.MatchString(s.Text) {
+			exits[depth] = s
+		}
+		// A closed block's exits say nothing about the statements after
+		// it, and a new block starts without any.
+		if end == '}' || end == '{' {
+			delete(exits, depth+1)
+		}
+		if end == '}' {
+			delete(exits, depth)
+		}
+	})
+	return dead, after, found
+}
+
+// constantValues returns the values of the constants visible in c whose
+// value is a literal number or boolean (or another such constant): its
+// own and those declared at file level.
+func constantValues(file *solidity.File, c solidity.Contract) map[string]string {
+	raw := map[string]string{}
+	lines := strings.Split(file.Code, "\n")
+	for n := 1; n <= len(lines); n++ {
+		if !c.Contains(n) {
+			if enclosing, _ := file.At(n); enclosing != nil {
+				continue
+			}
+		}
+		for _, m := range constantDefRe.FindAllStringSubmatch(lines[n-1], -1) {
+			raw[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+	return raw
+}
+
+// literal resolves a literal or a constant name to a boolean ("true",
+// "false") or an integer.
+func literal(constants map[string]string, expr string, depth int) (bool, *big.Int, bool) {
+	expr = strings.TrimSpace(expr)
+	if depth > 4 {
+		return false, nil, false
+	}
+	switch expr {
+	case "true":
+		return true, nil, true
+	case "false":
+		return false, nil, true
+	}
+	if m := numberRe.FindStringSubmatch(expr); m != nil {
+		digits := strings.ReplaceAll(m[1], "_", "")
+		n := new(big.Int)
+		if mantissa, exp, ok := strings.Cut(digits, "e"); ok && !strings.HasPrefix(digits, "0x") {
+			e, _ := new(big.Int).SetString(exp, 10)
+			n.SetString(mantissa, 10)
+			n.Mul(n, new(big.Int).Exp(big.NewInt(10), e, nil))
+		} else if _, ok := n.SetString(digits, 0); !ok {
+			return false, nil, false
+		}
+		return false, n.Mul(n, big.NewInt(units[m[2]])), true
+	}
+	if value, ok := constants[expr]; ok {
+		return literal(constants, value, depth+1)
+	}
+	return false, nil, false
+}
+
+// evaluate returns the value of a condition made only of literals and
+// constants, or comparing an unsigned variable with zero.
+func evaluate(constants map[string]string, unsigned func(string) bool, cond string) (value, ok bool) {
+	cond = strings.TrimSpace(cond)
+	for strings.HasPrefix(cond, "(") && strings.HasSuffix(cond, ")") && balanced(cond[1:len(cond)-1]) {
+		cond = strings.TrimSpace(cond[1 : len(cond)-1])
+	}
+	if rest, negated := strings.CutPrefix(cond, "!"); negated && !strings.HasPrefix(rest, "=") {
+		value, ok := evaluate(constants, unsigned, rest)
+		return !value, ok
+	}
+	if b, n, ok := literal(constants, cond, 0); ok && n == nil {
+		return b, true
+	}
+	m := comparisonRe.FindStringSubmatch(cond)
+	if m == nil {
+		return false, false
+	}
+	left, op, right := m[1], m[2], m[3]
+	_, l, lok := literal(constants, left, 0)
+	_, r, rok := literal(constants, right, 0)
+	switch {
+	case lok && rok && l != nil && r != nil:
+		cmp := l.Cmp(r)
+		switch op {
+		case "==":
+			return cmp == 0, true
+		case "!=":
+			return cmp != 0, true
+		case "<":
+			return cmp < 0, true
+		case "<=":
+			return cmp <= 0, true
+		case ">":
+			return cmp > 0, true
+		default:
+			return cmp >= 0, true
+		}
+	case rok && r != nil && r.Sign() == 0 && unsigned(left) && (op == ">=" || op == "<"):
+		return op == ">=", true
+	case lok && l != nil && l.Sign() == 0 && unsigned(right) && (op == "<=" || op == ">"):
+		return op == "<=", true
+	}
+	return false, false
+}
+
+// balanced reports whether the parentheses of s pair up, so that "(a) &&
+// (b)" is not mistaken for one parenthesized expression.
+func balanced(s string) bool {
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// constantConditions reports the conditions of fn whose value never
+// changes, except the deliberate ones.
+func constantConditions(file *solidity.File, c solidity.Contract, fn solidity.Function, constants map[string]string) []parser.Finding {
+	unsigned := func(name string) bool {
+		for _, p := range fn.Params {
+			if p.Name == name {
+				return strings.HasPrefix(p.Type, "uint")
+			}
+		}
+		for _, v := range c.StateVars {
+			if v.Name == name {
+				return strings.HasPrefix(v.Type, "uint")
+			}
+		}
+		return false
+	}
+	var findings []parser.Finding
+	for _, s := range statements(file, fn)[1:] {
+		m := controlRe.FindStringSubmatchIndex(s.Text)
+		if m == nil {
+			continue
+		}
+		kind := s.Text[m[2]:m[3]]
+		close := matchParen(s.Text, m[1]-1)
+		if close < 0 {
+			continue
+		}
+		cond := s.Text[m[1]:close]
+		if kind == "require" || kind == "assert" {
+			cond = firstArg(cond)
+		}
+		value, ok := evaluate(constants, unsigned, cond)
+		if !ok || deliberate(kind, cond) {
+			continue
+		}
+		severity, effect := parser.SeverityLow, "so the check guards nothing; if it was meant to validate something, that validation is missing"
+		switch {
+		case !value && (kind == "if" || kind == "while"):
+			effect = fmt.Sprintf("so the body of the %s never runs", kind)
+		case value && kind == "if":
+			effect = "so any else branch never runs and the condition adds nothing"
+		case !value:
+			severity, effect = parser.SeverityMedium, fmt.Sprintf("so %s always reverts and %s can never succeed", kind, fn.Name)
+		}
+		findings = append(findings, parser.Finding{
+			Check: "custom-constant-condition",
+			Title: fmt.Sprintf("Condition Is Always %s: %s.%s", titleCase(fmt.Sprint(value)), c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — `%s` is always %t, %s.",
+				file.Path, s.Line, oneLine(cond), value, effect,
+			),
+			Severity:   severity,
+			Confidence: "High",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence:   conditionEvidence(constants, cond),
+		})
+	}
+	return findings
+}
+
+// deliberate reports the constant conditions written on purpose: while
+// (true) loops and require(false) or assert(false).
+func deliberate(kind, cond string) bool {
+	switch strings.TrimSpace(cond) {
+	case "true":
+		return kind == "while"
+	case "false":
+		return kind == "require" || kind == "assert"
+	}
+	return false
+}
+
+// conditionEvidence lists the constants a condition reads, with their
+// values.
+func conditionEvidence(constants map[string]string, cond string) []string {
+	var evidence []string
+	for _, word := range regexp.MustCompile(`[A-Za-z_$][\w$]*`).FindAllString(cond, -1) {
+		if value, ok := constants[word]; ok {
+			evidence = append(evidence, fmt.Sprintf("%s = %s", word, value))
+		}
+	}
+	return evidence
+}
+
+// matchParen returns the index of the parenthesis closing the one at open
+// in s, or -1.
+func matchParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// firstArg returns the first argument of a call's argument list.
+func firstArg(args string) string {
+	depth := 0
+	for i, r := range args {
+		switch r {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				return args[:i]
+			}
+		}
+	}
+	return args
+}
+
+// missingPlaceholder reports a modifier that never reaches "_;", or only
+// reaches it inside a conditional block without reverting otherwise. The
+// functions it guards then return default values without running.
+// Modifiers no function in the target applies are skipped.
+func missingPlaceholder(file *solidity.File, c solidity.Contract, mod solidity.Function, users []string) (parser.Finding, bool) {
+	if len(users) == 0 || !strings.Contains(file.Line(mod.Line), "{") && mod.End == mod.Line {
+		return parser.Finding{}, false
+	}
+	top, nested, exits := false, false, false
+	scanBlocks(file, mod, func(s statement, depth int, end rune) {
+		switch {
+		case placeholderRe.MatchString(s.Text):
+			top = top || depth == 1
+			nested = nested || depth > 1
+		case strings.HasPrefix(s.Text, "else") || regexp.MustCompile(`\b(revert|require)\b`).MatchString(s.Text):
+			exits = true
+		}
+	})
+	if top || (nested && exits) {
+		return parser.Finding{}, false
+	}
+
+	severity := parser.SeverityMedium
+	for _, u := range users {
+		if strings.HasSuffix(u, "(payable)") {
+			severity = parser.SeverityHigh
+		}
+	}
+	problem := "never executes `_;`"
+	if nested {
+		problem = "only executes `_;` inside a condition and does not revert when the condition fails"
+	}
+	return parser.Finding{
+		Check: "custom-modifier-no-placeholder",
+		Title: fmt.Sprintf("Modifier Skips the Function Body: %s.%s", c.Name, mod.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — modifier %s %s. Calls to the functions it guards (%s) then succeed without running their bodies and return default values, so calls, and ether sent to payable functions, are silently accepted instead of reverted.",
+			file.Path, mod.Line, mod.Name, problem, strings.Join(users, ", "),
+		),
+		Severity:   severity,
+		Confidence: "High",
+		File:       file.Path,
+		Lines:      []int{mod.Line},
+		Evidence:   users,
+	}, true
+}
+
+// oneLine collapses whitespace so multi-line statements quote on one line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
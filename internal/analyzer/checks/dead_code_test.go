@@ -0,0 +1,79 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckDeadCode(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+uint256 constant LIMIT = 1_000 ether;
+
+contract Pool {
+    uint256 constant CAP = 1e21;
+
+    modifier checked() {
+        _;
+    }
+
+    modifier hook() virtual;
+
+    modifier unused() {}
+
+    function join(uint256 amount) external checked {
+        if (CAP == LIMIT) {
+            revert("closed; try later");
+        }
+        for (uint256 i = 0; i < amount; i++) {
+            if (i > 10) {
+                break;
+            }
+        }
+        while (true) {
+            return;
+        }
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pool.sol"), []byte(content), 0644))
+
+	findings, err := CheckDeadCode(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 1, "unused and bodiless modifiers, strings and while (true) are skipped")
+
+	always := findings[0]
+	assert.Equal(t, "custom-constant-condition", always.Check)
+	assert.Equal(t, parser.SeverityLow, always.Severity)
+	assert.Equal(t, []int{17}, always.Lines)
+	assert.Equal(t, "CUSTOM-DEAD-1", always.ID)
+	assert.Contains(t, always.Description, "`CAP == LIMIT` is always true")
+	assert.Equal(t, []string{"CAP = 1e21", "LIMIT = 1_000 ether"}, always.Evidence)
+}
+
+func TestEvaluate(t *testing.T) {
+	constants := map[string]string{"ON": "true", "MAX": "0x10", "MIN": "MAX"}
+	unsigned := func(name string) bool { return name == "amount" }
+	for cond, want := range map[string]bool{
+		"ON":          true,
+		"!ON":         false,
+		"(MIN == 16)": true,
+		"amount >= 0": true,
+		"0 > amount":  false,
+		"MAX != MIN":  false,
+	} {
+		value, ok := evaluate(constants, unsigned, cond)
+		require.True(t, ok, cond)
+		assert.Equal(t, want, value, cond)
+	}
+	for _, cond := range []string{"(ON) && (!ON)", "MAX < 2 gwei", "balance >= 0"} {
+		_, ok := evaluate(constants, unsigned, cond)
+		assert.False(t, ok, cond)
+	}
+}
@@ -0,0 +1,273 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+var (
+	// solDeployRe matches a contract creation in a Foundry script:
+	// `new Vault(args)` or `new Vault{salt: s}(args)`.
+	solDeployRe = regexp.MustCompile(`\bnew\s+([A-Z]\w*)\s*(?:\{[^}]*\}\s*)?\(\s*([^)\s]?)`)
+
+	// jsDeployRe matches a contract deployment in a Hardhat script: ethers'
+	// getContractFactory, hardhat-ethers' deployContract, hardhat-deploy's
+	// deploy and Ignition's m.contract.
+	jsDeployRe = regexp.MustCompile(`\b(?:getContractFactory|deployContract|deploy|contract)\(\s*["'](\w+)["']`)
+
+	// jsArgsRe matches constructor arguments passed in a Hardhat script.
+	jsArgsRe = regexp.MustCompile(`\.deploy\(\s*[^)\s]|(?:deployContract|contract)\(\s*["']\w+["']\s*,\s*\[\s*[^\]\s]|\bargs\s*:\s*\[\s*[^\]\s]`)
+
+	broadcastRe = regexp.MustCompile(`\bvm\.(?:startBroadcast|broadcast)\(`)
+
+	// handoverRe matches a script handing control of what it deployed to
+	// another account, or naming one that is meant to hold it.
+	handoverRe = regexp.MustCompile(`(?i)\b(?:transferOwnership|grantRole|beginDefaultAdminTransfer|transferAdmin|changeAdmin|setPendingOwner)\s*\(|multi[_-]?sig|gnosis|\bsafe(?:_|\b)|timelock|governor|governance`)
+
+	// postDeployCheckRe matches a script checking what it deployed:
+	// assertions over the deployed state or explorer verification with the
+	// constructor arguments.
+	postDeployCheckRe = regexp.MustCompile(`\b(?:require|assert|assertEq|expect)\s*\(|\brevert\b|\bthrow\b|verify:verify|verifyContract`)
+
+	mainnetRe       = regexp.MustCompile(`(?i)\bmainnet\b|chainId\s*:\s*1\b`)
+	confirmationsRe = regexp.MustCompile(`(?i)confirmations|\.wait\(\s*(?:[2-9]|[1-9]\d+)\s*\)`)
+)
+
+// deployment is one contract creation in a deployment script.
+type deployment struct {
+	Contract string
+	Line     int
+	Args     bool // constructor arguments are passed
+}
+
+// CheckDeployScripts reviews the project's deployment scripts (Foundry
+// script/*.s.sol and Hardhat scripts/*.ts and *.js): hardcoded private keys,
+// owned contracts deployed without handing ownership to a multisig,
+// constructor arguments never checked after deployment, and Hardhat
+// deployments to mainnet without waiting for confirmations.
+func CheckDeployScripts(target string, filter Filter) ([]parser.Finding, error) {
+	root := source.ProjectRoot(target)
+	scripts := deployScripts(root, filter)
+	if len(scripts) == 0 {
+		return nil, nil
+	}
+	owned, err := ownedContracts(target, filter)
+	if err != nil {
+		return nil, err
+	}
+	hardhatConfig := ""
+	for _, name := range []string{"hardhat.config.ts", "hardhat.config.js"} {
+		if data, err := os.ReadFile(filepath.Join(root, name)); err == nil {
+			hardhatConfig = solidity.StripComments(string(data))
+			break
+		}
+	}
+
+	db := rules.Default()
+	var findings []parser.Finding
+	add := func(check, title, description string, severity parser.Severity, file string, line int, evidence ...string) {
+		findings = append(findings, parser.Finding{
+			ID:          fmt.Sprintf("CUSTOM-DEPLOY-%d", len(findings)+1),
+			Source:      "custom",
+			Check:       check,
+			Title:       title,
+			Description: description,
+			Severity:    severity,
+			Confidence:  "Medium",
+			File:        file,
+			Lines:       []int{line},
+			Remediation: db.Remediation(check),
+			SWCRef:      db.SWC(check),
+			CWERef:      db.CWE(check),
+			Evidence:    evidence,
+		})
+	}
+
+	for _, path := range scripts {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		code := solidity.StripComments(string(data))
+		lines := strings.Split(code, "\n")
+		for i, line := range lines {
+			if key := privateKey(line); key != "" {
+				add("custom-deploy-hardcoded-key", "Private Key Hardcoded in Deployment Script",
+					fmt.Sprintf("%s:%d signs with a private key written into the script. Anyone with access to the "+
+						"repository controls the deployer and every role it is granted.", path, i+1),
+					parser.SeverityCritical, path, i+1, "key "+redact(key))
+			}
+		}
+
+		solidityScript := filepath.Ext(path) == ".sol"
+		if solidityScript && !broadcastRe.MatchString(code) {
+			continue // a helper, nothing is deployed
+		}
+		deployed := scriptDeployments(code, solidityScript)
+		if len(deployed) == 0 {
+			continue
+		}
+
+		var unowned []string
+		line := 0
+		for _, d := range deployed {
+			if owned[d.Contract] && !slices.Contains(unowned, d.Contract) {
+				unowned = append(unowned, d.Contract)
+				if line == 0 {
+					line = d.Line
+				}
+			}
+		}
+		if len(unowned) > 0 && !handoverRe.MatchString(code) {
+			add("custom-deploy-ownership-not-transferred", "Ownership Not Transferred After Deployment",
+				fmt.Sprintf("%s deploys %s, which restrict privileged functions to an owner or admin role, but never "+
+					"transfers ownership or grants the role to a multisig or timelock. The deployer key, usually a "+
+					"hot wallet on the machine that ran the script, keeps full control.", path, strings.Join(unowned, ", ")),
+				parser.SeverityMedium, path, line,
+				fmt.Sprintf("owned contracts deployed: %s", strings.Join(unowned, ", ")),
+				"no transferOwnership/grantRole call or multisig, Safe or timelock reference in the script")
+		}
+
+		for _, d := range deployed {
+			if d.Args && !postDeployCheckRe.MatchString(code) {
+				add("custom-deploy-unverified-args", "Constructor Arguments Not Verified",
+					fmt.Sprintf("%s deploys %s with constructor arguments but never checks them afterwards: no "+
+						"assertion reads the deployed state back and nothing verifies the contract with its "+
+						"arguments on a block explorer. A wrong address or parameter from the environment goes "+
+						"live unnoticed.", path, d.Contract),
+					parser.SeverityInformational, path, d.Line, fmt.Sprintf("`%s` deployed with arguments", d.Contract))
+				break
+			}
+		}
+
+		if !solidityScript && (mainnetRe.MatchString(code) || mainnetRe.MatchString(hardhatConfig)) &&
+			!confirmationsRe.MatchString(code) && !confirmationsRe.MatchString(hardhatConfig) {
+			add("custom-deploy-no-confirmations", "Mainnet Deployment Without Confirmations",
+				fmt.Sprintf("%s can deploy to mainnet but does not wait for block confirmations. Follow-up "+
+					"transactions (initialization, role grants, verification) may run against a deployment that "+
+					"a reorg later drops.", path),
+				parser.SeverityLow, path, deployed[0].Line,
+				"mainnet network configured", "no waitConfirmations, confirmations setting or tx.wait(n)")
+		}
+	}
+	return findings, nil
+}
+
+// deployScripts returns the Solidity, TypeScript and JavaScript files in the
+// project's deployment script directories that pass the limits.
+func deployScripts(root string, filter Filter) []string {
+	var scripts []string
+	for _, dir := range deployDirs {
+		_ = filepath.Walk(filepath.Join(root, dir), func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return filepath.SkipDir
+			}
+			if fi.IsDir() {
+				if fi.Name() == "node_modules" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch filepath.Ext(path) {
+			case ".sol", ".ts", ".js", ".mjs", ".cjs":
+			default:
+				return nil
+			}
+			if filter.Limits.Screen(path) == "" {
+				scripts = append(scripts, path)
+			}
+			return nil
+		})
+	}
+	return scripts
+}
+
+// scriptDeployments finds the contract creations in a script's code.
+func scriptDeployments(code string, solidityScript bool) []deployment {
+	var deployed []deployment
+	if solidityScript {
+		for _, m := range solDeployRe.FindAllStringSubmatchIndex(code, -1) {
+			deployed = append(deployed, deployment{
+				Contract: code[m[2]:m[3]],
+				Line:     strings.Count(code[:m[0]], "\n") + 1,
+				Args:     m[5] > m[4],
+			})
+		}
+		return deployed
+	}
+	// Hardhat scripts pass arguments apart from naming the contract, so
+	// any arguments in the script count for each deployment.
+	args := jsArgsRe.MatchString(code)
+	for _, m := range jsDeployRe.FindAllStringSubmatchIndex(code, -1) {
+		deployed = append(deployed, deployment{
+			Contract: code[m[2]:m[3]],
+			Line:     strings.Count(code[:m[0]], "\n") + 1,
+			Args:     args,
+		})
+	}
+	return deployed
+}
+
+// ownedContracts returns the contracts in target with an owner or admin
+// role: those inheriting Ownable or AccessControl, or guarding functions
+// with onlyOwner or onlyRole, directly or through their bases.
+func ownedContracts(target string, filter Filter) (map[string]bool, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	bases := map[string][]string{}
+	owned := map[string]bool{}
+	for _, path := range files {
+		f, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range f.Contracts {
+			bases[c.Name] = c.Bases
+			for _, b := range c.Bases {
+				if strings.HasPrefix(b, "Ownable") || strings.HasPrefix(b, "AccessControl") {
+					owned[c.Name] = true
+				}
+			}
+			for _, fn := range c.Functions {
+				for _, m := range fn.Modifiers {
+					if strings.HasPrefix(m, "onlyOwner") || strings.HasPrefix(m, "onlyRole") {
+						owned[c.Name] = true
+					}
+				}
+			}
+		}
+	}
+	var inherits func(name string, seen map[string]bool) bool
+	inherits = func(name string, seen map[string]bool) bool {
+		if owned[name] {
+			return true
+		}
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+		for _, b := range bases[name] {
+			if inherits(b, seen) {
+				return true
+			}
+		}
+		return false
+	}
+	for name := range bases {
+		if inherits(name, map[string]bool{}) {
+			owned[name] = true
+		}
+	}
+	return owned, nil
+}
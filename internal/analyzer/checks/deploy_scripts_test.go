@@ -0,0 +1,40 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnedContracts(t *testing.T) {
+	dir := t.TempDir()
+	src := `contract Base is AccessControlEnumerable {}
+contract Pool is Base {}
+contract Guarded {
+    function pause() external onlyOwner {}
+}
+contract Plain {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pool.sol"), []byte(src), 0644))
+
+	owned, err := ownedContracts(dir, DefaultFilter)
+	require.NoError(t, err)
+	assert.True(t, owned["Base"])
+	assert.True(t, owned["Pool"], "inherited through Base")
+	assert.True(t, owned["Guarded"])
+	assert.False(t, owned["Plain"])
+}
+
+func TestScriptDeployments(t *testing.T) {
+	sol := "vm.startBroadcast();\nToken t = new Token();\nVault v = new Vault{salt: s}(\n    address(t)\n);\n"
+	assert.Equal(t, []deployment{
+		{Contract: "Token", Line: 2},
+		{Contract: "Vault", Line: 3, Args: true},
+	}, scriptDeployments(sol, true))
+
+	js := "const f = await ethers.getContractFactory(\"Vault\");\nconst v = await f.deploy();\n"
+	assert.Equal(t, []deployment{{Contract: "Vault", Line: 1}}, scriptDeployments(js, false))
+}
@@ -0,0 +1,306 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+// standardInterface is an interface with a fixed EIP-165 ID. Functions
+// lists name/arity pairs, which fix the ID; nil leaves the declaration
+// unchecked, as for IERC4906, whose ID is not derived from its functions.
+type standardInterface struct {
+	ID        string
+	Functions []string
+}
+
+// standardInterfaces are keyed by their name without the I prefix or the
+// Upgradeable suffix, uppercased.
+var standardInterfaces = map[string]standardInterface{
+	"ERC165":             {"0x01ffc9a7", []string{"supportsInterface/1"}},
+	"ERC721":             {"0x80ac58cd", []string{"balanceOf/1", "ownerOf/1", "safeTransferFrom/4", "safeTransferFrom/3", "transferFrom/3", "approve/2", "setApprovalForAll/2", "getApproved/1", "isApprovedForAll/2"}},
+	"ERC721METADATA":     {"0x5b5e139f", []string{"name/0", "symbol/0", "tokenURI/1"}},
+	"ERC721ENUMERABLE":   {"0x780e9d63", []string{"totalSupply/0", "tokenOfOwnerByIndex/2", "tokenByIndex/1"}},
+	"ERC721RECEIVER":     {"0x150b7a02", []string{"onERC721Received/4"}},
+	"ERC1155":            {"0xd9b67a26", []string{"safeTransferFrom/5", "safeBatchTransferFrom/5", "balanceOf/2", "balanceOfBatch/2", "setApprovalForAll/2", "isApprovedForAll/2"}},
+	"ERC1155METADATAURI": {"0x0e89341c", []string{"uri/1"}},
+	"ERC1155RECEIVER":    {"0x4e2312e0", []string{"onERC1155Received/5", "onERC1155BatchReceived/5"}},
+	"ERC2981":            {"0x2a55205a", []string{"royaltyInfo/2"}},
+	"ERC4906":            {"0x49064906", nil},
+	"ACCESSCONTROL":      {"0x7965db0b", []string{"hasRole/2", "getRoleAdmin/1", "grantRole/2", "revokeRole/2", "renounceRole/2"}},
+}
+
+var (
+	// interfaceIDConstRe matches a bytes4 constant holding an interface ID,
+	// e.g. _INTERFACE_ID_ERC721 = 0x80ac58cd in OpenZeppelin 3.x.
+	interfaceIDConstRe = regexp.MustCompile(`(?i)interface_?id`)
+	bytes4LiteralRe    = regexp.MustCompile(`=\s*(?:bytes4\s*\(\s*)?(0x[0-9a-fA-F]{8})\b`)
+	interfaceIDTypeRe  = regexp.MustCompile(`\btype\s*\(\s*(\w+)\s*\)\s*\.\s*interfaceId\b`)
+	superSupportsRe    = regexp.MustCompile(`\b\w+\s*\.\s*supportsInterface\s*\(`)
+	// erc165BaseRe matches the OpenZeppelin contracts that implement
+	// supportsInterface for the interfaces they provide.
+	erc165BaseRe = regexp.MustCompile(`^(ERC165|ERC721\w*|ERC1155\w*|ERC2981|ERC1363|AccessControl\w*|Governor\w*)(Upgradeable)?$`)
+)
+
+// CheckERC165 flags supportsInterface implementations that mislead the
+// integrators who use them to detect capabilities: implementations that
+// return true whatever they are asked, interface ID constants whose value
+// is not the standard's ID, interfaces declared under a standard's name
+// with other functions (so type(I).interfaceId is wrong), and overrides
+// that stop calling the inherited supportsInterface, which drops every
+// interface the bases report.
+func CheckERC165(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-ERC165-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	var parsed []*solidity.File
+	// Interfaces declared in the target, by name, which type(I).interfaceId
+	// is computed from.
+	declared := map[string][]declaredInterface{}
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		parsed = append(parsed, file)
+		for _, c := range file.Contracts {
+			if c.Kind == "interface" {
+				declared[c.Name] = append(declared[c.Name], declaredInterface{Path: file.Path, Contract: c})
+			}
+		}
+	}
+
+	for _, file := range parsed {
+		for _, c := range file.Contracts {
+			if c.Kind == "interface" {
+				continue
+			}
+			for _, v := range c.StateVars {
+				if f, ok := wrongIDConstant(file, c, v); ok {
+					add(f)
+				}
+			}
+			for _, fn := range c.Functions {
+				if fn.Name != "supportsInterface" || fn.End <= fn.Line {
+					continue
+				}
+				stmts := statements(file, fn)
+				if f, ok := alwaysTrue(file, c, fn, stmts); ok {
+					add(f)
+					continue
+				}
+				if f, ok := nonstandardInterface(file, c, fn, stmts, declared); ok {
+					add(f)
+				}
+				if f, ok := missingSuper(file, c, fn); ok {
+					add(f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// declaredInterface is an interface declared in the target.
+type declaredInterface struct {
+	Path string
+	solidity.Contract
+}
+
+// standardKey returns the standardInterfaces key of an interface or
+// constant name: IERC721Metadata and _INTERFACE_ID_ERC721_METADATA both
+// give ERC721METADATA.
+func standardKey(name string) string {
+	name = strings.TrimSuffix(name, "Upgradeable")
+	if len(name) > 1 && name[0] == 'I' && name[1] >= 'A' && name[1] <= 'Z' {
+		name = name[1:]
+	}
+	name = interfaceIDConstRe.ReplaceAllString(name, "")
+	return strings.ToUpper(strings.ReplaceAll(name, "_", ""))
+}
+
+// wrongIDConstant reports an interface ID constant named after a standard
+// whose value is not that standard's ID.
+func wrongIDConstant(file *solidity.File, c solidity.Contract, v solidity.StateVar) (parser.Finding, bool) {
+	if !v.Constant || v.Type != "bytes4" || !interfaceIDConstRe.MatchString(v.Name) {
+		return parser.Finding{}, false
+	}
+	std, ok := standardInterfaces[standardKey(v.Name)]
+	m := bytes4LiteralRe.FindStringSubmatch(file.Line(v.Line))
+	if !ok || m == nil || strings.EqualFold(m[1], std.ID) {
+		return parser.Finding{}, false
+	}
+	return parser.Finding{
+		Check: "custom-erc165-wrong-id",
+		Title: fmt.Sprintf("Wrong Interface ID: %s.%s", c.Name, v.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — %s.%s is %s, but the interface it is named after has the ID %s. supportsInterface answers for an interface nobody asks about, so integrators that check for the real one treat the contract as not implementing it.",
+			file.Path, v.Line, c.Name, v.Name, m[1], std.ID,
+		),
+		Severity:   parser.SeverityMedium,
+		Confidence: "High",
+		File:       file.Path,
+		Lines:      []int{v.Line},
+		Evidence: []string{
+			fmt.Sprintf("`%s` at line %d", strings.TrimSpace(file.Line(v.Line)), v.Line),
+			fmt.Sprintf("the standard ID is %s", std.ID),
+		},
+	}, true
+}
+
+// alwaysTrue reports a supportsInterface that returns true before looking
+// at the interface ID, or never looks at it.
+func alwaysTrue(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement) (parser.Finding, bool) {
+	if len(stmts) < 2 {
+		return parser.Finding{}, false
+	}
+	body := functionBody(file, fn)
+	var param string
+	if len(fn.Params) == 1 {
+		param = fn.Params[0].Name
+	}
+	first := stmts[1]
+	unconditional := first.Text == "return true"
+	ignored := (param == "" || !mentions(body, param)) && strings.Contains(body, "true")
+	if !unconditional && !ignored {
+		return parser.Finding{}, false
+	}
+	return parser.Finding{
+		Check: "custom-erc165-always-true",
+		Title: fmt.Sprintf("supportsInterface Always Returns True: %s", c.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — %s.supportsInterface() returns true without checking the interface ID. The contract claims every interface, so marketplaces, wallets and other contracts that detect capabilities through ERC-165 will call functions it does not have, or treat it as an ERC-721 receiver or royalty payer it is not.",
+			file.Path, first.Line, c.Name,
+		),
+		Severity:   parser.SeverityMedium,
+		Confidence: "High",
+		File:       file.Path,
+		Lines:      []int{first.Line},
+		Function:   fn.Name,
+		Evidence: []string{
+			fmt.Sprintf("`%s` at line %d", first.Text, first.Line),
+		},
+	}, true
+}
+
+// nonstandardInterface reports type(I).interfaceId for an interface the
+// target declares under a standard's name with different functions, which
+// gives a different ID from the standard's.
+func nonstandardInterface(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement, declared map[string][]declaredInterface) (parser.Finding, bool) {
+	for _, s := range stmts[1:] {
+		for _, m := range interfaceIDTypeRe.FindAllStringSubmatch(s.Text, -1) {
+			iface, ok := resolveInterface(declared[m[1]], file.Path)
+			std, known := standardInterfaces[standardKey(m[1])]
+			if !ok || !known || std.Functions == nil {
+				continue
+			}
+			var functions []string
+			for _, f := range iface.Functions {
+				functions = append(functions, fmt.Sprintf("%s/%d", f.Name, len(f.Params)))
+			}
+			missing, extra := diffFunctions(std.Functions, functions)
+			if len(missing) == 0 && len(extra) == 0 {
+				continue
+			}
+			var evidence []string
+			if len(missing) > 0 {
+				evidence = append(evidence, fmt.Sprintf("%s does not declare %s", m[1], strings.Join(missing, ", ")))
+			}
+			if len(extra) > 0 {
+				evidence = append(evidence, fmt.Sprintf("%s also declares %s", m[1], strings.Join(extra, ", ")))
+			}
+			return parser.Finding{
+				Check: "custom-erc165-wrong-id",
+				Title: fmt.Sprintf("Nonstandard Interface ID: %s.supportsInterface() reports %s", c.Name, m[1]),
+				Description: fmt.Sprintf(
+					"%s:%d — %s.supportsInterface() reports type(%s).interfaceId, but %s as declared at %s:%d does not have the standard's functions. Its ID is not %s, so integrators checking for the standard get false.",
+					file.Path, s.Line, c.Name, m[1], m[1], iface.Path, iface.Line, std.ID,
+				),
+				Severity:   parser.SeverityMedium,
+				Confidence: "Medium",
+				File:       file.Path,
+				Lines:      []int{s.Line},
+				Function:   fn.Name,
+				Evidence:   append([]string{fmt.Sprintf("`%s` at line %d", s.Text, s.Line)}, evidence...),
+			}, true
+		}
+	}
+	return parser.Finding{}, false
+}
+
+// resolveInterface picks the declaration a file refers to: its own, or
+// the only one in the target. Names declared in several other files are
+// ambiguous without resolving imports, so they are skipped.
+func resolveInterface(candidates []declaredInterface, path string) (declaredInterface, bool) {
+	for _, d := range candidates {
+		if d.Path == path {
+			return d, true
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	return declaredInterface{}, false
+}
+
+// diffFunctions returns the name/arity pairs of want missing from got, and
+// those of got that want lacks.
+func diffFunctions(want, got []string) (missing, extra []string) {
+	rest := slices.Clone(got)
+	for _, w := range want {
+		if i := slices.Index(rest, w); i >= 0 {
+			rest = slices.Delete(rest, i, i+1)
+		} else {
+			missing = append(missing, w)
+		}
+	}
+	return missing, rest
+}
+
+// missingSuper reports an override of supportsInterface in a contract that
+// inherits an implementation, when the override calls no base's version.
+func missingSuper(file *solidity.File, c solidity.Contract, fn solidity.Function) (parser.Finding, bool) {
+	bases := slices.DeleteFunc(slices.Clone(c.Bases), func(b string) bool { return !erc165BaseRe.MatchString(b) })
+	body := functionBody(file, fn)
+	if len(bases) == 0 || superSupportsRe.MatchString(body) {
+		return parser.Finding{}, false
+	}
+	if slices.Equal(bases, []string{"ERC165"}) && (strings.Contains(body, "IERC165") || strings.Contains(body, standardInterfaces["ERC165"].ID)) {
+		// ERC165 itself only reports IERC165, which the override lists.
+		return parser.Finding{}, false
+	}
+	return parser.Finding{
+		Check: "custom-erc165-missing-super",
+		Title: fmt.Sprintf("supportsInterface Drops Inherited Interfaces: %s", c.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — %s.supportsInterface() overrides the implementation inherited from %s without calling super.supportsInterface(). Every interface those bases report, such as ERC-165 itself or the metadata extension, is no longer advertised unless it is listed again by hand.",
+			file.Path, fn.Line, c.Name, strings.Join(bases, ", "),
+		),
+		Severity:   parser.SeverityLow,
+		Confidence: "Medium",
+		File:       file.Path,
+		Lines:      []int{fn.Line},
+		Function:   fn.Name,
+		Evidence: []string{
+			fmt.Sprintf("%s inherits %s", c.Name, strings.Join(bases, ", ")),
+			"no super.supportsInterface() or Base.supportsInterface() call in the override",
+		},
+	}, true
+}
@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckERC165(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+interface IERC721Metadata {
+    function name() external view returns (string memory);
+    function symbol() external view returns (string memory);
+}
+
+contract Gate is ERC165 {
+    function supportsInterface(bytes4 interfaceId) public view override returns (bool) {
+        return true;
+        interfaceId;
+    }
+}
+
+contract Listed is ERC165 {
+    function supportsInterface(bytes4 interfaceId) public view override returns (bool) {
+        return interfaceId == type(IERC165).interfaceId || interfaceId == 0x12345678;
+    }
+}
+
+contract Art is ERC721Enumerable {
+    bytes4 constant INTERFACE_ID_ERC721_ENUMERABLE = 0x780e9d63;
+
+    function supportsInterface(bytes4 interfaceId) public view override returns (bool) {
+        return interfaceId == type(IERC721Metadata).interfaceId || super.supportsInterface(interfaceId);
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Art.sol"), []byte(content), 0644))
+
+	findings, err := CheckERC165(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "an ERC165 override listing IERC165 and a correct constant are skipped")
+
+	always := findings[0]
+	assert.Equal(t, "custom-erc165-always-true", always.Check)
+	assert.Equal(t, parser.SeverityMedium, always.Severity)
+	assert.Equal(t, []int{10}, always.Lines)
+	assert.Equal(t, "CUSTOM-ERC165-1", always.ID)
+
+	metadata := findings[1]
+	assert.Equal(t, "custom-erc165-wrong-id", metadata.Check)
+	assert.Equal(t, []int{25}, metadata.Lines)
+	assert.Contains(t, metadata.Evidence, "IERC721Metadata does not declare tokenURI/1")
+	assert.Contains(t, metadata.Description, "Its ID is not 0x5b5e139f")
+}
+
+func TestStandardKey(t *testing.T) {
+	assert.Equal(t, "ERC721METADATA", standardKey("IERC721Metadata"))
+	assert.Equal(t, "ERC721METADATA", standardKey("_INTERFACE_ID_ERC721_METADATA"))
+	assert.Equal(t, "ERC2981", standardKey("IERC2981Upgradeable"))
+	assert.Equal(t, "ERC1155RECEIVER", standardKey("ERC1155_RECEIVER_INTERFACE_ID"))
+}
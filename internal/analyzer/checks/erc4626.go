@@ -0,0 +1,250 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+// eip4626 is the ERC-4626 specification the findings reference.
+const eip4626 = "https://eips.ethereum.org/EIPS/eip-4626"
+
+// pauseLimit names a pause among an entry point's limits; caps are named by
+// their variable.
+const pauseLimit = "the pause"
+
+// vaultRounding is the rounding direction ERC-4626 requires of each
+// conversion: always in favour of the vault, so that no sequence of calls
+// extracts value from the other depositors.
+var vaultRounding = []struct {
+	function string
+	up       bool
+}{
+	{"convertToShares", false},
+	{"convertToAssets", false},
+	{"previewDeposit", false},
+	{"previewMint", true},
+	{"previewWithdraw", true},
+	{"previewRedeem", false},
+}
+
+// vaultLimits pairs each ERC-4626 entry point with the view that must
+// report its limits.
+var vaultLimits = []struct{ entry, max string }{
+	{"deposit", "maxDeposit"},
+	{"mint", "maxMint"},
+	{"withdraw", "maxWithdraw"},
+	{"redeem", "maxRedeem"},
+}
+
+var (
+	roundUpRe   = regexp.MustCompile(`(?i)mulDivUp|divUp|ceilDiv|roundUp|Rounding\.(Ceil|Up|Expand)\b`)
+	roundDownRe = regexp.MustCompile(`(?i)mulDivDown|divDown|Rounding\.(Floor|Down|Trunc)\b|\bmulDiv\s*\(|[^/*]/[^/*=]|\b(convertToShares|convertToAssets|previewDeposit|previewRedeem)\s*\(`)
+	pauseRe     = regexp.MustCompile(`\b(_?paused\s*\(|_?paused\b|_requireNotPaused|whenNotPaused)`)
+	// capRe matches a limit enforced on an entry point, e.g.
+	// require(totalAssets() + assets <= depositCap).
+	capRe = regexp.MustCompile(`(require|revert|if)\b.*?\b(\w*(Cap|cap|Limit|limit)\w*)\b`)
+)
+
+// CheckERC4626 verifies that ERC-4626 vaults follow the parts of the
+// standard integrators rely on: conversions and previews round in the
+// vault's favour (down when paying out, up when charging), and the max*
+// views report the pauses and caps the entry points enforce, returning 0
+// when deposits or withdrawals are disabled.
+func CheckERC4626(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []parser.Finding
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" {
+				continue
+			}
+			functions := map[string]solidity.Function{}
+			for _, fn := range c.Functions {
+				if _, seen := functions[fn.Name]; !seen && fn.End > fn.Line {
+					functions[fn.Name] = fn
+				}
+			}
+			if !isVault(c, functions) {
+				continue
+			}
+			findings = append(findings, vaultRoundingFindings(file, path, c, functions, len(findings))...)
+			findings = append(findings, vaultLimitFindings(file, path, c, functions, len(findings))...)
+		}
+	}
+	return findings, nil
+}
+
+// isVault reports whether c implements ERC-4626: it inherits an ERC4626
+// base or defines one of the standard's conversions.
+func isVault(c solidity.Contract, functions map[string]solidity.Function) bool {
+	for _, b := range c.Bases {
+		if strings.Contains(b, "4626") {
+			return true
+		}
+	}
+	for _, r := range vaultRounding {
+		if _, ok := functions[r.function]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// vaultRoundingFindings reports the conversions that round against the
+// vault. Functions whose direction cannot be told (no division and no
+// explicit rounding) are left alone.
+func vaultRoundingFindings(file *solidity.File, path string, c solidity.Contract, functions map[string]solidity.Function, n int) []parser.Finding {
+	db := rules.Default()
+	var findings []parser.Finding
+	for _, r := range vaultRounding {
+		fn, ok := functions[r.function]
+		if !ok {
+			continue
+		}
+		body := functionBody(file, fn)
+		var evidence string
+		switch up := roundUpRe.FindString(body); {
+		case r.up && up == "":
+			down := roundDownRe.FindString(body)
+			if down == "" {
+				continue
+			}
+			evidence = fmt.Sprintf("rounds down: `%s` with no mulDivUp, ceilDiv or Rounding.Ceil", strings.TrimSpace(down))
+		case !r.up && up != "":
+			evidence = fmt.Sprintf("rounds up: `%s`", up)
+		default:
+			continue
+		}
+
+		want, effect := "down", "it rounds up, so callers receive more than they pay for"
+		if r.up {
+			want, effect = "up", "it rounds down, so callers are charged less than they receive"
+		}
+		severity := parser.SeverityHigh
+		if strings.HasPrefix(r.function, "convertTo") {
+			// convertTo* only inform; previews drive the actual accounting.
+			severity = parser.SeverityMedium
+		}
+		findings = append(findings, parser.Finding{
+			ID:     fmt.Sprintf("CUSTOM-ERC4626-%d", n+len(findings)+1),
+			Source: "custom",
+			Check:  "custom-erc4626-rounding",
+			Title:  fmt.Sprintf("ERC-4626 %s.%s() Rounds Against the Vault", c.Name, r.function),
+			Description: fmt.Sprintf(
+				"%s:%d — ERC-4626 requires %s to round %s, in favour of the vault, but %s. Repeated small deposits and withdrawals can then drain value from the other depositors.",
+				path, fn.Line, r.function, want, effect,
+			),
+			Severity:    severity,
+			Confidence:  "Medium",
+			File:        path,
+			Lines:       []int{fn.Line},
+			Remediation: db.Remediation("custom-erc4626-rounding"),
+			CWERef:      db.CWE("custom-erc4626-rounding"),
+			References:  []string{eip4626 + "#security-considerations", eip4626 + "#" + strings.ToLower(r.function)},
+			Evidence:    []string{evidence, fmt.Sprintf("ERC-4626: %s MUST round %s", r.function, want)},
+		})
+	}
+	return findings
+}
+
+// vaultLimitFindings reports the max* views that ignore a pause or cap
+// their entry point enforces, whether they are defined in the contract or
+// inherited from a base that knows nothing of them.
+func vaultLimitFindings(file *solidity.File, path string, c solidity.Contract, functions map[string]solidity.Function, n int) []parser.Finding {
+	db := rules.Default()
+	var findings []parser.Finding
+	for _, l := range vaultLimits {
+		entry, ok := functions[l.entry]
+		if !ok || entry.Mutability != "" {
+			continue
+		}
+		body := functionBody(file, entry)
+		var limits []string
+		if slices.Contains(entry.Modifiers, "whenNotPaused") || pauseRe.MatchString(body) {
+			limits = append(limits, pauseLimit)
+		}
+		if m := capRe.FindStringSubmatch(body); m != nil {
+			limits = append(limits, m[2])
+		}
+		if len(limits) == 0 {
+			continue
+		}
+
+		maxFn, defined := functions[l.max]
+		line := entry.Line
+		var maxBody string
+		if defined {
+			line, maxBody = maxFn.Line, functionBody(file, maxFn)
+		}
+		var ignored []string
+		for _, limit := range limits {
+			if limit == pauseLimit && !pauseRe.MatchString(maxBody) || limit != pauseLimit && !strings.Contains(maxBody, limit) {
+				ignored = append(ignored, limit)
+			}
+		}
+		if len(ignored) == 0 {
+			continue
+		}
+
+		where := fmt.Sprintf("%s() does not check %s", l.max, strings.Join(ignored, " or "))
+		if !defined {
+			where = fmt.Sprintf("%s() is not overridden, so it ignores %s", l.max, strings.Join(ignored, " and "))
+		}
+		findings = append(findings, parser.Finding{
+			ID:     fmt.Sprintf("CUSTOM-ERC4626-%d", n+len(findings)+1),
+			Source: "custom",
+			Check:  "custom-erc4626-max-limit",
+			Title:  fmt.Sprintf("ERC-4626 %s.%s() Ignores Limits of %s()", c.Name, l.max, l.entry),
+			Description: fmt.Sprintf(
+				"%s:%d — %s() enforces %s, but %s. ERC-4626 requires max* views to reflect every global and user limit, returning 0 while the operation is disabled; integrators and routers that rely on them will revert or mis-route funds.",
+				path, line, l.entry, strings.Join(limits, " and "), where,
+			),
+			Severity:    parser.SeverityMedium,
+			Confidence:  "Medium",
+			File:        path,
+			Lines:       []int{line},
+			Remediation: db.Remediation("custom-erc4626-max-limit"),
+			CWERef:      db.CWE("custom-erc4626-max-limit"),
+			References:  []string{eip4626 + "#" + strings.ToLower(l.max)},
+			Evidence: []string{
+				fmt.Sprintf("%s() at line %d enforces %s", l.entry, entry.Line, strings.Join(limits, ", ")),
+				where,
+			},
+		})
+	}
+	return findings
+}
+
+// functionBody returns the code of fn after its opening brace, so the
+// signature's parameter names do not match body patterns.
+func functionBody(file *solidity.File, fn solidity.Function) string {
+	var b strings.Builder
+	body := false
+	for n := fn.Line; n <= fn.End; n++ {
+		line := file.Line(n)
+		if !body {
+			i := strings.Index(line, "{")
+			if i < 0 {
+				continue
+			}
+			body, line = true, line[i+1:]
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckERC4626(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Vault is ERC4626 {
+    function convertToShares(uint256 assets) public view override returns (uint256) {
+        return assets.mulDivUp(totalSupply(), totalAssets());
+    }
+
+    function previewWithdraw(uint256 assets) public view override returns (uint256) {
+        return assets * totalSupply() / totalAssets();
+    }
+
+    function previewMint(uint256 shares) public view override returns (uint256) {
+        return shares.mulDivUp(totalAssets(), totalSupply());
+    }
+
+    function previewRedeem(uint256 shares) public view override returns (uint256) {
+        return _convertToAssets(shares, rounding);
+    }
+
+    function maxMint(address) public view override returns (uint256) {
+        return mintLimit - totalSupply();
+    }
+
+    function mint(uint256 shares, address receiver) public override returns (uint256) {
+        if (totalSupply() + shares > mintLimit) revert LimitExceeded();
+        return super.mint(shares, receiver);
+    }
+
+    function redeem(uint256 shares, address receiver, address owner) public override returns (uint256) {
+        require(!paused, "paused");
+        return super.redeem(shares, receiver, owner);
+    }
+}
+
+contract Bank {
+    function deposit() external payable whenNotPaused {}
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Vault.sol"), []byte(content), 0644))
+
+	findings, err := CheckERC4626(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 3, "previewMint rounds up, previewRedeem's direction is unknown, maxMint checks mintLimit and Bank is no vault")
+
+	convert := findings[0]
+	assert.Equal(t, "custom-erc4626-rounding", convert.Check)
+	assert.Equal(t, parser.SeverityMedium, convert.Severity)
+	assert.Equal(t, []int{4}, convert.Lines)
+	assert.Equal(t, "rounds up: `mulDivUp`", convert.Evidence[0])
+	assert.Contains(t, convert.References, "https://eips.ethereum.org/EIPS/eip-4626#converttoshares")
+
+	withdraw := findings[1]
+	assert.Equal(t, parser.SeverityHigh, withdraw.Severity)
+	assert.Equal(t, []int{8}, withdraw.Lines)
+	assert.Contains(t, withdraw.Description, "requires previewWithdraw to round up")
+
+	redeem := findings[2]
+	assert.Equal(t, "custom-erc4626-max-limit", redeem.Check)
+	assert.Equal(t, "ERC-4626 Vault.maxRedeem() Ignores Limits of redeem()", redeem.Title)
+	assert.Equal(t, []int{29}, redeem.Lines, "reported at the entry point when max* is inherited")
+	assert.Equal(t, "maxRedeem() is not overridden, so it ignores the pause", redeem.Evidence[1])
+}
@@ -0,0 +1,168 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+// CheckFunctionClashing flags implementation functions whose 4-byte
+// selector is also claimed by the proxy in front of them. A proxy only
+// delegates calls it does not handle itself, so when one of its own
+// functions (an admin function such as upgradeTo, or a public getter)
+// shares a selector with an implementation function, calls meant for the
+// implementation run the proxy's code instead. Selectors are computed from
+// the canonical signatures of the source, including the functions of
+// bases declared in the target.
+//
+// Proxies are contracts whose fallback, or a base's, reaches a
+// delegatecall. Implementations are the upgradeable contracts of the
+// target, and are compared with the proxies in their directory, or with
+// every proxy when their directory has none. A clash is High when every
+// caller reaches the proxy's function, and Low when the proxy routes
+// everyone but the admin to the implementation (the transparent proxy
+// pattern's ifAdmin), since only the admin is then affected.
+func CheckFunctionClashing(target string, filter Filter) ([]parser.Finding, error) {
+	paths, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-CLASH-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	var files []*solidity.File
+	byName := map[string]sourceContract{}
+	var contracts []sourceContract
+	for _, path := range paths {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		files = append(files, file)
+		for _, c := range file.Contracts {
+			sc := sourceContract{file, c}
+			if _, ok := byName[c.Name]; !ok {
+				byName[c.Name] = sc
+			}
+			if c.Kind == "contract" && !c.Abstract {
+				contracts = append(contracts, sc)
+			}
+		}
+	}
+	types := collectABITypes(files)
+
+	type proxy struct {
+		sourceContract
+		selectors []selector
+	}
+	var proxies []proxy
+	var implementations []sourceContract
+	for _, sc := range contracts {
+		chain := lineage(byName, sc)
+		switch {
+		case slices.ContainsFunc(chain, delegates):
+			proxies = append(proxies, proxy{sc, externalSelectors(types, chain)})
+		case slices.ContainsFunc(chain, func(b sourceContract) bool { return isUpgradeable(b.Contract) }):
+			implementations = append(implementations, sc)
+		}
+	}
+	if len(proxies) == 0 {
+		return nil, nil
+	}
+
+	reported := map[string]bool{}
+	for _, impl := range implementations {
+		candidates := proxies
+		if local := slices.DeleteFunc(slices.Clone(proxies), func(p proxy) bool {
+			return filepath.Dir(p.file.Path) != filepath.Dir(impl.file.Path)
+		}); len(local) > 0 {
+			candidates = local
+		}
+		for _, fn := range externalSelectors(types, lineage(byName, impl)) {
+			for _, p := range candidates {
+				for _, own := range p.selectors {
+					if own.Selector != fn.Selector {
+						continue
+					}
+					// A base shared by several implementations clashes once.
+					key := fmt.Sprintf("%s|%d|%s|%s", fn.Path, fn.Line, p.Name, own.Signature)
+					if reported[key] {
+						continue
+					}
+					reported[key] = true
+					add(clashFinding(byName, impl, fn, p.sourceContract, own))
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// clashFinding reports that fn of impl is unreachable, for some or all
+// callers, behind proxy p because p's own function claims its selector.
+func clashFinding(byName map[string]sourceContract, impl sourceContract, fn selector, p sourceContract, own selector) parser.Finding {
+	clash := fmt.Sprintf("%s.%s and %s.%s share the selector %s", impl.Name, fn.Signature, p.Name, own.Signature, fn.Selector)
+	if fn.Signature == own.Signature {
+		clash = fmt.Sprintf("%s declares %s too", p.Name, fn.Signature)
+	}
+	severity, effect := parser.SeverityHigh, fmt.Sprintf(
+		"The proxy handles the selector itself, so no call to %s ever reaches the implementation: callers run the proxy's %s instead, and a signature chosen to look harmless can hide an admin action behind it.",
+		fn.Signature, own.Signature)
+	if routesToImplementation(byName, p, own) {
+		severity, effect = parser.SeverityLow, fmt.Sprintf(
+			"%s routes callers other than the admin to the implementation, so only the admin is affected: calls it makes to %s run the proxy's %s instead.",
+			p.Name, fn.Signature, own.Signature)
+	}
+	return parser.Finding{
+		Check: "custom-proxy-selector-clash",
+		Title: fmt.Sprintf("Function Selector Clash with Proxy: %s.%s", impl.Name, fn.Signature),
+		Description: fmt.Sprintf(
+			"%s:%d — %s. %s",
+			fn.Path, fn.Line, clash, effect,
+		),
+		Severity:   severity,
+		Confidence: "Medium",
+		File:       fn.Path,
+		Lines:      []int{fn.Line},
+		Function:   fn.Function.Name,
+		Evidence: []string{
+			fmt.Sprintf("%s (%s) is declared in %s at %s:%d", fn.Signature, fn.Selector, fn.Contract, fn.Path, fn.Line),
+			fmt.Sprintf("%s (%s) is declared in %s at %s:%d", own.Signature, own.Selector, own.Contract, own.Path, own.Line),
+		},
+	}
+}
+
+// delegates reports whether sc's own fallback reaches a delegatecall.
+func delegates(sc sourceContract) bool {
+	fallback, ok := special(sc.Contract, "fallback")
+	return ok && delegatecallRe.MatchString(reachable(sc.file, sc.Contract, fallback))
+}
+
+// routesToImplementation reports whether the proxy function own forwards
+// callers other than the admin to the implementation, through an ifAdmin
+// modifier or a body that falls back to delegating.
+func routesToImplementation(byName map[string]sourceContract, p sourceContract, own selector) bool {
+	if own.Function.Name == "" {
+		return false // a getter always answers
+	}
+	if slices.Contains(own.Function.Modifiers, "ifAdmin") {
+		return true
+	}
+	declaring, ok := byName[own.Contract]
+	if !ok {
+		declaring = p
+	}
+	return delegatecallRe.MatchString(reachable(declaring.file, declaring.Contract, own.Function))
+}
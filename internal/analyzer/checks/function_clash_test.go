@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+func TestCheckFunctionClashing(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Proxy {
+    fallback() external payable {
+        _delegate(_implementation());
+    }
+
+    function _delegate(address impl) internal {
+        assembly { let ok := delegatecall(gas(), impl, 0, calldatasize(), 0, 0) }
+    }
+}
+
+contract TransparentProxy is Proxy {
+    function upgradeTo(address impl) external ifAdmin {}
+}
+
+contract Upgrades {
+    function upgradeTo(address impl) public {}
+}
+
+contract Vault is Initializable, Upgrades {
+    struct Order { address maker; uint amount; }
+
+    function initialize() external initializer {}
+    function fill(Order calldata order) external {}
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Proxy.sol"), []byte(content), 0644))
+
+	findings, err := CheckFunctionClashing(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+
+	clash := findings[0]
+	assert.Equal(t, "custom-proxy-selector-clash", clash.Check)
+	assert.Equal(t, parser.SeverityLow, clash.Severity, "ifAdmin routes everyone but the admin to the implementation")
+	assert.Equal(t, []int{18}, clash.Lines, "reported where the inherited function is declared")
+	assert.Equal(t, "CUSTOM-CLASH-1", clash.ID)
+	assert.Contains(t, clash.Description, "TransparentProxy declares upgradeTo(address) too")
+	assert.Contains(t, clash.Evidence, "upgradeTo(address) (0x3659cfe6) is declared in Upgrades at "+filepath.Join(dir, "Proxy.sol")+":18")
+}
+
+func TestSignatures(t *testing.T) {
+	file := solidity.Parse("Types.sol", `pragma solidity ^0.8.0;
+
+enum Side { Buy, Sell }
+type Price is uint128;
+
+contract Book {
+    struct Order { address maker; Side side; Price[] prices; IERC20 token; }
+
+    mapping(address owner => mapping(uint id => Order)) public orders;
+    uint[][] public levels;
+
+    function place(Order[] memory batch, address payable to, uint) external {}
+    function cancel(Unknown calldata u) external {}
+}
+`)
+	types := collectABITypes([]*solidity.File{file})
+	book := file.Contracts[0]
+
+	signature, ok := functionSignature(types, book.Functions[0])
+	require.True(t, ok)
+	assert.Equal(t, "place((address,uint8,uint128[],address)[],address,uint256)", signature)
+	_, ok = functionSignature(types, book.Functions[1])
+	assert.False(t, ok, "a struct declared outside the target cannot be resolved")
+
+	signature, ok = getterSignature(types, book.StateVars[0])
+	require.True(t, ok)
+	assert.Equal(t, "orders(address,uint256)", signature)
+	signature, ok = getterSignature(types, book.StateVars[1])
+	require.True(t, ok)
+	assert.Equal(t, "levels(uint256,uint256)", signature)
+}
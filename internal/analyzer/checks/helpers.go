@@ -1,31 +1,35 @@
 package checks
 
-import (
-	"os"
-	"path/filepath"
-)
+import "github.com/Zubimendi/solsec/internal/source"
 
-// solidityFiles returns all .sol files at the given path.
-// If path is a file, returns [path]. If a directory, walks it recursively.
-func solidityFiles(target string) ([]string, error) {
-	info, err := os.Stat(target)
+// Filter selects the files a check reads. Checks receive it as an argument
+// rather than sharing it, so a check left running after its timeout never
+// races with the next analysis.
+type Filter struct {
+	// Limits are the size and content guards applied to each file; files
+	// that fail them are skipped.
+	Limits source.Limits
+
+	// Exclude drops the files --exclude-paths names; nil drops none.
+	Exclude *source.Excluder
+}
+
+// DefaultFilter applies source.DefaultLimits and excludes nothing.
+var DefaultFilter = Filter{Limits: source.DefaultLimits}
+
+// solidityFiles returns all .sol files at the given path that pass the
+// limits and are not excluded. If path is a file, returns [path]. If a
+// directory, walks it recursively.
+func (f Filter) solidityFiles(target string) ([]string, error) {
+	files, err := source.SolidityFiles(target)
 	if err != nil {
 		return nil, err
 	}
-
-	if !info.IsDir() {
-		return []string{target}, nil
-	}
-
-	var files []string
-	err = filepath.Walk(target, func(path string, fi os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	kept := files[:0]
+	for _, file := range files {
+		if f.Limits.Screen(file) == "" && !f.Exclude.Excluded(file) {
+			kept = append(kept, file)
 		}
-		if !fi.IsDir() && filepath.Ext(path) == ".sol" {
-			files = append(files, path)
-		}
-		return nil
-	})
-	return files, err
-}
\ No newline at end of file
+	}
+	return kept, nil
+}
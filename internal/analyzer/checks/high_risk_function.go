@@ -0,0 +1,155 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+// minRiskFactors is how many risk factors must combine on one function for
+// a composite finding; each alone is a weak signal.
+const minRiskFactors = 3
+
+var (
+	assemblyRe = regexp.MustCompile(`\bassembly\s*(\(|"|\{)`)
+	// inlineGuardRe matches caller checks written in a function body rather
+	// than as a modifier, e.g. require(msg.sender == owner) or hasRole(...).
+	inlineGuardRe = regexp.MustCompile(`(msg\.sender|_msgSender\(\))\s*[!=]=|[!=]=\s*(msg\.sender|_msgSender\(\))|\b(hasRole|_checkRole|_checkOwner)\s*\(`)
+)
+
+// riskFactor is one weak signal on a function, with where it was seen.
+type riskFactor struct {
+	name     string
+	evidence string
+}
+
+// CheckHighRiskFunctions cross-checks the weak signals on each externally
+// callable function: accepting ether (payable), no access control, an
+// external call and inline assembly. Any one is common in safe code, but an
+// unguarded function combining at least three is where exploits
+// concentrate, so it gets a composite finding listing the contributing
+// factors: High for three, Critical for all four. Guarded functions are left
+// out, since only privileged callers reach them. receive and fallback are
+// skipped, as proxies forward every call through them by design.
+func CheckHighRiskFunctions(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" {
+				continue
+			}
+			for _, fn := range c.Functions {
+				factors := riskFactors(file, fn)
+				if len(factors) < minRiskFactors {
+					continue
+				}
+				severity := parser.SeverityHigh
+				if len(factors) == 4 {
+					severity = parser.SeverityCritical
+				}
+				names := make([]string, len(factors))
+				evidence := make([]string, len(factors))
+				for i, rf := range factors {
+					names[i], evidence[i] = rf.name, rf.evidence
+				}
+				findings = append(findings, parser.Finding{
+					ID:     fmt.Sprintf("CUSTOM-HIGHRISK-%d", len(findings)+1),
+					Source: "custom",
+					Check:  "custom-high-risk-function",
+					Title:  fmt.Sprintf("High-Risk Function %s.%s()", c.Name, fn.Name),
+					Description: fmt.Sprintf(
+						"%s:%d — Function '%s' combines %d risk factors: %s. Each is a weak signal alone, but together they make it the most likely entry point for an exploit.",
+						path, fn.Line, fn.Name, len(factors), strings.Join(names, ", "),
+					),
+					Severity:    severity,
+					Confidence:  "Medium",
+					File:        path,
+					Lines:       []int{fn.Line},
+					Remediation: db.Remediation("custom-high-risk-function"),
+					CWERef:      db.CWE("custom-high-risk-function"),
+					Evidence:    evidence,
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// riskFactors returns the weak signals on fn, or none when it restricts its
+// callers, cannot be called from outside the contract or cannot change state.
+func riskFactors(file *solidity.File, fn solidity.Function) []riskFactor {
+	switch {
+	case fn.Name == "constructor" || fn.Name == "receive" || fn.Name == "fallback":
+		return nil
+	case fn.Mutability != "" || fn.End <= fn.Line:
+		return nil
+	case fn.Visibility != "external" && fn.Visibility != "public" && fn.Visibility != "":
+		return nil
+	case guarded(file, fn):
+		return nil
+	}
+
+	var factors []riskFactor
+	if fn.Payable {
+		factors = append(factors, riskFactor{"payable", fmt.Sprintf("payable: accepts ether (line %d)", fn.Line)})
+	}
+	factors = append(factors, riskFactor{"no access control", fmt.Sprintf(
+		"no access control: no guard modifier (looked for only* and %s) and no msg.sender or role check in the body",
+		strings.Join(rules.Default().Signature("access-modifier"), ", "))})
+	calls := rules.Default().Signature("external-call")
+	var call, asm *riskFactor
+	for n := fn.Line; n <= fn.End; n++ {
+		line := file.Line(n)
+		if call == nil {
+			for _, p := range calls {
+				if i := strings.Index(line, p); i >= 0 {
+					call = &riskFactor{"external call", fmt.Sprintf("external call: `%s` at line %d col %d", p, n, i+1)}
+					break
+				}
+			}
+		}
+		if asm == nil {
+			if loc := assemblyRe.FindStringIndex(line); loc != nil {
+				asm = &riskFactor{"inline assembly", fmt.Sprintf("inline assembly at line %d col %d", n, loc[0]+1)}
+			}
+		}
+	}
+	for _, rf := range []*riskFactor{call, asm} {
+		if rf != nil {
+			factors = append(factors, *rf)
+		}
+	}
+	return factors
+}
+
+// guarded reports whether fn restricts its callers, by a modifier or by a
+// sender or role check in its body.
+func guarded(file *solidity.File, fn solidity.Function) bool {
+	accessModifiers := rules.Default().Signature("access-modifier")
+	for _, m := range fn.Modifiers {
+		if strings.HasPrefix(m, "only") || slices.Contains(accessModifiers, m) {
+			return true
+		}
+	}
+	for n := fn.Line; n <= fn.End; n++ {
+		if inlineGuardRe.MatchString(file.Line(n)) {
+			return true
+		}
+	}
+	return false
+}
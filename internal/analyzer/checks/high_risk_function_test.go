@@ -0,0 +1,65 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckHighRiskFunctions(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Router {
+    address public owner;
+
+    function execute(address target, bytes calldata data) external payable {
+        assembly {
+            calldatacopy(0, data.offset, data.length)
+        }
+        (bool ok, ) = target.call{value: msg.value}(data);
+        require(ok);
+    }
+
+    function relay(address target, bytes calldata data) external {
+        (bool ok, ) = target.call(data);
+        require(ok);
+    }
+
+    function rescue(address target) external payable {
+        require(msg.sender == owner, "not owner");
+        assembly { let s := extcodesize(target) }
+        payable(target).transfer(msg.value);
+    }
+
+    // An external call in a comment: target.call{value: 1}("")
+    function deposit() external payable {
+        assembly { let v := callvalue() }
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Router.sol"), []byte(content), 0644))
+
+	findings, err := CheckHighRiskFunctions(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "relay has two factors and rescue is guarded")
+
+	execute := findings[0]
+	assert.Equal(t, "custom-high-risk-function", execute.Check)
+	assert.Equal(t, parser.SeverityCritical, execute.Severity, "all four factors")
+	assert.Equal(t, []int{6}, execute.Lines)
+	assert.Contains(t, execute.Description, "combines 4 risk factors: payable, no access control, external call, inline assembly")
+	assert.Len(t, execute.Evidence, 4)
+	assert.Contains(t, execute.Evidence[2], "`.call{` at line 10")
+	assert.Contains(t, execute.Evidence[3], "inline assembly at line 7")
+	assert.NotEmpty(t, execute.Remediation)
+
+	deposit := findings[1]
+	assert.Equal(t, parser.SeverityHigh, deposit.Severity)
+	assert.Equal(t, []int{26}, deposit.Lines)
+	assert.Contains(t, deposit.Description, "payable, no access control, inline assembly")
+}
@@ -7,12 +7,13 @@ import (
 	"strings"
 
 	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
 )
 
 // CheckIntegerOverflow scans for unchecked arithmetic in Solidity < 0.8.0
 // and dangerous use of unchecked{} blocks in 0.8.0+.
-func CheckIntegerOverflow(target string) ([]parser.Finding, error) {
-	files, err := solidityFiles(target)
+func CheckIntegerOverflow(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +43,7 @@ func checkIntegerOverflowInFile(path string) ([]parser.Finding, error) {
 		solidityMinor int
 		inUnchecked   bool
 		uncheckedLine int
+		pragmaLine    string
 	)
 
 	scanner := bufio.NewScanner(f)
@@ -53,6 +55,7 @@ func checkIntegerOverflowInFile(path string) ([]parser.Finding, error) {
 		// Extract Solidity version from pragma
 		if strings.HasPrefix(trimmed, "pragma solidity") {
 			solidityMajor, solidityMinor = extractSolidityVersion(trimmed)
+			pragmaLine = trimmed
 		}
 
 		// Track unchecked blocks (valid in 0.8.0+, dangerous if misused)
@@ -66,7 +69,7 @@ func checkIntegerOverflowInFile(path string) ([]parser.Finding, error) {
 
 		// For Solidity < 0.8: flag arithmetic without SafeMath
 		if solidityMajor == 0 && solidityMinor < 8 {
-			if containsArithmetic(trimmed) && !strings.Contains(trimmed, "SafeMath") && !strings.HasPrefix(trimmed, "//") {
+			if op := arithmeticOp(trimmed); op != "" && !strings.Contains(trimmed, "SafeMath") && !strings.HasPrefix(trimmed, "//") {
 				findings = append(findings, parser.Finding{
 					ID:     fmt.Sprintf("CUSTOM-OVERFLOW-%d", len(findings)+1),
 					Source: "custom",
@@ -84,17 +87,23 @@ func checkIntegerOverflowInFile(path string) ([]parser.Finding, error) {
 					Remediation: "Upgrade to Solidity ^0.8.0 where overflow/underflow revert by default. " +
 						"If upgrading is not possible, use OpenZeppelin SafeMath for all arithmetic.",
 					SWCRef: "SWC-101",
+					CWERef: rules.Default().CWE("custom-integer-overflow"),
 					References: []string{
 						"https://swcregistry.io/docs/SWC-101",
 						"https://docs.openzeppelin.com/contracts/4.x/api/utils#SafeMath",
 					},
+					Evidence: []string{
+						fmt.Sprintf("`%s` resolves to Solidity %d.%d (< 0.8, no built-in overflow checks)", pragmaLine, solidityMajor, solidityMinor),
+						fmt.Sprintf("arithmetic `%s` at line %d col %d", strings.TrimSpace(op), lineNum, strings.Index(line, op)+1),
+						"no SafeMath call on the line",
+					},
 				})
 			}
 		}
 
 		// For Solidity >= 0.8: flag unchecked blocks containing arithmetic on user-supplied values
 		if solidityMajor == 0 && solidityMinor >= 8 && inUnchecked {
-			if containsArithmetic(trimmed) && !strings.HasPrefix(trimmed, "//") {
+			if op := arithmeticOp(trimmed); op != "" && !strings.HasPrefix(trimmed, "//") {
 				findings = append(findings, parser.Finding{
 					ID:     fmt.Sprintf("CUSTOM-UNCHECKED-%d", len(findings)+1),
 					Source: "custom",
@@ -112,9 +121,14 @@ func checkIntegerOverflowInFile(path string) ([]parser.Finding, error) {
 					Remediation: "Only use unchecked{} when overflow is mathematically impossible " +
 						"(e.g. loop counter bounded by array length). Add a comment explaining why it is safe.",
 					SWCRef: "SWC-101",
+					CWERef: rules.Default().CWE("custom-unchecked-arithmetic"),
 					References: []string{
 						"https://docs.soliditylang.org/en/latest/control-structures.html#checked-or-unchecked-arithmetic",
 					},
+					Evidence: []string{
+						fmt.Sprintf("unchecked block opened at line %d", uncheckedLine),
+						fmt.Sprintf("arithmetic `%s` at line %d col %d", strings.TrimSpace(op), lineNum, strings.Index(line, op)+1),
+					},
 				})
 			}
 		}
@@ -123,14 +137,15 @@ func checkIntegerOverflowInFile(path string) ([]parser.Finding, error) {
 	return findings, scanner.Err()
 }
 
-func containsArithmetic(line string) bool {
+// arithmeticOp returns the first arithmetic operator found in line, or "".
+func arithmeticOp(line string) string {
 	ops := []string{" + ", " - ", " * ", " / ", " % ", "++", "--", "+=", "-=", "*=", "/="}
 	for _, op := range ops {
 		if strings.Contains(line, op) {
-			return true
+			return op
 		}
 	}
-	return false
+	return ""
 }
 
 func extractSolidityVersion(pragma string) (major, minor int) {
@@ -27,7 +27,7 @@ contract Old {
 	err = os.WriteFile(tmpFile, []byte(content), 0644)
 	require.NoError(t, err)
 
-	findings, err := CheckIntegerOverflow(tmpFile)
+	findings, err := CheckIntegerOverflow(tmpFile, DefaultFilter)
 	require.NoError(t, err)
 
 	assert.Len(t, findings, 1)
@@ -54,7 +54,7 @@ contract New {
 	err = os.WriteFile(tmpFile, []byte(content), 0644)
 	require.NoError(t, err)
 
-	findings, err := CheckIntegerOverflow(tmpFile)
+	findings, err := CheckIntegerOverflow(tmpFile, DefaultFilter)
 	require.NoError(t, err)
 
 	assert.Len(t, findings, 1)
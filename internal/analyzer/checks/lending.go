@@ -0,0 +1,251 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+// lendingParam is a family of risk parameters of a lending protocol, with
+// the bounds a setter has to enforce on it.
+type lendingParam struct {
+	rule         string
+	what         string
+	name         *regexp.Regexp
+	lower, upper bool
+	risk         string
+}
+
+// lendingParams are matched in order, so liquidationThreshold is taken for
+// a collateral factor before anything else.
+var lendingParams = []lendingParam{
+	{
+		rule:  "custom-collateral-factor-bound",
+		what:  "collateral factor",
+		name:  regexp.MustCompile(`(?i)collateral\w*factor|ltv|loantovalue|liquidationthreshold`),
+		upper: true,
+		risk:  "A factor above 100% lets borrowers take out more than their collateral is worth, leaving the protocol with bad debt.",
+	},
+	{
+		rule:  "custom-liquidation-bonus-bound",
+		what:  "liquidation bonus",
+		name:  regexp.MustCompile(`(?i)liquidation\w*(bonus|incentive|discount|penalty|reward)`),
+		lower: true,
+		upper: true,
+		risk:  "A zero bonus leaves liquidators no incentive, so underwater positions accrue bad debt; an extreme one lets them seize far more collateral than the debt they repay.",
+	},
+	{
+		rule:  "custom-rate-model-bound",
+		what:  "interest rate model parameter",
+		name:  regexp.MustCompile(`(?i)baserate|multiplier|kink|slope|optimalutili[sz]ation|reservefactor|rateper(block|second|year)|maxborrowrate`),
+		upper: true,
+		risk:  "An extreme rate can make every borrow position liquidatable at once, or overflow interest accrual and freeze the market.",
+	},
+}
+
+var (
+	// lendingRe matches the vocabulary of a lending protocol.
+	lendingRe = regexp.MustCompile(`(?i)borrow|liquidat|collateral|utili[sz]ation`)
+	// paramAssignRe matches an assignment to a state variable, a mapping
+	// entry or a struct field, e.g. markets[asset].collateralFactor = x.
+	paramAssignRe = regexp.MustCompile(`^([A-Za-z_]\w*)((?:\[[^\]]*\]|\.\w+)*)\s*=[^=](.*)$`)
+	fieldRe       = regexp.MustCompile(`\.(\w+)$`)
+	// validatorRe matches passing a value to a validation helper, which is
+	// taken to bound it both ways.
+	validatorRe = regexp.MustCompile(`(?i)\b_?(validate|check|ensure)\w*\s*\(`)
+)
+
+// CheckLendingParams flags setters of lending risk parameters that accept
+// any value: collateral factors and LTVs with no cap at 100%, liquidation
+// bonuses that can be set to zero or to extreme values, and interest rate
+// model parameters with no upper bound. These are set by admins or
+// governance, so a mistake or a compromised key is enough to break the
+// market. Only contracts that talk about borrowing, collateral or
+// liquidation are checked.
+func CheckLendingParams(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-LENDING-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" || !isLending(file, c) {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if fn.Name == "constructor" || fn.Visibility == "internal" || fn.Visibility == "private" || fn.Mutability != "" {
+					continue
+				}
+				for _, f := range unboundedLendingParams(file, c, fn) {
+					add(f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// isLending reports whether c looks like part of a lending protocol.
+func isLending(file *solidity.File, c solidity.Contract) bool {
+	for n := c.Line; n <= c.End; n++ {
+		if lendingRe.MatchString(file.Line(n)) {
+			return true
+		}
+	}
+	return false
+}
+
+// unboundedLendingParams reports the risk parameters fn sets from its
+// arguments without the bounds their family needs, once per parameter.
+func unboundedLendingParams(file *solidity.File, c solidity.Contract, fn solidity.Function) []parser.Finding {
+	stmts := statements(file, fn)
+	var findings []parser.Finding
+	seen := map[string]bool{}
+	for _, s := range stmts {
+		m := paramAssignRe.FindStringSubmatch(s.Text)
+		if m == nil || !isStateVar(c, m[1]) && !storagePointer(stmts, m[1]) {
+			continue
+		}
+		name := m[1]
+		if f := fieldRe.FindStringSubmatch(m[2]); f != nil {
+			name = f[1]
+		}
+		p, ok := lendingParamFor(name)
+		if !ok || seen[name] {
+			continue
+		}
+		arg := paramIn(fn, m[3])
+		if arg == "" {
+			continue
+		}
+		lower, upper := bounds(stmts, arg, name)
+		var missing []string
+		if p.lower && !lower {
+			missing = append(missing, "below")
+		}
+		if p.upper && !upper {
+			missing = append(missing, "above")
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		seen[name] = true
+
+		unbounded := "without bounding it from " + strings.Join(missing, " or ")
+		if p.rule == "custom-collateral-factor-bound" {
+			unbounded = "without capping it at 100%"
+		}
+		findings = append(findings, parser.Finding{
+			Check: p.rule,
+			Title: fmt.Sprintf("Unbounded %s: %s.%s", titleCase(p.what), c.Name, name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() sets the %s '%s' from its argument '%s' %s. %s",
+				file.Path, s.Line, c.Name, fn.Name, p.what, name, arg, unbounded, p.risk,
+			),
+			Severity:   parser.SeverityHigh,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("no condition bounds `%s` or `%s` from %s in %s()", arg, name, strings.Join(missing, " or "), fn.Name),
+			},
+		})
+	}
+	return findings
+}
+
+func lendingParamFor(name string) (lendingParam, bool) {
+	for _, p := range lendingParams {
+		if p.name.MatchString(name) {
+			return p, true
+		}
+	}
+	return lendingParam{}, false
+}
+
+// storagePointer reports whether name is declared in the function as a
+// storage pointer, e.g. Market storage market = markets[asset].
+func storagePointer(stmts []statement, name string) bool {
+	re := regexp.MustCompile(`\bstorage\s+` + regexp.QuoteMeta(name) + `\b`)
+	for _, s := range stmts {
+		if re.MatchString(s.Text) {
+			return true
+		}
+	}
+	return false
+}
+
+// bounds reports whether a condition bounds any of names from below or
+// from above. A require or assert bounds in the direction it compares; an
+// if, which is expected to revert, in the opposite one.
+func bounds(stmts []statement, names ...string) (lower, upper bool) {
+	for _, s := range stmts {
+		if validatorRe.MatchString(s.Text) && mentions(s.Text, names...) {
+			return true, true
+		}
+		if !conditionRe.MatchString(s.Text) && !strings.HasPrefix(s.Text, "assert") {
+			continue
+		}
+		guard := !strings.HasPrefix(strings.TrimPrefix(s.Text, "else "), "if")
+		for _, name := range names {
+			n := regexp.QuoteMeta(name)
+			var ops []string
+			for _, m := range regexp.MustCompile(`\b`+n+`\s*(<=?|>=?|==|!=)`).FindAllStringSubmatch(s.Text, -1) {
+				ops = append(ops, m[1])
+			}
+			for _, m := range regexp.MustCompile(`(<=?|>=?|==|!=)\s*`+n+`\b`).FindAllStringSubmatch(s.Text, -1) {
+				ops = append(ops, strings.NewReplacer("<", ">", ">", "<").Replace(m[1]))
+			}
+			for _, op := range ops {
+				// Normalized to name OP bound.
+				switch {
+				case guard && op[0] == '<', !guard && op[0] == '>':
+					upper = true
+				case guard && (op[0] == '>' || op == "!="), !guard && (op[0] == '<' || op == "=="):
+					lower = true
+				}
+			}
+		}
+	}
+	return lower, upper
+}
+
+// mentions reports whether text uses any of names as an identifier.
+func mentions(text string, names ...string) bool {
+	for _, name := range names {
+		if regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`).MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// titleCase capitalizes the first letter of each word.
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,68 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckLendingParams(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Pool {
+    struct Reserve { uint256 ltv; uint256 liquidationBonus; }
+    mapping(address => Reserve) reserves;
+    uint256 public reserveFactor;
+
+    constructor(uint256 factor) {
+        reserveFactor = factor;
+    }
+
+    function configure(address asset, uint256 ltv, uint256 bonus) external onlyAdmin {
+        Reserve storage r = reserves[asset];
+        if (bonus == 0) revert InvalidBonus();
+        r.ltv = ltv;
+        r.liquidationBonus = bonus;
+    }
+
+    function setReserveFactor(uint256 factor) external onlyAdmin {
+        _validateFactor(factor);
+        reserveFactor = factor;
+    }
+
+    function borrow(uint256 amount) external {}
+}
+
+contract Rewards {
+    uint256 public multiplier;
+
+    function setMultiplier(uint256 m) external onlyOwner {
+        multiplier = m;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pool.sol"), []byte(content), 0644))
+
+	findings, err := CheckLendingParams(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "the constructor, a validated reserve factor and a non-lending contract are skipped")
+
+	ltv := findings[0]
+	assert.Equal(t, "custom-collateral-factor-bound", ltv.Check)
+	assert.Equal(t, "Unbounded Collateral Factor: Pool.ltv", ltv.Title)
+	assert.Equal(t, parser.SeverityHigh, ltv.Severity)
+	assert.Equal(t, []int{15}, ltv.Lines)
+	assert.Equal(t, "configure", ltv.Function)
+	assert.Contains(t, ltv.Description, "without capping it at 100%")
+
+	bonus := findings[1]
+	assert.Equal(t, "custom-liquidation-bonus-bound", bonus.Check)
+	assert.Equal(t, "CUSTOM-LENDING-2", bonus.ID)
+	assert.Equal(t, "no condition bounds `bonus` or `liquidationBonus` from above in configure()", bonus.Evidence[1],
+		"if (bonus == 0) revert bounds it from below")
+}
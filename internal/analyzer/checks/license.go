@@ -0,0 +1,197 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var spdxRe = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\n*]+)`)
+
+// licenseClass groups SPDX identifiers by what they demand of code that
+// imports them.
+type licenseClass int
+
+const (
+	licenseUnknown licenseClass = iota
+	licensePermissive
+	licenseWeakCopyleft
+	licenseStrongCopyleft
+	licenseProprietary
+)
+
+// CheckLicenses is release hygiene: it flags files without an SPDX license
+// identifier (solc warns about them too) and imports that mix incompatible
+// licenses, such as a GPL library imported into an MIT or UNLICENSED codebase.
+func CheckLicenses(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := solidity.LoadGraph(files)
+	if err != nil {
+		return nil, err
+	}
+
+	db := rules.Default()
+	licenses := map[string]string{}
+	licenseLines := map[string]int{}
+	var findings []parser.Finding
+	for _, path := range graph.Paths() {
+		id, line := spdxIdentifier(path)
+		licenses[path] = id
+		licenseLines[path] = line
+		if id != "" {
+			continue
+		}
+		findings = append(findings, parser.Finding{
+			ID:          fmt.Sprintf("CUSTOM-LICENSE-%d", len(findings)+1),
+			Source:      "custom",
+			Check:       "custom-missing-spdx",
+			Title:       "Missing SPDX License Identifier",
+			Description: fmt.Sprintf("%s has no SPDX-License-Identifier comment. The license of published source and verified contracts is ambiguous without one.", path),
+			Severity:    parser.SeverityInformational,
+			Confidence:  "High",
+			File:        path,
+			Lines:       []int{line},
+			Remediation: db.Remediation("custom-missing-spdx"),
+			References:  []string{"https://docs.soliditylang.org/en/latest/layout-of-source-files.html#spdx-license-identifier"},
+			Evidence:    []string{"no `SPDX-License-Identifier:` comment found in the file"},
+		})
+	}
+
+	for _, path := range graph.Paths() {
+		from := licenses[path]
+		if from == "" {
+			continue
+		}
+		for _, dep := range graph.Imports[path] {
+			to := licenses[dep]
+			if to == "" || licensesCompatible(from, to) {
+				continue
+			}
+			findings = append(findings, parser.Finding{
+				ID:     fmt.Sprintf("CUSTOM-LICENSE-%d", len(findings)+1),
+				Source: "custom",
+				Check:  "custom-license-conflict",
+				Title:  fmt.Sprintf("Incompatible License Mix: %s imports %s", from, to),
+				Description: fmt.Sprintf(
+					"%s is licensed %s but imports %s, licensed %s. The combined work cannot be distributed under %s.",
+					path, from, dep, to, from,
+				),
+				Severity:    parser.SeverityInformational,
+				Confidence:  "Medium",
+				File:        path,
+				Lines:       []int{importLine(graph.Files[path], dep)},
+				Remediation: db.Remediation("custom-license-conflict"),
+				References:  []string{"https://www.gnu.org/licenses/license-compatibility.html"},
+				Evidence: []string{
+					fmt.Sprintf("SPDX `%s` at %s:%d", from, path, licenseLines[path]),
+					fmt.Sprintf("SPDX `%s` at %s:%d", to, dep, licenseLines[dep]),
+					fmt.Sprintf("%s code may not be included in %s code", to, from),
+				},
+			})
+		}
+	}
+	return findings, nil
+}
+
+// spdxIdentifier returns the file's SPDX expression and the line it is on.
+// A missing identifier is reported at line 1.
+func spdxIdentifier(path string) (string, int) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", 1
+	}
+	text := string(data)
+	m := spdxRe.FindStringSubmatchIndex(text)
+	if m == nil {
+		return "", 1
+	}
+	return strings.TrimSpace(text[m[2]:m[3]]), lineOf(text, m[0])
+}
+
+// licensesCompatible reports whether code under importer may include code
+// under imported. For SPDX "OR" expressions any alternative may be chosen.
+func licensesCompatible(importer, imported string) bool {
+	for _, a := range spdxAlternatives(importer) {
+		for _, b := range spdxAlternatives(imported) {
+			if licensePairCompatible(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func licensePairCompatible(importer, imported string) bool {
+	if importer == imported {
+		return true
+	}
+	from, to := classifyLicense(importer), classifyLicense(imported)
+	if from == licenseUnknown || to == licenseUnknown {
+		return true // no basis to judge
+	}
+	switch to {
+	case licenseStrongCopyleft:
+		if from != licenseStrongCopyleft {
+			return false
+		}
+		// GPL-2.0-only cannot absorb GPL-3.0 or AGPL code.
+		return !(isGPL2Only(importer) && !isGPL2Only(imported))
+	case licensePermissive:
+		// Apache-2.0's patent terms are incompatible with GPL-2.0-only.
+		return !(isGPL2Only(importer) && strings.HasPrefix(imported, "Apache-2.0"))
+	}
+	return true
+}
+
+// spdxAlternatives splits "MIT OR Apache-2.0" into its alternatives. AND and
+// WITH clauses are kept whole and classified by their first identifier.
+func spdxAlternatives(expr string) []string {
+	expr = strings.Trim(expr, "() ")
+	parts := strings.Split(expr, " OR ")
+	for i, p := range parts {
+		p = strings.Trim(p, "() ")
+		if f := strings.Fields(p); len(f) > 0 {
+			p = f[0]
+		}
+		parts[i] = p
+	}
+	return parts
+}
+
+func classifyLicense(id string) licenseClass {
+	switch {
+	case id == "UNLICENSED":
+		return licenseProprietary
+	case strings.HasPrefix(id, "GPL-"), strings.HasPrefix(id, "AGPL-"):
+		return licenseStrongCopyleft
+	case strings.HasPrefix(id, "LGPL-"), strings.HasPrefix(id, "MPL-"):
+		return licenseWeakCopyleft
+	case id == "MIT", id == "ISC", id == "Unlicense", id == "CC0-1.0", id == "0BSD", id == "WTFPL",
+		strings.HasPrefix(id, "Apache-"), strings.HasPrefix(id, "BSD-"):
+		return licensePermissive
+	}
+	return licenseUnknown
+}
+
+func isGPL2Only(id string) bool {
+	return id == "GPL-2.0" || id == "GPL-2.0-only"
+}
+
+// importLine returns the line of the import in f that resolves to dep.
+func importLine(f *solidity.File, dep string) int {
+	for _, imp := range f.Imports {
+		if strings.HasSuffix(filepath.ToSlash(dep), strings.TrimLeft(imp.Path, "./")) {
+			return imp.Line
+		}
+	}
+	return 1
+}
@@ -0,0 +1,43 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLicenses(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("GplLib.sol", "// SPDX-License-Identifier: GPL-3.0-or-later\nlibrary GplLib {}\n")
+	write("MitLib.sol", "// SPDX-License-Identifier: MIT\nlibrary MitLib {}\n")
+	write("Token.sol", "// SPDX-License-Identifier: MIT\nimport \"./MitLib.sol\";\nimport \"./GplLib.sol\";\ncontract Token {}\n")
+	write("Vault.sol", "/* SPDX-License-Identifier: GPL-3.0 */\nimport \"./MitLib.sol\";\ncontract Vault {}\n")
+	write("NoLicense.sol", "pragma solidity ^0.8.0;\ncontract NoLicense {}\n")
+
+	findings, err := CheckLicenses(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	assert.Equal(t, "custom-missing-spdx", findings[0].Check)
+	assert.Equal(t, filepath.Join(dir, "NoLicense.sol"), findings[0].File)
+
+	assert.Equal(t, "custom-license-conflict", findings[1].Check)
+	assert.Equal(t, filepath.Join(dir, "Token.sol"), findings[1].File)
+	assert.Equal(t, []int{3}, findings[1].Lines)
+}
+
+func TestLicensesCompatible(t *testing.T) {
+	assert.True(t, licensesCompatible("MIT", "MIT"))
+	assert.True(t, licensesCompatible("GPL-3.0", "MIT"))
+	assert.True(t, licensesCompatible("MIT OR GPL-3.0", "GPL-3.0-only"))
+	assert.False(t, licensesCompatible("UNLICENSED", "AGPL-3.0"))
+	assert.False(t, licensesCompatible("GPL-2.0-only", "GPL-3.0"))
+	assert.False(t, licensesCompatible("GPL-2.0-only", "Apache-2.0"))
+	assert.True(t, licensesCompatible("MIT", "Custom-License"))
+}
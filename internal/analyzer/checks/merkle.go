@@ -0,0 +1,237 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	// merkleVerifyRe matches verifying a Merkle proof through OpenZeppelin's
+	// MerkleProof (called directly or attached with using-for) or a helper.
+	merkleVerifyRe = regexp.MustCompile(`\bMerkleProof\w*\.(verify|verifyCalldata|multiProofVerify|processProof|processProofCalldata)\s*\(|\b\w*[pP]roof\w*\.(verify|verifyCalldata|processProof)\s*\(|\b_?verify(Proof|Merkle)\w*\s*\(`)
+	// claimedRe matches recording a claim: marking a bitmap or mapping, as
+	// Uniswap's MerkleDistributor does with _setClaimed(index), or adding to
+	// a cumulative claimed amount.
+	claimedRe = regexp.MustCompile(`(?i)\b_?(set|mark)\w*(claimed|used|redeemed)\w*\s*\(|\b\w*(claim|used|redeem|spent|nullifier|bitmap)\w*\s*(\[[^\]]*\])+\s*(=|\|=|\+=)[^=]|\b\w*(claim|bitmap)\w*\.(set|setTo)\s*\(`)
+	// packedLeafRe matches hashing packed arguments, which a leaf is not
+	// protected from second preimages by unless it is hashed again.
+	packedLeafRe = regexp.MustCompile(`\bkeccak256\s*\(\s*abi\.encodePacked\s*\(`)
+	// payoutRe matches sending tokens or minting; the recipient is captured.
+	payoutRe = regexp.MustCompile(`\b(?:safeTransfer|transfer|_mint|mint|_safeMint|safeMint)\s*\(\s*([\w.]+)\s*,|\b(?:safeTransferFrom|transferFrom)\s*\(\s*[^,]+,\s*([\w.]+)\s*,|\bpayable\s*\(\s*([\w.]+)\s*\)`)
+)
+
+// CheckMerkleClaims flags Merkle airdrop and distributor claims that can
+// be abused: claims that verify a proof but never record it, so the same
+// proof pays out again; leaves hashed once over abi.encodePacked, which an
+// intermediate node of the tree can pass for; and payouts to a recipient
+// the leaf does not commit to, so whoever sees a proof first can take it.
+func CheckMerkleClaims(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-MERKLE-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if fn.Visibility == "internal" || fn.Visibility == "private" || fn.Mutability != "" || fn.End <= fn.Line {
+					continue
+				}
+				stmts := statements(file, fn)
+				verify, ok := proofVerification(stmts)
+				if !ok {
+					continue
+				}
+				if f, ok := doubleClaim(file, c, fn, stmts, verify); ok {
+					add(f)
+				}
+				if f, ok := packedLeaf(file, c, fn, stmts); ok {
+					add(f)
+				}
+				if f, ok := unboundRecipient(file, c, fn, stmts); ok {
+					add(f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// proofVerification returns the statement that verifies a Merkle proof,
+// if any; the header is skipped.
+func proofVerification(stmts []statement) (statement, bool) {
+	for _, s := range stmts[1:] {
+		if merkleVerifyRe.MatchString(s.Text) {
+			return s, true
+		}
+	}
+	return statement{}, false
+}
+
+// merkleLeaf returns the hashing statements of a claim, which are taken to
+// build the leaf.
+func merkleLeaf(stmts []statement) string {
+	var parts []string
+	for _, s := range stmts[1:] {
+		if strings.Contains(s.Text, "keccak256") {
+			parts = append(parts, s.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// doubleClaim reports a claim that records neither in its body nor in the
+// contract's functions it calls that the proof has been used.
+func doubleClaim(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement, verify statement) (parser.Finding, bool) {
+	scopes := [][]statement{stmts}
+	body := functionBody(file, fn)
+	for _, callee := range c.Functions {
+		if callee.Name != fn.Name && callee.End > callee.Line && regexp.MustCompile(`\b`+regexp.QuoteMeta(callee.Name)+`\s*\(`).MatchString(body) {
+			scopes = append(scopes, statements(file, callee))
+		}
+	}
+	for _, stmts := range scopes {
+		for _, s := range stmts[1:] {
+			if claimedRe.MatchString(s.Text) {
+				return parser.Finding{}, false
+			}
+		}
+	}
+	return parser.Finding{
+		Check: "custom-merkle-double-claim",
+		Title: fmt.Sprintf("Merkle Claim Not Recorded: %s.%s()", c.Name, fn.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — %s.%s() verifies a Merkle proof but never marks it as claimed. The same proof can be replayed until the contract is drained.",
+			file.Path, verify.Line, c.Name, fn.Name,
+		),
+		Severity:   parser.SeverityCritical,
+		Confidence: "Medium",
+		File:       file.Path,
+		Lines:      []int{verify.Line},
+		Function:   fn.Name,
+		Evidence: []string{
+			fmt.Sprintf("`%s` at line %d", verify.Text, verify.Line),
+			fmt.Sprintf("no claimed bitmap, mapping or _setClaimed call in %s() or the functions it calls", fn.Name),
+		},
+	}, true
+}
+
+// packedLeaf reports a leaf hashed once over abi.encodePacked. A 64-byte
+// preimage is indistinguishable from a pair of child hashes, so an
+// intermediate node can be claimed as a leaf; packed dynamic types also
+// collide with each other.
+func packedLeaf(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement) (parser.Finding, bool) {
+	for _, s := range stmts[1:] {
+		if !packedLeafRe.MatchString(s.Text) || strings.Count(s.Text, "keccak256") > 1 {
+			continue
+		}
+		return parser.Finding{
+			Check: "custom-merkle-leaf-encoding",
+			Title: fmt.Sprintf("Merkle Leaf Hashed Once Over Packed Data: %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() builds its leaf with a single keccak256 over abi.encodePacked. If the packed data is 64 bytes long, an intermediate node of the tree passes for a leaf (a second preimage), and packed dynamic values can collide, so claims the tree never contained can be proven.",
+				file.Path, s.Line, c.Name, fn.Name,
+			),
+			Severity:   parser.SeverityMedium,
+			Confidence: "Low",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				"the leaf is not hashed twice",
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// unboundRecipient reports a payout to msg.sender or a parameter that the
+// leaf does not commit to and that is not checked against msg.sender, so
+// anyone holding a valid proof can redirect the claim.
+func unboundRecipient(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement) (parser.Finding, bool) {
+	leaf := merkleLeaf(stmts)
+	if leaf == "" {
+		// The leaf is built elsewhere, so what it commits to is unknown.
+		return parser.Finding{}, false
+	}
+	for _, s := range stmts[1:] {
+		match := payoutRe.FindStringSubmatch(s.Text)
+		if match == nil {
+			continue
+		}
+		to := match[1] + match[2] + match[3]
+		var bound bool
+		switch {
+		case to == "msg.sender":
+			bound = strings.Contains(leaf, "msg.sender") || senderChecked(fn, stmts, leaf)
+		case paramIn(fn, to) == to:
+			// Either the leaf names the recipient, or the caller is checked
+			// to be the recipient or the account the leaf names.
+			bound = mentions(leaf, to) || senderChecked(fn, stmts, to) || senderChecked(fn, stmts, leaf)
+		default:
+			// A state variable or local, such as a treasury, is not the caller's.
+			bound = true
+		}
+		if bound {
+			continue
+		}
+		return parser.Finding{
+			Check: "custom-merkle-arbitrary-recipient",
+			Title: fmt.Sprintf("Merkle Claim to Arbitrary Recipient: %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() pays '%s', which the proven leaf does not include. Proofs are public once the tree is published, so anyone can claim another user's allocation for themselves, or front-run a pending claim.",
+				file.Path, s.Line, c.Name, fn.Name, to,
+			),
+			Severity:   parser.SeverityHigh,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("`%s` is not part of the leaf and is not compared with msg.sender", to),
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// senderChecked reports whether a condition compares msg.sender with a
+// parameter mentioned in text, e.g. require(account == msg.sender) when
+// the leaf commits to account.
+func senderChecked(fn solidity.Function, stmts []statement, text string) bool {
+	for _, s := range stmts[1:] {
+		if !conditionRe.MatchString(s.Text) || !strings.Contains(s.Text, "msg.sender") || !strings.Contains(s.Text, "=") {
+			continue
+		}
+		for _, p := range fn.Params {
+			if p.Name != "" && mentions(s.Text, p.Name) && mentions(text, p.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckMerkleClaims(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Drop {
+    using MerkleProof for bytes32[];
+    bytes32 public root;
+    mapping(address => bool) public hasClaimed;
+
+    function claim(address account, uint256 amount, bytes32[] calldata proof) external {
+        bytes32 leaf = keccak256(bytes.concat(keccak256(abi.encode(account, amount))));
+        require(proof.verify(root, leaf), "invalid proof");
+        token.safeTransfer(msg.sender, amount);
+    }
+
+    function claimOnce(uint256 amount, bytes32[] calldata proof) external {
+        require(proof.verify(root, _leaf(msg.sender, amount)), "invalid proof");
+        _record(msg.sender);
+        _mint(msg.sender, amount);
+    }
+
+    function _record(address account) internal {
+        hasClaimed[account] = true;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Drop.sol"), []byte(content), 0644))
+
+	findings, err := CheckMerkleClaims(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "a claim recorded by a helper, with a leaf built elsewhere, is skipped")
+
+	replay := findings[0]
+	assert.Equal(t, "custom-merkle-double-claim", replay.Check)
+	assert.Equal(t, parser.SeverityCritical, replay.Severity)
+	assert.Equal(t, []int{10}, replay.Lines)
+	assert.Equal(t, "CUSTOM-MERKLE-1", replay.ID)
+
+	recipient := findings[1]
+	assert.Equal(t, "custom-merkle-arbitrary-recipient", recipient.Check)
+	assert.Equal(t, "Merkle Claim to Arbitrary Recipient: Drop.claim()", recipient.Title)
+	assert.Equal(t, []int{11}, recipient.Lines)
+	assert.Contains(t, recipient.Description, "pays 'msg.sender'")
+}
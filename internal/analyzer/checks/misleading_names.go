@@ -0,0 +1,364 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+// builtins are the global names a declaration can shadow, with what code
+// using the name expects to get.
+var builtins = map[string]string{
+	"now":          "the block timestamp",
+	"block":        "the current block's properties",
+	"msg":          "the caller, value and calldata",
+	"tx":           "the transaction origin and gas price",
+	"gasleft":      "the remaining gas",
+	"blockhash":    "recent block hashes",
+	"require":      "the check that reverts the call",
+	"assert":       "the check that reverts the call",
+	"revert":       "the revert that aborts the call",
+	"keccak256":    "the keccak256 hash",
+	"sha256":       "the sha256 hash",
+	"sha3":         "the keccak256 hash",
+	"ripemd160":    "the ripemd160 hash",
+	"ecrecover":    "signature recovery",
+	"addmod":       "modular addition",
+	"mulmod":       "modular multiplication",
+	"selfdestruct": "contract destruction",
+	"suicide":      "contract destruction",
+	"abi":          "ABI encoding and decoding",
+}
+
+// standardViews are the ERC-20 getters integrators call as views.
+var standardViews = map[string]bool{
+	"balanceOf": true, "totalSupply": true, "allowance": true,
+	"name": true, "symbol": true, "decimals": true,
+}
+
+var (
+	localDeclRe      = regexp.MustCompile(`^([A-Za-z_$][\w$.]*(?:\s*\[[^\]]*\])*)\s+(?:(?:memory|storage|calldata|payable)\s+)*([A-Za-z_$][\w$]*)\s*(?:=|$)`)
+	safeNameRe       = regexp.MustCompile(`^_?safe([A-Z0-9_]|$)`)
+	safeArithRe      = regexp.MustCompile(`(?i)^_?safe(add|sub|mul|div|mod|pow)`)
+	truncatingCastRe = regexp.MustCompile(`\bu?int(8|16|24|32|40|48|56|64|72|80|88|96|104|112|120|128|136|144|152|160|168|176|184|192|200|208|216|224|232|240|248)\s*\(`)
+	checkedRe        = regexp.MustCompile(`\b(require|assert|if)\s*\(|\brevert\b|\?`)
+	tokenCallRe      = regexp.MustCompile(`^[\w$.\[\]()]*?\.(transfer|transferFrom|approve|send)\s*\(`)
+	usesResultRe     = regexp.MustCompile(`^(require|assert|if|return|bool)\b`)
+	lowCallRe        = regexp.MustCompile(`\.call\s*(\{[^}]*\})?\s*\(`)
+	successVarRe     = regexp.MustCompile(`\(\s*bool\s+([A-Za-z_$][\w$]*)`)
+	tokenDataRe      = regexp.MustCompile(`\.(transfer|transferFrom|approve)\b|0xa9059cbb|0x23b872dd|0x095ea7b3`)
+	allowanceRe      = regexp.MustCompile(`(?i)allow|\b_spend\w*\s*\(`)
+	nftReceiverRe    = regexp.MustCompile(`onERC721Received|onERC1155Received|onERC1155BatchReceived|_checkOnERC721Received|_doSafe\w*AcceptanceCheck`)
+	pragmaDeclRe     = regexp.MustCompile(`(?m)^\s*pragma\s+solidity[^;]*;`)
+	internalCall     = regexp.MustCompile(`(?:^|[^\w$.])(_[\w$]+)\s*\(`)
+)
+
+// CheckMisleadingNames flags names that promise something the code does
+// not do: declarations that shadow builtins such as now, msg or require;
+// ERC-20 functions that do not behave like the standard (a transferFrom
+// that ignores allowances, an approve that records none, balanceOf or
+// totalSupply writing state); and safe* wrappers that are not safe, such as
+// a safeTransfer that discards the token's return value or a safeAdd
+// without an overflow check before Solidity 0.8.
+func CheckMisleadingNames(target string, filter Filter) ([]parser.Finding, error) {
+	paths, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-NAME-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	for _, path := range paths {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		pragma := pragmaDeclRe.FindString(file.Code)
+		major, minor := extractSolidityVersion(strings.TrimSpace(pragma))
+		checked := major > 0 || minor >= 8
+
+		for _, c := range file.Contracts {
+			if c.Kind == "interface" {
+				continue
+			}
+			for _, f := range builtinShadowing(file, c) {
+				add(f)
+			}
+			for _, fn := range c.Functions {
+				if !hasBody(file, fn) {
+					continue
+				}
+				if isToken(c) {
+					if f, ok := nonStandard(file, c, fn); ok {
+						add(f)
+					}
+				}
+				if f, ok := unsafeWrapper(file, c, fn, checked); ok {
+					add(f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// hasBody reports whether fn is implemented rather than only declared.
+func hasBody(file *solidity.File, fn solidity.Function) bool {
+	return fn.End > fn.Line || strings.Contains(file.Line(fn.Line), "{")
+}
+
+// builtinShadowing reports the state variables, functions, modifiers,
+// parameters and local variables of c named after a builtin. Functions and
+// modifiers replace the builtin for every call in the contract, so a
+// require that no longer reverts is Medium; variables are Low.
+func builtinShadowing(file *solidity.File, c solidity.Contract) []parser.Finding {
+	var findings []parser.Finding
+	report := func(kind, name string, line int, fn string) {
+		severity := parser.SeverityLow
+		effect := fmt.Sprintf("code in its scope that uses `%s` gets the %s instead of %s", name, kind, builtins[name])
+		if kind == "function" || kind == "modifier" {
+			severity = parser.SeverityMedium
+			effect = fmt.Sprintf("every use of `%s` in %s and the contracts inheriting it runs this %s instead of %s", name, c.Name, kind, builtins[name])
+		}
+		findings = append(findings, parser.Finding{
+			Check: "custom-builtin-shadowing",
+			Title: fmt.Sprintf("Builtin Shadowed: %s in %s", name, c.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — the %s `%s` shadows the builtin of the same name: %s. Readers, and code written against the builtin, expect the global.",
+				file.Path, line, kind, name, effect,
+			),
+			Severity:   severity,
+			Confidence: "High",
+			File:       file.Path,
+			Lines:      []int{line},
+			Function:   fn,
+			Evidence:   []string{fmt.Sprintf("%s %s declared at line %d", kind, name, line)},
+		})
+	}
+
+	for _, v := range c.StateVars {
+		if _, ok := builtins[v.Name]; ok {
+			report("state variable", v.Name, v.Line, "")
+		}
+	}
+	callables := append(append([]solidity.Function(nil), c.Functions...), c.Modifiers...)
+	for i, fn := range callables {
+		kind := "function"
+		if i >= len(c.Functions) {
+			kind = "modifier"
+		}
+		if _, ok := builtins[fn.Name]; ok {
+			report(kind, fn.Name, fn.Line, "")
+		}
+		for _, p := range fn.Params {
+			if _, ok := builtins[p.Name]; ok {
+				report("parameter", p.Name, fn.Line, fn.Name)
+			}
+		}
+		if !hasBody(file, fn) {
+			continue
+		}
+		for _, s := range statements(file, fn)[1:] {
+			m := localDeclRe.FindStringSubmatch(s.Text)
+			if m == nil || slices.Contains([]string{"return", "emit", "delete", "else", "new", "do"}, m[1]) {
+				continue
+			}
+			if _, ok := builtins[m[2]]; ok {
+				report("local variable", m[2], s.Line, fn.Name)
+			}
+		}
+	}
+	return findings
+}
+
+// nonStandard reports an ERC-20 entry point of token c that does not do
+// what the standard's name promises: transferFrom without an allowance
+// check, approve without recording an allowance, or a getter that writes
+// state. Functions that call into code solsec cannot see (super or an
+// inherited internal helper) are skipped.
+func nonStandard(file *solidity.File, c solidity.Contract, fn solidity.Function) (parser.Finding, bool) {
+	if fn.Visibility == "internal" || fn.Visibility == "private" {
+		return parser.Finding{}, false
+	}
+	body := reachable(file, c, fn)
+	if opaque(c, body) {
+		return parser.Finding{}, false
+	}
+
+	var severity parser.Severity
+	var problem, evidence string
+	switch {
+	case fn.Name == "transferFrom" && len(fn.Params) == 3 && !allowanceRe.MatchString(body):
+		severity = parser.SeverityHigh
+		problem = "never reads or reduces the spender's allowance, so anyone can move any holder's tokens"
+		if guarded(file, fn) {
+			severity = parser.SeverityMedium
+			problem = "never reads or reduces the spender's allowance; the caller it is restricted to can move any holder's tokens"
+		}
+		evidence = "no allowance read or update in transferFrom or the functions it calls"
+	case fn.Name == "approve" && len(fn.Params) == 2 && !allowanceRe.MatchString(body):
+		severity = parser.SeverityMedium
+		problem = "does not record an allowance, so transferFrom calls that rely on the approval fail, or approve does something else entirely"
+		evidence = "no allowance write in approve or the functions it calls"
+	case standardViews[fn.Name] && fn.Mutability == "":
+		written := writtenStateVar(c, body)
+		if written == "" {
+			return parser.Finding{}, false
+		}
+		severity = parser.SeverityMedium
+		problem = fmt.Sprintf("is not a view and writes %s. Integrations call it through the standard's view signature (a STATICCALL), which reverts on the write, and every other call has a side effect", written)
+		evidence = fmt.Sprintf("%s is written in %s or the functions it calls", written, fn.Name)
+	default:
+		return parser.Finding{}, false
+	}
+	return parser.Finding{
+		Check: "custom-misleading-standard-function",
+		Title: fmt.Sprintf("Non-Standard %s: %s", fn.Name, c.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — %s.%s has the ERC-20 name and signature but %s. Wallets, DEXes and auditors assume the standard behavior.",
+			file.Path, fn.Line, c.Name, fn.Name, problem,
+		),
+		Severity:   severity,
+		Confidence: "Medium",
+		File:       file.Path,
+		Lines:      []int{fn.Line},
+		Function:   fn.Name,
+		Evidence:   []string{evidence},
+	}, true
+}
+
+// opaque reports whether body calls super or an internal helper c does not
+// declare, whose behavior is out of sight.
+func opaque(c solidity.Contract, body string) bool {
+	if strings.Contains(body, "super.") {
+		return true
+	}
+	for _, m := range internalCall.FindAllStringSubmatch(body, -1) {
+		name := m[1]
+		if name == "_msgSender" || name == "_msgData" {
+			continue
+		}
+		if !slices.ContainsFunc(c.Functions, func(fn solidity.Function) bool { return fn.Name == name }) {
+			return true
+		}
+	}
+	return false
+}
+
+// writtenStateVar returns the first mutable state variable of c that body
+// assigns, increments or decrements, or "".
+func writtenStateVar(c solidity.Contract, body string) string {
+	for _, v := range c.StateVars {
+		if v.Constant {
+			continue
+		}
+		name := regexp.QuoteMeta(v.Name)
+		if regexp.MustCompile(`\b` + name + `\b(\s*\[[^\]]*\])*\s*([-+*/%|&^]?=[^=]|\+\+|--)|(\+\+|--)\s*\b` + name + `\b`).MatchString(body) {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// unsafeWrapper reports a safe* function that does not provide the safety
+// its name promises: a token or ether transfer whose failure goes
+// unnoticed, arithmetic that can wrap (before Solidity 0.8, or in an
+// unchecked block) or a narrowing cast, either without any check. ERC-721
+// and ERC-1155 safe transfers and mints, where "safe" means the receiver
+// check, are skipped.
+func unsafeWrapper(file *solidity.File, c solidity.Contract, fn solidity.Function, checked bool) (parser.Finding, bool) {
+	if !safeNameRe.MatchString(fn.Name) || strings.Contains(fn.Name, "Mint") {
+		return parser.Finding{}, false
+	}
+	body := reachable(file, c, fn)
+	if nftReceiverRe.MatchString(body) || slices.ContainsFunc(c.Bases, func(b string) bool {
+		return strings.Contains(b, "ERC721") || strings.Contains(b, "ERC1155")
+	}) {
+		return parser.Finding{}, false
+	}
+
+	severity := parser.SeverityHigh
+	problem, line := transferFailure(file, fn, body)
+	if problem == "" {
+		severity, line = parser.SeverityMedium, fn.Line
+		switch guardedBody := checkedRe.MatchString(body); {
+		case guardedBody:
+			return parser.Finding{}, false
+		case safeArithRe.MatchString(fn.Name) && !checked:
+			problem = "does its arithmetic without any check, and the file's pragma selects a compiler before 0.8, where overflow wraps silently"
+		case safeArithRe.MatchString(fn.Name) && uncheckedRe.MatchString(body):
+			problem = "does its arithmetic in an unchecked block without any check, so overflow wraps silently"
+		case truncatingCastRe.MatchString(body):
+			problem = fmt.Sprintf("narrows with `%s` without checking that the value fits, so large values are silently truncated", strings.TrimSuffix(truncatingCastRe.FindString(body), "("))
+		default:
+			return parser.Finding{}, false
+		}
+	}
+	return parser.Finding{
+		Check: "custom-unsafe-safe-wrapper",
+		Title: fmt.Sprintf("Misleading safe* Wrapper: %s.%s", c.Name, fn.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — %s.%s is named as a safe wrapper but %s. Callers rely on the name and skip their own checks.",
+			file.Path, line, c.Name, fn.Name, problem,
+		),
+		Severity:   severity,
+		Confidence: "Medium",
+		File:       file.Path,
+		Lines:      []int{line},
+		Function:   fn.Name,
+		Evidence:   []string{fmt.Sprintf("line %d: %s", line, oneLine(file.Line(line)))},
+	}, true
+}
+
+// transferFailure describes how a transfer in fn can fail without fn
+// noticing, and the line of the transfer: an ERC-20 call or send whose
+// returned bool is discarded, a low-level call whose success is never
+// checked, or a low-level token call that never decodes the returned bool.
+func transferFailure(file *solidity.File, fn solidity.Function, body string) (string, int) {
+	for _, s := range statements(file, fn)[1:] {
+		if m := tokenCallRe.FindStringSubmatch(s.Text); m != nil && !usesResultRe.MatchString(s.Text) {
+			open := strings.Index(s.Text, m[0]) + len(m[0]) - 1
+			close := matchParen(s.Text, open)
+			args := ""
+			if close > open {
+				args = s.Text[open+1 : close]
+			}
+			switch {
+			case m[1] == "send":
+				return "discards the bool send returns, so a failed ether transfer goes unnoticed", s.Line
+			case m[1] == "transfer" && firstArg(args) == args:
+				// ETH transfer(amount) reverts on failure.
+			default:
+				return fmt.Sprintf("discards the bool %s returns, so tokens that return false instead of reverting fail silently", m[1]), s.Line
+			}
+		}
+		if !lowCallRe.MatchString(s.Text) {
+			continue
+		}
+		m := successVarRe.FindStringSubmatch(s.Text)
+		if m == nil {
+			return "never checks whether the low-level call succeeded", s.Line
+		}
+		success := regexp.QuoteMeta(m[1])
+		if !regexp.MustCompile(`\b(require|assert|if)\s*\([^;]*\b` + success + `\b|\breturn\s+[^;]*\b` + success + `\b`).MatchString(body) {
+			return fmt.Sprintf("never checks %s, the success flag of the low-level call", m[1]), s.Line
+		}
+		if tokenDataRe.MatchString(body) && !strings.Contains(body, "abi.decode") {
+			return "checks only that the low-level token call did not revert, not the bool the token returns, so tokens that return false pass as successful", s.Line
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckMisleadingNames(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+library Transfers {
+    function safeTransfer(IERC20 token, address to, uint256 amount) internal {
+        require(token.transfer(to, amount), "transfer failed");
+    }
+
+    function safeSend(address payable to, uint256 amount) internal {
+        to.send(amount);
+    }
+
+    function safeSub(uint256 a, uint256 b) internal pure returns (uint256) {
+        return a >= b ? a - b : 0;
+    }
+}
+
+contract Token is ERC20 {
+    mapping(address => mapping(address => uint256)) private _allowed;
+    mapping(address => uint256) private _balances;
+
+    function transferFrom(address from, address to, uint256 amount) external onlyOwner returns (bool) {
+        _balances[from] -= amount;
+        _balances[to] += amount;
+        return true;
+    }
+
+    function approve(address spender, uint256 amount) public override returns (bool) {
+        return super.approve(spender, amount);
+    }
+
+    function time() external view returns (uint256) {
+        return now;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Token.sol"), []byte(content), 0644))
+
+	findings, err := CheckMisleadingNames(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "a checked transfer, a ternary bound, super and return now are not flagged")
+
+	send := findings[0]
+	assert.Equal(t, "custom-unsafe-safe-wrapper", send.Check)
+	assert.Equal(t, parser.SeverityHigh, send.Severity)
+	assert.Equal(t, []int{9}, send.Lines, "reported at the send, not the declaration")
+	assert.Equal(t, "CUSTOM-NAME-1", send.ID)
+	assert.Contains(t, send.Description, "discards the bool send returns")
+
+	transferFrom := findings[1]
+	assert.Equal(t, "custom-misleading-standard-function", transferFrom.Check)
+	assert.Equal(t, parser.SeverityMedium, transferFrom.Severity, "only the owner can move holders' tokens")
+	assert.Equal(t, []int{21}, transferFrom.Lines)
+}
@@ -0,0 +1,244 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	// royaltyCallRe matches OpenZeppelin's ERC2981 setters; the royalty
+	// fraction is their last argument.
+	royaltyCallRe = regexp.MustCompile(`\b_set(Default|Token)Royalty\s*\(.*,\s*([^,]+?)\s*\)$`)
+	royaltyVarRe  = regexp.MustCompile(`(?i)royalt`)
+	// baseURIRe matches writing the base URI every token's URI derives from.
+	baseURIRe = regexp.MustCompile(`(?i)^_?base\w*uri\w*\s*=[^=](.*)$|\b_?setBaseURI\s*\((.*)\)$`)
+	// tokenURIRe matches writing the URI of a single token, as
+	// ERC721URIStorage's _setTokenURI does.
+	tokenURIRe = regexp.MustCompile(`\b_setTokenURI\s*\((.*)\)$|^_?tokenURIs?\s*\[[^\]]*\]\s*=[^=](.*)$`)
+	// rendererRe matches the contracts tokenURI can delegate to.
+	rendererRe = regexp.MustCompile(`(?i)renderer|metadata|descriptor|uriprovider|tokenuri`)
+	mintRe     = regexp.MustCompile(`\b_?(safe)?[mM]int\s*\(`)
+	// freezeRe matches the flags and modifiers that make metadata permanent.
+	freezeRe = regexp.MustCompile(`(?i)frozen|freeze|locked|permanent|finali[sz]ed`)
+)
+
+// CheckNFTCentralization reports what the owner of an NFT collection can
+// change after holders have bought in, which marketplaces and buyers should
+// know about: ERC-2981 royalties that can be raised without a cap, a base
+// URI that can be repointed because nothing freezes it, and per-token URI
+// or renderer overrides that change the art of tokens already sold. Only
+// guarded setters are reported; unguarded ones are left to the access
+// control check. Only contracts that look like ERC-721, ERC-1155 or
+// ERC-2981 implementations are checked.
+func CheckNFTCentralization(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-NFT-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if !isNFT(c) {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if fn.Name == "constructor" || fn.Name == "initialize" || fn.Visibility == "internal" || fn.Visibility == "private" || fn.Mutability != "" {
+					continue
+				}
+				if fn.End <= fn.Line || !guarded(file, fn) {
+					continue
+				}
+				stmts := statements(file, fn)
+				if f, ok := unboundedRoyalty(file, c, fn, stmts); ok {
+					add(f)
+				}
+				if frozen(fn, stmts) {
+					continue
+				}
+				if f, ok := mutableBaseURI(file, c, fn, stmts); ok {
+					add(f)
+				}
+				if f, ok := tokenURIOverride(file, c, fn, stmts); ok {
+					add(f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// isNFT reports whether c looks like an NFT collection.
+func isNFT(c solidity.Contract) bool {
+	if c.Kind != "contract" {
+		return false
+	}
+	for _, b := range c.Bases {
+		if strings.Contains(b, "ERC721") || strings.Contains(b, "ERC1155") || strings.Contains(b, "ERC2981") {
+			return true
+		}
+	}
+	return slices.ContainsFunc(c.Functions, func(fn solidity.Function) bool {
+		return fn.Name == "tokenURI" || fn.Name == "royaltyInfo"
+	})
+}
+
+// frozen reports whether fn checks a freeze flag or carries a modifier
+// that does, so the metadata it writes can be made permanent.
+func frozen(fn solidity.Function, stmts []statement) bool {
+	if slices.ContainsFunc(fn.Modifiers, freezeRe.MatchString) {
+		return true
+	}
+	for _, s := range stmts[1:] {
+		if conditionRe.MatchString(s.Text) && freezeRe.MatchString(s.Text) {
+			return true
+		}
+	}
+	return false
+}
+
+// unboundedRoyalty reports a royalty set from fn's argument with no upper
+// bound below the 100% ERC2981 allows.
+func unboundedRoyalty(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement) (parser.Finding, bool) {
+	for _, s := range stmts[1:] {
+		var name, value string
+		if m := royaltyCallRe.FindStringSubmatch(s.Text); m != nil {
+			name, value = "the royalty fraction", m[2]
+		} else if m := paramAssignRe.FindStringSubmatch(s.Text); m != nil && royaltyVarRe.MatchString(m[1]) && uintStateVar(c, m[1]) {
+			name, value = m[1], m[3]
+		} else {
+			continue
+		}
+		arg := paramIn(fn, value)
+		if arg == "" {
+			continue
+		}
+		if _, upper := bounds(stmts, arg, name); upper {
+			continue
+		}
+		return parser.Finding{
+			Check: "custom-nft-royalty-bound",
+			Title: fmt.Sprintf("Unbounded Royalty: %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() sets %s from its argument '%s' without an upper bound. The owner can raise royalties up to 100%% of every sale after holders have bought in, and marketplaces that honour ERC-2981 will pay it.",
+				file.Path, s.Line, c.Name, fn.Name, name, arg,
+			),
+			Severity:   parser.SeverityMedium,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("no condition bounds `%s` from above in %s()", arg, fn.Name),
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// uintStateVar reports whether name is an unsigned integer state variable
+// of c, so royalty receivers are not taken for royalty amounts.
+func uintStateVar(c solidity.Contract, name string) bool {
+	for _, v := range c.StateVars {
+		if v.Name == name {
+			return strings.HasPrefix(v.Type, "uint")
+		}
+	}
+	return false
+}
+
+// mutableBaseURI reports a base URI set from fn's argument while nothing
+// can freeze it.
+func mutableBaseURI(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement) (parser.Finding, bool) {
+	for _, s := range stmts[1:] {
+		m := baseURIRe.FindStringSubmatch(s.Text)
+		if m == nil || paramIn(fn, m[1]+m[2]) == "" {
+			continue
+		}
+		return parser.Finding{
+			Check: "custom-nft-mutable-metadata",
+			Title: fmt.Sprintf("Mutable Base URI: %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() lets the owner repoint the base URI of every token, and no freeze flag is checked. The metadata and art buyers paid for can be replaced at any time.",
+				file.Path, s.Line, c.Name, fn.Name,
+			),
+			Severity:   parser.SeverityInformational,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("no frozen, locked or permanent flag checked in %s()", fn.Name),
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// tokenURIOverride reports fn rewriting the URI of a token already minted,
+// or swapping the contract that tokenURI delegates to.
+func tokenURIOverride(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement) (parser.Finding, bool) {
+	if mintRe.MatchString(functionBody(file, fn)) {
+		// Setting the URI of a token being minted is not an override.
+		return parser.Finding{}, false
+	}
+	for _, s := range stmts[1:] {
+		var what string
+		if m := tokenURIRe.FindStringSubmatch(s.Text); m != nil && paramIn(fn, m[1]+m[2]) != "" {
+			what = "the URI of an existing token"
+		} else if m := paramAssignRe.FindStringSubmatch(s.Text); m != nil && m[2] == "" && rendererRe.MatchString(m[1]) && isStateVar(c, m[1]) && paramIn(fn, m[3]) != "" && rendersTokenURI(file, c, m[1]) {
+			what = fmt.Sprintf("'%s', which tokenURI() delegates to", m[1])
+		} else {
+			continue
+		}
+		return parser.Finding{
+			Check: "custom-nft-token-uri-override",
+			Title: fmt.Sprintf("Owner-Controlled Token URI: %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() lets the owner replace %s, and no freeze flag is checked. Individual tokens can be made to show different metadata after they are sold, which marketplaces and buyers cannot detect from the listing.",
+				file.Path, s.Line, c.Name, fn.Name, what,
+			),
+			Severity:   parser.SeverityMedium,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("no frozen, locked or permanent flag checked in %s()", fn.Name),
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// rendersTokenURI reports whether c's tokenURI or uri function uses name.
+func rendersTokenURI(file *solidity.File, c solidity.Contract, name string) bool {
+	for _, fn := range c.Functions {
+		if (fn.Name == "tokenURI" || fn.Name == "uri") && fn.End > fn.Line && mentions(functionBody(file, fn), name) {
+			return true
+		}
+	}
+	return false
+}
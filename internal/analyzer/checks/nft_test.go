@@ -0,0 +1,65 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckNFTCentralization(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Drop is ERC721 {
+    uint256 public royaltyBps;
+    address public royaltyReceiver;
+    string public baseURI;
+
+    function setRoyalty(address receiver, uint256 bps) external onlyOwner {
+        royaltyReceiver = receiver;
+        royaltyBps = bps;
+    }
+
+    function setBaseURI(string calldata uri) external onlyOwner whenNotFrozen {
+        baseURI = uri;
+    }
+
+    function setContractURI(string calldata uri) external onlyOwner {
+        _setBaseURI(uri);
+    }
+
+    function royaltyInfo(uint256, uint256 price) external view returns (address, uint256) {
+        return (royaltyReceiver, price * royaltyBps / 10000);
+    }
+}
+
+contract Registry {
+    string public baseURI;
+
+    function setBaseURI(string calldata uri) external onlyOwner {
+        baseURI = uri;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Drop.sol"), []byte(content), 0644))
+
+	findings, err := CheckNFTCentralization(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "a frozen setter and a contract that is not an NFT are skipped")
+
+	royalty := findings[0]
+	assert.Equal(t, "custom-nft-royalty-bound", royalty.Check)
+	assert.Equal(t, parser.SeverityMedium, royalty.Severity)
+	assert.Equal(t, []int{10}, royalty.Lines, "the receiver is not a royalty amount")
+	assert.Contains(t, royalty.Description, "sets royaltyBps from its argument 'bps'")
+
+	metadata := findings[1]
+	assert.Equal(t, "custom-nft-mutable-metadata", metadata.Check)
+	assert.Equal(t, parser.SeverityInformational, metadata.Severity)
+	assert.Equal(t, []int{18}, metadata.Lines)
+	assert.Equal(t, "CUSTOM-NFT-2", metadata.ID)
+}
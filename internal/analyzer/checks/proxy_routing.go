@@ -0,0 +1,314 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	delegatecallRe = regexp.MustCompile(`\bdelegatecall\s*\(`)
+	// revertOnFailureRe matches propagating a failed delegatecall: an
+	// assembly revert, or a check of the success flag.
+	revertOnFailureRe = regexp.MustCompile(`\brevert\s*\(|\brequire\s*\(\s*\w*(success|ok)\w*|!\s*\w*(success|ok)\w*`)
+	// returnDataRe matches handing the implementation's return data back
+	// to the caller.
+	returnDataRe = regexp.MustCompile(`\breturndatacopy\s*\(|\breturn\s+\w*(data|result|ret)\w*\b`)
+	// versionRe splits a contract name such as VaultV2 into its stem and
+	// version.
+	versionRe = regexp.MustCompile(`^(\w+?)V(\d+)$`)
+)
+
+// CheckProxyRouting flags proxies that route calls and ether incorrectly,
+// and implementations whose receive and fallback behaviour silently
+// changes behind a proxy. Proxies are contracts whose fallback reaches a
+// delegatecall. It reports delegatecalls that do not revert on failure or
+// drop the implementation's return data, fallbacks that are not payable,
+// and receive functions that keep ether instead of delegating. For
+// upgradeable implementations it reports a receive or fallback that a
+// proxy in the same directory shadows, and upgrades (VaultV1 to VaultV2 in
+// the same directory) that add, remove or change the payability of receive
+// or fallback.
+func CheckProxyRouting(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-PROXY-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	type located struct {
+		file *solidity.File
+		c    solidity.Contract
+	}
+	// Proxies and implementations are matched within a directory.
+	shadowing := map[string]located{}
+	var implementations []located
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" {
+				continue
+			}
+			if isUpgradeable(c) {
+				implementations = append(implementations, located{file, c})
+			}
+			fallback, ok := special(c, "fallback")
+			if !ok || !delegatecallRe.MatchString(reachable(file, c, fallback)) {
+				continue
+			}
+			if f, ok := delegateReturn(file, c, fallback); ok {
+				add(f)
+			}
+			for _, f := range proxyEther(file, c, fallback) {
+				add(f)
+			}
+			dir := filepath.Dir(path)
+			if _, seen := shadowing[dir]; !seen && swallows(file, c) {
+				shadowing[dir] = located{file, c}
+			}
+		}
+	}
+
+	for _, impl := range implementations {
+		for _, name := range []string{"receive", "fallback"} {
+			fn, ok := special(impl.c, name)
+			proxy, shadowed := shadowing[filepath.Dir(impl.file.Path)]
+			if !ok || !shadowed {
+				continue
+			}
+			add(parser.Finding{
+				Check: "custom-proxy-receive-conflict",
+				Title: fmt.Sprintf("Implementation %s() Shadowed by Proxy: %s", name, impl.c.Name),
+				Description: fmt.Sprintf(
+					"%s:%d — %s is an upgradeable implementation with its own %s(), but %s (%s:%d) answers plain ether transfers in its own receive() without delegating. Behind that proxy, %s.%s() never runs for them, so its accounting and events are skipped while the proxy keeps the ether.",
+					impl.file.Path, fn.Line, impl.c.Name, name, proxy.c.Name, proxy.file.Path, proxy.c.Line, impl.c.Name, name,
+				),
+				Severity:   parser.SeverityMedium,
+				Confidence: "Low",
+				File:       impl.file.Path,
+				Lines:      []int{fn.Line},
+				Function:   name,
+				Evidence: []string{
+					fmt.Sprintf("%s defines %s() at line %d", impl.c.Name, name, fn.Line),
+					fmt.Sprintf("%s.receive() does not reach a delegatecall", proxy.c.Name),
+				},
+			})
+		}
+	}
+
+	// Consecutive versions of an implementation should route ether alike.
+	versions := map[string][]located{}
+	for _, impl := range implementations {
+		if m := versionRe.FindStringSubmatch(impl.c.Name); m != nil {
+			stem := filepath.Join(filepath.Dir(impl.file.Path), m[1])
+			versions[stem] = append(versions[stem], impl)
+		}
+	}
+	stems := make([]string, 0, len(versions))
+	for stem := range versions {
+		stems = append(stems, stem)
+	}
+	sort.Strings(stems)
+	for _, stem := range stems {
+		impls := versions[stem]
+		sort.Slice(impls, func(i, j int) bool { return version(impls[i].c.Name) < version(impls[j].c.Name) })
+		for i := 1; i < len(impls); i++ {
+			prev, next := impls[i-1], impls[i]
+			if change := etherRoutingChange(prev.c, next.c); change != "" {
+				add(parser.Finding{
+					Check: "custom-proxy-receive-conflict",
+					Title: fmt.Sprintf("Upgrade Changes Ether Handling: %s to %s", prev.c.Name, next.c.Name),
+					Description: fmt.Sprintf(
+						"%s:%d — %s %s. Upgrading the proxy from %s changes what happens to ether sent to it: transfers that used to succeed start reverting, or ether that used to be rejected is silently accepted.",
+						next.file.Path, next.c.Line, next.c.Name, change, prev.c.Name,
+					),
+					Severity:   parser.SeverityMedium,
+					Confidence: "Medium",
+					File:       next.file.Path,
+					Lines:      []int{next.c.Line},
+					Evidence: []string{
+						fmt.Sprintf("%s is declared at %s:%d", prev.c.Name, prev.file.Path, prev.c.Line),
+					},
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// special returns c's receive or fallback function.
+func special(c solidity.Contract, name string) (solidity.Function, bool) {
+	for _, fn := range c.Functions {
+		if fn.Name == name && fn.End > 0 {
+			return fn, true
+		}
+	}
+	return solidity.Function{}, false
+}
+
+// reachable returns the body of fn and of the functions of c it calls,
+// transitively, so that a fallback delegating through _fallback() and
+// _delegate() is seen whole.
+func reachable(file *solidity.File, c solidity.Contract, fn solidity.Function) string {
+	var bodies []string
+	for _, f := range reachableFunctions(file, c, fn) {
+		bodies = append(bodies, functionBody(file, f))
+	}
+	return strings.Join(bodies, "\n")
+}
+
+// reachableFunctions returns fn and the functions of c it calls,
+// transitively, in the order they are found.
+func reachableFunctions(file *solidity.File, c solidity.Contract, fn solidity.Function) []solidity.Function {
+	seen := map[string]bool{fn.Name: true}
+	fns := []solidity.Function{fn}
+	bodies := []string{functionBody(file, fn)}
+	for i := 0; i < len(bodies); i++ {
+		for _, callee := range c.Functions {
+			if seen[callee.Name] || callee.End == 0 {
+				continue
+			}
+			if regexp.MustCompile(`\b` + regexp.QuoteMeta(callee.Name) + `\s*\(`).MatchString(bodies[i]) {
+				seen[callee.Name] = true
+				fns = append(fns, callee)
+				bodies = append(bodies, functionBody(file, callee))
+			}
+		}
+	}
+	return fns
+}
+
+// isUpgradeable reports whether c looks like an implementation meant to
+// sit behind a proxy.
+func isUpgradeable(c solidity.Contract) bool {
+	for _, b := range c.Bases {
+		if b == "Initializable" || strings.HasSuffix(b, "Upgradeable") {
+			return true
+		}
+	}
+	return slices.ContainsFunc(c.Functions, func(fn solidity.Function) bool { return fn.Name == "initialize" })
+}
+
+// delegateReturn reports a proxy fallback whose delegatecall does not
+// revert when the implementation reverts, or does not return what the
+// implementation returned.
+func delegateReturn(file *solidity.File, c solidity.Contract, fallback solidity.Function) (parser.Finding, bool) {
+	code := reachable(file, c, fallback)
+	header := statements(file, fallback)[0].Text
+	propagates := revertOnFailureRe.MatchString(code)
+	returns := returnDataRe.MatchString(code) || strings.Contains(header, "returns")
+	if propagates && returns {
+		return parser.Finding{}, false
+	}
+	severity, what := parser.SeverityMedium, "drops the implementation's return data, so every view and every function with a return value appears to return nothing or zero"
+	var evidence []string
+	if !returns {
+		evidence = append(evidence, "no returndatacopy or returned data after the delegatecall")
+	}
+	if !propagates {
+		severity, what = parser.SeverityHigh, "does not revert when the implementation reverts, so failed calls, including failed transfers and access checks, look successful to callers"
+		evidence = append([]string{"no revert or success check after the delegatecall"}, evidence...)
+	}
+	return parser.Finding{
+		Check: "custom-proxy-fallback-returndata",
+		Title: fmt.Sprintf("Proxy Fallback Mishandles the Delegatecall Result: %s", c.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — the fallback of %s delegates to the implementation but %s.",
+			file.Path, fallback.Line, c.Name, what,
+		),
+		Severity:   severity,
+		Confidence: "Medium",
+		File:       file.Path,
+		Lines:      []int{fallback.Line},
+		Function:   "fallback",
+		Evidence:   evidence,
+	}, true
+}
+
+// proxyEther reports a proxy that cannot forward ether: a receive that
+// keeps ether instead of delegating, or a fallback that is not payable.
+func proxyEther(file *solidity.File, c solidity.Contract, fallback solidity.Function) []parser.Finding {
+	var findings []parser.Finding
+	report := func(fn solidity.Function, title, problem, evidence string) {
+		findings = append(findings, parser.Finding{
+			Check: "custom-proxy-ether-routing",
+			Title: fmt.Sprintf("%s: %s", title, c.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s delegates calls to an implementation, but %s.",
+				file.Path, fn.Line, c.Name, problem,
+			),
+			Severity:   parser.SeverityMedium,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{fn.Line},
+			Function:   fn.Name,
+			Evidence:   []string{evidence},
+		})
+	}
+	if swallows(file, c) {
+		receive, _ := special(c, "receive")
+		report(receive, "Proxy receive() Keeps Ether", "its receive() keeps plain ether transfers instead of delegating them, so the implementation's receive logic never runs",
+			"receive() does not reach a delegatecall")
+	}
+	if !fallback.Payable {
+		report(fallback, "Proxy Fallback Not Payable", "its fallback is not payable, so calls to payable implementation functions revert",
+			"fallback() is not payable")
+	}
+	return findings
+}
+
+// swallows reports whether c has a receive function that does not reach a
+// delegatecall.
+func swallows(file *solidity.File, c solidity.Contract) bool {
+	receive, ok := special(c, "receive")
+	return ok && !delegatecallRe.MatchString(reachable(file, c, receive))
+}
+
+// etherRoutingChange describes how next handles ether differently from
+// prev, or returns "" when both route it alike.
+func etherRoutingChange(prev, next solidity.Contract) string {
+	var changes []string
+	for _, name := range []string{"receive", "fallback"} {
+		before, had := special(prev, name)
+		after, has := special(next, name)
+		switch {
+		case had && !has:
+			changes = append(changes, fmt.Sprintf("removes the %s() %s has", name, prev.Name))
+		case !had && has:
+			changes = append(changes, fmt.Sprintf("adds a %s() %s does not have", name, prev.Name))
+		case had && has && before.Payable != after.Payable:
+			changes = append(changes, fmt.Sprintf("changes whether %s() is payable", name))
+		}
+	}
+	return strings.Join(changes, " and ")
+}
+
+// version returns the version number in a contract name such as VaultV2.
+func version(name string) int {
+	m := versionRe.FindStringSubmatch(name)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[2])
+	return n
+}
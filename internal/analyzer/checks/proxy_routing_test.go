@@ -0,0 +1,59 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckProxyRouting(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Forwarder {
+    address impl;
+
+    fallback() external payable {
+        _delegate();
+    }
+
+    function _delegate() internal {
+        (bool ok, ) = impl.delegatecall(msg.data);
+        require(ok);
+    }
+}
+
+contract TokenV1 is Initializable {
+    fallback() external payable {}
+}
+
+contract TokenV2 is Initializable {
+    fallback() external {}
+}
+
+contract TokenV3 is Initializable {
+    fallback() external {}
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Proxy.sol"), []byte(content), 0644))
+
+	findings, err := CheckProxyRouting(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "a proxy without receive() and unchanged versions are skipped")
+
+	dropped := findings[0]
+	assert.Equal(t, "custom-proxy-fallback-returndata", dropped.Check)
+	assert.Equal(t, parser.SeverityMedium, dropped.Severity, "the failure is propagated, only the return data is lost")
+	assert.Equal(t, []int{6}, dropped.Lines)
+	assert.Equal(t, "CUSTOM-PROXY-1", dropped.ID)
+
+	upgrade := findings[1]
+	assert.Equal(t, "custom-proxy-receive-conflict", upgrade.Check)
+	assert.Equal(t, []int{20}, upgrade.Lines)
+	assert.Contains(t, upgrade.Description, "TokenV2 changes whether fallback() is payable")
+	assert.NotEmpty(t, upgrade.Remediation)
+}
@@ -4,18 +4,24 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
 )
 
+// assignedVarRe captures the variable a statement assigns, increments or
+// deletes, e.g. balances in "balances[msg.sender] -= amount;".
+var assignedVarRe = regexp.MustCompile(`^(?:delete\s+)?([A-Za-z_$][\w$]*)\s*(?:\[[^\]]*\]\s*)*(?:\.[A-Za-z_$][\w$]*\s*)*(?:[-+*/%|&^]?=[^=]|\+\+|--|;)`)
+
 // CheckReentrancy scans Solidity source for the classic reentrancy anti-pattern:
 // an external call followed by a state change, without a reentrancy guard.
 //
 // This check catches patterns that Slither's reentrancy detector sometimes misses
 // at Low confidence — particularly in newer Solidity syntax styles.
-func CheckReentrancy(target string) ([]parser.Finding, error) {
-	files, err := solidityFiles(target)
+func CheckReentrancy(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
 	if err != nil {
 		return nil, err
 	}
@@ -45,26 +51,18 @@ func checkReentrancyInFile(path string) ([]parser.Finding, error) {
 		functionName   string
 		sawExternalCall bool
 		callLine       int
+		callPattern    string
+		callCol        int
 		hasGuard       bool
 		lineNum        int
 	)
 
-	// Signals of an external call
-	externalCallPatterns := []string{
-		".call{", ".call(",
-		".delegatecall(",
-		".transfer(",
-		".send(",
-	}
-
-	// Signals of state changes after a call
-	stateChangePatterns := []string{
-		"balances[",
-		"balanceOf[",
-		"= 0;",
-		"-= ",
-		"+= ",
-	}
+	// Signal lists come from the rules bundle so they can be extended with
+	// `solsec update-db` without a new release.
+	db := rules.Default()
+	externalCallPatterns := db.Signature("external-call")
+	stateChangePatterns := db.Signature("state-change")
+	guardPatterns := db.Signature("reentrancy-guard")
 
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
@@ -86,10 +84,10 @@ func checkReentrancyInFile(path string) ([]parser.Finding, error) {
 		}
 
 		// Detect reentrancy guard
-		if strings.Contains(trimmed, "nonReentrant") ||
-			strings.Contains(trimmed, "ReentrancyGuard") ||
-			strings.Contains(trimmed, "mutex") {
-			hasGuard = true
+		for _, pattern := range guardPatterns {
+			if strings.Contains(trimmed, pattern) {
+				hasGuard = true
+			}
 		}
 
 		// Detect external call
@@ -97,6 +95,8 @@ func checkReentrancyInFile(path string) ([]parser.Finding, error) {
 			if strings.Contains(trimmed, pattern) && !strings.HasPrefix(trimmed, "//") {
 				sawExternalCall = true
 				callLine = lineNum
+				callPattern = pattern
+				callCol = strings.Index(line, pattern) + 1
 			}
 		}
 
@@ -121,10 +121,21 @@ func checkReentrancyInFile(path string) ([]parser.Finding, error) {
 						Remediation: "Move all state changes BEFORE the external call (checks-effects-interactions). " +
 							"Alternatively, add OpenZeppelin's nonReentrant modifier.",
 						SWCRef:     "SWC-107",
+						CWERef:     db.CWE("custom-reentrancy-ordering"),
 						References: []string{
 							"https://swcregistry.io/docs/SWC-107",
 							"https://docs.openzeppelin.com/contracts/4.x/api/security#ReentrancyGuard",
 						},
+						Flow: []parser.FlowStep{
+							{File: path, Line: callLine, Message: fmt.Sprintf("External call `%s` hands control to the callee", strings.TrimSpace(lines[callLine-1]))},
+							{File: path, Line: lineNum, Message: fmt.Sprintf("State written after the call: `%s`", trimmed)},
+						},
+						RootCause: writtenAfterCall(trimmed),
+						Evidence: []string{
+							fmt.Sprintf("external call pattern `%s` at line %d col %d", callPattern, callLine, callCol),
+							fmt.Sprintf("state write `%s` at line %d col %d", pattern, lineNum, strings.Index(line, pattern)+1),
+							fmt.Sprintf("no reentrancy guard in '%s' (looked for %s)", functionName, strings.Join(guardPatterns, ", ")),
+						},
 					})
 					break
 				}
@@ -141,6 +152,17 @@ func checkReentrancyInFile(path string) ([]parser.Finding, error) {
 	return findings, scanner.Err()
 }
 
+// writtenAfterCall is the root cause of a state write after an external
+// call: the variable written, so writes to the same state from several
+// functions cluster. It is "" when the statement's target is unclear.
+func writtenAfterCall(stmt string) string {
+	m := assignedVarRe.FindStringSubmatch(stmt)
+	if m == nil {
+		return ""
+	}
+	return m[1] + " written after an external call"
+}
+
 func extractFunctionName(line string) string {
 	// "function transfer(address to, uint256 amount)" → "transfer"
 	start := strings.Index(line, "function ") + len("function ")
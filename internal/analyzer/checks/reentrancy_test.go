@@ -36,7 +36,7 @@ contract Vulnerable {
 	err = os.WriteFile(tmpFile, []byte(content), 0644)
 	require.NoError(t, err)
 
-	findings, err := CheckReentrancy(tmpFile)
+	findings, err := CheckReentrancy(tmpFile, DefaultFilter)
 	require.NoError(t, err)
 
 	assert.Len(t, findings, 1)
@@ -63,6 +63,18 @@ contract Vulnerable {
 	// Call at 11, change at 14.
 	assert.Contains(t, findings[0].Lines, 11)
 	assert.Contains(t, findings[0].Lines, 14)
+
+	require.Len(t, findings[0].Evidence, 3)
+	assert.Contains(t, findings[0].Evidence[0], "`.call{` at line 11")
+	assert.Contains(t, findings[0].Evidence[1], "at line 14")
+	assert.Contains(t, findings[0].Evidence[2], "no reentrancy guard in 'withdraw'")
+
+	require.Len(t, findings[0].Flow, 2)
+	assert.Equal(t, 11, findings[0].Flow[0].Line)
+	assert.Contains(t, findings[0].Flow[0].Message, "msg.sender.call{value: amount}")
+	assert.Equal(t, 14, findings[0].Flow[1].Line)
+	assert.Equal(t, "State written after the call: `balances[msg.sender] = 0;`", findings[0].Flow[1].Message)
+	assert.Equal(t, "balances written after an external call", findings[0].RootCause)
 }
 
 func TestCheckReentrancy_WithGuard(t *testing.T) {
@@ -94,7 +106,7 @@ contract Safe {
 	err = os.WriteFile(tmpFile, []byte(content), 0644)
 	require.NoError(t, err)
 
-	findings, err := CheckReentrancy(tmpFile)
+	findings, err := CheckReentrancy(tmpFile, DefaultFilter)
 	require.NoError(t, err)
 
 	assert.Empty(t, findings)
@@ -0,0 +1,307 @@
+package checks
+
+import (
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// Check describes a built-in custom check: the name used to select it with
+// --checks, the rules (finding check IDs) it can emit, and its entry point.
+// Run reads only the files filter selects.
+type Check struct {
+	Name  string
+	Rules []Rule
+	Run   func(target string, filter Filter) ([]parser.Finding, error)
+
+	// OptIn checks only run when selected by name, not by default.
+	OptIn bool
+}
+
+// Rule is a single finding type a check can produce.
+type Rule struct {
+	ID          string
+	Severity    string
+	Description string
+}
+
+var registry = []Check{
+	{
+		Name: "reentrancy",
+		Rules: []Rule{
+			{"custom-reentrancy-ordering", "High", "State change after external call without reentrancy guard"},
+		},
+		Run: CheckReentrancy,
+	},
+	{
+		Name: "access-control",
+		Rules: []Rule{
+			{"custom-missing-access-control", "Critical/High", "Sensitive functions (mint, burn, pause, upgrade) without access modifiers"},
+		},
+		Run: CheckAccessControl,
+	},
+	{
+		Name: "integer-overflow",
+		Rules: []Rule{
+			{"custom-integer-overflow", "High", "Arithmetic without SafeMath in Solidity <0.8"},
+			{"custom-unchecked-arithmetic", "Low", "Arithmetic inside unchecked{} blocks"},
+		},
+		Run: CheckIntegerOverflow,
+	},
+	{
+		Name: "unused-contract",
+		Rules: []Rule{
+			{"custom-unused-contract", "Informational", "Contracts never imported, referenced or deployed (stale code in scope)"},
+		},
+		Run: CheckUnusedContracts,
+	},
+	{
+		Name: "compiler-settings",
+		Rules: []Rule{
+			{"custom-optimizer-runs", "Informational", "Unusual optimizer runs values in foundry.toml/hardhat config"},
+			{"custom-missing-via-ir", "Informational", "Stack-too-deep workarounds without viaIR enabled"},
+			{"custom-pragma-mismatch", "Low", "File pragma not satisfied by the configured solc version"},
+		},
+		Run: CheckCompilerSettings,
+	},
+	{
+		Name: "license",
+		Rules: []Rule{
+			{"custom-missing-spdx", "Informational", "Files without an SPDX license identifier"},
+			{"custom-license-conflict", "Informational", "Imports mixing incompatible licenses (e.g. GPL into MIT)"},
+		},
+		Run: CheckLicenses,
+	},
+	{
+		Name: "comments",
+		Rules: []Rule{
+			{"custom-todo-comment", "Informational", "TODO/FIXME/HACK markers in production contracts"},
+			{"custom-commented-out-code", "Informational", "Large blocks of commented-out code"},
+		},
+		Run: CheckComments,
+	},
+	{
+		Name: "token-diligence",
+		Rules: []Rule{
+			{"custom-honeypot-sell-restriction", "Critical", "Transfer paths where only owner-approved addresses can sell to the pair"},
+			{"custom-unbounded-fee", "High", "Fee or tax variables settable without an upper bound"},
+			{"custom-blacklist", "Medium", "Functions that blacklist holders from transferring"},
+			{"custom-balance-rewrite", "Critical", "Balance writes outside transfer, mint and burn"},
+		},
+		Run: CheckTokenDueDiligence,
+	},
+	{
+		Name: "deploy-scripts",
+		Rules: []Rule{
+			{"custom-deploy-hardcoded-key", "Critical", "Private keys written into Foundry or Hardhat deployment scripts"},
+			{"custom-deploy-ownership-not-transferred", "Medium", "Owned contracts deployed without handing ownership to a multisig or timelock"},
+			{"custom-deploy-unverified-args", "Informational", "Constructor arguments never checked or verified after deployment"},
+			{"custom-deploy-no-confirmations", "Low", "Hardhat mainnet deployments without a block confirmations setting"},
+		},
+		Run: CheckDeployScripts,
+	},
+	{
+		Name: "high-risk-function",
+		Rules: []Rule{
+			{"custom-high-risk-function", "High/Critical", "Functions combining payable, no access control, an external call and inline assembly"},
+		},
+		Run: CheckHighRiskFunctions,
+	},
+	{
+		Name: "vault-inflation",
+		Rules: []Rule{
+			{"custom-first-depositor-inflation", "Medium/High", "Vault share math open to first-depositor inflation (no virtual offset, dead shares or minimum deposit)"},
+		},
+		Run: CheckVaultInflation,
+	},
+	{
+		Name: "erc4626",
+		Rules: []Rule{
+			{"custom-erc4626-rounding", "Medium/High", "ERC-4626 conversions and previews rounding against the vault"},
+			{"custom-erc4626-max-limit", "Medium", "ERC-4626 max* views ignoring the pauses and caps their entry points enforce"},
+		},
+		Run: CheckERC4626,
+	},
+	{
+		Name: "amm-math",
+		Rules: []Rule{
+			{"custom-amm-k-invariant", "Critical", "Swaps paying out caller-chosen amounts without checking x * y >= k"},
+			{"custom-amm-fee-order", "High", "Swap fees computed after the reserves are written"},
+			{"custom-amm-skim-sync", "Low/Medium", "skim and sync callable by anyone in pairs that keep reserves"},
+		},
+		Run: CheckAMMMath,
+	},
+	{
+		Name: "lending-params",
+		Rules: []Rule{
+			{"custom-collateral-factor-bound", "High", "Collateral factors and LTVs settable above 100%"},
+			{"custom-liquidation-bonus-bound", "High", "Liquidation bonuses settable to zero or to extreme values"},
+			{"custom-rate-model-bound", "High", "Interest rate model parameters settable without an upper bound"},
+		},
+		Run: CheckLendingParams,
+	},
+	{
+		Name: "staking-rewards",
+		Rules: []Rule{
+			{"custom-reward-accrual-math", "Medium/High", "Reward accrual over elapsed time that loses precision or can overflow"},
+			{"custom-reward-rate-unsettled", "High", "Reward rate changes that do not settle accrued rewards first"},
+		},
+		Run: CheckStakingRewards,
+	},
+	{
+		Name: "merkle-claims",
+		Rules: []Rule{
+			{"custom-merkle-double-claim", "Critical", "Merkle proof claims that never record the claim"},
+			{"custom-merkle-leaf-encoding", "Medium", "Merkle leaves hashed once over abi.encodePacked"},
+			{"custom-merkle-arbitrary-recipient", "High", "Merkle proof claims that pay a recipient the leaf does not commit to"},
+		},
+		Run: CheckMerkleClaims,
+	},
+	{
+		Name: "nft-centralization",
+		Rules: []Rule{
+			{"custom-nft-royalty-bound", "Medium", "Owner-set ERC-2981 royalties with no upper bound"},
+			{"custom-nft-mutable-metadata", "Informational", "Owner-set base URIs with no freeze mechanism"},
+			{"custom-nft-token-uri-override", "Medium", "Owner overrides of existing tokens' URIs or of the tokenURI renderer"},
+		},
+		Run: CheckNFTCentralization,
+	},
+	{
+		Name: "vesting-schedules",
+		Rules: []Rule{
+			{"custom-vesting-schedule-mutable", "High", "Admin functions that change a vesting beneficiary, start, duration or unlock time after the grant"},
+			{"custom-vesting-revoke-vested", "High", "Revoke functions that return vested tokens to the owner"},
+		},
+		Run: CheckVestingSchedules,
+	},
+	{
+		Name: "erc165",
+		Rules: []Rule{
+			{"custom-erc165-always-true", "Medium", "supportsInterface implementations that return true for every interface ID"},
+			{"custom-erc165-wrong-id", "Medium", "Interface ID constants and interface declarations that do not match the standard's ID"},
+			{"custom-erc165-missing-super", "Low", "supportsInterface overrides that drop the interfaces inherited bases report"},
+		},
+		Run: CheckERC165,
+	},
+	{
+		Name: "proxy-routing",
+		Rules: []Rule{
+			{"custom-proxy-fallback-returndata", "Medium/High", "Proxy fallbacks that do not revert with or return the implementation's result"},
+			{"custom-proxy-ether-routing", "Medium", "Proxies with a non-payable fallback or a receive that keeps ether instead of delegating"},
+			{"custom-proxy-receive-conflict", "Medium", "Implementation receive/fallback functions shadowed by the proxy or changed between versions"},
+		},
+		Run: CheckProxyRouting,
+	},
+	{
+		Name: "function-clashing",
+		Rules: []Rule{
+			{"custom-proxy-selector-clash", "Low/High", "Implementation functions whose 4-byte selector a proxy's own functions or getters also claim"},
+		},
+		Run: CheckFunctionClashing,
+	},
+	{
+		Name: "dead-code",
+		Rules: []Rule{
+			{"custom-unreachable-code", "Low", "Statements after an unconditional return, revert, break or continue"},
+			{"custom-constant-condition", "Low/Medium", "Conditions that are always true or always false given constant values"},
+			{"custom-modifier-no-placeholder", "Medium/High", "Modifiers that never reach _; and silently skip the functions they guard"},
+		},
+		Run: CheckDeadCode,
+	},
+	{
+		Name: "misleading-names",
+		Rules: []Rule{
+			{"custom-builtin-shadowing", "Low/Medium", "Variables, parameters, functions and modifiers named after builtins such as now, msg or require"},
+			{"custom-misleading-standard-function", "Medium/High", "ERC-20 functions that do not behave like the standard, e.g. a transferFrom that ignores allowances"},
+			{"custom-unsafe-safe-wrapper", "Medium/High", "safe* wrappers that let failed transfers, overflows or truncating casts pass unchecked"},
+		},
+		Run: CheckMisleadingNames,
+	},
+	{
+		Name: "transfer-events",
+		Rules: []Rule{
+			{"custom-transfer-event-missing", "Low/Medium", "ERC-20 balance or supply changes with no Transfer event on the path"},
+			{"custom-transfer-event-mismatch", "Medium", "Transfer events whose amount differs from the balance or supply change, or that move no balance"},
+		},
+		Run: CheckTransferEvents,
+	},
+	{
+		Name: "bridge-sender",
+		Rules: []Rule{
+			{"custom-cross-domain-sender", "High/Critical", "Cross-domain message handlers that do not check both the messenger and the source-domain sender"},
+		},
+		Run:   CheckBridgeSenders,
+		OptIn: true,
+	},
+	{
+		Name: "secrets",
+		Rules: []Rule{
+			{"custom-hardcoded-private-key", "Critical", "Private keys committed anywhere in the repository"},
+			{"custom-hardcoded-mnemonic", "Critical", "Wallet mnemonic phrases committed anywhere in the repository"},
+			{"custom-rpc-url-key", "Medium", "RPC URLs embedding a provider API key"},
+			{"custom-committed-env-file", "Medium", ".env files committed to the repository"},
+		},
+		Run:   CheckSecrets,
+		OptIn: true,
+	},
+}
+
+// All returns every built-in check in execution order.
+func All() []Check {
+	return append([]Check(nil), registry...)
+}
+
+// Names returns the selectable names of all built-in checks.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for _, c := range registry {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// ForRule returns the check that emits the rule ID.
+func ForRule(id string) (Check, bool) {
+	for _, c := range registry {
+		for _, r := range c.Rules {
+			if r.ID == id {
+				return c, true
+			}
+		}
+	}
+	return Check{}, false
+}
+
+// Select returns the checks named in names, in registry order. A check also
+// matches by any of its rule IDs, so "custom-reentrancy-ordering" selects
+// "reentrancy". An empty selection means every check but the opt-in ones.
+func Select(names []string) []Check {
+	if len(names) == 0 {
+		var defaults []Check
+		for _, c := range registry {
+			if !c.OptIn {
+				defaults = append(defaults, c)
+			}
+		}
+		return defaults
+	}
+	want := map[string]bool{}
+	for _, n := range names {
+		want[strings.TrimSpace(n)] = true
+	}
+
+	var selected []Check
+	for _, c := range registry {
+		if want[c.Name] {
+			selected = append(selected, c)
+			continue
+		}
+		for _, r := range c.Rules {
+			if want[r.ID] {
+				selected = append(selected, c)
+				break
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,182 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+var (
+	// privateKeyRe matches a 64-hex-digit value assigned to a key-like name
+	// (PRIVATE_KEY=..., privateKey: "0x...", uint256 constant PK = 0x...).
+	privateKeyRe = regexp.MustCompile(`(?i)(?:(?:private|priv|secret|deployer|signer|wallet)[_-]?key\w*|\bpk\b)["']?\s*(?::|=|:=)\s*["']?((?:0x)?[0-9a-f]{64})\b`)
+
+	// accountsKeyRe matches a key in a Hardhat network's accounts list.
+	accountsKeyRe = regexp.MustCompile(`(?i)\baccounts\s*:\s*\[\s*["']((?:0x)?[0-9a-f]{64})["']`)
+
+	// walletKeyRe matches a key passed to ethers' Wallet.
+	walletKeyRe = regexp.MustCompile(`\bWallet\(\s*["'](0x[0-9a-fA-F]{64})["']`)
+
+	// broadcastKeyRe matches a literal key passed to the Foundry cheatcodes
+	// that sign with it.
+	broadcastKeyRe = regexp.MustCompile(`\b(?:startBroadcast|broadcast|rememberKey|addr|sign)\(\s*(0x[0-9a-fA-F]{64})\s*[,)]`)
+
+	// mnemonicRe matches a phrase of lowercase words assigned to a
+	// mnemonic-like name.
+	mnemonicRe = regexp.MustCompile(`(?i)(?:mnemonic|seed[_ -]?phrase|secret[_ -]?phrase|recovery[_ -]?phrase)\w*["']?\s*(?::|=|:=)\s*["']?((?:[a-z]{3,8}\s+){11,23}[a-z]{3,8})\b`)
+
+	// rpcKeyRe matches RPC URLs carrying a provider API key, in the path
+	// (Infura, Alchemy, QuickNode, Ankr) or in the query string. The key
+	// ends the match.
+	rpcKeyRe = regexp.MustCompile(`(?:https?|wss?)://[^\s"'<>]*?(?:infura\.io/(?:ws/)?v3/[0-9a-fA-F]{32}|alchemy(?:api)?\.(?:com|io)/v2/[A-Za-z0-9_-]{20,}|quiknode\.pro/[0-9a-fA-F]{20,}|ankr\.com/[a-z_]+/[0-9a-fA-F]{32,}|[?&](?:api[-_]?key|apikey|key|token)=[A-Za-z0-9_-]{16,})`)
+	urlKeyRe = regexp.MustCompile(`[A-Za-z0-9_-]+$`)
+)
+
+// publicKeys are private keys published with development tools (the first
+// Anvil and Hardhat accounts), which hold no real funds.
+var publicKeys = map[string]bool{
+	"ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80": true,
+	"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d": true,
+	"5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a": true,
+}
+
+// publicMnemonic is the development mnemonic Anvil and Hardhat derive their
+// accounts from.
+const publicMnemonic = "test test test test test test test test test test test junk"
+
+// envTemplateSuffixes mark .env files that document variables rather than
+// hold them.
+var envTemplateSuffixes = []string{".example", ".sample", ".template", ".dist", ".defaults"}
+
+// CheckSecrets scans every file in the repository around the target, not just
+// its Solidity sources, for credentials committed next to the contracts:
+// private keys, mnemonic phrases, RPC URLs embedding provider API keys and
+// .env files. Leaked deployer keys are a common cause of real fund loss. Only
+// files git would commit are scanned: ignored paths, node_modules and
+// submodules are skipped. Reported values are redacted.
+func CheckSecrets(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := source.RepoFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(check, title, description string, severity parser.Severity, file string, line int, evidence ...string) {
+		f := parser.Finding{
+			ID:          fmt.Sprintf("CUSTOM-SECRET-%d", len(findings)+1),
+			Source:      "custom",
+			Check:       check,
+			Title:       title,
+			Description: description,
+			Severity:    severity,
+			Confidence:  "Medium",
+			File:        file,
+			Remediation: db.Remediation(check),
+			SWCRef:      db.SWC(check),
+			CWERef:      db.CWE(check),
+			Evidence:    evidence,
+		}
+		if line > 0 {
+			f.Lines = []int{line}
+		}
+		findings = append(findings, f)
+	}
+
+	for _, path := range files {
+		if filter.Limits.Screen(path) != "" {
+			continue
+		}
+		if isEnvFile(filepath.Base(path)) {
+			add("custom-committed-env-file", "Environment File Committed",
+				fmt.Sprintf("%s is committed to the repository. Deployment scripts read private keys and RPC "+
+					"credentials from .env files, so anything in it is exposed to everyone with access to the "+
+					"repository and its history.", path),
+				parser.SeverityMedium, path, 0)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			n := i + 1
+			if key := privateKey(line); key != "" {
+				add("custom-hardcoded-private-key", "Hardcoded Private Key",
+					fmt.Sprintf("%s:%d holds what looks like a private key. Anyone who can read the repository "+
+						"can sign transactions with it and drain the account and any contract roles it holds.", path, n),
+					parser.SeverityCritical, path, n, "key "+redact(key))
+			}
+			if m := mnemonicRe.FindStringSubmatch(line); m != nil {
+				words := strings.Fields(m[1])
+				phrase := strings.Join(words, " ")
+				if len(words)%3 == 0 && phrase != publicMnemonic {
+					add("custom-hardcoded-mnemonic", "Hardcoded Mnemonic Phrase",
+						fmt.Sprintf("%s:%d holds what looks like a %d-word mnemonic. It derives every account of the "+
+							"wallet, so all of them must be treated as compromised.", path, n, len(words)),
+						parser.SeverityCritical, path, n, fmt.Sprintf("mnemonic %s … (%d words)", words[0], len(words)))
+				}
+			}
+			for _, url := range rpcKeyRe.FindAllString(line, -1) {
+				add("custom-rpc-url-key", "RPC URL With Embedded API Key",
+					fmt.Sprintf("%s:%d contains an RPC URL with the provider API key in it. A leaked key can be "+
+						"used to exhaust the quota, and paid plans billed to the owner.", path, n),
+					parser.SeverityMedium, path, n, "url "+urlKeyRe.ReplaceAllStringFunc(url, redact))
+			}
+		}
+	}
+	return findings, nil
+}
+
+// privateKey returns the private key hardcoded on line, or "".
+func privateKey(line string) string {
+	for _, re := range []*regexp.Regexp{privateKeyRe, accountsKeyRe, walletKeyRe, broadcastKeyRe} {
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(m[1], "0x"), "0X"))
+		if publicKeys[key] || distinct(key) < 8 {
+			// Development keys and placeholders such as 0x000...001.
+			continue
+		}
+		return m[1]
+	}
+	return ""
+}
+
+// distinct counts the different characters in s.
+func distinct(s string) int {
+	seen := map[rune]bool{}
+	for _, r := range s {
+		seen[r] = true
+	}
+	return len(seen)
+}
+
+// isEnvFile reports whether name is a dotenv file (.env, .env.mainnet), not
+// a template such as .env.example.
+func isEnvFile(name string) bool {
+	if name != ".env" && !strings.HasPrefix(name, ".env.") {
+		return false
+	}
+	for _, suffix := range envTemplateSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// redact keeps the ends of a secret so it can be found, and hides the rest.
+func redact(secret string) string {
+	if len(secret) <= 12 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:8] + "…" + secret[len(secret)-4:]
+}
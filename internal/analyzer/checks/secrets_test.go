@@ -0,0 +1,49 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSecrets(t *testing.T) {
+	const key = "0x8f2a559490c2b7c4d6e1f3a5b7c9d0e2f4a6b8c0d1e3f5a7b9c0d2e4f6a8b0c1"
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+	write(".gitignore", ".env\n")
+	write(".env", "PRIVATE_KEY="+key+"\n")
+	write(".env.mainnet", "DEPLOYER_PRIVATE_KEY="+key+"\n")
+	write(".env.example", "PRIVATE_KEY=\n")
+	write("lib/forge-std/.git", "gitdir: ../../.git/modules/forge-std\n")
+	write("lib/forge-std/Keys.sol", "uint256 constant PRIVATE_KEY = "+key+";\n")
+	write("node_modules/pkg/index.js", "const privateKey = '"+key+"';\n")
+	write("src/Vault.sol", "contract Vault {}\n")
+
+	findings, err := CheckSecrets(filepath.Join(dir, "src"), DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "ignored .env, templates, submodules and node_modules are skipped")
+
+	assert.Equal(t, "custom-committed-env-file", findings[0].Check)
+	assert.Equal(t, filepath.Join(dir, ".env.mainnet"), findings[0].File)
+	assert.Equal(t, "custom-hardcoded-private-key", findings[1].Check)
+	assert.Equal(t, []int{1}, findings[1].Lines)
+	assert.Equal(t, []string{"key 0x8f2a55…b0c1"}, findings[1].Evidence)
+}
+
+func TestPrivateKey(t *testing.T) {
+	const key = "0x8f2a559490c2b7c4d6e1f3a5b7c9d0e2f4a6b8c0d1e3f5a7b9c0d2e4f6a8b0c1"
+	assert.Equal(t, key, privateKey(`  accounts: ["`+key+`"],`))
+	assert.Equal(t, key, privateKey(`vm.startBroadcast(`+key+`);`))
+	assert.Equal(t, key, privateKey(`pk := "`+key+`"`))
+	assert.Empty(t, privateKey(`bytes32 constant ROLE = `+key+`;`), "not assigned to a key name")
+	assert.Empty(t, privateKey(`PRIVATE_KEY=0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80`), "Anvil account")
+	assert.Empty(t, privateKey(`PRIVATE_KEY=0x0000000000000000000000000000000000000000000000000000000000000001`), "placeholder")
+}
@@ -0,0 +1,229 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/abi"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	elementaryTypeRe = regexp.MustCompile(`^(u?int\d*|bytes\d*|byte|address|bool|string|function)$`)
+	enumDeclRe       = regexp.MustCompile(`\benum\s+(\w+)\s*\{`)
+	valueTypeDeclRe  = regexp.MustCompile(`\btype\s+(\w+)\s+is\s+(\w+)\s*;`)
+	structDeclRe     = regexp.MustCompile(`(?s)\bstruct\s+(\w+)\s*\{([^}]*)\}`)
+	// mappingTypeRe splits a mapping into its key type and value type,
+	// dropping the optional key and value names of Solidity 0.8.18.
+	mappingTypeRe = regexp.MustCompile(`(?s)^mapping\s*\(\s*([\w.]+)(?:\s+\w+)?\s*=>\s*(.*?)(?:\s+\w+)?\s*\)$`)
+	// interfaceNameRe matches the conventional names of interfaces, which
+	// are often imported from outside the target (IERC20).
+	interfaceNameRe = regexp.MustCompile(`^I[A-Z]\w*$`)
+)
+
+// abiTypes resolves the user-defined types declared in a target to the
+// types they are ABI-encoded as.
+type abiTypes struct {
+	named   map[string]string   // contracts and interfaces, enums, user-defined value types
+	structs map[string][]string // struct name to its field types
+}
+
+// collectABITypes gathers the contracts, enums, user-defined value types
+// and structs declared in files.
+func collectABITypes(files []*solidity.File) abiTypes {
+	types := abiTypes{named: map[string]string{}, structs: map[string][]string{}}
+	for _, file := range files {
+		for _, c := range file.Contracts {
+			if c.Kind != "library" {
+				types.named[c.Name] = "address"
+			}
+		}
+		for _, m := range enumDeclRe.FindAllStringSubmatch(file.Code, -1) {
+			types.named[m[1]] = "uint8"
+		}
+		for _, m := range valueTypeDeclRe.FindAllStringSubmatch(file.Code, -1) {
+			types.named[m[1]] = m[2]
+		}
+		for _, m := range structDeclRe.FindAllStringSubmatch(file.Code, -1) {
+			var fields []string
+			for _, field := range strings.Split(m[2], ";") {
+				if words := strings.Fields(field); len(words) > 0 {
+					fields = append(fields, words[0])
+				}
+			}
+			types.structs[m[1]] = fields
+		}
+	}
+	return types
+}
+
+// canonicalType returns the canonical ABI type of a Solidity type as
+// written in source, e.g. "uint[]" to "uint256[]" or a struct to a tuple.
+// It reports false for types it cannot resolve, such as structs declared
+// outside the target.
+func canonicalType(types abiTypes, t string) (string, bool) {
+	return resolveType(types, t, 0)
+}
+
+func resolveType(types abiTypes, t string, depth int) (string, bool) {
+	if depth > 8 {
+		return "", false
+	}
+	t = strings.Join(strings.Fields(t), "")
+	base, suffix := t, ""
+	if i := strings.Index(t, "["); i >= 0 {
+		base, suffix = t[:i], t[i:]
+	}
+	if i := strings.LastIndex(base, "."); i >= 0 {
+		base = base[i+1:]
+	}
+	switch {
+	case base == "uint" || base == "int":
+		return base + "256" + suffix, true
+	case base == "byte":
+		return "bytes1" + suffix, true
+	case elementaryTypeRe.MatchString(base):
+		return base + suffix, true
+	}
+	if named, ok := types.named[base]; ok {
+		resolved, ok := resolveType(types, named, depth+1)
+		return resolved + suffix, ok
+	}
+	if fields, ok := types.structs[base]; ok {
+		resolved := make([]string, len(fields))
+		for i, field := range fields {
+			if resolved[i], ok = resolveType(types, field, depth+1); !ok {
+				return "", false
+			}
+		}
+		return "(" + strings.Join(resolved, ",") + ")" + suffix, true
+	}
+	if interfaceNameRe.MatchString(base) {
+		return "address" + suffix, true
+	}
+	return "", false
+}
+
+// functionSignature returns the canonical signature of fn, e.g.
+// "transfer(address,uint256)", which its selector is hashed from.
+func functionSignature(types abiTypes, fn solidity.Function) (string, bool) {
+	params := make([]string, len(fn.Params))
+	for i, p := range fn.Params {
+		var ok bool
+		if params[i], ok = canonicalType(types, p.Type); !ok {
+			return "", false
+		}
+	}
+	return fn.Name + "(" + strings.Join(params, ",") + ")", true
+}
+
+// getterSignature returns the signature of the getter the compiler
+// generates for a public state variable: one parameter per mapping key
+// and per array index.
+func getterSignature(types abiTypes, v solidity.StateVar) (string, bool) {
+	var params []string
+	t := strings.TrimSpace(v.Type)
+	for {
+		m := mappingTypeRe.FindStringSubmatch(t)
+		if m == nil {
+			break
+		}
+		key, ok := canonicalType(types, m[1])
+		if !ok {
+			return "", false
+		}
+		params = append(params, key)
+		t = strings.TrimSpace(m[2])
+	}
+	for strings.HasSuffix(t, "]") {
+		params = append(params, "uint256")
+		t = strings.TrimSpace(t[:strings.LastIndex(t, "[")])
+	}
+	return v.Name + "(" + strings.Join(params, ",") + ")", true
+}
+
+// selector is a function callable on a deployed contract, with its
+// 4-byte selector.
+type selector struct {
+	Signature string
+	Selector  string
+	Path      string
+	Contract  string // the contract declaring it, which may be a base
+	Line      int
+	Function  solidity.Function // zero for a state variable getter
+}
+
+// externalSelectors returns the functions and public getters callable on
+// c, including those of its bases declared in the target. A function a
+// derived contract overrides is listed once, from the most derived one.
+// Functions whose parameter types cannot be resolved are left out.
+func externalSelectors(types abiTypes, lineage []sourceContract) []selector {
+	seen := map[string]bool{}
+	var out []selector
+	add := func(sc sourceContract, signature string, line int, fn solidity.Function) {
+		if seen[signature] {
+			return
+		}
+		seen[signature] = true
+		out = append(out, selector{
+			Signature: signature,
+			Selector:  abi.Selector(signature),
+			Path:      sc.file.Path,
+			Contract:  sc.Name,
+			Line:      line,
+			Function:  fn,
+		})
+	}
+	for _, sc := range lineage {
+		for _, fn := range sc.Functions {
+			switch fn.Name {
+			case "constructor", "receive", "fallback":
+				continue
+			}
+			if fn.Visibility != "public" && fn.Visibility != "external" && fn.Visibility != "" {
+				continue
+			}
+			if signature, ok := functionSignature(types, fn); ok {
+				add(sc, signature, fn.Line, fn)
+			}
+		}
+		for _, v := range sc.StateVars {
+			if v.Visibility != "public" {
+				continue
+			}
+			if signature, ok := getterSignature(types, v); ok {
+				add(sc, signature, v.Line, solidity.Function{})
+			}
+		}
+	}
+	return out
+}
+
+// sourceContract is a contract together with the file declaring it.
+type sourceContract struct {
+	file *solidity.File
+	solidity.Contract
+}
+
+// lineage returns c followed by its bases declared in the target, most
+// derived first, each listed once.
+func lineage(byName map[string]sourceContract, c sourceContract) []sourceContract {
+	seen := map[string]bool{}
+	var out []sourceContract
+	var walk func(sc sourceContract)
+	walk = func(sc sourceContract) {
+		if seen[sc.Name] {
+			return
+		}
+		seen[sc.Name] = true
+		out = append(out, sc)
+		// Solidity linearizes the rightmost base as the most derived.
+		for i := len(sc.Bases) - 1; i >= 0; i-- {
+			if base, ok := byName[sc.Bases[i]]; ok {
+				walk(base)
+			}
+		}
+	}
+	walk(c)
+	return out
+}
@@ -0,0 +1,231 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	// rewardsRe matches the vocabulary of staking and reward contracts.
+	rewardsRe = regexp.MustCompile(`(?i)reward`)
+	// elapsedRe matches the time (or blocks) since the last reward update,
+	// e.g. block.timestamp - lastUpdateTime or a timeElapsed variable.
+	elapsedRe = regexp.MustCompile(`(?i)(block\.timestamp|block\.number|\bnow\b|lastTimeRewardApplicable\(\))\s*\)?\s*-\s*\w+(\.\w+)*|\b(elapsed|timeElapsed|timeDelta|deltaTime|blocksElapsed|blockDelta)\b`)
+	// supplyDivRe matches dividing by the staked supply.
+	supplyDivRe = regexp.MustCompile(`(?i)/\s*\(?\s*_?(totalSupply|totalStaked|totalShares|totalDeposits|staked\w*|supply)\b`)
+	// precisionRe matches the scaling factors that keep per-token rewards
+	// from rounding to zero.
+	precisionRe = regexp.MustCompile(`(?i)\b1e\d+\b|10\s*\*\*\s*\d+|\w*(PRECISION|WAD|SCALE|MULTIPLIER|UNIT)\w*`)
+	// narrowCastRe matches casts to integers that elapsed * rate can overflow.
+	narrowCastRe = regexp.MustCompile(`\buint(8|16|24|32|40|48|56|64|72|80|88|96|104|112|120|128)\s*\(`)
+	uncheckedRe  = regexp.MustCompile(`\bunchecked\s*\{`)
+	// rewardRateRe matches the emission rate of a reward program.
+	rewardRateRe = regexp.MustCompile(`(?i)^_?(reward\w*rate|rewards?per(second|sec|block)|emission\w*(rate|per\w*)|tokens?per(second|sec|block))$`)
+	// settleRe matches settling accrued rewards: a call such as
+	// updateReward(address(0)) or updatePool(), or writing the accumulator.
+	settleRe = regexp.MustCompile(`(?i)\b_?(update\w*|accrue\w*|checkpoint\w*|settle\w*|massUpdatePools)\s*\(|\b(rewardPerTokenStored|acc\w*PerShare|rewardIndex|rewardPerShare\w*)\s*(=|\+=)[^=]`)
+	// settleModifierRe matches modifiers that settle rewards first, e.g.
+	// Synthetix's updateReward.
+	settleModifierRe = regexp.MustCompile(`(?i)^(update\w*|accrue\w*|checkpoint\w*|settle\w*)$`)
+)
+
+// CheckStakingRewards flags the ways staking contracts drift from the
+// rewards they promise: accrual math over the time since the last update
+// that loses precision or overflows, and emission rate changes that do not
+// settle the rewards accrued at the old rate first, so the new rate is
+// applied retroactively. Only contracts that mention rewards are checked.
+func CheckStakingRewards(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-REWARDS-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" || !mentionsRewards(file, c) {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if fn.End <= fn.Line {
+					continue
+				}
+				for _, f := range accrualMath(file, c, fn) {
+					add(f)
+				}
+				if fn.Name == "constructor" || fn.Name == "initialize" || fn.Visibility == "internal" || fn.Visibility == "private" || fn.Mutability != "" {
+					continue
+				}
+				if f, ok := unsettledRateChange(file, c, fn); ok {
+					add(f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// mentionsRewards reports whether c looks like a staking or reward contract.
+func mentionsRewards(file *solidity.File, c solidity.Contract) bool {
+	for n := c.Line; n <= c.End; n++ {
+		if rewardsRe.MatchString(file.Line(n)) {
+			return true
+		}
+	}
+	return false
+}
+
+// accrualMath reports the lines of fn that multiply by the time since the
+// last update and either lose precision (no scaling factor before dividing
+// by the supply, or dividing before multiplying) or can overflow (a narrow
+// cast, or an unchecked block).
+func accrualMath(file *solidity.File, c solidity.Contract, fn solidity.Function) []parser.Finding {
+	var findings []parser.Finding
+	depth, uncheckedDepth := 0, -1
+	for n := fn.Line; n <= fn.End; n++ {
+		line := file.Line(n)
+		if uncheckedDepth < 0 && uncheckedRe.MatchString(line) {
+			uncheckedDepth = depth
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		unchecked := uncheckedDepth >= 0
+		if unchecked && depth <= uncheckedDepth {
+			uncheckedDepth = -1
+		}
+
+		elapsed := elapsedRe.FindString(line)
+		if elapsed == "" || !strings.Contains(line, "*") {
+			continue
+		}
+		code := strings.TrimSpace(line)
+		var problems []string
+		severity := parser.SeverityMedium
+		if cast := narrowCastRe.FindString(line); cast != "" {
+			problems = append(problems, fmt.Sprintf("the product is cast to %s), which overflows for long idle periods or high rates", strings.TrimSpace(cast)))
+			severity = parser.SeverityHigh
+		}
+		if unchecked {
+			problems = append(problems, "the product is computed in an unchecked block, so an overflow wraps silently")
+			severity = parser.SeverityHigh
+		}
+		if supplyDivRe.MatchString(line) && !precisionRe.MatchString(line) {
+			problems = append(problems, "it divides by the staked supply without a precision factor such as 1e18, so the per-token reward rounds down to zero once the supply is large")
+		} else if divideBeforeMultiply(line) {
+			problems = append(problems, "it divides before multiplying, so the remainder of every update is lost")
+		}
+		if len(problems) == 0 {
+			continue
+		}
+
+		findings = append(findings, parser.Finding{
+			Check: "custom-reward-accrual-math",
+			Title: fmt.Sprintf("Reward Accrual Drift in %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() accrues rewards over the time since the last update, but %s. Stakers are paid less (or more) than the emission schedule, and the error compounds with every update.",
+				file.Path, n, c.Name, fn.Name, strings.Join(problems, "; "),
+			),
+			Severity:   severity,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{n},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", code, n),
+				fmt.Sprintf("elapsed time `%s`", strings.TrimSpace(elapsed)),
+			},
+		})
+	}
+	return findings
+}
+
+// divideBeforeMultiply reports whether a division on line comes before a
+// multiplication, ignoring comments and exponentiation.
+func divideBeforeMultiply(line string) bool {
+	if i := strings.Index(line, "//"); i >= 0 {
+		line = line[:i]
+	}
+	div := -1
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '/':
+			if div < 0 {
+				div = i
+			}
+		case '*':
+			if i+1 < len(line) && line[i+1] == '*' || i > 0 && line[i-1] == '*' {
+				continue
+			}
+			if div >= 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unsettledRateChange reports fn changing the reward rate without first
+// settling the rewards accrued at the old one.
+func unsettledRateChange(file *solidity.File, c solidity.Contract, fn solidity.Function) (parser.Finding, bool) {
+	for _, m := range fn.Modifiers {
+		if settleModifierRe.MatchString(m) {
+			return parser.Finding{}, false
+		}
+	}
+	// The first statement is the header, whose name may look like a call.
+	stmts := statements(file, fn)
+	for i, s := range stmts {
+		if i == 0 {
+			continue
+		}
+		if settleRe.MatchString(s.Text) {
+			return parser.Finding{}, false
+		}
+		m := paramAssignRe.FindStringSubmatch(s.Text)
+		if m == nil || !isStateVar(c, m[1]) && !storagePointer(stmts[:i], m[1]) {
+			continue
+		}
+		name := m[1]
+		if f := fieldRe.FindStringSubmatch(m[2]); f != nil {
+			name = f[1]
+		}
+		if !rewardRateRe.MatchString(name) {
+			continue
+		}
+		return parser.Finding{
+			Check: "custom-reward-rate-unsettled",
+			Title: fmt.Sprintf("Reward Rate Changed Without Settling: %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() changes '%s' without first updating the accrued rewards. The next update applies the new rate to the whole period since the last one, so rewards earned at the old rate are lost or inflated.",
+				file.Path, s.Line, c.Name, fn.Name, name,
+			),
+			Severity:   parser.SeverityHigh,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("no update modifier, update call or accumulator write before it in %s()", fn.Name),
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckStakingRewards(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Farm {
+    struct Pool { uint256 rewardPerSecond; uint256 accRewardPerShare; uint256 lastRewardTime; }
+    Pool[] public pools;
+
+    function updatePool(uint256 pid) public {
+        Pool storage pool = pools[pid];
+        uint256 elapsed = block.timestamp - pool.lastRewardTime;
+        pool.accRewardPerShare += elapsed * pool.rewardPerSecond * 1e12 / _staked(pid);
+        pool.lastRewardTime = block.timestamp;
+    }
+
+    function pending(uint256 pid, uint256 amount) external view returns (uint256) {
+        Pool storage pool = pools[pid];
+        return amount * (pool.accRewardPerShare + (block.timestamp - pool.lastRewardTime) / 60 * pool.rewardPerSecond);
+    }
+
+    function updateRewardRate(uint256 pid, uint256 rate) external onlyOwner {
+        Pool storage pool = pools[pid];
+        pool.rewardPerSecond = rate;
+    }
+
+    function setRate(uint256 pid, uint256 rate) external onlyOwner {
+        updatePool(pid);
+        pools[pid].rewardPerSecond = rate;
+    }
+}
+
+contract Vesting {
+    uint256 public ratePerSecond;
+
+    function vested(uint256 start) external view returns (uint256) {
+        return (block.timestamp - start) / 30 days * ratePerSecond;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Farm.sol"), []byte(content), 0644))
+
+	findings, err := CheckStakingRewards(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "scaled accrual, a settled rate change and a contract without rewards are skipped")
+
+	drift := findings[0]
+	assert.Equal(t, "custom-reward-accrual-math", drift.Check)
+	assert.Equal(t, parser.SeverityMedium, drift.Severity)
+	assert.Equal(t, []int{16}, drift.Lines)
+	assert.Contains(t, drift.Description, "divides before multiplying")
+	assert.Equal(t, "elapsed time `block.timestamp - pool.lastRewardTime`", drift.Evidence[1])
+
+	rate := findings[1]
+	assert.Equal(t, "custom-reward-rate-unsettled", rate.Check)
+	assert.Equal(t, "Reward Rate Changed Without Settling: Farm.updateRewardRate()", rate.Title)
+	assert.Equal(t, []int{21}, rate.Lines, "the function name is not a settling call")
+	assert.Equal(t, "CUSTOM-REWARDS-2", rate.ID)
+}
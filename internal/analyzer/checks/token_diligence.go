@@ -0,0 +1,337 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+// transferFunctions are the ERC-20 entry points and hooks every transfer
+// passes through, where honeypots hide their sell restrictions.
+var transferFunctions = map[string]bool{
+	"transfer": true, "transferFrom": true, "_transfer": true, "_update": true,
+	"_tokenTransfer": true, "_beforeTokenTransfer": true, "_afterTokenTransfer": true,
+}
+
+// balanceWriterRe matches the functions expected to write balances: transfers,
+// mints, burns and the constructor.
+var balanceWriterRe = regexp.MustCompile(`(?i)^(constructor|_?transfer\w*|_update|_tokenTransfer|_?mint\w*|_?burn\w*)$`)
+
+var (
+	conditionRe    = regexp.MustCompile(`\b(require|if)\s*\(`)
+	pairRe         = regexp.MustCompile(`(?i)\b\w*(pair|router)\w*\b`)
+	gateRe         = regexp.MustCompile(`(?i)\b\w*(owner|whitelist|allowlist|cansell|sellallowed|authori[sz]ed|admin)\w*\b`)
+	blacklistFnRe  = regexp.MustCompile(`(?i)(black|block|deny)list|sniper|^(set|add|remove|del|block|mark)?bots?$|^ban`)
+	balanceWriteRe = regexp.MustCompile(`\b(\w*[Bb]alance\w*)\s*\[([^\]]*)\]\s*([+-]?=)[^=]`)
+	feeVarRe       = regexp.MustCompile(`(?i)fee|tax`)
+)
+
+// CheckTokenDueDiligence looks for the patterns scam tokens use to trap or
+// drain holders: transfer paths that let only privileged addresses sell,
+// fees the owner can raise without bound, blacklists, and functions that
+// rewrite other accounts' balances. It is meant for reviewing third-party
+// tokens before integrating them; the patterns also appear in legitimate
+// tokens with centralized controls, which integrators should know about too.
+// Only contracts that look like ERC-20 tokens are checked.
+func CheckTokenDueDiligence(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-TOKEN-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if !isToken(c) {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if fn.Mutability == "view" || fn.Mutability == "pure" {
+					continue
+				}
+				if transferFunctions[fn.Name] {
+					if f, ok := sellRestriction(file, c, fn); ok {
+						add(f)
+					}
+				}
+				if f, ok := blacklist(file, c, fn); ok {
+					add(f)
+				}
+				if f, ok := balanceRewrite(file, c, fn); ok {
+					add(f)
+				}
+			}
+			for _, f := range unboundedFees(file, c) {
+				add(f)
+			}
+		}
+	}
+	return findings, nil
+}
+
+// isToken reports whether c looks like an ERC-20 implementation.
+func isToken(c solidity.Contract) bool {
+	if c.Kind != "contract" {
+		return false
+	}
+	for _, b := range c.Bases {
+		if strings.Contains(b, "ERC20") {
+			return true
+		}
+	}
+	for _, fn := range c.Functions {
+		if transferFunctions[fn.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// statement is one statement of a function body, with its first line.
+type statement struct {
+	Text string
+	Line int
+}
+
+// statements splits fn's body into statements at ";", "{" and "}".
+func statements(file *solidity.File, fn solidity.Function) []statement {
+	var stmts []statement
+	var cur strings.Builder
+	start := 0
+	for n := fn.Line; n <= fn.End; n++ {
+		for _, r := range file.Line(n) + "\n" {
+			switch r {
+			case ';', '{', '}':
+				if text := strings.TrimSpace(cur.String()); text != "" {
+					stmts = append(stmts, statement{Text: text, Line: start})
+				}
+				cur.Reset()
+				continue
+			}
+			if cur.Len() == 0 || strings.TrimSpace(cur.String()) == "" {
+				start = n
+			}
+			cur.WriteRune(r)
+		}
+	}
+	return stmts
+}
+
+// sellRestriction reports a transfer function that reverts based on both
+// the AMM pair (i.e. whether the transfer is a sell) and an owner or
+// whitelist flag: buys go through, but only privileged addresses can sell.
+func sellRestriction(file *solidity.File, c solidity.Contract, fn solidity.Function) (parser.Finding, bool) {
+	var pairLine, gateLine int
+	var pairStmt, gateStmt string
+	reverts := false
+	for _, s := range statements(file, fn) {
+		if strings.Contains(s.Text, "revert") || strings.Contains(s.Text, "require") {
+			reverts = true
+		}
+		if !conditionRe.MatchString(s.Text) {
+			continue
+		}
+		if pairLine == 0 && pairRe.MatchString(s.Text) {
+			pairLine, pairStmt = s.Line, s.Text
+		}
+		if gateLine == 0 && gateRe.MatchString(s.Text) {
+			gateLine, gateStmt = s.Line, s.Text
+		}
+	}
+	if !reverts || pairLine == 0 || gateLine == 0 {
+		return parser.Finding{}, false
+	}
+	lines := []int{pairLine}
+	if gateLine != pairLine {
+		lines = append(lines, gateLine)
+	}
+	return parser.Finding{
+		Check: "custom-honeypot-sell-restriction",
+		Title: fmt.Sprintf("Possible Honeypot: Sell Restriction in %s.%s()", c.Name, fn.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — %s.%s() can revert depending on both the trading pair and an owner or "+
+				"whitelist condition. Tokens built this way let anyone buy but only privileged "+
+				"addresses sell, trapping holders' funds.",
+			file.Path, pairLine, c.Name, fn.Name,
+		),
+		Severity:   parser.SeverityCritical,
+		Confidence: "Low",
+		File:       file.Path,
+		Lines:      lines,
+		Function:   fn.Name,
+		Evidence: []string{
+			fmt.Sprintf("pair condition at line %d: `%s`", pairLine, pairStmt),
+			fmt.Sprintf("privilege condition at line %d: `%s`", gateLine, gateStmt),
+			fmt.Sprintf("%s() is on every transfer path", fn.Name),
+		},
+	}, true
+}
+
+// blacklist reports an externally callable function that adds addresses to
+// a blacklist (or bot/sniper list) the token checks on transfer.
+func blacklist(file *solidity.File, c solidity.Contract, fn solidity.Function) (parser.Finding, bool) {
+	if fn.Visibility == "internal" || fn.Visibility == "private" || !blacklistFnRe.MatchString(fn.Name) {
+		return parser.Finding{}, false
+	}
+	evidence := []string{fmt.Sprintf("function name `%s` matches a blacklist pattern", fn.Name)}
+	if len(fn.Modifiers) > 0 {
+		evidence = append(evidence, "restricted by "+strings.Join(fn.Modifiers, ", "))
+	}
+	return parser.Finding{
+		Check: "custom-blacklist",
+		Title: fmt.Sprintf("Blacklist Function: %s.%s()", c.Name, fn.Name),
+		Description: fmt.Sprintf(
+			"%s:%d — %s.%s() lets its caller block addresses. A blacklisted holder can "+
+				"no longer transfer or sell, so the token's owner can freeze any position, "+
+				"including an integrating protocol's.",
+			file.Path, fn.Line, c.Name, fn.Name,
+		),
+		Severity:   parser.SeverityMedium,
+		Confidence: "Medium",
+		File:       file.Path,
+		Lines:      []int{fn.Line},
+		Function:   fn.Name,
+		Evidence:   evidence,
+	}, true
+}
+
+// balanceRewrite reports a function other than a transfer, mint, burn or
+// the constructor that writes the balance of an account other than the
+// caller.
+func balanceRewrite(file *solidity.File, c solidity.Contract, fn solidity.Function) (parser.Finding, bool) {
+	if balanceWriterRe.MatchString(fn.Name) {
+		return parser.Finding{}, false
+	}
+	for _, s := range statements(file, fn) {
+		m := balanceWriteRe.FindStringSubmatch(s.Text)
+		if m == nil || !isStateVar(c, m[1]) {
+			continue
+		}
+		if key := strings.TrimSpace(m[2]); key == "msg.sender" || key == "_msgSender()" {
+			continue
+		}
+		return parser.Finding{
+			Check: "custom-balance-rewrite",
+			Title: fmt.Sprintf("Balance Rewrite in %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() writes %s[%s] directly, outside any transfer, mint or burn. "+
+					"Whoever can call it can set any holder's balance, silently draining "+
+					"or inflating positions without Transfer events or supply changes.",
+				file.Path, s.Line, c.Name, fn.Name, m[1], strings.TrimSpace(m[2]),
+			),
+			Severity:   parser.SeverityCritical,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("%s() is not a transfer, mint or burn function", fn.Name),
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// unboundedFees reports fee and tax state variables that a function sets
+// from its arguments without comparing them to a cap, so they can be
+// raised to 100% (or beyond, making every transfer revert).
+func unboundedFees(file *solidity.File, c solidity.Contract) []parser.Finding {
+	var findings []parser.Finding
+	for _, v := range c.StateVars {
+		if v.Constant || !strings.HasPrefix(v.Type, "uint") || !feeVarRe.MatchString(v.Name) {
+			continue
+		}
+		assignRe := regexp.MustCompile(`^` + regexp.QuoteMeta(v.Name) + `\s*=[^=](.*)$`)
+		for _, fn := range c.Functions {
+			if fn.Name == "constructor" || fn.Visibility == "internal" || fn.Visibility == "private" {
+				continue
+			}
+			stmts := statements(file, fn)
+			for _, s := range stmts {
+				m := assignRe.FindStringSubmatch(s.Text)
+				if m == nil {
+					continue
+				}
+				param := paramIn(fn, m[1])
+				if param == "" || capped(stmts, param, v.Name) {
+					continue
+				}
+				findings = append(findings, parser.Finding{
+					Check: "custom-unbounded-fee",
+					Title: fmt.Sprintf("Unbounded Fee: %s.%s", c.Name, v.Name),
+					Description: fmt.Sprintf(
+						"%s:%d — %s.%s() sets the fee variable '%s' from its argument '%s' "+
+							"without an upper bound. The fee can be raised to 100%% of every "+
+							"transfer, or high enough that sells revert.",
+						file.Path, s.Line, c.Name, fn.Name, v.Name, param,
+					),
+					Severity:   parser.SeverityHigh,
+					Confidence: "Medium",
+					File:       file.Path,
+					Lines:      []int{s.Line},
+					Function:   fn.Name,
+					Evidence: []string{
+						fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+						fmt.Sprintf("no comparison bounds `%s` or `%s` in %s()", param, v.Name, fn.Name),
+					},
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// paramIn returns the first of fn's parameters that expr mentions.
+func paramIn(fn solidity.Function, expr string) string {
+	for _, p := range fn.Params {
+		if p.Name != "" && regexp.MustCompile(`\b`+regexp.QuoteMeta(p.Name)+`\b`).MatchString(expr) {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// capped reports whether a require or if condition compares param or the
+// fee variable against an upper bound.
+func capped(stmts []statement, names ...string) bool {
+	for _, s := range stmts {
+		if !conditionRe.MatchString(s.Text) {
+			continue
+		}
+		for _, name := range names {
+			n := regexp.QuoteMeta(name)
+			if regexp.MustCompile(`\b` + n + `\s*(<|>)|(<|>)=?\s*` + n + `\b`).MatchString(s.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isStateVar(c solidity.Contract, name string) bool {
+	for _, v := range c.StateVars {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTokenDueDiligence(t *testing.T) {
+	content := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract ScamToken {
+    mapping(address => uint256) private _balances;
+    mapping(address => bool) private _isBlacklisted;
+    address public uniswapV2Pair;
+    address private _owner;
+    uint256 public sellFee;
+    uint256 public buyFee;
+
+    function transfer(address to, uint256 amount) external returns (bool) {
+        _transfer(msg.sender, to, amount);
+        return true;
+    }
+
+    function _transfer(address from, address to, uint256 amount) internal {
+        require(!_isBlacklisted[from], "blocked");
+        if (to == uniswapV2Pair) {
+            require(from == _owner, "no sells");
+        }
+        _balances[from] -= amount;
+        _balances[to] += amount;
+    }
+
+    function setFees(uint256 newSell, uint256 newBuy) external {
+        require(msg.sender == _owner);
+        require(newBuy <= 10, "cap");
+        sellFee = newSell;
+        buyFee = newBuy;
+    }
+
+    function blacklistAddress(address account) external {
+        _isBlacklisted[account] = true;
+    }
+
+    function syncBalance(address account, uint256 amount) external {
+        _balances[account] = amount;
+    }
+}
+
+contract Vault {
+    mapping(address => uint256) public balances;
+    uint256 public fee;
+
+    function withdraw(address account) external {
+        balances[account] = 0;
+    }
+
+    function setFee(uint256 f) external {
+        fee = f;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Token.sol"), []byte(content), 0644))
+
+	findings, err := CheckTokenDueDiligence(dir, DefaultFilter)
+	require.NoError(t, err)
+
+	byCheck := map[string][]int{}
+	for _, f := range findings {
+		assert.Equal(t, "custom", f.Source)
+		assert.NotEmpty(t, f.Remediation, f.Check)
+		byCheck[f.Check] = append(byCheck[f.Check], f.Lines[0])
+	}
+	// Vault is not a token, and buyFee is capped.
+	assert.Equal(t, map[string][]int{
+		"custom-honeypot-sell-restriction": {19},
+		"custom-blacklist":                 {33},
+		"custom-balance-rewrite":           {38},
+		"custom-unbounded-fee":             {29},
+	}, byCheck)
+}
+
+func TestCheckTokenDueDiligence_CleanToken(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Token is ERC20, Ownable {
+    uint256 public constant MAX_FEE = 500;
+    uint256 public fee;
+
+    function setFee(uint256 newFee) external onlyOwner {
+        require(newFee <= MAX_FEE, "fee too high");
+        fee = newFee;
+    }
+
+    function _update(address from, address to, uint256 value) internal override {
+        if (to != address(0) && from != owner()) {
+            value -= value * fee / 10_000;
+        }
+        super._update(from, to, value);
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Token.sol"), []byte(content), 0644))
+
+	findings, err := CheckTokenDueDiligence(dir, DefaultFilter)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
@@ -0,0 +1,237 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	// ledgerWriteRe matches writes to the mappings tokens keep holdings in,
+	// including reflection (_rOwned) and share-based ledgers.
+	ledgerWriteRe  = regexp.MustCompile(`(?i)\b(\w*(?:balance|owned|shares)\w*)\s*\[([^\]]*)\]\s*([-+]?=)[^=]`)
+	supplyWriteRe  = regexp.MustCompile(`\b(_?totalSupply_?|_supply)\s*([-+]?=)[^=]`)
+	mappingWriteRe = regexp.MustCompile(`\]\s*([-+]?=)[^=]`)
+	emitTransferRe = regexp.MustCompile(`\bemit\s+Transfer\s*\(`)
+	amountDeclRe   = regexp.MustCompile(`^u?int\d*\s+([A-Za-z_$][\w$]*)\s*=\s*(.+)$`)
+)
+
+// CheckTransferEvents reconciles ERC-20 state changes with the Transfer
+// events indexers rebuild balances from: entry points (and the
+// constructor) that change balances or the total supply without emitting
+// Transfer on their path, functions whose Transfer amount differs from
+// what they credit or mint, and entry points that emit Transfer without
+// changing any balance. Paths that call super or inherited helpers are
+// skipped.
+func CheckTransferEvents(target string, filter Filter) ([]parser.Finding, error) {
+	paths, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-EVENT-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	for _, path := range paths {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if !isToken(c) {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if !hasBody(file, fn) || fn.Mutability == "view" || fn.Mutability == "pure" {
+					continue
+				}
+				if fn.Name == "constructor" || fn.Visibility != "internal" && fn.Visibility != "private" {
+					if f, ok := unloggedPath(file, c, fn); ok {
+						add(f)
+					}
+				}
+				if f, ok := eventAmountMismatch(file, c, fn); ok {
+					add(f)
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// unloggedPath reports an entry point whose path writes balances or the
+// supply without emitting Transfer, or emits Transfer without writing any
+// mapping or the supply.
+func unloggedPath(file *solidity.File, c solidity.Contract, fn solidity.Function) (parser.Finding, bool) {
+	fns := reachableFunctions(file, c, fn)
+	body := reachable(file, c, fn)
+	if opaque(c, body) {
+		return parser.Finding{}, false
+	}
+
+	var writes, emits []string
+	var balances, supply bool
+	emitLine := 0
+	for _, f := range fns {
+		for _, s := range statements(file, f)[1:] {
+			switch {
+			case ledgerWriteRe.MatchString(s.Text):
+				balances = true
+				writes = append(writes, fmt.Sprintf("line %d: %s", s.Line, oneLine(s.Text)))
+			case supplyWriteRe.MatchString(s.Text):
+				supply = true
+				writes = append(writes, fmt.Sprintf("line %d: %s", s.Line, oneLine(s.Text)))
+			case emitTransferRe.MatchString(s.Text):
+				if emitLine == 0 {
+					emitLine = s.Line
+				}
+				emits = append(emits, fmt.Sprintf("line %d: %s", s.Line, oneLine(s.Text)))
+			}
+		}
+	}
+
+	switch {
+	case len(writes) > 0 && len(emits) == 0:
+		changed := "balances"
+		switch {
+		case supply && balances:
+			changed = "balances and the total supply"
+		case supply:
+			changed = "the total supply"
+		}
+		severity := parser.SeverityMedium
+		effect := fmt.Sprintf("Indexers, wallets and explorers rebuild %s from Transfer events, so their view drifts from the contract's state", changed)
+		if fn.Name == "constructor" {
+			severity = parser.SeverityLow
+			effect = "Tokens created at deployment should be announced with Transfer from address(0); without it, indexers never see them"
+		}
+		return parser.Finding{
+			Check: "custom-transfer-event-missing",
+			Title: fmt.Sprintf("Balance Change Without Transfer Event: %s.%s", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s changes %s but no Transfer event is emitted on its path. %s.",
+				file.Path, fn.Line, c.Name, fn.Name, changed, effect,
+			),
+			Severity:   severity,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{fn.Line},
+			Function:   fn.Name,
+			Evidence:   writes,
+		}, true
+	case len(emits) > 0 && len(writes) == 0 && !mappingWriteRe.MatchString(body):
+		return parser.Finding{
+			Check: "custom-transfer-event-mismatch",
+			Title: fmt.Sprintf("Transfer Event Without Balance Change: %s.%s", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s emits Transfer without changing any balance, so explorers and wallets show transfers that never happened, the pattern fake airdrops use to spam addresses.",
+				file.Path, emitLine, c.Name, fn.Name,
+			),
+			Severity:   parser.SeverityMedium,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{emitLine},
+			Function:   fn.Name,
+			Evidence:   emits,
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// eventAmountMismatch reports the first balance credit or supply change
+// in fn whose amount matches none of the amounts fn's own Transfer events
+// report, e.g. crediting amount - fee while emitting amount. Local
+// variables are resolved to their initializers before comparing.
+func eventAmountMismatch(file *solidity.File, c solidity.Contract, fn solidity.Function) (parser.Finding, bool) {
+	stmts := statements(file, fn)[1:]
+	aliases := map[string]string{}
+	emitted := map[string]bool{}
+	var emits []string
+	for _, s := range stmts {
+		if m := amountDeclRe.FindStringSubmatch(s.Text); m != nil {
+			aliases[m[1]] = m[2]
+		}
+		if loc := emitTransferRe.FindStringIndex(s.Text); loc != nil {
+			close := matchParen(s.Text, loc[1]-1)
+			if close < 0 {
+				continue
+			}
+			if args := callArgs(s.Text[loc[1]:close]); len(args) == 3 {
+				emitted[resolveAmount(aliases, args[2], 0)] = true
+				emits = append(emits, fmt.Sprintf("line %d: %s", s.Line, oneLine(s.Text)))
+			}
+		}
+	}
+	if len(emitted) == 0 {
+		return parser.Finding{}, false
+	}
+
+	for _, s := range stmts {
+		var amount, change string
+		if m := balanceWriteRe.FindStringSubmatchIndex(s.Text); m != nil && s.Text[m[6]:m[7]] == "+=" {
+			amount = s.Text[m[7]:]
+			change = fmt.Sprintf("credits `%s` to %s", oneLine(amount), oneLine(s.Text[m[2]:m[5]+1]))
+		} else if m := supplyWriteRe.FindStringSubmatchIndex(s.Text); m != nil && s.Text[m[4]:m[5]] != "=" {
+			amount = s.Text[m[5]:]
+			change = fmt.Sprintf("changes the total supply by `%s`", oneLine(amount))
+		} else {
+			continue
+		}
+		if emitted[resolveAmount(aliases, amount, 0)] {
+			continue
+		}
+		return parser.Finding{
+			Check: "custom-transfer-event-mismatch",
+			Title: fmt.Sprintf("Transfer Event Amount Mismatch: %s.%s", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s %s, but its Transfer events report a different amount. Indexers summing Transfer events then disagree with balanceOf and totalSupply, which breaks accounting and reconciliation.",
+				file.Path, s.Line, c.Name, fn.Name, change,
+			),
+			Severity:   parser.SeverityMedium,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence:   append([]string{fmt.Sprintf("line %d: %s", s.Line, oneLine(s.Text))}, emits...),
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// resolveAmount normalizes an amount expression for comparison: spaces
+// and enclosing parentheses are dropped, and a local variable is replaced
+// by its initializer.
+func resolveAmount(aliases map[string]string, expr string, depth int) string {
+	expr = strings.Join(strings.Fields(expr), "")
+	for strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") && balanced(expr[1:len(expr)-1]) {
+		expr = expr[1 : len(expr)-1]
+	}
+	if value, ok := aliases[expr]; ok && depth < 4 {
+		return resolveAmount(aliases, value, depth+1)
+	}
+	return expr
+}
+
+// callArgs splits a call's argument list at its top-level commas.
+func callArgs(args string) []string {
+	var out []string
+	for {
+		arg := firstArg(args)
+		out = append(out, strings.TrimSpace(arg))
+		if len(arg) == len(args) {
+			return out
+		}
+		args = args[len(arg)+1:]
+	}
+}
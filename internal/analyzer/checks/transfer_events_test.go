@@ -0,0 +1,75 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckTransferEvents(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Token {
+    mapping(address => uint256) public balanceOf;
+    uint256 public totalSupply;
+
+    function transfer(address to, uint256 amount) external returns (bool) {
+        _move(msg.sender, to, amount);
+        emit Transfer(msg.sender, to, amount);
+        return true;
+    }
+
+    function batch(address[] calldata to, uint256[] calldata amounts) external {
+        for (uint256 i = 0; i < to.length; i++) {
+            balanceOf[msg.sender] -= amounts[i];
+            unchecked {
+                balanceOf[to[i]] += amounts[i];
+            }
+            emit Transfer(msg.sender, to[i], amounts[i]);
+        }
+    }
+
+    function burn(uint256 amount) external {
+        uint256 burned = (amount);
+        balanceOf[msg.sender] -= burned;
+        totalSupply -= burned;
+        emit Transfer(msg.sender, address(0), amount);
+    }
+
+    function wrap() external payable {
+        _mint(msg.sender, msg.value);
+    }
+
+    function sweep(address from) external {
+        balanceOf[from] = 0;
+    }
+
+    function _move(address from, address to, uint256 value) internal {
+        balanceOf[from] -= value;
+        balanceOf[to] += value;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Token.sol"), []byte(content), 0644))
+
+	findings, err := CheckTransferEvents(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 1, "emits in callers, batches, aliases and inherited _mint are not flagged")
+
+	sweep := findings[0]
+	assert.Equal(t, "custom-transfer-event-missing", sweep.Check)
+	assert.Equal(t, parser.SeverityMedium, sweep.Severity)
+	assert.Equal(t, []int{34}, sweep.Lines)
+	assert.Equal(t, "CUSTOM-EVENT-1", sweep.ID)
+	assert.Equal(t, []string{"line 35: balanceOf[from] = 0"}, sweep.Evidence)
+}
+
+func TestCallArgs(t *testing.T) {
+	assert.Equal(t, []string{"from", "to[i]", "f(a, b)"}, callArgs("from, to[i], f(a, b)"))
+	assert.Equal(t, []string{""}, callArgs(""))
+}
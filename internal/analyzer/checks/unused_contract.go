@@ -0,0 +1,172 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// deployDirs are the directories, relative to the project root, where
+// Foundry, Hardhat, Truffle and Ape keep deployment scripts and artifacts.
+var deployDirs = []string{"script", "scripts", "deploy", "deployments", "ignition", "migrations", "tasks"}
+
+var identifierRe = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+
+// CheckUnusedContracts flags contracts that are in scope but never used: their
+// file is not imported by any other file, nothing else in the file refers to
+// them, and (for deployable contracts) no deployment script mentions them.
+// Stale code widens the audit surface without shipping.
+//
+// Concrete contracts are only flagged when the project has deployment
+// scripts to check against; otherwise every entry point would look unused.
+func CheckUnusedContracts(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := solidity.LoadGraph(files)
+	if err != nil {
+		return nil, err
+	}
+	root := source.ProjectRoot(target)
+	deployed, haveDeploys := deploymentReferences(root)
+	db := rules.Default()
+
+	var findings []parser.Finding
+	for _, path := range graph.Paths() {
+		if isTestOrScript(root, path) || len(graph.ImportedBy(path)) > 0 {
+			continue
+		}
+		file := graph.Files[path]
+		for _, c := range file.Contracts {
+			if referencedInFile(file, c.Name) {
+				continue
+			}
+			deployable := c.Kind == "contract" && !c.Abstract
+			if deployable && (!haveDeploys || deployed[c.Name]) {
+				continue
+			}
+
+			reason := "is never imported or referenced by another file in scope"
+			evidence := []string{
+				fmt.Sprintf("%s is imported by 0 of %d file(s) in scope", path, len(files)),
+				fmt.Sprintf("`%s` appears only in its own declaration at line %d", c.Name, c.Line),
+			}
+			if deployable {
+				reason += " and no deployment script references it"
+				evidence = append(evidence, fmt.Sprintf("`%s` not mentioned under %s/{%s}", c.Name, root, strings.Join(deployDirs, ",")))
+			} else {
+				evidence = append(evidence, fmt.Sprintf("%s cannot be deployed on its own", describeKind(c)))
+			}
+			findings = append(findings, parser.Finding{
+				ID:     fmt.Sprintf("CUSTOM-UNUSED-%d", len(findings)+1),
+				Source: "custom",
+				Check:  "custom-unused-contract",
+				Title:  fmt.Sprintf("Unused %s: %s", describeKind(c), c.Name),
+				Description: fmt.Sprintf(
+					"%s '%s' (%s line %d) %s. Stale code in scope still has to be audited "+
+						"and can be deployed or copied by mistake.",
+					upperFirst(describeKind(c)), c.Name, path, c.Line, reason,
+				),
+				Severity:    parser.SeverityInformational,
+				Confidence:  "Medium",
+				File:        path,
+				Lines:       []int{c.Line},
+				Remediation: db.Remediation("custom-unused-contract"),
+				CWERef:      db.CWE("custom-unused-contract"),
+				References: []string{
+					"https://cwe.mitre.org/data/definitions/561.html",
+				},
+				Evidence: evidence,
+			})
+		}
+	}
+	return findings, nil
+}
+
+func describeKind(c solidity.Contract) string {
+	if c.Abstract {
+		return "abstract contract"
+	}
+	return c.Kind
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// referencedInFile reports whether name appears in the file beyond its own
+// declaration, e.g. as a base contract, library call or `new` expression.
+func referencedInFile(f *solidity.File, name string) bool {
+	re := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+	return len(re.FindAllStringIndex(f.Code, 2)) > 1
+}
+
+// isTestOrScript reports whether path is a test or deployment script, which
+// are entry points by nature and never imported.
+func isTestOrScript(root, path string) bool {
+	base := filepath.Base(path)
+	if strings.HasSuffix(base, ".t.sol") || strings.HasSuffix(base, ".s.sol") {
+		return true
+	}
+	dir := filepath.Dir(path)
+	if abs, err := filepath.Abs(dir); err == nil {
+		if rel, err := filepath.Rel(root, abs); err == nil {
+			dir = rel
+		}
+	}
+	for _, part := range strings.Split(filepath.ToSlash(dir), "/") {
+		switch part {
+		case "test", "tests", "script", "scripts":
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentReferences collects every identifier mentioned in the project's
+// deployment scripts. found is false when the project has none.
+func deploymentReferences(root string) (idents map[string]bool, found bool) {
+	idents = map[string]bool{}
+	for _, dir := range deployDirs {
+		_ = filepath.Walk(filepath.Join(root, dir), func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return filepath.SkipDir
+			}
+			if fi.IsDir() {
+				if fi.Name() == "node_modules" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch filepath.Ext(path) {
+			case ".sol", ".js", ".ts", ".mjs", ".cjs", ".json", ".py", ".yaml", ".yml", ".toml":
+			default:
+				return nil
+			}
+			if fi.Size() > 4<<20 {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			found = true
+			for _, id := range identifierRe.FindAllString(string(data), -1) {
+				idents[id] = true
+			}
+			return nil
+		})
+	}
+	return idents, found
+}
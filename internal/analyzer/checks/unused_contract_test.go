@@ -0,0 +1,47 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUnusedContracts(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	write("foundry.toml", "[profile.default]\n")
+	write("src/IVault.sol", "interface IVault {}\n")
+	write("src/Vault.sol", "import \"./IVault.sol\";\ncontract Vault is IVault {}\n")
+	write("src/Legacy.sol", "library OldMath {}\n\ncontract LegacyVault {}\n")
+	write("test/Vault.t.sol", "import \"../src/Vault.sol\";\ncontract VaultTest {}\n")
+	write("script/Deploy.s.sol", "contract Deploy { function run() external { new Vault(); } }\n")
+
+	findings, err := CheckUnusedContracts(filepath.Join(dir, "src"), DefaultFilter)
+	require.NoError(t, err)
+
+	var titles []string
+	for _, f := range findings {
+		assert.Equal(t, "custom-unused-contract", f.Check)
+		titles = append(titles, f.Title)
+	}
+	assert.ElementsMatch(t, []string{"Unused library: OldMath", "Unused contract: LegacyVault"}, titles)
+}
+
+func TestCheckUnusedContracts_NoDeployScripts(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Token.sol"), []byte("interface IUnused {}\ncontract Token {}\n"), 0644))
+
+	findings, err := CheckUnusedContracts(dir, DefaultFilter)
+	require.NoError(t, err)
+
+	// Without deployment scripts, concrete contracts are assumed to be entry points.
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Unused interface: IUnused", findings[0].Title)
+}
@@ -0,0 +1,115 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	// shareMathRe matches converting assets to shares pro rata to the
+	// share supply, e.g. assets * totalSupply() / totalAssets() or
+	// mulDiv(assets, totalSupply, totalAssets()).
+	shareMathRe = regexp.MustCompile(`\*\s*(totalSupply\(\)|totalSupply|_totalSupply|totalShares)\s*\)?\s*/|mulDiv\w*\([^;]*\b(totalSupply|_totalSupply|totalShares)\b`)
+	// inflationGuardRe matches the usual defences: OpenZeppelin's decimals
+	// offset, virtual shares or assets, dead shares minted on the first
+	// deposit (as Uniswap V2's MINIMUM_LIQUIDITY), a minimum deposit, or a
+	// supply offset such as totalSupply() + 1.
+	inflationGuardRe = regexp.MustCompile(`(?i)_decimalsOffset|virtual_?(shares|assets|offset)|minimum_liquidity|dead_?shares|min_?(imum_?)?deposit|_mint\(\s*address\((0|0xdead)|(totalSupply\(\)|totalSupply|totalShares)\s*\+\s*(1\b|10\s*\*\*)`)
+	// emptyVaultRe matches the first-deposit branch taken while no shares
+	// exist, e.g. totalSupply() == 0 ? assets : ...
+	emptyVaultRe = regexp.MustCompile(`\b(totalSupply\(\)|totalSupply|_totalSupply|totalShares|supply)\s*==\s*0\b`)
+	// donatableRe matches assets read from the vault's own balance, which
+	// anyone can raise by transferring to the vault directly.
+	donatableRe = regexp.MustCompile(`\.balanceOf\(\s*address\(\s*this\s*\)\s*\)|address\(\s*this\s*\)\.balance\b`)
+)
+
+// vaultSignal is a line of a vault's share accounting, with its evidence.
+type vaultSignal struct {
+	line     int
+	evidence string
+}
+
+// CheckVaultInflation flags vaults that mint shares pro rata to the share
+// supply without a defence against the first-depositor inflation attack:
+// the first depositor mints one share, then donates assets directly to the
+// vault so that the next deposit rounds down to zero shares, and redeems
+// the victim's assets with their own. It is High when the vault values its
+// assets from its own token balance, which donations raise, and Medium when
+// it appears to track them internally. One finding is reported per
+// contract, at its first share computation.
+func CheckVaultInflation(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" {
+				continue
+			}
+			var share, empty, donation *vaultSignal
+			guard := false
+			for n := c.Line; n <= c.End; n++ {
+				code := file.Line(n)
+				if inflationGuardRe.MatchString(code) {
+					guard = true
+					break
+				}
+				if loc := shareMathRe.FindStringIndex(code); share == nil && loc != nil {
+					share = &vaultSignal{n, fmt.Sprintf("share computation `%s` at line %d col %d", strings.TrimSpace(code[loc[0]:loc[1]]), n, loc[0]+1)}
+				}
+				if loc := emptyVaultRe.FindStringIndex(code); empty == nil && loc != nil {
+					empty = &vaultSignal{n, fmt.Sprintf("first-deposit branch `%s` at line %d col %d", code[loc[0]:loc[1]], n, loc[0]+1)}
+				}
+				if loc := donatableRe.FindStringIndex(code); donation == nil && loc != nil {
+					donation = &vaultSignal{n, fmt.Sprintf("assets read from the vault's own balance `%s` at line %d col %d, which direct transfers raise", code[loc[0]:loc[1]], n, loc[0]+1)}
+				}
+			}
+			if share == nil || guard {
+				continue
+			}
+
+			evidence := []string{share.evidence}
+			if empty != nil {
+				evidence = append(evidence, empty.evidence)
+			}
+			severity, exposure := parser.SeverityMedium, "Assets appear to be tracked internally, which resists donations, but any path that raises them without minting shares (rewards, rebasing, fees) re-opens the attack."
+			if donation != nil {
+				evidence = append(evidence, donation.evidence)
+				severity, exposure = parser.SeverityHigh, "Assets are valued from the vault's own balance, so a direct transfer inflates the share price."
+			}
+			evidence = append(evidence, "no _decimalsOffset, virtual shares or assets, dead shares, minimum deposit or supply offset in the contract")
+
+			findings = append(findings, parser.Finding{
+				ID:     fmt.Sprintf("CUSTOM-INFLATION-%d", len(findings)+1),
+				Source: "custom",
+				Check:  "custom-first-depositor-inflation",
+				Title:  fmt.Sprintf("First-Depositor Share Inflation in %s", c.Name),
+				Description: fmt.Sprintf(
+					"%s:%d — '%s' mints shares pro rata to the share supply with no virtual offset, dead shares or minimum deposit. The first depositor can mint a single share and donate assets to the vault so that later deposits round down to zero shares, stealing them. %s",
+					path, share.line, c.Name, exposure,
+				),
+				Severity:    severity,
+				Confidence:  "Medium",
+				File:        path,
+				Lines:       []int{share.line},
+				Remediation: db.Remediation("custom-first-depositor-inflation"),
+				CWERef:      db.CWE("custom-first-depositor-inflation"),
+				Evidence:    evidence,
+			})
+		}
+	}
+	return findings, nil
+}
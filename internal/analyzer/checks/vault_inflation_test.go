@@ -0,0 +1,91 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckVaultInflation(t *testing.T) {
+	vulnerable := `pragma solidity ^0.8.0;
+
+contract Vault {
+    function convertToShares(uint256 assets) public view returns (uint256) {
+        uint256 supply = totalSupply();
+        return supply == 0 ? assets : assets.mulDiv(supply, totalAssets());
+    }
+
+    function previewDeposit(uint256 assets) public view returns (uint256) {
+        return assets * totalSupply() / totalAssets();
+    }
+
+    function totalAssets() public view returns (uint256) {
+        return asset.balanceOf(address(this));
+    }
+}
+`
+	offset := `pragma solidity ^0.8.0;
+
+contract Vault {
+    function _decimalsOffset() internal pure returns (uint8) {
+        return 3;
+    }
+
+    function previewDeposit(uint256 assets) public view returns (uint256) {
+        return assets * totalSupply() / totalAssets();
+    }
+}
+`
+	tracked := `pragma solidity ^0.8.0;
+
+contract Staking {
+    uint256 public totalShares;
+    uint256 public totalStaked;
+
+    function stake(uint256 amount) external returns (uint256 shares) {
+        shares = totalShares == 0 ? amount : amount * totalShares / totalStaked;
+        totalStaked += amount;
+        totalShares += shares;
+    }
+}
+`
+	deadShares := `pragma solidity ^0.8.0;
+
+contract Pool {
+    uint256 public constant MINIMUM_LIQUIDITY = 1000;
+
+    function mint(uint256 amount) external returns (uint256 liquidity) {
+        liquidity = amount * totalSupply / reserve;
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Vault.sol"), []byte(vulnerable), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Offset.sol"), []byte(offset), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pool.sol"), []byte(deadShares), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Staking.sol"), []byte(tracked), 0644))
+
+	findings, err := CheckVaultInflation(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "one finding per contract; the offset and dead shares defend the others")
+
+	staking := findings[0]
+	assert.Equal(t, filepath.Join(dir, "Staking.sol"), staking.File)
+	assert.Equal(t, parser.SeverityMedium, staking.Severity, "assets are tracked internally")
+	assert.Equal(t, []int{8}, staking.Lines)
+	assert.Contains(t, staking.Evidence[1], "first-deposit branch `totalShares == 0` at line 8")
+
+	vault := findings[1]
+	assert.Equal(t, "custom-first-depositor-inflation", vault.Check)
+	assert.Equal(t, filepath.Join(dir, "Vault.sol"), vault.File)
+	assert.Equal(t, parser.SeverityHigh, vault.Severity, "donations raise totalAssets()")
+	assert.Equal(t, []int{10}, vault.Lines, "mulDiv with a local supply copy is not matched")
+	assert.Contains(t, vault.Evidence[0], "`* totalSupply() /` at line 10")
+	assert.Contains(t, vault.Evidence[1], "first-deposit branch `supply == 0` at line 6")
+	assert.Contains(t, vault.Evidence[2], "`.balanceOf(address(this))` at line 14")
+	assert.NotEmpty(t, vault.Remediation)
+}
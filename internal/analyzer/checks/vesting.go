@@ -0,0 +1,194 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+var (
+	// vestingRe matches the vocabulary of vesting wallets and token locks.
+	vestingRe = regexp.MustCompile(`(?i)vest|cliff|timelock|unlock\w*time|release\w*time`)
+	// scheduleRe matches the terms of a grant: who receives it and when it
+	// unlocks.
+	scheduleRe = regexp.MustCompile(`(?i)^_?(beneficiar\w*|recipient|grantee|duration\w*|\w*cliff\w*|start\w*|end\w*|vesting\w*(period|duration|start|end|schedule)|release\w*(time|date|rate|schedule|period)|unlock\w*(time|date|at)?|lock\w*(until|end|time|period|duration))$`)
+	// beneficiaryRe matches the holder of a grant, who may move or extend
+	// it themselves.
+	beneficiaryRe = regexp.MustCompile(`(?i)beneficiar|recipient|grantee`)
+	// onceRe matches the conditions that let a setter run only once: an
+	// initialized flag, or the value being unset.
+	onceRe = regexp.MustCompile(`(?i)initiali[sz]ed|==\s*(0|address\(0\))\s*[,)]`)
+	// revokeRe matches the functions that end a grant early.
+	revokeRe = regexp.MustCompile(`(?i)^_?(revoke|terminate|cancel|clawback|claw)\w*$`)
+	// ownerPayoutRe matches tokens or ether sent back to the grantor.
+	ownerPayoutRe = regexp.MustCompile(`\b(?:safeTransfer|transfer)\s*\(\s*(owner\(\)|_?owner|msg\.sender|_msgSender\(\)|\w*(admin|treasury|grantor)\w*)\s*,|\bpayable\s*\(\s*(owner\(\)|_?owner|msg\.sender|_msgSender\(\)|\w*(admin|treasury|grantor)\w*)\s*\)`)
+	// vestedRe matches accounting for what has already vested.
+	vestedRe = regexp.MustCompile(`(?i)vested|releasable|unreleased|released|claimable`)
+)
+
+// CheckVestingSchedules flags vesting wallets and token locks whose terms
+// the admin can change after the grant is made: functions that reassign
+// the beneficiary or move the start, cliff, duration or unlock time, and
+// revoke functions that send the whole balance back to the owner instead
+// of only the unvested part. Either lets the grantor take back tokens the
+// beneficiary has already earned. Only contracts that talk about vesting,
+// cliffs or unlock times are checked.
+func CheckVestingSchedules(target string, filter Filter) ([]parser.Finding, error) {
+	files, err := filter.solidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	add := func(f parser.Finding) {
+		f.ID = fmt.Sprintf("CUSTOM-VESTING-%d", len(findings)+1)
+		f.Source = "custom"
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+
+	for _, path := range files {
+		file, err := solidity.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		for _, c := range file.Contracts {
+			if c.Kind != "contract" || !isVesting(file, c) {
+				continue
+			}
+			for _, fn := range c.Functions {
+				if fn.Name == "constructor" || fn.Visibility == "internal" || fn.Visibility == "private" || fn.Mutability != "" || fn.End <= fn.Line {
+					continue
+				}
+				if slices.Contains(fn.Modifiers, "initializer") {
+					continue
+				}
+				stmts := statements(file, fn)
+				if f, ok := mutableSchedule(file, c, fn, stmts); ok {
+					add(f)
+				}
+				if revokeRe.MatchString(fn.Name) {
+					if f, ok := revokeVested(file, c, fn, stmts); ok {
+						add(f)
+					}
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// isVesting reports whether c looks like a vesting wallet or token lock.
+func isVesting(file *solidity.File, c solidity.Contract) bool {
+	for n := c.Line; n <= c.End; n++ {
+		if vestingRe.MatchString(file.Line(n)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mutableSchedule reports fn setting a term of a grant from its arguments
+// when the grant may already be running. Setters that can only run once,
+// and beneficiaries moving their own grant, are left alone.
+func mutableSchedule(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement) (parser.Finding, bool) {
+	for i, s := range stmts[1:] {
+		m := paramAssignRe.FindStringSubmatch(s.Text)
+		if m == nil || !isStateVar(c, m[1]) && !storagePointer(stmts[:i+1], m[1]) {
+			continue
+		}
+		name := m[1]
+		if f := fieldRe.FindStringSubmatch(m[2]); f != nil {
+			name = f[1]
+		}
+		if !scheduleRe.MatchString(name) || paramIn(fn, m[3]) == "" {
+			continue
+		}
+		if setOnce(stmts, name) || beneficiaryOnly(stmts) {
+			return parser.Finding{}, false
+		}
+		return parser.Finding{
+			Check: "custom-vesting-schedule-mutable",
+			Title: fmt.Sprintf("Vesting Schedule Changeable After Grant: %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() sets '%s' from its arguments with no check that the schedule has not started. The grantor can redirect the grant or push its unlock back after the beneficiary has earned it, which bypasses the vesting or timelock entirely.",
+				file.Path, s.Line, c.Name, fn.Name, name,
+			),
+			Severity:   parser.SeverityHigh,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("%s() is not an initializer and does not check that '%s' is unset", fn.Name, name),
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
+
+// setOnce reports whether a condition lets the setter run only while name
+// is unset or the contract uninitialized.
+func setOnce(stmts []statement, name string) bool {
+	for _, s := range stmts[1:] {
+		if !conditionRe.MatchString(s.Text) || !onceRe.MatchString(s.Text) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(s.Text), "initiali") || mentions(s.Text, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// beneficiaryOnly reports whether fn is restricted to the beneficiary, so
+// the holder of the grant is the one changing it.
+func beneficiaryOnly(stmts []statement) bool {
+	for _, s := range stmts[1:] {
+		if conditionRe.MatchString(s.Text) && strings.Contains(s.Text, "msg.sender") && beneficiaryRe.MatchString(s.Text) {
+			return true
+		}
+	}
+	return false
+}
+
+// revokeVested reports a revoke that pays the grantor without working out
+// what has already vested, so the beneficiary loses earned tokens too.
+func revokeVested(file *solidity.File, c solidity.Contract, fn solidity.Function, stmts []statement) (parser.Finding, bool) {
+	if vestedRe.MatchString(functionBody(file, fn)) {
+		return parser.Finding{}, false
+	}
+	for _, s := range stmts[1:] {
+		m := ownerPayoutRe.FindStringSubmatch(s.Text)
+		if m == nil {
+			continue
+		}
+		to := m[1] + m[3]
+		return parser.Finding{
+			Check: "custom-vesting-revoke-vested",
+			Title: fmt.Sprintf("Revoke Returns Vested Tokens: %s.%s()", c.Name, fn.Name),
+			Description: fmt.Sprintf(
+				"%s:%d — %s.%s() sends funds to '%s' without computing the vested or releasable amount first. Revoking takes back tokens the beneficiary has already earned, not just the unvested remainder.",
+				file.Path, s.Line, c.Name, fn.Name, to,
+			),
+			Severity:   parser.SeverityHigh,
+			Confidence: "Medium",
+			File:       file.Path,
+			Lines:      []int{s.Line},
+			Function:   fn.Name,
+			Evidence: []string{
+				fmt.Sprintf("`%s` at line %d", s.Text, s.Line),
+				fmt.Sprintf("no vested, releasable or released amount in %s()", fn.Name),
+			},
+		}, true
+	}
+	return parser.Finding{}, false
+}
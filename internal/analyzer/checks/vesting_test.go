@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCheckVestingSchedules(t *testing.T) {
+	content := `pragma solidity ^0.8.0;
+
+contract Grants {
+    struct Grant { address beneficiary; uint64 cliff; uint256 amount; }
+    mapping(uint256 => Grant) public grants;
+    uint256 public unlockTime;
+    bool public initialized;
+
+    function init(uint256 unlockAt) external onlyOwner {
+        require(!initialized, "initialized");
+        initialized = true;
+        unlockTime = unlockAt;
+    }
+
+    function setCliff(uint256 id, uint64 cliff) external onlyOwner {
+        Grant storage grant = grants[id];
+        grant.cliff = cliff;
+    }
+
+    function cancel(uint256 id) external onlyOwner {
+        delete grants[id];
+        payable(owner()).transfer(address(this).balance);
+    }
+}
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Grants.sol"), []byte(content), 0644))
+
+	findings, err := CheckVestingSchedules(dir, DefaultFilter)
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "a setter guarded by an initialized flag is skipped")
+
+	cliff := findings[0]
+	assert.Equal(t, "custom-vesting-schedule-mutable", cliff.Check)
+	assert.Equal(t, parser.SeverityHigh, cliff.Severity)
+	assert.Equal(t, []int{17}, cliff.Lines)
+	assert.Contains(t, cliff.Description, "sets 'cliff'")
+
+	revoke := findings[1]
+	assert.Equal(t, "custom-vesting-revoke-vested", revoke.Check)
+	assert.Equal(t, "Revoke Returns Vested Tokens: Grants.cancel()", revoke.Title)
+	assert.Equal(t, []int{22}, revoke.Lines)
+	assert.Equal(t, "CUSTOM-VESTING-2", revoke.ID)
+}
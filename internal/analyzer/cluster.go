@@ -0,0 +1,51 @@
+package analyzer
+
+import "github.com/Zubimendi/solsec/internal/parser"
+
+// Cluster groups findings that share a root cause: the same check reporting
+// the same RootCause inside one contract (e.g. the same access-control
+// modifier missing from a dozen functions, or one state variable written
+// after external calls in several functions). Each group becomes its most
+// severe finding with the others attached as Related, so the design flaw is
+// listed and scored once. Findings without a root cause, or without a
+// contract, are left alone. The summary still counts every finding.
+func Cluster(report *parser.AnalysisReport) {
+	groups := map[string][]int{}
+	var order []string
+	for i, f := range report.Findings {
+		if f.Contract == "" || f.RootCause == "" || len(f.Related) > 0 {
+			continue
+		}
+		key := f.Check + "|" + f.File + "|" + f.Contract + "|" + f.RootCause
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	drop := map[int]bool{}
+	for _, key := range order {
+		members := groups[key]
+		if len(members) < 2 {
+			continue
+		}
+		// Findings are sorted most severe first, so the first member leads.
+		parent := &report.Findings[members[0]]
+		for _, i := range members[1:] {
+			parent.Related = append(parent.Related, report.Findings[i])
+			drop[i] = true
+		}
+	}
+	if len(drop) == 0 {
+		return
+	}
+
+	kept := make([]parser.Finding, 0, len(report.Findings)-len(drop))
+	for i, f := range report.Findings {
+		if !drop[i] {
+			kept = append(kept, f)
+		}
+	}
+	report.Findings = kept
+	report.Summary = buildSummary(kept)
+}
@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+)
+
+// Compliance frameworks, as recorded in parser.ComplianceControl.Framework.
+const (
+	FrameworkEthTrust = "EthTrust"
+	FrameworkSCSVS    = "SCSVS"
+)
+
+// complianceMatrix lists the controls evaluated by the evaluated checks,
+// with no findings counted yet (see countCompliance).
+func complianceMatrix(evaluated []string) []parser.ComplianceControl {
+	db := rules.Default()
+	byKey := map[[2]string]*parser.ComplianceControl{}
+	add := func(framework, control, check string) {
+		key := [2]string{framework, control}
+		c, ok := byKey[key]
+		if !ok {
+			c = &parser.ComplianceControl{Framework: framework, Control: control}
+			byKey[key] = c
+		}
+		if !slices.Contains(c.Checks, check) {
+			c.Checks = append(c.Checks, check)
+		}
+	}
+	for _, check := range evaluated {
+		for _, control := range db.EthTrust(check) {
+			add(FrameworkEthTrust, control, check)
+		}
+		for _, control := range db.SCSVS(check) {
+			add(FrameworkSCSVS, control, check)
+		}
+	}
+
+	matrix := make([]parser.ComplianceControl, 0, len(byKey))
+	for _, c := range byKey {
+		sort.Strings(c.Checks)
+		matrix = append(matrix, *c)
+	}
+	sort.Slice(matrix, func(i, j int) bool {
+		if matrix[i].Framework != matrix[j].Framework {
+			return matrix[i].Framework < matrix[j].Framework
+		}
+		return matrix[i].Control < matrix[j].Control
+	})
+	return matrix
+}
+
+// countCompliance recounts the findings against each control, including
+// clustered ones, so the matrix follows every pipeline stage.
+func countCompliance(report *parser.AnalysisReport) {
+	if len(report.Compliance) == 0 {
+		return
+	}
+	perCheck := map[string]int{}
+	for _, f := range parser.Flatten(report.Findings) {
+		perCheck[f.Check]++
+	}
+	for i := range report.Compliance {
+		c := &report.Compliance[i]
+		c.Findings = 0
+		for _, check := range c.Checks {
+			c.Findings += perCheck[check]
+		}
+	}
+}
+
+// customRules returns the rule IDs the selected custom checks can emit.
+func customRules(selected []string) []string {
+	var ids []string
+	for _, c := range checks.Select(selected) {
+		for _, r := range c.Rules {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids
+}
+
+// mergeCompliance rebuilds the matrix for the union of the checks
+// evaluated by each report.
+func mergeCompliance(reports []*parser.AnalysisReport) []parser.ComplianceControl {
+	var evaluated []string
+	for _, r := range reports {
+		for _, c := range r.Compliance {
+			evaluated = append(evaluated, c.Checks...)
+		}
+	}
+	sort.Strings(evaluated)
+	return complianceMatrix(slices.Compact(evaluated))
+}
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// Confidences are the confidence levels findings carry, most certain first.
+var Confidences = []string{"High", "Medium", "Low"}
+
+// ParseConfidence validates a --min-confidence level and returns its
+// canonical form. Names are case-insensitive; "" means no minimum.
+func ParseConfidence(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	for _, c := range Confidences {
+		if strings.EqualFold(name, c) {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown confidence %q (use %s)", name, strings.ToLower(strings.Join(Confidences, ", ")))
+}
+
+// filterConfidence drops findings less certain than min. Findings whose
+// confidence is missing or not a known level are kept, since nothing says
+// they are noise.
+func filterConfidence(findings []parser.Finding, min string) []parser.Finding {
+	threshold := slices.Index(Confidences, min)
+	if threshold < 0 {
+		return findings
+	}
+	kept := findings[:0]
+	for _, f := range findings {
+		if rank := slices.IndexFunc(Confidences, func(c string) bool { return strings.EqualFold(c, f.Confidence) }); rank > threshold {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
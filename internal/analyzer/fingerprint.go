@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// fingerprint identifies a finding independently of line numbers, so it stays
+// the same when unrelated code above it moves: the check, the file relative
+// to the analysis root, the enclosing contract and function, and the
+// whitespace-normalized code of the flagged line.
+func fingerprint(root string, f parser.Finding, code string) string {
+	file := f.File
+	if abs, err := filepath.Abs(file); err == nil {
+		if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	parts := []string{f.Check, filepath.ToSlash(file), f.Contract, f.Function, strings.Join(strings.Fields(code), " ")}
+	if code == "" {
+		// Nothing to anchor on besides the title (e.g. a compiler setting).
+		parts = append(parts, f.Title)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// fingerprintRoot is the directory fingerprint paths are relative to: the
+// target itself, or the directory of a single-file target.
+func fingerprintRoot(target string) string {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return target
+	}
+	if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+		return filepath.Dir(abs)
+	}
+	return abs
+}
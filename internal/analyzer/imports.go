@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// buildImportGraph resolves the import statements of every file in the target
+// that exclude keeps into a dependency graph. Unresolved imports become
+// external nodes.
+func buildImportGraph(target string, exclude *source.Excluder) (*parser.ImportGraph, error) {
+	files, err := source.SolidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	files = exclude.Filter(files)
+	g, err := solidity.LoadGraph(files)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &parser.ImportGraph{}
+	external := map[string]bool{}
+	for _, path := range g.Paths() {
+		node := parser.GraphNode{Path: path}
+		for _, c := range g.Files[path].Contracts {
+			node.Contracts = append(node.Contracts, c.Name)
+		}
+		out.Nodes = append(out.Nodes, node)
+
+		for _, to := range g.Imports[path] {
+			out.Edges = append(out.Edges, parser.GraphEdge{From: path, To: to})
+		}
+		for _, ext := range g.External[path] {
+			out.Edges = append(out.Edges, parser.GraphEdge{From: path, To: ext})
+			external[ext] = true
+		}
+	}
+	for _, ext := range sortedKeys(external) {
+		out.Nodes = append(out.Nodes, parser.GraphNode{Path: ext, External: true})
+	}
+	return out, nil
+}
+
+// mergeImportGraphs unions the import graphs of several reports.
+func mergeImportGraphs(reports []*parser.AnalysisReport) *parser.ImportGraph {
+	out := &parser.ImportGraph{}
+	seenNode := map[string]bool{}
+	seenEdge := map[parser.GraphEdge]bool{}
+	for _, r := range reports {
+		if r.ImportGraph == nil {
+			continue
+		}
+		for _, n := range r.ImportGraph.Nodes {
+			if !seenNode[n.Path] {
+				seenNode[n.Path] = true
+				out.Nodes = append(out.Nodes, n)
+			}
+		}
+		for _, e := range r.ImportGraph.Edges {
+			if !seenEdge[e] {
+				seenEdge[e] = true
+				out.Edges = append(out.Edges, e)
+			}
+		}
+	}
+	if len(out.Nodes) == 0 {
+		return nil
+	}
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,21 @@
+package analyzer
+
+import (
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// link sets each finding's CodeURL when the target is in a git repository
+// hosted on GitHub or GitLab. Links point at the checked-out commit, so they
+// keep matching the reported lines after later pushes.
+func link(target string, findings []parser.Finding) {
+	host := source.DetectCodeHost(target)
+	if host == nil {
+		return
+	}
+	for i := range findings {
+		if f := &findings[i]; f.File != "" && f.CodeURL == "" {
+			f.CodeURL = host.URL(f.File, f.Lines)
+		}
+	}
+}
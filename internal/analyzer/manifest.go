@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// buildManifest describes every Solidity file in the target that exclude
+// keeps: its content hash, size, pragma, and the engines that covered it.
+// Files listed in skipped were excluded from the engines (e.g. compile
+// errors) and only got custom checks; files failing limits were excluded
+// from the custom checks.
+func buildManifest(target string, exclude *source.Excluder, engines []string, skipped map[string]string, limits source.Limits) ([]parser.AnalyzedFile, error) {
+	files, err := source.SolidityFiles(target)
+	if err != nil {
+		return nil, err
+	}
+	files = exclude.Filter(files)
+
+	manifest := make([]parser.AnalyzedFile, 0, len(files))
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		entry := parser.AnalyzedFile{
+			Path:    path,
+			SHA256:  hex.EncodeToString(sum[:]),
+			SLOC:    source.CountSLOC(string(data)),
+			Pragma:  source.Pragma(string(data)),
+			Engines: []string{"custom"},
+		}
+		if reason := limits.Screen(path); reason != "" {
+			entry.Excluded = reason
+			entry.Engines = []string{}
+		}
+		if reason, ok := skipped[path]; ok {
+			entry.Skipped = true
+			entry.SkipReason = reason
+		} else {
+			entry.Engines = append(entry.Engines, engines...)
+		}
+		manifest = append(manifest, entry)
+	}
+	return manifest, nil
+}
+
+// manifestWarnings reports the files of manifest that the engines or the
+// custom checks left out.
+func manifestWarnings(manifest []parser.AnalyzedFile) []parser.Warning {
+	var warnings []parser.Warning
+	for _, f := range manifest {
+		if f.Skipped {
+			warnings = append(warnings, parser.Warning{Kind: parser.WarningCompileFailure, File: f.Path,
+				Message: "not analyzed by the engines: " + f.SkipReason})
+		}
+		if f.Excluded != "" {
+			warnings = append(warnings, parser.Warning{Kind: parser.WarningSkippedFile, File: f.Path,
+				Message: "skipped by the custom checks: " + f.Excluded})
+		}
+	}
+	return warnings
+}
+
+// mergeManifests concatenates manifests, keeping the first entry per path.
+func mergeManifests(reports []*parser.AnalysisReport) []parser.AnalyzedFile {
+	seen := map[string]bool{}
+	var out []parser.AnalyzedFile
+	for _, r := range reports {
+		for _, f := range r.AnalyzedFiles {
+			if seen[f.Path] {
+				continue
+			}
+			seen[f.Path] = true
+			out = append(out, f)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// Processor is one post-processing stage of the report pipeline, run after
+// engine and custom findings are merged. Adding a stage means implementing
+// this interface and calling Register — Analyze and Merge pick it up by
+// name. Stages read their settings (thresholds, overrides, the triage
+// database) from opts, and do nothing when theirs are unset.
+type Processor interface {
+	Name() string
+	Process(report *parser.AnalysisReport, opts Options) error
+}
+
+// ProcessorFunc adapts a function to the Processor interface.
+type ProcessorFunc struct {
+	ID string
+	Fn func(report *parser.AnalysisReport, opts Options) error
+}
+
+func (p ProcessorFunc) Name() string { return p.ID }
+
+func (p ProcessorFunc) Process(report *parser.AnalysisReport, opts Options) error {
+	return p.Fn(report, opts)
+}
+
+// DefaultPipeline is the stage order used when none is configured: findings
+// are filtered by confidence, their severity adjusted (archetype
+// escalation, then severity_overrides, then path_severity) and filtered,
+// then triaged, baselined, deduplicated and clustered.
+var DefaultPipeline = []string{
+	"min-confidence", "escalate", "severity-overrides", "path-severity", "min-severity",
+	"triage", "baseline", "dedup", "cluster",
+}
+
+var processors = map[string]Processor{}
+
+func init() {
+	Register(ProcessorFunc{"min-confidence", func(r *parser.AnalysisReport, opts Options) error {
+		r.Findings = filterConfidence(r.Findings, opts.MinConfidence)
+		return nil
+	}})
+	Register(ProcessorFunc{"escalate", func(r *parser.AnalysisReport, opts Options) error {
+		if opts.Archetype == "" {
+			return nil
+		}
+		a, err := LookupArchetype(opts.Archetype)
+		if err != nil {
+			return err
+		}
+		escalate(r.Findings, a)
+		return nil
+	}})
+	Register(ProcessorFunc{"severity-overrides", func(r *parser.AnalysisReport, opts Options) error {
+		OverrideSeverities(r.Findings, opts.SeverityOverrides)
+		return nil
+	}})
+	Register(ProcessorFunc{"path-severity", func(r *parser.AnalysisReport, opts Options) error {
+		AdjustPathSeverities(r.Findings, opts.PathSeverities)
+		return nil
+	}})
+	Register(ProcessorFunc{"min-severity", func(r *parser.AnalysisReport, opts Options) error {
+		r.Findings = filterSeverity(r.Findings, opts.MinSeverity)
+		return nil
+	}})
+	Register(ProcessorFunc{"triage", applyTriage})
+	Register(ProcessorFunc{"baseline", applyBaseline})
+	Register(ProcessorFunc{"dedup", func(r *parser.AnalysisReport, _ Options) error {
+		// Remove findings that duplicate another engine's (same file + first
+		// line + same SWC reference).
+		var dropped []parser.Warning
+		r.Findings, dropped = deduplicate(r.Findings)
+		r.Warnings = append(r.Warnings, dropped...)
+		return nil
+	}})
+	Register(ProcessorFunc{"cluster", func(r *parser.AnalysisReport, _ Options) error {
+		Cluster(r)
+		return nil
+	}})
+}
+
+// Register makes p available to pipelines under p.Name(). It panics if the
+// name is already taken, as registration happens at init time.
+func Register(p Processor) {
+	if _, dup := processors[p.Name()]; dup {
+		panic(fmt.Sprintf("analyzer: processor %q registered twice", p.Name()))
+	}
+	processors[p.Name()] = p
+}
+
+// ProcessorNames lists the registered processors, sorted.
+func ProcessorNames() []string {
+	names := make([]string, 0, len(processors))
+	for name := range processors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidatePipeline checks that every stage in names is registered.
+func ValidatePipeline(names []string) error {
+	for _, name := range names {
+		if _, ok := processors[name]; !ok {
+			return fmt.Errorf("unknown pipeline stage %q (available: %s)", name, strings.Join(ProcessorNames(), ", "))
+		}
+	}
+	return nil
+}
+
+// Process runs the stages named by opts.Pipeline over report in order, with
+// the settings in opts. Findings are re-sorted and re-summarized before each
+// stage and after the last, so every stage sees a consistent report whatever
+// the ones before it did. A nil pipeline runs DefaultPipeline; an empty one
+// only sorts and summarizes.
+func Process(report *parser.AnalysisReport, opts Options) error {
+	names := opts.Pipeline
+	if names == nil {
+		names = DefaultPipeline
+	}
+	if err := ValidatePipeline(names); err != nil {
+		return err
+	}
+	for _, name := range names {
+		finalize(report)
+		if err := processors[name].Process(report, opts); err != nil {
+			return fmt.Errorf("pipeline stage %s: %w", name, err)
+		}
+	}
+	finalize(report)
+	return nil
+}
+
+// finalize sorts findings most severe first and recomputes the summary.
+// Ties are broken by location, check and ID, so identical inputs always
+// produce the same order.
+func finalize(report *parser.AnalysisReport) {
+	findings := report.Findings
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := &findings[i], &findings[j]
+		if ra, rb := parser.SeverityRank(a.Severity), parser.SeverityRank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if la, lb := firstLine(a), firstLine(b); la != lb {
+			return la < lb
+		}
+		if a.Check != b.Check {
+			return a.Check < b.Check
+		}
+		return a.ID < b.ID
+	})
+	report.Summary = buildSummary(findings)
+	countCompliance(report)
+	countArchetype(report)
+}
+
+func firstLine(f *parser.Finding) int {
+	if len(f.Lines) == 0 {
+		return 0
+	}
+	return f.Lines[0]
+}
@@ -0,0 +1,176 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/baseline"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/triage"
+)
+
+func TestProcess(t *testing.T) {
+	var seen []string
+	Register(ProcessorFunc{"test-downgrade", func(r *parser.AnalysisReport, _ Options) error {
+		seen = append(seen, r.Findings[0].ID)
+		for i := range r.Findings {
+			if r.Findings[i].Check == "noisy" {
+				r.Findings[i].Severity = parser.SeverityInformational
+			}
+		}
+		return nil
+	}})
+	defer delete(processors, "test-downgrade")
+	assert.Panics(t, func() { Register(ProcessorFunc{ID: "dedup"}) }, "names are unique")
+
+	findings := func() []parser.Finding {
+		return []parser.Finding{
+			{ID: "A", Check: "quiet", Severity: parser.SeverityLow, File: "a.sol", Lines: []int{1}},
+			{ID: "B", Check: "noisy", Severity: parser.SeverityHigh, File: "a.sol", Lines: []int{2}, SWCRef: "SWC-107"},
+			{ID: "C", Check: "noisy", Severity: parser.SeverityHigh, File: "a.sol", Lines: []int{2}, SWCRef: "SWC-107"},
+		}
+	}
+
+	report := &parser.AnalysisReport{Findings: findings()}
+	require.NoError(t, Process(report, Options{Pipeline: []string{"dedup", "test-downgrade"}}))
+	assert.Equal(t, []string{"B"}, seen, "each stage sees the findings sorted most severe first")
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, "A", report.Findings[0].ID, "re-sorted after the last stage")
+	assert.Equal(t, 1, report.Summary.Low)
+	assert.Equal(t, 1, report.Summary.Informational)
+	assert.Zero(t, report.Summary.High)
+
+	report = &parser.AnalysisReport{Findings: findings()}
+	require.NoError(t, Process(report, Options{Pipeline: []string{}}))
+	assert.Len(t, report.Findings, 3, "an empty pipeline only sorts and summarizes")
+	assert.Equal(t, 2, report.Summary.High)
+
+	err := Process(report, Options{Pipeline: []string{"dedup", "sort"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown pipeline stage "sort"`)
+	assert.Len(t, report.Findings, 3, "nothing runs when a stage is unknown")
+}
+
+func TestDedupKey(t *testing.T) {
+	cases := []struct {
+		finding parser.Finding
+		want    string
+	}{
+		{parser.Finding{Check: "reentrancy-eth", SWCRef: "SWC-107", CWERef: "CWE-841", File: "a.sol", Lines: []int{5, 9}}, "SWC-107|a.sol|5"},
+		{parser.Finding{Check: "custom-deploy-hardcoded-key", CWERef: "CWE-798", File: "a.sol", Lines: []int{5}}, "CWE-798|a.sol|5"},
+		{parser.Finding{Check: "custom-deploy-no-confirmations", File: "a.sol", Lines: []int{5}}, "custom-deploy-no-confirmations|a.sol|5"},
+		{parser.Finding{Check: "custom-license", File: "a.sol"}, "custom-license|a.sol"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, dedupKey(c.finding), c.finding.Check)
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	kept, dropped := deduplicate([]parser.Finding{
+		{ID: "A", Check: "reentrancy-eth", SWCRef: "SWC-107", File: "a.sol", Lines: []int{5}},
+		{ID: "B", Check: "custom-reentrancy-ordering", SWCRef: "SWC-107", File: "a.sol", Lines: []int{5}},
+		{ID: "C", Check: "custom-hardcoded-private-key", CWERef: "CWE-798", File: "a.sol", Lines: []int{5}},
+		{ID: "D", Check: "custom-deploy-hardcoded-key", CWERef: "CWE-798", File: "a.sol", Lines: []int{5}},
+		{ID: "E", Check: "custom-deploy-no-confirmations", File: "a.sol", Lines: []int{5}},
+		{ID: "F", Check: "custom-optimizer-runs", File: "a.sol", Lines: []int{5}},
+	})
+	var ids []string
+	for _, f := range kept {
+		ids = append(ids, f.ID)
+	}
+	assert.Equal(t, []string{"A", "C", "E", "F"}, ids, "same SWC, or CWE without one, is a duplicate; otherwise the check decides")
+	assert.Equal(t, []parser.Warning{
+		{Kind: parser.WarningDedup, Check: "custom-reentrancy-ordering", File: "a.sol", Message: "B dropped as a duplicate of A (reentrancy-eth)"},
+		{Kind: parser.WarningDedup, Check: "custom-deploy-hardcoded-key", File: "a.sol", Message: "D dropped as a duplicate of C (custom-hardcoded-private-key)"},
+	}, dropped)
+}
+
+func TestProcess_Deterministic(t *testing.T) {
+	findings := []parser.Finding{
+		{ID: "S-2", Check: "b", Severity: parser.SeverityHigh, File: "a.sol", Lines: []int{4}},
+		{ID: "S-1", Check: "b", Severity: parser.SeverityHigh, File: "a.sol", Lines: []int{4}},
+		{ID: "S-3", Check: "a", Severity: parser.SeverityHigh, File: "a.sol", Lines: []int{4}},
+		{ID: "S-4", Check: "a", Severity: parser.SeverityHigh, File: "a.sol", Lines: []int{2}},
+		{ID: "S-5", Check: "a", Severity: parser.SeverityHigh, File: "a.sol"},
+	}
+	var orders [][]string
+	for _, reversed := range []bool{false, true} {
+		input := append([]parser.Finding{}, findings...)
+		if reversed {
+			slices.Reverse(input)
+		}
+		report := &parser.AnalysisReport{Findings: input}
+		require.NoError(t, Process(report, Options{Pipeline: []string{}}))
+		var ids []string
+		for _, f := range report.Findings {
+			ids = append(ids, f.ID)
+		}
+		orders = append(orders, ids)
+	}
+	assert.Equal(t, []string{"S-5", "S-4", "S-3", "S-1", "S-2"}, orders[0], "ties broken by line, check and ID")
+	assert.Equal(t, orders[0], orders[1], "input order does not matter")
+}
+
+func TestProcess_Triage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), triage.DefaultPath)
+	require.NoError(t, os.WriteFile(path, []byte(`[{"check": "reentrancy-eth", "id": "abc123", "elements": []}]`), 0644))
+	db, err := triage.Load(path)
+	require.NoError(t, err)
+
+	report := &parser.AnalysisReport{Findings: []parser.Finding{
+		{ID: "SLITHER-001", Source: "slither", Check: "reentrancy-eth", EngineID: "abc123", Severity: parser.SeverityHigh},
+		{ID: "SLITHER-002", Source: "slither", Check: "tx-origin", EngineID: "def456", Severity: parser.SeverityMedium},
+	}}
+	require.NoError(t, Process(report, Options{Triage: db}))
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "SLITHER-002", report.Findings[0].ID)
+	assert.Equal(t, 1, report.Triaged)
+	assert.Equal(t, 1, report.Summary.Total)
+}
+
+func TestProcess_Baseline(t *testing.T) {
+	report := &parser.AnalysisReport{Findings: []parser.Finding{
+		{ID: "CUSTOM-1", Fingerprint: "aaaa", Check: "custom-missing-access-control", Severity: parser.SeverityHigh},
+		{ID: "CUSTOM-2", Fingerprint: "bbbb", Check: "custom-missing-access-control", Severity: parser.SeverityHigh},
+	}}
+	require.NoError(t, Process(report, Options{Baseline: &baseline.Baseline{Findings: []baseline.Entry{{Fingerprint: "aaaa"}}}}))
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "CUSTOM-2", report.Findings[0].ID, "only the new finding is reported")
+	assert.Equal(t, 1, report.Baselined)
+	assert.Equal(t, 1, report.Summary.High)
+}
+
+func TestProcess_Stages(t *testing.T) {
+	findings := func() []parser.Finding {
+		return []parser.Finding{
+			{ID: "A", Check: "custom-tx-origin", Severity: parser.SeverityMedium, Confidence: "Low", File: "a.sol"},
+			{ID: "B", Check: "custom-missing-access-control", Severity: parser.SeverityHigh, Confidence: "High", File: "mocks/M.sol"},
+			{ID: "C", Check: "custom-unchecked-call", Severity: parser.SeverityLow, Confidence: "High", File: "a.sol"},
+		}
+	}
+	opts := Options{
+		MinConfidence:     "Medium",
+		MinSeverity:       parser.SeverityMedium,
+		SeverityOverrides: map[string]parser.Severity{"custom-unchecked-call": parser.SeverityHigh},
+		PathSeverities:    []PathSeverity{{Paths: []string{"mocks/**"}, Adjust: -2}},
+	}
+
+	report := &parser.AnalysisReport{Findings: findings()}
+	require.NoError(t, Process(report, opts))
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "C", report.Findings[0].ID, "A is too uncertain, B is lowered below the threshold and C raised above it")
+
+	opts.Pipeline = []string{"min-severity", "severity-overrides"}
+	report = &parser.AnalysisReport{Findings: findings()}
+	require.NoError(t, Process(report, opts))
+	var ids []string
+	for _, f := range report.Findings {
+		ids = append(ids, f.ID)
+	}
+	assert.Equal(t, []string{"B", "A"}, ids, "only the configured stages run, in order")
+}
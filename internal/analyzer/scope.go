@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// locate fills in the enclosing contract and function of every finding,
+// parsing each referenced file once, then fingerprints it.
+func locate(target string, findings []parser.Finding) {
+	root := fingerprintRoot(target)
+	files := map[string]*solidity.File{}
+	for i := range findings {
+		f := &findings[i]
+		if f.File == "" || len(f.Lines) == 0 {
+			f.Fingerprint = fingerprint(root, *f, "")
+			continue
+		}
+		src, ok := files[f.File]
+		if !ok {
+			src, _ = solidity.ParseFile(f.File)
+			files[f.File] = src
+		}
+		if src == nil {
+			f.Fingerprint = fingerprint(root, *f, "")
+			continue
+		}
+		c, fn := src.At(f.Lines[0])
+		if c != nil {
+			f.Contract = c.Name
+		}
+		if fn != nil {
+			f.Function = fn.Name
+		}
+		f.Fingerprint = fingerprint(root, *f, src.Line(f.Lines[0]))
+	}
+}
+
+// inScope keeps the findings located inside the scope's contracts and
+// functions. File-level findings (pragmas, licenses) are outside any scope.
+func inScope(findings []parser.Finding, scope *parser.Scope) []parser.Finding {
+	if scope.Empty() {
+		return findings
+	}
+	kept := findings[:0]
+	for _, f := range findings {
+		if f.Contract == "" || !inScopeFunction(scope, f.Contract, f.Function) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// inScopeFunction reports whether the scope selects function of contract.
+func inScopeFunction(scope *parser.Scope, contract, function string) bool {
+	if len(scope.Contracts) > 0 && !slices.Contains(scope.Contracts, contract) {
+		return false
+	}
+	return len(scope.Functions) == 0 || slices.Contains(scope.Functions, function)
+}
+
+// ValidateScope checks that every contract and function named by scope is
+// declared somewhere in the target, so a typo is not mistaken for a clean
+// result.
+func ValidateScope(target string, scope *parser.Scope) error {
+	if scope.Empty() {
+		return nil
+	}
+	files, err := source.SolidityFiles(target)
+	if err != nil {
+		return err
+	}
+	contracts := map[string]bool{}
+	functions := map[string]bool{}
+	for _, path := range files {
+		src, err := solidity.ParseFile(path)
+		if err != nil {
+			return err
+		}
+		for _, c := range src.Contracts {
+			contracts[c.Name] = true
+			if len(scope.Contracts) > 0 && !slices.Contains(scope.Contracts, c.Name) {
+				continue
+			}
+			for _, fn := range c.Functions {
+				functions[fn.Name] = true
+			}
+		}
+	}
+	for _, name := range scope.Contracts {
+		if !contracts[name] {
+			return fmt.Errorf("--contract %s: no such contract in %s", name, target)
+		}
+	}
+	for _, name := range scope.Functions {
+		if !functions[name] {
+			return fmt.Errorf("--function %s: no such function in the selected contracts", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// severities are the levels a severity override can set.
+var severities = []parser.Severity{
+	parser.SeverityCritical, parser.SeverityHigh, parser.SeverityMedium,
+	parser.SeverityLow, parser.SeverityInformational, parser.SeverityOptimization,
+}
+
+// ParseSeverityOverrides validates the severity_overrides of .solsec.yaml,
+// which map rule names to the severity their findings should get. Severity
+// names are case-insensitive.
+func ParseSeverityOverrides(raw map[string]string) (map[string]parser.Severity, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	rules := make([]string, 0, len(raw))
+	for rule := range raw {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	overrides := make(map[string]parser.Severity, len(raw))
+	for _, rule := range rules {
+		s, ok := parseSeverity(raw[rule])
+		if !ok {
+			names := make([]string, len(severities))
+			for i, s := range severities {
+				names[i] = string(s)
+			}
+			return nil, fmt.Errorf("severity override for %s: unknown severity %q (use %s)", rule, raw[rule], strings.Join(names, ", "))
+		}
+		overrides[rule] = s
+	}
+	return overrides, nil
+}
+
+// ParseMinSeverity validates a --min-severity level. Names are
+// case-insensitive; "" means no minimum.
+func ParseMinSeverity(name string) (parser.Severity, error) {
+	if name == "" {
+		return "", nil
+	}
+	s, ok := parseSeverity(name)
+	if !ok {
+		names := make([]string, len(severities))
+		for i, s := range severities {
+			names[i] = strings.ToLower(string(s))
+		}
+		return "", fmt.Errorf("unknown severity %q (use %s)", name, strings.Join(names, ", "))
+	}
+	return s, nil
+}
+
+// filterSeverity drops findings less severe than min. Findings whose
+// severity is not a known level are kept.
+func filterSeverity(findings []parser.Finding, min parser.Severity) []parser.Finding {
+	if min == "" {
+		return findings
+	}
+	threshold := parser.SeverityRank(min)
+	kept := findings[:0]
+	for _, f := range findings {
+		if rank := parser.SeverityRank(f.Severity); rank > threshold && rank <= parser.SeverityRank(parser.SeverityOptimization) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+func parseSeverity(name string) (parser.Severity, bool) {
+	for _, s := range severities {
+		if strings.EqualFold(name, string(s)) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// OverrideSeverities sets the severity of each finding whose rule has an
+// override, recording the change in its evidence. It runs after archetype
+// escalation, so a team's own risk matrix has the last word on each rule;
+// path_severity then adjusts by where the finding lives.
+func OverrideSeverities(findings []parser.Finding, overrides map[string]parser.Severity) {
+	for i := range findings {
+		f := &findings[i]
+		s, ok := overrides[f.Check]
+		if !ok || s == f.Severity {
+			continue
+		}
+		f.Evidence = append(f.Evidence, fmt.Sprintf("severity changed from %s to %s by severity_overrides", f.Severity, s))
+		f.Severity = s
+	}
+}
+
+// PathSeverity shifts the severity of findings in files matching one of
+// Paths, gitignore-syntax patterns relative to the working directory, by
+// Adjust levels: 1 escalates Medium to High, -1 lowers it to Low.
+type PathSeverity struct {
+	Paths  []string
+	Adjust int
+}
+
+// maxAdjust is the largest shift that can matter, from Informational to
+// Critical.
+const maxAdjust = 4
+
+// Validate checks that r has paths and a shift that can matter.
+func (r PathSeverity) Validate() error {
+	if len(r.Paths) == 0 {
+		return fmt.Errorf("no paths")
+	}
+	if slices.Contains(r.Paths, "") {
+		return fmt.Errorf("empty path pattern")
+	}
+	if r.Adjust < -maxAdjust || r.Adjust > maxAdjust {
+		return fmt.Errorf("adjust %d is out of range (use %d to %d)", r.Adjust, -maxAdjust, maxAdjust)
+	}
+	return nil
+}
+
+// ValidatePathSeverity checks the path_severity rules of .solsec.yaml.
+func ValidatePathSeverity(rules []PathSeverity) error {
+	for i, r := range rules {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("path_severity[%d] in .solsec.yaml: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// AdjustPathSeverities shifts the severity of each finding by the first
+// rule matching its file, so a narrower rule listed first (e.g.
+// contracts/core/mocks/** with adjust 0) carves an exception out of a
+// broader one. Severities stop at Critical and Informational; Optimization
+// findings are not risks and keep theirs. Changes are recorded in the
+// findings' evidence.
+func AdjustPathSeverities(findings []parser.Finding, rules []PathSeverity) {
+	if len(rules) == 0 {
+		return
+	}
+	type pattern struct {
+		glob   string
+		match  *source.Excluder
+		adjust int
+	}
+	var patterns []pattern
+	for _, r := range rules {
+		for _, p := range r.Paths {
+			patterns = append(patterns, pattern{p, source.NewExcluder([]string{p}), r.Adjust})
+		}
+	}
+	informational := parser.SeverityRank(parser.SeverityInformational)
+	for i := range findings {
+		f := &findings[i]
+		rank := parser.SeverityRank(f.Severity)
+		if f.File == "" || rank > informational {
+			continue
+		}
+		for _, p := range patterns {
+			if !p.match.Excluded(f.File) {
+				continue
+			}
+			s := severities[min(max(rank-p.adjust, 0), informational)]
+			if s != f.Severity {
+				f.Evidence = append(f.Evidence, fmt.Sprintf("severity changed from %s to %s by path_severity (%s)", f.Severity, s, p.glob))
+				f.Severity = s
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"slices"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+)
+
+// tag fills in each finding's tags from the rules bundle entry for its check.
+func tag(findings []parser.Finding) {
+	db := rules.Default()
+	for i := range findings {
+		if len(findings[i].Tags) == 0 {
+			findings[i].Tags = db.Tags(findings[i].Check)
+		}
+	}
+}
+
+// filterTags keeps the findings carrying at least one include tag (all of
+// them when include is empty) and none of the exclude tags.
+func filterTags(findings []parser.Finding, include, exclude []string) []parser.Finding {
+	if len(include) == 0 && len(exclude) == 0 {
+		return findings
+	}
+	kept := findings[:0]
+	for _, f := range findings {
+		if len(include) > 0 && !hasAnyTag(f.Tags, include) {
+			continue
+		}
+		if hasAnyTag(f.Tags, exclude) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+func hasAnyTag(tags, want []string) bool {
+	for _, t := range want {
+		if slices.Contains(tags, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package analyzer
+
+import (
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// applyTriage drops the findings recorded in opts.Triage, counting them in
+// report.Triaged.
+func applyTriage(report *parser.AnalysisReport, opts Options) error {
+	if opts.Triage == nil || opts.Triage.Len() == 0 {
+		return nil
+	}
+	kept := report.Findings[:0]
+	for _, f := range report.Findings {
+		if opts.Triage.Hidden(f) {
+			report.Triaged++
+			continue
+		}
+		kept = append(kept, f)
+	}
+	report.Findings = kept
+	return nil
+}
@@ -0,0 +1,119 @@
+// Package baseline records the findings a codebase already has when solsec
+// is adopted, so later analyses leave them out and only report, and fail CI
+// on, new ones. Findings are matched by fingerprint, which survives
+// unrelated edits and line shifts.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// DefaultPath is where "baseline create" writes and --baseline reads.
+const DefaultPath = "solsec-baseline.json"
+
+// version is the file format version, bumped on incompatible changes.
+const version = 1
+
+// Baseline is a baseline file: the accepted findings of a codebase.
+type Baseline struct {
+	Version   int     `json:"version"`
+	CreatedAt string  `json:"created_at,omitempty"`
+	Target    string  `json:"target,omitempty"`
+	Findings  []Entry `json:"findings"`
+}
+
+// Entry is one accepted finding. Only the fingerprint is matched; the rest
+// says what it is to whoever reviews the file.
+type Entry struct {
+	Fingerprint string          `json:"fingerprint"`
+	Check       string          `json:"check"`
+	Severity    parser.Severity `json:"severity"`
+	Title       string          `json:"title"`
+	File        string          `json:"file,omitempty"`
+	Line        int             `json:"line,omitempty"`
+}
+
+// New records every finding in report, clustered ones included.
+func New(report *parser.AnalysisReport) (*Baseline, error) {
+	b := &Baseline{
+		Version:   version,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Target:    report.Target,
+		Findings:  []Entry{},
+	}
+	var add func(f parser.Finding) error
+	add = func(f parser.Finding) error {
+		if f.Fingerprint == "" {
+			return fmt.Errorf("finding %s has no fingerprint; re-run the analysis to get one", f.ID)
+		}
+		e := Entry{Fingerprint: f.Fingerprint, Check: f.Check, Severity: f.Severity, Title: f.Title, File: f.File}
+		if len(f.Lines) > 0 {
+			e.Line = f.Lines[0]
+		}
+		b.Findings = append(b.Findings, e)
+		for _, r := range f.Related {
+			if err := add(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, f := range report.Findings {
+		if err := add(f); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Load reads the baseline at path.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if b.Version != version {
+		return nil, fmt.Errorf("%s: unsupported baseline version %d (want %d); re-create it with \"solsec baseline create\"", path, b.Version, version)
+	}
+	return &b, nil
+}
+
+// Write saves the baseline at path.
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Filter drops the baselined findings and returns the new ones, with how
+// many were dropped. Filtering is idempotent, so reports that are merged
+// and processed again (workspaces, shards) are not filtered twice.
+func (b *Baseline) Filter(findings []parser.Finding) (kept []parser.Finding, baselined int) {
+	accepted := make(map[string]bool, len(b.Findings))
+	for _, e := range b.Findings {
+		accepted[e.Fingerprint] = true
+	}
+	kept = findings[:0]
+	for _, f := range findings {
+		if f.Fingerprint != "" && accepted[f.Fingerprint] {
+			baselined++
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept, baselined
+}
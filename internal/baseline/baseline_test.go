@@ -0,0 +1,67 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestNew_WriteLoad(t *testing.T) {
+	report := &parser.AnalysisReport{
+		Target: "contracts",
+		Findings: []parser.Finding{
+			{ID: "C-1", Fingerprint: "aaaa", Check: "custom-missing-access-control", Severity: parser.SeverityHigh, Title: "Missing Access Control on mint()", File: "Token.sol", Lines: []int{12, 13},
+				Related: []parser.Finding{{ID: "C-2", Fingerprint: "bbbb", Check: "custom-missing-access-control", Severity: parser.SeverityHigh, File: "Token.sol", Lines: []int{20}}}},
+			{ID: "C-3", Fingerprint: "cccc", Check: "custom-missing-spdx", Severity: parser.SeverityInformational},
+		},
+	}
+	b, err := New(report)
+	require.NoError(t, err)
+	require.Len(t, b.Findings, 3, "clustered findings are recorded too")
+	assert.Equal(t, Entry{Fingerprint: "aaaa", Check: "custom-missing-access-control", Severity: parser.SeverityHigh, Title: "Missing Access Control on mint()", File: "Token.sol", Line: 12}, b.Findings[0])
+	assert.Equal(t, "bbbb", b.Findings[1].Fingerprint)
+
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	require.NoError(t, b.Write(path))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, b, loaded)
+
+	report.Findings[1].Fingerprint = ""
+	_, err = New(report)
+	assert.ErrorContains(t, err, "C-3 has no fingerprint")
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Load(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "reading baseline")
+
+	path := filepath.Join(dir, "old.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 0, "findings": []}`), 0644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "unsupported baseline version 0")
+}
+
+func TestFilter(t *testing.T) {
+	b := &Baseline{Version: version, Findings: []Entry{{Fingerprint: "aaaa"}, {Fingerprint: "cccc"}}}
+	findings := []parser.Finding{
+		{ID: "1", Fingerprint: "aaaa"},
+		{ID: "2", Fingerprint: "bbbb"},
+		{ID: "3", Fingerprint: "cccc"},
+		{ID: "4"},
+	}
+	kept, n := b.Filter(findings)
+	assert.Equal(t, 2, n)
+	require.Len(t, kept, 2)
+	assert.Equal(t, "2", kept[0].ID)
+	assert.Equal(t, "4", kept[1].ID, "findings without a fingerprint are never baselined")
+
+	kept, n = b.Filter(kept)
+	assert.Zero(t, n, "filtering again drops nothing")
+	assert.Len(t, kept, 2)
+}
@@ -0,0 +1,220 @@
+// Package benchmark measures detection rates against a corpus of known
+// vulnerable contracts annotated in the SmartBugs-curated style:
+//
+//	// <yes> <report> REENTRANCY
+//	(bool ok, ) = msg.sender.call{value: amount}("");
+//
+// The annotation marks the next code line as vulnerable in that category, so
+// a clone of https://github.com/smartbugs/smartbugs-curated (its dataset/
+// directory) can be used as-is.
+package benchmark
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// LineTolerance is how far (in lines) a finding may be from an annotation and
+// still count as detecting it; tools differ on which line of a statement
+// they report.
+const LineTolerance = 2
+
+var annotationRe = regexp.MustCompile(`<yes>\s*<report>\s*([A-Za-z_]+)`)
+
+// Vulnerability is one annotated vulnerable line.
+type Vulnerability struct {
+	File     string `json:"file"`
+	Category string `json:"category"`
+	Line     int    `json:"line"`
+}
+
+// Corpus is a set of contracts and their annotated vulnerabilities.
+type Corpus struct {
+	Root  string
+	Files []string
+	Vulns []Vulnerability
+}
+
+// LoadCorpus discovers the contracts under root and parses their annotations.
+func LoadCorpus(root string) (*Corpus, error) {
+	files, err := source.SolidityFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	c := &Corpus{Root: root, Files: files}
+	for _, f := range files {
+		vulns, err := annotations(f)
+		if err != nil {
+			return nil, err
+		}
+		c.Vulns = append(c.Vulns, vulns...)
+	}
+	return c, nil
+}
+
+// annotations returns the vulnerabilities annotated in a file. Each annotation
+// applies to the next line that is not blank or a comment.
+func annotations(path string) ([]Vulnerability, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		vulns   []Vulnerability
+		pending []string
+		lineNum int
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lineNum++
+		trimmed := strings.TrimSpace(scanner.Text())
+		if m := annotationRe.FindStringSubmatch(trimmed); m != nil {
+			pending = append(pending, strings.ToLower(m[1]))
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") || strings.HasPrefix(trimmed, "/*") {
+			continue
+		}
+		for _, cat := range pending {
+			vulns = append(vulns, Vulnerability{File: path, Category: cat, Line: lineNum})
+		}
+		pending = nil
+	}
+	return vulns, scanner.Err()
+}
+
+// checkCategories maps detector and custom check names to corpus categories.
+// It takes precedence over the SWC mapping below.
+var checkCategories = map[string]string{
+	"reentrancy-eth":                "reentrancy",
+	"reentrancy-no-eth":             "reentrancy",
+	"reentrancy-benign":             "reentrancy",
+	"reentrancy-events":             "reentrancy",
+	"reentrancy-unlimited-gas":      "reentrancy",
+	"custom-reentrancy-ordering":    "reentrancy",
+	"suicidal":                      "access_control",
+	"arbitrary-send-eth":            "access_control",
+	"tx-origin":                     "access_control",
+	"unprotected-upgrade":           "access_control",
+	"controlled-delegatecall":       "access_control",
+	"custom-missing-access-control": "access_control",
+	"custom-integer-overflow":       "arithmetic",
+	"custom-unchecked-arithmetic":   "arithmetic",
+	"divide-before-multiply":        "arithmetic",
+	"incorrect-shift":               "arithmetic",
+	"unchecked-lowlevel":            "unchecked_ll_calls",
+	"unchecked-send":                "unchecked_ll_calls",
+	"unchecked-transfer":            "unchecked_ll_calls",
+	"timestamp":                     "time_manipulation",
+	"weak-prng":                     "bad_randomness",
+	"calls-loop":                    "denial_of_service",
+	"msg-value-loop":                "denial_of_service",
+}
+
+var swcCategories = map[string]string{
+	"SWC-100": "access_control",
+	"SWC-101": "arithmetic",
+	"SWC-104": "unchecked_ll_calls",
+	"SWC-105": "access_control",
+	"SWC-106": "access_control",
+	"SWC-107": "reentrancy",
+	"SWC-112": "access_control",
+	"SWC-113": "denial_of_service",
+	"SWC-114": "front_running",
+	"SWC-115": "access_control",
+	"SWC-116": "time_manipulation",
+	"SWC-120": "bad_randomness",
+	"SWC-128": "denial_of_service",
+}
+
+// Category returns the corpus category a finding belongs to, or "".
+func Category(f parser.Finding) string {
+	if c, ok := checkCategories[f.Check]; ok {
+		return c
+	}
+	return swcCategories[f.SWCRef]
+}
+
+// CategoryScore is the detection rate for one category.
+type CategoryScore struct {
+	Category string `json:"category"`
+	Total    int    `json:"total"`
+	Detected int    `json:"detected"`
+}
+
+// Rate is the fraction of vulnerabilities detected, 0..1.
+func (s CategoryScore) Rate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Detected) / float64(s.Total)
+}
+
+// Result is the outcome of a benchmark run.
+type Result struct {
+	Corpus     string            `json:"corpus"`
+	Files      int               `json:"files"`
+	Errors     map[string]string `json:"errors,omitempty"` // file → analysis error
+	Categories []CategoryScore   `json:"categories"`
+	Overall    CategoryScore     `json:"overall"`
+	Missed     []Vulnerability   `json:"missed"`
+}
+
+// Score compares the findings for each corpus file with its annotations.
+// findings maps a corpus file path to the findings reported for it.
+func Score(c *Corpus, findings map[string][]parser.Finding) Result {
+	res := Result{Corpus: c.Root, Files: len(c.Files), Overall: CategoryScore{Category: "overall"}}
+	byCat := map[string]*CategoryScore{}
+	for _, v := range c.Vulns {
+		s, ok := byCat[v.Category]
+		if !ok {
+			s = &CategoryScore{Category: v.Category}
+			byCat[v.Category] = s
+		}
+		s.Total++
+		res.Overall.Total++
+		if detected(v, findings[v.File]) {
+			s.Detected++
+			res.Overall.Detected++
+		} else {
+			res.Missed = append(res.Missed, v)
+		}
+	}
+	for _, s := range byCat {
+		res.Categories = append(res.Categories, *s)
+	}
+	sort.Slice(res.Categories, func(i, j int) bool { return res.Categories[i].Category < res.Categories[j].Category })
+	return res
+}
+
+func detected(v Vulnerability, findings []parser.Finding) bool {
+	for _, f := range findings {
+		if Category(f) != v.Category {
+			continue
+		}
+		for _, l := range f.Lines {
+			if l >= v.Line-LineTolerance && l <= v.Line+LineTolerance {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RelPath shortens a corpus file path for display.
+func (c *Corpus) RelPath(path string) string {
+	if rel, err := filepath.Rel(c.Root, path); err == nil {
+		return rel
+	}
+	return path
+}
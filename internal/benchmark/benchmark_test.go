@@ -0,0 +1,53 @@
+package benchmark
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCorpusAndScore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Bank.sol")
+	require.NoError(t, os.WriteFile(path, []byte(`contract Bank {
+    function withdraw() external {
+        // <yes> <report> REENTRANCY
+
+        (bool ok, ) = msg.sender.call{value: 1}("");
+        // <yes> <report> ARITHMETIC
+        total = total + 1;
+    }
+}
+`), 0644))
+
+	c, err := LoadCorpus(dir)
+	require.NoError(t, err)
+	require.Equal(t, []Vulnerability{
+		{File: path, Category: "reentrancy", Line: 5},
+		{File: path, Category: "arithmetic", Line: 7},
+	}, c.Vulns)
+
+	res := Score(c, map[string][]parser.Finding{
+		path: {
+			{Check: "custom-reentrancy-ordering", Lines: []int{5, 6}},
+			{Check: "timestamp", Lines: []int{7}}, // wrong category
+		},
+	})
+	assert.Equal(t, 2, res.Overall.Total)
+	assert.Equal(t, 1, res.Overall.Detected)
+	assert.Equal(t, []CategoryScore{
+		{Category: "arithmetic", Total: 1, Detected: 0},
+		{Category: "reentrancy", Total: 1, Detected: 1},
+	}, res.Categories)
+	assert.Equal(t, []Vulnerability{{File: path, Category: "arithmetic", Line: 7}}, res.Missed)
+}
+
+func TestCategory(t *testing.T) {
+	assert.Equal(t, "reentrancy", Category(parser.Finding{Check: "reentrancy-eth"}))
+	assert.Equal(t, "bad_randomness", Category(parser.Finding{Check: "unknown", SWCRef: "SWC-120"}))
+	assert.Equal(t, "", Category(parser.Finding{Check: "naming-convention"}))
+}
@@ -0,0 +1,244 @@
+// Package cache stores parsed engine results so repeated runs on unchanged
+// sources (e.g. CI re-runs of the same commit) skip the engine subprocess.
+//
+// Entries are keyed by everything that can change the result: the engine
+// version, the detector selection, compiler settings, the target, and a
+// Merkle hash of the Solidity inputs (including the dependencies they
+// import), the build configuration and the dependency lockfiles.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// DefaultTTL is how long an entry is trusted when no TTL is configured.
+const DefaultTTL = 24 * time.Hour
+
+// configFiles are build configuration files and dependency lockfiles whose
+// contents affect compilation, hashed alongside the sources.
+var configFiles = []string{
+	"foundry.toml", "remappings.txt", "hardhat.config.js", "hardhat.config.ts",
+	"truffle-config.js", "ape-config.yaml",
+	"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "foundry.lock", "soldeer.lock", ".gitmodules",
+}
+
+// depConfigGlobs match the build configuration of Foundry dependencies,
+// whose remappings forge picks up automatically.
+var depConfigGlobs = []string{"lib/*/remappings.txt", "lib/*/foundry.toml"}
+
+// foundryRemappingsRe matches the remappings array of foundry.toml, and
+// quotedRe its entries.
+var (
+	foundryRemappingsRe = regexp.MustCompile(`(?s)\bremappings\s*=\s*\[(.*?)\]`)
+	quotedRe            = regexp.MustCompile(`["']([^"']+)["']`)
+)
+
+// Key identifies one engine run.
+type Key struct {
+	Engine        string   `json:"engine"`
+	EngineVersion string   `json:"engine_version"`
+	Detectors     []string `json:"detectors,omitempty"`
+	Exclude       []string `json:"exclude,omitempty"`
+	SolcVersion   string   `json:"solc_version,omitempty"`
+	Framework     string   `json:"framework,omitempty"`
+	FilterPaths   []string `json:"filter_paths,omitempty"`
+	Target        string   `json:"target"` // absolute; findings carry absolute paths
+	Inputs        string   `json:"inputs"` // InputsHash of the target
+}
+
+// ID is the key's stable digest, used as the entry's file name.
+func (k Key) ID() string {
+	data, _ := json.Marshal(k)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// InputsHash is the Merkle root over the target's Solidity files, the
+// files they import (dependencies under node_modules or lib/ included, for
+// single-file targets too), its project's build configuration and its
+// dependency lockfiles. Leaves are sha256(path, content) sorted by path, so
+// renames and edits both change the hash.
+func InputsHash(target string) (string, error) {
+	sources, err := source.SolidityFiles(target)
+	if err != nil {
+		return "", err
+	}
+	root := source.ProjectRoot(target)
+	files, err := solidity.ImportClosure(sources, root, remappings(root))
+	if err != nil {
+		return "", err
+	}
+	for _, name := range configFiles {
+		if p := filepath.Join(root, name); fileExists(p) {
+			files = append(files, p)
+		}
+	}
+	for _, pattern := range depConfigGlobs {
+		matches, _ := filepath.Glob(filepath.Join(root, pattern))
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	leaves := make([][]byte, 0, len(files))
+	for _, f := range files {
+		leaf, err := hashFile(root, f)
+		if err != nil {
+			return "", err
+		}
+		leaves = append(leaves, leaf)
+	}
+	return hex.EncodeToString(merkleRoot(leaves)), nil
+}
+
+// remappings returns the project's import remappings, from remappings.txt
+// and foundry.toml.
+func remappings(root string) []string {
+	var out []string
+	if data, err := os.ReadFile(filepath.Join(root, "remappings.txt")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); strings.Contains(line, "=") && !strings.HasPrefix(line, "#") {
+				out = append(out, line)
+			}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "foundry.toml")); err == nil {
+		for _, m := range foundryRemappingsRe.FindAllStringSubmatch(string(data), -1) {
+			for _, q := range quotedRe.FindAllStringSubmatch(m[1], -1) {
+				out = append(out, q[1])
+			}
+		}
+	}
+	return out
+}
+
+func hashFile(root, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	h := sha256.New()
+	h.Write([]byte(filepath.ToSlash(rel)))
+	h.Write([]byte{0})
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("hashing %s: %w", path, err)
+	}
+	return h.Sum(nil), nil
+}
+
+// merkleRoot pairs hashes level by level; an odd hash out is promoted.
+func merkleRoot(level [][]byte) []byte {
+	if len(level) == 0 {
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	}
+	for len(level) > 1 {
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			sum := sha256.Sum256(append(append([]byte{}, level[i]...), level[i+1]...))
+			next = append(next, sum[:])
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// Store is a directory of cached results.
+type Store struct {
+	Dir string
+	TTL time.Duration // zero means DefaultTTL
+}
+
+// DefaultDir is the user-level results cache.
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "solsec", "results")
+}
+
+type entry struct {
+	Key       Key              `json:"key"`
+	CreatedAt time.Time        `json:"created_at"`
+	Findings  []parser.Finding `json:"findings"`
+}
+
+// Get returns the cached findings for k, if present and fresh.
+func (s Store) Get(k Key) ([]parser.Finding, bool) {
+	data, err := os.ReadFile(s.path(k))
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if json.Unmarshal(data, &e) != nil || time.Since(e.CreatedAt) > s.ttl() {
+		return nil, false
+	}
+	return e.Findings, true
+}
+
+// Put stores findings under k. Caching is best effort; callers may ignore
+// the error.
+func (s Store) Put(k Key, findings []parser.Finding) error {
+	if findings == nil {
+		findings = []parser.Finding{}
+	}
+	data, err := json.Marshal(entry{Key: k, CreatedAt: time.Now(), Findings: findings})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(k))
+}
+
+func (s Store) path(k Key) string {
+	return filepath.Join(s.Dir, k.ID()+".json")
+}
+
+func (s Store) ttl() time.Duration {
+	if s.TTL <= 0 {
+		return DefaultTTL
+	}
+	return s.TTL
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestInputsHash(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "foundry.toml"), "[profile.default]\n")
+	writeFile(t, filepath.Join(dir, "src", "A.sol"), "contract A {}")
+	writeFile(t, filepath.Join(dir, "src", "B.sol"), "contract B {}")
+	writeFile(t, filepath.Join(dir, "src", "C.sol"), "contract C {}")
+
+	h1, err := InputsHash(dir)
+	require.NoError(t, err)
+	h2, err := InputsHash(dir)
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2, "hash is deterministic")
+
+	writeFile(t, filepath.Join(dir, "src", "B.sol"), "contract B { uint x; }")
+	h3, err := InputsHash(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, h1, h3, "content edit changes the hash")
+
+	require.NoError(t, os.Rename(filepath.Join(dir, "src", "C.sol"), filepath.Join(dir, "src", "D.sol")))
+	h4, err := InputsHash(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, h3, h4, "rename changes the hash")
+
+	writeFile(t, filepath.Join(dir, "foundry.toml"), "[profile.default]\nvia_ir = true\n")
+	h5, err := InputsHash(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, h4, h5, "build config is part of the inputs")
+}
+
+func TestInputsHash_Dependencies(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "hardhat.config.js"), "module.exports = {}")
+	writeFile(t, filepath.Join(dir, "package-lock.json"), `{"lockfileVersion": 3}`)
+	writeFile(t, filepath.Join(dir, "remappings.txt"), "solmate/=lib/solmate/src/\n")
+	target := filepath.Join(dir, "src", "Vault.sol")
+	writeFile(t, target, `import "./Base.sol";
+import "@openzeppelin/contracts/access/Ownable.sol";
+import "solmate/tokens/ERC20.sol";
+contract Vault {}`)
+	writeFile(t, filepath.Join(dir, "src", "Base.sol"), "contract Base {}")
+	writeFile(t, filepath.Join(dir, "node_modules", "@openzeppelin", "contracts", "access", "Ownable.sol"), "contract Ownable {}")
+	writeFile(t, filepath.Join(dir, "lib", "solmate", "src", "tokens", "ERC20.sol"), "contract ERC20 {}")
+	writeFile(t, filepath.Join(dir, "lib", "solmate", "remappings.txt"), "ds-test/=lib/ds-test/src/\n")
+
+	hash := func() string {
+		h, err := InputsHash(target)
+		require.NoError(t, err)
+		return h
+	}
+	prev := hash()
+	for _, edit := range []struct{ path, content, why string }{
+		{"src/Base.sol", "contract Base { uint x; }", "an imported file of a single-file target"},
+		{"node_modules/@openzeppelin/contracts/access/Ownable.sol", "contract Ownable { address owner; }", "a dependency under node_modules"},
+		{"lib/solmate/src/tokens/ERC20.sol", "contract ERC20 { uint supply; }", "a remapped dependency under lib/"},
+		{"package-lock.json", `{"lockfileVersion": 3, "packages": {}}`, "the lockfile"},
+		{"lib/solmate/remappings.txt", "ds-test/=lib/ds-test/src/\nforge-std/=lib/forge-std/src/\n", "a dependency's remappings"},
+	} {
+		writeFile(t, filepath.Join(dir, filepath.FromSlash(edit.path)), edit.content)
+		next := hash()
+		assert.NotEqual(t, prev, next, "editing %s changes the hash", edit.why)
+		prev = next
+	}
+
+	writeFile(t, filepath.Join(dir, "src", "Unrelated.sol"), "contract Unrelated {}")
+	assert.Equal(t, prev, hash(), "files the target does not import are not inputs")
+}
+
+func TestStore(t *testing.T) {
+	s := Store{Dir: t.TempDir()}
+	k := Key{Engine: "slither", EngineVersion: "0.10.4", Target: "/p", Inputs: "abc"}
+	findings := []parser.Finding{{ID: "SLITHER-1", Check: "reentrancy-eth", Lines: []int{3}}}
+
+	_, ok := s.Get(k)
+	assert.False(t, ok)
+
+	require.NoError(t, s.Put(k, findings))
+	got, ok := s.Get(k)
+	require.True(t, ok)
+	assert.Equal(t, findings, got)
+
+	other := k
+	other.EngineVersion = "0.10.5"
+	_, ok = s.Get(other)
+	assert.False(t, ok, "a different slither version misses")
+
+	other = k
+	other.Exclude = []string{"timestamp"}
+	_, ok = s.Get(other)
+	assert.False(t, ok, "a different detector set misses")
+
+	require.NoError(t, s.Put(other, nil))
+	got, ok = s.Get(other)
+	require.True(t, ok, "empty results are cached too")
+	assert.Empty(t, got)
+}
+
+func TestStore_TTL(t *testing.T) {
+	s := Store{Dir: t.TempDir(), TTL: time.Millisecond}
+	k := Key{Engine: "slither", Inputs: "abc"}
+	require.NoError(t, s.Put(k, nil))
+	time.Sleep(5 * time.Millisecond)
+	_, ok := s.Get(k)
+	assert.False(t, ok, "expired entries are ignored")
+}
+
+func TestMerkleRoot(t *testing.T) {
+	a, b, c := []byte("a"), []byte("b"), []byte("c")
+	assert.NotEqual(t, merkleRoot([][]byte{a, b}), merkleRoot([][]byte{b, a}))
+	assert.Equal(t, c, merkleRoot([][]byte{c}))
+	assert.Len(t, merkleRoot(nil), 32)
+	assert.Len(t, merkleRoot([][]byte{a, b, c}), 32)
+}
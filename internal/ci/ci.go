@@ -0,0 +1,126 @@
+// Package ci detects the CI provider solsec is running under and renders
+// findings in that provider's native annotation format.
+package ci
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// Provider is a detected CI environment.
+type Provider struct {
+	Name string // "github", "gitlab", "circleci", "jenkins" or "generic"
+
+	// Workspace is the checkout directory; reports written there can be
+	// collected as build artifacts. Empty when the provider does not say.
+	Workspace string
+}
+
+// Detect identifies the CI provider from the environment, or returns nil
+// outside CI. getenv is usually os.Getenv.
+func Detect(getenv func(string) string) *Provider {
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		return &Provider{Name: "github", Workspace: getenv("GITHUB_WORKSPACE")}
+	case getenv("GITLAB_CI") == "true":
+		return &Provider{Name: "gitlab", Workspace: getenv("CI_PROJECT_DIR")}
+	case getenv("CIRCLECI") == "true":
+		return &Provider{Name: "circleci", Workspace: getenv("CIRCLE_WORKING_DIRECTORY")}
+	case getenv("JENKINS_URL") != "":
+		return &Provider{Name: "jenkins", Workspace: getenv("WORKSPACE")}
+	case getenv("CI") == "true" || getenv("CI") == "1":
+		return &Provider{Name: "generic"}
+	}
+	return nil
+}
+
+// DisplayName is the provider's human-readable name.
+func (p Provider) DisplayName() string {
+	switch p.Name {
+	case "github":
+		return "GitHub Actions"
+	case "gitlab":
+		return "GitLab CI"
+	case "circleci":
+		return "CircleCI"
+	case "jenkins":
+		return "Jenkins"
+	}
+	return "CI"
+}
+
+// ReportPath places a report file in the workspace so artifact upload steps
+// find it regardless of the directory solsec ran from. CircleCI's working
+// directory may start with "~", which is not expanded; such paths are left
+// relative.
+func (p Provider) ReportPath(name string) string {
+	if p.Workspace == "" || strings.HasPrefix(p.Workspace, "~") {
+		return name
+	}
+	return filepath.Join(p.Workspace, name)
+}
+
+// Annotate writes one annotation per finding of Low severity or above.
+// GitHub Actions gets workflow commands (::error file=…,line=…::…), which
+// appear inline on the pull request diff; other providers get
+// compiler-style "file:line: level: message" lines, which GitLab, CircleCI
+// and the Jenkins warnings plugin all pick up from the job log.
+func (p Provider) Annotate(w io.Writer, findings []parser.Finding) {
+	for _, f := range findings {
+		level := annotationLevel(f.Severity)
+		if level == "" {
+			continue
+		}
+		file := p.relative(f.File)
+		line := 0
+		if len(f.Lines) > 0 {
+			line = f.Lines[0]
+		}
+		msg := fmt.Sprintf("[%s] %s (%s)", f.Severity, f.Title, f.Check)
+
+		if p.Name == "github" {
+			fmt.Fprintf(w, "::%s file=%s,line=%d,title=%s::%s\n",
+				level, escapeProperty(file), max(line, 1), escapeProperty("solsec: "+f.Check), escapeData(msg))
+			continue
+		}
+		if level == "notice" {
+			level = "note"
+		}
+		fmt.Fprintf(w, "%s:%d: %s: %s\n", file, line, level, msg)
+	}
+}
+
+func (p Provider) relative(file string) string {
+	if p.Workspace != "" {
+		if rel, err := filepath.Rel(p.Workspace, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+func annotationLevel(sev parser.Severity) string {
+	switch sev {
+	case parser.SeverityCritical, parser.SeverityHigh:
+		return "error"
+	case parser.SeverityMedium:
+		return "warning"
+	case parser.SeverityLow:
+		return "notice"
+	}
+	return ""
+}
+
+// escapeData and escapeProperty follow the GitHub Actions workflow command
+// escaping rules.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,63 @@
+package ci
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(k string) string { return vars[k] }
+}
+
+func TestDetect(t *testing.T) {
+	assert.Nil(t, Detect(env(nil)))
+
+	p := Detect(env(map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_WORKSPACE": "/w", "CI": "true"}))
+	require.NotNil(t, p)
+	assert.Equal(t, "github", p.Name)
+	assert.Equal(t, "/w/solsec-report.sarif", p.ReportPath("solsec-report.sarif"))
+
+	p = Detect(env(map[string]string{"GITLAB_CI": "true", "CI_PROJECT_DIR": "/builds/org/repo"}))
+	require.NotNil(t, p)
+	assert.Equal(t, "GitLab CI", p.DisplayName())
+
+	p = Detect(env(map[string]string{"CIRCLECI": "true", "CIRCLE_WORKING_DIRECTORY": "~/project"}))
+	require.NotNil(t, p)
+	assert.Equal(t, "solsec-report.html", p.ReportPath("solsec-report.html"), "unexpanded ~ is not used")
+
+	p = Detect(env(map[string]string{"JENKINS_URL": "https://ci.example.com/", "WORKSPACE": "/var/jenkins/ws"}))
+	require.NotNil(t, p)
+	assert.Equal(t, "jenkins", p.Name)
+
+	p = Detect(env(map[string]string{"CI": "true"}))
+	require.NotNil(t, p)
+	assert.Equal(t, "generic", p.Name)
+}
+
+var annotated = []parser.Finding{
+	{Check: "reentrancy-eth", Title: "Reentrancy, in withdraw", Severity: parser.SeverityHigh, File: "/w/src/Vault.sol", Lines: []int{42, 43}},
+	{Check: "timestamp", Title: "Timestamp", Severity: parser.SeverityLow, File: "/w/src/Vault.sol", Lines: []int{7}},
+	{Check: "naming-convention", Title: "Naming", Severity: parser.SeverityInformational, File: "/w/src/Vault.sol", Lines: []int{1}},
+}
+
+func TestAnnotate_GitHub(t *testing.T) {
+	var buf bytes.Buffer
+	Provider{Name: "github", Workspace: "/w"}.Annotate(&buf, annotated)
+	assert.Equal(t,
+		"::error file=src/Vault.sol,line=42,title=solsec%3A reentrancy-eth::[High] Reentrancy, in withdraw (reentrancy-eth)\n"+
+			"::notice file=src/Vault.sol,line=7,title=solsec%3A timestamp::[Low] Timestamp (timestamp)\n",
+		buf.String())
+}
+
+func TestAnnotate_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	Provider{Name: "gitlab", Workspace: "/w"}.Annotate(&buf, annotated)
+	assert.Equal(t,
+		"src/Vault.sol:42: error: [High] Reentrancy, in withdraw (reentrancy-eth)\n"+
+			"src/Vault.sol:7: note: [Low] Timestamp (timestamp)\n",
+		buf.String())
+}
@@ -0,0 +1,162 @@
+// Package config provides the typed view of solsec's configuration file
+// (.solsec.yaml), loaded through viper so flags, env vars and file share keys.
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// Config is the project configuration.
+type Config struct {
+	Workspace Workspace `mapstructure:"workspace"`
+
+	// Exclude and Checks are defaults for the --exclude and --checks flags.
+	Exclude []string `mapstructure:"exclude"`
+	Checks  []string `mapstructure:"checks"`
+
+	// ExcludePaths is the default for --exclude-paths.
+	ExcludePaths []string `mapstructure:"exclude_paths"`
+
+	// MaxFileSize (e.g. "2MB", "0" for no limit) and MaxLineLength bound
+	// the files custom checks read; see source.Limits.
+	MaxFileSize   string `mapstructure:"max_file_size"`
+	MaxLineLength *int   `mapstructure:"max_line_length"`
+
+	// SeverityOverrides maps rule names to the severity their findings get
+	// (e.g. timestamp: Low), to match a team's own risk matrix.
+	SeverityOverrides map[string]string `mapstructure:"severity_overrides"`
+
+	// PathSeverity shifts the severity of findings by the files they are in
+	// (e.g. contracts/core/** one level up); the first matching rule wins.
+	PathSeverity []PathSeverity `mapstructure:"path_severity"`
+
+	// MaxCritical, MaxHigh and MaxMedium are the defaults for --max-critical,
+	// --max-high and --max-medium: how many findings of that severity a run
+	// may report before it fails.
+	MaxCritical *int `mapstructure:"max_critical"`
+	MaxHigh     *int `mapstructure:"max_high"`
+	MaxMedium   *int `mapstructure:"max_medium"`
+
+	// Policy lists the gate rules analyze evaluates to decide its exit
+	// code; see package policy. With a policy, --fail-on defaults to none.
+	Policy []PolicyRule `mapstructure:"policy"`
+
+	// Pipeline orders the report post-processing stages (e.g. baseline,
+	// dedup, cluster); unset means analyzer.DefaultPipeline.
+	Pipeline []string `mapstructure:"pipeline"`
+
+	// Offline forbids network access, like --offline.
+	Offline bool `mapstructure:"offline"`
+
+	// Grading replaces the default A–F grade boundaries and verdicts.
+	Grading Grading `mapstructure:"grading"`
+
+	// Extends names the shared (organization) config this one inherits from;
+	// see Inherit. AllowOverride lists the keys projects may override.
+	Extends       string   `mapstructure:"extends"`
+	AllowOverride []string `mapstructure:"allow_override"`
+
+	// dir is the directory of the config file; relative paths resolve against it.
+	dir string
+}
+
+// Grading configures how risk scores map to grades and verdicts. Bands are
+// listed from best to worst; each covers the scores below its Below bound,
+// and the last one covers the rest.
+type Grading struct {
+	Bands []GradeBand `mapstructure:"bands"`
+
+	// Emoji set to false strips the emoji from verdicts.
+	Emoji *bool `mapstructure:"emoji"`
+}
+
+// GradeBand is one grade on the scale.
+type GradeBand struct {
+	Grade   string `mapstructure:"grade"`
+	Below   int    `mapstructure:"below"`
+	Verdict string `mapstructure:"verdict"` // empty keeps the default verdict for the score
+}
+
+// PolicyRule is one named gate expression, e.g. summary.critical == 0.
+type PolicyRule struct {
+	Name string `mapstructure:"name"`
+	Expr string `mapstructure:"expr"`
+}
+
+// PathSeverity adjusts the severity of findings in files matching Paths,
+// gitignore-style globs, by Adjust levels (positive escalates).
+type PathSeverity struct {
+	Paths  []string `mapstructure:"paths"`
+	Adjust int      `mapstructure:"adjust"`
+}
+
+// Workspace defines the packages of a protocol monorepo, each analyzed with
+// its own settings (e.g. core/periphery/governance).
+type Workspace struct {
+	Packages []Package `mapstructure:"packages"`
+}
+
+// Package is one named analysis unit in a workspace.
+type Package struct {
+	Name      string `mapstructure:"name"`
+	Path      string `mapstructure:"path"`
+	Framework string `mapstructure:"framework"` // foundry | hardhat | truffle | ... (passed to crytic-compile)
+	Solc      string `mapstructure:"solc"`
+	Profile   string `mapstructure:"profile"`
+	FailOn    string `mapstructure:"fail_on"` // policy: severity that fails this package
+
+	// Baseline is this package's baseline file, used instead of --baseline.
+	Baseline string `mapstructure:"baseline"`
+
+	// MaxCritical, MaxHigh and MaxMedium override the top-level max_*
+	// limits for this package, and Policy adds rules to the top-level ones.
+	MaxCritical *int         `mapstructure:"max_critical"`
+	MaxHigh     *int         `mapstructure:"max_high"`
+	MaxMedium   *int         `mapstructure:"max_medium"`
+	Policy      []PolicyRule `mapstructure:"policy"`
+}
+
+// Load decodes the configuration held by v.
+func Load(v *viper.Viper) (*Config, error) {
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+	if used := v.ConfigFileUsed(); used != "" {
+		cfg.dir = filepath.Dir(used)
+	}
+	return &cfg, nil
+}
+
+// Resolve returns path relative to the config file's directory, unless it is
+// already absolute or no config file was loaded.
+func (c *Config) Resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) || c.dir == "" {
+		return path
+	}
+	return filepath.Join(c.dir, path)
+}
+
+// Validate checks that every workspace package is named uniquely and has a path.
+func (w Workspace) Validate() error {
+	if len(w.Packages) == 0 {
+		return fmt.Errorf("no workspace packages configured (add workspace.packages to .solsec.yaml)")
+	}
+	seen := map[string]bool{}
+	for i, p := range w.Packages {
+		if p.Name == "" {
+			return fmt.Errorf("workspace package #%d has no name", i+1)
+		}
+		if p.Path == "" {
+			return fmt.Errorf("workspace package %q has no path", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("workspace package %q is defined more than once", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Workspace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".solsec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+workspace:
+  packages:
+    - name: core
+      path: contracts/core
+      solc: 0.8.24
+      fail_on: medium
+      baseline: baselines/core.json
+      max_high: 2
+      policy:
+        - name: no-critical
+          expr: summary.critical == 0
+    - name: periphery
+      path: /abs/periphery
+      framework: foundry
+`), 0644))
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+
+	cfg, err := Load(v)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Workspace.Validate())
+	require.Len(t, cfg.Workspace.Packages, 2)
+
+	core := cfg.Workspace.Packages[0]
+	assert.Equal(t, "0.8.24", core.Solc)
+	assert.Equal(t, "medium", core.FailOn)
+	assert.Equal(t, "baselines/core.json", core.Baseline)
+	require.NotNil(t, core.MaxHigh)
+	assert.Equal(t, 2, *core.MaxHigh)
+	assert.Nil(t, core.MaxMedium)
+	assert.Equal(t, []PolicyRule{{Name: "no-critical", Expr: "summary.critical == 0"}}, core.Policy)
+	assert.Equal(t, filepath.Join(dir, "contracts/core"), cfg.Resolve(core.Path))
+	assert.Equal(t, "/abs/periphery", cfg.Resolve(cfg.Workspace.Packages[1].Path))
+}
+
+func TestWorkspaceValidate(t *testing.T) {
+	assert.Error(t, Workspace{}.Validate())
+	assert.Error(t, Workspace{Packages: []Package{{Path: "x"}}}.Validate())
+	assert.Error(t, Workspace{Packages: []Package{{Name: "a"}}}.Validate())
+	assert.Error(t, Workspace{Packages: []Package{{Name: "a", Path: "x"}, {Name: "a", Path: "y"}}}.Validate())
+	assert.NoError(t, Workspace{Packages: []Package{{Name: "a", Path: "x"}}}.Validate())
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".solsec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+exclude:
+  - naming-convention
+  - solc-version
+checks: [reentrancy, access-control]
+`), 0644))
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+
+	cfg, err := Load(v)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"naming-convention", "solc-version"}, cfg.Exclude)
+	assert.Equal(t, []string{"reentrancy", "access-control"}, cfg.Checks)
+}
+
+func TestLoad_Grading(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".solsec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+grading:
+  emoji: false
+  bands:
+    - grade: A+
+      below: 1
+      verdict: No scored findings.
+    - grade: A
+      below: 10
+    - grade: F
+`), 0644))
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+
+	cfg, err := Load(v)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Grading.Emoji)
+	assert.False(t, *cfg.Grading.Emoji)
+	assert.Equal(t, []GradeBand{
+		{Grade: "A+", Below: 1, Verdict: "No scored findings."},
+		{Grade: "A", Below: 10},
+		{Grade: "F"},
+	}, cfg.Grading.Bands)
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+	"github.com/Zubimendi/solsec/internal/offline"
+)
+
+// maxExtendsDepth bounds chains of configs extending each other.
+const maxExtendsDepth = 5
+
+// httpClient downloads remote configs; tests point it at a TLS test server.
+var httpClient = http.DefaultClient
+
+// Keys that control inheritance itself. A project cannot set allow_override
+// for its own base.
+const (
+	keyExtends       = "extends"
+	keyAllowOverride = "allow_override"
+)
+
+// Inherit resolves the extends: chain of the config held by v. The base
+// (organization) config is loaded first and the local config is layered on
+// top; a local value for a top-level key the base sets only takes effect
+// when the base lists that key under allow_override. Rejected overrides are
+// returned as warnings and the base value is kept, so central policies stay
+// mandatory.
+//
+// extends may be:
+//
+//	https://example.com/org-solsec.yaml
+//	git::https://github.com/org/security-policy.git//solsec.yaml?ref=v2
+//	../shared/solsec.yaml (relative to the config file)
+//
+// Remote configs must come over https (or ssh, for git): a policy fetched
+// over plain http or git:// could be rewritten by anyone on the network
+// path.
+func Inherit(ctx context.Context, v *viper.Viper) (warnings []string, err error) {
+	ref := v.GetString(keyExtends)
+	if ref == "" {
+		return nil, nil
+	}
+	dir := "."
+	if used := v.ConfigFileUsed(); used != "" {
+		dir = filepath.Dir(used)
+	}
+
+	base, err := loadBase(ctx, ref, dir, map[string]bool{}, 1)
+	if err != nil {
+		return nil, fmt.Errorf("extends %s: %w", ref, err)
+	}
+
+	allowed := map[string]bool{}
+	for _, k := range toStrings(base[keyAllowOverride]) {
+		allowed[strings.ToLower(k)] = true
+	}
+	merged := map[string]any{}
+	for k, val := range base {
+		merged[k] = val
+	}
+	local := v.AllSettings()
+	keys := make([]string, 0, len(local))
+	for k := range local {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if k == keyExtends || k == keyAllowOverride {
+			continue
+		}
+		if _, set := base[k]; set && !allowed[k] {
+			warnings = append(warnings, fmt.Sprintf("%q is set by %s and may not be overridden locally (allow_override: %s); using the inherited value",
+				k, ref, strings.Join(toStrings(base[keyAllowOverride]), ", ")))
+			continue
+		}
+		merged[k] = local[k]
+	}
+	merged[keyExtends] = ref
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("applying inherited config: %w", err)
+	}
+	return warnings, nil
+}
+
+// loadBase fetches ref and resolves its own extends: chain. A base's
+// allow_override list is intersected with its parent's, so a team config
+// cannot loosen the organization's policy.
+func loadBase(ctx context.Context, ref, dir string, seen map[string]bool, depth int) (map[string]any, error) {
+	if depth > maxExtendsDepth {
+		return nil, fmt.Errorf("extends chain deeper than %d", maxExtendsDepth)
+	}
+	if seen[ref] {
+		return nil, fmt.Errorf("extends cycle through %s", ref)
+	}
+	seen[ref] = true
+
+	data, err := fetch(ctx, ref, dir)
+	if err != nil {
+		return nil, err
+	}
+	bv := viper.New()
+	bv.SetConfigType("yaml")
+	if err := bv.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+	cfg := bv.AllSettings()
+
+	parentRef := bv.GetString(keyExtends)
+	delete(cfg, keyExtends)
+	if parentRef == "" {
+		return cfg, nil
+	}
+	parentDir := dir
+	if isLocalRef(ref) {
+		parentDir = filepath.Dir(resolveLocal(ref, dir))
+	}
+	parent, err := loadBase(ctx, parentRef, parentDir, seen, depth+1)
+	if err != nil {
+		return nil, fmt.Errorf("extends %s: %w", parentRef, err)
+	}
+
+	parentAllowed := map[string]bool{}
+	for _, k := range toStrings(parent[keyAllowOverride]) {
+		parentAllowed[strings.ToLower(k)] = true
+	}
+	for k, val := range cfg {
+		if k == keyAllowOverride {
+			continue
+		}
+		if _, set := parent[k]; !set || parentAllowed[k] {
+			parent[k] = val
+		}
+	}
+	var allowed []string
+	for _, k := range toStrings(cfg[keyAllowOverride]) {
+		if _, set := parent[k]; !set || parentAllowed[strings.ToLower(k)] {
+			allowed = append(allowed, k)
+		}
+	}
+	if _, ok := cfg[keyAllowOverride]; ok {
+		parent[keyAllowOverride] = allowed
+	}
+	return parent, nil
+}
+
+// fetch reads the config referenced by ref. Remote references fail in
+// offline mode; vendor the shared config and extend it by path instead.
+func fetch(ctx context.Context, ref, dir string) ([]byte, error) {
+	if !isLocalRef(ref) {
+		if err := offline.Guard("fetching remote config"); err != nil {
+			return nil, fmt.Errorf("%w; vendor it and extend it by local path", err)
+		}
+	}
+	switch {
+	case strings.HasPrefix(ref, "git::"):
+		return fetchGit(ctx, strings.TrimPrefix(ref, "git::"))
+	case strings.HasPrefix(ref, "https://"):
+		return fetchHTTP(ctx, ref)
+	case strings.HasPrefix(ref, "http://"):
+		return nil, fmt.Errorf("refusing to fetch a config over plain http; use https://")
+	}
+	return os.ReadFile(resolveLocal(ref, dir))
+}
+
+func fetchHTTP(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// fetchGit shallow-clones a repository and reads one file from it. spec is
+// <repo-url>//<path>[?ref=<branch-or-tag>].
+func fetchGit(ctx context.Context, spec string) ([]byte, error) {
+	spec, gitRef, _ := strings.Cut(spec, "?ref=")
+	// Skip the scheme's "//" when looking for the path separator.
+	schemeEnd := 0
+	if i := strings.Index(spec, "://"); i >= 0 {
+		schemeEnd = i + 3
+	}
+	i := strings.Index(spec[schemeEnd:], "//")
+	if i < 0 {
+		return nil, fmt.Errorf("git reference must be <repo-url>//<path>[?ref=<ref>]")
+	}
+	repo, file := spec[:schemeEnd+i], spec[schemeEnd+i+2:]
+	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "git://") {
+		return nil, fmt.Errorf("refusing to clone %s over an unauthenticated transport; use https:// or ssh", repo)
+	}
+	if !filepath.IsLocal(filepath.FromSlash(file)) {
+		return nil, fmt.Errorf("path %q must stay inside the repository", file)
+	}
+
+	dir, err := os.MkdirTemp("", "solsec-extends-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if gitRef != "" {
+		args = append(args, "--branch", gitRef)
+	}
+	args = append(args, "--", repo, dir)
+	if out, err := exec.CommandContext(ctx, "git", args...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git clone %s: %v: %s", repo, err, strings.TrimSpace(string(out)))
+	}
+	return readInside(dir, filepath.FromSlash(file))
+}
+
+// readInside reads file relative to dir, refusing paths that resolve
+// outside dir, e.g. through a symlink committed to the repository.
+func readInside(dir, file string) ([]byte, error) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(root, file))
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(root, path); err != nil || !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("path %q resolves outside the repository", file)
+	}
+	return os.ReadFile(path)
+}
+
+func isLocalRef(ref string) bool {
+	return !strings.HasPrefix(ref, "git::") && !strings.HasPrefix(ref, "https://") && !strings.HasPrefix(ref, "http://")
+}
+
+func resolveLocal(ref, dir string) string {
+	if filepath.IsAbs(ref) {
+		return ref
+	}
+	return filepath.Join(dir, ref)
+}
+
+func toStrings(v any) []string {
+	switch s := v.(type) {
+	case []string:
+		return s
+	case []any:
+		out := make([]string, 0, len(s))
+		for _, e := range s {
+			out = append(out, fmt.Sprint(e))
+		}
+		return out
+	}
+	return nil
+}
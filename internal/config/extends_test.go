@@ -0,0 +1,181 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/offline"
+)
+
+const orgPolicy = `
+exclude:
+  - naming-convention
+checks: [reentrancy, access-control]
+allow_override: [checks]
+`
+
+func readLocal(t *testing.T, dir, content string) *viper.Viper {
+	t.Helper()
+	path := filepath.Join(dir, ".solsec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	return v
+}
+
+func TestInherit_LocalFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org.yaml"), []byte(orgPolicy), 0644))
+	v := readLocal(t, dir, `
+extends: org.yaml
+exclude: []
+checks: [reentrancy]
+workspace:
+  packages:
+    - name: core
+      path: src
+`)
+
+	warnings, err := Inherit(context.Background(), v)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], `"exclude" is set by org.yaml`)
+
+	cfg, err := Load(v)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"naming-convention"}, cfg.Exclude, "mandatory policy is kept")
+	assert.Equal(t, []string{"reentrancy"}, cfg.Checks, "allowed override applies")
+	assert.Equal(t, "org.yaml", cfg.Extends)
+	require.Len(t, cfg.Workspace.Packages, 1)
+	assert.Equal(t, filepath.Join(dir, "src"), cfg.Resolve(cfg.Workspace.Packages[0].Path))
+}
+
+func TestInherit_HTTP(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org-solsec.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(orgPolicy))
+	}))
+	defer srv.Close()
+	httpClient = srv.Client()
+	defer func() { httpClient = http.DefaultClient }()
+
+	v := readLocal(t, t.TempDir(), "extends: "+srv.URL+"/org-solsec.yaml\n")
+	warnings, err := Inherit(context.Background(), v)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	cfg, err := Load(v)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"naming-convention"}, cfg.Exclude)
+	assert.Equal(t, []string{"checks"}, cfg.AllowOverride)
+
+	v = readLocal(t, t.TempDir(), "extends: "+srv.URL+"/missing.yaml\n")
+	_, err = Inherit(context.Background(), v)
+	assert.ErrorContains(t, err, "404")
+
+	v = readLocal(t, t.TempDir(), "extends: http://example.com/org-solsec.yaml\n")
+	_, err = Inherit(context.Background(), v)
+	assert.ErrorContains(t, err, "plain http")
+}
+
+func TestInherit_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "policies"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "policies", "solsec.yaml"), []byte(orgPolicy), 0644))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "--quiet", "-m", "policy"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	v := readLocal(t, t.TempDir(), "extends: git::file://"+repo+"//policies/solsec.yaml?ref=v1\n")
+	_, err := Inherit(context.Background(), v)
+	require.NoError(t, err)
+	cfg, err := Load(v)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reentrancy", "access-control"}, cfg.Checks)
+
+	secret := filepath.Join(t.TempDir(), "secret.yaml")
+	require.NoError(t, os.WriteFile(secret, []byte(orgPolicy), 0644))
+	require.NoError(t, os.Symlink(secret, filepath.Join(repo, "link.yaml")))
+	for _, args := range [][]string{{"add", "."}, {"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "--quiet", "-m", "link"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	for spec, msg := range map[string]string{
+		"//../../etc/passwd": "must stay inside the repository",
+		"//link.yaml":        "resolves outside the repository",
+	} {
+		v := readLocal(t, t.TempDir(), "extends: git::file://"+repo+spec+"\n")
+		_, err := Inherit(context.Background(), v)
+		assert.ErrorContains(t, err, msg, spec)
+	}
+
+	_, err = Inherit(context.Background(), readLocal(t, t.TempDir(), "extends: git::http://example.com/org/policy.git//solsec.yaml\n"))
+	assert.ErrorContains(t, err, "unauthenticated transport")
+}
+
+func TestInherit_ChainCannotLoosenPolicy(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org.yaml"), []byte(orgPolicy), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "team.yaml"), []byte(`
+extends: org.yaml
+exclude: []
+allow_override: [exclude, checks]
+`), 0644))
+	v := readLocal(t, dir, "extends: team.yaml\nexclude: [solc-version]\n")
+
+	warnings, err := Inherit(context.Background(), v)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	cfg, err := Load(v)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"naming-convention"}, cfg.Exclude)
+	assert.Equal(t, []string{"checks"}, cfg.AllowOverride)
+}
+
+func TestInherit_Cycle(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("extends: b.yaml\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("extends: a.yaml\n"), 0644))
+	v := readLocal(t, dir, "extends: a.yaml\n")
+	_, err := Inherit(context.Background(), v)
+	assert.ErrorContains(t, err, "cycle")
+}
+
+func TestInherit_Offline(t *testing.T) {
+	offline.Enable()
+	defer offline.Disable()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org.yaml"), []byte(orgPolicy), 0644))
+	_, err := Inherit(context.Background(), readLocal(t, dir, "extends: org.yaml\n"))
+	assert.NoError(t, err, "local bases need no network")
+
+	v := readLocal(t, t.TempDir(), "extends: https://example.com/org-solsec.yaml\n")
+	_, err = Inherit(context.Background(), v)
+	assert.ErrorIs(t, err, offline.ErrOffline)
+	assert.ErrorContains(t, err, "extend it by local path")
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/suggest"
+)
+
+// Issue levels.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+// Issue is one problem Lint found in a configuration.
+type Issue struct {
+	Key     string `json:"key"` // e.g. workspace.packages[0].path
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// deprecatedFrameworks are crytic-compile platforms whose projects are no
+// longer maintained, with what to use instead.
+var deprecatedFrameworks = map[string]string{
+	"truffle":   "Truffle was sunset in 2023; migrate to foundry or hardhat",
+	"embark":    "Embark is no longer maintained; migrate to foundry or hardhat",
+	"etherlime": "Etherlime is no longer maintained; migrate to foundry or hardhat",
+	"dapp":      "dapptools is no longer maintained; migrate to foundry",
+}
+
+// Lint checks a config file: settings holds the keys the file itself sets
+// (before extends: is applied) and cfg the decoded, inherited result. It
+// reports keys the schema does not know, deprecated options, settings that
+// contradict each other and paths that do not resolve. Values that name
+// checks, profiles or pipeline stages are left to the caller.
+func Lint(settings map[string]any, cfg *Config) []Issue {
+	var issues []Issue
+	lintKeys("", settings, reflect.TypeOf(Config{}), &issues)
+
+	if _, ok := settings[keyAllowOverride]; ok && cfg.Extends != "" {
+		issues = append(issues, Issue{Key: keyAllowOverride, Level: LevelWarning,
+			Message: "has no effect in a config that extends another; only the base config decides what may be overridden"})
+	}
+	for _, k := range toStrings(settings[keyAllowOverride]) {
+		if _, ok := fieldByKey(reflect.TypeOf(Config{}), k); !ok {
+			issues = append(issues, unknownKey(keyAllowOverride, k, reflect.TypeOf(Config{})))
+		}
+	}
+
+	if cfg.Offline && cfg.Extends != "" && !isLocalRef(cfg.Extends) {
+		issues = append(issues, Issue{Key: keyExtends, Level: LevelError,
+			Message: fmt.Sprintf("offline: true forbids fetching %s; vendor it and extend it by local path", cfg.Extends)})
+	}
+	if cfg.MaxLineLength != nil && *cfg.MaxLineLength < 0 {
+		issues = append(issues, Issue{Key: "max_line_length", Level: LevelError, Message: "must not be negative"})
+	}
+
+	if len(cfg.Workspace.Packages) > 0 {
+		if err := cfg.Workspace.Validate(); err != nil {
+			issues = append(issues, Issue{Key: "workspace.packages", Level: LevelError, Message: err.Error()})
+		}
+	}
+	var seen []Package // earlier packages, with resolved paths
+	for i, p := range cfg.Workspace.Packages {
+		key := fmt.Sprintf("workspace.packages[%d]", i)
+		if reason, ok := deprecatedFrameworks[strings.ToLower(p.Framework)]; ok {
+			issues = append(issues, Issue{Key: key + ".framework", Level: LevelWarning,
+				Message: fmt.Sprintf("%s is deprecated: %s", p.Framework, reason)})
+		}
+		if p.Path == "" {
+			continue
+		}
+		path := filepath.Clean(cfg.Resolve(p.Path))
+		if _, err := os.Stat(path); err != nil {
+			issues = append(issues, Issue{Key: key + ".path", Level: LevelError,
+				Message: fmt.Sprintf("%s does not exist", path)})
+		}
+		for _, other := range seen {
+			if rel, err := filepath.Rel(other.Path, path); err == nil && !strings.HasPrefix(rel, "..") {
+				issues = append(issues, overlap(key, p.Name, other.Name, rel == "."))
+			} else if rel, err := filepath.Rel(path, other.Path); err == nil && !strings.HasPrefix(rel, "..") {
+				issues = append(issues, overlap(key, p.Name, other.Name, false))
+			}
+		}
+		seen = append(seen, Package{Name: p.Name, Path: path})
+	}
+	return issues
+}
+
+// overlap reports two workspace packages analyzing the same files, which are
+// then reported twice and gated by two fail_on policies.
+func overlap(key, name, other string, same bool) Issue {
+	msg := fmt.Sprintf("package %q overlaps package %q: shared files are analyzed and gated twice", name, other)
+	if same {
+		msg = fmt.Sprintf("package %q has the same path as package %q: its files are analyzed and gated twice", name, other)
+	}
+	return Issue{Key: key + ".path", Level: LevelWarning, Message: msg}
+}
+
+// lintKeys reports the keys in value that the schema type t has no field for,
+// descending into nested sections and lists of sections.
+func lintKeys(prefix string, value any, t reflect.Type, issues *[]Issue) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := value.(map[string]any)
+		if !ok {
+			return // a type mismatch, which Load reports
+		}
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			f, ok := fieldByKey(t, k)
+			if !ok {
+				*issues = append(*issues, unknownKey(prefix, k, t))
+				continue
+			}
+			lintKeys(join(prefix, k), m[k], f.Type, issues)
+		}
+	case reflect.Slice:
+		list, ok := value.([]any)
+		if !ok {
+			return
+		}
+		for i, v := range list {
+			lintKeys(fmt.Sprintf("%s[%d]", prefix, i), v, t.Elem(), issues)
+		}
+	}
+}
+
+// unknownKey reports key, set in section prefix of type t. Keys listed under
+// allow_override are reported against allow_override itself.
+func unknownKey(prefix, key string, t reflect.Type) Issue {
+	issue := Issue{Key: join(prefix, key), Level: LevelWarning, Message: "unknown key"}
+	if prefix == keyAllowOverride {
+		issue.Key, issue.Message = prefix, fmt.Sprintf("unknown key %q", key)
+	}
+	if s := suggest.Closest(key, keysOf(t)); s != "" {
+		issue.Message += fmt.Sprintf(" (did you mean %q?)", s)
+	}
+	return issue
+}
+
+// Keys returns the top-level keys of the configuration schema, in order.
+func Keys() []string {
+	return keysOf(reflect.TypeOf(Config{}))
+}
+
+// keysOf returns the mapstructure keys of struct type t.
+func keysOf(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("mapstructure"); tag != "" {
+			keys = append(keys, tag)
+		}
+	}
+	return keys
+}
+
+// fieldByKey finds the field of struct type t decoded from key. Keys match
+// case-insensitively, as they do when decoding.
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tag := f.Tag.Get("mapstructure"); tag != "" && strings.EqualFold(tag, key) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "contracts", "core"), 0755))
+	path := filepath.Join(dir, ".solsec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+chekcs: [reentrancy]
+offline: true
+extends: https://security.example.com/org-solsec.yaml
+allow_override: [exclude, pipline]
+workspace:
+  packages:
+    - name: core
+      path: contracts/core
+      framework: truffle
+    - name: all
+      path: contracts
+      solcc: 0.8.24
+    - name: gone
+      path: missing
+`), 0644))
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	cfg, err := Load(v)
+	require.NoError(t, err)
+
+	issues := Lint(v.AllSettings(), cfg)
+	assert.Equal(t, []Issue{
+		{Key: "chekcs", Level: LevelWarning, Message: `unknown key (did you mean "checks"?)`},
+		{Key: "workspace.packages[1].solcc", Level: LevelWarning, Message: `unknown key (did you mean "solc"?)`},
+		{Key: "allow_override", Level: LevelWarning, Message: "has no effect in a config that extends another; only the base config decides what may be overridden"},
+		{Key: "allow_override", Level: LevelWarning, Message: `unknown key "pipline" (did you mean "pipeline"?)`},
+		{Key: "extends", Level: LevelError, Message: "offline: true forbids fetching https://security.example.com/org-solsec.yaml; vendor it and extend it by local path"},
+		{Key: "workspace.packages[0].framework", Level: LevelWarning, Message: "truffle is deprecated: Truffle was sunset in 2023; migrate to foundry or hardhat"},
+		{Key: "workspace.packages[1].path", Level: LevelWarning, Message: `package "all" overlaps package "core": shared files are analyzed and gated twice`},
+		{Key: "workspace.packages[2].path", Level: LevelError, Message: filepath.Join(dir, "missing") + " does not exist"},
+	}, issues)
+}
+
+func TestLint_Clean(t *testing.T) {
+	cfg := &Config{Checks: []string{"reentrancy"}}
+	assert.Empty(t, Lint(map[string]any{"checks": []any{"reentrancy"}, "GRADING": map[string]any{"emoji": false}}, cfg))
+}
@@ -0,0 +1,211 @@
+// Package explain turns a single finding into an extended narrative: what
+// the pattern means, how it could be exploited against the flagged code, and
+// how to fix it step by step.
+package explain
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+)
+
+// contextLines is how many lines of code are shown around the finding.
+const contextLines = 3
+
+// maxContext caps the code shown for findings spanning a whole function.
+const maxContext = 25
+
+// minPrefix is the shortest fingerprint prefix Find accepts.
+const minPrefix = 4
+
+// Find returns the finding whose fingerprint starts with ref (at least
+// minPrefix characters) or whose ID equals ref, including clustered findings.
+func Find(report *parser.AnalysisReport, ref string) (*parser.Finding, error) {
+	var all []*parser.Finding
+	for i := range report.Findings {
+		f := &report.Findings[i]
+		all = append(all, f)
+		for j := range f.Related {
+			all = append(all, &f.Related[j])
+		}
+	}
+
+	var matches []*parser.Finding
+	for _, f := range all {
+		if f.ID == ref {
+			return f, nil
+		}
+		if len(ref) >= minPrefix && strings.HasPrefix(f.Fingerprint, ref) {
+			matches = append(matches, f)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		if len(ref) < minPrefix {
+			return nil, fmt.Errorf("no finding with ID %q (fingerprint prefixes need at least %d characters)", ref, minPrefix)
+		}
+		return nil, fmt.Errorf("no finding matches %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		var ids []string
+		for _, m := range matches {
+			ids = append(ids, m.Fingerprint+" ("+m.ID+")")
+		}
+		return nil, fmt.Errorf("%q is ambiguous: %s", ref, strings.Join(ids, ", "))
+	}
+}
+
+// Write prints the narrative for f, using db for the rule knowledge and the
+// finding's source file, when it is readable, for code context.
+func Write(w io.Writer, f *parser.Finding, db *rules.DB) {
+	rule := db.Rules[f.Check]
+	subst := placeholders(f)
+
+	fmt.Fprintf(w, "\n🔎 %s\n", f.Title)
+	fmt.Fprintf(w, "   %s · %s · %s\n", f.Severity, f.Check, f.Source)
+	if f.Fingerprint != "" {
+		fmt.Fprintf(w, "   Fingerprint: %s\n", f.Fingerprint)
+	}
+	if loc := location(f); loc != "" {
+		fmt.Fprintf(w, "   Location:    %s\n", loc)
+	}
+	var refs []string
+	for _, r := range []string{f.SWCRef, f.CWERef} {
+		if r != "" {
+			refs = append(refs, r)
+		}
+	}
+	if len(refs) > 0 {
+		fmt.Fprintf(w, "   Classified:  %s\n", strings.Join(refs, ", "))
+	}
+	if controls := append(append([]string{}, rule.EthTrust...), rule.SCSVS...); len(controls) > 0 {
+		fmt.Fprintf(w, "   Controls:    %s\n", strings.Join(controls, ", "))
+	}
+
+	fmt.Fprintf(w, "\nWhat this means\n")
+	fmt.Fprintf(w, "  %s\n", strings.TrimSpace(f.Description))
+	for _, e := range f.Evidence {
+		fmt.Fprintf(w, "  • %s\n", e)
+	}
+
+	if code := codeContext(f); code != "" {
+		fmt.Fprintf(w, "\nThe code\n%s", code)
+	}
+
+	if len(rule.Attack) > 0 {
+		fmt.Fprintf(w, "\nHow it could be exploited\n")
+		for i, step := range rule.Attack {
+			fmt.Fprintf(w, "  %d. %s\n", i+1, subst.Replace(step))
+		}
+	}
+
+	fmt.Fprintf(w, "\nHow to fix it\n")
+	switch {
+	case len(rule.Fix) > 0:
+		for i, step := range rule.Fix {
+			fmt.Fprintf(w, "  %d. %s\n", i+1, subst.Replace(step))
+		}
+	case f.Remediation != "":
+		fmt.Fprintf(w, "  %s\n", f.Remediation)
+	default:
+		fmt.Fprintf(w, "  No remediation guidance is recorded for %s; see the references below.\n", f.Check)
+	}
+
+	if len(f.Related) > 0 {
+		fmt.Fprintf(w, "\nSame root cause\n")
+		for _, r := range f.Related {
+			fmt.Fprintf(w, "  - %s\n", location(&r))
+		}
+	}
+	if len(f.References) > 0 {
+		fmt.Fprintf(w, "\nReferences\n")
+		for _, r := range f.References {
+			fmt.Fprintf(w, "  %s\n", r)
+		}
+	}
+}
+
+// WriteBrief prints what a reviewer needs to judge f: its location,
+// description, evidence and flagged code, without the rule narrative.
+func WriteBrief(w io.Writer, f *parser.Finding) {
+	fmt.Fprintf(w, "%s\n", f.Title)
+	fmt.Fprintf(w, "   %s · %s · %s\n", f.Severity, f.Check, f.Source)
+	if loc := location(f); loc != "" {
+		fmt.Fprintf(w, "   Location: %s\n", loc)
+	}
+	fmt.Fprintf(w, "\n  %s\n", strings.TrimSpace(f.Description))
+	for _, e := range f.Evidence {
+		fmt.Fprintf(w, "  • %s\n", e)
+	}
+	if code := codeContext(f); code != "" {
+		fmt.Fprintf(w, "\n%s", code)
+	}
+}
+
+// placeholders fills the rule narrative's {contract}, {function}, {file}
+// and {line} with the finding's location, or neutral wording when unknown.
+func placeholders(f *parser.Finding) *strings.Replacer {
+	contract, function, line := "the contract", "the affected function", "see location"
+	if f.Contract != "" {
+		contract = f.Contract
+	}
+	if f.Function != "" {
+		function = f.Function
+	}
+	if len(f.Lines) > 0 {
+		line = strconv.Itoa(f.Lines[0])
+	}
+	return strings.NewReplacer("{contract}", contract, "{function}", function, "{file}", f.File, "{line}", line)
+}
+
+func location(f *parser.Finding) string {
+	loc := f.File
+	if len(f.Lines) > 0 {
+		loc += ":" + strconv.Itoa(f.Lines[0])
+	}
+	if f.Contract != "" {
+		loc += " (" + f.Contract
+		if f.Function != "" {
+			loc += "." + f.Function + "()"
+		}
+		loc += ")"
+	}
+	return loc
+}
+
+// codeContext renders the flagged lines with a few lines around them,
+// marking the flagged ones. It returns "" when the file cannot be read.
+func codeContext(f *parser.Finding) string {
+	if f.File == "" || len(f.Lines) == 0 {
+		return ""
+	}
+	data, err := os.ReadFile(f.File)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(data), "\n")
+	flagged := map[int]bool{}
+	first, last := f.Lines[0], f.Lines[0]
+	for _, l := range f.Lines {
+		flagged[l] = true
+		first, last = min(first, l), max(last, l)
+	}
+	first = max(1, first-contextLines)
+	last = min(len(lines), last+contextLines, first+maxContext-1)
+
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if flagged[n] {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "  %s %4d | %s\n", marker, n, lines[n-1])
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package explain
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+)
+
+func TestFind(t *testing.T) {
+	report := &parser.AnalysisReport{Findings: []parser.Finding{
+		{ID: "A-1", Fingerprint: "abcd1111"},
+		{ID: "A-2", Fingerprint: "abcd2222", Related: []parser.Finding{{ID: "A-3", Fingerprint: "ffee0000"}}},
+	}}
+
+	f, err := Find(report, "abcd2")
+	require.NoError(t, err)
+	assert.Equal(t, "A-2", f.ID)
+
+	f, err = Find(report, "A-1")
+	require.NoError(t, err)
+	assert.Equal(t, "abcd1111", f.Fingerprint)
+
+	f, err = Find(report, "ffee")
+	require.NoError(t, err)
+	assert.Equal(t, "A-3", f.ID, "clustered findings can be explained too")
+
+	_, err = Find(report, "abcd")
+	assert.ErrorContains(t, err, "ambiguous")
+	_, err = Find(report, "ab")
+	assert.ErrorContains(t, err, "at least 4")
+	_, err = Find(report, "9999")
+	assert.Error(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Bank.sol")
+	require.NoError(t, os.WriteFile(path, []byte("contract Bank {\n    function withdraw() external {\n        msg.sender.call{value: 1}(\"\");\n    }\n}\n"), 0644))
+
+	db, err := rules.Embedded()
+	require.NoError(t, err)
+	f := &parser.Finding{
+		ID: "R-1", Fingerprint: "0123456789abcdef", Check: "reentrancy-eth", Title: "Reentrancy",
+		Severity: parser.SeverityHigh, File: path, Lines: []int{3}, Contract: "Bank", Function: "withdraw",
+	}
+	var out bytes.Buffer
+	Write(&out, f, db)
+
+	text := out.String()
+	assert.Contains(t, text, "How it could be exploited")
+	assert.Contains(t, text, "calls back into Bank.withdraw()")
+	assert.Contains(t, text, "(line 3)")
+	assert.Contains(t, text, ">    3 |         msg.sender.call")
+	assert.Contains(t, text, "How to fix it\n  1. Reorder withdraw()")
+	assert.NotContains(t, text, "{function}")
+}
+
+func TestWrite_FallsBackToRemediation(t *testing.T) {
+	db, err := rules.Embedded()
+	require.NoError(t, err)
+	f := &parser.Finding{ID: "X-1", Check: "no-such-rule", Title: "X", Remediation: "Do the thing."}
+	var out bytes.Buffer
+	Write(&out, f, db)
+
+	assert.NotContains(t, out.String(), "How it could be exploited")
+	assert.Contains(t, out.String(), "How to fix it\n  Do the thing.")
+}
@@ -0,0 +1,308 @@
+// Package explorer fetches the verified source of deployed contracts from an
+// Etherscan-compatible block explorer API.
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/offline"
+)
+
+// DefaultURL is the Etherscan API, which serves every chain it indexes from
+// one endpoint, keyed by chain ID.
+const DefaultURL = "https://api.etherscan.io/v2/api"
+
+// APIKeyEnv is the environment variable the explorer API key is read from.
+const APIKeyEnv = "ETHERSCAN_API_KEY"
+
+// Chains maps the chain names accepted by --chain to their chain IDs.
+var Chains = map[string]int64{
+	"mainnet":   1,
+	"sepolia":   11155111,
+	"holesky":   17000,
+	"optimism":  10,
+	"arbitrum":  42161,
+	"base":      8453,
+	"polygon":   137,
+	"bsc":       56,
+	"avalanche": 43114,
+	"gnosis":    100,
+	"linea":     59144,
+	"scroll":    534352,
+}
+
+// ParseChain returns the chain ID of a chain name (see Chains) or of a
+// decimal chain ID.
+func ParseChain(chain string) (int64, error) {
+	if id, ok := Chains[strings.ToLower(chain)]; ok {
+		return id, nil
+	}
+	if id, err := strconv.ParseInt(chain, 10, 64); err == nil && id > 0 {
+		return id, nil
+	}
+	names := make([]string, 0, len(Chains))
+	for name := range Chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return 0, fmt.Errorf("unknown chain %q (use a chain ID or one of %s)", chain, strings.Join(names, ", "))
+}
+
+var addressRe = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// IsAddress reports whether s is a 20-byte hex address.
+func IsAddress(s string) bool { return addressRe.MatchString(s) }
+
+// Contract describes a deployed contract whose verified source was fetched.
+type Contract struct {
+	Address  string `json:"address"`
+	ChainID  int64  `json:"chain_id"`
+	Name     string `json:"name"`
+	Compiler string `json:"compiler"`
+
+	// Implementation is the address the contract delegates to when the
+	// explorer identifies it as a proxy.
+	Implementation string `json:"implementation,omitempty"`
+
+	// Dir holds the source files, laid out by their verified paths.
+	Dir string `json:"-"`
+}
+
+// metadataFile records the Contract in its source directory.
+const metadataFile = "contract.json"
+
+// Client queries an Etherscan-compatible API.
+type Client struct {
+	URL    string // "" means DefaultURL
+	APIKey string
+	HTTP   *http.Client
+}
+
+type response struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type sourceResult struct {
+	SourceCode      string
+	ContractName    string
+	CompilerVersion string
+	Proxy           string
+	Implementation  string
+}
+
+// source fetches the verified source of address on chainID and returns the
+// contract, its files by path and its remappings.
+func (c *Client) source(ctx context.Context, chainID int64, address string) (*Contract, map[string]string, []string, error) {
+	if err := offline.Guard("querying the block explorer"); err != nil {
+		return nil, nil, nil, err
+	}
+	if c.APIKey == "" {
+		return nil, nil, nil, fmt.Errorf("no block explorer API key: set %s", APIKeyEnv)
+	}
+	endpoint := c.URL
+	if endpoint == "" {
+		endpoint = DefaultURL
+	}
+	query := url.Values{
+		"chainid": {strconv.FormatInt(chainID, 10)},
+		"module":  {"contract"},
+		"action":  {"getsourcecode"},
+		"address": {strings.ToLower(address)},
+		"apikey":  {c.APIKey},
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("creating explorer request: %w", err)
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL carries the API key; keep it out of the error.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return nil, nil, nil, fmt.Errorf("explorer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, nil, fmt.Errorf("explorer returned %s", resp.Status)
+	}
+	var out response
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<20)).Decode(&out); err != nil {
+		return nil, nil, nil, fmt.Errorf("parsing explorer response: %w", err)
+	}
+	if out.Status != "1" {
+		var reason string
+		if json.Unmarshal(out.Result, &reason) != nil || reason == "" {
+			reason = out.Message
+		}
+		return nil, nil, nil, fmt.Errorf("explorer: %s", reason)
+	}
+	var results []sourceResult
+	if err := json.Unmarshal(out.Result, &results); err != nil || len(results) == 0 {
+		return nil, nil, nil, fmt.Errorf("parsing explorer response: unexpected result")
+	}
+	r := results[0]
+	files, remappings, err := sourceFiles(r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	contract := &Contract{
+		Address:  strings.ToLower(address),
+		ChainID:  chainID,
+		Name:     r.ContractName,
+		Compiler: r.CompilerVersion,
+	}
+	if r.Proxy == "1" && IsAddress(r.Implementation) {
+		contract.Implementation = strings.ToLower(r.Implementation)
+	}
+	return contract, files, remappings, nil
+}
+
+// sourceFiles decodes the explorer's SourceCode field: a single file, a map
+// of files, or a standard JSON compiler input wrapped in an extra pair of
+// braces.
+func sourceFiles(r sourceResult) (map[string]string, []string, error) {
+	code := strings.TrimSpace(r.SourceCode)
+	if code == "" {
+		return nil, nil, fmt.Errorf("the contract's source is not verified")
+	}
+	if strings.HasPrefix(strings.ToLower(r.CompilerVersion), "vyper") {
+		return nil, nil, fmt.Errorf("%s is a Vyper contract; only Solidity is supported", r.ContractName)
+	}
+	if !strings.HasPrefix(code, "{") {
+		return map[string]string{r.ContractName + ".sol": code}, nil, nil
+	}
+	type sources map[string]struct {
+		Content string `json:"content"`
+	}
+	var input struct {
+		Sources  sources `json:"sources"`
+		Settings struct {
+			Remappings []string `json:"remappings"`
+		} `json:"settings"`
+	}
+	if strings.HasPrefix(code, "{{") {
+		if err := json.Unmarshal([]byte(code[1:len(code)-1]), &input); err != nil {
+			return nil, nil, fmt.Errorf("parsing verified source: %w", err)
+		}
+	} else if err := json.Unmarshal([]byte(code), &input.Sources); err != nil {
+		return nil, nil, fmt.Errorf("parsing verified source: %w", err)
+	}
+	if len(input.Sources) == 0 {
+		return nil, nil, fmt.Errorf("the verified source lists no files")
+	}
+	files := map[string]string{}
+	for path, s := range input.Sources {
+		files[path] = s.Content
+	}
+	return files, input.Settings.Remappings, nil
+}
+
+// Fetcher keeps verified source under Dir, one directory per chain and
+// address. Verified source never changes at an address, so source fetched
+// once is reused without querying the explorer again, even offline.
+type Fetcher struct {
+	Client *Client
+	Dir    string
+}
+
+// DefaultDir is the user-level source cache.
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "solsec", "sources")
+}
+
+// Fetch returns the verified source of address on chainID, downloading it
+// unless it is already in f.Dir.
+func (f *Fetcher) Fetch(ctx context.Context, chainID int64, address string) (*Contract, error) {
+	if !IsAddress(address) {
+		return nil, fmt.Errorf("%q is not an address", address)
+	}
+	dir := filepath.Join(f.Dir, strconv.FormatInt(chainID, 10), strings.ToLower(address))
+	if data, err := os.ReadFile(filepath.Join(dir, metadataFile)); err == nil {
+		var c Contract
+		if err := json.Unmarshal(data, &c); err == nil {
+			c.Dir = dir
+			return &c, nil
+		}
+	}
+
+	contract, files, remappings, err := f.Client.source(ctx, chainID, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0750); err != nil {
+		return nil, fmt.Errorf("creating source directory: %w", err)
+	}
+	// Write into a temporary directory and move it into place, so an
+	// interrupted download is never mistaken for complete source.
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".fetch-")
+	if err != nil {
+		return nil, fmt.Errorf("creating source directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	if err := writeSource(tmp, contract, files, remappings); err != nil {
+		return nil, err
+	}
+	_ = os.RemoveAll(dir)
+	if err := os.Rename(tmp, dir); err != nil {
+		return nil, fmt.Errorf("storing verified source: %w", err)
+	}
+	contract.Dir = dir
+	return contract, nil
+}
+
+// writeSource writes the files, their remappings and the contract's
+// metadata into dir. Paths come from whoever verified the contract, so any
+// that would land outside dir are rejected.
+func writeSource(dir string, contract *Contract, files map[string]string, remappings []string) error {
+	for path, content := range files {
+		rel := filepath.FromSlash(strings.TrimLeft(path, "/"))
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("verified source path %q leaves the source directory", path)
+		}
+		dest := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+		if err := os.WriteFile(dest, []byte(content), 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+	if len(remappings) > 0 {
+		data := strings.Join(remappings, "\n") + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "remappings.txt"), []byte(data), 0644); err != nil {
+			return fmt.Errorf("writing remappings: %w", err)
+		}
+	}
+	data, err := json.MarshalIndent(contract, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, metadataFile), data, 0644)
+}
@@ -0,0 +1,132 @@
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/offline"
+)
+
+const (
+	tokenAddr = "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+	implAddr  = "0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f"
+)
+
+// explorerServer serves getsourcecode responses from results, keyed by
+// lowercase address, and counts the requests.
+func explorerServer(t *testing.T, results map[string]sourceResult) (*httptest.Server, *int) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		assert.Equal(t, "1", q.Get("chainid"))
+		assert.Equal(t, "getsourcecode", q.Get("action"))
+		if q.Get("apikey") != "key" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": "0", "message": "NOTOK", "result": "Invalid API Key"})
+			return
+		}
+		res, ok := results[q.Get("address")]
+		if !ok {
+			res = sourceResult{ContractName: ""}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "1", "message": "OK", "result": []sourceResult{res}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestFetch(t *testing.T) {
+	input, err := json.Marshal(map[string]any{
+		"language": "Solidity",
+		"sources": map[string]any{
+			"src/Token.sol": map[string]string{"content": `import "@openzeppelin/contracts/token/ERC20/ERC20.sol";`},
+			"@openzeppelin/contracts/token/ERC20/ERC20.sol": map[string]string{"content": "contract ERC20 {}"},
+		},
+		"settings": map[string]any{"remappings": []string{"@openzeppelin/=lib/openzeppelin-contracts/"}},
+	})
+	require.NoError(t, err)
+	srv, requests := explorerServer(t, map[string]sourceResult{
+		"0x6b175474e89094c44da98b954eedeac495271d0f": {
+			SourceCode: "{" + string(input) + "}", ContractName: "Token", CompilerVersion: "v0.8.20+commit.a1b79de6",
+			Proxy: "1", Implementation: implAddr,
+		},
+	})
+	f := Fetcher{Client: &Client{URL: srv.URL, APIKey: "key"}, Dir: t.TempDir()}
+
+	c, err := f.Fetch(context.Background(), 1, tokenAddr)
+	require.NoError(t, err)
+	assert.Equal(t, "Token", c.Name)
+	assert.Equal(t, "0x6b175474e89094c44da98b954eedeac495271d0f", c.Address)
+	assert.Equal(t, implAddr, c.Implementation, "proxies name their implementation")
+	assert.Equal(t, filepath.Join(f.Dir, "1", c.Address), c.Dir)
+	data, err := os.ReadFile(filepath.Join(c.Dir, "@openzeppelin/contracts/token/ERC20/ERC20.sol"))
+	require.NoError(t, err)
+	assert.Equal(t, "contract ERC20 {}", string(data))
+	assert.FileExists(t, filepath.Join(c.Dir, "src", "Token.sol"))
+	data, err = os.ReadFile(filepath.Join(c.Dir, "remappings.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "@openzeppelin/=lib/openzeppelin-contracts/\n", string(data))
+
+	offline.Enable()
+	defer offline.Disable()
+	cached, err := f.Fetch(context.Background(), 1, tokenAddr)
+	require.NoError(t, err, "fetched source is reused, even offline")
+	assert.Equal(t, c, cached)
+	assert.Equal(t, 1, *requests)
+
+	_, err = f.Fetch(context.Background(), 1, implAddr)
+	assert.ErrorIs(t, err, offline.ErrOffline)
+}
+
+func TestFetch_Errors(t *testing.T) {
+	srv, _ := explorerServer(t, map[string]sourceResult{
+		"0x6b175474e89094c44da98b954eedeac495271d0f": {
+			SourceCode:   `{"../../evil.sol": {"content": "contract Evil {}"}}`,
+			ContractName: "Evil",
+		},
+		implAddr: {SourceCode: "contract Flat {}", ContractName: "Flat"},
+	})
+	dir := t.TempDir()
+	fetch := func(key, address string) (*Contract, error) {
+		f := Fetcher{Client: &Client{URL: srv.URL, APIKey: key}, Dir: dir}
+		return f.Fetch(context.Background(), 1, address)
+	}
+
+	_, err := fetch("key", tokenAddr)
+	assert.ErrorContains(t, err, `"../../evil.sol" leaves the source directory`)
+	assert.NoDirExists(t, filepath.Join(dir, "1", "0x6b175474e89094c44da98b954eedeac495271d0f"), "nothing is kept from a rejected fetch")
+
+	_, err = fetch("key", "0x0000000000000000000000000000000000000001")
+	assert.EqualError(t, err, "the contract's source is not verified")
+
+	_, err = fetch("wrong", implAddr)
+	assert.EqualError(t, err, "explorer: Invalid API Key")
+
+	_, err = fetch("", implAddr)
+	assert.EqualError(t, err, "no block explorer API key: set ETHERSCAN_API_KEY")
+
+	_, err = fetch("key", "Token")
+	assert.EqualError(t, err, `"Token" is not an address`)
+
+	c, err := fetch("key", implAddr)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(c.Dir, "Flat.sol"), "single-file source is named after the contract")
+}
+
+func TestParseChain(t *testing.T) {
+	id, err := ParseChain("Mainnet")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+	id, err = ParseChain("8453")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8453), id)
+	_, err = ParseChain("ethereum")
+	assert.ErrorContains(t, err, `unknown chain "ethereum" (use a chain ID or one of arbitrum, avalanche,`)
+}
@@ -0,0 +1,92 @@
+// Package history records the outcome of each analysis run in a JSON Lines
+// store so reports can show severity trends and score deltas.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// DefaultPath is the conventional project-local history store.
+const DefaultPath = ".solsec/history.jsonl"
+
+// MaxPoints bounds how many runs are embedded in a report's trend.
+const MaxPoints = 30
+
+// Exists reports whether a history store is present at path.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Load reads all recorded runs, oldest first. A missing store is empty.
+func Load(path string) ([]parser.TrendPoint, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+	defer f.Close()
+
+	var points []parser.TrendPoint
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var p parser.TrendPoint
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			return nil, fmt.Errorf("parsing history %s: %w", path, err)
+		}
+		points = append(points, p)
+	}
+	return points, scanner.Err()
+}
+
+// Append records one run at the end of the store, creating it if needed.
+func Append(path string, p parser.TrendPoint) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("creating history directory: %w", err)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encoding history entry: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Trend builds the trend for current from previous runs of the same target,
+// keeping at most MaxPoints points.
+func Trend(previous []parser.TrendPoint, current parser.TrendPoint) *parser.Trend {
+	var points []parser.TrendPoint
+	for _, p := range previous {
+		if p.Target == current.Target {
+			points = append(points, p)
+		}
+	}
+	t := &parser.Trend{}
+	if len(points) > 0 {
+		t.ScoreDelta = current.Score - points[len(points)-1].Score
+	}
+	points = append(points, current)
+	if len(points) > MaxPoints {
+		points = points[len(points)-MaxPoints:]
+	}
+	t.Points = points
+	return t
+}
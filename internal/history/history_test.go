@@ -0,0 +1,46 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".solsec", "history.jsonl")
+	assert.False(t, Exists(path))
+
+	points, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, points)
+
+	require.NoError(t, Append(path, parser.TrendPoint{Target: "a", Score: 40}))
+	require.NoError(t, Append(path, parser.TrendPoint{Target: "a", Score: 20}))
+	assert.True(t, Exists(path))
+
+	points, err = Load(path)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.Equal(t, 20, points[1].Score)
+}
+
+func TestTrend(t *testing.T) {
+	previous := []parser.TrendPoint{
+		{Target: "a", Score: 40},
+		{Target: "other", Score: 90},
+		{Target: "a", Score: 20},
+	}
+	trend := Trend(previous, parser.TrendPoint{Target: "a", Score: 63})
+
+	assert.Equal(t, 43, trend.ScoreDelta)
+	require.Len(t, trend.Points, 3)
+	assert.Equal(t, 63, trend.Points[2].Score)
+
+	first := Trend(nil, parser.TrendPoint{Target: "a", Score: 10})
+	assert.Equal(t, 0, first.ScoreDelta)
+	assert.Len(t, first.Points, 1)
+}
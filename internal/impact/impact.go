@@ -0,0 +1,123 @@
+package impact
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// nativeDecimals is the precision of every EVM chain's native currency.
+const nativeDecimals = 18
+
+// Token is an ERC-20 whose balance counts towards the value at risk.
+type Token struct {
+	Symbol  string
+	Address string
+}
+
+// Assessor values the holdings of deployed contracts.
+type Assessor struct {
+	Client *Client
+	Native string // symbol of the chain's native currency, e.g. "ETH"
+	Tokens []Token
+	// Prices maps asset symbols to their price in Currency. Unpriced assets
+	// are listed but not valued. Without any price, value is counted in the
+	// native currency alone.
+	Prices   map[string]float64
+	Currency string
+
+	decimals map[string]int
+}
+
+// Assess reads the balances of the contract at address and values them.
+func (a *Assessor) Assess(ctx context.Context, address string) (*parser.Impact, error) {
+	prices, currency := a.Prices, a.Currency
+	if len(prices) == 0 {
+		prices, currency = map[string]float64{a.Native: 1}, a.Native
+	}
+	impact := &parser.Impact{Address: address, Currency: currency}
+	add := func(asset string, amount *big.Int, decimals int) {
+		units := toUnits(amount, decimals)
+		h := parser.Holding{Asset: asset, Amount: formatUnits(amount, decimals)}
+		if price, ok := prices[asset]; ok {
+			v, _ := new(big.Float).Mul(units, big.NewFloat(price)).Float64()
+			h.Value = v
+			impact.Value += v
+		}
+		impact.Holdings = append(impact.Holdings, h)
+	}
+
+	balance, err := a.Client.Balance(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("balance of %s: %w", address, err)
+	}
+	add(a.Native, balance, nativeDecimals)
+	for _, t := range a.Tokens {
+		decimals, err := a.tokenDecimals(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := a.Client.TokenBalance(ctx, t.Address, address)
+		if err != nil {
+			return nil, fmt.Errorf("%s balance of %s: %w", t.Symbol, address, err)
+		}
+		if amount.Sign() > 0 {
+			add(t.Symbol, amount, decimals)
+		}
+	}
+	return impact, nil
+}
+
+func (a *Assessor) tokenDecimals(ctx context.Context, t Token) (int, error) {
+	if d, ok := a.decimals[t.Address]; ok {
+		return d, nil
+	}
+	d, err := a.Client.Decimals(ctx, t.Address)
+	if err != nil {
+		return 0, fmt.Errorf("decimals of %s: %w", t.Symbol, err)
+	}
+	if a.decimals == nil {
+		a.decimals = map[string]int{}
+	}
+	a.decimals[t.Address] = d
+	return d, nil
+}
+
+// Sort orders findings by the value at risk, highest first. Findings without
+// an impact come last; ties keep their order, which is most severe first in
+// a report.
+func Sort(findings []parser.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Impact, findings[j].Impact
+		if (a == nil) != (b == nil) {
+			return b == nil
+		}
+		return a != nil && a.Value > b.Value
+	})
+}
+
+func toUnits(amount *big.Int, decimals int) *big.Float {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(scale))
+}
+
+// formatUnits renders amount with its decimals as an exact decimal, without
+// trailing zeros: 1500000 with 6 decimals is "1.5".
+func formatUnits(amount *big.Int, decimals int) string {
+	s := amount.String()
+	if decimals == 0 {
+		return s
+	}
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
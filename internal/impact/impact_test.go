@@ -0,0 +1,130 @@
+package impact
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/offline"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+const (
+	vault = "0x1111111111111111111111111111111111111111"
+	usdc  = "0x2222222222222222222222222222222222222222"
+)
+
+// fakeNode answers eth_getBalance with 2.5 ether and USDC's balanceOf and
+// decimals with 1500.25 USDC and 6, recording the block tags it was asked.
+func fakeNode(t *testing.T, blocks *[]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var block string
+		_ = json.Unmarshal(req.Params[len(req.Params)-1], &block)
+		*blocks = append(*blocks, block)
+
+		result := "0x"
+		switch req.Method {
+		case "eth_getBalance":
+			result = "0x22b1c8c1227a0000" // 2.5e18
+		case "eth_call":
+			var call struct{ To, Data string }
+			_ = json.Unmarshal(req.Params[0], &call)
+			switch {
+			case call.To != usdc:
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+				return
+			case call.Data == decimalsSelector:
+				result = "0x" + strings.Repeat("0", 63) + "6"
+			case strings.HasPrefix(call.Data, balanceOfSelector):
+				assert.True(t, strings.HasSuffix(call.Data, strings.TrimPrefix(vault, "0x")))
+				result = "0x00000000000000000000000000000000000000000000000000000000596bff90" // 1500.25e6
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAssess(t *testing.T) {
+	var blocks []string
+	srv := fakeNode(t, &blocks)
+	a := &Assessor{
+		Client:   &Client{URL: srv.URL, Block: 19000000},
+		Native:   "ETH",
+		Tokens:   []Token{{Symbol: "USDC", Address: usdc}},
+		Prices:   map[string]float64{"ETH": 2000, "USDC": 1},
+		Currency: "USD",
+	}
+	impact, err := a.Assess(context.Background(), vault)
+	require.NoError(t, err)
+	assert.Equal(t, vault, impact.Address)
+	assert.Equal(t, "USD", impact.Currency)
+	assert.Equal(t, []parser.Holding{
+		{Asset: "ETH", Amount: "2.5", Value: 5000},
+		{Asset: "USDC", Amount: "1500.25", Value: 1500.25},
+	}, impact.Holdings)
+	assert.InDelta(t, 6500.25, impact.Value, 1e-9)
+	assert.Contains(t, blocks, "0x121eac0")
+
+	// Without prices, value is counted in the native currency.
+	a.Prices = nil
+	impact, err = a.Assess(context.Background(), vault)
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", impact.Currency)
+	assert.InDelta(t, 2.5, impact.Value, 1e-9)
+	assert.Zero(t, impact.Holdings[1].Value, "USDC is unpriced")
+
+	a.Tokens = []Token{{Symbol: "BAD", Address: vault}}
+	_, err = a.Assess(context.Background(), vault)
+	assert.ErrorContains(t, err, "decimals of BAD: eth_call: execution reverted")
+}
+
+func TestAssess_Offline(t *testing.T) {
+	offline.Enable()
+	defer offline.Disable()
+	a := &Assessor{Client: &Client{URL: "http://127.0.0.1:1"}, Native: "ETH"}
+	_, err := a.Assess(context.Background(), vault)
+	assert.ErrorIs(t, err, offline.ErrOffline)
+}
+
+func TestSort(t *testing.T) {
+	findings := []parser.Finding{
+		{ID: "A", Severity: parser.SeverityHigh},
+		{ID: "B", Severity: parser.SeverityMedium, Impact: &parser.Impact{Value: 10}},
+		{ID: "C", Severity: parser.SeverityLow, Impact: &parser.Impact{Value: 500}},
+		{ID: "D", Severity: parser.SeverityLow, Impact: &parser.Impact{Value: 10}},
+	}
+	Sort(findings)
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.ID)
+	}
+	assert.Equal(t, []string{"C", "B", "D", "A"}, ids)
+}
+
+func TestFormatUnits(t *testing.T) {
+	for want, in := range map[string]struct {
+		amount   int64
+		decimals int
+	}{
+		"1.5":      {1500000, 6},
+		"0.000001": {1, 6},
+		"0":        {0, 18},
+		"42":       {42, 0},
+		"3":        {3000, 3},
+	} {
+		assert.Equal(t, want, formatUnits(big.NewInt(in.amount), in.decimals))
+	}
+}
@@ -0,0 +1,158 @@
+// Package impact estimates what an exploit of a deployed contract could
+// take: the native currency and ERC-20 tokens it holds, read over JSON-RPC.
+package impact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/offline"
+)
+
+// Selectors of the ERC-20 views queried.
+const (
+	balanceOfSelector = "0x70a08231" // balanceOf(address)
+	decimalsSelector  = "0x313ce567" // decimals()
+)
+
+// Client queries an Ethereum JSON-RPC endpoint.
+type Client struct {
+	URL   string
+	Block uint64 // block to query; 0 queries the latest
+	HTTP  *http.Client
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Result string `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Balance returns the native balance of address, in wei.
+func (c *Client) Balance(ctx context.Context, address string) (*big.Int, error) {
+	result, err := c.call(ctx, "eth_getBalance", address, c.blockTag())
+	if err != nil {
+		return nil, err
+	}
+	return parseQuantity(result)
+}
+
+// TokenBalance returns the balance of holder in the ERC-20 token, in its
+// smallest unit.
+func (c *Client) TokenBalance(ctx context.Context, token, holder string) (*big.Int, error) {
+	data := balanceOfSelector + fmt.Sprintf("%064s", strings.ToLower(strings.TrimPrefix(holder, "0x")))
+	result, err := c.call(ctx, "eth_call", map[string]string{"to": token, "data": data}, c.blockTag())
+	if err != nil {
+		return nil, err
+	}
+	return parseQuantity(result)
+}
+
+// Decimals returns the number of decimals of the ERC-20 token.
+func (c *Client) Decimals(ctx context.Context, token string) (int, error) {
+	result, err := c.call(ctx, "eth_call", map[string]string{"to": token, "data": decimalsSelector}, c.blockTag())
+	if err != nil {
+		return 0, err
+	}
+	d, err := parseQuantity(result)
+	if err != nil {
+		return 0, err
+	}
+	if !d.IsInt64() || d.Int64() > 255 {
+		return 0, fmt.Errorf("token %s reports %s decimals", token, d)
+	}
+	return int(d.Int64()), nil
+}
+
+// Code returns the runtime bytecode at address, "0x" when it has none.
+func (c *Client) Code(ctx context.Context, address string) (string, error) {
+	return c.call(ctx, "eth_getCode", address, c.blockTag())
+}
+
+// StorageAt returns the 32-byte word in storage slot of address.
+func (c *Client) StorageAt(ctx context.Context, address, slot string) (string, error) {
+	return c.call(ctx, "eth_getStorageAt", address, slot, c.blockTag())
+}
+
+// Call runs a read-only call of data (selector and encoded arguments)
+// against to and returns the raw result.
+func (c *Client) Call(ctx context.Context, to, data string) (string, error) {
+	return c.call(ctx, "eth_call", map[string]string{"to": to, "data": data}, c.blockTag())
+}
+
+func (c *Client) blockTag() string {
+	if c.Block == 0 {
+		return "latest"
+	}
+	return fmt.Sprintf("0x%x", c.Block)
+}
+
+func (c *Client) call(ctx context.Context, method string, params ...any) (string, error) {
+	if err := offline.Guard("querying the chain over RPC"); err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating RPC request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL often embeds an API key; keep it out of the error.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return "", fmt.Errorf("%s: RPC request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: RPC endpoint returned %s", method, resp.Status)
+	}
+	var out rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("%s: parsing RPC response: %w", method, err)
+	}
+	if out.Error != nil {
+		return "", fmt.Errorf("%s: %s (code %d)", method, out.Error.Message, out.Error.Code)
+	}
+	return out.Result, nil
+}
+
+// parseQuantity decodes a hex quantity or a 32-byte eth_call word.
+func parseQuantity(s string) (*big.Int, error) {
+	digits := strings.TrimPrefix(s, "0x")
+	if digits == "" {
+		// eth_call on an address without code returns "0x".
+		return nil, fmt.Errorf("empty RPC result: not a contract?")
+	}
+	n, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid RPC quantity %q", s)
+	}
+	return n, nil
+}
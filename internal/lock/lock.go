@@ -0,0 +1,99 @@
+// Package lock reads and writes solsec.lock, which pins the toolchain an
+// analysis runs with (solsec, rules database, Slither and its detectors,
+// solc) so audit evidence can be reproduced.
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// DefaultPath is the conventional project-local lock file.
+const DefaultPath = "solsec.lock"
+
+// formatVersion is bumped when the lock file layout changes.
+const formatVersion = 1
+
+type file struct {
+	Version int `json:"lock_version"`
+	parser.Environment
+}
+
+// Load reads the environment pinned at path.
+func Load(path string) (*parser.Environment, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%s not found; record one with --write-lock", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading lock file: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if f.Version != formatVersion {
+		return nil, fmt.Errorf("%s: unsupported lock_version %d (this solsec writes %d)", path, f.Version, formatVersion)
+	}
+	return &f.Environment, nil
+}
+
+// Write pins env at path.
+func Write(path string, env *parser.Environment) error {
+	data, err := json.MarshalIndent(file{Version: formatVersion, Environment: *env}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling lock file: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Diff describes every way current differs from locked, one line each. It
+// is empty when the environments match.
+func Diff(locked, current *parser.Environment) []string {
+	var diffs []string
+	field := func(name, want, got string) {
+		if want != got {
+			diffs = append(diffs, fmt.Sprintf("%s: locked %s, found %s", name, orNone(want), orNone(got)))
+		}
+	}
+	field("solsec", locked.Solsec, current.Solsec)
+	field("rules database", fmt.Sprintf("v%d", locked.RulesDB), fmt.Sprintf("v%d", current.RulesDB))
+	field("slither", locked.Slither, current.Slither)
+	field("solc", locked.Solc, current.Solc)
+
+	var added, removed []string
+	for _, d := range current.Detectors {
+		if !slices.Contains(locked.Detectors, d) {
+			added = append(added, d)
+		}
+	}
+	for _, d := range locked.Detectors {
+		if !slices.Contains(current.Detectors, d) {
+			removed = append(removed, d)
+		}
+	}
+	if len(added) > 0 {
+		diffs = append(diffs, "detectors not in the lock: "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		diffs = append(diffs, "locked detectors missing: "+strings.Join(removed, ", "))
+	}
+	return diffs
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
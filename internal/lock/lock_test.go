@@ -0,0 +1,45 @@
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	env := &parser.Environment{Solsec: "1.0.0", RulesDB: 4, Slither: "0.10.4", Detectors: []string{"reentrancy-eth", "tx-origin"}, Solc: "0.8.24"}
+	require.NoError(t, Write(path, env))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, env, loaded)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Load(filepath.Join(dir, "missing.lock"))
+	assert.ErrorContains(t, err, "--write-lock")
+
+	path := filepath.Join(dir, DefaultPath)
+	require.NoError(t, os.WriteFile(path, []byte(`{"lock_version": 99}`), 0644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "unsupported lock_version 99")
+}
+
+func TestDiff(t *testing.T) {
+	locked := &parser.Environment{Solsec: "1.0.0", RulesDB: 4, Slither: "0.10.4", Detectors: []string{"reentrancy-eth", "tx-origin"}, Solc: "0.8.24"}
+	assert.Empty(t, Diff(locked, locked))
+
+	current := &parser.Environment{Solsec: "1.0.0", RulesDB: 5, Slither: "0.10.4", Detectors: []string{"reentrancy-eth", "weak-prng"}}
+	assert.Equal(t, []string{
+		"rules database: locked v4, found v5",
+		"solc: locked 0.8.24, found none",
+		"detectors not in the lock: weak-prng",
+		"locked detectors missing: tx-origin",
+	}, Diff(locked, current))
+}
@@ -0,0 +1,217 @@
+// Package noise measures how much of a report each detector is responsible
+// for and suggests .solsec.yaml snippets that tune the noisiest ones out.
+//
+// A detector is over budget when it produces at least MinFindings findings
+// and at least MaxShare of the report. Noise concentrated in test, mock and
+// vendored code is addressed by narrowing the analyzed paths instead of
+// disabling the detector.
+package noise
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// Default budget thresholds.
+const (
+	DefaultMinFindings = 10
+	DefaultMaxShare    = 0.25
+)
+
+// nonProductionDirs are directory names whose findings rarely matter for a
+// deployment: tests, mocks, scripts and vendored dependencies.
+var nonProductionDirs = map[string]bool{
+	"test": true, "tests": true, "mock": true, "mocks": true,
+	"script": true, "scripts": true, "lib": true, "node_modules": true,
+}
+
+// Options tunes the budget.
+type Options struct {
+	MinFindings int     // findings a detector needs before it can be over budget
+	MaxShare    float64 // share of all findings (0..1) above which a detector is over budget
+}
+
+// Detector is one detector's contribution to the report.
+type Detector struct {
+	Check         string  `json:"check"`
+	Source        string  `json:"source"`
+	Count         int     `json:"count"`
+	Share         float64 `json:"share"`
+	NonProduction int     `json:"non_production"` // findings in test/mock/vendored paths
+	OverBudget    bool    `json:"over_budget"`
+}
+
+// Suggestion is one tuning recommendation with a ready-to-paste snippet.
+type Suggestion struct {
+	Reason  string `json:"reason"`
+	Snippet string `json:"snippet"`
+}
+
+// Budget is the noise report for one analysis.
+type Budget struct {
+	Total       int          `json:"total"`
+	Detectors   []Detector   `json:"detectors"` // noisiest first
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// Analyze computes the noise budget for findings reported on target.
+func Analyze(target string, findings []parser.Finding, opts Options) Budget {
+	if opts.MinFindings <= 0 {
+		opts.MinFindings = DefaultMinFindings
+	}
+	if opts.MaxShare <= 0 {
+		opts.MaxShare = DefaultMaxShare
+	}
+
+	// Each occurrence of a clustered finding counts towards its detector.
+	var all []parser.Finding
+	for _, f := range findings {
+		all = append(all, f)
+		all = append(all, f.Related...)
+	}
+	findings = all
+
+	b := Budget{Total: len(findings)}
+	byCheck := map[string]*Detector{}
+	nonProd := 0
+	prodDirs := map[string]bool{}
+	for _, f := range findings {
+		d, ok := byCheck[f.Check]
+		if !ok {
+			d = &Detector{Check: f.Check, Source: f.Source}
+			byCheck[f.Check] = d
+		}
+		d.Count++
+		if dir, np := topDir(target, f.File); np {
+			d.NonProduction++
+			nonProd++
+		} else if dir != "" {
+			prodDirs[dir] = true
+		}
+	}
+	for _, d := range byCheck {
+		d.Share = float64(d.Count) / float64(b.Total)
+		d.OverBudget = d.Count >= opts.MinFindings && d.Share >= opts.MaxShare
+		b.Detectors = append(b.Detectors, *d)
+	}
+	sort.Slice(b.Detectors, func(i, j int) bool {
+		if b.Detectors[i].Count != b.Detectors[j].Count {
+			return b.Detectors[i].Count > b.Detectors[j].Count
+		}
+		return b.Detectors[i].Check < b.Detectors[j].Check
+	})
+
+	if nonProd >= opts.MinFindings && float64(nonProd)/float64(b.Total) >= opts.MaxShare && len(prodDirs) > 0 {
+		b.Suggestions = append(b.Suggestions, pathSuggestion(target, nonProd, b.Total, prodDirs))
+	}
+
+	var excluded []string
+	disabled := map[string]bool{}
+	for _, d := range b.Detectors {
+		// Mostly non-production noise is handled by the path suggestion.
+		if !d.OverBudget || d.NonProduction*2 > d.Count {
+			continue
+		}
+		if d.Source == "custom" {
+			if c, ok := checks.ForRule(d.Check); ok {
+				disabled[c.Name] = true
+			}
+			continue
+		}
+		excluded = append(excluded, d.Check)
+	}
+	if len(excluded) > 0 {
+		b.Suggestions = append(b.Suggestions, excludeSuggestion(b, excluded))
+	}
+	if len(disabled) > 0 {
+		b.Suggestions = append(b.Suggestions, checksSuggestion(disabled))
+	}
+	return b
+}
+
+func excludeSuggestion(b Budget, excluded []string) Suggestion {
+	var reasons []string
+	var sb strings.Builder
+	sb.WriteString("exclude:\n")
+	for _, check := range excluded {
+		for _, d := range b.Detectors {
+			if d.Check == check {
+				reasons = append(reasons, fmt.Sprintf("%s (%d findings, %.0f%%)", check, d.Count, d.Share*100))
+			}
+		}
+		fmt.Fprintf(&sb, "  - %s\n", check)
+	}
+	return Suggestion{
+		Reason:  "Exclude detectors that dominate the report: " + strings.Join(reasons, ", ") + ". Review a sample first; exclusion hides true positives too.",
+		Snippet: sb.String(),
+	}
+}
+
+func checksSuggestion(disabled map[string]bool) Suggestion {
+	var keep, off []string
+	for _, c := range checks.Select(nil) {
+		if disabled[c.Name] {
+			off = append(off, c.Name)
+		} else {
+			keep = append(keep, c.Name)
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("checks:\n")
+	for _, name := range keep {
+		fmt.Fprintf(&sb, "  - %s\n", name)
+	}
+	return Suggestion{
+		Reason:  "Run the custom checks except the noisiest: " + strings.Join(off, ", ") + ".",
+		Snippet: sb.String(),
+	}
+}
+
+func pathSuggestion(target string, nonProd, total int, prodDirs map[string]bool) Suggestion {
+	dirs := make([]string, 0, len(prodDirs))
+	for d := range prodDirs {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+
+	var sb strings.Builder
+	sb.WriteString("workspace:\n  packages:\n")
+	for _, d := range dirs {
+		fmt.Fprintf(&sb, "    - name: %s\n      path: %s\n", d, filepath.ToSlash(filepath.Join(target, d)))
+	}
+	return Suggestion{
+		Reason: fmt.Sprintf("%d of %d findings are in test, mock, script or vendored code. Analyze only the production "+
+			"sources (run with --workspace), or point solsec at them directly.", nonProd, total),
+		Snippet: sb.String(),
+	}
+}
+
+// topDir returns the first directory of file below target and whether the
+// file lives in non-production code.
+func topDir(target, file string) (string, bool) {
+	rel := file
+	absTarget, err1 := filepath.Abs(target)
+	absFile, err2 := filepath.Abs(file)
+	if err1 == nil && err2 == nil {
+		if r, err := filepath.Rel(absTarget, absFile); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+	base := filepath.Base(rel)
+	np := strings.HasSuffix(base, ".t.sol") || strings.HasSuffix(base, ".s.sol") || strings.HasPrefix(base, "Mock")
+	parts := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	for _, p := range parts {
+		if nonProductionDirs[p] {
+			np = true
+		}
+	}
+	if parts[0] == "." || parts[0] == ".." || parts[0] == "" {
+		return "", np
+	}
+	return parts[0], np
+}
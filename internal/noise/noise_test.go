@@ -0,0 +1,61 @@
+package noise
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findings(n int, source, check, file string) []parser.Finding {
+	out := make([]parser.Finding, n)
+	for i := range out {
+		out[i] = parser.Finding{ID: fmt.Sprintf("%s-%d", check, i), Source: source, Check: check, File: file}
+	}
+	return out
+}
+
+func TestAnalyze_ExcludesNoisyDetector(t *testing.T) {
+	var all []parser.Finding
+	all = append(all, findings(30, "slither", "naming-convention", "proj/src/Token.sol")...)
+	all = append(all, findings(3, "slither", "reentrancy-eth", "proj/src/Vault.sol")...)
+	all = append(all, findings(12, "custom", "custom-todo-comment", "proj/src/Vault.sol")...)
+
+	b := Analyze("proj", all, Options{})
+	assert.Equal(t, 45, b.Total)
+	require.Len(t, b.Detectors, 3)
+	assert.Equal(t, "naming-convention", b.Detectors[0].Check)
+	assert.True(t, b.Detectors[0].OverBudget)
+	assert.True(t, b.Detectors[1].OverBudget, "custom-todo-comment is 27% of the report")
+	assert.False(t, b.Detectors[2].OverBudget)
+
+	require.Len(t, b.Suggestions, 2)
+	assert.Equal(t, "exclude:\n  - naming-convention\n", b.Suggestions[0].Snippet)
+	assert.Contains(t, b.Suggestions[1].Snippet, "checks:\n")
+	assert.NotContains(t, b.Suggestions[1].Snippet, "- comments")
+	assert.Contains(t, b.Suggestions[1].Snippet, "- reentrancy")
+}
+
+func TestAnalyze_NonProductionNoiseSuggestsPaths(t *testing.T) {
+	var all []parser.Finding
+	all = append(all, findings(20, "slither", "solc-version", "proj/lib/openzeppelin/ERC20.sol")...)
+	all = append(all, findings(5, "slither", "reentrancy-eth", "proj/src/Vault.sol")...)
+	all = append(all, findings(2, "custom", "custom-todo-comment", "proj/test/Vault.t.sol")...)
+
+	b := Analyze("proj", all, Options{})
+	require.Len(t, b.Suggestions, 1, "vendored noise narrows paths instead of excluding the detector")
+	assert.Contains(t, b.Suggestions[0].Reason, "22 of 27 findings")
+	assert.Equal(t, "workspace:\n  packages:\n    - name: src\n      path: proj/src\n", b.Suggestions[0].Snippet)
+}
+
+func TestAnalyze_WithinBudget(t *testing.T) {
+	b := Analyze("proj", findings(9, "slither", "timestamp", "proj/A.sol"), Options{})
+	assert.False(t, b.Detectors[0].OverBudget)
+	assert.Empty(t, b.Suggestions)
+
+	b = Analyze("proj", nil, Options{})
+	assert.Zero(t, b.Total)
+	assert.Empty(t, b.Detectors)
+}
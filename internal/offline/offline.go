@@ -0,0 +1,39 @@
+// Package offline enforces air-gapped operation. Once enabled, every code
+// path that would reach the network fails fast through Guard instead, and
+// child tools are told not to download anything either.
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// ErrOffline is wrapped by every error Guard returns.
+var ErrOffline = errors.New("network access is disabled (--offline)")
+
+var enabled atomic.Bool
+
+// Enable turns offline mode on for the rest of the process. Foundry, which
+// crytic-compile may invoke, is put in offline mode too so it cannot fetch
+// compilers.
+func Enable() {
+	enabled.Store(true)
+	_ = os.Setenv("FOUNDRY_OFFLINE", "true")
+}
+
+// Enabled reports whether offline mode is on.
+func Enabled() bool { return enabled.Load() }
+
+// Guard returns an error naming what would have needed the network when
+// offline mode is on, and nil otherwise.
+func Guard(what string) error {
+	if !Enabled() {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", what, ErrOffline)
+}
+
+// Disable turns offline mode back off; tests use it to restore state.
+func Disable() { enabled.Store(false) }
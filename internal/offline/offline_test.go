@@ -0,0 +1,20 @@
+package offline
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard(t *testing.T) {
+	assert.NoError(t, Guard("downloading rules"))
+
+	t.Setenv("FOUNDRY_OFFLINE", "")
+	Enable()
+	defer Disable()
+	err := Guard("downloading rules")
+	assert.ErrorIs(t, err, ErrOffline)
+	assert.EqualError(t, err, "downloading rules: network access is disabled (--offline)")
+	assert.Equal(t, "true", os.Getenv("FOUNDRY_OFFLINE"))
+}
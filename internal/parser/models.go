@@ -1,5 +1,10 @@
 package parser
 
+import (
+	"fmt"
+	"strings"
+)
+
 // SlitherOutput is the top-level structure of Slither's JSON output.
 // Slither produces this when run with --json flag.
 type SlitherOutput struct {
@@ -30,6 +35,10 @@ type DetectorElement struct {
 	Name             string           `json:"name"`
 	SourceMapping    SourceMapping    `json:"source_mapping"`
 	TypeSpecificInfo TypeSpecificInfo `json:"type_specific_fields"`
+
+	// AdditionalFields says what the element is to the detector, e.g. a
+	// reentrancy's {"underlying_type": "external_calls"}.
+	AdditionalFields map[string]any `json:"additional_fields,omitempty"`
 }
 
 type SourceMapping struct {
@@ -54,6 +63,7 @@ type ParentInfo struct {
 
 type Finding struct {
 	ID          string   `json:"id"`
+	Fingerprint string   `json:"fingerprint,omitempty"` // stable across runs and unrelated edits
 	Source      string   `json:"source"`      // "slither" or "custom"
 	Check       string   `json:"check"`       // detector name / check name
 	Title       string   `json:"title"`
@@ -64,9 +74,100 @@ type Finding struct {
 	Lines       []int    `json:"lines"`
 	Remediation string   `json:"remediation"`
 	SWCRef      string   `json:"swc_ref"`     // SWC registry reference e.g. "SWC-107"
+	CWERef      string   `json:"cwe_ref"`     // CWE reference e.g. "CWE-841"
 	References  []string `json:"references"`
+
+	// Evidence records exactly what a heuristic matched (patterns, columns,
+	// missing guards) so a finding can be understood and disputed.
+	Evidence []string `json:"evidence,omitempty"`
+
+	// CodeURL links the finding's lines on GitHub or GitLab, at the commit
+	// analyzed, when the target is in a repository hosted there.
+	CodeURL string `json:"code_url,omitempty"`
+
+	// Tags categorize the finding (e.g. "reentrancy", "defi"), taken from
+	// the rules bundle entry for its check.
+	Tags []string `json:"tags,omitempty"`
+
+	// Contract and Function name the declarations enclosing the finding's
+	// first line, when it falls inside one.
+	Contract string `json:"contract,omitempty"`
+	Function string `json:"function,omitempty"`
+
+	// RootCause names the flaw the finding shares with others of its check
+	// in the same contract, e.g. "balances written after an external call"
+	// or "missing onlyOwner". Findings with the same root cause are
+	// clustered; it is empty when the check cannot tell, and such findings
+	// stay on their own.
+	RootCause string `json:"root_cause,omitempty"`
+
+	// Related holds the findings clustered under this one because they share
+	// its root cause. They are listed and scored through this finding, but
+	// still count in the summary and toward failure thresholds.
+	Related []Finding `json:"related,omitempty"`
+
+	// EngineID is the engine's own identifier for the result (Slither's
+	// result hash), which its triage database refers to.
+	EngineID string `json:"engine_id,omitempty"`
+
+	// Validation is the outcome of replaying the finding against a fork of
+	// the deployed contract (solsec validate); empty when not validated.
+	Validation Validation `json:"validation,omitempty"`
+
+	// Impact is the value held by the deployed contract the finding is in
+	// (solsec impact); nil when not assessed.
+	Impact *Impact `json:"impact,omitempty"`
+
+	// Flow is the dangerous path step by step, e.g. a reentrancy's external
+	// call followed by the state write after it.
+	Flow []FlowStep `json:"flow,omitempty"`
+}
+
+// FlowStep is one location on a finding's Flow.
+type FlowStep struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Impact is what an exploit of a finding could take: the balances of the
+// deployed contract it is in.
+type Impact struct {
+	Address  string    `json:"address"`
+	Holdings []Holding `json:"holdings"`
+	Value    float64   `json:"value"` // the priced holdings, in Currency
+	Currency string    `json:"currency"`
+}
+
+// String renders the value at risk with thousands separators, e.g.
+// "6,500.25 USD".
+func (i *Impact) String() string {
+	whole, cents, _ := strings.Cut(fmt.Sprintf("%.2f", i.Value), ".")
+	var b strings.Builder
+	for n, d := range whole {
+		if n > 0 && (len(whole)-n)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String() + "." + cents + " " + i.Currency
+}
+
+// Holding is a contract's balance of one asset.
+type Holding struct {
+	Asset  string  `json:"asset"`           // symbol, e.g. "ETH" or "USDC"
+	Amount string  `json:"amount"`          // in whole units, e.g. "12.5"
+	Value  float64 `json:"value,omitempty"` // in the impact's currency; 0 when unpriced
 }
 
+// Validation is the dynamic verdict on a finding.
+type Validation string
+
+const (
+	ValidationConfirmed       Validation = "confirmed"        // the exploit succeeded on the fork
+	ValidationNotReproducible Validation = "not-reproducible" // the exploit reverted on the fork
+)
+
 // Severity represents the risk level of a finding.
 type Severity string
 
@@ -99,12 +200,230 @@ func SeverityRank(s Severity) int {
 	}
 }
 
+// Flatten expands clustered findings into individual ones: each finding,
+// without its Related, followed by the findings clustered under it.
+func Flatten(findings []Finding) []Finding {
+	out := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		related := f.Related
+		f.Related = nil
+		out = append(out, f)
+		out = append(out, related...)
+	}
+	return out
+}
+
 // AnalysisReport is the final output produced after all checks are complete.
 type AnalysisReport struct {
 	Target      string    `json:"target"`
 	GeneratedAt string    `json:"generated_at"`
 	Summary     Summary   `json:"summary"`
 	Findings    []Finding `json:"findings"`
+	Trend       *Trend    `json:"trend,omitempty"`
+
+	// AnalyzedFiles is the scope manifest: every file in the target and
+	// which engines covered it, so coverage can be proven to auditors.
+	AnalyzedFiles []AnalyzedFile `json:"analyzed_files"`
+
+	// ImportGraph is the dependency graph between the files in scope.
+	ImportGraph *ImportGraph `json:"import_graph,omitempty"`
+
+	// Interrupted marks a partial report: the run was cancelled (e.g. by
+	// Ctrl-C) before every engine and check finished.
+	Interrupted bool `json:"interrupted,omitempty"`
+
+	// Scope is set when the analysis was restricted to named contracts or
+	// functions; findings outside them were dropped.
+	Scope *Scope `json:"scope,omitempty"`
+
+	// Environment records the tool versions and detector set that produced
+	// the report, as reproducibility evidence.
+	Environment *Environment `json:"environment,omitempty"`
+
+	// Triaged counts findings left out because they are recorded in the
+	// triage database (slither.db.json).
+	Triaged int `json:"triaged,omitempty"`
+
+	// Baselined counts findings left out because they are recorded in the
+	// baseline file (--baseline): known findings accepted when adopting
+	// solsec.
+	Baselined int `json:"baselined,omitempty"`
+
+	// Compliance is the control matrix: every EthTrust requirement and SCSVS
+	// control area evaluated by the checks that ran.
+	Compliance []ComplianceControl `json:"compliance,omitempty"`
+
+	// Archetype is set when the analysis was tuned for a kind of contract
+	// (--archetype): its key risks and how many findings bear on each.
+	Archetype *ArchetypeReview `json:"archetype,omitempty"`
+
+	// System is set on system-level reports (solsec system): the deployed
+	// components and the trust relationships between them.
+	System *System `json:"system,omitempty"`
+
+	// AccessMatrix maps every external or public state-changing function to
+	// the guards that restrict who can call it.
+	AccessMatrix []AccessEntry `json:"access_matrix,omitempty"`
+
+	// Degraded lists the custom checks that crashed or timed out. Their
+	// findings are missing, so the report may understate the risk.
+	Degraded []DegradedCheck `json:"degraded,omitempty"`
+
+	// Warnings records the non-fatal problems of the run: checks that
+	// failed, files left unanalyzed and findings dropped as duplicates.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// Warning kinds.
+const (
+	WarningCheckError     = "check-error"     // a custom check returned an error
+	WarningSkippedFile    = "skipped-file"    // a file failed the size or binary guards
+	WarningCompileFailure = "compile-failure" // a file the engines could not compile
+	WarningDedup          = "dedup"           // a finding dropped as a duplicate
+)
+
+// Warning is a non-fatal problem of an analysis: it ran to the end, but part
+// of the target was not covered or findings were dropped.
+type Warning struct {
+	Kind    string `json:"kind"`
+	Check   string `json:"check,omitempty"`
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+// String describes w on one line, naming the file or check it concerns.
+func (w Warning) String() string {
+	switch {
+	case w.File != "":
+		return w.File + ": " + w.Message
+	case w.Check != "":
+		return w.Check + ": " + w.Message
+	}
+	return w.Message
+}
+
+// DegradedCheck is a custom check whose findings are missing from a report.
+type DegradedCheck struct {
+	Check  string `json:"check"`
+	Reason string `json:"reason"` // e.g. "timed out after 2m0s" or "panic: ..."
+}
+
+// AccessEntry is one row of the access-control matrix.
+type AccessEntry struct {
+	Contract string `json:"contract"`
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Payable  bool   `json:"payable,omitempty"`
+
+	// CallableBy lists the modifiers (with their role arguments) and inline
+	// sender checks guarding the function, or just Anyone when unguarded.
+	CallableBy []string `json:"callable_by"`
+}
+
+// Anyone is the AccessEntry.CallableBy of a function without a guard.
+const Anyone = "ANYONE"
+
+// System is a protocol deployed as several contracts, e.g. a router,
+// factory and vault.
+type System struct {
+	Components []Component `json:"components"`
+	Edges      []TrustEdge `json:"edges"`
+}
+
+// Component is one deployed contract of a system and its verified source.
+type Component struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+	Path    string `json:"path"`
+}
+
+// TrustEdge is an address constant in one component's code: From calls or
+// trusts whatever is deployed at Address.
+type TrustEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to,omitempty"` // the component at Address; empty when outside the system
+	Address string `json:"address"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+// ComplianceControl is one evaluated control of a security standard.
+type ComplianceControl struct {
+	Framework string   `json:"framework"` // "EthTrust" or "SCSVS"
+	Control   string   `json:"control"`   // e.g. "[S] No tx.origin" or "SCSVS-AUTH"
+	Checks    []string `json:"checks"`    // checks that ran and evaluate the control, sorted
+	Findings  int      `json:"findings"`  // findings from those checks; 0 means it passed
+}
+
+// ArchetypeReview is the threat model of the contract archetype an analysis
+// was tuned for.
+type ArchetypeReview struct {
+	Name        string          `json:"name"` // e.g. "vault"
+	Description string          `json:"description"`
+	Risks       []ArchetypeRisk `json:"risks"`
+}
+
+// ArchetypeRisk is one key risk of an archetype.
+type ArchetypeRisk struct {
+	Title    string   `json:"title"`
+	Checks   []string `json:"checks,omitempty"` // checks that ran and evaluate the risk; none means it was not evaluated
+	Findings int      `json:"findings"`         // findings from those checks
+}
+
+// Environment is the toolchain an analysis ran with: what solsec.lock pins.
+// Slither, Detectors and Solc are empty when Slither did not run.
+type Environment struct {
+	Solsec    string   `json:"solsec"`
+	RulesDB   int      `json:"rules_db"`
+	Slither   string   `json:"slither,omitempty"`
+	Detectors []string `json:"detectors,omitempty"` // Slither detectors that were enabled, sorted
+	Solc      string   `json:"solc,omitempty"`
+}
+
+// Scope restricts an analysis to named contracts and functions. A function
+// matches in any contract unless Contracts is also set.
+type Scope struct {
+	Contracts []string `json:"contracts,omitempty"`
+	Functions []string `json:"functions,omitempty"`
+}
+
+// Empty reports whether the scope places no restriction.
+func (s *Scope) Empty() bool {
+	return s == nil || (len(s.Contracts) == 0 && len(s.Functions) == 0)
+}
+
+// ImportGraph records which files import which. External nodes are imports
+// that resolve outside the analysis scope (libraries, remapped packages).
+type ImportGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+type GraphNode struct {
+	Path      string   `json:"path"`
+	Contracts []string `json:"contracts,omitempty"`
+	External  bool     `json:"external,omitempty"`
+}
+
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// AnalyzedFile records one file in the analysis scope.
+type AnalyzedFile struct {
+	Path       string   `json:"path"`
+	SHA256     string   `json:"sha256"`
+	SLOC       int      `json:"sloc"`
+	Pragma     string   `json:"pragma"`
+	Engines    []string `json:"engines"`
+	Skipped    bool     `json:"skipped,omitempty"`
+	SkipReason string   `json:"skip_reason,omitempty"`
+
+	// Excluded is why custom checks did not read the file (too large,
+	// binary or embedded data); such files are missing "custom" in Engines.
+	Excluded string `json:"excluded,omitempty"`
 }
 
 type Summary struct {
@@ -115,4 +434,17 @@ type Summary struct {
 	Low           int `json:"low"`
 	Informational int `json:"informational"`
 	Optimization  int `json:"optimization"`
-}
\ No newline at end of file
+}
+// Trend is the run history attached to a report when a history store exists.
+type Trend struct {
+	Points     []TrendPoint `json:"points"`      // oldest first, ending with this run
+	ScoreDelta int          `json:"score_delta"` // change vs the previous run
+}
+
+// TrendPoint is the recorded outcome of one analysis run.
+type TrendPoint struct {
+	GeneratedAt string  `json:"generated_at"`
+	Target      string  `json:"target"`
+	Score       int     `json:"score"`
+	Summary     Summary `json:"summary"`
+}
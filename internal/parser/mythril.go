@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MythrilOutput is the top-level structure of `myth analyze -o json`.
+type MythrilOutput struct {
+	Success bool           `json:"success"`
+	Error   *string        `json:"error"`
+	Issues  []MythrilIssue `json:"issues"`
+}
+
+// MythrilIssue is a single finding from Mythril's symbolic execution.
+type MythrilIssue struct {
+	Title       string `json:"title"`
+	SWCID       string `json:"swc-id"`
+	Severity    string `json:"severity"`
+	Contract    string `json:"contract"`
+	Function    string `json:"function"`
+	Description string `json:"description"`
+	Filename    string `json:"filename"`
+	LineNo      int    `json:"lineno"`
+}
+
+// ParseMythril converts Mythril JSON output into unified Finding structs.
+func ParseMythril(data []byte) ([]Finding, error) {
+	var output MythrilOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("parsing mythril JSON: %w", err)
+	}
+	if !output.Success {
+		errMsg := "unknown error"
+		if output.Error != nil {
+			errMsg = *output.Error
+		}
+		return nil, fmt.Errorf("mythril analysis failed: %s", errMsg)
+	}
+
+	findings := make([]Finding, 0, len(output.Issues))
+	for i, issue := range output.Issues {
+		swc := ""
+		if issue.SWCID != "" {
+			swc = "SWC-" + issue.SWCID
+		}
+		check := "mythril-" + strings.ToLower(strings.ReplaceAll(strings.TrimSpace(issue.Title), " ", "-"))
+
+		f := Finding{
+			ID:          fmt.Sprintf("MYTHRIL-%03d", i+1),
+			Source:      "mythril",
+			Check:       check,
+			Title:       issue.Title,
+			Description: strings.TrimSpace(issue.Description),
+			Severity:    mapImpact(issue.Severity),
+			Confidence:  "Medium",
+			File:        issue.Filename,
+			Remediation: "Review the Mythril transaction sequence for this issue and apply the SWC registry remediation.",
+			SWCRef:      swc,
+		}
+		if issue.LineNo > 0 {
+			f.Lines = []int{issue.LineNo}
+		}
+		if swc != "" {
+			f.References = []string{fmt.Sprintf("https://swcregistry.io/docs/%s", swc)}
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
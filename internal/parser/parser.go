@@ -5,47 +5,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
-)
-
-// remediations maps Slither detector check names to human-readable fix guidance.
-// This is the "opinionated output" that makes solsec more useful than raw Slither.
-var remediations = map[string]string{
-	"reentrancy-eth":        "Apply the checks-effects-interactions pattern. Move all state changes before external calls. Consider using ReentrancyGuard from OpenZeppelin.",
-	"reentrancy-no-eth":     "Apply the checks-effects-interactions pattern. Move all state changes before external calls.",
-	"reentrancy-benign":     "Although impact is low, apply checks-effects-interactions pattern as defence in depth.",
-	"reentrancy-unlimited-gas": "Apply the checks-effects-interactions pattern and use ReentrancyGuard.",
-	"unprotected-upgrade":   "Add access control to upgrade functions. Use OpenZeppelin's OwnableUpgradeable.",
-	"controlled-delegatecall": "Avoid passing user-controlled data to delegatecall. Whitelist allowed targets.",
-	"arbitrary-send-eth":    "Restrict which addresses can receive ETH. Use a withdrawal pattern with explicit recipient validation.",
-	"suicidal":              "Remove selfdestruct or gate it behind a multi-sig with a timelock.",
-	"backdoor":              "Remove any functions that allow unauthorized state manipulation.",
-	"tx-origin":             "Replace tx.origin with msg.sender for authentication. tx.origin is vulnerable to phishing attacks.",
-	"weak-prng":             "Do not use block.timestamp or blockhash for randomness. Use Chainlink VRF or commit-reveal schemes.",
-	"timestamp":             "Avoid using block.timestamp for critical logic. Miners can manipulate it by ~15 seconds.",
-	"unchecked-transfer":    "Always check the return value of ERC-20 transfer() and transferFrom(). Use SafeERC20 from OpenZeppelin.",
-	"uninitialized-local":   "Initialize all local variables before use. Uninitialized storage pointers in older Solidity versions can corrupt state.",
-	"shadowing-state":       "Rename the local variable to avoid shadowing the state variable. This causes silent bugs.",
-	"abiencoderv2-array":    "Upgrade to Solidity 0.8.x where ABIEncoderV2 is stable, or avoid nested dynamic arrays.",
-	"msg-value-loop":        "Do not use msg.value inside a loop — it does not change per iteration and causes logic errors.",
-	"divide-before-multiply": "Perform multiplications before divisions to avoid precision loss due to integer truncation.",
-	"tautology":             "Remove the tautological condition — it always evaluates to true/false and may hide a logic error.",
-	"boolean-equality":      "Compare bool directly (if flag) instead of (if flag == true). The latter is redundant and reduces readability.",
-}
 
-// swcRefs maps detector names to SWC registry IDs for professional report references.
-var swcRefs = map[string]string{
-	"reentrancy-eth":          "SWC-107",
-	"reentrancy-no-eth":       "SWC-107",
-	"reentrancy-benign":       "SWC-107",
-	"tx-origin":               "SWC-115",
-	"weak-prng":               "SWC-120",
-	"timestamp":               "SWC-116",
-	"unprotected-upgrade":     "SWC-112",
-	"arbitrary-send-eth":      "SWC-105",
-	"suicidal":                "SWC-106",
-	"unchecked-transfer":      "SWC-104",
-	"divide-before-multiply":  "SWC-101",
-}
+	"github.com/Zubimendi/solsec/internal/rules"
+)
 
 // Parse reads a Slither JSON output file and converts it into unified Finding structs.
 func Parse(slitherJSONPath string) ([]Finding, error) {
@@ -82,8 +44,10 @@ func ParseBytes(data []byte) ([]Finding, error) {
 			Severity:    mapImpact(d.Impact),
 			Confidence:  d.Confidence,
 			Remediation: remediationFor(d.Check),
-			SWCRef:      swcRefs[d.Check],
+			SWCRef:      rules.Default().SWC(d.Check),
+			CWERef:      rules.Default().CWE(d.Check),
 			References:  referencesFor(d.Check),
+			EngineID:    d.ID,
 		}
 
 		// Extract file and line info from the first element
@@ -92,6 +56,12 @@ func ParseBytes(data []byte) ([]Finding, error) {
 			f.File = el.SourceMapping.Filename
 			f.Lines = el.SourceMapping.Lines
 		}
+		if strings.HasPrefix(d.Check, "reentrancy-") {
+			f.Flow = reentrancyFlow(d.Elements)
+			if name := writtenVariable(d.Elements); name != "" {
+				f.RootCause = name + " written after an external call"
+			}
+		}
 
 		findings = append(findings, f)
 	}
@@ -99,6 +69,57 @@ func ParseBytes(data []byte) ([]Finding, error) {
 	return findings, nil
 }
 
+// reentrancyFlow is the path a Slither reentrancy result describes: the
+// first external call, then the first state write or event emitted after
+// it. It is nil unless both are present.
+func reentrancyFlow(elements []DetectorElement) []FlowStep {
+	var call, effect *FlowStep
+	for _, el := range elements {
+		if len(el.SourceMapping.Lines) == 0 {
+			continue
+		}
+		step := &FlowStep{File: el.SourceMapping.Filename, Line: el.SourceMapping.Lines[0]}
+		switch el.AdditionalFields["underlying_type"] {
+		case "external_calls", "external_calls_sending_eth":
+			if call == nil {
+				step.Message = fmt.Sprintf("External call: %s", el.Name)
+				call = step
+			}
+		case "variables_written":
+			if effect == nil {
+				step.Message = fmt.Sprintf("State written after the call: %s", el.Name)
+				if name, ok := el.AdditionalFields["variable_name"].(string); ok {
+					step.Message = fmt.Sprintf("State variable %s written after the call: %s", name, el.Name)
+				}
+				effect = step
+			}
+		case "event":
+			if effect == nil {
+				step.Message = fmt.Sprintf("Event emitted after the call: %s", el.Name)
+				effect = step
+			}
+		}
+	}
+	if call == nil || effect == nil {
+		return nil
+	}
+	return []FlowStep{*call, *effect}
+}
+
+// writtenVariable is the first state variable a Slither reentrancy result
+// reports written after the call, or "".
+func writtenVariable(elements []DetectorElement) string {
+	for _, el := range elements {
+		if el.AdditionalFields["underlying_type"] != "variables_written" {
+			continue
+		}
+		if name, ok := el.AdditionalFields["variable_name"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
 // mapImpact converts Slither's impact string to our Severity type.
 func mapImpact(impact string) Severity {
 	switch strings.ToLower(impact) {
@@ -129,8 +150,10 @@ func formatTitle(check string) string {
 	return strings.Join(parts, " ")
 }
 
+// remediationFor returns the opinionated fix guidance from the rules bundle —
+// the "opinionated output" that makes solsec more useful than raw Slither.
 func remediationFor(check string) string {
-	if r, ok := remediations[check]; ok {
+	if r := rules.Default().Remediation(check); r != "" {
 		return r
 	}
 	return "Review the Slither documentation for this detector and apply the recommended fix."
@@ -140,7 +163,7 @@ func referencesFor(check string) []string {
 	refs := []string{
 		fmt.Sprintf("https://github.com/crytic/slither/wiki/Detector-Documentation#%s", check),
 	}
-	if swc, ok := swcRefs[check]; ok {
+	if swc := rules.Default().SWC(check); swc != "" {
 		refs = append(refs, fmt.Sprintf("https://swcregistry.io/docs/%s", swc))
 	}
 	return refs
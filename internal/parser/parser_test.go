@@ -1,6 +1,8 @@
 package parser_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -91,6 +93,7 @@ func TestParseBytes_FirstFindingIsReentrancy(t *testing.T) {
 	assert.Equal(t, "Medium", f.Confidence)
 	assert.Equal(t, "/contracts/EtherStore.sol", f.File)
 	assert.Equal(t, []int{10, 11, 12, 13, 14}, f.Lines)
+	assert.Equal(t, "abc123", f.EngineID)
 }
 
 func TestParseBytes_RemediationPopulated(t *testing.T) {
@@ -138,8 +141,191 @@ func TestParseBytes_TitleFormatting(t *testing.T) {
 	assert.Equal(t, "Reentrancy Eth", findings[0].Title)
 }
 
+func TestParseBytes_ReentrancyFlow(t *testing.T) {
+	out := []byte(`{"success": true, "results": {"detectors": [{
+  "check": "reentrancy-no-eth", "impact": "Medium", "confidence": "Medium",
+  "elements": [
+    {"type": "function", "name": "withdraw", "source_mapping": {"filename_absolute": "/c/Bank.sol", "lines": [10, 11, 12, 13, 14]}},
+    {"type": "node", "name": "token.transfer(msg.sender,amount)", "source_mapping": {"filename_absolute": "/c/Bank.sol", "lines": [12]},
+     "additional_fields": {"underlying_type": "external_calls"}},
+    {"type": "node", "name": "balances[msg.sender] = 0", "source_mapping": {"filename_absolute": "/c/Bank.sol", "lines": [13]},
+     "additional_fields": {"underlying_type": "variables_written", "variable_name": "balances"}}
+  ]}, {
+  "check": "reentrancy-benign", "impact": "Low", "confidence": "Medium",
+  "elements": [{"type": "function", "name": "f", "source_mapping": {"filename_absolute": "/c/Bank.sol", "lines": [20]}}]
+}]}}`)
+	findings, err := parser.ParseBytes(out)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	assert.Equal(t, []parser.FlowStep{
+		{File: "/c/Bank.sol", Line: 12, Message: "External call: token.transfer(msg.sender,amount)"},
+		{File: "/c/Bank.sol", Line: 13, Message: "State variable balances written after the call: balances[msg.sender] = 0"},
+	}, findings[0].Flow)
+	assert.Nil(t, findings[1].Flow, "no call and write elements")
+	assert.Equal(t, "balances written after an external call", findings[0].RootCause)
+	assert.Empty(t, findings[1].RootCause)
+}
+
 func TestSeverityRank_Order(t *testing.T) {
 	assert.Less(t, parser.SeverityRank(parser.SeverityCritical), parser.SeverityRank(parser.SeverityHigh))
 	assert.Less(t, parser.SeverityRank(parser.SeverityHigh), parser.SeverityRank(parser.SeverityMedium))
 	assert.Less(t, parser.SeverityRank(parser.SeverityMedium), parser.SeverityRank(parser.SeverityLow))
-}
\ No newline at end of file
+}
+var sampleMythrilOutput = []byte(`{
+  "error": null,
+  "issues": [
+    {
+      "title": "External Call To User-Supplied Address",
+      "swc-id": "107",
+      "severity": "Low",
+      "contract": "EtherStore",
+      "function": "withdraw()",
+      "description": "A call to a user-supplied address is executed.",
+      "filename": "/contracts/EtherStore.sol",
+      "lineno": 12
+    }
+  ],
+  "success": true
+}`)
+
+func TestParseMythril(t *testing.T) {
+	findings, err := parser.ParseMythril(sampleMythrilOutput)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+
+	f := findings[0]
+	assert.Equal(t, "mythril", f.Source)
+	assert.Equal(t, "mythril-external-call-to-user-supplied-address", f.Check)
+	assert.Equal(t, parser.SeverityLow, f.Severity)
+	assert.Equal(t, "SWC-107", f.SWCRef)
+	assert.Equal(t, []int{12}, f.Lines)
+}
+
+var sampleSARIF = []byte(`{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "Aderyn", "rules": [
+      {"id": "centralization-risk", "shortDescription": {"text": "Centralization Risk"}, "helpUri": "https://example.com/rules/1",
+       "properties": {"tags": ["access-control"], "security-severity": "7.5"}},
+      {"id": "unspecific-pragma", "name": "UnspecificPragma", "defaultConfiguration": {"level": "note"}}
+    ]}},
+    "results": [
+      {"ruleId": "centralization-risk", "message": {"text": "Owner can drain funds"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///contracts/Vault%20V2.sol"}, "region": {"startLine": 4, "endLine": 6}}}]},
+      {"ruleId": "unspecific-pragma", "message": {"text": "Pin the pragma"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/Token.sol"}, "region": {"startLine": 2}}}]},
+      {"ruleId": "unknown-rule", "level": "error", "message": {"text": "Something"}}
+    ]
+  }]
+}`)
+
+func TestParseSARIF(t *testing.T) {
+	findings, err := parser.ParseSARIF(sampleSARIF)
+	require.NoError(t, err)
+	require.Len(t, findings, 3)
+
+	f := findings[0]
+	assert.Equal(t, "ADERYN-001", f.ID)
+	assert.Equal(t, "aderyn", f.Source)
+	assert.Equal(t, "centralization-risk", f.Check)
+	assert.Equal(t, "Centralization Risk", f.Title)
+	assert.Equal(t, parser.SeverityHigh, f.Severity, "from security-severity")
+	assert.Equal(t, "/contracts/Vault V2.sol", f.File)
+	assert.Equal(t, []int{4, 5, 6}, f.Lines)
+	assert.Equal(t, []string{"access-control"}, f.Tags)
+	assert.Equal(t, []string{"https://example.com/rules/1"}, f.References)
+
+	assert.Equal(t, parser.SeverityLow, findings[1].Severity, "from the rule's default level")
+	assert.Equal(t, "UnspecificPragma", findings[1].Title)
+	assert.Equal(t, "src/Token.sol", findings[1].File)
+	assert.Equal(t, parser.SeverityHigh, findings[2].Severity, "from the result level")
+	assert.Empty(t, findings[2].File)
+
+	_, err = parser.ParseSARIF([]byte(`{"version": "1.0.0", "runs": []}`))
+	assert.Error(t, err)
+}
+
+var sampleSemgrepOutput = []byte(`{
+  "results": [
+    {
+      "check_id": "solidity.security.compound-borrowfresh-reentrancy",
+      "path": "src/Market.sol",
+      "start": {"line": 20, "col": 9},
+      "end": {"line": 21, "col": 10},
+      "extra": {
+        "message": "borrowFresh() transfers before updating state",
+        "severity": "WARNING",
+        "metadata": {"cwe": ["CWE-841: Improper Enforcement of Behavioral Workflow"], "references": "https://example.com/ref", "confidence": "HIGH", "impact": "HIGH"}
+      }
+    },
+    {
+      "check_id": "solidity.performance.array-length-outside-loop",
+      "path": "src/Market.sol",
+      "start": {"line": 40}, "end": {"line": 40},
+      "extra": {"message": "Cache the length", "severity": "INFO", "metadata": {}}
+    }
+  ],
+  "errors": []
+}`)
+
+func TestParseSemgrep(t *testing.T) {
+	findings, err := parser.ParseSemgrep(sampleSemgrepOutput)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	f := findings[0]
+	assert.Equal(t, "SEMGREP-001", f.ID)
+	assert.Equal(t, "semgrep", f.Source)
+	assert.Equal(t, "Compound Borrowfresh Reentrancy", f.Title)
+	assert.Equal(t, parser.SeverityHigh, f.Severity, "impact metadata wins over the match severity")
+	assert.Equal(t, "High", f.Confidence)
+	assert.Equal(t, "CWE-841", f.CWERef)
+	assert.Equal(t, []string{"https://example.com/ref"}, f.References)
+	assert.Equal(t, []int{20, 21}, f.Lines)
+
+	assert.Equal(t, parser.SeverityInformational, findings[1].Severity)
+	assert.Equal(t, "Medium", findings[1].Confidence)
+	assert.Empty(t, findings[1].CWERef)
+}
+
+func TestLoadReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "target": "./contracts",
+  "summary": {"total": 1, "high": 1},
+  "findings": [{"id": "CUSTOM-REENTRANT-1", "check": "custom-reentrancy-ordering", "severity": "High"}],
+  "risk_score": 20,
+  "grade": "B"
+}`), 0644))
+
+	report, err := parser.LoadReport(path)
+	require.NoError(t, err)
+	assert.Equal(t, "./contracts", report.Target)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, parser.SeverityHigh, report.Findings[0].Severity)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": "2.1.0"}`), 0644))
+	_, err = parser.LoadReport(path)
+	assert.Error(t, err)
+}
+
+func TestLoadReport_NDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte(`{"type":"report","target":"./contracts","summary":{"total":2,"high":1,"low":1},"risk_score":23}
+{"type":"finding","id":"CUSTOM-REENTRANT-1","check":"custom-reentrancy-ordering","severity":"High"}
+{"type":"finding","id":"CUSTOM-SPDX-1","check":"custom-missing-spdx","severity":"Low"}
+`), 0644))
+
+	report, err := parser.LoadReport(path)
+	require.NoError(t, err)
+	assert.Equal(t, "./contracts", report.Target)
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, "CUSTOM-SPDX-1", report.Findings[1].ID)
+}
+
+func TestImpactString(t *testing.T) {
+	assert.Equal(t, "6,500.25 USD", (&parser.Impact{Value: 6500.25, Currency: "USD"}).String())
+	assert.Equal(t, "1,234,567.00 USD", (&parser.Impact{Value: 1234567, Currency: "USD"}).String())
+	assert.Equal(t, "0.50 ETH", (&parser.Impact{Value: 0.5, Currency: "ETH"}).String())
+}
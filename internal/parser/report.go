@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadReport reads a solsec JSON or NDJSON report (as written by the JSON and
+// NDJSON reporters) back into an AnalysisReport. Reporter-only fields like
+// risk_score are ignored; they are recomputed from the findings.
+func LoadReport(path string) (*AnalysisReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading report: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	var head struct {
+		Type string `json:"type"`
+		AnalysisReport
+	}
+	if err := dec.Decode(&head); err != nil {
+		return nil, fmt.Errorf("parsing report %s: %w", path, err)
+	}
+	report := head.AnalysisReport
+	if head.Type == "report" {
+		// NDJSON: one finding record per following line.
+		for dec.More() {
+			var record struct {
+				Type string `json:"type"`
+				Finding
+			}
+			if err := dec.Decode(&record); err != nil {
+				return nil, fmt.Errorf("parsing report %s: %w", path, err)
+			}
+			if record.Type == "finding" {
+				report.Findings = append(report.Findings, record.Finding)
+			}
+		}
+	}
+	if report.Target == "" && report.Findings == nil {
+		return nil, fmt.Errorf("%s does not look like a solsec JSON report", path)
+	}
+	return &report, nil
+}
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SARIFLog is the subset of a SARIF 2.1.0 log that imports need.
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []SARIFRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+	FullDescription  SARIFMessage `json:"fullDescription"`
+	HelpURI          string       `json:"helpUri"`
+	Help             SARIFMessage `json:"help"`
+	Properties       struct {
+		Tags []string `json:"tags"`
+		// SecuritySeverity is a CVSS-style score ("0.0"–"10.0"), as GitHub
+		// code scanning reads it.
+		SecuritySeverity string `json:"security-severity"`
+	} `json:"properties"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine int `json:"startLine"`
+			EndLine   int `json:"endLine"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+// ParseSARIF converts the results of every run in a SARIF 2.1.0 log into
+// unified Finding structs. Each finding's Source is the producing tool's name.
+func ParseSARIF(data []byte) ([]Finding, error) {
+	var log SARIFLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("parsing SARIF: %w", err)
+	}
+	if !strings.HasPrefix(log.Version, "2.") {
+		return nil, fmt.Errorf("parsing SARIF: unsupported version %q (need 2.1.0)", log.Version)
+	}
+
+	var findings []Finding
+	for _, run := range log.Runs {
+		tool := strings.ToLower(run.Tool.Driver.Name)
+		if tool == "" {
+			tool = "sarif"
+		}
+		rules := map[string]SARIFRule{}
+		for _, r := range run.Tool.Driver.Rules {
+			rules[r.ID] = r
+		}
+		for i, res := range run.Results {
+			rule := rules[res.RuleID]
+			title := firstNonEmpty(rule.ShortDescription.Text, rule.Name, res.RuleID)
+			f := Finding{
+				ID:          fmt.Sprintf("%s-%03d", strings.ToUpper(tool), i+1),
+				Source:      tool,
+				Check:       res.RuleID,
+				Title:       title,
+				Description: strings.TrimSpace(res.Message.Text),
+				Severity:    sarifSeverity(rule, res.Level),
+				Confidence:  "Medium",
+				Remediation: firstNonEmpty(strings.TrimSpace(rule.Help.Text), "Review the "+run.Tool.Driver.Name+" documentation for this rule."),
+				Tags:        rule.Properties.Tags,
+			}
+			if rule.HelpURI != "" {
+				f.References = []string{rule.HelpURI}
+			}
+			if len(res.Locations) > 0 {
+				loc := res.Locations[0].PhysicalLocation
+				f.File = sarifPath(loc.ArtifactLocation.URI)
+				for l := loc.Region.StartLine; l > 0 && l <= max(loc.Region.StartLine, loc.Region.EndLine); l++ {
+					f.Lines = append(f.Lines, l)
+				}
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// sarifSeverity prefers the rule's security-severity score, then the
+// result's level, then the rule's default level ("warning" when unset, per
+// the SARIF spec).
+func sarifSeverity(rule SARIFRule, level string) Severity {
+	if score, err := strconv.ParseFloat(rule.Properties.SecuritySeverity, 64); err == nil {
+		switch {
+		case score >= 9:
+			return SeverityCritical
+		case score >= 7:
+			return SeverityHigh
+		case score >= 4:
+			return SeverityMedium
+		case score > 0:
+			return SeverityLow
+		default:
+			return SeverityInformational
+		}
+	}
+	switch firstNonEmpty(level, rule.DefaultConfiguration.Level, "warning") {
+	case "error":
+		return SeverityHigh
+	case "warning":
+		return SeverityMedium
+	case "note":
+		return SeverityLow
+	default:
+		return SeverityInformational
+	}
+}
+
+// sarifPath turns an artifact URI into a file path; file:// URIs are
+// decoded, relative references are kept as they are.
+func sarifPath(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		return u.Path
+	}
+	if p, err := url.PathUnescape(uri); err == nil {
+		return p
+	}
+	return uri
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
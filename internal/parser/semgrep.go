@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SemgrepOutput is the top-level structure of `semgrep --json`.
+type SemgrepOutput struct {
+	Results []SemgrepResult `json:"results"`
+}
+
+// SemgrepResult is a single rule match.
+type SemgrepResult struct {
+	CheckID string `json:"check_id"`
+	Path    string `json:"path"`
+	Start   struct {
+		Line int `json:"line"`
+	} `json:"start"`
+	End struct {
+		Line int `json:"line"`
+	} `json:"end"`
+	Extra struct {
+		Message  string          `json:"message"`
+		Severity string          `json:"severity"` // ERROR, WARNING or INFO
+		Metadata SemgrepMetadata `json:"metadata"`
+	} `json:"extra"`
+}
+
+// SemgrepMetadata is the free-form rule metadata; the fields below are the
+// ones rule packs such as Decurity's smart-contract rules fill in. CWE and
+// References may be a string or a list.
+type SemgrepMetadata struct {
+	CWE        json.RawMessage `json:"cwe"`
+	References json.RawMessage `json:"references"`
+	Confidence string          `json:"confidence"`
+	Impact     string          `json:"impact"`
+}
+
+// ParseSemgrep converts Semgrep JSON output into unified Finding structs.
+func ParseSemgrep(data []byte) ([]Finding, error) {
+	var output SemgrepOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("parsing semgrep JSON: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(output.Results))
+	for i, r := range output.Results {
+		meta := r.Extra.Metadata
+		// Rule IDs are namespaced by their config path, e.g.
+		// "rules.solidity.security.compound-borrowfresh-reentrancy".
+		name := r.CheckID[strings.LastIndex(r.CheckID, ".")+1:]
+		f := Finding{
+			ID:          fmt.Sprintf("SEMGREP-%03d", i+1),
+			Source:      "semgrep",
+			Check:       r.CheckID,
+			Title:       formatTitle(name),
+			Description: strings.TrimSpace(r.Extra.Message),
+			Severity:    semgrepSeverity(meta.Impact, r.Extra.Severity),
+			Confidence:  capitalize(firstNonEmpty(meta.Confidence, "medium")),
+			File:        r.Path,
+			Remediation: "Review the Semgrep rule " + r.CheckID + " and its references for the recommended fix.",
+			References:  stringOrList(meta.References),
+		}
+		if cwe := stringOrList(meta.CWE); len(cwe) > 0 {
+			// "CWE-841: Improper Enforcement of Behavioral Workflow"
+			f.CWERef = strings.TrimSpace(strings.SplitN(cwe[0], ":", 2)[0])
+		}
+		for l := r.Start.Line; l > 0 && l <= max(r.Start.Line, r.End.Line); l++ {
+			f.Lines = append(f.Lines, l)
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// semgrepSeverity prefers the rule's impact metadata, which security rule
+// packs set, over Semgrep's coarser match severity.
+func semgrepSeverity(impact, severity string) Severity {
+	switch strings.ToUpper(impact) {
+	case "CRITICAL":
+		return SeverityCritical
+	case "HIGH":
+		return SeverityHigh
+	case "MEDIUM":
+		return SeverityMedium
+	case "LOW":
+		return SeverityLow
+	}
+	switch strings.ToUpper(severity) {
+	case "ERROR":
+		return SeverityHigh
+	case "WARNING":
+		return SeverityMedium
+	default:
+		return SeverityInformational
+	}
+}
+
+// stringOrList decodes a JSON string or list of strings.
+func stringOrList(raw json.RawMessage) []string {
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil && s != "" {
+		return []string{s}
+	}
+	return nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return ""
+	}
+	return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
+}
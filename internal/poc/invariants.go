@@ -0,0 +1,235 @@
+package poc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// GeneratedMarker starts every generated invariant file; only files carrying
+// it are overwritten on regeneration.
+const GeneratedMarker = "// Generated by solsec --suggest-invariants."
+
+// Invariant is one suggested property, rendered as a Foundry invariant_
+// function.
+type Invariant struct {
+	Name        string // function name, e.g. "invariant_solvent"
+	Description string
+	Body        []string // Solidity statements
+}
+
+// InvariantSuite is the suggested invariants for one contract.
+type InvariantSuite struct {
+	Contract   *solidity.Contract
+	File       string
+	Invariants []Invariant
+
+	// Restricted lists functions guarded by modifiers, which the
+	// unprivileged fuzzed actors should never get through.
+	Restricted []string
+	// Snapshots are state getters recorded in setUp and compared by the
+	// access-restricted invariants.
+	Snapshots []string
+}
+
+// balanceNames are mapping names that usually hold per-account balances.
+var balanceNames = []string{"balanceOf", "balances", "_balances", "deposits", "shares"}
+
+// privilegedNames are address variables that only privileged roles may change.
+var privilegedNames = []string{"owner", "admin", "governance", "guardian", "pendingOwner", "implementation", "treasury"}
+
+// SuggestInvariants inspects the surface of every deployable contract in
+// files and suggests invariants for those with a recognizable shape: token
+// supply accounting, ETH solvency and access-restricted state.
+func SuggestInvariants(files []*solidity.File) []InvariantSuite {
+	var suites []InvariantSuite
+	for _, f := range files {
+		for i := range f.Contracts {
+			c := &f.Contracts[i]
+			if c.Kind != "contract" || c.Abstract {
+				continue
+			}
+			if s := suggest(f.Path, c); len(s.Invariants) > 0 {
+				suites = append(suites, s)
+			}
+		}
+	}
+	return suites
+}
+
+func suggest(path string, c *solidity.Contract) InvariantSuite {
+	s := InvariantSuite{Contract: c, File: path}
+	for _, fn := range c.Functions {
+		if isCallable(fn) && slices.ContainsFunc(fn.Modifiers, func(m string) bool { return m != "nonReentrant" }) {
+			s.Restricted = append(s.Restricted, fn.Name)
+		}
+	}
+
+	erc20 := slices.ContainsFunc(c.Bases, func(b string) bool { return strings.Contains(b, "ERC20") })
+	balances := ""
+	if erc20 {
+		balances = "balanceOf"
+	} else if v := publicVar(c, balanceNames, "mapping(address=>uint"); v != nil {
+		balances = v.Name
+	}
+	hasSupply := erc20 || publicVar(c, []string{"totalSupply"}, "uint") != nil
+
+	if balances != "" && hasSupply {
+		s.Invariants = append(s.Invariants, Invariant{
+			Name:        "invariant_balancesWithinTotalSupply",
+			Description: "Supply conservation: no sequence of calls credits accounts with more than the total supply.",
+			Body: []string{
+				"uint256 sum;",
+				"for (uint256 i = 0; i < actors.length; i++) {",
+				"    sum += target." + balances + "(actors[i]);",
+				"}",
+				"assertLe(sum, target.totalSupply());",
+			},
+		})
+	}
+	if balances != "" && !erc20 && slices.ContainsFunc(c.Functions, func(fn solidity.Function) bool { return fn.Payable }) {
+		s.Invariants = append(s.Invariants, Invariant{
+			Name:        "invariant_solvent",
+			Description: "Solvency: the contract always holds enough ETH to repay what it owes its accounts.",
+			Body: []string{
+				"uint256 owed;",
+				"for (uint256 i = 0; i < actors.length; i++) {",
+				"    owed += target." + balances + "(actors[i]);",
+				"}",
+				"assertGe(address(target).balance, owed);",
+			},
+		})
+	}
+
+	var privileged []string
+	for _, v := range c.StateVars {
+		if v.Visibility == "public" && !v.Constant && v.Type == "address" && slices.Contains(privilegedNames, v.Name) {
+			privileged = append(privileged, v.Name)
+		}
+	}
+	if len(privileged) == 0 && slices.ContainsFunc(c.Bases, func(b string) bool { return strings.HasPrefix(b, "Ownable") }) {
+		privileged = []string{"owner"}
+	}
+	for _, name := range privileged {
+		s.Snapshots = append(s.Snapshots, name)
+		s.Invariants = append(s.Invariants, Invariant{
+			Name:        "invariant_" + name + "Unchanged",
+			Description: fmt.Sprintf("Access-restricted state: unprivileged actors can never change %s.", name),
+			Body:        []string{fmt.Sprintf("assertEq(target.%s(), initial_%s);", name, name)},
+		})
+	}
+	return s
+}
+
+func isCallable(fn solidity.Function) bool {
+	return fn.Visibility == "public" || fn.Visibility == "external"
+}
+
+// publicVar returns the first public, mutable state variable named one of
+// names whose type, without spaces, starts with typePrefix.
+func publicVar(c *solidity.Contract, names []string, typePrefix string) *solidity.StateVar {
+	for _, name := range names {
+		for i, v := range c.StateVars {
+			if v.Name == name && v.Visibility == "public" && !v.Constant && strings.HasPrefix(strings.ReplaceAll(v.Type, " ", ""), typePrefix) {
+				return &c.StateVars[i]
+			}
+		}
+	}
+	return nil
+}
+
+// InvariantPath is where the suite for contract is written under root.
+func InvariantPath(root, contract string) string {
+	return filepath.Join(root, "test", "invariants", contract+"Invariants.t.sol")
+}
+
+// RenderInvariants renders s as a Foundry test file to be written at
+// outputPath.
+func RenderInvariants(s InvariantSuite, outputPath string) ([]byte, error) {
+	data, err := os.ReadFile(s.File)
+	if err != nil {
+		return nil, fmt.Errorf("reading target source: %w", err)
+	}
+	importPath, err := relativeImport(outputPath, s.File)
+	if err != nil {
+		return nil, err
+	}
+	pragma := source.Pragma(string(data))
+	if pragma == "" {
+		pragma = "^0.8.0"
+	}
+	v := struct {
+		InvariantSuite
+		Marker   string
+		Pragma   string
+		Import   string
+		CtorArgs string
+	}{InvariantSuite: s, Marker: GeneratedMarker, Pragma: pragma, Import: importPath}
+	for _, fn := range s.Contract.Functions {
+		if fn.Name == "constructor" {
+			v.CtorArgs = arguments(fn.Params, "address(this)")
+		}
+	}
+
+	var out bytes.Buffer
+	if err := invariantTemplate.Execute(&out, v); err != nil {
+		return nil, fmt.Errorf("rendering invariants: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+var invariantTemplate = template.Must(template.New("invariants").Parse(`{{.Marker}}
+// SPDX-License-Identifier: UNLICENSED
+pragma solidity {{.Pragma}};
+
+// Invariant test stubs suggested from the surface of {{.Contract.Name}}.
+// They are starting points: check every assumption against the protocol's
+// intended behaviour and fill in the TODOs, then run:
+//   forge test --match-contract {{.Contract.Name}}Invariants -vvv
+
+import {Test} from "forge-std/Test.sol";
+import { {{- .Contract.Name -}} } from "{{.Import}}";
+
+contract {{.Contract.Name}}Invariants is Test {
+    {{.Contract.Name}} target;
+    address[] actors;
+{{- range .Snapshots}}
+    address initial_{{.}};
+{{- end}}
+
+    function setUp() public {
+        target = new {{.Contract.Name}}({{.CtorArgs}});
+        for (uint256 i = 0; i < 3; i++) {
+            actors.push(makeAddr(string.concat("actor", vm.toString(i))));
+            targetSender(actors[i]);
+        }
+        // TODO: fund the actors and seed realistic state (deposits, mints)
+        // so the fuzzer starts from a meaningful position.
+{{- range .Snapshots}}
+        initial_{{.}} = target.{{.}}();
+{{- end}}
+        targetContract(address(target));
+    }
+{{- if .Restricted}}
+
+    // Actors hold no privileges, so calls to the guarded functions
+    // ({{range $i, $f := .Restricted}}{{if $i}}, {{end}}{{$f}}{{end}}) should always revert for them.
+{{- end}}
+{{- range .Invariants}}
+
+    /// {{.Description}}
+    function {{.Name}}() public view {
+{{- range .Body}}
+        {{.}}
+{{- end}}
+    }
+{{- end}}
+}
+`))
@@ -0,0 +1,84 @@
+package poc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/solidity"
+)
+
+const vault = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+abstract contract Base {
+    address public owner;
+}
+
+contract Vault {
+    address public owner;
+    uint256 public totalSupply;
+    mapping(address=>uint256) public balances;
+
+    constructor(address admin) { owner = admin; }
+
+    function deposit() external payable {
+        balances[msg.sender] += msg.value;
+        totalSupply += msg.value;
+    }
+
+    function sweep(address to) external onlyOwner nonReentrant {}
+}
+
+interface IVault {
+    function deposit() external payable;
+}
+`
+
+func TestSuggestInvariants(t *testing.T) {
+	suites := SuggestInvariants([]*solidity.File{solidity.Parse("Vault.sol", vault)})
+	require.Len(t, suites, 1, "abstract contracts and interfaces are not deployable")
+
+	s := suites[0]
+	assert.Equal(t, "Vault", s.Contract.Name)
+	var names []string
+	for _, inv := range s.Invariants {
+		names = append(names, inv.Name)
+	}
+	assert.Equal(t, []string{"invariant_balancesWithinTotalSupply", "invariant_solvent", "invariant_ownerUnchanged"}, names)
+	assert.Equal(t, []string{"sweep"}, s.Restricted)
+	assert.Equal(t, []string{"owner"}, s.Snapshots)
+}
+
+func TestSuggestInvariants_NoSurface(t *testing.T) {
+	src := "pragma solidity ^0.8.0;\ncontract Math {\n    function add(uint a, uint b) public pure returns (uint) { return a + b; }\n}\n"
+	assert.Empty(t, SuggestInvariants([]*solidity.File{solidity.Parse("Math.sol", src)}))
+}
+
+func TestRenderInvariants(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "src", "Vault.sol")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(vault), 0644))
+	f, err := solidity.ParseFile(path)
+	require.NoError(t, err)
+	suites := SuggestInvariants([]*solidity.File{f})
+	require.Len(t, suites, 1)
+
+	out := InvariantPath(dir, "Vault")
+	assert.Equal(t, filepath.Join(dir, "test", "invariants", "VaultInvariants.t.sol"), out)
+	code, err := RenderInvariants(suites[0], out)
+	require.NoError(t, err)
+	text := string(code)
+
+	assert.True(t, len(text) > 0 && text[:len(GeneratedMarker)] == GeneratedMarker)
+	assert.Contains(t, text, `import {Vault} from "../../src/Vault.sol";`)
+	assert.Contains(t, text, "target = new Vault(address(this));")
+	assert.Contains(t, text, "initial_owner = target.owner();")
+	assert.Contains(t, text, "sum += target.balances(actors[i]);")
+	assert.Contains(t, text, "assertGe(address(target).balance, owed);")
+	assert.Contains(t, text, "function invariant_ownerUnchanged() public view {")
+	assert.Contains(t, text, "(sweep) should always revert")
+}
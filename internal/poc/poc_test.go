@@ -0,0 +1,110 @@
+package poc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+const token = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Token {
+    mapping(address => uint256) public balanceOf;
+
+    constructor(address admin, uint256 supply) {}
+
+    function mint(address to, uint256 amount) public {
+        balanceOf[to] += amount;
+    }
+
+    function withdraw(uint256 amount) external payable {
+        (bool ok, ) = msg.sender.call{value: amount}("");
+        balanceOf[msg.sender] -= amount;
+    }
+}
+`
+
+func writeToken(t *testing.T) string {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "src", "Token.sol")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(token), 0644))
+	return path
+}
+
+func TestGenerate_AccessControl(t *testing.T) {
+	path := writeToken(t)
+	f := &parser.Finding{ID: "A-1", Fingerprint: "abc", Check: "custom-missing-access-control", Title: "Missing Access Control",
+		File: path, Lines: []int{9}, Contract: "Token", Function: "mint"}
+	out := filepath.Join(filepath.Dir(path), "..", "test", "poc", "Mint.t.sol")
+
+	code, err := Generate(f, out)
+	require.NoError(t, err)
+	text := string(code)
+
+	assert.Contains(t, text, "pragma solidity ^0.8.20;")
+	assert.Contains(t, text, `import {Token} from "../../src/Token.sol";`)
+	assert.Contains(t, text, "contract TokenAccessControlPoC is Test {")
+	assert.Contains(t, text, "target = new Token(address(this), 1 ether);")
+	assert.Contains(t, text, "target.mint(attacker, 1 ether);")
+}
+
+func TestGenerate_Reentrancy(t *testing.T) {
+	path := writeToken(t)
+	f := &parser.Finding{ID: "R-1", Check: "reentrancy-eth", File: path, Lines: []int{14}, Contract: "Token", Function: "withdraw"}
+
+	code, err := Generate(f, filepath.Join(filepath.Dir(path), "PoC.t.sol"))
+	require.NoError(t, err)
+	text := string(code)
+
+	assert.Contains(t, text, `import {Token} from "./Token.sol";`)
+	assert.Contains(t, text, "contract TokenReentrancyAttacker {")
+	assert.Contains(t, text, "target.withdraw{value: msg.value}(1 ether);")
+	assert.Contains(t, text, "function test_exploit_withdraw_reentrancy()")
+}
+
+func TestGenerate_Unsupported(t *testing.T) {
+	path := writeToken(t)
+	_, err := Generate(&parser.Finding{Check: "timestamp", File: path, Contract: "Token", Function: "mint"}, "x.t.sol")
+	assert.ErrorContains(t, err, "no PoC template")
+
+	_, err = Generate(&parser.Finding{ID: "A-1", Check: "suicidal", File: path}, "x.t.sol")
+	assert.ErrorContains(t, err, "not located inside a contract function")
+}
+
+func TestGenerateFork(t *testing.T) {
+	path := writeToken(t)
+	f := &parser.Finding{ID: "A-1", Fingerprint: "abc", Check: "custom-missing-access-control", File: path,
+		Lines: []int{9}, Contract: "Token", Function: "mint"}
+	out := filepath.Join(filepath.Dir(path), "..", "test", "solsec-validate", "Token_mint.t.sol")
+
+	code, err := GenerateFork(f, out, "0x6b175474e89094c44da98b954eedeac495271d0f", 19000000)
+	require.NoError(t, err)
+	text := string(code)
+
+	assert.Contains(t, text, "contract Token_mintFork is Test {")
+	assert.Contains(t, text, `vm.createSelectFork(vm.envString("SOLSEC_FORK_RPC"), 19000000);`)
+	assert.Contains(t, text, `target = Token(payable(vm.parseAddress("0x6b175474e89094c44da98b954eedeac495271d0f")));`)
+	assert.Contains(t, text, "target.mint(attacker, 1 ether);")
+	assert.NotContains(t, text, "new Token(")
+
+	code, err = GenerateFork(f, out, "0x6b175474e89094c44da98b954eedeac495271d0f", 0)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), `vm.createSelectFork(vm.envString("SOLSEC_FORK_RPC"));`)
+
+	_, err = GenerateFork(&parser.Finding{Check: "reentrancy-eth", File: path, Contract: "Token", Function: "withdraw"}, out, "0x0", 0)
+	assert.ErrorContains(t, err, "no fork test")
+}
+
+func TestPlaceholder(t *testing.T) {
+	assert.Equal(t, "attacker", placeholder("address", "attacker"))
+	assert.Equal(t, "uint8(1)", placeholder("uint8", "x"))
+	assert.Equal(t, "bytes32(0)", placeholder("bytes32", "x"))
+	assert.Equal(t, `""`, placeholder("string", "x"))
+	assert.Equal(t, "/* TODO: uint256[] */", placeholder("uint256[]", "x"))
+}
@@ -0,0 +1,454 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// An expression is parsed into a tree of nodes, evaluated against an Env.
+// Values are float64, string, bool, object (fields that must exist) or
+// counts (keys that default to 0).
+type node interface {
+	eval(env Env, all bool) (any, error)
+}
+
+type (
+	literal struct{ value any }
+	ident   struct{ name string }
+	member  struct {
+		target node
+		field  string
+	}
+	index struct {
+		target node
+		key    node
+	}
+	unary struct {
+		op      string
+		operand node
+	}
+	binary struct {
+		op          string
+		left, right node
+	}
+)
+
+// object holds named values; reading a missing field is an error, so a
+// typo like summary.critcal is caught instead of comparing against zero.
+type object map[string]any
+
+// counts holds per-key finding counts; a key no finding has counts 0.
+type counts map[string]float64
+
+// token is a lexeme and the 1-based column it starts at.
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+	col   int
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+// operators are matched longest first.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", "."}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' || src[j] == '_') {
+				j++
+			}
+			n, err := strconv.ParseFloat(strings.ReplaceAll(src[i:j], "_", ""), 64)
+			if err != nil {
+				return nil, fmt.Errorf("column %d: invalid number %q", i+1, src[i:j])
+			}
+			tokens = append(tokens, token{tokNumber, src[i:j], n, i + 1})
+			i = j
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("column %d: unterminated string", i+1)
+			}
+			// Single-quoted strings are taken as written.
+			s := src[i+1 : j]
+			if c == '"' {
+				var err error
+				if s, err = strconv.Unquote(src[i : j+1]); err != nil {
+					return nil, fmt.Errorf("column %d: invalid string %s", i+1, src[i:j+1])
+				}
+			}
+			tokens = append(tokens, token{tokString, src[i : j+1], s, i + 1})
+			i = j + 1
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || src[j] >= '0' && src[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[i:j], col: i + 1})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("column %d: unexpected %q", i+1, c)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, col: i + 1})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, col: len(src) + 1}), nil
+}
+
+// exprParser is a precedence-climbing parser over the tokens of one expression.
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+// precedence ranks the binary operators; higher binds tighter.
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	n, err := p.expr(1)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("column %d: unexpected %q", t.col, t.text)
+	}
+	return n, nil
+}
+
+func (p *exprParser) peek() token { return p.tokens[p.pos] }
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) expect(op string) error {
+	if t := p.next(); t.kind != tokOp || t.text != op {
+		return unexpected(t, "expected "+op)
+	}
+	return nil
+}
+
+func (p *exprParser) expr(minPrec int) (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		prec, ok := precedence[t.text]
+		if t.kind != tokOp || !ok || prec < minPrec {
+			return left, nil
+		}
+		p.next()
+		right, err := p.expr(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binary{t.text, left, right}
+	}
+}
+
+func (p *exprParser) unary() (node, error) {
+	if t := p.peek(); t.kind == tokOp && (t.text == "!" || t.text == "-") {
+		p.next()
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{t.text, operand}, nil
+	}
+	return p.postfix()
+}
+
+func (p *exprParser) postfix() (node, error) {
+	n, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokOp && t.text == ".":
+			p.next()
+			field := p.next()
+			if field.kind != tokIdent {
+				return nil, unexpected(field, "expected a field name")
+			}
+			n = member{n, field.text}
+		case t.kind == tokOp && t.text == "[":
+			p.next()
+			key, err := p.expr(1)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = index{n, key}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *exprParser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber, tokString:
+		return literal{t.value}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		}
+		return ident{t.text}, nil
+	case tokOp:
+		if t.text == "(" {
+			n, err := p.expr(1)
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		}
+	}
+	return nil, unexpected(t, "expected a value")
+}
+
+func unexpected(t token, want string) error {
+	if t.kind == tokEOF {
+		return fmt.Errorf("column %d: unexpected end of expression, %s", t.col, want)
+	}
+	return fmt.Errorf("column %d: unexpected %q, %s", t.col, t.text, want)
+}
+
+func (n literal) eval(Env, bool) (any, error) { return n.value, nil }
+
+func (n ident) eval(env Env, _ bool) (any, error) {
+	v, ok := env[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable %q", n.name)
+	}
+	return v, nil
+}
+
+func (n member) eval(env Env, all bool) (any, error) {
+	target, err := n.target.eval(env, all)
+	if err != nil {
+		return nil, err
+	}
+	return lookup(target, n.field)
+}
+
+func (n index) eval(env Env, all bool) (any, error) {
+	target, err := n.target.eval(env, all)
+	if err != nil {
+		return nil, err
+	}
+	key, err := n.key.eval(env, all)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := key.(string)
+	if !ok {
+		return nil, fmt.Errorf("index must be a string, got %s", typeName(key))
+	}
+	return lookup(target, s)
+}
+
+func lookup(target any, key string) (any, error) {
+	switch t := target.(type) {
+	case object:
+		v, ok := t[key]
+		if !ok {
+			return nil, fmt.Errorf("no field %q", key)
+		}
+		return v, nil
+	case counts:
+		return t[key], nil
+	}
+	return nil, fmt.Errorf("cannot select %q from %s", key, typeName(target))
+}
+
+func (n unary) eval(env Env, all bool) (any, error) {
+	v, err := n.operand.eval(env, all)
+	if err != nil {
+		return nil, err
+	}
+	switch x := v.(type) {
+	case bool:
+		if n.op == "!" {
+			return !x, nil
+		}
+	case float64:
+		if n.op == "-" {
+			return -x, nil
+		}
+	}
+	return nil, fmt.Errorf("operator %s does not apply to %s", n.op, typeName(v))
+}
+
+// eval evaluates the operands left to right. && and || skip the right
+// operand once the left decides the result, unless all is set, which
+// Check uses to reach every branch.
+func (n binary) eval(env Env, all bool) (any, error) {
+	left, err := n.left.eval(env, all)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s needs bool operands, got %s", n.op, typeName(left))
+		}
+		if !all && l == (n.op == "||") {
+			return l, nil
+		}
+		right, err := n.right.eval(env, all)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s needs bool operands, got %s", n.op, typeName(right))
+		}
+		if n.op == "||" {
+			return l || r, nil
+		}
+		return l && r, nil
+	}
+
+	right, err := n.right.eval(env, all)
+	if err != nil {
+		return nil, err
+	}
+	mismatch := fmt.Errorf("operator %s does not apply to %s and %s", n.op, typeName(left), typeName(right))
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, mismatch
+		}
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		case ">=":
+			return l >= r, nil
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/":
+			return l / r, nil
+		case "%":
+			return math.Mod(l, r), nil
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, mismatch
+		}
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		case ">=":
+			return l >= r, nil
+		case "+":
+			return l + r, nil
+		}
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			return nil, mismatch
+		}
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		}
+	}
+	return nil, mismatch
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case object, counts:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,92 @@
+// Package policy evaluates the gate rules of .solsec.yaml: expressions such
+// as `summary.critical == 0 && score < 25` that decide whether a run passes.
+// The syntax is a small subset of CEL: numbers, 'strings' and "strings",
+// true and false, the operators || && == != < <= > >= + - * / % ! and
+// parentheses, field access (summary.high) and indexing (rules["x"]).
+package policy
+
+import (
+	"fmt"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/scorer"
+)
+
+// Rule is a named gate expression; the run passes the rule when it
+// evaluates to true.
+type Rule struct {
+	Name string `json:"name,omitempty"`
+	Expr string `json:"expr"`
+
+	program node
+}
+
+// Env holds the variables a rule can read.
+type Env map[string]any
+
+// Compile parses expr and checks it against an empty report, so unknown
+// variables and fields, type errors and non-bool results are reported
+// before any analysis runs.
+func Compile(name, expr string) (Rule, error) {
+	program, err := parse(expr)
+	if err != nil {
+		return Rule{}, err
+	}
+	r := Rule{Name: name, Expr: expr, program: program}
+	if _, err := r.eval(NewEnv(&parser.AnalysisReport{}, 0), true); err != nil {
+		return Rule{}, err
+	}
+	return r, nil
+}
+
+// Eval reports whether env satisfies the rule.
+func (r Rule) Eval(env Env) (bool, error) {
+	return r.eval(env, false)
+}
+
+func (r Rule) eval(env Env, all bool) (bool, error) {
+	v, err := r.program.eval(env, all)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression must be a bool, got %s", typeName(v))
+	}
+	return b, nil
+}
+
+// NewEnv exposes a report to rules:
+//
+//	summary.total, summary.critical ... summary.optimization  finding counts
+//	score, grade                                               risk score and grade
+//	rules["reentrancy-eth"]                                    findings per check
+//	tags["defi"]                                               findings per tag
+//
+// rules and tags count 0 for checks and tags no finding has. Like summary,
+// they count findings clustered under others.
+func NewEnv(report *parser.AnalysisReport, score int) Env {
+	s := report.Summary
+	byCheck, byTag := counts{}, counts{}
+	for _, f := range parser.Flatten(report.Findings) {
+		byCheck[f.Check]++
+		for _, t := range f.Tags {
+			byTag[t]++
+		}
+	}
+	return Env{
+		"summary": object{
+			"total":         float64(s.Total),
+			"critical":      float64(s.Critical),
+			"high":          float64(s.High),
+			"medium":        float64(s.Medium),
+			"low":           float64(s.Low),
+			"informational": float64(s.Informational),
+			"optimization":  float64(s.Optimization),
+		},
+		"score": float64(score),
+		"grade": scorer.Grade(score),
+		"rules": byCheck,
+		"tags":  byTag,
+	}
+}
@@ -0,0 +1,58 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestRule_Eval(t *testing.T) {
+	report := &parser.AnalysisReport{
+		Summary: parser.Summary{Total: 3, High: 1, Medium: 2},
+		Findings: []parser.Finding{
+			{Check: "reentrancy-eth", Severity: parser.SeverityHigh, Tags: []string{"reentrancy", "defi"}},
+			{Check: "timestamp", Severity: parser.SeverityMedium},
+			{Check: "timestamp", Severity: parser.SeverityMedium},
+		},
+	}
+	env := NewEnv(report, 20)
+
+	for expr, want := range map[string]bool{
+		"summary.critical == 0 && score < 25":           true,
+		"summary.high + summary.medium <= 2":            false,
+		"!(summary.high > 0) || grade == 'B'":           true,
+		`rules["timestamp"] < 3 && tags['defi'] == 1`:   true,
+		`rules["unused-return"] == 0`:                   true,
+		"summary.medium / summary.total > 0.5":          true,
+		"-score < -10 && 7 % 4 == 3":                    true,
+		`summary["informational"] == 0 != false`:        true,
+		"summary.critical > 0 || summary.high >= 1_000": false,
+	} {
+		r, err := Compile("", expr)
+		require.NoError(t, err, expr)
+		got, err := r.Eval(env)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, got, expr)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for expr, msg := range map[string]string{
+		"summary.critical == ":            "column 21: unexpected end of expression",
+		"score < 25 )":                    `column 12: unexpected ")"`,
+		"score # 2":                       "column 7: unexpected '#'",
+		"summary.critcal == 0":            `no field "critcal"`,
+		"scroe < 25":                      `unknown variable "scroe"`,
+		"score":                           "expression must be a bool, got number",
+		"grade < 3":                       "operator < does not apply to string and number",
+		"summary.high == 0 || rules.x":    "operator || needs bool operands, got number",
+		`"unterminated`:                   "column 1: unterminated string",
+		"summary.critical == 0 && !score": "operator ! does not apply to number",
+	} {
+		_, err := Compile("", expr)
+		require.Error(t, err, expr)
+		assert.Contains(t, err.Error(), msg, expr)
+	}
+}
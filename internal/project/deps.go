@@ -0,0 +1,108 @@
+package project
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MissingDependencies lists the dependencies the project rooted at root
+// declares but has not installed: git submodules (Foundry's lib/), remapping
+// targets, and node_modules for package.json dependencies. Building without
+// them needs the network.
+func MissingDependencies(root string) []string {
+	var missing []string
+	for _, path := range submodulePaths(root) {
+		if isEmptyDir(filepath.Join(root, path)) {
+			missing = append(missing, fmt.Sprintf("submodule %s is not checked out (git submodule update --init)", path))
+		}
+	}
+	for _, r := range remappings(root) {
+		if _, err := os.Stat(filepath.Join(root, r.target)); err != nil {
+			missing = append(missing, fmt.Sprintf("remapping %s points to missing %s", r.prefix, r.target))
+		}
+	}
+	if hasNodeDependencies(root) && isEmptyDir(filepath.Join(root, "node_modules")) {
+		missing = append(missing, "package.json dependencies are not installed (npm install)")
+	}
+	return missing
+}
+
+// submodulePaths reads the path = entries of .gitmodules.
+func submodulePaths(root string) []string {
+	var paths []string
+	forEachLine(filepath.Join(root, ".gitmodules"), func(line string) {
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "path" {
+			paths = append(paths, strings.TrimSpace(value))
+		}
+	})
+	return paths
+}
+
+type remapping struct{ prefix, target string }
+
+// remappings reads remappings.txt. Targets inside a submodule that is itself
+// missing are left to the submodule entry.
+func remappings(root string) []remapping {
+	var out []remapping
+	submodules := submodulePaths(root)
+	forEachLine(filepath.Join(root, "remappings.txt"), func(line string) {
+		prefix, target, ok := strings.Cut(line, "=")
+		if !ok {
+			return
+		}
+		// Drop an optional "context:" before the prefix.
+		if _, p, found := strings.Cut(prefix, ":"); found {
+			prefix = p
+		}
+		target = filepath.Clean(strings.TrimSpace(target))
+		for _, s := range submodules {
+			if strings.HasPrefix(target, filepath.Clean(s)) && isEmptyDir(filepath.Join(root, s)) {
+				return
+			}
+		}
+		out = append(out, remapping{prefix: strings.TrimSpace(prefix), target: target})
+	})
+	return out
+}
+
+func hasNodeDependencies(root string) bool {
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return false
+	}
+	return len(pkg.Dependencies)+len(pkg.DevDependencies) > 0
+}
+
+// forEachLine calls fn with every non-blank, non-comment line of path, if it
+// exists.
+func forEachLine(path string, fn func(line string)) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		fn(line)
+	}
+}
+
+func isEmptyDir(path string) bool {
+	entries, err := os.ReadDir(path)
+	return err != nil || len(entries) == 0
+}
@@ -0,0 +1,32 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingDependencies(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	assert.Empty(t, MissingDependencies(dir))
+
+	write(".gitmodules", "[submodule \"lib/forge-std\"]\n\tpath = lib/forge-std\n\turl = https://github.com/foundry-rs/forge-std\n"+
+		"[submodule \"lib/solmate\"]\n\tpath = lib/solmate\n\turl = https://github.com/transmissions11/solmate\n")
+	write("lib/solmate/src/tokens/ERC20.sol", "")
+	write("remappings.txt", "forge-std/=lib/forge-std/src/\nsolmate/=lib/solmate/src/\n@oz/=node_modules/@openzeppelin/contracts/\n")
+	write("package.json", `{"devDependencies": {"@openzeppelin/contracts": "^5.0.0"}}`)
+
+	assert.Equal(t, []string{
+		"submodule lib/forge-std is not checked out (git submodule update --init)",
+		"remapping @oz/ points to missing " + filepath.Join("node_modules", "@openzeppelin", "contracts"),
+		"package.json dependencies are not installed (npm install)",
+	}, MissingDependencies(dir))
+}
@@ -0,0 +1,184 @@
+// Package project reads build settings from a Solidity project's framework
+// configuration (foundry.toml, hardhat.config.js/ts).
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Settings are the compiler settings a project builds with.
+type Settings struct {
+	Framework  string // "foundry" or "hardhat"
+	ConfigFile string
+
+	// SolcVersions lists the configured compiler versions. Empty means the
+	// framework picks one automatically from the pragmas.
+	SolcVersions []string
+
+	OptimizerEnabled bool
+	OptimizerRuns    int // -1 when not configured
+	ViaIR            bool
+
+	// Lines maps a setting ("solc", "optimizer_runs", "via_ir") to the line
+	// of the config file that sets it, for precise findings.
+	Lines map[string]int
+}
+
+// Load reads the settings of the project rooted at root. It returns nil and
+// no error when the directory has no recognized framework configuration.
+func Load(root string) (*Settings, error) {
+	if path := filepath.Join(root, "foundry.toml"); fileExists(path) {
+		return loadFoundry(path)
+	}
+	for _, name := range []string{"hardhat.config.ts", "hardhat.config.js"} {
+		if path := filepath.Join(root, name); fileExists(path) {
+			return loadHardhat(path)
+		}
+	}
+	return nil, nil
+}
+
+// foundryProfile is the subset of a foundry.toml profile solsec reads.
+type foundryProfile struct {
+	Solc          string `toml:"solc"`
+	SolcVersion   string `toml:"solc_version"`
+	Optimizer     *bool  `toml:"optimizer"`
+	OptimizerRuns *int   `toml:"optimizer_runs"`
+	ViaIR         bool   `toml:"via_ir"`
+}
+
+func loadFoundry(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Profile map[string]foundryProfile `toml:"profile"`
+	}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	// The active profile inherits unset keys from the default profile.
+	p := doc.Profile["default"]
+	if name := os.Getenv("FOUNDRY_PROFILE"); name != "" && name != "default" {
+		if active, ok := doc.Profile[name]; ok {
+			if active.Solc != "" || active.SolcVersion != "" {
+				p.Solc, p.SolcVersion = active.Solc, active.SolcVersion
+			}
+			if active.Optimizer != nil {
+				p.Optimizer = active.Optimizer
+			}
+			if active.OptimizerRuns != nil {
+				p.OptimizerRuns = active.OptimizerRuns
+			}
+			p.ViaIR = p.ViaIR || active.ViaIR
+		}
+	}
+
+	s := &Settings{
+		Framework:        "foundry",
+		ConfigFile:       path,
+		OptimizerEnabled: false, // foundry's default since 1.0
+		OptimizerRuns:    -1,
+		ViaIR:            p.ViaIR,
+		Lines:            keyLines(string(data), "solc", "solc_version", "optimizer_runs", "via_ir"),
+	}
+	if v := firstNonEmpty(p.SolcVersion, p.Solc); v != "" && !strings.ContainsAny(v, "/\\") {
+		s.SolcVersions = []string{v}
+	}
+	if s.Lines["solc"] == 0 {
+		s.Lines["solc"] = s.Lines["solc_version"]
+	}
+	if p.Optimizer != nil {
+		s.OptimizerEnabled = *p.Optimizer
+	}
+	if p.OptimizerRuns != nil {
+		s.OptimizerRuns = *p.OptimizerRuns
+	}
+	return s, nil
+}
+
+var (
+	hardhatVersionRe = regexp.MustCompile(`(?:\bversion|\bsolidity)\s*:\s*["'](\d+\.\d+\.\d+)["']`)
+	hardhatRunsRe    = regexp.MustCompile(`\bruns\s*:\s*(\d+)`)
+	hardhatEnabledRe = regexp.MustCompile(`\benabled\s*:\s*(true|false)`)
+	hardhatViaIRRe   = regexp.MustCompile(`\bviaIR\s*:\s*true`)
+)
+
+// loadHardhat extracts compiler settings from a Hardhat config. The config is
+// JavaScript, so this is a best-effort scan of literal values.
+func loadHardhat(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+	s := &Settings{
+		Framework:     "hardhat",
+		ConfigFile:    path,
+		OptimizerRuns: -1,
+		Lines:         map[string]int{},
+	}
+	for _, m := range hardhatVersionRe.FindAllStringSubmatchIndex(text, -1) {
+		s.SolcVersions = append(s.SolcVersions, text[m[2]:m[3]])
+		if s.Lines["solc"] == 0 {
+			s.Lines["solc"] = lineAt(text, m[0])
+		}
+	}
+	if m := hardhatRunsRe.FindStringSubmatchIndex(text); m != nil {
+		s.OptimizerRuns, _ = strconv.Atoi(text[m[2]:m[3]])
+		s.Lines["optimizer_runs"] = lineAt(text, m[0])
+	}
+	if m := hardhatEnabledRe.FindStringSubmatch(text); m != nil {
+		s.OptimizerEnabled = m[1] == "true"
+	}
+	if m := hardhatViaIRRe.FindStringIndex(text); m != nil {
+		s.ViaIR = true
+		s.Lines["via_ir"] = lineAt(text, m[0])
+	}
+	return s, nil
+}
+
+// keyLines finds the first line assigning each TOML key.
+func keyLines(text string, keys ...string) map[string]int {
+	lines := map[string]int{}
+	for i, line := range strings.Split(text, "\n") {
+		k, _, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		k = strings.TrimSpace(k)
+		for _, want := range keys {
+			if k == want && lines[want] == 0 {
+				lines[want] = i + 1
+			}
+		}
+	}
+	return lines
+}
+
+func lineAt(text string, offset int) int {
+	return strings.Count(text[:offset], "\n") + 1
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Foundry(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foundry.toml"), []byte(`[profile.default]
+src = "src"
+solc = "0.8.24"
+optimizer = true
+optimizer_runs = 200
+
+[profile.ci]
+via_ir = true
+`), 0644))
+
+	s, err := Load(dir)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, "foundry", s.Framework)
+	assert.Equal(t, []string{"0.8.24"}, s.SolcVersions)
+	assert.True(t, s.OptimizerEnabled)
+	assert.Equal(t, 200, s.OptimizerRuns)
+	assert.Equal(t, 5, s.Lines["optimizer_runs"])
+	assert.False(t, s.ViaIR)
+
+	t.Setenv("FOUNDRY_PROFILE", "ci")
+	s, err = Load(dir)
+	require.NoError(t, err)
+	assert.True(t, s.ViaIR)
+	assert.Equal(t, []string{"0.8.24"}, s.SolcVersions)
+}
+
+func TestLoad_Hardhat(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hardhat.config.ts"), []byte(`export default {
+  solidity: {
+    version: "0.8.20",
+    settings: { optimizer: { enabled: true, runs: 5000000 }, viaIR: true },
+  },
+};
+`), 0644))
+
+	s, err := Load(dir)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, []string{"0.8.20"}, s.SolcVersions)
+	assert.True(t, s.OptimizerEnabled)
+	assert.Equal(t, 5000000, s.OptimizerRuns)
+	assert.True(t, s.ViaIR)
+}
+
+func TestLoad_NoConfig(t *testing.T) {
+	s, err := Load(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, s)
+}
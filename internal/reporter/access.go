@@ -0,0 +1,50 @@
+package reporter
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// accessTable is the access-control matrix laid out for HTML: one column
+// per guard (parser.Anyone first), one row per function.
+type accessTable struct {
+	Guards []string
+	Rows   []accessRow
+}
+
+type accessRow struct {
+	parser.AccessEntry
+	Cells []bool // whether each guard in accessTable.Guards applies
+}
+
+func buildAccessTable(report *parser.AnalysisReport) *accessTable {
+	if len(report.AccessMatrix) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	t := &accessTable{}
+	for _, e := range report.AccessMatrix {
+		for _, g := range e.CallableBy {
+			if !seen[g] {
+				seen[g] = true
+				t.Guards = append(t.Guards, g)
+			}
+		}
+	}
+	sort.Slice(t.Guards, func(i, j int) bool {
+		if (t.Guards[i] == parser.Anyone) != (t.Guards[j] == parser.Anyone) {
+			return t.Guards[i] == parser.Anyone
+		}
+		return t.Guards[i] < t.Guards[j]
+	})
+	for _, e := range report.AccessMatrix {
+		row := accessRow{AccessEntry: e, Cells: make([]bool, len(t.Guards))}
+		for i, g := range t.Guards {
+			row.Cells[i] = slices.Contains(e.CallableBy, g)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
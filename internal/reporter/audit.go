@@ -0,0 +1,441 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// auditTODO marks the parts of the audit report an auditor must write.
+const auditTODO = "> **TODO:**"
+
+// auditPrefixes are the severity letters of audit finding IDs, e.g. H-01.
+var auditPrefixes = map[parser.Severity]string{
+	parser.SeverityCritical:      "C",
+	parser.SeverityHigh:          "H",
+	parser.SeverityMedium:        "M",
+	parser.SeverityLow:           "L",
+	parser.SeverityInformational: "I",
+	parser.SeverityOptimization:  "G",
+}
+
+// engineNames describe the engines of AnalyzedFile.Engines for the
+// methodology.
+var engineNames = map[string]string{
+	"custom":  "its custom checks",
+	"slither": "Slither",
+	"mythril": "Mythril",
+}
+
+// severityMeanings define the severities for the audit's readers, in the
+// terms the ratings use: impact and likelihood.
+var severityMeanings = []struct {
+	Severity parser.Severity
+	Meaning  string
+}{
+	{parser.SeverityCritical, "Direct loss of funds or control of the contracts, exploitable by anyone with little effort."},
+	{parser.SeverityHigh, "Loss of funds or broken core functionality under realistic conditions."},
+	{parser.SeverityMedium, "Loss or disruption that needs specific conditions, privileged mistakes or unlikely timing."},
+	{parser.SeverityLow, "Limited impact, or deviations from best practice that could become exploitable."},
+	{parser.SeverityInformational, "Code quality, readability and documentation; no direct risk."},
+	{parser.SeverityOptimization, "Gas savings that do not change behavior."},
+}
+
+// AuditReporter writes a Markdown audit report skeleton: the executive
+// summary, scope, methodology and one section per finding, filled in from
+// the analysis, with TODO markers for what auditors write by hand.
+type AuditReporter struct {
+	Project  string   // defaults to the target's name
+	Auditors []string // listed on the cover; a TODO when empty
+}
+
+func (r *AuditReporter) Name() string { return "md" }
+
+func (r *AuditReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
+	return writeFile(outputPath, "audit report", func(w *bufio.Writer) error {
+		a := &auditWriter{w: w, report: report, score: score, excerpts: newSnippets()}
+		a.cover(r.project(report), r.Auditors)
+		a.summary()
+		a.scope()
+		a.methodology()
+		a.findings()
+		a.appendix()
+		return nil
+	})
+}
+
+func (r *AuditReporter) project(report *parser.AnalysisReport) string {
+	if r.Project != "" {
+		return r.Project
+	}
+	if abs, err := filepath.Abs(report.Target); err == nil {
+		return filepath.Base(strings.TrimSuffix(abs, filepath.Ext(abs)))
+	}
+	return report.Target
+}
+
+// auditWriter renders the sections of one audit report.
+type auditWriter struct {
+	w        *bufio.Writer
+	report   *parser.AnalysisReport
+	score    int
+	excerpts *snippets
+}
+
+func (a *auditWriter) printf(format string, args ...any) { fmt.Fprintf(a.w, format, args...) }
+
+func (a *auditWriter) cover(project string, auditors []string) {
+	a.printf("# %s Security Audit\n\n", project)
+	date := time.Now().UTC().Format("2006-01-02")
+	if t, err := time.Parse(time.RFC3339, a.report.GeneratedAt); err == nil {
+		date = t.UTC().Format("2006-01-02")
+	}
+	who := "_TODO_"
+	if len(auditors) > 0 {
+		who = mdCell(strings.Join(auditors, ", "))
+	}
+	a.printf("| | |\n|---|---|\n")
+	a.printf("| Project | %s |\n", mdCell(project))
+	a.printf("| Auditors | %s |\n", who)
+	a.printf("| Date | %s |\n", date)
+	if h := source.DetectCodeHost(a.report.Target); h != nil {
+		a.printf("| Repository | <%s> |\n", h.Web)
+		a.printf("| Commit | `%s` |\n", h.Commit)
+	} else {
+		a.printf("| Commit | _TODO_ |\n")
+	}
+	a.printf("\n")
+}
+
+func (a *auditWriter) summary() {
+	s := a.report.Summary
+	a.printf("## Executive Summary\n\n")
+	a.printf("%s Summarize the engagement, the system's purpose and the overall security posture.\n\n", auditTODO)
+	a.printf("Automated analysis reported %d issue(s) in %d file(s) (%d SLOC). Its risk score is %d/100, grade %s — %s\n\n",
+		s.Total, len(a.report.AnalyzedFiles), a.sloc(), a.score, scorer.Grade(a.score), scorer.Verdict(a.score))
+	a.printf("| Severity | Count |\n|---|---|\n")
+	for _, m := range severityMeanings {
+		a.printf("| %s | %d |\n", m.Severity, countSeverity(s, m.Severity))
+	}
+	a.printf("\n")
+
+	var caveats []string
+	if a.report.Interrupted {
+		caveats = append(caveats, "The analysis was interrupted; the findings are incomplete.")
+	}
+	if n := len(a.report.Degraded); n > 0 {
+		caveats = append(caveats, fmt.Sprintf("%d check(s) crashed or timed out, so their findings are missing.", n))
+	}
+	if a.report.Triaged > 0 {
+		caveats = append(caveats, fmt.Sprintf("%d finding(s) triaged as false positives are left out.", a.report.Triaged))
+	}
+	if a.report.Baselined > 0 {
+		caveats = append(caveats, fmt.Sprintf("%d known finding(s) recorded in the baseline are left out.", a.report.Baselined))
+	}
+	for _, c := range caveats {
+		a.printf("- %s\n", c)
+	}
+	if len(caveats) > 0 {
+		a.printf("\n")
+	}
+}
+
+func (a *auditWriter) scope() {
+	a.printf("## Scope\n\n")
+	a.printf("%s Confirm the scope with the client and describe the system's actors and trust assumptions.\n\n", auditTODO)
+	a.printf("Target: `%s`\n\n", a.report.Target)
+	if sc := a.report.Scope; !sc.Empty() {
+		if len(sc.Contracts) > 0 {
+			a.printf("Restricted to contracts: %s\n\n", mdCodes(sc.Contracts))
+		}
+		if len(sc.Functions) > 0 {
+			a.printf("Restricted to functions: %s\n\n", mdCodes(sc.Functions))
+		}
+	}
+	if len(a.report.AnalyzedFiles) == 0 {
+		return
+	}
+	a.printf("| File | SLOC | SHA-256 |\n|---|---|---|\n")
+	for _, f := range a.report.AnalyzedFiles {
+		note := ""
+		switch {
+		case f.Skipped:
+			note = " (not compiled: " + mdCell(f.SkipReason) + ")"
+		case f.Excluded != "":
+			note = " (excluded from custom checks: " + mdCell(f.Excluded) + ")"
+		}
+		a.printf("| `%s`%s | %d | `%s` |\n", relTo(a.report.Target, f.Path), note, f.SLOC, shortHash(f.SHA256))
+	}
+	a.printf("\n")
+}
+
+func (a *auditWriter) methodology() {
+	a.printf("## Methodology\n\n")
+	a.printf("%s Describe the manual review: time spent, areas of focus and tests written.\n\n", auditTODO)
+	a.printf("The code was analyzed with solsec")
+	var engines []string
+	for _, f := range a.report.AnalyzedFiles {
+		for _, e := range f.Engines {
+			if !slices.Contains(engines, e) {
+				engines = append(engines, e)
+			}
+		}
+	}
+	if len(engines) > 0 {
+		slices.Sort(engines)
+		for i, e := range engines {
+			if name, ok := engineNames[e]; ok {
+				engines[i] = name
+			}
+		}
+		a.printf(", running %s", strings.Join(engines, ", "))
+	}
+	a.printf(". Automated findings were then reviewed by hand; each section below records the auditors' assessment.\n\n")
+	if env := a.report.Environment; env != nil {
+		a.printf("| Tool | Version |\n|---|---|\n")
+		a.printf("| solsec | %s (rules database v%d) |\n", env.Solsec, env.RulesDB)
+		if env.Slither != "" {
+			a.printf("| Slither | %s (%d detectors) |\n", env.Slither, len(env.Detectors))
+		}
+		if env.Solc != "" {
+			a.printf("| solc | %s |\n", env.Solc)
+		}
+		a.printf("\n")
+	}
+	a.printf("### Severity Classification\n\n| Severity | Meaning |\n|---|---|\n")
+	for _, m := range severityMeanings {
+		a.printf("| %s | %s |\n", m.Severity, m.Meaning)
+	}
+	a.printf("\n")
+}
+
+func (a *auditWriter) findings() {
+	a.printf("## Findings\n\n")
+	if len(a.report.Findings) == 0 {
+		a.printf("No issues were found.\n\n")
+		return
+	}
+	ids := auditIDs(a.report.Findings)
+	a.printf("| ID | Title | Severity | Status |\n|---|---|---|---|\n")
+	for i, f := range a.report.Findings {
+		a.printf("| [%s](#%s) | %s | %s | Open |\n", ids[i], strings.ToLower(ids[i]), mdCell(f.Title), f.Severity)
+	}
+	a.printf("\n")
+	for i, f := range a.report.Findings {
+		a.finding(ids[i], f)
+	}
+}
+
+func (a *auditWriter) finding(id string, f parser.Finding) {
+	a.printf("<a id=\"%s\"></a>\n\n### [%s] %s\n\n", strings.ToLower(id), id, f.Title)
+	a.printf("| | |\n|---|---|\n")
+	a.printf("| Severity | %s |\n", f.Severity)
+	a.printf("| Check | `%s` (%s, %s confidence) |\n", f.Check, f.Source, strings.ToLower(f.Confidence))
+	if loc := a.location(f); loc != "" {
+		a.printf("| Location | %s |\n", loc)
+	}
+	a.printf("| Status | Open |\n\n")
+
+	a.printf("#### Description\n\n%s\n\n", strings.TrimSpace(f.Description))
+	if lines, first, last, ok := a.excerpts.excerpt(f); ok {
+		lang := ""
+		if strings.EqualFold(filepath.Ext(f.File), ".sol") {
+			lang = "solidity"
+		}
+		a.printf("```%s\n", lang)
+		for n := first; n <= last; n++ {
+			a.printf("%4d  %s\n", n, lines[n-1])
+		}
+		a.printf("```\n\n")
+	}
+	if len(f.Evidence) > 0 {
+		a.printf("Evidence:\n\n")
+		for _, e := range f.Evidence {
+			a.printf("- %s\n", e)
+		}
+		a.printf("\n")
+	}
+
+	a.printf("#### Severity Rationale\n\n")
+	for _, reason := range severityRationale(f) {
+		a.printf("- %s\n", reason)
+	}
+	a.printf("\n%s Confirm the severity, or adjust it and explain why.\n\n", auditTODO)
+
+	a.printf("#### Recommendation\n\n")
+	if f.Remediation != "" {
+		a.printf("%s\n\n", f.Remediation)
+	} else {
+		a.printf("%s Recommend a fix.\n\n", auditTODO)
+	}
+	if refs := findingRefs(f); len(refs) > 0 {
+		a.printf("#### References\n\n")
+		for _, r := range refs {
+			a.printf("- %s\n", r)
+		}
+		a.printf("\n")
+	}
+	a.printf("#### Client Response\n\n%s Record the client's response and the fix commit.\n\n", auditTODO)
+}
+
+// location names where f is, linked to the code when it can be.
+func (a *auditWriter) location(f parser.Finding) string {
+	if f.File == "" {
+		return ""
+	}
+	loc := relTo(a.report.Target, f.File)
+	if len(f.Lines) > 0 {
+		first, last := slices.Min(f.Lines), slices.Max(f.Lines)
+		loc += fmt.Sprintf("#L%d", first)
+		if last > first {
+			loc += fmt.Sprintf("-L%d", last)
+		}
+	}
+	loc = "`" + loc + "`"
+	if f.CodeURL != "" {
+		loc = "[" + loc + "](" + f.CodeURL + ")"
+	}
+	if f.Contract != "" {
+		decl := f.Contract
+		if f.Function != "" {
+			decl += "." + f.Function
+		}
+		loc += " in `" + decl + "`"
+	}
+	if n := len(f.Related); n > 0 {
+		loc += fmt.Sprintf(", and %d more location(s) with the same root cause", n)
+	}
+	return loc
+}
+
+// severityRationale explains f's rating from what the analysis knows: what
+// the severity means, how sure the engine is and what was observed on chain.
+func severityRationale(f parser.Finding) []string {
+	var reasons []string
+	for _, m := range severityMeanings {
+		if m.Severity == f.Severity {
+			reasons = append(reasons, fmt.Sprintf("Rated %s by %s: %s", f.Severity, f.Source, strings.ToLower(m.Meaning[:1])+m.Meaning[1:]))
+		}
+	}
+	switch strings.ToLower(f.Confidence) {
+	case "high":
+		reasons = append(reasons, "The detector reports high confidence in the match.")
+	case "low":
+		reasons = append(reasons, "The detector reports low confidence; confirm the issue is reachable before keeping this rating.")
+	}
+	switch f.Validation {
+	case parser.ValidationConfirmed:
+		reasons = append(reasons, "The exploit succeeded against a fork of the deployed contract.")
+	case parser.ValidationNotReproducible:
+		reasons = append(reasons, "The exploit reverted against a fork of the deployed contract, which argues for a lower rating.")
+	}
+	if f.Impact != nil && f.Impact.Value > 0 {
+		reasons = append(reasons, fmt.Sprintf("The deployed contract holds %s, which an exploit could reach.", f.Impact))
+	}
+	if n := len(f.Related); n > 0 {
+		reasons = append(reasons, fmt.Sprintf("The same root cause occurs in %d more location(s).", n))
+	}
+	if f.SWCRef != "" || f.CWERef != "" {
+		var refs []string
+		for _, r := range []string{f.SWCRef, f.CWERef} {
+			if r != "" {
+				refs = append(refs, r)
+			}
+		}
+		reasons = append(reasons, "Classified as "+strings.Join(refs, " and ")+".")
+	}
+	return reasons
+}
+
+func findingRefs(f parser.Finding) []string {
+	var refs []string
+	for _, r := range f.References {
+		if r != "" && !slices.Contains(refs, r) {
+			refs = append(refs, r)
+		}
+	}
+	return refs
+}
+
+func (a *auditWriter) appendix() {
+	if len(a.report.Warnings) == 0 && len(a.report.Degraded) == 0 {
+		return
+	}
+	a.printf("## Appendix: Analysis Warnings\n\n")
+	a.printf("Parts of the code the automated analysis did not fully cover, to be reviewed by hand.\n\n")
+	for _, d := range a.report.Degraded {
+		a.printf("- `%s`: %s\n", d.Check, d.Reason)
+	}
+	for _, w := range a.report.Warnings {
+		a.printf("- %s\n", w)
+	}
+	a.printf("\n")
+}
+
+func (a *auditWriter) sloc() int {
+	n := 0
+	for _, f := range a.report.AnalyzedFiles {
+		n += f.SLOC
+	}
+	return n
+}
+
+// auditIDs numbers findings per severity in report order: C-01, H-01, H-02...
+func auditIDs(findings []parser.Finding) []string {
+	counts := map[parser.Severity]int{}
+	ids := make([]string, len(findings))
+	for i, f := range findings {
+		prefix, ok := auditPrefixes[f.Severity]
+		if !ok {
+			prefix = "N"
+		}
+		counts[f.Severity]++
+		ids[i] = fmt.Sprintf("%s-%02d", prefix, counts[f.Severity])
+	}
+	return ids
+}
+
+func countSeverity(s parser.Summary, sev parser.Severity) int {
+	switch sev {
+	case parser.SeverityCritical:
+		return s.Critical
+	case parser.SeverityHigh:
+		return s.High
+	case parser.SeverityMedium:
+		return s.Medium
+	case parser.SeverityLow:
+		return s.Low
+	case parser.SeverityInformational:
+		return s.Informational
+	case parser.SeverityOptimization:
+		return s.Optimization
+	}
+	return 0
+}
+
+// mdCell escapes text for a Markdown table cell.
+func mdCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace(s)
+}
+
+func mdCodes(names []string) string {
+	codes := make([]string, len(names))
+	for i, n := range names {
+		codes[i] = "`" + n + "`"
+	}
+	return strings.Join(codes, ", ")
+}
+
+func shortHash(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,116 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// Code Climate issue format, read by reviewdog, GitLab Code Quality and
+// other annotation tools.
+// https://github.com/codeclimate/platform/blob/master/spec/analyzers/SPEC.md#data-types
+
+type codeClimateIssue struct {
+	Type           string                `json:"type"`
+	CheckName      string                `json:"check_name"`
+	Description    string                `json:"description"`
+	Content        *codeClimateContent   `json:"content,omitempty"`
+	Categories     []string              `json:"categories"`
+	Location       codeClimateLocation   `json:"location"`
+	OtherLocations []codeClimateLocation `json:"other_locations,omitempty"`
+	Severity       string                `json:"severity"`
+	Fingerprint    string                `json:"fingerprint,omitempty"`
+}
+
+type codeClimateContent struct {
+	Body string `json:"body"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// CodeClimateReporter writes a JSON array of Code Climate issues. Clustered
+// occurrences become other_locations of their parent's issue.
+type CodeClimateReporter struct {
+	// RepoRoot, when set, makes paths relative to the repository root,
+	// which reviewdog and GitLab Code Quality match against the diff.
+	// Files outside it keep their path.
+	RepoRoot string
+}
+
+func (r *CodeClimateReporter) Name() string { return "codeclimate" }
+
+func (r *CodeClimateReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
+	issues := []codeClimateIssue{}
+	for _, f := range report.Findings {
+		issue := codeClimateIssue{
+			Type:        "issue",
+			CheckName:   f.Check,
+			Description: f.Title,
+			Categories:  []string{"Security"},
+			Location:    r.locationOf(f),
+			Severity:    codeClimateSeverity(f.Severity),
+			Fingerprint: f.Fingerprint,
+		}
+		if body := codeClimateBody(f); body != "" {
+			issue.Content = &codeClimateContent{Body: body}
+		}
+		for _, rel := range f.Related {
+			issue.OtherLocations = append(issue.OtherLocations, r.locationOf(rel))
+		}
+		issues = append(issues, issue)
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling Code Climate report: %w", err)
+	}
+	return os.WriteFile(outputPath, data, 0640)
+}
+
+func (r *CodeClimateReporter) locationOf(f parser.Finding) codeClimateLocation {
+	path, _ := relativePath(r.RepoRoot, f.File)
+	// Findings without a line (file-level ones) point at the first line.
+	loc := codeClimateLocation{Path: path, Lines: codeClimateLines{Begin: 1, End: 1}}
+	if len(f.Lines) > 0 {
+		loc.Lines = codeClimateLines{Begin: slices.Min(f.Lines), End: slices.Max(f.Lines)}
+	}
+	return loc
+}
+
+// codeClimateBody is the issue's Markdown explanation.
+func codeClimateBody(f parser.Finding) string {
+	body := f.Description
+	if f.Remediation != "" {
+		body += "\n\n**Remediation:** " + f.Remediation
+	}
+	if f.SWCRef != "" {
+		body += "\n\nRef: " + f.SWCRef
+	}
+	return body
+}
+
+func codeClimateSeverity(s parser.Severity) string {
+	switch s {
+	case parser.SeverityCritical:
+		return "blocker"
+	case parser.SeverityHigh:
+		return "critical"
+	case parser.SeverityMedium:
+		return "major"
+	case parser.SeverityLow:
+		return "minor"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,38 @@
+package reporter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeClimateReporter(t *testing.T) {
+	root := t.TempDir()
+	var issues []codeClimateIssue
+	require.NoError(t, json.Unmarshal(writeReport(t, &CodeClimateReporter{RepoRoot: root}, testReport(root)), &issues))
+
+	require.Len(t, issues, 3, "clustered occurrences join their parent's issue")
+	cases := []struct {
+		check    string
+		severity string
+		location codeClimateLocation
+		others   []codeClimateLocation
+	}{
+		{"access-control", "critical", codeClimateLocation{"src/Vault.sol", codeClimateLines{10, 12}},
+			[]codeClimateLocation{{"src/Vault.sol", codeClimateLines{20, 20}}}},
+		{"imported", "minor", codeClimateLocation{"src/Token.sol", codeClimateLines{5, 5}}, nil},
+		{"license", "info", codeClimateLocation{"src/Token.sol", codeClimateLines{1, 1}}, nil},
+	}
+	for i, c := range cases {
+		issue := issues[i]
+		assert.Equal(t, "issue", issue.Type)
+		assert.Equal(t, c.check, issue.CheckName)
+		assert.Equal(t, c.severity, issue.Severity)
+		assert.Equal(t, c.location, issue.Location)
+		assert.Equal(t, c.others, issue.OtherLocations)
+	}
+	assert.Equal(t, "0a1b2c3d4e5f6071", issues[0].Fingerprint)
+	assert.Contains(t, issues[0].Content.Body, "**Remediation:** Add onlyOwner.")
+}
@@ -0,0 +1,54 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// csvHeader names the CSV columns. related_to is the ID of the finding an
+// occurrence was clustered under, empty for top-level findings.
+var csvHeader = []string{"id", "severity", "check", "title", "file", "lines", "swc", "remediation", "related_to"}
+
+// CSVReporter writes one row per finding, for spreadsheets and ticket
+// trackers. Clustered occurrences get rows of their own after their parent.
+type CSVReporter struct{}
+
+func (r *CSVReporter) Name() string { return "csv" }
+
+func (r *CSVReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
+	return writeFile(outputPath, "CSV report", func(w *bufio.Writer) error {
+		cw := csv.NewWriter(w)
+		cw.Write(csvHeader)
+		for _, f := range report.Findings {
+			cw.Write(csvRow(f, ""))
+			for _, rel := range f.Related {
+				cw.Write(csvRow(rel, f.ID))
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+}
+
+func csvRow(f parser.Finding, parent string) []string {
+	lines := make([]string, len(f.Lines))
+	for i, l := range f.Lines {
+		lines[i] = fmt.Sprint(l)
+	}
+	row := []string{
+		f.ID, string(f.Severity), f.Check, f.Title, f.File,
+		strings.Join(lines, ", "), f.SWCRef, f.Remediation, parent,
+	}
+	for i, cell := range row {
+		// Imported findings carry text from other tools; keep spreadsheets
+		// from evaluating any of it as a formula.
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			row[i] = "'" + cell
+		}
+	}
+	return row
+}
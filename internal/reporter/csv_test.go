@@ -0,0 +1,48 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestCSVReporter(t *testing.T) {
+	data := writeReport(t, &CSVReporter{}, testReport(t.TempDir()))
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, rows, 5, "a header and a row per finding, clustered ones included")
+	assert.Equal(t, csvHeader, rows[0])
+	var ids []string
+	for _, row := range rows[1:] {
+		ids = append(ids, row[0])
+	}
+	assert.Equal(t, []string{"SOLSEC-001", "SOLSEC-002", "SOLSEC-003", "SOLSEC-004"}, ids, "clustered occurrences follow their parent")
+	assert.Equal(t, "SOLSEC-001", rows[2][8], "related_to names the parent")
+	assert.Empty(t, rows[1][8])
+	assert.Equal(t, "10, 12", rows[1][5])
+	assert.Equal(t, `'=HYPERLINK("https://example.com")`, rows[3][3])
+}
+
+func TestCSVRow_EscapesFormulas(t *testing.T) {
+	cases := []struct {
+		title, want string
+	}{
+		{"=1+1", "'=1+1"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tx", "'\tx"},
+		{"\rx", "'\rx"},
+		{"a = b", "a = b"},
+		{"", ""},
+	}
+	for _, c := range cases {
+		row := csvRow(parser.Finding{Title: c.title}, "")
+		assert.Equal(t, c.want, row[3], "%q", c.title)
+	}
+}
@@ -0,0 +1,74 @@
+package reporter
+
+import (
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// tagFacet is one entry in the report's tag navigation bar.
+type tagFacet struct {
+	Tag   string
+	Count int
+}
+
+// tagFacets counts findings per tag, most frequent first.
+func tagFacets(report *parser.AnalysisReport) []tagFacet {
+	counts := map[string]int{}
+	for _, f := range report.Findings {
+		for _, t := range f.Tags {
+			counts[t]++
+		}
+	}
+	facets := make([]tagFacet, 0, len(counts))
+	for t, n := range counts {
+		facets = append(facets, tagFacet{Tag: t, Count: n})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Tag < facets[j].Tag
+	})
+	return facets
+}
+
+// findingFacets are the aggregate counts in JSON reports, so dashboards need
+// not recompute them from the findings. Findings are counted like the
+// summary, including those clustered under others. Findings without a
+// file, contract or confidence are left out of that facet.
+type findingFacets struct {
+	Severity   map[string]int `json:"severity"`
+	Check      map[string]int `json:"check"`
+	File       map[string]int `json:"file"`
+	Contract   map[string]int `json:"contract"`
+	Tag        map[string]int `json:"tag"`
+	Confidence map[string]int `json:"confidence"`
+}
+
+func buildFacets(report *parser.AnalysisReport) findingFacets {
+	facets := findingFacets{
+		Severity:   map[string]int{},
+		Check:      map[string]int{},
+		File:       map[string]int{},
+		Contract:   map[string]int{},
+		Tag:        map[string]int{},
+		Confidence: map[string]int{},
+	}
+	count := func(m map[string]int, key string) {
+		if key != "" {
+			m[key]++
+		}
+	}
+	for _, f := range parser.Flatten(report.Findings) {
+		count(facets.Severity, string(f.Severity))
+		count(facets.Check, f.Check)
+		count(facets.File, f.File)
+		count(facets.Contract, f.Contract)
+		count(facets.Confidence, f.Confidence)
+		for _, t := range f.Tags {
+			count(facets.Tag, t)
+		}
+	}
+	return facets
+}
@@ -0,0 +1,29 @@
+package reporter
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONReporter_Facets(t *testing.T) {
+	root := t.TempDir()
+	var out struct {
+		Facets findingFacets `json:"facets"`
+	}
+	require.NoError(t, json.Unmarshal(writeReport(t, &JSONReporter{}, testReport(root)), &out))
+
+	facets := out.Facets
+	assert.Equal(t, map[string]int{"High": 1, "Medium": 1, "Low": 1, "Informational": 1}, facets.Severity)
+	assert.Equal(t, map[string]int{"access-control": 2, "imported": 1, "license": 1}, facets.Check, "clustered findings are counted")
+	assert.Equal(t, map[string]int{
+		filepath.Join(root, "src", "Vault.sol"): 2,
+		filepath.Join(root, "src", "Token.sol"): 2,
+	}, facets.File)
+	assert.Equal(t, map[string]int{"Vault": 2}, facets.Contract, "findings without a contract are left out")
+	assert.Equal(t, map[string]int{"access-control": 2, "token": 1}, facets.Tag)
+	assert.Equal(t, map[string]int{"High": 2, "Medium": 1}, facets.Confidence, "findings without a confidence are left out")
+}
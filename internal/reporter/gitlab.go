@@ -0,0 +1,200 @@
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// GitLab Security Report schema for SAST, shown in merge request security
+// widgets when uploaded as the artifacts:reports:sast of a CI job.
+// https://gitlab.com/gitlab-org/security-products/security-report-schemas
+
+const gitlabSchemaVersion = "15.0.7"
+
+type gitlabReport struct {
+	Version         string                `json:"version"`
+	Vulnerabilities []gitlabVulnerability `json:"vulnerabilities"`
+	Scan            gitlabScan            `json:"scan"`
+}
+
+type gitlabVulnerability struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Severity    string             `json:"severity"`
+	Solution    string             `json:"solution,omitempty"`
+	Location    gitlabLocation     `json:"location"`
+	Identifiers []gitlabIdentifier `json:"identifiers"`
+	Links       []gitlabLink       `json:"links,omitempty"`
+}
+
+type gitlabLocation struct {
+	File      string `json:"file,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Class     string `json:"class,omitempty"`
+	Method    string `json:"method,omitempty"`
+}
+
+type gitlabIdentifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type gitlabLink struct {
+	URL string `json:"url"`
+}
+
+type gitlabScan struct {
+	Analyzer  gitlabTool `json:"analyzer"`
+	Scanner   gitlabTool `json:"scanner"`
+	Type      string     `json:"type"`
+	StartTime string     `json:"start_time"`
+	EndTime   string     `json:"end_time"`
+	Status    string     `json:"status"`
+
+	// Messages are shown on the pipeline's security tab, for warnings
+	// about the scan itself.
+	Messages []gitlabMessage `json:"messages,omitempty"`
+}
+
+type gitlabMessage struct {
+	Level string `json:"level"` // info, warn or fatal
+	Value string `json:"value"`
+}
+
+type gitlabTool struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Version string       `json:"version"`
+	Vendor  gitlabVendor `json:"vendor"`
+}
+
+type gitlabVendor struct {
+	Name string `json:"name"`
+}
+
+// GitLabReporter writes a GitLab SAST report (gl-sast-report.json).
+// GitLab tracks vulnerabilities by location and has no notion of related
+// locations, so clustered occurrences are reported individually.
+type GitLabReporter struct {
+	// RepoRoot, when set, makes file paths relative to the repository
+	// root, which the merge request widget needs to link them. Files
+	// outside it keep their path.
+	RepoRoot string
+}
+
+func (r *GitLabReporter) Name() string { return "gitlab" }
+
+func (r *GitLabReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
+	vulns := []gitlabVulnerability{}
+	for _, f := range report.Findings {
+		vulns = append(vulns, r.vulnerabilityOf(f))
+		for _, rel := range f.Related {
+			vulns = append(vulns, r.vulnerabilityOf(rel))
+		}
+	}
+
+	version := "unknown"
+	if report.Environment != nil && report.Environment.Solsec != "" {
+		version = report.Environment.Solsec
+	}
+	tool := gitlabTool{ID: "solsec", Name: "solsec", Version: version, Vendor: gitlabVendor{Name: "solsec"}}
+	// GitLab requires scan times, in UTC without a zone suffix. Deterministic
+	// reports have no generation time, so the epoch stands in.
+	at := "1970-01-01T00:00:00"
+	if t, err := time.Parse(time.RFC3339, report.GeneratedAt); err == nil {
+		at = t.UTC().Format("2006-01-02T15:04:05")
+	}
+	status := "success"
+	if report.Interrupted {
+		status = "failure"
+	}
+
+	var messages []gitlabMessage
+	for _, w := range report.Warnings {
+		level := "warn"
+		if w.Kind == parser.WarningDedup {
+			level = "info"
+		}
+		messages = append(messages, gitlabMessage{Level: level, Value: w.String()})
+	}
+
+	data, err := json.MarshalIndent(gitlabReport{
+		Version:         gitlabSchemaVersion,
+		Vulnerabilities: vulns,
+		Scan: gitlabScan{
+			Analyzer: tool, Scanner: tool, Type: "sast",
+			StartTime: at, EndTime: at, Status: status,
+			Messages: messages,
+		},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling GitLab report: %w", err)
+	}
+	return os.WriteFile(outputPath, data, 0640)
+}
+
+func (r *GitLabReporter) vulnerabilityOf(f parser.Finding) gitlabVulnerability {
+	file, _ := relativePath(r.RepoRoot, f.File)
+	v := gitlabVulnerability{
+		ID:          gitlabID(f),
+		Name:        f.Title,
+		Description: f.Description,
+		Severity:    gitlabSeverity(f.Severity),
+		Solution:    f.Remediation,
+		Location:    gitlabLocation{File: file, Class: f.Contract, Method: f.Function},
+		Identifiers: []gitlabIdentifier{{Type: "solsec_check", Name: "solsec " + f.Check, Value: f.Check}},
+	}
+	if len(f.Lines) > 0 {
+		v.Location.StartLine = slices.Min(f.Lines)
+		v.Location.EndLine = slices.Max(f.Lines)
+	}
+	if cwe := strings.TrimPrefix(f.CWERef, "CWE-"); cwe != "" {
+		v.Identifiers = append(v.Identifiers, gitlabIdentifier{
+			Type: "cwe", Name: f.CWERef, Value: cwe,
+			URL: "https://cwe.mitre.org/data/definitions/" + cwe + ".html",
+		})
+	}
+	if f.SWCRef != "" {
+		v.Identifiers = append(v.Identifiers, gitlabIdentifier{
+			Type: "swc", Name: f.SWCRef, Value: f.SWCRef,
+			URL: "https://swcregistry.io/docs/" + f.SWCRef,
+		})
+	}
+	for _, ref := range f.References {
+		v.Links = append(v.Links, gitlabLink{URL: ref})
+	}
+	return v
+}
+
+// gitlabID derives a stable UUID-formatted ID from the finding's
+// fingerprint, so a finding keeps its ID across pipelines.
+func gitlabID(f parser.Finding) string {
+	key := f.Fingerprint
+	if key == "" {
+		key = fmt.Sprintf("%s:%s:%v", f.Check, f.File, f.Lines)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+func gitlabSeverity(s parser.Severity) string {
+	switch s {
+	case parser.SeverityCritical, parser.SeverityHigh, parser.SeverityMedium, parser.SeverityLow:
+		return string(s)
+	case parser.SeverityInformational, parser.SeverityOptimization:
+		return "Info"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,51 @@
+package reporter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabReporter(t *testing.T) {
+	root := t.TempDir()
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(writeReport(t, &GitLabReporter{RepoRoot: root}, testReport(root)), &out))
+
+	assert.Equal(t, gitlabSchemaVersion, out["version"])
+	scan := out["scan"].(map[string]any)
+	for _, key := range []string{"analyzer", "scanner", "type", "start_time", "end_time", "status"} {
+		assert.Contains(t, scan, key)
+	}
+	assert.Equal(t, "sast", scan["type"])
+	assert.Equal(t, "2026-01-02T03:04:05", scan["start_time"], "UTC without a zone suffix")
+
+	vulns := out["vulnerabilities"].([]any)
+	require.Len(t, vulns, 4, "clustered occurrences are reported individually")
+	ids := map[string]bool{}
+	for _, v := range vulns {
+		v := v.(map[string]any)
+		for _, key := range []string{"id", "name", "severity", "location", "identifiers"} {
+			assert.Contains(t, v, key)
+		}
+		id := v["id"].(string)
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`, id)
+		assert.False(t, ids[id], "IDs are unique")
+		ids[id] = true
+		assert.NotEmpty(t, v["identifiers"])
+	}
+
+	first := vulns[0].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"file": "src/Vault.sol", "start_line": 10.0, "end_line": 12.0, "class": "Vault", "method": "setOwner",
+	}, first["location"], "paths are relative to the repository root")
+	assert.Equal(t, "Info", vulns[3].(map[string]any)["severity"])
+}
+
+func TestGitLabID_IsStable(t *testing.T) {
+	f := testReport(t.TempDir()).Findings[0]
+	shifted := f
+	shifted.Lines = []int{13, 15}
+	assert.Equal(t, gitlabID(f), gitlabID(shifted), "the ID follows the fingerprint, not the line")
+}
@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+const (
+	graphNodeW = 190
+	graphNodeH = 30
+	graphGapX  = 24
+	graphGapY  = 56
+)
+
+// importGraphSVG renders the report's import graph as a layered SVG: files
+// nobody imports sit in the top row and each dependency is drawn below its
+// deepest importer. Files holding unused contracts are outlined in the
+// informational color; out-of-scope imports are dashed.
+func importGraphSVG(report *parser.AnalysisReport) string {
+	g := report.ImportGraph
+	if g == nil || len(g.Edges) == 0 {
+		return ""
+	}
+
+	unused := map[string]bool{}
+	for _, f := range report.Findings {
+		if f.Check == "custom-unused-contract" {
+			unused[f.File] = true
+		}
+	}
+
+	// Longest-path layering; the iteration cap keeps import cycles finite.
+	depth := map[string]int{}
+	for i := 0; i < len(g.Nodes); i++ {
+		changed := false
+		for _, e := range g.Edges {
+			if depth[e.To] < depth[e.From]+1 && depth[e.From]+1 < len(g.Nodes) {
+				depth[e.To] = depth[e.From] + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	layers := map[int][]parser.GraphNode{}
+	maxDepth, maxWidth := 0, 0
+	for _, n := range g.Nodes {
+		d := depth[n.Path]
+		layers[d] = append(layers[d], n)
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	type point struct{ x, y int }
+	pos := map[string]point{}
+	for d := 0; d <= maxDepth; d++ {
+		nodes := layers[d]
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Path < nodes[j].Path })
+		for i, n := range nodes {
+			pos[n.Path] = point{x: i*(graphNodeW+graphGapX) + 1, y: d*(graphNodeH+graphGapY) + 1}
+		}
+		if len(nodes) > maxWidth {
+			maxWidth = len(nodes)
+		}
+	}
+	width := maxWidth*(graphNodeW+graphGapX) - graphGapX + 2
+	height := (maxDepth+1)*(graphNodeH+graphGapY) - graphGapY + 2
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="import-graph" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 8 8" refX="8" refY="4" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0 L8,4 L0,8 z" fill="currentColor"/></marker></defs>`)
+	for _, e := range g.Edges {
+		from, to := pos[e.From], pos[e.To]
+		x1, y1 := from.x+graphNodeW/2, from.y+graphNodeH
+		x2, y2 := to.x+graphNodeW/2, to.y
+		mid := (y1 + y2) / 2
+		fmt.Fprintf(&b, `<path class="edge" d="M%d,%d C%d,%d %d,%d %d,%d" marker-end="url(#arrow)"/>`, x1, y1, x1, mid, x2, mid, x2, y2)
+	}
+	for _, n := range g.Nodes {
+		p := pos[n.Path]
+		class := "node"
+		label := n.Path
+		if n.External {
+			class += " external"
+		} else {
+			label = relTo(report.Target, n.Path)
+			if unused[n.Path] {
+				class += " unused"
+			}
+		}
+		title := n.Path
+		if len(n.Contracts) > 0 {
+			title += " — " + strings.Join(n.Contracts, ", ")
+		}
+		fmt.Fprintf(&b, `<g class="%s"><title>%s</title><rect x="%d" y="%d" width="%d" height="%d" rx="4"/><text x="%d" y="%d">%s</text></g>`,
+			class, html.EscapeString(title), p.x, p.y, graphNodeW, graphNodeH,
+			p.x+graphNodeW/2, p.y+graphNodeH/2+4, html.EscapeString(shortLabel(label)))
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// shortLabel trims a path to fit a graph node, keeping the file name.
+func shortLabel(path string) string {
+	const limit = 26
+	if len(path) <= limit {
+		return path
+	}
+	base := filepath.Base(path)
+	if len(base) >= limit-2 {
+		return "…" + base[len(base)-(limit-2):]
+	}
+	return "…/" + base
+}
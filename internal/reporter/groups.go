@@ -0,0 +1,102 @@
+package reporter
+
+import (
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/scorer"
+)
+
+// findingGroup is one section of the grouped findings view: a file holding
+// its contracts, or a contract holding its findings.
+type findingGroup struct {
+	Name      string // file path relative to the target, or contract name
+	Summary   parser.Summary
+	Score     int
+	Contracts []*findingGroup
+	Findings  []parser.Finding
+}
+
+// Groups for findings without a file or outside any contract.
+const (
+	noFileGroup     = "(no file)"
+	noContractGroup = "(outside a contract)"
+)
+
+// groupFindings groups the report's findings by file, then by contract, in
+// the order an auditor reads the code: files by path, contracts by their
+// first finding's line. Findings keep their report order within a contract.
+func groupFindings(report *parser.AnalysisReport) []*findingGroup {
+	var files []*findingGroup
+	byFile := map[string]*findingGroup{}
+	byContract := map[*findingGroup]map[string]*findingGroup{}
+	firstLines := map[*findingGroup]int{}
+
+	for _, f := range report.Findings {
+		name := noFileGroup
+		if f.File != "" {
+			name = relTo(report.Target, f.File)
+		}
+		file, ok := byFile[name]
+		if !ok {
+			file = &findingGroup{Name: name}
+			byFile[name] = file
+			byContract[file] = map[string]*findingGroup{}
+			files = append(files, file)
+		}
+		contract := f.Contract
+		if contract == "" {
+			contract = noContractGroup
+		}
+		c, ok := byContract[file][contract]
+		if !ok {
+			c = &findingGroup{Name: contract}
+			byContract[file][contract] = c
+			file.Contracts = append(file.Contracts, c)
+			firstLines[c] = firstLine(f)
+		}
+		firstLines[c] = min(firstLines[c], firstLine(f))
+		c.Findings = append(c.Findings, f)
+		file.Findings = append(file.Findings, f)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		// Findings without a file come last.
+		if (files[i].Name == noFileGroup) != (files[j].Name == noFileGroup) {
+			return files[j].Name == noFileGroup
+		}
+		return files[i].Name < files[j].Name
+	})
+	for _, file := range files {
+		sort.SliceStable(file.Contracts, func(i, j int) bool {
+			return firstLines[file.Contracts[i]] < firstLines[file.Contracts[j]]
+		})
+		file.summarize()
+		for _, c := range file.Contracts {
+			c.summarize()
+		}
+	}
+	return files
+}
+
+// summarize counts the group's findings by severity and scores them.
+func (g *findingGroup) summarize() {
+	g.Summary = parser.Summary{Total: len(g.Findings)}
+	for _, f := range g.Findings {
+		switch f.Severity {
+		case parser.SeverityCritical:
+			g.Summary.Critical++
+		case parser.SeverityHigh:
+			g.Summary.High++
+		case parser.SeverityMedium:
+			g.Summary.Medium++
+		case parser.SeverityLow:
+			g.Summary.Low++
+		case parser.SeverityInformational:
+			g.Summary.Informational++
+		case parser.SeverityOptimization:
+			g.Summary.Optimization++
+		}
+	}
+	g.Score = scorer.ScoreFindings(g.Findings)
+}
@@ -0,0 +1,93 @@
+package reporter
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/scorer"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// heatmapCell is one file in the risk heatmap: sized by SLOC, colored by the
+// risk score of the findings located in it.
+type heatmapCell struct {
+	File     string
+	SLOC     int
+	Score    int
+	Grade    string
+	Findings int
+}
+
+// buildHeatmap scores every Solidity file under the report target plus any
+// file referenced by a finding. Files that can no longer be read (e.g. a
+// report rendered on another machine) still appear, with a nominal size.
+func buildHeatmap(report *parser.AnalysisReport) []heatmapCell {
+	byFile := map[string][]parser.Finding{}
+	display := map[string]string{}
+
+	if files, err := source.SolidityFiles(report.Target); err == nil {
+		for _, f := range files {
+			key := absPath(f)
+			byFile[key] = nil
+			display[key] = relTo(report.Target, f)
+		}
+	}
+	for _, f := range report.Findings {
+		if f.File == "" {
+			continue
+		}
+		key := absPath(f.File)
+		byFile[key] = append(byFile[key], f)
+		if _, ok := display[key]; !ok {
+			display[key] = relTo(report.Target, f.File)
+		}
+	}
+
+	cells := make([]heatmapCell, 0, len(byFile))
+	for key, findings := range byFile {
+		sloc, err := source.SLOC(key)
+		if err != nil || sloc == 0 {
+			sloc = 1
+		}
+		score := scorer.ScoreFindings(findings)
+		cells = append(cells, heatmapCell{
+			File:     display[key],
+			SLOC:     sloc,
+			Score:    score,
+			Grade:    scorer.Grade(score),
+			Findings: len(findings),
+		})
+	}
+
+	// Riskiest first, then largest, so hot spots lead the map.
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Score != cells[j].Score {
+			return cells[i].Score > cells[j].Score
+		}
+		if cells[i].SLOC != cells[j].SLOC {
+			return cells[i].SLOC > cells[j].SLOC
+		}
+		return cells[i].File < cells[j].File
+	})
+	return cells
+}
+
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return p
+}
+
+// relTo shortens path relative to the target directory for display.
+func relTo(target, path string) string {
+	base := target
+	if filepath.Ext(target) == ".sol" {
+		base = filepath.Dir(target)
+	}
+	if rel, err := filepath.Rel(absPath(base), absPath(path)); err == nil && !filepath.IsAbs(rel) && rel != "" && rel[0] != '.' {
+		return rel
+	}
+	return path
+}
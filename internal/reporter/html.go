@@ -3,6 +3,7 @@ package reporter
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/template"
 	"time"
 
@@ -10,11 +11,72 @@ import (
 	"github.com/Zubimendi/solsec/internal/scorer"
 )
 
-type HTMLReporter struct{}
+type HTMLReporter struct {
+	// Verbose renders each finding's evidence (what the heuristic matched).
+	Verbose bool
+
+	// PageSize paginates the findings table when there are more findings
+	// than this: rows past the current page stay hidden, so the browser only
+	// lays out one page at a time. 0 disables paging.
+	PageSize int
+	// Print renders for paper (see PDFReporter): collapsible sections are
+	// expanded and the findings table is not paginated.
+	Print bool
+	// Template is a text/template file rendered in place of the built-in
+	// template: a complete report, or {{define}}s of its blocks (head, logo,
+	// disclaimer, footer) to brand it. Empty uses the built-in one.
+	Template string
+}
+
+// DefaultPageSize is the findings-per-page default for HTML reports.
+const DefaultPageSize = 100
 
 func (r *HTMLReporter) Name() string { return "html" }
 
+// ValidateTemplate checks that the custom HTML template at path reads and
+// parses, before an analysis is spent on it.
+func ValidateTemplate(path string) error {
+	_, err := (&HTMLReporter{Template: path}).parse(&parser.AnalysisReport{}, 0)
+	return err
+}
+
 func (r *HTMLReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
+	pageSize := r.PageSize
+	if r.Print {
+		pageSize = 0
+	}
+	tmpl, err := r.parse(report, pageSize)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return fmt.Errorf("creating HTML report: %w", err)
+	}
+	defer f.Close()
+
+	return tmpl.Execute(f, htmlData{
+		Report:    report,
+		Score:     score,
+		Grade:     scorer.Grade(score),
+		Verdict:   scorer.Verdict(score),
+		Heatmap:   buildHeatmap(report),
+		Trend:     trendSeries(report.Trend),
+		Graph:     importGraphSVG(report),
+		Access:    buildAccessTable(report),
+		Facets:    tagFacets(report),
+		Groups:    groupFindings(report),
+		Breakdown: scorer.Explain(report),
+		Verbose:   r.Verbose,
+		PageSize:  pageSize,
+		Print:     r.Print,
+	})
+}
+
+// parse builds the report template, with functions bound to report.
+func (r *HTMLReporter) parse(report *parser.AnalysisReport, pageSize int) (*template.Template, error) {
+	excerpts := newSnippets()
 	tmpl, err := template.New("report").Funcs(template.FuncMap{
 		"severityClass": func(s parser.Severity) string {
 			switch s {
@@ -30,25 +92,21 @@ func (r *HTMLReporter) Write(report *parser.AnalysisReport, score int, outputPat
 				return "info"
 			}
 		},
-		"gradeClass": func(g string) string {
-			switch g {
-			case "A":
-				return "grade-a"
-			case "B":
-				return "grade-b"
-			case "C":
-				return "grade-c"
-			case "D", "F":
-				return "grade-f"
-			default:
-				return ""
+		"tier":         scorer.Tier,
+		"severityRank": parser.SeverityRank,
+		"timestamp": func(rfc3339 string) string {
+			t, err := time.Parse(time.RFC3339, rfc3339)
+			if err != nil {
+				return rfc3339
 			}
+			return t.UTC().Format("2006-01-02 15:04:05 UTC")
 		},
-		"now": func() string {
-			return time.Now().Format("2006-01-02 15:04:05 UTC")
-		},
-		"grade":   scorer.Grade,
-		"verdict": scorer.Verdict,
+		"grade":     scorer.Grade,
+		"verdict":   scorer.Verdict,
+		"joinNames": func(names []string) string { return strings.Join(names, ", ") },
+		"tagAttr":   func(tags []string) string { return strings.Join(tags, " ") },
+		"snippet":   excerpts.render,
+		"paged":     func(i int) bool { return pageSize > 0 && len(report.Findings) > pageSize && i >= pageSize },
 		"join": func(lines []int) string {
 			result := ""
 			for i, l := range lines {
@@ -60,28 +118,42 @@ func (r *HTMLReporter) Write(report *parser.AnalysisReport, score int, outputPat
 			return result
 		},
 	}).Parse(htmlTemplate)
-
 	if err != nil {
-		return fmt.Errorf("parsing HTML template: %w", err)
+		return nil, fmt.Errorf("parsing HTML template: %w", err)
+	}
+	if r.Template == "" {
+		return tmpl, nil
 	}
 
-	f, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	// Parsing over the built-in template replaces its body when the custom
+	// one has content outside {{define}}, and otherwise only the blocks it
+	// defines.
+	custom, err := os.ReadFile(r.Template)
 	if err != nil {
-		return fmt.Errorf("creating HTML report: %w", err)
+		return nil, fmt.Errorf("reading HTML template: %w", err)
 	}
-	defer f.Close()
+	if tmpl, err = tmpl.Parse(string(custom)); err != nil {
+		return nil, fmt.Errorf("parsing HTML template %s: %w", r.Template, err)
+	}
+	return tmpl, nil
+}
 
-	return tmpl.Execute(f, struct {
-		Report  *parser.AnalysisReport
-		Score   int
-		Grade   string
-		Verdict string
-	}{
-		Report:  report,
-		Score:   score,
-		Grade:   scorer.Grade(score),
-		Verdict: scorer.Verdict(score),
-	})
+// htmlData is what the HTML template renders.
+type htmlData struct {
+	Report    *parser.AnalysisReport
+	Score     int
+	Grade     string
+	Verdict   string
+	Heatmap   []heatmapCell
+	Trend     []sparkSeries
+	Graph     string
+	Access    *accessTable
+	Facets    []tagFacet
+	Groups    []*findingGroup
+	Breakdown scorer.Breakdown
+	Verbose   bool
+	PageSize  int
+	Print     bool
 }
 
 const htmlTemplate = `<!DOCTYPE html>
@@ -113,7 +185,7 @@ const htmlTemplate = `<!DOCTYPE html>
     padding: 1.5rem; margin-bottom: 2rem; display: flex; align-items: center; gap: 1.5rem; }
   .grade-letter { font-size: 4rem; font-weight: 900; line-height: 1; }
   .grade-a { color: var(--low); } .grade-b { color: #57ab5a; } .grade-c { color: var(--medium); }
-  .grade-f { color: var(--critical); }
+  .grade-d, .grade-f { color: var(--critical); }
   .verdict-text { font-size: 1.1rem; }
   .score-bar { height: 8px; background: var(--border); border-radius: 4px; margin-top: 0.5rem; overflow: hidden; }
   .score-fill { height: 100%; border-radius: 4px; background: var(--critical); transition: width 0.3s; }
@@ -132,23 +204,117 @@ const htmlTemplate = `<!DOCTYPE html>
   .badge-info { background: rgba(88,166,255,0.15); color: var(--info); border: 1px solid var(--info); }
   .remediation { background: rgba(88,166,255,0.05); border-left: 3px solid var(--info);
     padding: 0.5rem 0.75rem; margin-top: 0.5rem; font-size: 0.85rem; border-radius: 0 4px 4px 0; }
+  .code-link { text-decoration: none; }
+  .code-link:hover code { text-decoration: underline; }
+  .group { margin-top: 0.5rem; }
+  .group summary { cursor: pointer; }
+  .group.contract { margin-left: 1.25rem; }
+  .group ul { list-style: none; margin: 0.3rem 0 0.5rem 1.25rem; font-size: 0.85rem; }
+  .group li { margin: 0.25rem 0; }
+  .group-summary { color: var(--muted); font-size: 0.8rem; margin-left: 0.5rem; }
   .swc-ref { font-size: 0.75rem; color: var(--muted); }
+  .breakdown { margin-bottom: 1.5rem; }
+  .breakdown summary { cursor: pointer; color: var(--muted); font-size: 0.9rem; }
+  .related summary { cursor: pointer; color: var(--muted); font-size: 0.8rem; margin-top: 0.3rem; }
+  .related ul { margin: 0.2rem 0 0 1.2rem; color: var(--muted); font-size: 0.8rem; }
+  .facets { display: flex; flex-wrap: wrap; gap: 0.4rem; margin-bottom: 0.75rem; }
+  .facet { background: var(--surface); color: var(--muted); border: 1px solid var(--border); border-radius: 999px;
+    padding: 0.2rem 0.7rem; font-size: 0.8rem; cursor: pointer; }
+  .facet.active { color: var(--info); border-color: var(--info); }
+  .pager { display: flex; align-items: center; justify-content: center; gap: 1rem; margin-top: 1rem;
+    color: var(--muted); font-size: 0.85rem; }
+  .pager[hidden] { display: none; }
+  .facet:disabled { opacity: 0.4; cursor: default; }
+  .filters { display: flex; flex-wrap: wrap; align-items: center; gap: 0.5rem; margin-bottom: 0.75rem; font-size: 0.85rem; }
+  .filters input, .filters select { background: var(--surface); color: var(--text); border: 1px solid var(--border);
+    border-radius: 6px; padding: 0.3rem 0.5rem; font: inherit; }
+  .filters input { flex: 1 1 16rem; }
+  .filter-status { color: var(--muted); margin-left: auto; }
+  .findings-table th[data-sort] { cursor: pointer; user-select: none; }
+  .findings-table th[data-sort]::after { content: " ↕"; opacity: 0.4; }
+  .findings-table th[aria-sort=ascending]::after { content: " ▲"; opacity: 1; }
+  .findings-table th[aria-sort=descending]::after { content: " ▼"; opacity: 1; }
+  .tag { display: inline-block; font-size: 0.7rem; color: var(--muted); border: 1px solid var(--border);
+    border-radius: 999px; padding: 0 0.5em; margin: 0.3rem 0.25rem 0 0; }
+  .validation-confirmed { color: var(--critical); border-color: var(--critical); }
+  .validation-not-reproducible { color: var(--low); border-color: var(--low); }
+  .impact { color: var(--high); border-color: var(--high); }
   code { font-family: 'JetBrains Mono', 'Fira Code', monospace; font-size: 0.85em;
     background: var(--surface); padding: 0.1em 0.4em; border-radius: 3px; }
   .no-findings { text-align: center; padding: 3rem; color: var(--muted); }
   .source-badge { font-size: 0.7rem; padding: 0.1em 0.4em; border-radius: 3px;
     background: var(--border); color: var(--muted); }
+  h2 { font-size: 1.1rem; margin-bottom: 0.75rem; }
+  .heatmap { display: flex; flex-wrap: wrap; gap: 3px; margin-bottom: 2rem; }
+  .heat-cell { min-width: 90px; min-height: 64px; padding: 0.4rem 0.5rem; border-radius: 4px;
+    font-size: 0.75rem; overflow: hidden; color: #0d1117; }
+  .heat-cell .heat-file { font-weight: 600; word-break: break-all; }
+  .heat-a { background: #2ea043; } .heat-b { background: #7fb04a; } .heat-c { background: var(--medium); }
+  .heat-d { background: var(--high); } .heat-f { background: var(--critical); }
+  .trend { display: flex; flex-wrap: wrap; gap: 1.25rem; margin-top: 1rem; font-size: 0.75rem; color: var(--muted); }
+  .trend-item { display: flex; align-items: center; gap: 0.4rem; }
+  .delta-up { color: var(--critical); } .delta-down { color: var(--low); }
+  .scope { margin-top: 2rem; }
+  .scope summary { cursor: pointer; }
+  .graph-wrap { overflow-x: auto; margin-top: 0.75rem; color: var(--muted); }
+  .import-graph .edge { fill: none; stroke: var(--border); stroke-width: 1.5; }
+  .import-graph .node rect { fill: var(--surface); stroke: var(--border); }
+  .import-graph .node text { fill: var(--text); font-size: 11px; text-anchor: middle;
+    font-family: 'JetBrains Mono', 'Fira Code', monospace; }
+  .import-graph .external rect { stroke-dasharray: 4 3; }
+  .import-graph .external text { fill: var(--muted); }
+  .import-graph .unused rect { stroke: var(--info); stroke-width: 2; }
+  .evidence { font-size: 0.8rem; color: var(--muted); margin-top: 0.5rem; }
+  .evidence ul { margin: 0.2rem 0 0 1.2rem; }
+  .snippet { background: var(--bg); border: 1px solid var(--border); border-radius: 6px; margin-top: 0.5rem;
+    padding: 0.4rem 0; font-size: 0.78rem; line-height: 1.45; overflow-x: auto; }
+  .snippet .line { display: block; padding: 0 0.75rem 0 0; white-space: pre; }
+  .snippet .line.flagged { background: rgba(248,81,73,0.15); box-shadow: inset 3px 0 0 var(--critical); }
+  .snippet .ln { display: inline-block; width: 3.5em; padding-right: 1em; text-align: right; color: var(--muted); user-select: none; }
+  .tok-keyword { color: #ff7b72; } .tok-type { color: #79c0ff; } .tok-string { color: #a5d6ff; }
+  .tok-number { color: #79c0ff; } .tok-comment { color: var(--muted); font-style: italic; }
+  .partial-banner { margin-top: 0.75rem; padding: 0.5rem 0.75rem; border: 1px solid var(--high); border-radius: 6px; color: var(--high); font-size: 0.85rem; }
+  .heat-legend { font-size: 0.75rem; color: var(--muted); margin-bottom: 0.5rem; }
+  @media print {
+    :root { --bg: #fff; --surface: #f6f8fa; --border: #d0d7de; --text: #1f2328; --muted: #59636e; }
+    body { padding: 0; }
+    .facets, .filters, .pager { display: none; }
+    tr, .grade-card, .stat-card { break-inside: avoid; }
+    .snippet .line { white-space: pre-wrap; }
+    .tok-keyword { color: #cf222e; } .tok-type, .tok-number { color: #0550ae; } .tok-string { color: #0a3069; }
+  }
 </style>
+{{block "head" .}}{{end}}
 </head>
 <body>
 <div class="container">
   <header>
+    {{block "logo" .}}{{end}}
     <h1>🔐 solsec — Smart Contract Security Report</h1>
-    <div class="meta">Target: <code>{{.Report.Target}}</code> &nbsp;|&nbsp; Generated: {{now}}</div>
+    <div class="meta">Target: <code>{{.Report.Target}}</code>{{with .Report.GeneratedAt}} &nbsp;|&nbsp; Generated: {{timestamp .}}{{end}}</div>
+    {{with .Report.Scope}}
+    <div class="meta">Scope:{{if .Contracts}} contracts <code>{{joinNames .Contracts}}</code>{{end}}{{if .Functions}} functions <code>{{joinNames .Functions}}</code>{{end}}; findings elsewhere are not reported</div>
+    {{end}}
+    {{if .Report.Interrupted}}
+    <div class="partial-banner">⚠️ Partial report: the analysis was interrupted before every engine and check finished.</div>
+    {{end}}
+    {{with .Report.Degraded}}
+    <div class="partial-banner">⚠️ Degraded analysis: {{range $i, $d := .}}{{if $i}}; {{end}}custom check <code>{{$d.Check}}</code> {{$d.Reason}}{{end}}. Their findings are missing from this report.</div>
+    {{end}}
+    {{if .Trend}}
+    <div class="trend">
+      <div class="trend-item">Last {{len .Report.Trend.Points}} runs &nbsp;
+        {{with .Report.Trend.ScoreDelta}}{{if gt . 0}}<span class="delta-up">▲ +{{.}} risk vs previous run</span>{{else}}<span class="delta-down">▼ {{.}} risk vs previous run</span>{{end}}{{else}}no score change vs previous run{{end}}
+      </div>
+      {{range .Trend}}
+      <div class="trend-item"><span class="{{.Class}}">{{.SVG}}</span>{{.Label}} {{.Latest}}</div>
+      {{end}}
+    </div>
+    {{end}}
   </header>
 
   <div class="grade-card">
-    <div class="grade-letter {{.Grade | gradeClass}}">{{.Grade}}</div>
+    <div class="grade-letter grade-{{tier .Score}}">{{.Grade}}</div>
     <div>
       <div class="verdict-text">{{.Verdict}}</div>
       <div class="score-bar" style="width: 200px; margin-top: 0.75rem;">
@@ -167,47 +333,351 @@ const htmlTemplate = `<!DOCTYPE html>
     <div class="stat-card"><div class="count info">{{.Report.Summary.Informational}}</div><div class="label">Info</div></div>
   </div>
 
+  {{with .Breakdown}}{{if .Contributions}}
+  <details class="breakdown"{{if $.Print}} open{{end}}>
+    <summary>Score breakdown: {{.Score}}/100 from {{len .Contributions}} scored finding{{if ne (len .Contributions) 1}}s{{end}}{{if gt .Raw .Score}} ({{.Raw}} points before the cap){{end}}</summary>
+    <table class="findings-table" style="margin-top:0.75rem;">
+      <thead><tr><th>Finding</th><th>Severity</th><th>Weight</th><th>Counted</th></tr></thead>
+      <tbody>
+      {{range .Contributions}}
+      <tr>
+        <td><code>{{.ID}}</code> {{.Title}}</td>
+        <td><span class="badge badge-{{.Severity | severityClass}}">{{.Severity}}</span></td>
+        <td>{{.Points}}</td>
+        <td>{{.Counted}}{{if .Note}} <span style="color:var(--muted);">({{.Note}})</span>{{end}}</td>
+      </tr>
+      {{end}}
+      </tbody>
+    </table>
+    {{if .Unscored}}<div class="heat-legend" style="margin-top:0.5rem;">{{.Unscored}} Informational/Optimization finding(s) carry no weight.</div>{{end}}
+  </details>
+  {{end}}{{end}}
+
+  {{with .Report.Warnings}}
+  <details class="breakdown"{{if $.Print}} open{{end}}>
+    <summary>⚠️ {{len .}} warning{{if ne (len .) 1}}s{{end}}: checks that failed, files left unanalyzed and dropped duplicates</summary>
+    <table class="findings-table" style="margin-top:0.75rem;">
+      <thead><tr><th>Kind</th><th>Check / File</th><th>Message</th></tr></thead>
+      <tbody>
+      {{range .}}
+      <tr>
+        <td><span class="tag">{{.Kind}}</span></td>
+        <td>{{if .File}}<code>{{.File | html}}</code>{{else if .Check}}<code>{{.Check}}</code>{{end}}</td>
+        <td>{{.Message | html}}</td>
+      </tr>
+      {{end}}
+      </tbody>
+    </table>
+  </details>
+  {{end}}
+
+  {{if .Heatmap}}
+  <h2>Risk Heatmap</h2>
+  <div class="heat-legend">Each tile is a file, sized by SLOC and colored by the risk grade of its findings.</div>
+  <div class="heatmap">
+    {{range .Heatmap}}
+    <div class="heat-cell heat-{{tier .Score}}" style="flex: {{.SLOC}} 1 0;"
+      title="{{.File}} — {{.SLOC}} SLOC, {{.Findings}} finding(s), risk {{.Score}}/100">
+      <div class="heat-file">{{.File}}</div>
+      <div>{{.SLOC}} SLOC · {{.Findings}} finding{{if ne .Findings 1}}s{{end}} · {{.Score}}/100</div>
+    </div>
+    {{end}}
+  </div>
+  {{end}}
+
   {{if eq .Report.Summary.Total 0}}
   <div class="no-findings">
     <div style="font-size: 3rem; margin-bottom: 1rem;">✅</div>
     <div>No findings detected. Review manually before mainnet deployment.</div>
   </div>
   {{else}}
-  <table class="findings-table">
+  {{if .Facets}}
+  <div class="facets">
+    {{range .Facets}}<button class="facet" data-tag="{{.Tag}}">{{.Tag}} ({{.Count}})</button>{{end}}
+  </div>
+  {{end}}
+  <div class="filters">
+    <input type="search" id="filter-text" placeholder="Search findings…" aria-label="Search findings">
+    <select id="filter-severity" aria-label="Severity"><option value="">All severities</option></select>
+    <select id="filter-source" aria-label="Source"><option value="">All sources</option></select>
+    <select id="filter-check" aria-label="Check"><option value="">All checks</option></select>
+    <span class="filter-status"></span>
+  </div>
+  <table class="findings-table" id="findings" data-page-size="{{.PageSize}}">
     <thead>
       <tr>
-        <th>Severity</th><th>ID</th><th>Title</th><th>Location</th><th>Source</th>
+        <th data-sort="0">Severity</th><th data-sort="1">ID</th><th data-sort="2">Title</th><th data-sort="3">Location</th><th data-sort="4">Source</th>
       </tr>
     </thead>
     <tbody>
-    {{range .Report.Findings}}
-    <tr>
-      <td><span class="badge badge-{{.Severity | severityClass}}">{{.Severity}}</span></td>
+    {{range $i, $f := .Report.Findings}}{{with $f}}
+    <tr data-tags="{{tagAttr .Tags}}" data-severity="{{.Severity}}" data-source="{{.Source}}" data-check="{{.Check}}"{{if paged $i}} hidden{{end}}>
+      <td data-key="{{severityRank .Severity}}"><span class="badge badge-{{.Severity | severityClass}}">{{.Severity}}</span></td>
       <td><code>{{.ID}}</code></td>
       <td>
         <strong>{{.Title}}</strong>
         <div style="color:var(--muted); font-size:0.85rem; margin-top:0.25rem;">{{.Description}}</div>
+        {{range .Tags}}<span class="tag">{{.}}</span>{{end}}
+        {{if .Validation}}<span class="tag validation-{{.Validation}}">{{if eq .Validation "confirmed"}}✓ confirmed on fork{{else}}not reproducible on fork{{end}}</span>{{end}}
+        {{with .Impact}}<span class="tag impact" title="{{range $i, $h := .Holdings}}{{if $i}}, {{end}}{{$h.Amount}} {{$h.Asset}}{{end}} held by {{.Address}}">💰 {{.}} at risk</span>{{end}}
         {{if .Remediation}}
         <div class="remediation">💡 {{.Remediation}}</div>
         {{end}}
+        {{snippet .}}
+        {{if and $.Verbose .Evidence}}
+        <div class="evidence">🔎 Evidence<ul>{{range .Evidence}}<li>{{.}}</li>{{end}}</ul></div>
+        {{end}}
         {{if .SWCRef}}<div class="swc-ref" style="margin-top:0.4rem;">Ref: {{.SWCRef}}</div>{{end}}
       </td>
-      <td>
-        {{if .File}}<code>{{.File}}</code>{{end}}
+      <td data-key="{{.File | html}}:{{with .Lines}}{{index . 0 | printf "%08d"}}{{end}}">
+        {{if .File}}{{if .CodeURL}}<a class="code-link" href="{{.CodeURL}}" target="_blank" rel="noopener"><code>{{.File}}</code></a>{{else}}<code>{{.File}}</code>{{end}}{{end}}
         {{if .Lines}}<br><span style="color:var(--muted);">Line{{if gt (len .Lines) 1}}s{{end}}: {{join .Lines}}</span>{{end}}
+        {{if .Contract}}<br><span style="color:var(--muted);">in <code>{{.Contract}}{{if .Function}}.{{.Function}}{{end}}</code></span>{{end}}
+        {{if .Related}}
+        <details class="related"{{if $.Print}} open{{end}}><summary>+{{len .Related}} more with the same root cause</summary>
+          <ul>{{range .Related}}<li>{{if .Function}}<code>{{.Function}}</code> {{end}}line {{join .Lines}}</li>{{end}}</ul>
+        </details>
+        {{end}}
       </td>
       <td><span class="source-badge">{{.Source}}</span></td>
     </tr>
-    {{end}}
+    {{end}}{{end}}
     </tbody>
   </table>
+  <div class="pager" hidden>
+    <button class="facet" data-page="prev">← Previous</button>
+    <span class="pager-status"></span>
+    <button class="facet" data-page="next">Next →</button>
+  </div>
+
+  <details class="scope"{{if $.Print}} open{{end}}>
+    <summary><h2 style="display:inline;">Findings by File — {{len .Groups}} file(s)</h2></summary>
+    <div class="heat-legend" style="margin-top:0.5rem;">The findings above grouped by file and contract, to walk through the code one contract at a time.</div>
+    {{range .Groups}}
+    <details class="group"{{if $.Print}} open{{end}}>
+      <summary><code>{{.Name}}</code> {{template "groupSummary" .}}</summary>
+      {{range .Contracts}}
+      <details class="group contract"{{if $.Print}} open{{end}}>
+        <summary><code>{{.Name}}</code> {{template "groupSummary" .}}</summary>
+        <ul>
+          {{range .Findings}}
+          <li><span class="badge badge-{{.Severity | severityClass}}">{{.Severity}}</span> <code>{{.ID}}</code> {{.Title}}{{if .Lines}} <span style="color:var(--muted);">— line{{if gt (len .Lines) 1}}s{{end}} {{join .Lines}}{{if .Function}} in <code>{{.Function}}</code>{{end}}</span>{{end}}{{if .CodeURL}} <a class="code-link" href="{{.CodeURL}}" target="_blank" rel="noopener">↗</a>{{end}}</li>
+          {{end}}
+        </ul>
+      </details>
+      {{end}}
+    </details>
+    {{end}}
+  </details>
   {{end}}
 
+  {{if .Graph}}
+  <details class="scope"{{if $.Print}} open{{end}}>
+    <summary><h2 style="display:inline;">Import Graph — {{len .Report.ImportGraph.Nodes}} file(s)</h2></summary>
+    <div class="heat-legend" style="margin-top:0.5rem;">Arrows point from the importing file to its dependency. Dashed nodes are outside the analysis scope; highlighted nodes contain unused contracts.</div>
+    <div class="graph-wrap">{{.Graph}}</div>
+  </details>
+  {{end}}
+
+  {{with .Report.Archetype}}
+  <details class="scope" open>
+    <summary><h2 style="display:inline;">Archetype Review — {{.Name}}</h2></summary>
+    <div class="heat-legend" style="margin-top:0.5rem;">{{.Description}}. The analysis ran this archetype's checks and raised its key findings one severity level. Risks without checks were not evaluated by the engines that ran.</div>
+    <table class="findings-table" style="margin-top:0.75rem;">
+      <thead><tr><th>Risk</th><th>Checks</th><th>Status</th></tr></thead>
+      <tbody>
+      {{range .Risks}}
+      <tr>
+        <td>{{.Title}}</td>
+        <td>{{range .Checks}}<span class="source-badge">{{.}}</span> {{end}}</td>
+        <td>{{if not .Checks}}<span class="swc-ref">Not evaluated</span>{{else if .Findings}}<span class="high">✗ {{.Findings}} finding(s)</span>{{else}}<span class="low">✓ None found</span>{{end}}</td>
+      </tr>
+      {{end}}
+      </tbody>
+    </table>
+  </details>
+  {{end}}
+
+  {{if .Report.Compliance}}
+  <details class="scope"{{if $.Print}} open{{end}}>
+    <summary><h2 style="display:inline;">Compliance Matrix — {{len .Report.Compliance}} control(s) evaluated</h2></summary>
+    <div class="heat-legend" style="margin-top:0.5rem;">EEA EthTrust Security Levels requirements and OWASP SCSVS control areas covered by the checks that ran. A control passes when none of its checks reported a finding; controls not listed were not evaluated.</div>
+    <table class="findings-table" style="margin-top:0.75rem;">
+      <thead><tr><th>Framework</th><th>Control</th><th>Checks</th><th>Status</th></tr></thead>
+      <tbody>
+      {{range .Report.Compliance}}
+      <tr>
+        <td>{{.Framework}}</td>
+        <td><code>{{.Control}}</code></td>
+        <td>{{range .Checks}}<span class="source-badge">{{.}}</span> {{end}}</td>
+        <td>{{if .Findings}}<span class="high">✗ {{.Findings}} finding(s)</span>{{else}}<span class="low">✓ Pass</span>{{end}}</td>
+      </tr>
+      {{end}}
+      </tbody>
+    </table>
+  </details>
+  {{end}}
+
+  {{with .Access}}
+  <details class="scope"{{if $.Print}} open{{end}}>
+    <summary><h2 style="display:inline;">Access Control — {{len .Rows}} state-changing function(s)</h2></summary>
+    <div class="heat-legend" style="margin-top:0.5rem;">Every external or public function that can change state, with the modifiers and inline <code>msg.sender</code> checks that restrict its callers. ANYONE marks functions without a guard.</div>
+    <table class="findings-table" style="margin-top:0.75rem;">
+      <thead><tr><th>Function</th>{{range .Guards}}<th><code>{{.}}</code></th>{{end}}</tr></thead>
+      <tbody>
+      {{range .Rows}}
+      <tr>
+        <td><code>{{.Contract}}.{{.Function}}()</code>{{if .Payable}} <span class="source-badge">payable</span>{{end}}<div class="swc-ref">{{.File}}:{{.Line}}</div></td>
+        {{range $i, $c := .Cells}}<td>{{if $c}}{{if eq (index $.Access.Guards $i) "ANYONE"}}<span class="high">●</span>{{else}}<span class="low">●</span>{{end}}{{end}}</td>{{end}}
+      </tr>
+      {{end}}
+      </tbody>
+    </table>
+  </details>
+  {{end}}
+
+  {{if .Report.AnalyzedFiles}}
+  <details class="scope"{{if $.Print}} open{{end}}>
+    <summary><h2 style="display:inline;">Scope — {{len .Report.AnalyzedFiles}} file(s) analyzed</h2></summary>
+    <table class="findings-table" style="margin-top:0.75rem;">
+      <thead><tr><th>File</th><th>SLOC</th><th>Pragma</th><th>Engines</th><th>SHA-256</th></tr></thead>
+      <tbody>
+      {{range .Report.AnalyzedFiles}}
+      <tr>
+        <td><code>{{.Path}}</code>{{if .Skipped}}<div class="swc-ref high">Skipped by engines: {{.SkipReason}}</div>{{end}}{{if .Excluded}}<div class="swc-ref medium">Skipped by custom checks: {{.Excluded}}</div>{{end}}</td>
+        <td>{{.SLOC}}</td>
+        <td>{{if .Pragma}}<code>{{.Pragma}}</code>{{else}}—{{end}}</td>
+        <td>{{range .Engines}}<span class="source-badge">{{.}}</span> {{end}}</td>
+        <td><code title="{{.SHA256}}">{{printf "%.12s" .SHA256}}…</code></td>
+      </tr>
+      {{end}}
+      </tbody>
+    </table>
+  </details>
+  {{end}}
+
+  {{block "disclaimer" .}}{{end}}
   <footer style="margin-top:2rem; padding-top:1rem; border-top:1px solid var(--border);
     font-size:0.8rem; color:var(--muted); text-align:center;">
-    Generated by <strong>solsec v1.0.0</strong> — Smart Contract Static Analyzer<br>
-    This report is a tool-assisted analysis. Always conduct a manual audit before mainnet deployment.
+    {{block "footer" .}}Generated by <strong>solsec v1.0.0</strong> — Smart Contract Static Analyzer<br>
+    This report is a tool-assisted analysis. Always conduct a manual audit before mainnet deployment.{{end}}
   </footer>
 </div>
+<script>
+  // Findings are filtered by the active tag facet, the severity, source and
+  // check menus and the search text, sorted by the clicked column, then
+  // paginated: only the current page's rows are shown, so huge reports stay
+  // responsive.
+  (function () {
+    var table = document.getElementById('findings');
+    if (!table) return;
+    var tbody = table.tBodies[0];
+    var rows = Array.prototype.slice.call(table.querySelectorAll('tr[data-tags]'));
+    var pageSize = parseInt(table.dataset.pageSize, 10) || 0;
+    var pager = document.querySelector('.pager');
+    var status = document.querySelector('.filter-status');
+    var search = document.getElementById('filter-text');
+    var menus = {
+      severity: document.getElementById('filter-severity'),
+      source: document.getElementById('filter-source'),
+      check: document.getElementById('filter-check')
+    };
+    var tag = '', page = 0;
+    var text = rows.map(function (row) { return row.textContent.toLowerCase(); });
+    rows.forEach(function (row, i) { row.dataset.index = i; });
+
+    // Menus offer the values present, severities in report order.
+    Object.keys(menus).forEach(function (key) {
+      var seen = {};
+      rows.forEach(function (row) {
+        var v = row.dataset[key];
+        if (!v || seen[v]) return;
+        seen[v] = true;
+        var opt = document.createElement('option');
+        opt.value = opt.textContent = v;
+        menus[key].appendChild(opt);
+      });
+      if (key !== 'severity') {
+        Array.prototype.slice.call(menus[key].options, 1)
+          .sort(function (a, b) { return a.value.localeCompare(b.value); })
+          .forEach(function (o) { menus[key].appendChild(o); });
+      }
+      menus[key].addEventListener('change', function () { page = 0; render(); });
+    });
+    search.addEventListener('input', function () { page = 0; render(); });
+
+    function render() {
+      var words = search.value.toLowerCase().split(/\s+/).filter(Boolean);
+      var matching = rows.filter(function (row) {
+        if (tag && row.dataset.tags.split(' ').indexOf(tag) < 0) return false;
+        for (var key in menus) {
+          if (menus[key].value && row.dataset[key] !== menus[key].value) return false;
+        }
+        var t = text[row.dataset.index];
+        return words.every(function (w) { return t.indexOf(w) >= 0; });
+      });
+      var paged = pageSize > 0 && matching.length > pageSize;
+      var pages = paged ? Math.ceil(matching.length / pageSize) : 1;
+      page = Math.min(page, pages - 1);
+      rows.forEach(function (row) { row.hidden = true; });
+      matching.forEach(function (row, i) {
+        row.hidden = paged && Math.floor(i / pageSize) !== page;
+      });
+      status.textContent = matching.length === rows.length
+        ? rows.length + ' findings' : matching.length + ' of ' + rows.length + ' findings';
+      pager.hidden = !paged;
+      if (paged) {
+        var first = page * pageSize + 1, last = Math.min((page + 1) * pageSize, matching.length);
+        pager.querySelector('.pager-status').textContent =
+          'Findings ' + first + '–' + last + ' of ' + matching.length + ' (page ' + (page + 1) + ' of ' + pages + ')';
+        pager.querySelector('[data-page=prev]').disabled = page === 0;
+        pager.querySelector('[data-page=next]').disabled = page === pages - 1;
+      }
+    }
+
+    // Clicking a column header sorts by it, clicking again reverses the
+    // order. Cells sort by their data-key, or their leading element's text.
+    function sortKey(row, col) {
+      var cell = row.cells[col];
+      if (cell.dataset.key !== undefined) return cell.dataset.key;
+      return (cell.firstElementChild || cell).textContent.trim().toLowerCase();
+    }
+    table.querySelectorAll('th[data-sort]').forEach(function (th) {
+      th.addEventListener('click', function () {
+        var col = parseInt(th.dataset.sort, 10);
+        var dir = th.getAttribute('aria-sort') === 'ascending' ? -1 : 1;
+        table.querySelectorAll('th[data-sort]').forEach(function (o) { o.removeAttribute('aria-sort'); });
+        th.setAttribute('aria-sort', dir > 0 ? 'ascending' : 'descending');
+        rows.sort(function (a, b) {
+          var ka = sortKey(a, col), kb = sortKey(b, col);
+          var c = col === 0 ? ka - kb : ka.localeCompare(kb, undefined, {numeric: true});
+          return dir * c || a.dataset.index - b.dataset.index;
+        });
+        rows.forEach(function (row) { tbody.appendChild(row); });
+        page = 0;
+        render();
+      });
+    });
+
+    document.querySelectorAll('.facet[data-tag]').forEach(function (b) {
+      b.addEventListener('click', function () {
+        var active = b.classList.toggle('active');
+        document.querySelectorAll('.facet[data-tag]').forEach(function (o) { if (o !== b) o.classList.remove('active'); });
+        tag = active ? b.dataset.tag : '';
+        page = 0;
+        render();
+      });
+    });
+    pager.querySelectorAll('[data-page]').forEach(function (b) {
+      b.addEventListener('click', function () {
+        page += b.dataset.page === 'next' ? 1 : -1;
+        render();
+        table.scrollIntoView();
+      });
+    });
+    render();
+  })();
+</script>
 </body>
-</html>`
\ No newline at end of file
+</html>
+{{define "groupSummary"}}<span class="group-summary">{{.Summary.Total}} finding{{if ne .Summary.Total 1}}s{{end}}{{with .Summary.Critical}} · <span class="critical">{{.}} critical</span>{{end}}{{with .Summary.High}} · <span class="high">{{.}} high</span>{{end}}{{with .Summary.Medium}} · <span class="medium">{{.}} medium</span>{{end}}{{with .Summary.Low}} · <span class="low">{{.}} low</span>{{end}} · risk {{.Score}}/100 ({{grade .Score}})</span>{{end}}`
\ No newline at end of file
@@ -1,6 +1,8 @@
 package reporter
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -13,27 +15,140 @@ type JSONReporter struct{}
 
 func (r *JSONReporter) Name() string { return "json" }
 
+// Write streams the report: findings are encoded one at a time instead of
+// marshalling the whole report into memory first, so reports with tens of
+// thousands of findings stay cheap to write.
 func (r *JSONReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
-	out := struct {
-		*parser.AnalysisReport
-		RiskScore int    `json:"risk_score"`
-		Grade     string `json:"grade"`
-		Verdict   string `json:"verdict"`
-	}{
-		AnalysisReport: report,
-		RiskScore:       score,
-		Grade:           scorer.Grade(score),
-		Verdict:         scorer.Verdict(score),
-	}
-
-	data, err := json.MarshalIndent(out, "", "  ")
+	return writeFile(outputPath, "JSON report", func(w *bufio.Writer) error {
+		head, tail, err := splitAtFindings(report, score, "", "  ")
+		if err != nil {
+			return err
+		}
+		w.Write(head)
+		w.WriteString(`"findings": [`)
+		for i, f := range report.Findings {
+			if i > 0 {
+				w.WriteString(",")
+			}
+			data, err := json.MarshalIndent(f, "    ", "  ")
+			if err != nil {
+				return fmt.Errorf("marshalling finding %s: %w", f.ID, err)
+			}
+			w.WriteString("\n    ")
+			w.Write(data)
+		}
+		if len(report.Findings) > 0 {
+			w.WriteString("\n  ")
+		}
+		w.WriteString("]")
+		w.Write(tail)
+		return nil
+	})
+}
+
+// NDJSONReporter writes newline-delimited JSON: a "report" record with
+// everything but the findings, then one "finding" record per line, so
+// consumers can process huge reports without loading them whole.
+type NDJSONReporter struct{}
+
+func (r *NDJSONReporter) Name() string { return "ndjson" }
+
+func (r *NDJSONReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
+	return writeFile(outputPath, "NDJSON report", func(w *bufio.Writer) error {
+		head, tail, err := splitAtFindings(report, score, "", "")
+		if err != nil {
+			return err
+		}
+		// Drop the findings key, and the comma joining it to the next one.
+		w.Write(head)
+		w.Write(bytes.TrimPrefix(tail, []byte(",")))
+		w.WriteString("\n")
+
+		enc := json.NewEncoder(w)
+		for i := range report.Findings {
+			record := struct {
+				Type string `json:"type"`
+				*parser.Finding
+			}{"finding", &report.Findings[i]}
+			if err := enc.Encode(record); err != nil {
+				return fmt.Errorf("marshalling finding %s: %w", record.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// jsonReport is the top-level object of JSON reports and the header record
+// of NDJSON reports.
+type jsonReport struct {
+	Type string `json:"type,omitempty"`
+	*parser.AnalysisReport
+	RiskScore int    `json:"risk_score"`
+	Grade     string `json:"grade"`
+	Verdict   string `json:"verdict"`
+
+	ScoreBreakdown scorer.Breakdown `json:"score_breakdown"`
+	Facets         findingFacets    `json:"facets"`
+}
+
+// splitAtFindings marshals the report without its findings and returns the
+// output before the "findings" key and after its value, for the caller to
+// stream the findings in between.
+func splitAtFindings(report *parser.AnalysisReport, score int, prefix, indent string) (head, tail []byte, err error) {
+	withoutFindings := *report
+	withoutFindings.Findings = nil
+	out := jsonReport{
+		AnalysisReport: &withoutFindings,
+		RiskScore:      score,
+		Grade:          scorer.Grade(score),
+		Verdict:        scorer.Verdict(score),
+		ScoreBreakdown: scorer.Explain(report),
+		Facets:         buildFacets(report),
+	}
+	if indent == "" {
+		out.Type = "report"
+	}
+
+	var data []byte
+	if indent == "" {
+		data, err = json.Marshal(out)
+	} else {
+		data, err = json.MarshalIndent(out, prefix, indent)
+	}
 	if err != nil {
-		return fmt.Errorf("marshalling JSON report: %w", err)
+		return nil, nil, fmt.Errorf("marshalling JSON report: %w", err)
 	}
 
-	if err := os.WriteFile(outputPath, data, 0640); err != nil {
-		return fmt.Errorf("writing JSON report to %s: %w", outputPath, err)
+	key := []byte(`"findings":`)
+	i := bytes.Index(data, key)
+	if i < 0 {
+		return nil, nil, fmt.Errorf("marshalling JSON report: no findings key")
 	}
+	value := i + len(key)
+	value += len(data[value:]) - len(bytes.TrimLeft(data[value:], " "))
+	if !bytes.HasPrefix(data[value:], []byte("null")) {
+		return nil, nil, fmt.Errorf("marshalling JSON report: unexpected findings value")
+	}
+	return data[:i], data[value+len("null"):], nil
+}
 
+// writeFile creates path and writes it through a buffered writer.
+func writeFile(path, what string, write func(w *bufio.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return fmt.Errorf("writing %s to %s: %w", what, path, err)
+	}
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s to %s: %w", what, path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %s to %s: %w", what, path, err)
+	}
 	return nil
-}
\ No newline at end of file
+}
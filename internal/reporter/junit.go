@@ -0,0 +1,185 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// JUnitReporter writes JUnit XML for CI test dashboards (Jenkins, TeamCity,
+// Azure Pipelines). Each file is a test suite; each finding in it is a
+// failed test case, and a file without findings has one passing case.
+// Clustered occurrences fail cases of their own, so the failure count
+// matches the findings --fail-on and the --max-* limits count.
+type JUnitReporter struct{}
+
+func (r *JUnitReporter) Name() string { return "junit" }
+
+type junitSuites struct {
+	XMLName   xml.Name     `xml:"testsuites"`
+	Name      string       `xml:"name,attr"`
+	Tests     int          `xml:"tests,attr"`
+	Failures  int          `xml:"failures,attr"`
+	Skipped   int          `xml:"skipped,attr"`
+	Timestamp string       `xml:"timestamp,attr,omitempty"`
+	Suites    []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+	// SystemErr carries the report's warnings about the file.
+	SystemErr string `xml:"system-err,omitempty"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func (r *JUnitReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
+	byFile := map[string][]junitCase{}
+	add := func(f parser.Finding, parent *parser.Finding) {
+		file := f.File
+		if file == "" {
+			file = report.Target
+		}
+		byFile[file] = append(byFile[file], junitFinding(f, file, parent))
+	}
+	for _, f := range report.Findings {
+		add(f, nil)
+		for _, rel := range f.Related {
+			add(rel, &f)
+		}
+	}
+	for _, af := range report.AnalyzedFiles {
+		if len(byFile[af.Path]) > 0 {
+			continue
+		}
+		c := junitCase{Name: "no findings", Classname: af.Path, File: af.Path}
+		if af.Skipped && af.Excluded != "" {
+			// Neither the engines nor the custom checks read the file.
+			c.Skipped = &junitSkipped{Message: af.SkipReason + "; " + af.Excluded}
+		}
+		byFile[af.Path] = []junitCase{c}
+	}
+
+	// Warnings go to the suite of their file, or of the target when they
+	// name none.
+	warnings := map[string][]string{}
+	for _, w := range report.Warnings {
+		file := w.File
+		if file == "" {
+			file = report.Target
+		}
+		warnings[file] = append(warnings[file], "warning: "+w.String())
+		if _, ok := byFile[file]; !ok {
+			byFile[file] = nil
+		}
+	}
+
+	out := junitSuites{Name: "solsec", Timestamp: report.GeneratedAt}
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		s := junitSuite{Name: file, Cases: byFile[file], SystemErr: strings.Join(warnings[file], "\n")}
+		for _, c := range s.Cases {
+			s.Tests++
+			if c.Failure != nil {
+				s.Failures++
+			}
+			if c.Skipped != nil {
+				s.Skipped++
+			}
+		}
+		out.Tests += s.Tests
+		out.Failures += s.Failures
+		out.Skipped += s.Skipped
+		out.Suites = append(out.Suites, s)
+	}
+
+	return writeFile(outputPath, "JUnit report", func(w *bufio.Writer) error {
+		w.WriteString(xml.Header)
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return fmt.Errorf("encoding JUnit report: %w", err)
+		}
+		w.WriteString("\n")
+		return nil
+	})
+}
+
+// junitFinding is the failed test case for f. The failure text links the
+// occurrences of a cluster: parent is the finding f is clustered under, or
+// nil for a parent, which lists its related occurrences.
+func junitFinding(f parser.Finding, file string, parent *parser.Finding) junitCase {
+	var text strings.Builder
+	text.WriteString(f.Description)
+	if len(f.Lines) > 0 {
+		lines := make([]string, len(f.Lines))
+		for i, l := range f.Lines {
+			lines[i] = fmt.Sprint(l)
+		}
+		fmt.Fprintf(&text, "\nLines: %s", strings.Join(lines, ", "))
+	}
+	if parent != nil {
+		fmt.Fprintf(&text, "\nSame root cause as %s at %s", parent.ID, junitLocation(*parent))
+	}
+	for _, rel := range f.Related {
+		fmt.Fprintf(&text, "\nSame root cause at %s", junitLocation(rel))
+	}
+	if f.Remediation != "" {
+		fmt.Fprintf(&text, "\nRemediation: %s", f.Remediation)
+	}
+	return junitCase{
+		Name:      f.ID + ": " + f.Title,
+		Classname: file,
+		File:      f.File,
+		Line:      firstLine(f),
+		Failure: &junitFailure{
+			Message: fmt.Sprintf("[%s] %s", f.Severity, f.Title),
+			Type:    f.Check,
+			Text:    text.String(),
+		},
+	}
+}
+
+// junitLocation is f's file:line, or its file alone when it has no line.
+func junitLocation(f parser.Finding) string {
+	if line := firstLine(f); line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, line)
+	}
+	return f.File
+}
+
+func firstLine(f parser.Finding) int {
+	if len(f.Lines) > 0 {
+		return f.Lines[0]
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJUnitReporter(t *testing.T) {
+	root := t.TempDir()
+	var out junitSuites
+	require.NoError(t, xml.Unmarshal(writeReport(t, &JUnitReporter{}, testReport(root)), &out))
+
+	assert.Equal(t, 5, out.Tests)
+	assert.Equal(t, 4, out.Failures, "every clustered occurrence fails a case")
+	require.Len(t, out.Suites, 3)
+
+	suites := map[string]junitSuite{}
+	for _, s := range out.Suites {
+		suites[s.Name] = s
+	}
+	clean := suites[filepath.Join(root, "src", "Clean.sol")]
+	require.Len(t, clean.Cases, 1, "a clean file has a passing case")
+	assert.Equal(t, "no findings", clean.Cases[0].Name)
+	assert.Nil(t, clean.Cases[0].Failure)
+	assert.Zero(t, clean.Failures)
+
+	vault := filepath.Join(root, "src", "Vault.sol")
+	cases := suites[vault].Cases
+	require.Len(t, cases, 2)
+	assert.Equal(t, 10, cases[0].Line)
+	assert.Equal(t, "[High] Missing access control", cases[0].Failure.Message)
+	assert.Contains(t, cases[0].Failure.Text, "Same root cause at "+vault+":20")
+	assert.Equal(t, "[Medium] Missing access control", cases[1].Failure.Message)
+	assert.Contains(t, cases[1].Failure.Text, "Same root cause as SOLSEC-001 at "+vault+":10")
+}
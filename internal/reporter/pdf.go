@@ -0,0 +1,100 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// PDFEngineEnv overrides the headless browser (or wkhtmltopdf) used to
+// render PDF reports.
+const PDFEngineEnv = "SOLSEC_PDF_ENGINE"
+
+// pdfEngines are looked up on PATH in order when PDFEngineEnv is unset.
+var pdfEngines = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "microsoft-edge", "wkhtmltopdf"}
+
+const pdfTimeout = 2 * time.Minute
+
+// PDFReporter renders the HTML report for print (every section expanded, no
+// paging, light colors) and converts it to PDF with a headless browser.
+type PDFReporter struct {
+	// Verbose renders each finding's evidence.
+	Verbose bool
+	// Template is a custom HTML template (see HTMLReporter.Template).
+	Template string
+}
+
+func (r *PDFReporter) Name() string { return "pdf" }
+
+func (r *PDFReporter) Write(report *parser.AnalysisReport, score int, outputPath string) error {
+	engine, err := DetectPDFEngine()
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "solsec-pdf-")
+	if err != nil {
+		return fmt.Errorf("creating PDF workspace: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	page := filepath.Join(dir, "report.html")
+	html := &HTMLReporter{Verbose: r.Verbose, Print: true, Template: r.Template}
+	if err := html.Write(report, score, page); err != nil {
+		return err
+	}
+
+	out, err := filepath.Abs(outputPath)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", outputPath, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pdfTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, engine, pdfArgs(engine, page, out)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("rendering PDF with %s: %w\n%s", filepath.Base(engine), err, strings.TrimSpace(string(output)))
+	}
+	if _, err := os.Stat(out); err != nil {
+		return fmt.Errorf("rendering PDF with %s: no output written", filepath.Base(engine))
+	}
+	return nil
+}
+
+// DetectPDFEngine returns the path of the program that renders PDF reports:
+// $SOLSEC_PDF_ENGINE, else the first Chromium-based browser or wkhtmltopdf
+// on PATH.
+func DetectPDFEngine() (string, error) {
+	if engine := os.Getenv(PDFEngineEnv); engine != "" {
+		path, err := exec.LookPath(engine)
+		if err != nil {
+			return "", fmt.Errorf("%s=%s: %w", PDFEngineEnv, engine, err)
+		}
+		return path, nil
+	}
+	for _, name := range pdfEngines {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("PDF output needs a headless browser, none found on PATH\n\n" +
+		"Install Chromium or Google Chrome (or wkhtmltopdf), or set " + PDFEngineEnv + " to its path")
+}
+
+// pdfArgs returns the command line that prints page to out with engine.
+func pdfArgs(engine, page, out string) []string {
+	if strings.Contains(filepath.Base(engine), "wkhtmltopdf") {
+		return []string{"--quiet", "--enable-local-file-access", "--print-media-type", page, out}
+	}
+	args := []string{"--headless", "--disable-gpu", "--no-pdf-header-footer", "--print-to-pdf=" + out}
+	if os.Geteuid() == 0 {
+		// Chromium refuses to run as root (e.g. in CI containers) with its sandbox.
+		args = append(args, "--no-sandbox")
+	}
+	return append(args, (&url.URL{Scheme: "file", Path: page}).String())
+}
@@ -1,10 +1,31 @@
 package reporter
 
-import "github.com/Zubimendi/solsec/internal/parser"
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
 
 // Reporter is implemented by every output format.
 // Adding a new format means implementing this one interface — nothing else changes.
 type Reporter interface {
 	Write(report *parser.AnalysisReport, score int, outputPath string) error
 	Name() string
-}
\ No newline at end of file
+}
+
+// relativePath returns file relative to root, with forward slashes, when it
+// is inside root. Otherwise, or when root is empty, ok is false and path is
+// file with forward slashes. Code hosts map report locations to files by
+// their path in the repository, so absolute paths (such as Slither's) only
+// resolve on the machine that ran the analysis.
+func relativePath(root, file string) (path string, ok bool) {
+	if root != "" && file != "" {
+		if abs, err := filepath.Abs(file); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return filepath.ToSlash(rel), true
+			}
+		}
+	}
+	return filepath.ToSlash(file), false
+}
@@ -0,0 +1,79 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// testReport is a report on the repository at root: a clustered finding in
+// Vault.sol, an imported finding whose title looks like a formula, a
+// file-level finding, and a clean file.
+func testReport(root string) *parser.AnalysisReport {
+	vault := filepath.Join(root, "src", "Vault.sol")
+	return &parser.AnalysisReport{
+		Target:      root,
+		GeneratedAt: "2026-01-02T03:04:05Z",
+		Findings: []parser.Finding{
+			{
+				ID: "SOLSEC-001", Fingerprint: "0a1b2c3d4e5f6071", Check: "access-control",
+				Title: "Missing access control", Description: "setOwner lacks onlyOwner.",
+				Severity: parser.SeverityHigh, Confidence: "High", File: vault, Lines: []int{10, 12},
+				Contract: "Vault", Function: "setOwner", Tags: []string{"access-control"},
+				SWCRef: "SWC-105", CWERef: "CWE-284", Remediation: "Add onlyOwner.",
+				Related: []parser.Finding{{
+					ID: "SOLSEC-002", Fingerprint: "1a2b3c4d5e6f7081", Check: "access-control",
+					Title: "Missing access control", Severity: parser.SeverityMedium, File: vault, Lines: []int{20},
+					Contract: "Vault", Function: "setFee", Tags: []string{"access-control"},
+				}},
+			},
+			{
+				ID: "SOLSEC-003", Check: "imported", Title: `=HYPERLINK("https://example.com")`,
+				Severity: parser.SeverityLow, Confidence: "Medium", File: filepath.Join(root, "src", "Token.sol"),
+				Lines: []int{5}, Tags: []string{"token"},
+			},
+			{
+				ID: "SOLSEC-004", Check: "license", Title: "Missing SPDX license identifier",
+				Severity: parser.SeverityInformational, Confidence: "High", File: filepath.Join(root, "src", "Token.sol"),
+			},
+		},
+		AnalyzedFiles: []parser.AnalyzedFile{
+			{Path: vault},
+			{Path: filepath.Join(root, "src", "Token.sol")},
+			{Path: filepath.Join(root, "src", "Clean.sol")},
+		},
+	}
+}
+
+// writeReport writes report with r and returns the output.
+func writeReport(t *testing.T, r Reporter, report *parser.AnalysisReport) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "report")
+	require.NoError(t, r.Write(report, 42, path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+func TestRelativePath(t *testing.T) {
+	root := t.TempDir()
+	cases := []struct {
+		root, file string
+		want       string
+		ok         bool
+	}{
+		{root, filepath.Join(root, "src", "Vault.sol"), "src/Vault.sol", true},
+		{root, filepath.Join(filepath.Dir(root), "other", "Vault.sol"), filepath.ToSlash(filepath.Join(filepath.Dir(root), "other", "Vault.sol")), false},
+		{"", filepath.Join(root, "Vault.sol"), filepath.ToSlash(filepath.Join(root, "Vault.sol")), false},
+		{root, "", "", false},
+	}
+	for _, c := range cases {
+		got, ok := relativePath(c.root, c.file)
+		assert.Equal(t, c.want, got, c.file)
+		assert.Equal(t, c.ok, ok, c.file)
+	}
+}
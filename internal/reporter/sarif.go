@@ -1,9 +1,14 @@
 package reporter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/Zubimendi/solsec/internal/parser"
 )
@@ -18,8 +23,21 @@ type sarifOutput struct {
 }
 
 type sarifRun struct {
-	Tool    sarifTool    `json:"tool"`
-	Results []sarifResult `json:"results"`
+	Tool        sarifTool         `json:"tool"`
+	Results     []sarifResult     `json:"results"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+}
+
+// sarifInvocation is only emitted for interrupted runs, to flag the results
+// as partial.
+type sarifInvocation struct {
+	ExecutionSuccessful        bool           `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Message sarifMessage `json:"message"`
+	Level   string       `json:"level"`
 }
 
 type sarifTool struct {
@@ -37,6 +55,13 @@ type sarifRule struct {
 	Name             string             `json:"name"`
 	ShortDescription sarifMessage       `json:"shortDescription"`
 	HelpURI          string             `json:"helpUri,omitempty"`
+	Properties       *sarifProperties   `json:"properties,omitempty"`
+}
+
+// sarifProperties carries rule tags, which GitHub code scanning shows as
+// filterable labels.
+type sarifProperties struct {
+	Tags []string `json:"tags,omitempty"`
 }
 
 type sarifResult struct {
@@ -44,14 +69,43 @@ type sarifResult struct {
 	Level     string          `json:"level"`
 	Message   sarifMessage    `json:"message"`
 	Locations []sarifLocation `json:"locations"`
+
+	// RelatedLocations point at the other occurrences of a clustered finding.
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+
+	// PartialFingerprints let code scanning match an alert across commits
+	// by its code rather than its line number.
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+
+	// CodeFlows walk through the finding's Flow, which code scanning shows
+	// step by step.
+	CodeFlows []sarifCodeFlow `json:"codeFlows,omitempty"`
+}
+
+type sarifCodeFlow struct {
+	ThreadFlows []sarifThreadFlow `json:"threadFlows"`
+}
+
+type sarifThreadFlow struct {
+	Locations []sarifThreadFlowLocation `json:"locations"`
 }
 
+type sarifThreadFlowLocation struct {
+	Location sarifLocation `json:"location"`
+}
+
+// sarifFingerprintKey names solsec's partial fingerprint: the finding's
+// fingerprint (check, file, enclosing function and normalized code of the
+// flagged line) and its occurrence among findings sharing it.
+const sarifFingerprintKey = "solsecFingerprint/v1"
+
 type sarifMessage struct {
 	Text string `json:"text"`
 }
 
 type sarifLocation struct {
 	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
 }
 
 type sarifPhysicalLocation struct {
@@ -61,13 +115,27 @@ type sarifPhysicalLocation struct {
 
 type sarifArtifact struct {
 	URI string `json:"uri"`
+
+	// URIBaseID marks URIs relative to the repository root.
+	URIBaseID string `json:"uriBaseId,omitempty"`
 }
 
+// sarifSrcRoot is the conventional base for repository-relative URIs.
+const sarifSrcRoot = "%SRCROOT%"
+
 type sarifRegion struct {
 	StartLine int `json:"startLine"`
 }
 
-type SARIFReporter struct{}
+type SARIFReporter struct {
+	// Verbose appends each finding's evidence to the result message.
+	Verbose bool
+
+	// RepoRoot, when set, makes artifact URIs relative to the repository
+	// root, which is how code scanning maps results to files. Files outside
+	// it keep their path.
+	RepoRoot string
+}
 
 func (r *SARIFReporter) Name() string { return "sarif" }
 
@@ -87,6 +155,11 @@ func (r *SARIFReporter) Write(report *parser.AnalysisReport, score int, outputPa
 					return ""
 				}(),
 			}
+			if len(f.Tags) > 0 {
+				r := ruleMap[f.Check]
+				r.Properties = &sarifProperties{Tags: f.Tags}
+				ruleMap[f.Check] = r
+			}
 		}
 	}
 
@@ -94,30 +167,40 @@ func (r *SARIFReporter) Write(report *parser.AnalysisReport, score int, outputPa
 	for _, r := range ruleMap {
 		rules = append(rules, r)
 	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
 
 	// Build results
 	results := make([]sarifResult, 0, len(report.Findings))
+	occurrences := map[string]int{}
 	for _, f := range report.Findings {
-		startLine := 1
-		if len(f.Lines) > 0 {
-			startLine = f.Lines[0]
+		text := fmt.Sprintf("%s\n\nRemediation: %s", f.Description, f.Remediation)
+		if len(f.Related) > 0 {
+			text += fmt.Sprintf("\n\nThe same root cause affects %d other location(s) in %s.", len(f.Related), f.Contract)
+		}
+		if r.Verbose && len(f.Evidence) > 0 {
+			text += "\n\nEvidence:\n- " + strings.Join(f.Evidence, "\n- ")
 		}
 
-		results = append(results, sarifResult{
+		result := sarifResult{
 			RuleID: f.Check,
 			Level:  severityToSARIFLevel(f.Severity),
 			Message: sarifMessage{
-				Text: fmt.Sprintf("%s\n\nRemediation: %s", f.Description, f.Remediation),
-			},
-			Locations: []sarifLocation{
-				{
-					PhysicalLocation: sarifPhysicalLocation{
-						ArtifactLocation: sarifArtifact{URI: f.File},
-						Region:           sarifRegion{StartLine: startLine},
-					},
-				},
+				Text: text,
 			},
-		})
+			Locations: []sarifLocation{r.locationOf(f)},
+		}
+		fp := sarifFingerprint(f)
+		occurrences[fp]++
+		result.PartialFingerprints = map[string]string{
+			sarifFingerprintKey: fmt.Sprintf("%s:%d", fp, occurrences[fp]),
+		}
+		for _, rel := range f.Related {
+			result.RelatedLocations = append(result.RelatedLocations, r.locationOf(rel))
+		}
+		if len(f.Flow) > 0 {
+			result.CodeFlows = []sarifCodeFlow{r.codeFlowOf(f.Flow)}
+		}
+		results = append(results, result)
 	}
 
 	output := sarifOutput{
@@ -136,6 +219,29 @@ func (r *SARIFReporter) Write(report *parser.AnalysisReport, score int, outputPa
 			},
 		},
 	}
+	if report.Interrupted || len(report.Degraded) > 0 || len(report.Warnings) > 0 {
+		invocation := sarifInvocation{ExecutionSuccessful: !report.Interrupted}
+		if report.Interrupted {
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Message: sarifMessage{Text: "Analysis was interrupted; results are partial."},
+				Level:   "warning",
+			})
+		}
+		for _, d := range report.Degraded {
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Message: sarifMessage{Text: fmt.Sprintf("Custom check %s %s; its results are missing.", d.Check, d.Reason)},
+				Level:   "warning",
+			})
+		}
+		for _, w := range report.Warnings {
+			n := sarifNotification{Message: sarifMessage{Text: w.String()}, Level: "warning"}
+			if w.Kind == parser.WarningDedup {
+				n.Level = "note"
+			}
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, n)
+		}
+		output.Runs[0].Invocations = []sarifInvocation{invocation}
+	}
 
 	data, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
@@ -154,4 +260,51 @@ func severityToSARIFLevel(s parser.Severity) string {
 	default:
 		return "note"
 	}
-}
\ No newline at end of file
+}
+
+// sarifFingerprint is the finding's fingerprint, or for findings loaded
+// from reports that predate fingerprints, a hash of its check, file and
+// title.
+func sarifFingerprint(f parser.Finding) string {
+	if f.Fingerprint != "" {
+		return f.Fingerprint
+	}
+	sum := sha256.Sum256([]byte(f.Check + "\x00" + f.File + "\x00" + f.Title))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (r *SARIFReporter) locationOf(f parser.Finding) sarifLocation {
+	startLine := 1
+	if len(f.Lines) > 0 {
+		startLine = f.Lines[0]
+	}
+	return sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: r.artifactOf(f.File),
+			Region:           sarifRegion{StartLine: startLine},
+		},
+	}
+}
+
+func (r *SARIFReporter) codeFlowOf(flow []parser.FlowStep) sarifCodeFlow {
+	var thread sarifThreadFlow
+	for _, step := range flow {
+		thread.Locations = append(thread.Locations, sarifThreadFlowLocation{Location: sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: r.artifactOf(step.File),
+				Region:           sarifRegion{StartLine: max(step.Line, 1)},
+			},
+			Message: &sarifMessage{Text: step.Message},
+		}})
+	}
+	return sarifCodeFlow{ThreadFlows: []sarifThreadFlow{thread}}
+}
+
+// artifactOf locates file relative to the repository root when it is inside
+// it, with forward slashes as URIs require.
+func (r *SARIFReporter) artifactOf(file string) sarifArtifact {
+	if rel, ok := relativePath(r.RepoRoot, file); ok {
+		return sarifArtifact{URI: rel, URIBaseID: sarifSrcRoot}
+	}
+	return sarifArtifact{URI: filepath.ToSlash(file)}
+}
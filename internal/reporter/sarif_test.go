@@ -0,0 +1,51 @@
+package reporter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestSARIFReporter_FingerprintsSurviveLineShift(t *testing.T) {
+	root := t.TempDir()
+	write := func(report *parser.AnalysisReport) []sarifResult {
+		var out sarifOutput
+		require.NoError(t, json.Unmarshal(writeReport(t, &SARIFReporter{RepoRoot: root}, report), &out))
+		require.Len(t, out.Runs, 1)
+		return out.Runs[0].Results
+	}
+
+	report := testReport(root)
+	before := write(report)
+	for i := range report.Findings {
+		f := &report.Findings[i]
+		for j := range f.Lines {
+			f.Lines[j] += 3
+		}
+	}
+	after := write(report)
+
+	require.Len(t, after, len(before))
+	for i := range before {
+		if len(report.Findings[i].Lines) > 0 {
+			assert.NotEqual(t, before[i].Locations, after[i].Locations)
+		}
+		assert.Equal(t, before[i].PartialFingerprints, after[i].PartialFingerprints, before[i].RuleID)
+	}
+	assert.Equal(t, "src/Vault.sol", after[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 13, after[0].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestSARIFReporter_RepeatedFingerprints(t *testing.T) {
+	report := testReport(t.TempDir())
+	report.Findings[1].Fingerprint = report.Findings[0].Fingerprint
+
+	var out sarifOutput
+	require.NoError(t, json.Unmarshal(writeReport(t, &SARIFReporter{}, report), &out))
+	results := out.Runs[0].Results
+	assert.Equal(t, "0a1b2c3d4e5f6071:1", results[0].PartialFingerprints[sarifFingerprintKey])
+	assert.Equal(t, "0a1b2c3d4e5f6071:2", results[1].PartialFingerprints[sarifFingerprintKey], "each occurrence is told apart")
+}
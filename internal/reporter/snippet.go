@@ -0,0 +1,206 @@
+package reporter
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+// snippetContext is how many lines are shown around a finding's lines.
+const snippetContext = 3
+
+// maxSnippet caps the lines shown for findings spanning a whole function.
+const maxSnippet = 25
+
+// snippets renders the source excerpts embedded in the HTML report, reading
+// each flagged file once.
+type snippets struct {
+	files map[string][]string // lines by path; nil when unreadable
+}
+
+func newSnippets() *snippets {
+	return &snippets{files: map[string][]string{}}
+}
+
+// render returns f's lines with snippetContext lines around them as a
+// highlighted <pre> block, or "" when there is nothing to show: no location,
+// a file that cannot be read (e.g. a report rendered on another machine) or
+// one over the default size limits. Secret findings are never excerpted, as
+// the line holds the very credential the finding redacts.
+func (s *snippets) render(f parser.Finding) string {
+	lines, first, last, ok := s.excerpt(f)
+	if !ok {
+		return ""
+	}
+	flagged := map[int]bool{}
+	for _, l := range f.Lines {
+		flagged[l] = true
+	}
+
+	highlight := html.EscapeString
+	if strings.EqualFold(filepath.Ext(f.File), ".sol") {
+		h := &solidityHighlighter{}
+		for _, line := range lines[:first-1] {
+			h.line(line) // carry block comments into the excerpt
+		}
+		highlight = h.line
+	}
+
+	var b strings.Builder
+	b.WriteString(`<pre class="snippet">`)
+	for n := first; n <= last; n++ {
+		class := "line"
+		if flagged[n] {
+			class += " flagged"
+		}
+		fmt.Fprintf(&b, `<span class="%s"><span class="ln">%d</span>%s</span>`, class, n, highlight(lines[n-1]))
+	}
+	b.WriteString(`</pre>`)
+	return b.String()
+}
+
+// excerpt returns the lines of f's file and the range of them to show, or
+// false when f cannot be excerpted.
+func (s *snippets) excerpt(f parser.Finding) (lines []string, first, last int, ok bool) {
+	if f.File == "" || len(f.Lines) == 0 || isSecret(f) {
+		return nil, 0, 0, false
+	}
+	lines = s.lines(f.File)
+	if lines == nil {
+		return nil, 0, 0, false
+	}
+	first, last = f.Lines[0], f.Lines[0]
+	for _, l := range f.Lines {
+		first, last = min(first, l), max(last, l)
+	}
+	if first < 1 || first > len(lines) {
+		return nil, 0, 0, false // the file changed since the analysis
+	}
+	first = max(1, first-snippetContext)
+	last = min(len(lines), last+snippetContext, first+maxSnippet-1)
+	return lines, first, last, true
+}
+
+// lines returns the lines of path, or nil when it cannot be excerpted.
+func (s *snippets) lines(path string) []string {
+	if lines, ok := s.files[path]; ok {
+		return lines
+	}
+	var lines []string
+	if source.DefaultLimits.Screen(path) == "" {
+		if data, err := os.ReadFile(path); err == nil {
+			text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+			lines = strings.Split(text, "\n")
+		}
+	}
+	s.files[path] = lines
+	return lines
+}
+
+func isSecret(f parser.Finding) bool {
+	return slices.Contains(f.Tags, "secrets") || slices.Contains(rules.Default().Tags(f.Check), "secrets")
+}
+
+var (
+	solidityKeywords = wordSet(`abstract anonymous as assembly break catch constant constructor continue
+		contract delete do else emit enum error event external fallback for function if immutable import
+		indexed interface internal is library mapping memory modifier new override payable pragma private
+		public pure receive return returns revert storage struct calldata throw try type unchecked using
+		view virtual while true false`)
+	solidityTypes = wordSet(`address bool string bytes byte int uint fixed ufixed`)
+)
+
+func wordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// solidityHighlighter marks up Solidity a line at a time, wrapping keywords,
+// types, literals and comments in tok-* spans. It tracks block comments
+// across lines; everything else is escaped as is.
+type solidityHighlighter struct {
+	inComment bool
+}
+
+func (h *solidityHighlighter) line(line string) string {
+	var b strings.Builder
+	span := func(class, text string) {
+		fmt.Fprintf(&b, `<span class="tok-%s">%s</span>`, class, html.EscapeString(text))
+	}
+
+	i := 0
+	for i < len(line) {
+		c := line[i]
+		switch {
+		case h.inComment:
+			end := strings.Index(line[i:], "*/")
+			if end < 0 {
+				span("comment", line[i:])
+				return b.String()
+			}
+			span("comment", line[i:i+end+2])
+			i += end + 2
+			h.inComment = false
+		case strings.HasPrefix(line[i:], "//"):
+			span("comment", line[i:])
+			return b.String()
+		case strings.HasPrefix(line[i:], "/*"):
+			h.inComment = true
+			span("comment", "/*")
+			i += 2
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(line) && line[j] != c {
+				if line[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(line))
+			span("string", line[i:j])
+			i = j
+		case isDigit(c):
+			j := i + 1
+			for j < len(line) && (isWordByte(line[j]) || line[j] == '.') {
+				j++
+			}
+			span("number", line[i:j])
+			i = j
+		case isWordByte(c):
+			j := i + 1
+			for j < len(line) && isWordByte(line[j]) {
+				j++
+			}
+			word := line[i:j]
+			switch {
+			case solidityKeywords[word]:
+				span("keyword", word)
+			case solidityTypes[strings.TrimRight(word, "0123456789")]:
+				span("type", word)
+			default:
+				b.WriteString(html.EscapeString(word))
+			}
+			i = j
+		default:
+			b.WriteString(html.EscapeString(line[i : i+1]))
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,71 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// sparkSeries is one labelled line of the trend header.
+type sparkSeries struct {
+	Label  string
+	Class  string
+	Latest int
+	SVG    string
+}
+
+// trendSeries extracts the score and per-severity series from a trend.
+func trendSeries(t *parser.Trend) []sparkSeries {
+	if t == nil || len(t.Points) < 2 {
+		return nil
+	}
+	pick := func(f func(parser.TrendPoint) int) []int {
+		vals := make([]int, len(t.Points))
+		for i, p := range t.Points {
+			vals[i] = f(p)
+		}
+		return vals
+	}
+	series := []struct {
+		label, class string
+		vals         []int
+	}{
+		{"Score", "info", pick(func(p parser.TrendPoint) int { return p.Score })},
+		{"Critical", "critical", pick(func(p parser.TrendPoint) int { return p.Summary.Critical })},
+		{"High", "high", pick(func(p parser.TrendPoint) int { return p.Summary.High })},
+		{"Medium", "medium", pick(func(p parser.TrendPoint) int { return p.Summary.Medium })},
+		{"Low", "low", pick(func(p parser.TrendPoint) int { return p.Summary.Low })},
+	}
+
+	out := make([]sparkSeries, 0, len(series))
+	for _, s := range series {
+		out = append(out, sparkSeries{
+			Label:  s.label,
+			Class:  s.class,
+			Latest: s.vals[len(s.vals)-1],
+			SVG:    sparkline(s.vals, 120, 24),
+		})
+	}
+	return out
+}
+
+// sparkline renders values as a minimal inline SVG polyline.
+func sparkline(vals []int, width, height int) string {
+	maxVal := 1
+	for _, v := range vals {
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+	step := float64(width-4) / float64(len(vals)-1)
+	pts := make([]string, len(vals))
+	for i, v := range vals {
+		x := 2 + float64(i)*step
+		y := float64(height-2) - float64(v)/float64(maxVal)*float64(height-4)
+		pts[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	last := strings.Split(pts[len(pts)-1], ",")
+	return fmt.Sprintf(`<svg width="%d" height="%d" viewBox="0 0 %d %d"><polyline fill="none" stroke="currentColor" stroke-width="1.5" points="%s"/><circle cx="%s" cy="%s" r="2" fill="currentColor"/></svg>`,
+		width, height, width, height, strings.Join(pts, " "), last[0], last[1])
+}
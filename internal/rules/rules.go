@@ -0,0 +1,246 @@
+package rules
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/offline"
+)
+
+// DefaultUpdateURL is where `solsec update-db` fetches the latest rules bundle.
+const DefaultUpdateURL = "https://raw.githubusercontent.com/Zubimendi/solsec/main/internal/rules/data/rules.json"
+
+//go:embed data/rules.json
+var embeddedBundle []byte
+
+// DB is the versioned rules bundle: remediation guidance, SWC/CWE mappings and
+// the signature lists custom checks match against. It ships embedded in the
+// binary and can be refreshed independently with `solsec update-db`.
+type DB struct {
+	Version    int                 `json:"version"`
+	Rules      map[string]Rule     `json:"rules"`
+	Signatures map[string][]string `json:"signatures"`
+
+	// Origin records where the bundle was loaded from ("embedded" or a file path).
+	Origin string `json:"-"`
+}
+
+// Rule holds the knowledge attached to a single detector or custom check.
+type Rule struct {
+	Remediation string `json:"remediation,omitempty"`
+	SWC         string `json:"swc,omitempty"`
+	CWE         string `json:"cwe,omitempty"`
+
+	// Tags group rules into categories such as "reentrancy",
+	// "access-control", "defi", "upgradeability" or "gas".
+	Tags []string `json:"tags,omitempty"`
+
+	// EthTrust and SCSVS map the rule to the controls it evaluates: EEA
+	// EthTrust Security Levels requirements (e.g. "[S] No tx.origin") and
+	// OWASP SCSVS control areas (e.g. "SCSVS-AUTH"), which outlive the
+	// unmaintained SWC registry.
+	EthTrust []string `json:"ethtrust,omitempty"`
+	SCSVS    []string `json:"scsvs,omitempty"`
+
+	// Attack and Fix are step-by-step narratives for explain-finding. They
+	// may reference {contract}, {function}, {file} and {line}.
+	Attack []string `json:"attack,omitempty"`
+	Fix    []string `json:"fix,omitempty"`
+}
+
+var (
+	loadOnce sync.Once
+	active   *DB
+)
+
+// Default returns the active rules bundle. A cached bundle from a previous
+// `update-db` wins when it is valid and at least as new as the embedded copy;
+// otherwise the embedded copy is used, so analysis always works offline.
+func Default() *DB {
+	loadOnce.Do(func() {
+		active = mustEmbedded()
+		if cached, err := LoadFile(CachePath()); err == nil && cached.Version >= active.Version {
+			active = cached
+		}
+	})
+	return active
+}
+
+// Embedded returns the bundle compiled into the binary.
+func Embedded() (*DB, error) {
+	db, err := Decode(embeddedBundle)
+	if err != nil {
+		return nil, err
+	}
+	db.Origin = "embedded"
+	return db, nil
+}
+
+func mustEmbedded() *DB {
+	db, err := Embedded()
+	if err != nil {
+		panic(fmt.Sprintf("embedded rules bundle is invalid: %v", err))
+	}
+	return db
+}
+
+// LoadFile reads and validates a rules bundle from disk.
+func LoadFile(path string) (*DB, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	db.Origin = path
+	return db, nil
+}
+
+// requiredSignatures are the signature lists custom checks match against.
+// Without one, a check such as reentrancy would silently find nothing.
+var requiredSignatures = []string{"external-call", "state-change", "reentrancy-guard", "access-modifier"}
+
+// Decode parses and validates raw bundle bytes.
+func Decode(data []byte) (*DB, error) {
+	var db DB
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("parsing rules bundle: %w", err)
+	}
+	if db.Version <= 0 {
+		return nil, fmt.Errorf("rules bundle has no version")
+	}
+	if len(db.Rules) == 0 {
+		return nil, fmt.Errorf("rules bundle contains no rules")
+	}
+	for _, name := range requiredSignatures {
+		if len(db.Signatures[name]) == 0 {
+			return nil, fmt.Errorf("rules bundle has no %q signatures", name)
+		}
+	}
+	return &db, nil
+}
+
+// CachePath is where updated bundles are stored.
+func CachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "solsec", "rules.json")
+}
+
+// Update downloads a bundle from url, validates it, and installs it in the cache.
+// The cached bundle is only replaced when the download is newer than what is
+// currently active, so a stale mirror can never downgrade the rules.
+func Update(ctx context.Context, url string) (*DB, error) {
+	if err := offline.Guard("downloading rules bundle"); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading rules bundle: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading rules bundle: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading rules bundle: %w", err)
+	}
+	db, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	current := Default()
+	if db.Version <= current.Version {
+		return current, nil
+	}
+
+	path := CachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0640); err != nil {
+		return nil, fmt.Errorf("writing rules bundle: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("installing rules bundle: %w", err)
+	}
+
+	db.Origin = path
+	active = db
+	return db, nil
+}
+
+// Remediation returns fix guidance for a check, or "" when none is known.
+func (db *DB) Remediation(check string) string {
+	return db.Rules[check].Remediation
+}
+
+// SWC returns the SWC registry ID for a check, or "".
+func (db *DB) SWC(check string) string {
+	return db.Rules[check].SWC
+}
+
+// CWE returns the CWE ID for a check, or "".
+func (db *DB) CWE(check string) string {
+	return db.Rules[check].CWE
+}
+
+// Tags returns the categories of a check, or nil.
+func (db *DB) Tags(check string) []string {
+	return db.Rules[check].Tags
+}
+
+// EthTrust returns the EEA EthTrust requirements a check evaluates, or nil.
+func (db *DB) EthTrust(check string) []string {
+	return db.Rules[check].EthTrust
+}
+
+// SCSVS returns the OWASP SCSVS control areas a check evaluates, or nil.
+func (db *DB) SCSVS(check string) []string {
+	return db.Rules[check].SCSVS
+}
+
+// AllTags returns every tag used by the bundle, sorted.
+func (db *DB) AllTags() []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, r := range db.Rules {
+		for _, t := range r.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Signature returns the named signature list, e.g. "external-call".
+func (db *DB) Signature(name string) []string {
+	return db.Signatures[name]
+}
@@ -0,0 +1,78 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedBundleIsValid(t *testing.T) {
+	db, err := Embedded()
+	require.NoError(t, err)
+
+	assert.Equal(t, "embedded", db.Origin)
+	assert.Equal(t, "SWC-107", db.SWC("reentrancy-eth"))
+	assert.Equal(t, "CWE-841", db.CWE("reentrancy-eth"))
+	assert.Contains(t, db.Remediation("tx-origin"), "msg.sender")
+	assert.NotEmpty(t, db.Signature("external-call"))
+}
+
+func TestEmbeddedBundle_EveryRuleIsTagged(t *testing.T) {
+	db, err := Embedded()
+	require.NoError(t, err)
+
+	for check, r := range db.Rules {
+		assert.NotEmpty(t, r.Tags, check)
+	}
+	assert.Equal(t, []string{"reentrancy", "defi"}, db.Tags("reentrancy-eth"))
+	assert.Contains(t, db.AllTags(), "access-control")
+	assert.Contains(t, db.AllTags(), "upgradeability")
+	assert.Contains(t, db.AllTags(), "gas")
+}
+
+func TestEmbeddedBundle_ControlMappings(t *testing.T) {
+	db, err := Embedded()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"[S] No tx.origin"}, db.EthTrust("tx-origin"))
+	assert.Equal(t, []string{"SCSVS-AUTH"}, db.SCSVS("tx-origin"))
+	for check, r := range db.Rules {
+		for _, control := range r.EthTrust {
+			assert.Regexp(t, `^\[[SMQ]\] `, control, check)
+		}
+		for _, control := range r.SCSVS {
+			assert.Regexp(t, `^SCSVS-[A-Z]+$`, control, check)
+		}
+	}
+}
+
+func TestDecode_RejectsInvalidBundles(t *testing.T) {
+	_, err := Decode([]byte(`{"rules": {"x": {}}}`))
+	assert.Error(t, err)
+
+	_, err = Decode([]byte(`{"version": 2, "rules": {}}`))
+	assert.Error(t, err)
+
+	_, err = Decode([]byte(`not json`))
+	assert.Error(t, err)
+
+	_, err = Decode([]byte(`{"version": 2, "rules": {"x": {}}, "signatures": {
+		"external-call": [".call{"], "state-change": ["="], "reentrancy-guard": ["nonReentrant"]}}`))
+	assert.EqualError(t, err, `rules bundle has no "access-modifier" signatures`)
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99, "rules": {"timestamp": {"swc": "SWC-116"}}, "signatures": {
+		"external-call": [".call{"], "state-change": ["="], "reentrancy-guard": ["nonReentrant"], "access-modifier": ["onlyOwner"]}}`), 0644))
+
+	db, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 99, db.Version)
+	assert.Equal(t, path, db.Origin)
+	assert.Equal(t, "SWC-116", db.SWC("timestamp"))
+	assert.Empty(t, db.Remediation("timestamp"))
+}
@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Zubimendi/solsec/internal/suggest"
+)
+
+// catalogTTL is how long a cached detector list is trusted before Slither is
+// queried again. The cache is also invalidated when the slither binary changes.
+const catalogTTL = 24 * time.Hour
+
+// Detector is one entry from `slither --list-detectors-json`.
+type Detector struct {
+	Check       string `json:"check"`
+	Title       string `json:"title"`
+	Impact      string `json:"impact"`
+	Confidence  string `json:"confidence"`
+	Description string `json:"description"`
+	WikiURL     string `json:"wiki_url"`
+}
+
+type detectorCache struct {
+	SlitherPath string     `json:"slither_path"`
+	ModTime     time.Time  `json:"mod_time"`
+	FetchedAt   time.Time  `json:"fetched_at"`
+	Detectors   []Detector `json:"detectors"`
+}
+
+// ListDetectors returns the detectors supported by the installed Slither.
+// It only needs slither on PATH (not a full DetectEnvironment), so it is cheap
+// enough to call from shell completion. Results are cached per slither binary.
+func ListDetectors() ([]Detector, error) {
+	python := ""
+	if HasEmbeddedSlither() {
+		python, _, _ = findPython()
+	}
+	slitherPath, slitherArgs, err := resolveSlither(python)
+	if err != nil {
+		return nil, err
+	}
+	// The zipapp, not the interpreter, identifies an embedded Slither.
+	binary := slitherPath
+	if len(slitherArgs) > 0 {
+		binary = slitherArgs[0]
+	}
+	info, err := os.Stat(binary)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting slither: %w", err)
+	}
+
+	cachePath := detectorCachePath()
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var c detectorCache
+		if json.Unmarshal(data, &c) == nil &&
+			c.SlitherPath == binary &&
+			c.ModTime.Equal(info.ModTime()) &&
+			time.Since(c.FetchedAt) < catalogTTL &&
+			len(c.Detectors) > 0 {
+			return c.Detectors, nil
+		}
+	}
+
+	out, err := exec.Command(slitherPath, append(slitherArgs, "--list-detectors-json")...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing slither detectors: %w", err)
+	}
+	var detectors []Detector
+	if err := json.Unmarshal(out, &detectors); err != nil {
+		return nil, fmt.Errorf("parsing slither detector list: %w", err)
+	}
+
+	// Caching is best effort — a read-only cache dir must not break analysis.
+	if data, err := json.Marshal(detectorCache{
+		SlitherPath: binary,
+		ModTime:     info.ModTime(),
+		FetchedAt:   time.Now(),
+		Detectors:   detectors,
+	}); err == nil {
+		if os.MkdirAll(filepath.Dir(cachePath), 0750) == nil {
+			_ = os.WriteFile(cachePath, data, 0640)
+		}
+	}
+
+	return detectors, nil
+}
+
+func detectorCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "solsec", "slither-detectors.json")
+}
+
+// ValidateDetectors checks user-supplied detector names against the known
+// list and returns an error naming every unknown one, with a "did you mean"
+// suggestion where a close match exists.
+func ValidateDetectors(names []string, known []Detector) error {
+	checks := make([]string, 0, len(known))
+	valid := map[string]bool{}
+	for _, d := range known {
+		checks = append(checks, d.Check)
+		valid[d.Check] = true
+	}
+
+	var problems []string
+	for _, n := range names {
+		if valid[n] {
+			continue
+		}
+		msg := fmt.Sprintf("unknown slither detector %q", n)
+		if s := suggest.Closest(n, checks); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+		problems = append(problems, msg)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
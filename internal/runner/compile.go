@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CompileError is a single solc diagnostic extracted from crytic-compile output.
+type CompileError struct {
+	File    string
+	Line    int
+	Column  int
+	Kind    string // e.g. ParserError, TypeError, DeclarationError
+	Message string
+}
+
+var (
+	// Modern solc: "TypeError: Undeclared identifier.\n --> contracts/A.sol:12:9:"
+	compileHeaderRe = regexp.MustCompile(`^\s*(\w*Error): (.+)$`)
+	compileArrowRe  = regexp.MustCompile(`^\s*--> ([^:]+\.sol):(\d+):(\d+):?`)
+	// Legacy solc: "contracts/A.sol:12:9: TypeError: Undeclared identifier."
+	compileLegacyRe = regexp.MustCompile(`([^\s:]+\.sol):(\d+):(\d+): (\w*Error): (.+)$`)
+)
+
+// ParseCompileErrors extracts per-file compiler errors from Slither /
+// crytic-compile output. Warnings are ignored; duplicates are collapsed.
+func ParseCompileErrors(output string) []CompileError {
+	var (
+		errs    []CompileError
+		seen    = map[string]bool{}
+		pending *CompileError
+	)
+	add := func(e CompileError) {
+		key := e.File + ":" + strconv.Itoa(e.Line) + ":" + e.Message
+		if !seen[key] {
+			seen[key] = true
+			errs = append(errs, e)
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := compileLegacyRe.FindStringSubmatch(line); m != nil {
+			l, _ := strconv.Atoi(m[2])
+			c, _ := strconv.Atoi(m[3])
+			add(CompileError{File: m[1], Line: l, Column: c, Kind: m[4], Message: strings.TrimSpace(m[5])})
+			pending = nil
+			continue
+		}
+		if m := compileHeaderRe.FindStringSubmatch(line); m != nil {
+			pending = &CompileError{Kind: m[1], Message: strings.TrimSpace(m[2])}
+			continue
+		}
+		if m := compileArrowRe.FindStringSubmatch(line); m != nil && pending != nil {
+			pending.File = m[1]
+			pending.Line, _ = strconv.Atoi(m[2])
+			pending.Column, _ = strconv.Atoi(m[3])
+			add(*pending)
+			pending = nil
+		}
+	}
+	return errs
+}
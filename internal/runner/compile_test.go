@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompileErrors_Modern(t *testing.T) {
+	output := `Traceback (most recent call last):
+crytic_compile.platform.exceptions.InvalidCompilation: Invalid solc compilation Error: Source file requires different compiler version
+ParserError: Expected ';' but got '}'
+ --> contracts/Broken.sol:7:5:
+  |
+7 |     }
+  |     ^
+
+Warning: Unused local variable.
+ --> contracts/Ok.sol:3:9:
+
+DeclarationError: Undeclared identifier.
+ --> contracts/Other.sol:12:9:
+`
+	errs := ParseCompileErrors(output)
+	require.Len(t, errs, 2)
+
+	assert.Equal(t, CompileError{File: "contracts/Broken.sol", Line: 7, Column: 5, Kind: "ParserError", Message: "Expected ';' but got '}'"}, errs[0])
+	assert.Equal(t, "contracts/Other.sol", errs[1].File)
+	assert.Equal(t, "DeclarationError", errs[1].Kind)
+}
+
+func TestParseCompileErrors_Legacy(t *testing.T) {
+	output := "contracts/Old.sol:4:3: TypeError: Member \"x\" not found.\ncontracts/Old.sol:4:3: TypeError: Member \"x\" not found.\n"
+	errs := ParseCompileErrors(output)
+	require.Len(t, errs, 1)
+	assert.Equal(t, 4, errs[0].Line)
+	assert.Equal(t, "TypeError", errs[0].Kind)
+}
+
+func TestParseCompileErrors_NoErrors(t *testing.T) {
+	assert.Empty(t, ParseCompileErrors("slither did not produce output\nstderr: permission denied"))
+}
@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -8,48 +9,39 @@ import (
 
 // Environment holds detected versions of required tools.
 type Environment struct {
-	PythonPath  string
-	PythonVersion string
-	SlitherPath string
+	PythonPath     string
+	PythonVersion  string
+	SlitherPath    string
 	SlitherVersion string
+
+	// SlitherArgs precede every Slither invocation's arguments; set when the
+	// embedded zipapp runs as `python slither.pyz ...`.
+	SlitherArgs []string
+	// Embedded reports whether Slither is the zipapp built into solsec.
+	Embedded bool
+}
+
+// slitherCommand builds the command running Slither with args.
+func (e *Environment) slitherCommand(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, e.SlitherPath, append(append([]string{}, e.SlitherArgs...), args...)...)
 }
 
 // DetectEnvironment checks whether Python and Slither are available on PATH.
-// Returns a descriptive error if either is missing, with install instructions.
+// Builds with an embedded Slither zipapp fall back to it when slither is not
+// installed. Returns a descriptive error if either is missing, with install
+// instructions.
 func DetectEnvironment() (*Environment, error) {
 	env := &Environment{}
 
-	// Detect Python — try python3 first, fall back to python
-	for _, name := range []string{"python3", "python"} {
-		path, err := exec.LookPath(name)
-		if err != nil {
-			continue
-		}
-		out, err := exec.Command(path, "--version").Output()
-		if err != nil {
-			continue
-		}
-		version := strings.TrimSpace(string(out))
-		// Require Python 3.8+
-		if strings.HasPrefix(version, "Python 3.") {
-			env.PythonPath = path
-			env.PythonVersion = version
-			break
-		}
-	}
-
-	if env.PythonPath == "" {
-		return nil, fmt.Errorf(
-			"Python 3.8+ not found on PATH\n\n" +
-			"Install instructions:\n" +
-			"  Ubuntu/Debian: sudo apt install python3 python3-pip\n" +
-			"  macOS:         brew install python3\n" +
-			"  Windows:       https://python.org/downloads",
-		)
+	pythonPath, pythonVersion, err := findPython()
+	if err != nil {
+		return nil, err
 	}
+	env.PythonPath = pythonPath
+	env.PythonVersion = pythonVersion
 
 	// Detect Slither
-	slitherPath, err := exec.LookPath("slither")
+	slitherPath, slitherArgs, err := resolveSlither(env.PythonPath)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"Slither not found on PATH\n\n" +
@@ -59,12 +51,19 @@ func DetectEnvironment() (*Environment, error) {
 			"  %s -m pip install slither-analyzer", env.PythonPath,
 		)
 	}
+	env.SlitherPath = slitherPath
+	env.SlitherArgs = slitherArgs
+	env.Embedded = len(slitherArgs) > 0
 
-	out, err := exec.Command(slitherPath, "--version").Output()
+	out, err := env.slitherCommand(context.Background(), "--version").Output()
 	if err == nil {
 		env.SlitherVersion = strings.TrimSpace(string(out))
+	} else if env.Embedded {
+		env.SlitherVersion = embeddedSlitherVersion
+	}
+	if env.Embedded {
+		env.SlitherVersion += " (embedded)"
 	}
-	env.SlitherPath = slitherPath
 
 	return env, nil
-}
\ No newline at end of file
+}
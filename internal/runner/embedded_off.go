@@ -0,0 +1,9 @@
+//go:build !embedslither
+
+package runner
+
+// The default build embeds no Slither; it must be installed separately.
+var (
+	embeddedSlither        []byte
+	embeddedSlitherVersion string
+)
@@ -0,0 +1,20 @@
+//go:build embedslither
+
+package runner
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// Built with -tags embedslither (see `make build-embedded`): slither.pyz is a
+// shiv zipapp of a pinned slither-analyzer release, used when no slither is
+// installed.
+
+//go:embed slither.pyz
+var embeddedSlither []byte
+
+//go:embed slither.version
+var embeddedSlitherVersionFile string
+
+var embeddedSlitherVersion = strings.TrimSpace(embeddedSlitherVersionFile)
@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultForgeTimeout = 10 * time.Minute
+
+// ForgeTestOptions configures a `forge test` run.
+type ForgeTestOptions struct {
+	// Root is the Foundry project directory forge runs in.
+	Root string
+
+	// MatchPath selects the test files to run (forge's --match-path glob).
+	MatchPath string
+
+	// Env is added to forge's environment, e.g. the fork RPC URL.
+	Env []string
+
+	Timeout time.Duration
+}
+
+// DetectForge returns the path of Foundry's `forge` executable, or an error
+// with install instructions when it is not on PATH.
+func DetectForge() (string, error) {
+	path, err := exec.LookPath("forge")
+	if err != nil {
+		return "", fmt.Errorf("forge not found on PATH\n\nInstall instructions:\n  curl -L https://foundry.paradigm.xyz | bash && foundryup")
+	}
+	return path, nil
+}
+
+// RunForgeTest runs `forge test --json` and returns whether each test
+// passed, keyed by "<file>:<contract>.<test>()" as forge names them.
+func RunForgeTest(ctx context.Context, forgePath string, opts ForgeTestOptions) (map[string]bool, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultForgeTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, forgePath, "test", "--match-path", opts.MatchPath, "--json")
+	cmd.Dir = opts.Root
+	cmd.Env = append(os.Environ(), opts.Env...)
+	setProcessGroup(cmd)
+	var stdoutBuf, stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+
+	// forge exits non-zero when a test fails; the JSON tells which.
+	_ = cmd.Run()
+	if err := interrupted(ctx, runCtx, "forge", opts.Timeout); err != nil {
+		return nil, err
+	}
+	results, err := parseForgeResults(stdoutBuf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w\nstderr: %s", err, strings.TrimSpace(stderrBuf.String()))
+	}
+	return results, nil
+}
+
+// forgeSuite is one test contract in `forge test --json` output.
+type forgeSuite struct {
+	TestResults map[string]struct {
+		Status string `json:"status"` // "Success", "Failure" or "Skipped"
+	} `json:"test_results"`
+}
+
+func parseForgeResults(data []byte) (map[string]bool, error) {
+	// Compiler progress may precede the JSON document.
+	start := bytes.IndexByte(data, '{')
+	if start < 0 {
+		return nil, fmt.Errorf("forge produced no test results (did the tests compile?)")
+	}
+	var suites map[string]forgeSuite
+	if err := json.Unmarshal(data[start:], &suites); err != nil {
+		return nil, fmt.Errorf("parsing forge test output: %w", err)
+	}
+	results := map[string]bool{}
+	for suite, s := range suites {
+		for name, r := range s.TestResults {
+			results[suite+"."+name] = r.Status == "Success"
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,24 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForgeResults(t *testing.T) {
+	out := `Compiling 2 files with Solc 0.8.24
+{"test/solsec-validate/Token_mint.t.sol:Token_mintFork":{"duration":"1s","test_results":{"test_fork_mint_unrestricted()":{"status":"Success","reason":null}}},` +
+		`"test/solsec-validate/Vault_sweep.t.sol:Vault_sweepFork":{"duration":"1s","test_results":{"test_fork_sweep_unrestricted()":{"status":"Failure","reason":"revert: not owner"}}}}`
+
+	results, err := parseForgeResults([]byte(out))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{
+		"test/solsec-validate/Token_mint.t.sol:Token_mintFork.test_fork_mint_unrestricted()":    true,
+		"test/solsec-validate/Vault_sweep.t.sol:Vault_sweepFork.test_fork_sweep_unrestricted()": false,
+	}, results)
+
+	_, err = parseForgeResults([]byte("Error: Compiler run failed"))
+	assert.ErrorContains(t, err, "no test results")
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+const defaultMythrilTimeout = 10 * time.Minute
+
+// MythrilOptions configures a Mythril symbolic-execution run over one file.
+type MythrilOptions struct {
+	// File is the .sol file to analyze. Mythril does not accept directories.
+	File string
+
+	// Timeout bounds the whole subprocess; ExecutionTimeout is passed to Mythril
+	// so it stops exploring and still prints results before being killed.
+	Timeout time.Duration
+
+	// SolcVersion pins a specific solc compiler version e.g. "0.8.24".
+	SolcVersion string
+}
+
+// DetectMythril returns the path of the `myth` executable, or an error with
+// install instructions when it is not on PATH.
+func DetectMythril() (string, error) {
+	path, err := exec.LookPath("myth")
+	if err != nil {
+		return "", fmt.Errorf("Mythril not found on PATH\n\nInstall instructions:\n  pip3 install mythril")
+	}
+	return path, nil
+}
+
+// RunMythril runs `myth analyze` and returns its raw JSON output.
+func RunMythril(ctx context.Context, mythPath string, opts MythrilOptions) ([]byte, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultMythrilTimeout
+	}
+
+	args := []string{
+		"analyze", opts.File,
+		"-o", "json",
+		"--execution-timeout", strconv.Itoa(int((opts.Timeout * 9 / 10).Seconds())),
+	}
+	if opts.SolcVersion != "" {
+		args = append(args, "--solv", opts.SolcVersion)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, mythPath, args...)
+	setProcessGroup(cmd)
+	var stdoutBuf, stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+
+	// Like Slither, Mythril exits non-zero when it finds issues.
+	_ = cmd.Run()
+	if err := interrupted(ctx, runCtx, "mythril", opts.Timeout); err != nil {
+		return nil, err
+	}
+
+	if stdoutBuf.Len() == 0 {
+		return nil, fmt.Errorf("mythril produced no output\nstderr: %s", stderrBuf.String())
+	}
+	return stdoutBuf.Bytes(), nil
+}
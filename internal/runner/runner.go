@@ -6,8 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -28,8 +28,19 @@ type Options struct {
 	// ExcludeDetectors lists Slither detector names to skip.
 	ExcludeDetectors []string
 
+	// Detectors restricts Slither to only these detector names.
+	Detectors []string
+
 	// SolcVersion pins a specific solc compiler version e.g. "0.8.24".
 	SolcVersion string
+
+	// Framework forces crytic-compile's build framework e.g. "foundry".
+	// Empty lets crytic-compile auto-detect it.
+	Framework string
+
+	// FilterPaths are regular expressions matched against source paths;
+	// Slither drops the findings in files that match one.
+	FilterPaths []string
 }
 
 // Result holds everything captured from a Slither subprocess run.
@@ -41,8 +52,9 @@ type Result struct {
 }
 
 // Run executes Slither against the target, writes JSON output, and returns
-// the path to the JSON file plus captured stdio for debugging.
-func Run(env *Environment, opts Options) (*Result, error) {
+// the path to the JSON file plus captured stdio for debugging. Cancelling ctx
+// kills Slither and every process it started.
+func Run(ctx context.Context, env *Environment, opts Options) (*Result, error) {
 	if opts.Timeout == 0 {
 		opts.Timeout = defaultTimeout
 	}
@@ -72,20 +84,33 @@ func Run(env *Environment, opts Options) (*Result, error) {
 		"--no-fail-pedantic",           // don't exit non-zero on findings
 	}
 
+	// Slither takes a single comma-separated list; repeating the flag would
+	// silently keep only the last value.
 	if len(opts.ExcludeDetectors) > 0 {
-		for _, d := range opts.ExcludeDetectors {
-			args = append(args, "--exclude", d)
-		}
+		args = append(args, "--exclude", strings.Join(opts.ExcludeDetectors, ","))
+	}
+
+	if len(opts.Detectors) > 0 {
+		args = append(args, "--detect", strings.Join(opts.Detectors, ","))
 	}
 
 	if opts.SolcVersion != "" {
 		args = append(args, "--solc-remaps", fmt.Sprintf("solc=%s", opts.SolcVersion))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	if opts.Framework != "" {
+		args = append(args, "--compile-force-framework", opts.Framework)
+	}
+
+	if len(opts.FilterPaths) > 0 {
+		args = append(args, "--filter-paths", strings.Join(opts.FilterPaths, ","))
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, env.SlitherPath, args...)
+	cmd := env.slitherCommand(runCtx, args...)
+	setProcessGroup(cmd)
 
 	var stdoutBuf, stderrBuf bytes.Buffer
 	cmd.Stdout = &stdoutBuf
@@ -96,6 +121,9 @@ func Run(env *Environment, opts Options) (*Result, error) {
 	// We only treat it as a real error if the JSON file wasn't produced.
 	_ = cmd.Run()
 	duration := time.Since(start)
+	if err := interrupted(ctx, runCtx, "slither", opts.Timeout); err != nil {
+		return nil, err
+	}
 
 	// Confirm the JSON output file exists — if not, Slither truly failed
 	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
@@ -113,6 +141,18 @@ func Run(env *Environment, opts Options) (*Result, error) {
 	}, nil
 }
 
+// interrupted explains why a subprocess run under runCtx (derived from ctx
+// with a timeout) was stopped early, or returns nil if it was not.
+func interrupted(ctx, runCtx context.Context, name string, timeout time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s interrupted: %w", name, err)
+	}
+	if runCtx.Err() != nil {
+		return fmt.Errorf("%s timed out after %s", name, timeout)
+	}
+	return nil
+}
+
 // ValidateTarget checks that the target exists and looks like Solidity.
 func ValidateTarget(target string) error {
 	info, err := os.Stat(target)
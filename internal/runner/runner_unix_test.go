@@ -0,0 +1,68 @@
+//go:build unix
+
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSlither writes a script that starts a long-running child (standing in
+// for solc), records its PID, and waits.
+func fakeSlither(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "slither")
+	script := "#!/bin/sh\nsleep 60 &\necho $! > " + filepath.Join(dir, "child.pid") + "\nwait\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+func TestRun_CancelKillsProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	env := &Environment{SlitherPath: fakeSlither(t, dir)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// Cancel once the child is running.
+		for i := 0; i < 100; i++ {
+			if _, err := os.Stat(filepath.Join(dir, "child.pid")); err == nil {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := Run(ctx, env, Options{Target: dir, OutputPath: filepath.Join(dir, "out.json")})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	data, err := os.ReadFile(filepath.Join(dir, "child.pid"))
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(pid, 0) != nil
+	}, 5*time.Second, 50*time.Millisecond, "grandchild process survived cancellation")
+}
+
+func TestRun_Timeout(t *testing.T) {
+	dir := t.TempDir()
+	env := &Environment{SlitherPath: fakeSlither(t, dir)}
+
+	_, err := Run(context.Background(), env, Options{Target: dir, OutputPath: filepath.Join(dir, "out.json"), Timeout: 200 * time.Millisecond})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 200ms")
+	assert.NotErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var solcVersionRe = regexp.MustCompile(`Version:\s*(\d+\.\d+\.\d+)`)
+
+// DetectSolc returns the version of the solc on PATH (e.g. "0.8.24"), or ""
+// when there is none.
+func DetectSolc() string {
+	out, err := exec.Command("solc", "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return parseSolcVersion(string(out))
+}
+
+// parseSolcVersion extracts the release from `solc --version` output such as
+// "Version: 0.8.24+commit.e11b9ed9.Linux.g++".
+func parseSolcVersion(out string) string {
+	if m := solcVersionRe.FindStringSubmatch(out); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// SolcInstalled reports whether solc version is available without a
+// download: as the solc on PATH, or installed by solc-select or by Foundry.
+func SolcInstalled(version string) bool {
+	if DetectSolc() == version {
+		return true
+	}
+	home, err := os.UserHomeDir()
+	return err == nil && solcInstalledIn(home, version)
+}
+
+func solcInstalledIn(home, version string) bool {
+	for _, path := range []string{
+		filepath.Join(home, ".solc-select", "artifacts", "solc-"+version), // file or directory, by solc-select release
+		filepath.Join(home, ".svm", version, "solc-"+version),
+	} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+const solcTimeout = 5 * time.Minute
+
+// SolcCombinedJSON compiles files with the solc on PATH and returns its
+// `--combined-json` output of the given comma-separated fields (e.g. "abi"
+// or "storage-layout"). Remappings are read from remappings.txt in the
+// working directory, as Foundry projects declare them.
+func SolcCombinedJSON(ctx context.Context, fields string, files []string) ([]byte, error) {
+	if _, err := exec.LookPath("solc"); err != nil {
+		return nil, fmt.Errorf("solc not found on PATH\n\nInstall instructions:\n  pip3 install solc-select && solc-select install latest && solc-select use latest")
+	}
+	remappings, err := readRemappings("remappings.txt")
+	if err != nil {
+		return nil, err
+	}
+	runCtx, cancel := context.WithTimeout(ctx, solcTimeout)
+	defer cancel()
+
+	args := append([]string{"--combined-json", fields, "--allow-paths", "."}, remappings...)
+	cmd := exec.CommandContext(runCtx, "solc", append(args, files...)...)
+	setProcessGroup(cmd)
+	var stdoutBuf, stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+	runErr := cmd.Run()
+	if err := interrupted(ctx, runCtx, "solc", solcTimeout); err != nil {
+		return nil, err
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("solc failed: %w\n%s", runErr, strings.TrimSpace(stderrBuf.String()))
+	}
+	return stdoutBuf.Bytes(), nil
+}
+
+// readRemappings returns the prefix=target lines of a remappings file, or
+// none when it does not exist.
+func readRemappings(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	defer f.Close()
+	var remappings []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.Contains(line, "=") && !strings.HasPrefix(line, "#") {
+			remappings = append(remappings, line)
+		}
+	}
+	return remappings, scanner.Err()
+}
@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSolcVersion(t *testing.T) {
+	out := "solc, the solidity compiler commandline interface\nVersion: 0.8.24+commit.e11b9ed9.Linux.g++\n"
+	assert.Equal(t, "0.8.24", parseSolcVersion(out))
+	assert.Equal(t, "", parseSolcVersion("command not found"))
+}
+
+func TestSolcInstalledIn(t *testing.T) {
+	home := t.TempDir()
+	assert.False(t, solcInstalledIn(home, "0.8.24"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".svm", "0.8.24"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".svm", "0.8.24", "solc-0.8.24"), nil, 0755))
+	assert.True(t, solcInstalledIn(home, "0.8.24"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".solc-select", "artifacts", "solc-0.7.6"), 0755))
+	assert.True(t, solcInstalledIn(home, "0.7.6"))
+}
+
+func TestReadRemappings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remappings.txt")
+	require.NoError(t, os.WriteFile(path, []byte("@openzeppelin/=lib/openzeppelin-contracts/\n# comment\n\nforge-std/=lib/forge-std/src/\n"), 0644))
+	remappings, err := readRemappings(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@openzeppelin/=lib/openzeppelin-contracts/", "forge-std/=lib/forge-std/src/"}, remappings)
+
+	remappings, err = readRemappings(filepath.Join(t.TempDir(), "missing.txt"))
+	require.NoError(t, err)
+	assert.Empty(t, remappings)
+}
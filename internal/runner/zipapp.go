@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// PythonEnvVar overrides the Python interpreter solsec uses.
+const PythonEnvVar = "SOLSEC_PYTHON"
+
+var pythonVersionRe = regexp.MustCompile(`Python 3\.(\d+)`)
+
+// HasEmbeddedSlither reports whether this build carries a Slither zipapp.
+func HasEmbeddedSlither() bool {
+	return len(embeddedSlither) > 0
+}
+
+// EmbeddedSlitherVersion is the slither-analyzer release embedded in this
+// build, or "" when none is.
+func EmbeddedSlitherVersion() string {
+	if !HasEmbeddedSlither() {
+		return ""
+	}
+	return embeddedSlitherVersion
+}
+
+// findPython resolves a Python 3.8+ interpreter: $SOLSEC_PYTHON, then
+// python3, python and, on Windows, the py launcher.
+func findPython() (path, version string, err error) {
+	candidates := []string{"python3", "python"}
+	if runtime.GOOS == "windows" {
+		candidates = append(candidates, "py")
+	}
+	if p := os.Getenv(PythonEnvVar); p != "" {
+		candidates = []string{p}
+	}
+	for _, name := range candidates {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+		out, err := exec.Command(path, "--version").Output()
+		if err != nil {
+			continue
+		}
+		version := strings.TrimSpace(string(out))
+		if m := pythonVersionRe.FindStringSubmatch(version); m != nil {
+			if minor, _ := strconv.Atoi(m[1]); minor >= 8 {
+				return path, version, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf(
+		"Python 3.8+ not found on PATH\n\n" +
+			"Install instructions:\n" +
+			"  Ubuntu/Debian: sudo apt install python3 python3-pip\n" +
+			"  macOS:         brew install python3\n" +
+			"  Windows:       https://python.org/downloads\n\n" +
+			"Or point " + PythonEnvVar + " at an interpreter.",
+	)
+}
+
+// resolveSlither returns the command that runs Slither: the slither on PATH,
+// or else the embedded zipapp run by python. args are prepended to every
+// Slither invocation.
+func resolveSlither(python string) (path string, args []string, err error) {
+	if p, err := exec.LookPath("slither"); err == nil {
+		return p, nil, nil
+	}
+	if !HasEmbeddedSlither() || python == "" {
+		return "", nil, fmt.Errorf("slither not found on PATH")
+	}
+	pyz, err := extractZipapp()
+	if err != nil {
+		return "", nil, err
+	}
+	return python, []string{pyz}, nil
+}
+
+// extractZipapp writes the embedded zipapp to the user cache, named by its
+// content hash so upgrades never reuse a stale copy.
+func extractZipapp() (string, error) {
+	sum := sha256.Sum256(embeddedSlither)
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	path := filepath.Join(dir, "solsec", "slither-"+hex.EncodeToString(sum[:6])+".pyz")
+	if info, err := os.Stat(path); err == nil && info.Size() == int64(len(embeddedSlither)) {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return "", fmt.Errorf("creating cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "slither-*.pyz.tmp")
+	if err != nil {
+		return "", fmt.Errorf("extracting embedded slither: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(embeddedSlither); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("extracting embedded slither: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("extracting embedded slither: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("extracting embedded slither: %w", err)
+	}
+	return path, nil
+}
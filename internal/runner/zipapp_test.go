@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"archive/zip"
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeZipapp builds a zipapp whose __main__ behaves like `slither --version`.
+func fakeZipapp(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("__main__.py")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("import sys\nif '--version' in sys.argv:\n    print('0.10.4')\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// realPython returns the interpreter's own path; shims (pyenv, asdf) stop
+// working once PATH is emptied.
+func realPython(t *testing.T) string {
+	t.Helper()
+	out, err := exec.Command("python3", "-c", "import sys; print(sys.executable)").Output()
+	if err != nil {
+		t.Skip("python3 not installed")
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestDetectEnvironment_EmbeddedZipapp(t *testing.T) {
+	python := realPython(t)
+
+	saved, savedVersion := embeddedSlither, embeddedSlitherVersion
+	t.Cleanup(func() { embeddedSlither, embeddedSlitherVersion = saved, savedVersion })
+	embeddedSlither, embeddedSlitherVersion = fakeZipapp(t), "0.10.4"
+
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	t.Setenv("PATH", t.TempDir()) // no slither installed
+	t.Setenv(PythonEnvVar, python)
+
+	env, err := DetectEnvironment()
+	require.NoError(t, err)
+	assert.True(t, env.Embedded)
+	assert.Equal(t, python, env.SlitherPath)
+	require.Len(t, env.SlitherArgs, 1)
+	assert.FileExists(t, env.SlitherArgs[0])
+	assert.Equal(t, "0.10.4 (embedded)", env.SlitherVersion)
+
+	// Extraction is reused while the embedded bytes are unchanged.
+	again, err := extractZipapp()
+	require.NoError(t, err)
+	assert.Equal(t, env.SlitherArgs[0], again)
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(again), "*.pyz*"))
+	assert.Len(t, matches, 1)
+}
+
+func TestDetectEnvironment_NoSlither(t *testing.T) {
+	python := realPython(t)
+	saved := embeddedSlither
+	t.Cleanup(func() { embeddedSlither = saved })
+	embeddedSlither = nil
+
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv(PythonEnvVar, python)
+	_, err := DetectEnvironment()
+	assert.ErrorContains(t, err, "Slither not found on PATH")
+}
@@ -0,0 +1,58 @@
+package scorer
+
+import (
+	"fmt"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// Breakdown explains a risk score: which findings contributed how many
+// points, and how much was dropped by the 100-point cap.
+type Breakdown struct {
+	Contributions []Contribution `json:"contributions"`
+
+	// Raw is the sum of every finding's weight before the cap.
+	Raw   int `json:"raw"`
+	Score int `json:"score"`
+
+	// Unscored counts findings whose severity carries no weight
+	// (Informational, Optimization).
+	Unscored int `json:"unscored"`
+}
+
+// Contribution is one finding's share of the score. Counted is less than
+// Points once the cap has been reached.
+type Contribution struct {
+	ID       string          `json:"id"`
+	Check    string          `json:"check"`
+	Title    string          `json:"title"`
+	Severity parser.Severity `json:"severity"`
+	Points   int             `json:"points"`
+	Counted  int             `json:"counted"`
+	Note     string          `json:"note,omitempty"`
+}
+
+// Explain breaks the report's score down per finding. Findings are counted
+// in report order (most severe first), so the cap trims the least severe.
+func Explain(report *parser.AnalysisReport) Breakdown {
+	var b Breakdown
+	for _, f := range report.Findings {
+		points := Points(f.Severity)
+		if points == 0 {
+			b.Unscored++
+			continue
+		}
+		c := Contribution{ID: f.ID, Check: f.Check, Title: f.Title, Severity: f.Severity, Points: points}
+		c.Counted = min(points, 100-b.Score)
+		if n := len(f.Related); n > 0 {
+			c.Note = fmt.Sprintf("root cause of %d findings, counted once", n+1)
+		}
+		if c.Counted < points {
+			c.Note = "over the 100-point cap"
+		}
+		b.Raw += points
+		b.Score += c.Counted
+		b.Contributions = append(b.Contributions, c)
+	}
+	return b
+}
@@ -0,0 +1,46 @@
+package scorer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestExplain(t *testing.T) {
+	findings := []parser.Finding{
+		{ID: "C-1", Severity: parser.SeverityCritical},
+		{ID: "C-2", Severity: parser.SeverityCritical},
+		{ID: "H-1", Severity: parser.SeverityHigh},
+		{ID: "M-1", Severity: parser.SeverityMedium},
+		{ID: "I-1", Severity: parser.SeverityInformational},
+	}
+	report := &parser.AnalysisReport{
+		Findings: findings,
+		Summary:  parser.Summary{Total: 5, Critical: 2, High: 1, Medium: 1, Informational: 1},
+	}
+
+	b := Explain(report)
+	assert.Equal(t, Score(report), b.Score)
+	assert.Equal(t, 110, b.Raw)
+	assert.Equal(t, 1, b.Unscored)
+	require.Len(t, b.Contributions, 4)
+	assert.Equal(t, 40, b.Contributions[0].Counted)
+	assert.Equal(t, 20, b.Contributions[2].Counted)
+	assert.Equal(t, 10, b.Contributions[3].Points)
+	assert.Equal(t, 0, b.Contributions[3].Counted)
+	assert.NotEmpty(t, b.Contributions[3].Note)
+}
+
+func TestScore_ClustersCountOnce(t *testing.T) {
+	report := &parser.AnalysisReport{
+		Findings: []parser.Finding{{
+			ID:       "H-1",
+			Severity: parser.SeverityHigh,
+			Related:  []parser.Finding{{ID: "H-2", Severity: parser.SeverityHigh}, {ID: "M-1", Severity: parser.SeverityMedium}},
+		}},
+		Summary: parser.Summary{Total: 3, High: 2, Medium: 1},
+	}
+	assert.Equal(t, 20, Score(report), "a cluster is scored through its parent")
+}
@@ -0,0 +1,113 @@
+package scorer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Band is one grade on a Scale: scores below Below (and not in an earlier
+// band) receive Grade. The last band catches every remaining score, so its
+// Below is ignored.
+type Band struct {
+	Grade   string
+	Below   int
+	Verdict string
+}
+
+// Scale maps risk scores to grades and verdicts. Organizations with a
+// different risk appetite can move the boundaries, rename or add grades
+// (e.g. A+) and reword the verdicts.
+type Scale struct {
+	Bands []Band
+
+	// NoEmoji strips the leading emoji from verdicts.
+	NoEmoji bool
+}
+
+// DefaultScale is the built-in A–F scale.
+var DefaultScale = Scale{Bands: []Band{
+	{Grade: "A", Below: 10, Verdict: "✅ Low risk. Review findings before deployment."},
+	{Grade: "B", Below: 25, Verdict: "⚠️  Minor issues found. Address before mainnet deployment."},
+	{Grade: "C", Below: 50, Verdict: "🟠 Moderate risk. Fix all Medium+ findings before deployment."},
+	{Grade: "D", Below: 75, Verdict: "🔴 High risk. Do not deploy until Critical/High findings are resolved."},
+	{Grade: "F", Verdict: "🚨 Critical risk. This contract must not be deployed."},
+}}
+
+// TokenReviewScale grades a third-party token for integration rather than
+// deployment. One Critical finding (a honeypot or balance rewrite) or two
+// High ones are enough to advise against integrating.
+var TokenReviewScale = Scale{Bands: []Band{
+	{Grade: "A", Below: 1, Verdict: "✅ No red flags found. Integrate after reviewing the token's documentation."},
+	{Grade: "B", Below: 20, Verdict: "⚠️  Centralized controls found. Integrate only if the owner's powers are acceptable."},
+	{Grade: "C", Below: 40, Verdict: "🟠 The owner can change how transfers behave. Integrate only with safeguards (caps, pausing, monitoring)."},
+	{Grade: "F", Verdict: "🚨 Do not integrate this token."},
+}}
+
+var active = DefaultScale
+
+// SetScale makes s the scale used by Grade and Verdict. It must be valid.
+func SetScale(s Scale) {
+	active = s
+}
+
+// Validate checks that the scale has named, distinct grades with strictly
+// increasing boundaries within the 0–100 score range.
+func (s Scale) Validate() error {
+	if len(s.Bands) == 0 {
+		return fmt.Errorf("grade scale has no bands")
+	}
+	seen := map[string]bool{}
+	prev := 0
+	for i, b := range s.Bands {
+		if b.Grade == "" {
+			return fmt.Errorf("grade band #%d has no grade", i+1)
+		}
+		if seen[b.Grade] {
+			return fmt.Errorf("grade %q is defined more than once", b.Grade)
+		}
+		seen[b.Grade] = true
+		if i == len(s.Bands)-1 {
+			break
+		}
+		if b.Below <= prev || b.Below > 100 {
+			return fmt.Errorf("grade %q: below must be greater than %d and at most 100, got %d", b.Grade, prev, b.Below)
+		}
+		prev = b.Below
+	}
+	return nil
+}
+
+// Grade returns the grade of the band score falls in.
+func (s Scale) Grade(score int) string {
+	return s.band(score).Grade
+}
+
+// Verdict returns the verdict of the band score falls in. A band without
+// its own verdict uses the default scale's verdict for the score.
+func (s Scale) Verdict(score int) string {
+	v := s.band(score).Verdict
+	if v == "" {
+		v = DefaultScale.band(score).Verdict
+	}
+	if s.NoEmoji {
+		v = stripEmoji(v)
+	}
+	return v
+}
+
+func (s Scale) band(score int) Band {
+	for _, b := range s.Bands[:len(s.Bands)-1] {
+		if score < b.Below {
+			return b
+		}
+	}
+	return s.Bands[len(s.Bands)-1]
+}
+
+// stripEmoji removes the symbols and spacing before a verdict's first word.
+func stripEmoji(v string) string {
+	return strings.TrimLeftFunc(v, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,51 @@
+package scorer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultScale(t *testing.T) {
+	assert.NoError(t, DefaultScale.Validate())
+	assert.Equal(t, "A", DefaultScale.Grade(0))
+	assert.Equal(t, "B", DefaultScale.Grade(10))
+	assert.Equal(t, "D", DefaultScale.Grade(74))
+	assert.Equal(t, "F", DefaultScale.Grade(100))
+}
+
+func TestTokenReviewScale(t *testing.T) {
+	assert.NoError(t, TokenReviewScale.Validate())
+	assert.Equal(t, "A", TokenReviewScale.Grade(0))
+	assert.Equal(t, "B", TokenReviewScale.Grade(Points("Medium")))
+	assert.Equal(t, "C", TokenReviewScale.Grade(Points("High")))
+	assert.Equal(t, "F", TokenReviewScale.Grade(Points("Critical")), "one Critical finding rules the token out")
+}
+
+func TestScale_Custom(t *testing.T) {
+	s := Scale{
+		Bands: []Band{
+			{Grade: "A+", Below: 1, Verdict: "🏆 Clean."},
+			{Grade: "A", Below: 10},
+			{Grade: "Fail", Verdict: "Do not ship."},
+		},
+		NoEmoji: true,
+	}
+	assert.NoError(t, s.Validate())
+
+	assert.Equal(t, "A+", s.Grade(0))
+	assert.Equal(t, "Clean.", s.Verdict(0))
+	assert.Equal(t, "A", s.Grade(5))
+	assert.Equal(t, "Low risk. Review findings before deployment.", s.Verdict(5), "falls back to the default verdict, without emoji")
+	assert.Equal(t, "Fail", s.Grade(60))
+	assert.Equal(t, "f", Tier(80), "tiers follow the default scale")
+}
+
+func TestScale_Validate(t *testing.T) {
+	assert.Error(t, Scale{}.Validate())
+	assert.Error(t, Scale{Bands: []Band{{Grade: "A", Below: 10}, {Grade: "A"}}}.Validate())
+	assert.Error(t, Scale{Bands: []Band{{Grade: "A", Below: 50}, {Grade: "B", Below: 20}, {Grade: "F"}}}.Validate())
+	assert.Error(t, Scale{Bands: []Band{{Grade: "A", Below: 0}, {Grade: "F"}}}.Validate())
+	assert.Error(t, Scale{Bands: []Band{{Below: 10}, {Grade: "F"}}}.Validate())
+	assert.NoError(t, Scale{Bands: []Band{{Grade: "Pass"}}}.Validate())
+}
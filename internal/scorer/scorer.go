@@ -1,6 +1,10 @@
 package scorer
 
-import "github.com/Zubimendi/solsec/internal/parser"
+import (
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
 
 // Score calculates an overall risk score from 0 (perfect) to 100 (critical risk).
 // The scoring model is inspired by CVSS but simplified for smart contract context.
@@ -11,20 +15,44 @@ import "github.com/Zubimendi/solsec/internal/parser"
 //   Medium:   10 points each
 //   Low:       3 points each
 //   Info:      0 points
+//
+// A cluster of findings sharing a root cause is scored once, through its
+// parent; the summary and failure thresholds still count every finding.
 func Score(report *parser.AnalysisReport) int {
-	score := 0
-	score += report.Summary.Critical * 40
-	score += report.Summary.High * 20
-	score += report.Summary.Medium * 10
-	score += report.Summary.Low * 3
+	return ScoreFindings(report.Findings)
+}
 
+// Points returns the score weight of a single finding at the given severity.
+func Points(s parser.Severity) int {
+	switch s {
+	case parser.SeverityCritical:
+		return 40
+	case parser.SeverityHigh:
+		return 20
+	case parser.SeverityMedium:
+		return 10
+	case parser.SeverityLow:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ScoreFindings scores an arbitrary subset of findings (e.g. one file's) with
+// the same weights and cap as Score.
+func ScoreFindings(findings []parser.Finding) int {
+	score := 0
+	for _, f := range findings {
+		score += Points(f.Severity)
+	}
 	if score > 100 {
 		return 100
 	}
 	return score
 }
 
-// Grade returns a letter grade based on the score.
+// Grade returns the letter grade for score on the active scale. The default
+// scale is:
 //
 //	0–9:   A  (Low risk — review before deployment)
 //	10–24: B  (Minor issues found)
@@ -32,32 +60,17 @@ func Score(report *parser.AnalysisReport) int {
 //	50–74: D  (High risk — do not deploy)
 //	75–100: F (Critical risk — do not deploy)
 func Grade(score int) string {
-	switch {
-	case score < 10:
-		return "A"
-	case score < 25:
-		return "B"
-	case score < 50:
-		return "C"
-	case score < 75:
-		return "D"
-	default:
-		return "F"
-	}
+	return active.Grade(score)
 }
 
-// Verdict returns a human-readable deployment recommendation.
+// Verdict returns a human-readable deployment recommendation for score on
+// the active scale.
 func Verdict(score int) string {
-	switch Grade(score) {
-	case "A":
-		return "✅ Low risk. Review findings before deployment."
-	case "B":
-		return "⚠️  Minor issues found. Address before mainnet deployment."
-	case "C":
-		return "🟠 Moderate risk. Fix all Medium+ findings before deployment."
-	case "D":
-		return "🔴 High risk. Do not deploy until Critical/High findings are resolved."
-	default:
-		return "🚨 Critical risk. This contract must not be deployed."
-	}
-}
\ No newline at end of file
+	return active.Verdict(score)
+}
+
+// Tier returns the risk tier of score on the default scale ("a" to "f"),
+// independent of any custom grade names, for color coding.
+func Tier(score int) string {
+	return strings.ToLower(DefaultScale.Grade(score))
+}
@@ -0,0 +1,147 @@
+// Package selftest runs the built-in custom checks against their fixture
+// contracts and compares the findings with the expected (golden) results.
+//
+// Fixtures live in testdata/contracts/<check>/ with this layout:
+//
+//	vulnerable/*.sol   contracts the check must flag
+//	safe/*.sol         contracts the check must not flag
+//	expected.yaml      the exact findings expected from vulnerable/
+//
+// Each of vulnerable/ and safe/ is analyzed as its own project, so it may
+// also carry config files (e.g. foundry.toml) a check reads.
+package selftest
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"go.yaml.in/yaml/v3"
+)
+
+// Expected is the contents of a fixture's expected.yaml.
+type Expected struct {
+	Findings []Expectation `yaml:"findings"`
+}
+
+// Expectation is one finding the check must produce.
+type Expectation struct {
+	File string `yaml:"file"` // relative to the check's fixture dir, e.g. vulnerable/Bank.sol
+	Rule string `yaml:"rule"`
+	Line int    `yaml:"line"` // first reported line
+}
+
+func (e Expectation) String() string {
+	return fmt.Sprintf("%s:%d %s", e.File, e.Line, e.Rule)
+}
+
+// Result is the outcome of one check's self-test.
+type Result struct {
+	Check      string
+	Missing    []string // expected but not reported
+	Unexpected []string // reported but not expected (including on safe fixtures)
+	Err        error
+}
+
+// Passed reports whether the check behaved exactly as expected.
+func (r Result) Passed() bool {
+	return r.Err == nil && len(r.Missing) == 0 && len(r.Unexpected) == 0
+}
+
+// Run self-tests each check against its fixtures in fsys. A check without
+// fixtures fails, so every new check has to ship with regression coverage.
+func Run(fsys fs.FS, selected []checks.Check) []Result {
+	results := make([]Result, 0, len(selected))
+	for _, c := range selected {
+		results = append(results, runCheck(fsys, c))
+	}
+	return results
+}
+
+func runCheck(fsys fs.FS, c checks.Check) Result {
+	res := Result{Check: c.Name}
+
+	data, err := fs.ReadFile(fsys, path.Join(c.Name, "expected.yaml"))
+	if err != nil {
+		res.Err = fmt.Errorf("no fixtures: %w", err)
+		return res
+	}
+	var expected Expected
+	if err := yaml.Unmarshal(data, &expected); err != nil {
+		res.Err = fmt.Errorf("parsing expected.yaml: %w", err)
+		return res
+	}
+
+	// Checks read from disk, so materialize the fixtures in a temp dir.
+	dir, err := os.MkdirTemp("", "solsec-selftest-*")
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer os.RemoveAll(dir)
+	sub, err := fs.Sub(fsys, c.Name)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	if err := os.CopyFS(dir, sub); err != nil {
+		res.Err = fmt.Errorf("copying fixtures: %w", err)
+		return res
+	}
+
+	var got []Expectation
+	for _, kind := range []string{"vulnerable", "safe"} {
+		target := filepath.Join(dir, kind)
+		if _, err := os.Stat(target); err != nil {
+			continue
+		}
+		findings, err := c.Run(target, checks.DefaultFilter)
+		if err != nil {
+			res.Err = fmt.Errorf("running on %s fixtures: %w", kind, err)
+			return res
+		}
+		got = append(got, toExpectations(dir, findings)...)
+	}
+
+	want := map[string]bool{}
+	for _, e := range expected.Findings {
+		want[e.String()] = true
+	}
+	have := map[string]bool{}
+	for _, e := range got {
+		have[e.String()] = true
+		if !want[e.String()] {
+			res.Unexpected = append(res.Unexpected, e.String())
+		}
+	}
+	for _, e := range expected.Findings {
+		if !have[e.String()] {
+			res.Missing = append(res.Missing, e.String())
+		}
+	}
+	sort.Strings(res.Missing)
+	sort.Strings(res.Unexpected)
+	return res
+}
+
+// toExpectations converts findings to fixture-relative expectations.
+func toExpectations(dir string, findings []parser.Finding) []Expectation {
+	out := make([]Expectation, 0, len(findings))
+	for _, f := range findings {
+		file := f.File
+		if rel, err := filepath.Rel(dir, f.File); err == nil {
+			file = filepath.ToSlash(rel)
+		}
+		line := 0
+		if len(f.Lines) > 0 {
+			line = f.Lines[0]
+		}
+		out = append(out, Expectation{File: file, Rule: f.Check, Line: line})
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package selftest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Zubimendi/solsec/internal/analyzer/checks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFixtures is the golden test for every built-in check: the findings on
+// testdata/contracts/<check>/ must match its expected.yaml exactly.
+func TestFixtures(t *testing.T) {
+	for _, r := range Run(os.DirFS("../../testdata/contracts"), checks.All()) {
+		t.Run(r.Check, func(t *testing.T) {
+			assert.NoError(t, r.Err)
+			assert.Empty(t, r.Missing, "expected findings not reported")
+			assert.Empty(t, r.Unexpected, "findings not in expected.yaml")
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package solidity
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Graph is the import dependency graph of a set of Solidity files.
+type Graph struct {
+	Files map[string]*File // keyed by path as discovered
+
+	// Imports maps a file to the in-scope files it imports.
+	Imports map[string][]string
+
+	// External maps a file to imports that resolve outside the scope,
+	// e.g. "@openzeppelin/contracts/token/ERC20/ERC20.sol".
+	External map[string][]string
+}
+
+// LoadGraph parses every file and resolves their imports against each other.
+func LoadGraph(paths []string) (*Graph, error) {
+	files := make([]*File, 0, len(paths))
+	for _, p := range paths {
+		f, err := ParseFile(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return NewGraph(files), nil
+}
+
+// NewGraph builds the import graph of already-parsed files.
+func NewGraph(files []*File) *Graph {
+	g := &Graph{
+		Files:    map[string]*File{},
+		Imports:  map[string][]string{},
+		External: map[string][]string{},
+	}
+	for _, f := range files {
+		g.Files[f.Path] = f
+	}
+	for _, f := range files {
+		for _, imp := range f.Imports {
+			if to := g.resolve(f.Path, imp.Path); to != "" {
+				g.Imports[f.Path] = appendUnique(g.Imports[f.Path], to)
+			} else {
+				g.External[f.Path] = appendUnique(g.External[f.Path], imp.Path)
+			}
+		}
+	}
+	return g
+}
+
+// resolve maps an import path onto a known file. Relative imports resolve
+// against the importing file; anything else (remapped or project-absolute
+// paths) matches the known file whose path ends with the import path.
+func (g *Graph) resolve(from, importPath string) string {
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		want := absClean(filepath.Join(filepath.Dir(from), importPath))
+		for path := range g.Files {
+			if absClean(path) == want {
+				return path
+			}
+		}
+		return ""
+	}
+
+	suffix := "/" + strings.TrimPrefix(filepath.ToSlash(importPath), "/")
+	var match string
+	for path := range g.Files {
+		slash := filepath.ToSlash(absClean(path))
+		if strings.HasSuffix(slash, suffix) && (match == "" || path < match) {
+			match = path
+		}
+	}
+	return match
+}
+
+// ImportClosure returns paths plus every file on disk they import, directly
+// or transitively, as sorted absolute paths. Relative imports resolve
+// against the importing file; others through remappings (prefix=target,
+// with targets relative to root), then against root, root/node_modules and
+// Foundry's root/lib/<name>/src layout. Imports no file matches are
+// skipped.
+func ImportClosure(paths []string, root string, remappings []string) ([]string, error) {
+	seen := map[string]bool{}
+	queue := make([]string, 0, len(paths))
+	for _, p := range paths {
+		queue = append(queue, absClean(p))
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		f, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range f.Imports {
+			if to := resolveFile(path, imp.Path, root, remappings); to != "" && !seen[to] {
+				queue = append(queue, to)
+			}
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for p := range seen {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// resolveFile finds the file importPath refers to from the file from, or
+// "".
+func resolveFile(from, importPath, root string, remappings []string) string {
+	var candidates []string
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		candidates = append(candidates, filepath.Join(filepath.Dir(from), importPath))
+	} else {
+		prefix, target := "", ""
+		for _, r := range remappings {
+			if i := strings.Index(r, ":"); i >= 0 && i < strings.Index(r, "=") {
+				r = r[i+1:] // context:prefix=target; the context is ignored
+			}
+			p, t, ok := strings.Cut(r, "=")
+			if ok && strings.HasPrefix(importPath, p) && len(p) > len(prefix) {
+				prefix, target = p, t
+			}
+		}
+		if prefix != "" {
+			candidates = append(candidates, filepath.Join(root, target+strings.TrimPrefix(importPath, prefix)))
+		}
+		candidates = append(candidates,
+			filepath.Join(root, importPath),
+			filepath.Join(root, "node_modules", importPath))
+		if name, rest, ok := strings.Cut(importPath, "/"); ok {
+			candidates = append(candidates,
+				filepath.Join(root, "lib", name, "src", rest),
+				filepath.Join(root, "lib", name, rest))
+		}
+	}
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return absClean(c)
+		}
+	}
+	return ""
+}
+
+// ImportedBy returns the in-scope files that import path.
+func (g *Graph) ImportedBy(path string) []string {
+	var out []string
+	for from, tos := range g.Imports {
+		for _, to := range tos {
+			if to == path {
+				out = append(out, from)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Paths returns every file in the graph, sorted.
+func (g *Graph) Paths() []string {
+	out := make([]string, 0, len(g.Files))
+	for p := range g.Files {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func absClean(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return filepath.Clean(p)
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,443 @@
+// Package solidity is a lightweight source model of Solidity files: the
+// imports, contracts and inheritance that can be recovered from source text
+// without invoking a compiler.
+package solidity
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// File is the parsed outline of one Solidity source file.
+type File struct {
+	Path      string
+	Imports   []Import
+	Contracts []Contract
+
+	// Code is the source with comments blanked out (offsets and line
+	// numbers are preserved), for identifier searches.
+	Code string
+}
+
+// Import is a single import directive.
+type Import struct {
+	Path string // as written, e.g. "./Token.sol" or "@openzeppelin/contracts/access/Ownable.sol"
+	Line int
+}
+
+// Contract is a contract, interface or library declaration.
+type Contract struct {
+	Name      string
+	Kind      string // "contract", "interface" or "library"
+	Abstract  bool
+	Bases     []string
+	Line      int
+	End       int // line of the closing brace
+	Functions []Function
+	StateVars []StateVar
+
+	// Modifiers are the modifier declarations, parsed like functions: Name,
+	// Line, End and Params are set.
+	Modifiers []Function
+}
+
+// StateVar is a state variable declaration.
+type StateVar struct {
+	Type       string // e.g. "uint256" or "mapping(address => uint256)"
+	Name       string
+	Visibility string // "public", "private" or "internal" (the default)
+	Constant   bool   // constant or immutable
+	Line       int
+}
+
+// Function is a function, constructor, receive or fallback declaration.
+type Function struct {
+	Name       string // "constructor", "receive" and "fallback" for the special functions
+	Line       int
+	End        int // line of the body's closing brace, or Line when there is no body
+	Params     []Param
+	Payable    bool
+	Visibility string   // "public", "external", "internal", "private" or "" when unstated
+	Mutability string   // "view", "pure" or ""
+	Modifiers  []string // e.g. ["onlyOwner", "nonReentrant"]
+}
+
+// Param is one function parameter, e.g. {Type: "uint256[]", Name: "amounts"}.
+// Name is empty for unnamed parameters.
+type Param struct {
+	Type string
+	Name string
+}
+
+// Contains reports whether line falls within the declaration.
+func (c Contract) Contains(line int) bool { return line >= c.Line && line <= c.End }
+
+// Contains reports whether line falls within the declaration.
+func (fn Function) Contains(line int) bool { return line >= fn.Line && line <= fn.End }
+
+// Line returns the comment-stripped code on line n (1-based), or "".
+func (f *File) Line(n int) string {
+	lines := strings.Split(f.Code, "\n")
+	if n < 1 || n > len(lines) {
+		return ""
+	}
+	return lines[n-1]
+}
+
+// At returns the contract and function (either may be nil) enclosing line.
+func (f *File) At(line int) (*Contract, *Function) {
+	for i := range f.Contracts {
+		c := &f.Contracts[i]
+		if !c.Contains(line) {
+			continue
+		}
+		for j := range c.Functions {
+			if c.Functions[j].Contains(line) {
+				return c, &c.Functions[j]
+			}
+		}
+		return c, nil
+	}
+	return nil, nil
+}
+
+var (
+	importRe   = regexp.MustCompile(`(?m)^\s*import\s+(?:[^'";]*?\s+from\s+)?["']([^"']+)["']`)
+	contractRe = regexp.MustCompile(`(?m)^\s*(abstract\s+)?(contract|interface|library)\s+([A-Za-z_$][\w$]*)\s*(?:is\s+([^{]+))?\{`)
+	functionRe = regexp.MustCompile(`\b(?:function\s+([A-Za-z_$][\w$]*)|(constructor|receive|fallback))\s*\(`)
+	modifierRe = regexp.MustCompile(`\bmodifier\s+([A-Za-z_$][\w$]*)\s*`)
+	returnsRe  = regexp.MustCompile(`\breturns\s*\([^)]*\)`)
+	stateVarRe = regexp.MustCompile(`(?s)^(mapping\s*\(.*\)|[A-Za-z_][\w.]*(?:\s*\[[^\]]*\])*)\s+((?:(?:public|private|internal|constant|immutable|override|transient)\s+)*)([A-Za-z_$][\w$]*)\s*(?:=.*)?$`)
+)
+
+// ParseFile reads and parses the Solidity file at path.
+func ParseFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, string(data)), nil
+}
+
+// Parse extracts the outline of Solidity source text.
+func Parse(path, text string) *File {
+	code := StripComments(text)
+	f := &File{Path: path, Code: code}
+
+	for _, m := range importRe.FindAllStringSubmatchIndex(code, -1) {
+		f.Imports = append(f.Imports, Import{
+			Path: code[m[2]:m[3]],
+			Line: lineAt(code, m[2]),
+		})
+	}
+
+	for _, m := range contractRe.FindAllStringSubmatchIndex(code, -1) {
+		c := Contract{
+			Abstract: m[2] >= 0,
+			Kind:     code[m[4]:m[5]],
+			Name:     code[m[6]:m[7]],
+			Line:     lineAt(code, m[6]),
+		}
+		if m[8] >= 0 {
+			c.Bases = splitBases(code[m[8]:m[9]])
+		}
+		open := m[1] - 1
+		end := matchBrace(code, open)
+		c.End = lineAt(code, end)
+		c.Functions = parseFunctions(code, open+1, end)
+		c.StateVars = parseStateVars(code, open+1, end)
+		c.Modifiers = parseModifiers(code, open+1, end)
+		f.Contracts = append(f.Contracts, c)
+	}
+	return f
+}
+
+// parseFunctions finds the functions declared in code[start:end], a
+// contract body.
+func parseFunctions(code string, start, end int) []Function {
+	var fns []Function
+	pos := start
+	for pos < end {
+		m := functionRe.FindStringSubmatchIndex(code[pos:end])
+		if m == nil {
+			break
+		}
+		nameStart, nameEnd := m[2], m[3]
+		if nameStart < 0 {
+			nameStart, nameEnd = m[4], m[5]
+		}
+		fn := Function{Name: code[pos+nameStart : pos+nameEnd], Line: lineAt(code, pos+m[0])}
+		fn.End = fn.Line
+		next := pos + m[1]
+		if close := matchPair(code, next-1, '(', ')'); close < end {
+			fn.Params = splitParams(code[next:close])
+			next = close + 1
+		}
+		// The body starts at the first "{" before any ";" (an interface or
+		// abstract declaration has none).
+		body := strings.IndexAny(code[next:end], "{;")
+		if body >= 0 {
+			parseHeader(&fn, code[next:next+body])
+		}
+		if body >= 0 && code[next+body] == '{' {
+			close := matchBrace(code, next+body)
+			fn.End = lineAt(code, close)
+			next = close + 1
+		}
+		fns = append(fns, fn)
+		pos = next
+	}
+	return fns
+}
+
+// parseModifiers finds the modifiers declared in code[start:end], a
+// contract body. The parameter list is optional ("modifier onlyOwner {").
+func parseModifiers(code string, start, end int) []Function {
+	var mods []Function
+	pos := start
+	for pos < end {
+		m := modifierRe.FindStringSubmatchIndex(code[pos:end])
+		if m == nil {
+			break
+		}
+		mod := Function{Name: code[pos+m[2] : pos+m[3]], Line: lineAt(code, pos+m[0])}
+		mod.End = mod.Line
+		next := pos + m[1]
+		if next < end && code[next] == '(' {
+			if close := matchPair(code, next, '(', ')'); close < end {
+				mod.Params = splitParams(code[next+1 : close])
+				next = close + 1
+			}
+		}
+		// A virtual modifier may be declared without a body.
+		if body := strings.IndexAny(code[next:end], "{;"); body >= 0 && code[next+body] == '{' {
+			close := matchBrace(code, next+body)
+			mod.End = lineAt(code, close)
+			next = close + 1
+		}
+		mods = append(mods, mod)
+		pos = next
+	}
+	return mods
+}
+
+// parseHeader classifies the words between a function's parameter list and
+// its body: visibility, mutability and modifiers.
+func parseHeader(fn *Function, header string) {
+	// Drop "returns (...)" and modifier or override arguments.
+	header = returnsRe.ReplaceAllString(header, " ")
+	for {
+		open := strings.Index(header, "(")
+		if open < 0 {
+			break
+		}
+		close := matchPair(header, open, '(', ')')
+		header = header[:open] + " " + header[min(close+1, len(header)):]
+	}
+	for _, word := range strings.Fields(header) {
+		switch word {
+		case "public", "external", "internal", "private":
+			fn.Visibility = word
+		case "view", "pure":
+			fn.Mutability = word
+		case "payable":
+			fn.Payable = true
+		case "virtual", "override":
+		default:
+			fn.Modifiers = append(fn.Modifiers, word)
+		}
+	}
+}
+
+// parseStateVars finds the state variables declared directly in
+// code[start:end], a contract body. Nested blocks (function bodies, structs)
+// are skipped, and each one terminates the declaration before it.
+func parseStateVars(code string, start, end int) []StateVar {
+	var vars []StateVar
+	stmtStart := start
+	flush := func(stop int) {
+		stmt := code[stmtStart:stop]
+		offset := stmtStart + len(stmt) - len(strings.TrimLeft(stmt, " \t\r\n"))
+		if v, ok := parseStateVar(strings.TrimSpace(stmt)); ok {
+			v.Line = lineAt(code, offset)
+			vars = append(vars, v)
+		}
+	}
+	var quote byte
+	for i := start; i < end; i++ {
+		c := code[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			i = matchBrace(code, i)
+			stmtStart = i + 1
+		case c == ';':
+			flush(i)
+			stmtStart = i + 1
+		}
+	}
+	return vars
+}
+
+func parseStateVar(stmt string) (StateVar, bool) {
+	m := stateVarRe.FindStringSubmatch(stmt)
+	if m == nil || declarationKeywords[strings.Fields(stmt)[0]] {
+		return StateVar{}, false
+	}
+	v := StateVar{Type: strings.Join(strings.Fields(m[1]), " "), Name: m[3], Visibility: "internal"}
+	for _, word := range strings.Fields(m[2]) {
+		switch word {
+		case "public", "private", "internal":
+			v.Visibility = word
+		case "constant", "immutable":
+			v.Constant = true
+		}
+	}
+	return v, true
+}
+
+// declarationKeywords start contract-level statements that are not state
+// variables.
+var declarationKeywords = map[string]bool{
+	"function": true, "modifier": true, "constructor": true, "receive": true, "fallback": true,
+	"event": true, "error": true, "using": true, "struct": true, "enum": true, "return": true,
+}
+
+// splitParams parses a parameter list such as "address to, uint256 amount".
+func splitParams(list string) []Param {
+	var params []Param
+	for _, p := range strings.Split(list, ",") {
+		fields := strings.Fields(p)
+		if len(fields) == 0 {
+			continue
+		}
+		param := Param{Type: fields[0]}
+		if last := fields[len(fields)-1]; len(fields) > 1 && !dataLocations[last] {
+			param.Name = last
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+var dataLocations = map[string]bool{"memory": true, "calldata": true, "storage": true, "payable": true}
+
+// matchBrace returns the offset of the brace closing the one at open,
+// skipping string literals, or len(code)-1 when it is unbalanced.
+func matchBrace(code string, open int) int {
+	return matchPair(code, open, '{', '}')
+}
+
+// matchPair is matchBrace for any pair of delimiters.
+func matchPair(code string, open int, opening, closing byte) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == opening:
+			depth++
+		case c == closing:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(code) - 1
+}
+
+// splitBases turns "Ownable(msg.sender), ERC20("T", "T")" into its base names.
+func splitBases(list string) []string {
+	var bases []string
+	depth := 0
+	start := 0
+	flush := func(end int) {
+		name := strings.TrimSpace(list[start:end])
+		if i := strings.Index(name, "("); i >= 0 {
+			name = strings.TrimSpace(name[:i])
+		}
+		if name != "" {
+			bases = append(bases, name)
+		}
+	}
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				flush(i)
+				start = i + 1
+			}
+		}
+	}
+	flush(len(list))
+	return bases
+}
+
+// StripComments blanks out // and /* */ comments, keeping newlines so byte
+// offsets and line numbers still match the original text. String literals
+// are respected, so "http://..." is not treated as a comment.
+func StripComments(text string) string {
+	out := []byte(text)
+	inLine, inBlock := false, false
+	var quote byte
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case inLine:
+			if c == '\n' {
+				inLine = false
+			} else {
+				out[i] = ' '
+			}
+		case inBlock:
+			if c == '*' && i+1 < len(out) && out[i+1] == '/' {
+				out[i], out[i+1] = ' ', ' '
+				i++
+				inBlock = false
+			} else if c != '\n' {
+				out[i] = ' '
+			}
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote || c == '\n' {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			inLine = true
+			out[i] = ' '
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			inBlock = true
+			out[i], out[i+1] = ' ', ' '
+			i++
+		}
+	}
+	return string(out)
+}
+
+func lineAt(text string, offset int) int {
+	return strings.Count(text[:offset], "\n") + 1
+}
@@ -0,0 +1,229 @@
+package solidity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	text := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./IToken.sol";
+import {Ownable} from "@openzeppelin/contracts/access/Ownable.sol";
+// import "./Old.sol";
+/* contract Commented {} */
+
+abstract contract Base is IToken {
+}
+
+contract Token is Base, Ownable(msg.sender) {
+    string url = "http://example.com"; // contract Fake {
+}
+
+library Math {}
+`
+	f := Parse("Token.sol", text)
+
+	require.Len(t, f.Imports, 2)
+	assert.Equal(t, "./IToken.sol", f.Imports[0].Path)
+	assert.Equal(t, 4, f.Imports[0].Line)
+	assert.Equal(t, "@openzeppelin/contracts/access/Ownable.sol", f.Imports[1].Path)
+
+	require.Len(t, f.Contracts, 3)
+	assert.Equal(t, Contract{Name: "Base", Kind: "contract", Abstract: true, Bases: []string{"IToken"}, Line: 9, End: 10}, f.Contracts[0])
+	assert.Equal(t, []string{"Base", "Ownable"}, f.Contracts[1].Bases)
+	assert.Equal(t, "library", f.Contracts[2].Kind)
+}
+
+func TestLoadGraph(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "interfaces"), 0755))
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+		return p
+	}
+	iface := write("interfaces/IToken.sol", "interface IToken {}")
+	token := write("Token.sol", `import "./interfaces/IToken.sol";
+import "@openzeppelin/contracts/access/Ownable.sol";
+contract Token is IToken {}`)
+	vault := write("Vault.sol", `import "interfaces/IToken.sol";
+contract Vault {}`)
+
+	g, err := LoadGraph([]string{iface, token, vault})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{iface}, g.Imports[token])
+	assert.Equal(t, []string{iface}, g.Imports[vault])
+	assert.Equal(t, []string{"@openzeppelin/contracts/access/Ownable.sol"}, g.External[token])
+	assert.Equal(t, []string{token, vault}, g.ImportedBy(iface))
+	assert.Empty(t, g.ImportedBy(token))
+}
+
+func TestImportClosure(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+		return p
+	}
+	vault := write("src/Vault.sol", `import "./lib/Math.sol";
+import "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+import "solmate/tokens/ERC20.sol";
+import "forge-std/Test.sol";
+import "missing/Gone.sol";
+contract Vault {}`)
+	math := write("src/lib/Math.sol", `import "../Vault.sol";
+library Math {}`)
+	ierc20 := write("node_modules/@openzeppelin/contracts/token/ERC20/IERC20.sol", "interface IERC20 {}")
+	solmate := write("deps/solmate/src/tokens/ERC20.sol", `import "../utils/SafeCast.sol";
+abstract contract ERC20 {}`)
+	safeCast := write("deps/solmate/src/utils/SafeCast.sol", "library SafeCast {}")
+	test := write("lib/forge-std/src/Test.sol", "abstract contract Test {}")
+	write("src/Unrelated.sol", "contract Unrelated {}")
+
+	files, err := ImportClosure([]string{vault}, dir, []string{"src:solmate/=deps/solmate/src/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{solmate, safeCast, test, ierc20, vault, math}, files,
+		"relative, node_modules, remapped and lib/ imports are followed transitively; cycles and unresolved imports end the walk")
+}
+
+func TestParse_Functions(t *testing.T) {
+	text := `interface IToken {
+    function mint(address to) external;
+}
+contract Token {
+    string constant BRACE = "}";
+    constructor() {}
+    function mint(address to) public {
+        if (to == address(0)) {
+            revert("zero {");
+        }
+    }
+    receive() external payable {}
+}
+`
+	f := Parse("Token.sol", text)
+	require.Len(t, f.Contracts, 2)
+
+	iface := f.Contracts[0]
+	assert.Equal(t, 3, iface.End)
+	require.Len(t, iface.Functions, 1)
+	assert.Equal(t, Function{Name: "mint", Line: 2, End: 2, Params: []Param{{Type: "address", Name: "to"}}, Visibility: "external"}, iface.Functions[0])
+
+	token := f.Contracts[1]
+	assert.Equal(t, 4, token.Line)
+	assert.Equal(t, 13, token.End)
+	assert.Equal(t, []Function{
+		{Name: "constructor", Line: 6, End: 6},
+		{Name: "mint", Line: 7, End: 11, Params: []Param{{Type: "address", Name: "to"}}, Visibility: "public"},
+		{Name: "receive", Line: 12, End: 12, Payable: true, Visibility: "external"},
+	}, token.Functions)
+
+	assert.Equal(t, []StateVar{{Type: "string", Name: "BRACE", Visibility: "internal", Constant: true, Line: 5}}, token.StateVars)
+
+	assert.Equal(t, []Param{{Type: "uint256[]", Name: "amounts"}, {Type: "bytes"}},
+		splitParams("uint256[] calldata amounts, bytes memory"))
+
+	c, fn := f.At(9)
+	require.NotNil(t, c)
+	require.NotNil(t, fn)
+	assert.Equal(t, "Token", c.Name)
+	assert.Equal(t, "mint", fn.Name)
+
+	c, fn = f.At(5)
+	assert.Equal(t, "Token", c.Name)
+	assert.Nil(t, fn)
+
+	c, _ = f.At(14)
+	assert.Nil(t, c)
+}
+
+func TestParse_Modifiers(t *testing.T) {
+	text := `abstract contract Guarded {
+    address owner;
+
+    modifier onlyOwner {
+        require(msg.sender == owner);
+        _;
+    }
+
+    modifier after(uint256 time) {
+        require(block.timestamp > time);
+        _;
+    }
+
+    modifier hook() virtual;
+
+    function f() external onlyOwner {}
+}
+`
+	c := Parse("Guarded.sol", text).Contracts[0]
+	assert.Equal(t, []Function{
+		{Name: "onlyOwner", Line: 4, End: 7},
+		{Name: "after", Line: 9, End: 12, Params: []Param{{Type: "uint256", Name: "time"}}},
+		{Name: "hook", Line: 14, End: 14},
+	}, c.Modifiers)
+	require.Len(t, c.Functions, 1)
+	assert.Equal(t, []string{"onlyOwner"}, c.Functions[0].Modifiers)
+	assert.Len(t, c.StateVars, 1)
+}
+
+func TestParse_Surface(t *testing.T) {
+	text := `contract Vault is Ownable {
+    struct Position { uint256 amount; }
+    mapping(address => uint256) public balances;
+    uint256 public totalSupply;
+    address private immutable asset = address(0x1);
+    event Deposit(address who);
+    function deposit() external payable nonReentrant {}
+    function sweep(address to) public virtual override(A, B) onlyRole(ADMIN) returns (uint256 a) {}
+    function total() public view returns (uint256) { return totalSupply; }
+}
+`
+	c := Parse("Vault.sol", text).Contracts[0]
+
+	assert.Equal(t, []StateVar{
+		{Type: "mapping(address => uint256)", Name: "balances", Visibility: "public", Line: 3},
+		{Type: "uint256", Name: "totalSupply", Visibility: "public", Line: 4},
+		{Type: "address", Name: "asset", Visibility: "private", Constant: true, Line: 5},
+	}, c.StateVars)
+
+	require.Len(t, c.Functions, 3)
+	assert.True(t, c.Functions[0].Payable)
+	assert.Equal(t, []string{"nonReentrant"}, c.Functions[0].Modifiers)
+	assert.Equal(t, "public", c.Functions[1].Visibility)
+	assert.Equal(t, []string{"onlyRole"}, c.Functions[1].Modifiers)
+	assert.Equal(t, "view", c.Functions[2].Mutability)
+	assert.Empty(t, c.Functions[2].Modifiers)
+}
+
+func TestVersionSatisfies(t *testing.T) {
+	cases := []struct {
+		version, constraint string
+		want                bool
+	}{
+		{"0.8.24", "^0.8.0", true},
+		{"0.9.0", "^0.8.0", false},
+		{"0.8.24", ">=0.7.0 <0.9.0", true},
+		{"0.6.12", ">=0.7.0 <0.9.0", false},
+		{"0.8.24", "0.8.24", true},
+		{"0.8.23", "=0.8.24", false},
+		{"0.8.5", "~0.8.1", true},
+		{"0.8.24", "^0.7.0 || ^0.8.0", true},
+		{"0.8.24", ">= 0.8.0", true},
+	}
+	for _, c := range cases {
+		v, err := ParseVersion(c.version)
+		require.NoError(t, err)
+		got, err := v.Satisfies(c.constraint)
+		require.NoError(t, err, c.constraint)
+		assert.Equal(t, c.want, got, "%s %s", c.version, c.constraint)
+	}
+}
@@ -0,0 +1,120 @@
+package solidity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a solc version such as 0.8.24.
+type Version [3]int
+
+// ParseVersion parses "0.8.24" (an optional leading "v" is accepted).
+// Missing components default to zero, so "0.8" is 0.8.0.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return v, fmt.Errorf("empty version")
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// Compare returns -1, 0 or 1.
+func (v Version) Compare(o Version) int {
+	for i := range v {
+		if v[i] != o[i] {
+			if v[i] < o[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Satisfies reports whether v meets a pragma constraint such as "^0.8.0",
+// ">=0.7.0 <0.9.0", "~0.8.1", "0.8.24" or "^0.7.0 || ^0.8.0".
+func (v Version) Satisfies(constraint string) (bool, error) {
+	for _, alt := range strings.Split(constraint, "||") {
+		ok, err := v.satisfiesAll(alt)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (v Version) satisfiesAll(constraint string) (bool, error) {
+	// Allow "> = 0.8.0" style spacing between operator and version.
+	fields := strings.Fields(constraint)
+	var terms []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.Trim(f, "<>=^~") == "" && i+1 < len(fields) {
+			f += fields[i+1]
+			i++
+		}
+		terms = append(terms, f)
+	}
+	if len(terms) == 0 {
+		return false, fmt.Errorf("empty constraint")
+	}
+	for _, t := range terms {
+		ok, err := v.satisfiesTerm(t)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (v Version) satisfiesTerm(term string) (bool, error) {
+	rest := strings.TrimLeft(term, "<>=^~")
+	op := term[:len(term)-len(rest)]
+	want, err := ParseVersion(rest)
+	if err != nil {
+		return false, err
+	}
+	c := v.Compare(want)
+	switch op {
+	case "", "=":
+		return c == 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case "^":
+		// Caret keeps the leftmost non-zero component: ^0.8.1 means <0.9.0.
+		upper := Version{want[0] + 1}
+		if want[0] == 0 {
+			upper = Version{0, want[1] + 1}
+		}
+		return c >= 0 && v.Compare(upper) < 0, nil
+	case "~":
+		return c >= 0 && v.Compare(Version{want[0], want[1] + 1}) < 0, nil
+	}
+	return false, fmt.Errorf("unsupported operator %q", op)
+}
@@ -0,0 +1,111 @@
+package source
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CodeHost links the files of a git repository hosted on GitHub or GitLab
+// to their pages at a fixed commit.
+type CodeHost struct {
+	Root   string // repository root, absolute
+	Web    string // project page, e.g. https://github.com/org/repo
+	Commit string
+	GitLab bool
+}
+
+// scpRemoteRe matches scp-style remotes: git@github.com:org/repo.git.
+var scpRemoteRe = regexp.MustCompile(`^[\w.-]+@([\w.-]+):(.+)$`)
+
+// DetectCodeHost finds the repository enclosing target, its origin remote
+// and checked-out commit. It returns nil outside a git repository, without
+// a commit, or when origin is not a GitHub or GitLab remote (self-hosted
+// GitLab is recognized by "gitlab" in the host name).
+func DetectCodeHost(target string) *CodeHost {
+	root := RepoRoot(target)
+	git := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", root}, args...)...).Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := git("rev-parse", "--verify", "--quiet", "HEAD")
+	if commit == "" {
+		return nil
+	}
+	host, path, ok := parseRemote(git("remote", "get-url", "origin"))
+	if !ok {
+		return nil
+	}
+	h := &CodeHost{Root: root, Web: "https://" + host + "/" + path, Commit: commit}
+	switch {
+	case strings.Contains(host, "gitlab"):
+		h.GitLab = true
+	case host != "github.com":
+		return nil
+	}
+	return h
+}
+
+// parseRemote splits a remote URL into its host and project path, dropping
+// credentials, ports and the .git suffix.
+func parseRemote(remote string) (host, path string, ok bool) {
+	if m := scpRemoteRe.FindStringSubmatch(remote); m != nil && !strings.Contains(remote, "://") {
+		host, path = m[1], m[2]
+	} else {
+		u, err := url.Parse(remote)
+		if err != nil || u.Host == "" {
+			return "", "", false
+		}
+		host, path = u.Hostname(), u.Path
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", false
+	}
+	return strings.ToLower(host), path, true
+}
+
+// URL returns the page of file at the commit, anchored at the first to the
+// last of lines, or "" for a file outside the repository.
+func (h *CodeHost) URL(file string, lines []int) string {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(h.Root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+
+	blob := "/blob/"
+	if h.GitLab {
+		blob = "/-/blob/"
+	}
+	link := h.Web + blob + h.Commit + "/" + strings.Join(segments, "/")
+	if len(lines) == 0 {
+		return link
+	}
+	first, last := lines[0], lines[0]
+	for _, l := range lines {
+		first, last = min(first, l), max(last, l)
+	}
+	link += fmt.Sprintf("#L%d", first)
+	if last > first {
+		if h.GitLab {
+			link += fmt.Sprintf("-%d", last)
+		} else {
+			link += fmt.Sprintf("-L%d", last)
+		}
+	}
+	return link
+}
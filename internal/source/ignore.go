@@ -0,0 +1,360 @@
+package source
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFiles hold gitignore-syntax patterns excluding paths from directory
+// scans. .solsecignore excludes files from analysis without touching git.
+var IgnoreFiles = []string{".gitignore", ".solsecignore"}
+
+// ignoreRule is one pattern line, scoped to the directory of its file.
+type ignoreRule struct {
+	base    string // directory of the ignore file
+	re      *regexp.Regexp
+	pattern string // re's source without the anchors
+	negate  bool
+	dirOnly bool
+	// anchored patterns contain a slash and match the path relative to
+	// base; the others match the name at any depth.
+	anchored bool
+}
+
+// ignorer accumulates the rules of every ignore file seen so far. Later
+// rules win, so deeper ignore files override their parents as in git.
+type ignorer struct {
+	rules []ignoreRule
+}
+
+// newIgnorer loads the ignore files from the enclosing git repository (or
+// project) root down to, but excluding, dir. dir's own files and those
+// below are loaded by the walk.
+func newIgnorer(dir string) *ignorer {
+	ig := &ignorer{}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ig
+	}
+	dirs := ancestors(repoRoot(abs), abs)
+	for _, d := range dirs[:len(dirs)-1] {
+		ig.load(d)
+	}
+	return ig
+}
+
+// ancestors returns the directories from top down to dir, both included,
+// or just dir when it is not below top.
+func ancestors(top, dir string) []string {
+	rel, err := filepath.Rel(top, dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return []string{dir}
+	}
+	dirs := []string{top}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		dirs = append(dirs, filepath.Join(dirs[len(dirs)-1], part))
+	}
+	return dirs
+}
+
+// RepoRoot returns the root of the git repository enclosing target, or its
+// project root outside a repository, as an absolute path. A target that
+// does not exist (e.g. a merged report's) resolves from the working
+// directory.
+func RepoRoot(target string) string {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return target
+	}
+	if info, err := os.Stat(abs); err != nil {
+		if abs, err = os.Getwd(); err != nil {
+			return target
+		}
+	} else if !info.IsDir() {
+		abs = filepath.Dir(abs)
+	}
+	return repoRoot(abs)
+}
+
+// repoRoot is the nearest ancestor of dir holding .git, or the project root
+// when dir is not in a repository.
+func repoRoot(dir string) string {
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return ProjectRoot(dir)
+		}
+		d = parent
+	}
+}
+
+// load adds the rules of dir's ignore files.
+func (ig *ignorer) load(dir string) {
+	ig.loadFiles(dir, IgnoreFiles...)
+}
+
+// loadFiles adds the rules of the named ignore files in dir.
+func (ig *ignorer) loadFiles(dir string, names ...string) {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if r, ok := parseIgnoreRule(base, sc.Text()); ok {
+				ig.rules = append(ig.rules, r)
+			}
+		}
+		f.Close()
+	}
+}
+
+func parseIgnoreRule(base, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	r := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`) // escaped leading "#" or "!"
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		r.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	r.pattern = globToRegexp(line)
+	re, err := regexp.Compile("^" + r.pattern + "$")
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	r.re = re
+	return r, true
+}
+
+// globToRegexp translates gitignore wildcards: "*" and "?" stay within a
+// path segment, "**" spans segments.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case glob[i:] == "/**":
+			// Everything inside, but not the directory itself.
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			if end := strings.IndexByte(glob[i+1:], ']'); end >= 0 {
+				class := glob[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + class + "]")
+				i += end + 1
+			} else {
+				b.WriteString(`\[`)
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// ignored reports whether the rules exclude path, an absolute path.
+func (ig *ignorer) ignored(path string, isDir bool) bool {
+	ignored := false
+	for _, r := range ig.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(r.base, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		subject := filepath.Base(path)
+		if r.anchored {
+			subject = filepath.ToSlash(rel)
+		}
+		if r.re.MatchString(subject) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// SlitherFilters translates the .solsecignore rules that apply to target
+// into regular expressions for Slither's --filter-paths, which drops the
+// findings in source files whose absolute path matches one. Without them
+// Slither still reports on ignored files the analyzed ones import, such as
+// vendored libraries. A negation becomes a lookahead on the rules before
+// it. .gitignore rules are left out: they exclude build output, which
+// Slither never reports on.
+func SlitherFilters(target string) []string {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return nil
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil
+	}
+	dir := abs
+	if !info.IsDir() {
+		dir = filepath.Dir(abs)
+	}
+
+	ig := &ignorer{}
+	for _, d := range ancestors(repoRoot(dir), dir) {
+		ig.loadFiles(d, ".solsecignore")
+	}
+	if !info.IsDir() {
+		if ig.ignored(abs, false) {
+			// A file passed directly is always analyzed.
+			return nil
+		}
+	} else {
+		nested, _ := walk(dir, nil, func(path string) bool { return filepath.Base(path) == ".solsecignore" })
+		for _, f := range nested {
+			if d := filepath.Dir(f); d != dir {
+				ig.loadFiles(d, ".solsecignore")
+			}
+		}
+	}
+
+	return ig.filters()
+}
+
+// filters translates the rules into Slither --filter-paths expressions.
+func (ig *ignorer) filters() []string {
+	var filters []string
+	for i, r := range ig.rules {
+		if r.negate {
+			continue
+		}
+		var negations []string
+		for _, n := range ig.rules[i+1:] {
+			if n.negate {
+				negations = append(negations, n.pathPattern())
+			}
+		}
+		filter := r.pathPattern()
+		if len(negations) > 0 {
+			filter = "^(?!" + strings.Join(negations, "|") + ")" + filter[1:]
+		}
+		// Slither splits the flag's value on commas.
+		if !strings.Contains(filter, ",") {
+			filters = append(filters, filter)
+		}
+	}
+	return filters
+}
+
+// pathPattern is a regular expression matching the absolute paths of the
+// files the rule excludes, including everything inside an excluded
+// directory.
+func (r ignoreRule) pathPattern() string {
+	p := "^" + regexp.QuoteMeta(filepath.ToSlash(r.base)) + "/"
+	if !r.anchored {
+		p += "(?:.*/)?"
+	}
+	p += "(?:" + r.pattern + ")"
+	if r.dirOnly {
+		return p + "/"
+	}
+	return p + "(?:/|$)"
+}
+
+// Excluder drops the paths that gitignore-syntax patterns, relative to the
+// working directory, exclude on top of the ignore files (--exclude-paths).
+// A nil Excluder excludes nothing.
+type Excluder struct {
+	ig *ignorer
+}
+
+// NewExcluder returns an Excluder for patterns, or nil when there are none.
+func NewExcluder(patterns []string) *Excluder {
+	if len(patterns) == 0 {
+		return nil
+	}
+	base, err := filepath.Abs(".")
+	if err != nil {
+		return nil
+	}
+	ig := &ignorer{}
+	for _, p := range patterns {
+		if r, ok := parseIgnoreRule(base, p); ok {
+			ig.rules = append(ig.rules, r)
+		}
+	}
+	return &Excluder{ig: ig}
+}
+
+// Excluded reports whether path, or a directory containing it, matches.
+func (e *Excluder) Excluded(path string) bool {
+	if e == nil {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for d := filepath.Dir(abs); d != filepath.Dir(d); d = filepath.Dir(d) {
+		if e.ig.ignored(d, true) {
+			return true
+		}
+	}
+	return e.ig.ignored(abs, false)
+}
+
+// Filter returns the files that are not excluded.
+func (e *Excluder) Filter(files []string) []string {
+	if e == nil {
+		return files
+	}
+	kept := make([]string, 0, len(files))
+	for _, f := range files {
+		if !e.Excluded(f) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// SlitherFilters translates the patterns into Slither --filter-paths
+// expressions, as SlitherFilters does for .solsecignore.
+func (e *Excluder) SlitherFilters() []string {
+	if e == nil {
+		return nil
+	}
+	return e.ig.filters()
+}
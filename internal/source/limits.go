@@ -0,0 +1,83 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits guard the custom checks against pathological inputs such as
+// generated flattened files or accidentally committed artifacts, which would
+// otherwise dominate analysis time.
+type Limits struct {
+	MaxFileSize   int64 // bytes; 0 means no limit
+	MaxLineLength int   // characters; 0 means no limit
+}
+
+// DefaultLimits skip files over 1 MB and files with lines long enough to be
+// embedded bytecode or data blobs rather than source.
+var DefaultLimits = Limits{MaxFileSize: 1 << 20, MaxLineLength: 20000}
+
+// Screen returns why the file at path should be skipped under l, or "" when
+// it is fit to analyze. Binary content (NUL bytes or invalid UTF-8) is always
+// skipped.
+func (l Limits) Screen(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	if l.MaxFileSize > 0 && info.Size() > l.MaxFileSize {
+		return fmt.Sprintf("%s exceeds the %s size limit", FormatSize(info.Size()), FormatSize(l.MaxFileSize))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return "binary data"
+	}
+	if l.MaxLineLength > 0 {
+		for i, line := range bytes.Split(data, []byte("\n")) {
+			if n := utf8.RuneCount(line); n > l.MaxLineLength {
+				return fmt.Sprintf("embedded data: line %d is %d characters long", i+1, n)
+			}
+		}
+	}
+	return ""
+}
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+// ParseSize parses a size such as "512KB", "2MB" or "1048576" (bytes).
+func ParseSize(s string) (int64, error) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+	unit := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(text, u.suffix) {
+			text, unit = strings.TrimSpace(strings.TrimSuffix(text, u.suffix)), u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (e.g. 512KB, 2MB)", s)
+	}
+	return int64(n * float64(unit)), nil
+}
+
+// FormatSize renders bytes in the largest fitting unit, e.g. "1.5 MB".
+func FormatSize(n int64) string {
+	for _, u := range sizeUnits[:len(sizeUnits)-1] {
+		if n >= u.bytes {
+			value := strconv.FormatFloat(float64(n)/float64(u.bytes), 'f', 1, 64)
+			return strings.TrimSuffix(value, ".0") + " " + u.suffix
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
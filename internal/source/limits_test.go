@@ -0,0 +1,40 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitsScreen(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		return path
+	}
+	l := Limits{MaxFileSize: 2048, MaxLineLength: 100}
+
+	assert.Empty(t, l.Screen(write("Ok.sol", []byte("pragma solidity ^0.8.0;\ncontract A {}\n"))))
+	assert.Equal(t, "2.9 KB exceeds the 2 KB size limit", l.Screen(write("Flat.sol", []byte(strings.Repeat("// x\n", 600)))))
+	assert.Equal(t, "binary data", l.Screen(write("Artifact.sol", []byte{0x60, 0x80, 0x00, 0x40})))
+	assert.Equal(t, "embedded data: line 2 is 148 characters long",
+		l.Screen(write("Blob.sol", []byte("contract A {\nbytes c = hex\""+strings.Repeat("ab", 66)+"\";\n}\n"))))
+	assert.Empty(t, Limits{}.Screen(filepath.Join(dir, "Flat.sol")), "zero limits only reject binary data")
+}
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{"1048576": 1 << 20, "512KB": 512 << 10, "2mb": 2 << 20, "1.5 MB": 3 << 19, "0": 0} {
+		got, err := ParseSize(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseSize("big")
+	assert.Error(t, err)
+	assert.Equal(t, "1 MB", FormatSize(1<<20))
+	assert.Equal(t, "512 B", FormatSize(512))
+}
@@ -0,0 +1,189 @@
+// Package source discovers and reads the Solidity files that make up an
+// analysis target.
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SolidityFiles returns all .sol files at the given path.
+// If path is a file, returns [path]. If a directory, walks it recursively,
+// skipping .git and whatever .gitignore and .solsecignore files (in the
+// directory, below it, and above it up to the repository root) exclude.
+func SolidityFiles(target string) ([]string, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{target}, nil
+	}
+
+	return walk(target, nil, func(path string) bool { return filepath.Ext(path) == ".sol" })
+}
+
+// RepoFiles returns every file in the git repository enclosing target (or
+// its project root outside a repository), skipping what SolidityFiles skips
+// plus node_modules and nested repositories such as git submodules, which
+// are other projects' code.
+func RepoFiles(target string) ([]string, error) {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(abs); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		abs = filepath.Dir(abs)
+	}
+	root := repoRoot(abs)
+	// Keep paths relative when the target was, as for SolidityFiles.
+	if !filepath.IsAbs(target) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, root); err == nil {
+				root = rel
+			}
+		}
+	}
+	skipDir := func(path string) bool {
+		if filepath.Base(path) == "node_modules" {
+			return true
+		}
+		_, err := os.Stat(filepath.Join(path, ".git"))
+		return err == nil
+	}
+	return walk(root, skipDir, func(string) bool { return true })
+}
+
+// walk returns the files under dir that keep accepts, skipping .git, the
+// directories skipDir (which may be nil) rejects, and whatever ignore files
+// exclude.
+func walk(dir string, skipDir func(path string) bool, keep func(path string) bool) ([]string, error) {
+	ig := newIgnorer(dir)
+	var files []string
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if path != dir && (fi.Name() == ".git" || ig.ignored(abs, true) || (skipDir != nil && skipDir(path))) {
+				return filepath.SkipDir
+			}
+			ig.load(path)
+			return nil
+		}
+		if keep(path) && !ig.ignored(abs, false) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// SLOC counts source lines of code in a Solidity file: lines that are not
+// blank and not entirely comments.
+func SLOC(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return CountSLOC(string(data)), nil
+}
+
+// CountSLOC counts source lines of code in Solidity text.
+func CountSLOC(text string) int {
+	count := 0
+	inBlock := false
+	for _, line := range strings.Split(text, "\n") {
+		code := false
+		rest := strings.TrimSpace(line)
+		for rest != "" {
+			if inBlock {
+				end := strings.Index(rest, "*/")
+				if end < 0 {
+					rest = ""
+					break
+				}
+				inBlock = false
+				rest = strings.TrimSpace(rest[end+2:])
+				continue
+			}
+			if strings.HasPrefix(rest, "//") {
+				break
+			}
+			if strings.HasPrefix(rest, "/*") {
+				inBlock = true
+				rest = rest[2:]
+				continue
+			}
+			code = true
+			// Only a block comment opening later on this line changes state.
+			if i := strings.Index(rest, "/*"); i >= 0 && !strings.Contains(rest[:i], "//") {
+				rest = rest[i:]
+				continue
+			}
+			break
+		}
+		if code {
+			count++
+		}
+	}
+	return count
+}
+
+// Pragma returns the version constraint of the first `pragma solidity`
+// directive in text, e.g. "^0.8.24", or "" if there is none.
+func Pragma(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "pragma solidity") {
+			continue
+		}
+		constraint := strings.TrimPrefix(trimmed, "pragma solidity")
+		if i := strings.Index(constraint, ";"); i >= 0 {
+			constraint = constraint[:i]
+		}
+		return strings.TrimSpace(constraint)
+	}
+	return ""
+}
+
+// projectMarkers are files that identify the root of a Solidity project.
+var projectMarkers = []string{
+	"foundry.toml",
+	"hardhat.config.js",
+	"hardhat.config.ts",
+	"truffle-config.js",
+	"ape-config.yaml",
+}
+
+// ProjectRoot walks up from target to the nearest directory containing a
+// framework config file. Without one, the target directory itself is used.
+func ProjectRoot(target string) string {
+	dir := target
+	if info, err := os.Stat(target); err != nil || !info.IsDir() {
+		dir = filepath.Dir(target)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	for d := dir; ; {
+		for _, m := range projectMarkers {
+			if _, err := os.Stat(filepath.Join(d, m)); err == nil {
+				return d
+			}
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
@@ -0,0 +1,163 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountSLOC(t *testing.T) {
+	text := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+/*
+ * Multi-line header
+ */
+contract A { /* inline */
+    uint256 x; // trailing comment
+    /* a */ uint256 y;
+    // uint256 z;
+}
+`
+	assert.Equal(t, 5, CountSLOC(text))
+}
+
+func TestSolidityFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "A.sol"), []byte("contract A {}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "B.sol"), []byte("contract B {}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# x"), 0644))
+
+	files, err := SolidityFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "A.sol"), filepath.Join(dir, "sub", "B.sol")}, files)
+}
+
+func TestPragma(t *testing.T) {
+	assert.Equal(t, "^0.8.24", Pragma("// SPDX-License-Identifier: MIT\npragma solidity ^0.8.24;\n"))
+	assert.Equal(t, ">=0.7.0 <0.9.0", Pragma("pragma solidity >=0.7.0 <0.9.0; // range"))
+	assert.Empty(t, Pragma("contract A {}"))
+}
+
+func TestSolidityFiles_Ignore(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+	write(".git/Hook.sol", "")
+	write(".gitignore", "out/\ncache\n/node_modules/\n*.flattened.sol\n")
+	write(".solsecignore", "src/mocks/**\n!src/mocks/Keep.sol\n")
+	write("src/Token.sol", "")
+	write("src/Token.flattened.sol", "")
+	write("src/mocks/Mock.sol", "")
+	write("src/mocks/Keep.sol", "")
+	write("src/vendor/.gitignore", "Old*.sol\n")
+	write("src/vendor/OldLib.sol", "")
+	write("src/vendor/Lib.sol", "")
+	write("out/Token.sol/Token.sol", "")
+	write("lib/cache/Dep.sol", "")
+	write("node_modules/@oz/ERC20.sol", "")
+	write("test/node_modules/Fixture.sol", "")
+
+	files, err := SolidityFiles(dir)
+	require.NoError(t, err)
+	var rel []string
+	for _, f := range files {
+		r, _ := filepath.Rel(dir, f)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	assert.Equal(t, []string{
+		"src/Token.sol",
+		"src/mocks/Keep.sol",
+		"src/vendor/Lib.sol",
+		"test/node_modules/Fixture.sol",
+	}, rel)
+
+	// Ignore files above a subdirectory target still apply.
+	files, err = SolidityFiles(filepath.Join(dir, "src", "vendor"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "src", "vendor", "Lib.sol")}, files)
+}
+
+func TestSlitherFilters(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+	write(".gitignore", "out/\n")
+	write(".solsecignore", "lib/\n*.t.sol\n/test/mocks\n")
+	write("src/Token.sol", "")
+	write("src/vendor/.solsecignore", "Old*.sol\n")
+
+	filters := SlitherFilters(filepath.Join(dir, "src"))
+	require.Len(t, filters, 4, ".gitignore rules are left out")
+	root := filepath.ToSlash(dir)
+	for path, want := range map[string]bool{
+		"lib/openzeppelin/token/ERC20.sol": true,
+		"src/lib/Math.sol":                 true,
+		"src/Token.t.sol":                  true,
+		"test/mocks/Mock.sol":              true,
+		"src/test/mocks/Mock.sol":          false,
+		"src/vendor/OldLib.sol":            true,
+		"src/OldLib.sol":                   false,
+		"src/Token.sol":                    false,
+		"out/Token.sol":                    false,
+	} {
+		matched := false
+		for _, f := range filters {
+			matched = matched || regexp.MustCompile(f).MatchString(root+"/"+path)
+		}
+		assert.Equal(t, want, matched, path)
+	}
+
+	// A file passed directly filters its imports, but is never filtered
+	// itself.
+	assert.Len(t, SlitherFilters(filepath.Join(dir, "src", "Token.sol")), 3)
+	write("src/Token.t.sol", "")
+	assert.Nil(t, SlitherFilters(filepath.Join(dir, "src", "Token.t.sol")))
+}
+
+func TestSlitherFilters_Negation(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".solsecignore"), []byte("mocks/**\n!mocks/Keep.sol\n"), 0644))
+
+	base := regexp.QuoteMeta(filepath.ToSlash(dir))
+	assert.Equal(t, []string{
+		"^(?!^" + base + "/(?:mocks/Keep\\.sol)(?:/|$))" + base + "/(?:mocks/.*)(?:/|$)",
+	}, SlitherFilters(dir), "Slither's regexes support lookaheads, Go's do not")
+}
+
+func TestExcluder(t *testing.T) {
+	e := NewExcluder([]string{"lib/", "**/mocks/**", "*.t.sol", "!Keep.t.sol"})
+	for path, want := range map[string]bool{
+		"lib/openzeppelin/ERC20.sol": true,
+		"src/lib/Math.sol":           true,
+		"src/mocks/Mock.sol":         true,
+		"test/Token.t.sol":           true,
+		"test/Keep.t.sol":            false,
+		"src/Token.sol":              false,
+		"src/library/Lib.sol":        false,
+	} {
+		assert.Equal(t, want, e.Excluded(filepath.FromSlash(path)), path)
+	}
+	assert.Equal(t, []string{"src/Token.sol"}, e.Filter([]string{"lib/A.sol", "src/Token.sol"}))
+	assert.False(t, e.Excluded(filepath.Join(t.TempDir(), "lib", "A.sol")), "outside the working directory")
+	assert.Len(t, e.SlitherFilters(), 3)
+
+	var none *Excluder
+	assert.Nil(t, NewExcluder(nil))
+	assert.False(t, none.Excluded("lib/A.sol"))
+	assert.Equal(t, []string{"lib/A.sol"}, none.Filter([]string{"lib/A.sol"}))
+	assert.Empty(t, none.SlitherFilters())
+}
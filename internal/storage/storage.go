@@ -0,0 +1,272 @@
+// Package storage reads contract storage layouts from solc output and
+// compares two versions of a layout for upgrade safety.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Layout is the storage layout of one contract.
+type Layout struct {
+	Contract string `json:"contract"`
+	File     string `json:"file"`
+	Vars     []Var  `json:"storage"`
+}
+
+// Var is one state variable's storage location.
+type Var struct {
+	Label  string `json:"label"`
+	Type   string `json:"type"` // e.g. "uint256" or "mapping(address => uint256)"
+	Slot   string `json:"slot"` // decimal; slots can exceed 64 bits
+	Offset int    `json:"offset"`
+	Bytes  int    `json:"bytes"`
+
+	// shape is the type with struct members expanded, so a change inside a
+	// struct is a type change even when its name stays the same.
+	shape string
+}
+
+// solcLayout is the "storage-layout" output of one contract.
+type solcLayout struct {
+	Storage []struct {
+		Label  string `json:"label"`
+		Offset int    `json:"offset"`
+		Slot   string `json:"slot"`
+		Type   string `json:"type"`
+	} `json:"storage"`
+	Types map[string]solcType `json:"types"`
+}
+
+type solcType struct {
+	Label         string `json:"label"`
+	NumberOfBytes string `json:"numberOfBytes"`
+	Key           string `json:"key"`
+	Value         string `json:"value"`
+	Base          string `json:"base"`
+	Members       []struct {
+		Label string `json:"label"`
+		Type  string `json:"type"`
+	} `json:"members"`
+}
+
+// FromCombinedJSON reads the output of `solc --combined-json storage-layout`
+// and returns the layout of every contract with state variables, sorted by
+// file and contract.
+func FromCombinedJSON(data []byte) ([]Layout, error) {
+	var out struct {
+		Contracts map[string]struct {
+			Layout json.RawMessage `json:"storage-layout"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing solc output: %w", err)
+	}
+
+	var layouts []Layout
+	for key, c := range out.Contracts {
+		file, contract := key, key
+		if i := strings.LastIndex(key, ":"); i >= 0 {
+			file, contract = key[:i], key[i+1:]
+		}
+		raw := c.Layout
+		// Older solc releases emit the layout as a JSON string.
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			raw = json.RawMessage(s)
+		}
+		var sl solcLayout
+		if err := json.Unmarshal(raw, &sl); err != nil {
+			return nil, fmt.Errorf("%s: parsing storage layout: %w", key, err)
+		}
+		if len(sl.Storage) == 0 {
+			continue
+		}
+		l := Layout{Contract: contract, File: file}
+		for _, v := range sl.Storage {
+			t := sl.Types[v.Type]
+			size, _ := strconv.Atoi(t.NumberOfBytes)
+			l.Vars = append(l.Vars, Var{
+				Label: v.Label, Type: t.Label, Slot: v.Slot, Offset: v.Offset, Bytes: size,
+				shape: shape(sl.Types, v.Type, 0),
+			})
+		}
+		layouts = append(layouts, l)
+	}
+	sort.Slice(layouts, func(i, j int) bool {
+		if layouts[i].File != layouts[j].File {
+			return layouts[i].File < layouts[j].File
+		}
+		return layouts[i].Contract < layouts[j].Contract
+	})
+	return layouts, nil
+}
+
+// shape describes the type id, expanding struct members and the key and
+// value of mappings and the base of arrays.
+func shape(types map[string]solcType, id string, depth int) string {
+	t := types[id]
+	if depth > 8 {
+		return t.Label // recursive struct
+	}
+	switch {
+	case len(t.Members) > 0:
+		members := make([]string, len(t.Members))
+		for i, m := range t.Members {
+			members[i] = m.Label + ":" + shape(types, m.Type, depth+1)
+		}
+		return t.Label + "{" + strings.Join(members, ",") + "}"
+	case t.Key != "":
+		return "mapping(" + shape(types, t.Key, depth+1) + "=>" + shape(types, t.Value, depth+1) + ")"
+	case t.Base != "":
+		return t.Label + "<" + shape(types, t.Base, depth+1) + ">"
+	}
+	return t.Label
+}
+
+// Change kinds.
+const (
+	Appended    = "appended"     // new variable in storage the old layout did not use: safe
+	Renamed     = "renamed"      // same slot and type, new name: safe, but check intent
+	GapConsumed = "gap-consumed" // new variable placed in a reserved __gap: safe
+	Removed     = "removed"      // the old variable's slot no longer holds it
+	Moved       = "moved"        // the old variable is stored somewhere else
+	Retyped     = "type-changed" // same slot, different type
+	Inserted    = "inserted"     // new variable over storage the old layout used
+)
+
+// Change is one difference between two layouts of a contract.
+type Change struct {
+	Kind   string `json:"kind"`
+	Label  string `json:"label"`
+	Slot   string `json:"slot"`
+	Offset int    `json:"offset"`
+	Detail string `json:"detail"`
+	Unsafe bool   `json:"unsafe"`
+}
+
+type pos struct {
+	slot   string
+	offset int
+}
+
+// Diff compares the layout of an upgraded contract with the deployed one.
+// Upgrades must keep every old variable at its slot and offset with its
+// type; new variables may only use storage the old layout left unused, or
+// space reserved by a __gap array whose end stays in place.
+func Diff(old, upgraded Layout) []Change {
+	newAt := map[pos]Var{}
+	newByLabel := map[string]Var{}
+	for _, v := range upgraded.Vars {
+		newAt[pos{v.Slot, v.Offset}] = v
+		newByLabel[v.Label] = v
+	}
+	oldAt := map[pos]Var{}
+	oldByLabel := map[string]Var{}
+	for _, v := range old.Vars {
+		oldAt[pos{v.Slot, v.Offset}] = v
+		oldByLabel[v.Label] = v
+	}
+
+	// Old gaps that a new gap still ends with may hold new variables.
+	var gaps [][2]*big.Int
+	for _, g := range old.Vars {
+		if !isGap(g) {
+			continue
+		}
+		start, end := byteRange(g)
+		for _, n := range upgraded.Vars {
+			if _, nEnd := byteRange(n); isGap(n) && nEnd.Cmp(end) == 0 {
+				gaps = append(gaps, [2]*big.Int{start, end})
+			}
+		}
+	}
+	inGap := func(v Var) bool {
+		start, _ := byteRange(v)
+		for _, g := range gaps {
+			if start.Cmp(g[0]) >= 0 && start.Cmp(g[1]) < 0 {
+				return true
+			}
+		}
+		return false
+	}
+	moved := func(o Var) bool {
+		m, ok := newByLabel[o.Label]
+		return ok && !isGap(o) && (m.Slot != o.Slot || m.Offset != o.Offset)
+	}
+
+	var changes []Change
+	add := func(kind string, v Var, unsafe bool, format string, args ...any) {
+		changes = append(changes, Change{Kind: kind, Label: v.Label, Slot: v.Slot, Offset: v.Offset,
+			Detail: fmt.Sprintf(format, args...), Unsafe: unsafe})
+	}
+
+	for _, o := range old.Vars {
+		n, ok := newAt[pos{o.Slot, o.Offset}]
+		switch {
+		case isGap(o) && inGap(o):
+			// The gap shrank to make room; its end stays in place.
+		case moved(o):
+			m := newByLabel[o.Label]
+			add(Moved, o, true, "%s %s moved from slot %s offset %d to slot %s offset %d", o.Type, o.Label, o.Slot, o.Offset, m.Slot, m.Offset)
+		case !ok:
+			add(Removed, o, true, "%s %s is no longer stored at slot %s offset %d", o.Type, o.Label, o.Slot, o.Offset)
+		case n.shape != o.shape:
+			add(Retyped, o, true, "%s %s is now %s %s", o.Type, o.Label, n.Type, n.Label)
+		case n.Label != o.Label:
+			add(Renamed, o, false, "%s renamed to %s", o.Label, n.Label)
+		}
+	}
+
+	for _, n := range upgraded.Vars {
+		if o, ok := oldByLabel[n.Label]; ok && moved(o) {
+			continue // reported as moved
+		}
+		if o, ok := oldAt[pos{n.Slot, n.Offset}]; ok && !moved(o) && !(isGap(o) && inGap(o)) {
+			continue // compared above
+		}
+		switch {
+		case isGap(n) && inGap(n):
+		case inGap(n):
+			add(GapConsumed, n, false, "%s %s added in space reserved by __gap", n.Type, n.Label)
+		case overlaps(n, old.Vars, inGap):
+			add(Inserted, n, true, "%s %s added over storage the old layout used", n.Type, n.Label)
+		default:
+			add(Appended, n, false, "%s %s added", n.Type, n.Label)
+		}
+	}
+	return changes
+}
+
+// overlaps reports whether v shares bytes with an old variable, other than
+// a gap given up for new variables.
+func overlaps(v Var, old []Var, inGap func(Var) bool) bool {
+	start, end := byteRange(v)
+	for _, o := range old {
+		if isGap(o) && inGap(o) {
+			continue
+		}
+		oStart, oEnd := byteRange(o)
+		if start.Cmp(oEnd) < 0 && oStart.Cmp(end) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func isGap(v Var) bool { return strings.HasPrefix(v.Label, "__gap") }
+
+// byteRange returns the storage bytes v occupies, counting slots as 32
+// bytes: [slot*32+offset, slot*32+offset+bytes).
+func byteRange(v Var) (start, end *big.Int) {
+	slot, ok := new(big.Int).SetString(v.Slot, 10)
+	if !ok {
+		slot = big.NewInt(0)
+	}
+	start = new(big.Int).Add(new(big.Int).Mul(slot, big.NewInt(32)), big.NewInt(int64(v.Offset)))
+	return start, new(big.Int).Add(start, big.NewInt(int64(max(v.Bytes, 1))))
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const types = `{
+  "t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+  "t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+  "t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+  "t_array(t_uint256)48_storage": {"base": "t_uint256", "encoding": "inplace", "label": "uint256[48]", "numberOfBytes": "1536"},
+  "t_array(t_uint256)49_storage": {"base": "t_uint256", "encoding": "inplace", "label": "uint256[49]", "numberOfBytes": "1568"},
+  "t_array(t_uint256)50_storage": {"base": "t_uint256", "encoding": "inplace", "label": "uint256[50]", "numberOfBytes": "1600"},
+  "t_mapping(t_address,t_uint256)": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+  "t_struct(Pos)1_storage": {"encoding": "inplace", "label": "struct Vault.Pos", "numberOfBytes": "32", "members": [{"label": "amount", "type": "t_uint256"}]},
+  "t_struct(Pos)2_storage": {"encoding": "inplace", "label": "struct Vault.Pos", "numberOfBytes": "32", "members": [{"label": "shares", "type": "t_uint256"}]}
+}`
+
+// layout builds solc output for a Vault whose storage entries are given as
+// [label, slot, offset, type id].
+func layout(t *testing.T, vars ...[4]string) Layout {
+	storage := ""
+	for i, v := range vars {
+		if i > 0 {
+			storage += ","
+		}
+		storage += `{"label": "` + v[0] + `", "slot": "` + v[1] + `", "offset": ` + v[2] + `, "type": "` + v[3] + `"}`
+	}
+	out := `{"contracts": {
+  "src/Vault.sol:Vault": {"storage-layout": {"storage": [` + storage + `], "types": ` + types + `}},
+  "src/Vault.sol:IVault": {"storage-layout": {"storage": [], "types": null}}
+}}`
+	layouts, err := FromCombinedJSON([]byte(out))
+	require.NoError(t, err)
+	require.Len(t, layouts, 1, "contracts without storage are left out")
+	return layouts[0]
+}
+
+func kinds(changes []Change) map[string]string {
+	out := map[string]string{}
+	for _, c := range changes {
+		out[c.Label] = c.Kind
+	}
+	return out
+}
+
+func TestFromCombinedJSON(t *testing.T) {
+	l := layout(t, [4]string{"owner", "0", "0", "t_address"}, [4]string{"paused", "0", "20", "t_bool"},
+		[4]string{"balances", "1", "0", "t_mapping(t_address,t_uint256)"})
+	assert.Equal(t, "Vault", l.Contract)
+	assert.Equal(t, "src/Vault.sol", l.File)
+	require.Len(t, l.Vars, 3)
+	assert.Equal(t, Var{Label: "paused", Type: "bool", Slot: "0", Offset: 20, Bytes: 1, shape: "bool"}, l.Vars[1])
+	assert.Equal(t, "mapping(address => uint256)", l.Vars[2].Type)
+}
+
+func TestDiff(t *testing.T) {
+	old := layout(t, [4]string{"owner", "0", "0", "t_address"}, [4]string{"total", "1", "0", "t_uint256"})
+
+	// Appending, packing into the last slot and renaming are safe.
+	changes := Diff(old, layout(t, [4]string{"admin", "0", "0", "t_address"}, [4]string{"paused", "0", "20", "t_bool"},
+		[4]string{"total", "1", "0", "t_uint256"}, [4]string{"cap", "2", "0", "t_uint256"}))
+	assert.Equal(t, map[string]string{"owner": Renamed, "paused": Appended, "cap": Appended}, kinds(changes),
+		"paused packs into unused bytes of slot 0")
+	for _, c := range changes {
+		assert.False(t, c.Unsafe, c.Kind)
+	}
+
+	// Inserting shifts every later variable.
+	changes = Diff(old, layout(t, [4]string{"owner", "0", "0", "t_address"}, [4]string{"cap", "1", "0", "t_uint256"},
+		[4]string{"total", "2", "0", "t_uint256"}))
+	assert.Equal(t, map[string]string{"total": Moved, "cap": Inserted}, kinds(changes))
+	for _, c := range changes {
+		assert.True(t, c.Unsafe, c.Kind)
+	}
+
+	// Removing a variable, and changing a type.
+	changes = Diff(old, layout(t, [4]string{"total", "1", "0", "t_address"}))
+	assert.Equal(t, map[string]string{"owner": Removed, "total": Retyped}, kinds(changes))
+
+	// A different variable at an old one's slot and offset.
+	changes = Diff(old, layout(t, [4]string{"flag", "0", "0", "t_bool"}, [4]string{"total", "1", "0", "t_uint256"}))
+	assert.Equal(t, map[string]string{"owner": Retyped}, kinds(changes), "flag at owner's slot is compared as a type change")
+}
+
+func TestDiff_StructMembers(t *testing.T) {
+	old := layout(t, [4]string{"pos", "0", "0", "t_struct(Pos)1_storage"})
+	changes := Diff(old, layout(t, [4]string{"pos", "0", "0", "t_struct(Pos)2_storage"}))
+	require.Len(t, changes, 1)
+	assert.Equal(t, Retyped, changes[0].Kind, "same struct name, different members")
+}
+
+func TestDiff_Gap(t *testing.T) {
+	old := layout(t, [4]string{"owner", "0", "0", "t_address"}, [4]string{"__gap", "1", "0", "t_array(t_uint256)50_storage"})
+
+	// Taking slots from the gap, which keeps its end at slot 51, is safe.
+	changes := Diff(old, layout(t, [4]string{"owner", "0", "0", "t_address"}, [4]string{"cap", "1", "0", "t_uint256"},
+		[4]string{"fee", "2", "0", "t_uint256"}, [4]string{"__gap", "3", "0", "t_array(t_uint256)48_storage"}))
+	assert.Equal(t, map[string]string{"cap": GapConsumed, "fee": GapConsumed}, kinds(changes))
+
+	// Forgetting to shrink the gap moves its end.
+	changes = Diff(old, layout(t, [4]string{"owner", "0", "0", "t_address"}, [4]string{"cap", "1", "0", "t_uint256"},
+		[4]string{"__gap", "2", "0", "t_array(t_uint256)50_storage"}))
+	assert.Contains(t, kinds(changes), "__gap")
+	unsafe := 0
+	for _, c := range changes {
+		if c.Unsafe {
+			unsafe++
+		}
+	}
+	assert.NotZero(t, unsafe)
+}
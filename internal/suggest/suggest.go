@@ -0,0 +1,43 @@
+// Package suggest provides "did you mean" helpers for user-supplied names.
+package suggest
+
+import "strings"
+
+// Closest returns the candidate nearest to input by edit distance, or "" when
+// nothing is close enough to be a plausible typo.
+func Closest(input string, candidates []string) string {
+	input = strings.ToLower(input)
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		d := distance(input, strings.ToLower(c))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	// Allow roughly one typo per three characters.
+	if bestDist < 0 || bestDist > len(input)/3+1 {
+		return ""
+	}
+	return best
+}
+
+// distance is the Levenshtein edit distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
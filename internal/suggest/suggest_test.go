@@ -0,0 +1,17 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"reentrancy-eth", "reentrancy-no-eth", "timestamp", "tx-origin"}
+
+	assert.Equal(t, "timestamp", Closest("timestmap", candidates))
+	assert.Equal(t, "reentrancy-eth", Closest("reentrancy-et", candidates))
+	assert.Equal(t, "tx-origin", Closest("TX-ORIGIN", candidates))
+	assert.Empty(t, Closest("completely-unrelated", candidates))
+	assert.Empty(t, Closest("x", nil))
+}
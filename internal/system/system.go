@@ -0,0 +1,179 @@
+// Package system relates the components of a protocol deployed as several
+// contracts. Address constants in one component's source are resolved to
+// the other components, and each dependency on a component its owner can
+// change, or on code outside the system, is reported as a trust finding.
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zubimendi/solsec/internal/explorer"
+	"github.com/Zubimendi/solsec/internal/parser"
+	"github.com/Zubimendi/solsec/internal/rules"
+	"github.com/Zubimendi/solsec/internal/solidity"
+	"github.com/Zubimendi/solsec/internal/source"
+)
+
+var (
+	addressRe = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+	zeroRe    = regexp.MustCompile(`^0x0{40}$`)
+)
+
+// ParseComponent parses a component given as path@address, or as a bare
+// path when the component's address does not matter. Its name is the base
+// name of path. A bare address names a deployed component whose verified
+// source the caller fetches; its Name and Path are left empty.
+func ParseComponent(spec string) (parser.Component, error) {
+	path, addr, _ := strings.Cut(spec, "@")
+	if explorer.IsAddress(path) {
+		if addr != "" {
+			return parser.Component{}, fmt.Errorf("%s: expected <path>@<address> or <address>", spec)
+		}
+		return parser.Component{Address: strings.ToLower(path)}, nil
+	}
+	if addr != "" && !explorer.IsAddress(addr) {
+		return parser.Component{}, fmt.Errorf("%s: %q is not an address", spec, addr)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return parser.Component{Name: name, Address: strings.ToLower(addr), Path: path}, nil
+}
+
+// Resolve finds the address constants in every component's source. Each
+// component and address gives one edge, at its first occurrence. Addresses
+// of the component itself and the zero address are skipped.
+func Resolve(components []parser.Component) ([]parser.TrustEdge, error) {
+	byAddress := map[string]string{}
+	for _, c := range components {
+		if c.Address != "" {
+			byAddress[c.Address] = c.Name
+		}
+	}
+
+	var edges []parser.TrustEdge
+	for _, c := range components {
+		files, err := source.SolidityFiles(c.Path)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", c.Name, err)
+		}
+		seen := map[string]bool{}
+		for _, path := range files {
+			f, err := solidity.ParseFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("component %s: reading %s: %w", c.Name, path, err)
+			}
+			for _, m := range addressRe.FindAllStringIndex(f.Code, -1) {
+				addr := strings.ToLower(f.Code[m[0]:m[1]])
+				if addr == c.Address || zeroRe.MatchString(addr) || seen[addr] {
+					continue
+				}
+				seen[addr] = true
+				edges = append(edges, parser.TrustEdge{
+					From:    c.Name,
+					To:      byAddress[addr],
+					Address: addr,
+					File:    path,
+					Line:    strings.Count(f.Code[:m[0]], "\n") + 1,
+				})
+			}
+		}
+	}
+	return edges, nil
+}
+
+// Findings reports the trust relationships that need review: dependencies
+// on a component whose owner can change it, and on addresses outside the
+// system, whose code was not analyzed.
+func Findings(components []parser.Component, edges []parser.TrustEdge) ([]parser.Finding, error) {
+	powers := map[string][]string{}
+	for _, c := range components {
+		p, err := privileged(c.Path)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", c.Name, err)
+		}
+		powers[c.Name] = p
+	}
+	db := rules.Default()
+
+	var findings []parser.Finding
+	for _, e := range edges {
+		var f parser.Finding
+		switch {
+		case e.To == "":
+			f = parser.Finding{
+				Check: "custom-unresolved-dependency",
+				Title: fmt.Sprintf("%s Depends on Unanalyzed Address %s", e.From, e.Address),
+				Description: fmt.Sprintf(
+					"%s:%d — %s hardcodes %s, which is not one of the system's components. "+
+						"Its code was not part of this analysis, so anything %s trusts it with is unreviewed.",
+					e.File, e.Line, e.From, e.Address, e.From,
+				),
+				Severity:   parser.SeverityLow,
+				Confidence: "Medium",
+				Evidence:   []string{fmt.Sprintf("address constant %s at line %d", e.Address, e.Line)},
+			}
+		case len(powers[e.To]) > 0:
+			f = parser.Finding{
+				Check: "custom-inherited-trust",
+				Title: fmt.Sprintf("%s Trusts Privileged Component %s", e.From, e.To),
+				Description: fmt.Sprintf(
+					"%s:%d — %s hardcodes the address of %s (%s), whose behaviour can be changed "+
+						"by privileged callers. %s inherits that trust: whoever holds those roles "+
+						"can affect %s without touching it.",
+					e.File, e.Line, e.From, e.To, e.Address, e.From, e.From,
+				),
+				Severity:   parser.SeverityMedium,
+				Confidence: "Medium",
+				Evidence: []string{
+					fmt.Sprintf("address constant %s at line %d is component %s", e.Address, e.Line, e.To),
+					fmt.Sprintf("%s has privileged functions: %s", e.To, strings.Join(powers[e.To], ", ")),
+				},
+			}
+		default:
+			continue
+		}
+		f.ID = fmt.Sprintf("CUSTOM-SYSTEM-%d", len(findings)+1)
+		f.Source = "custom"
+		f.File = e.File
+		f.Lines = []int{e.Line}
+		f.Remediation = db.Remediation(f.Check)
+		f.CWERef = db.CWE(f.Check)
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// upgradeFunctions change a contract's code outright.
+var upgradeFunctions = []string{"upgradeTo", "upgradeToAndCall", "_authorizeUpgrade"}
+
+// privileged lists the functions in the component's source that are
+// guarded by an access modifier or upgrade it, as "Contract.function()".
+func privileged(path string) ([]string, error) {
+	files, err := source.SolidityFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	modifiers := rules.Default().Signature("access-modifier")
+	var out []string
+	for _, p := range files {
+		f, err := solidity.ParseFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", p, err)
+		}
+		for _, c := range f.Contracts {
+			if c.Kind != "contract" {
+				continue
+			}
+			for _, fn := range c.Functions {
+				guarded := slices.ContainsFunc(fn.Modifiers, func(m string) bool { return slices.Contains(modifiers, m) })
+				if guarded || slices.Contains(upgradeFunctions, fn.Name) {
+					out = append(out, c.Name+"."+fn.Name+"()")
+				}
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+const (
+	routerAddr = "0x2222222222222222222222222222222222222222"
+	vaultAddr  = "0x1111111111111111111111111111111111111111"
+	wethAddr   = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+)
+
+func TestParseComponent(t *testing.T) {
+	c, err := ParseComponent("deploy/vault@0x1111111111111111111111111111111111111111")
+	require.NoError(t, err)
+	assert.Equal(t, parser.Component{Name: "vault", Address: vaultAddr, Path: "deploy/vault"}, c)
+
+	c, err = ParseComponent("src/Router.sol")
+	require.NoError(t, err)
+	assert.Equal(t, "Router", c.Name)
+	assert.Empty(t, c.Address)
+
+	_, err = ParseComponent("src@0x1234")
+	assert.ErrorContains(t, err, "not an address")
+	c, err = ParseComponent("0x1111111111111111111111111111111111111111")
+	require.NoError(t, err)
+	assert.Equal(t, parser.Component{Address: vaultAddr}, c, "the source of a bare address is fetched by the caller")
+	_, err = ParseComponent(vaultAddr + "@" + vaultAddr)
+	assert.ErrorContains(t, err, "expected <path>@<address> or <address>")
+}
+
+func TestResolveAndFindings(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	write("router/Router.sol", `pragma solidity ^0.8.20;
+
+contract Router {
+    // Points at 0x3333333333333333333333333333333333333333 in comments only.
+    address constant VAULT = 0x1111111111111111111111111111111111111111;
+    address constant WETH = 0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2;
+    address constant NONE = 0x0000000000000000000000000000000000000000;
+    bytes32 constant SALT = 0x1111111111111111111111111111111111111111111111111111111111111111;
+
+    function vault() external pure returns (address) { return VAULT; }
+}
+`)
+	write("vault/Vault.sol", `pragma solidity ^0.8.20;
+
+contract Vault {
+    address constant ROUTER = 0x2222222222222222222222222222222222222222;
+
+    function setFee(uint256 fee) external onlyOwner {}
+}
+`)
+	components := []parser.Component{
+		{Name: "router", Address: routerAddr, Path: filepath.Join(dir, "router")},
+		{Name: "vault", Address: vaultAddr, Path: filepath.Join(dir, "vault")},
+	}
+
+	edges, err := Resolve(components)
+	require.NoError(t, err)
+	router := filepath.Join(dir, "router", "Router.sol")
+	assert.Equal(t, []parser.TrustEdge{
+		{From: "router", To: "vault", Address: vaultAddr, File: router, Line: 5},
+		{From: "router", Address: wethAddr, File: router, Line: 6},
+		{From: "vault", To: "router", Address: routerAddr, File: filepath.Join(dir, "vault", "Vault.sol"), Line: 4},
+	}, edges)
+
+	findings, err := Findings(components, edges)
+	require.NoError(t, err)
+	// The router has no privileged functions, so the vault's dependency on
+	// it is not reported.
+	require.Len(t, findings, 2)
+	assert.Equal(t, "custom-inherited-trust", findings[0].Check)
+	assert.Equal(t, parser.SeverityMedium, findings[0].Severity)
+	assert.Contains(t, findings[0].Evidence[1], "Vault.setFee()")
+	assert.Equal(t, "custom-unresolved-dependency", findings[1].Check)
+	assert.Equal(t, []int{6}, findings[1].Lines)
+	assert.NotEmpty(t, findings[1].Remediation)
+}
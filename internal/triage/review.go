@@ -0,0 +1,222 @@
+package triage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sort"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// DefaultReviewPath is where solsec review records verdicts.
+const DefaultReviewPath = "solsec-review.json"
+
+// Review verdicts.
+const (
+	VerdictAccepted = "accepted" // a true positive
+	VerdictRejected = "rejected" // a false positive; the finding is also triaged
+)
+
+// Review is a reviewer's verdict on one finding.
+type Review struct {
+	Fingerprint string          `json:"fingerprint"`
+	ID          string          `json:"id"`
+	Check       string          `json:"check"`
+	Severity    parser.Severity `json:"severity"`
+	File        string          `json:"file,omitempty"`
+	Line        int             `json:"line,omitempty"`
+	Verdict     string          `json:"verdict"`
+	Note        string          `json:"note,omitempty"`
+	ReviewedAt  string          `json:"reviewed_at"`
+}
+
+// LoadReviews reads the review log at path. A missing file holds no reviews.
+func LoadReviews(path string) ([]Review, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading review log: %w", err)
+	}
+	var reviews []Review
+	if err := json.Unmarshal(data, &reviews); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return reviews, nil
+}
+
+// WriteReviews saves the review log at path.
+func WriteReviews(path string, reviews []Review) error {
+	data, err := json.MarshalIndent(reviews, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling review log: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Reviewed reports whether reviews hold a verdict on f.
+func Reviewed(reviews []Review, f parser.Finding) bool {
+	for _, r := range reviews {
+		if r.Fingerprint != "" && r.Fingerprint == f.Fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// Sample picks up to n findings for review, stratified by severity: every
+// severity present gets at least one pick while n allows, and the rest are
+// shared in proportion to each severity's count. Within a severity, picks
+// are spread over as many checks, then files, as possible. Findings come
+// back most severe first.
+func Sample(findings []parser.Finding, n int, rng *rand.Rand) []parser.Finding {
+	strata := map[int][]parser.Finding{}
+	var ranks []int
+	for _, f := range findings {
+		rank := parser.SeverityRank(f.Severity)
+		if _, ok := strata[rank]; !ok {
+			ranks = append(ranks, rank)
+		}
+		strata[rank] = append(strata[rank], f)
+	}
+	sort.Ints(ranks)
+
+	// One pick per severity, then each next pick goes to the severity with
+	// the most findings per pick so far.
+	alloc := map[int]int{}
+	for picked := 0; picked < n; picked++ {
+		best := -1
+		for _, rank := range ranks {
+			if alloc[rank] == 0 {
+				best = rank
+				break
+			}
+		}
+		if best < 0 {
+			bestRatio := 0.0
+			for _, rank := range ranks {
+				size := len(strata[rank])
+				if ratio := float64(size) / float64(alloc[rank]+1); alloc[rank] < size && ratio > bestRatio {
+					best, bestRatio = rank, ratio
+				}
+			}
+		}
+		if best < 0 {
+			break // every finding is picked
+		}
+		alloc[best]++
+	}
+
+	var sample []parser.Finding
+	for _, rank := range ranks {
+		sample = append(sample, spread(strata[rank], alloc[rank], rng)...)
+	}
+	return sample
+}
+
+// spread picks n of findings in random order, preferring checks and then
+// files not picked yet.
+func spread(findings []parser.Finding, n int, rng *rand.Rand) []parser.Finding {
+	pool := append([]parser.Finding{}, findings...)
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	checks, files := map[string]int{}, map[string]int{}
+	var picked []parser.Finding
+	for len(picked) < n {
+		best := 0
+		for i, f := range pool {
+			if cost(checks, files, f) < cost(checks, files, pool[best]) {
+				best = i
+			}
+		}
+		f := pool[best]
+		pool = append(pool[:best], pool[best+1:]...)
+		checks[f.Check]++
+		files[f.File]++
+		picked = append(picked, f)
+	}
+	return picked
+}
+
+func cost(checks, files map[string]int, f parser.Finding) int {
+	return 2*checks[f.Check] + files[f.File]
+}
+
+// Estimate is the false-positive rate of a set of findings, extrapolated
+// from the reviewed ones.
+type Estimate struct {
+	Rate     float64 // estimated share of false positives
+	Margin   float64 // half-width of the 95% confidence interval
+	Reviewed int
+	Rejected int
+	// Covered is the share of findings whose severity had reviews; the
+	// estimate says nothing about the others.
+	Covered float64
+}
+
+// EstimateFalsePositives extrapolates the verdicts in reviews to findings.
+// Each severity's rejection rate is weighted by its share of the findings,
+// matching how Sample stratifies. Reviews of findings not in findings are
+// ignored. It returns false when none of findings is reviewed.
+func EstimateFalsePositives(findings []parser.Finding, reviews []Review) (Estimate, bool) {
+	verdicts := map[string]string{}
+	for _, r := range reviews {
+		if r.Fingerprint != "" {
+			verdicts[r.Fingerprint] = r.Verdict
+		}
+	}
+	type stratum struct{ size, reviewed, rejected int }
+	strata := map[parser.Severity]*stratum{}
+	for _, f := range findings {
+		s, ok := strata[f.Severity]
+		if !ok {
+			s = &stratum{}
+			strata[f.Severity] = s
+		}
+		s.size++
+		switch verdicts[f.Fingerprint] {
+		case VerdictAccepted:
+			s.reviewed++
+		case VerdictRejected:
+			s.reviewed++
+			s.rejected++
+		}
+	}
+
+	var e Estimate
+	covered := 0
+	for _, s := range strata {
+		if s.reviewed > 0 {
+			covered += s.size
+			e.Reviewed += s.reviewed
+			e.Rejected += s.rejected
+		}
+	}
+	if covered == 0 {
+		return Estimate{}, false
+	}
+	variance := 0.0
+	for _, s := range strata {
+		if s.reviewed == 0 {
+			continue
+		}
+		w := float64(s.size) / float64(covered)
+		p := float64(s.rejected) / float64(s.reviewed)
+		e.Rate += w * p
+		// Finite population correction: reviewing all of a severity leaves
+		// no uncertainty about it.
+		fpc := 1 - float64(s.reviewed)/float64(s.size)
+		variance += w * w * p * (1 - p) / float64(s.reviewed) * fpc
+	}
+	e.Margin = 1.96 * math.Sqrt(variance)
+	e.Covered = float64(covered) / float64(len(findings))
+	return e, true
+}
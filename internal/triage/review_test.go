@@ -0,0 +1,86 @@
+package triage
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+func TestSample_Stratified(t *testing.T) {
+	var findings []parser.Finding
+	add := func(severity parser.Severity, n int, check string) {
+		for i := 0; i < n; i++ {
+			findings = append(findings, parser.Finding{
+				ID: fmt.Sprintf("%s-%d", check, i), Severity: severity, Check: check,
+				File: fmt.Sprintf("f%d.sol", i%3),
+			})
+		}
+	}
+	add(parser.SeverityHigh, 2, "reentrancy-eth")
+	add(parser.SeverityLow, 60, "naming-convention")
+	add(parser.SeverityLow, 20, "unused-state")
+	add(parser.SeverityInformational, 1, "solc-version")
+
+	sample := Sample(findings, 10, rand.New(rand.NewPCG(1, 2)))
+	require.Len(t, sample, 10)
+	counts := map[parser.Severity]int{}
+	checks := map[string]bool{}
+	for _, f := range sample {
+		counts[f.Severity]++
+		checks[f.Check] = true
+	}
+	assert.Equal(t, 1, counts[parser.SeverityInformational], "every severity is sampled")
+	assert.GreaterOrEqual(t, counts[parser.SeverityHigh], 1)
+	assert.Greater(t, counts[parser.SeverityLow], counts[parser.SeverityHigh], "the rest in proportion")
+	assert.True(t, checks["unused-state"], "picks spread over checks")
+	assert.Equal(t, parser.SeverityHigh, sample[0].Severity, "most severe first")
+
+	assert.Len(t, Sample(findings[:3], 10, rand.New(rand.NewPCG(1, 2))), 3, "never more than there are")
+}
+
+func TestEstimateFalsePositives(t *testing.T) {
+	var findings []parser.Finding
+	for i := 0; i < 10; i++ {
+		findings = append(findings, parser.Finding{Fingerprint: fmt.Sprintf("h%d", i), Severity: parser.SeverityHigh})
+		findings = append(findings, parser.Finding{Fingerprint: fmt.Sprintf("l%d", i), Severity: parser.SeverityLow})
+	}
+	findings = append(findings, parser.Finding{Fingerprint: "i0", Severity: parser.SeverityInformational})
+
+	_, ok := EstimateFalsePositives(findings, nil)
+	assert.False(t, ok)
+
+	reviews := []Review{
+		{Fingerprint: "h0", Verdict: VerdictAccepted},
+		{Fingerprint: "h1", Verdict: VerdictAccepted},
+		{Fingerprint: "l0", Verdict: VerdictRejected},
+		{Fingerprint: "l1", Verdict: VerdictAccepted},
+		{Fingerprint: "gone", Verdict: VerdictRejected},
+	}
+	e, ok := EstimateFalsePositives(findings, reviews)
+	require.True(t, ok)
+	assert.InDelta(t, 0.25, e.Rate, 1e-9, "0% of High and 50% of Low, equally weighted")
+	assert.Equal(t, 4, e.Reviewed)
+	assert.Equal(t, 1, e.Rejected)
+	assert.InDelta(t, 20.0/21, e.Covered, 1e-9, "Informational is not reviewed")
+	assert.Greater(t, e.Margin, 0.0)
+}
+
+func TestReviews_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultReviewPath)
+	reviews, err := LoadReviews(path)
+	require.NoError(t, err)
+	assert.Empty(t, reviews, "a missing log holds no reviews")
+
+	want := []Review{{Fingerprint: "3f9a1c2e", ID: "SLITHER-1", Check: "reentrancy-eth", Verdict: VerdictRejected, Note: "guarded by a mutex"}}
+	require.NoError(t, WriteReviews(path, want))
+	got, err := LoadReviews(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.True(t, Reviewed(got, parser.Finding{Fingerprint: "3f9a1c2e"}))
+	assert.False(t, Reviewed(got, parser.Finding{Fingerprint: "other"}))
+}
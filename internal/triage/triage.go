@@ -0,0 +1,166 @@
+// Package triage reads and writes Slither's triage database
+// (slither.db.json): the results a user has reviewed and hidden, which
+// Slither leaves out of later runs. solsec honours the same file, so findings
+// triaged in raw Slither workflows stay hidden in solsec and vice versa.
+package triage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// DefaultPath is where Slither looks for the database (--triage-database).
+const DefaultPath = "slither.db.json"
+
+// DB is a triage database. Entries are kept as read so fields solsec does
+// not model survive a rewrite.
+type DB struct {
+	raw     []json.RawMessage
+	entries []parser.SlitherDetector
+}
+
+// Load reads the database at path. A missing file is an empty database.
+func Load(path string) (*DB, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &DB{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading triage database: %w", err)
+	}
+	db := &DB{}
+	if err := json.Unmarshal(data, &db.raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	db.entries = make([]parser.SlitherDetector, len(db.raw))
+	for i, r := range db.raw {
+		if err := json.Unmarshal(r, &db.entries[i]); err != nil {
+			return nil, fmt.Errorf("parsing %s: result #%d: %w", path, i+1, err)
+		}
+	}
+	return db, nil
+}
+
+// Write saves the database at path, indented like Slither writes it.
+func (db *DB) Write(path string) error {
+	raw := db.raw
+	if raw == nil {
+		raw = []json.RawMessage{}
+	}
+	data, err := json.MarshalIndent(raw, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshalling triage database: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Len returns the number of triaged results.
+func (db *DB) Len() int { return len(db.entries) }
+
+// Hidden reports whether f has been triaged: an entry carries its Slither
+// result ID or its fingerprint, or reports the same check at the same
+// location (which survives Slither changing how it computes IDs).
+func (db *DB) Hidden(f parser.Finding) bool {
+	return db.index(f) >= 0
+}
+
+func (db *DB) index(f parser.Finding) int {
+	id := ID(f)
+	loc := location(f.Check, f.File, f.Lines)
+	for i, e := range db.entries {
+		if e.ID != "" && e.ID == id {
+			return i
+		}
+		if len(e.Elements) > 0 && loc != "" {
+			el := e.Elements[0].SourceMapping
+			if location(e.Check, el.Filename, el.Lines) == loc {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Add records f as triaged. It returns false if it already was.
+func (db *DB) Add(f parser.Finding) (bool, error) {
+	if db.Hidden(f) {
+		return false, nil
+	}
+	if ID(f) == "" {
+		return false, fmt.Errorf("finding %s has no fingerprint; re-run the analysis to get one", f.ID)
+	}
+	e := Entry(f)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return false, fmt.Errorf("marshalling triage entry for %s: %w", f.ID, err)
+	}
+	db.raw = append(db.raw, data)
+	db.entries = append(db.entries, e)
+	return true, nil
+}
+
+// Remove un-triages f. It returns false if f was not triaged.
+func (db *DB) Remove(f parser.Finding) bool {
+	i := db.index(f)
+	if i < 0 {
+		return false
+	}
+	db.raw = append(db.raw[:i], db.raw[i+1:]...)
+	db.entries = append(db.entries[:i], db.entries[i+1:]...)
+	return true
+}
+
+// ID is the identifier f is triaged under: Slither's own result ID for
+// Slither findings, so raw Slither recognizes the entry, and the fingerprint
+// otherwise.
+func ID(f parser.Finding) string {
+	if f.Source == "slither" && f.EngineID != "" {
+		return f.EngineID
+	}
+	return f.Fingerprint
+}
+
+// Entry renders f as a Slither detector result.
+func Entry(f parser.Finding) parser.SlitherDetector {
+	e := parser.SlitherDetector{
+		Check:       f.Check,
+		Impact:      string(f.Severity),
+		Confidence:  f.Confidence,
+		Description: f.Description,
+		ID:          ID(f),
+	}
+	if f.File != "" {
+		el := parser.DetectorElement{Type: "node"}
+		if f.Function != "" {
+			el.Type, el.Name = "function", f.Function
+		}
+		el.SourceMapping.Filename = f.File
+		if abs, err := filepath.Abs(f.File); err == nil {
+			el.SourceMapping.Filename = abs
+		}
+		el.SourceMapping.Lines = f.Lines
+		e.Elements = []parser.DetectorElement{el}
+	}
+	return e
+}
+
+// location keys a result by check, absolute file and first line, or is
+// empty when the result has no line.
+func location(check, file string, lines []int) string {
+	if file == "" || len(lines) == 0 {
+		return ""
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	return fmt.Sprintf("%s|%s|%d", check, file, lines[0])
+}
@@ -0,0 +1,88 @@
+package triage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/Zubimendi/solsec/internal/parser"
+)
+
+// slitherDB is a database as written by slither --triage-mode.
+const slitherDB = `[
+    {
+        "elements": [
+            {
+                "type": "function",
+                "name": "withdraw",
+                "source_mapping": {"start": 200, "length": 120, "filename_absolute": "/contracts/EtherStore.sol", "lines": [10, 11]},
+                "type_specific_fields": {"parent": {"type": "contract", "name": "EtherStore"}}
+            }
+        ],
+        "description": "EtherStore.withdraw() sends eth to arbitrary user",
+        "markdown": "EtherStore.withdraw() sends eth to arbitrary user",
+        "first_markdown_element": "EtherStore.sol#L10-L11",
+        "id": "abc123",
+        "check": "reentrancy-eth",
+        "impact": "High",
+        "confidence": "Medium"
+    }
+]`
+
+func TestLoad_SlitherDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	require.NoError(t, os.WriteFile(path, []byte(slitherDB), 0644))
+
+	db, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.Len())
+
+	byID := parser.Finding{Source: "slither", Check: "reentrancy-eth", EngineID: "abc123", File: "/contracts/Other.sol", Lines: []int{3}}
+	assert.True(t, db.Hidden(byID), "matched by Slither's result ID")
+	byLocation := parser.Finding{Source: "slither", Check: "reentrancy-eth", EngineID: "changed", File: "/contracts/EtherStore.sol", Lines: []int{10, 11}}
+	assert.True(t, db.Hidden(byLocation), "matched by check and location")
+	other := parser.Finding{Source: "slither", Check: "tx-origin", EngineID: "def456", File: "/contracts/EtherStore.sol", Lines: []int{10}}
+	assert.False(t, db.Hidden(other))
+
+	missing, err := Load(filepath.Join(t.TempDir(), DefaultPath))
+	require.NoError(t, err, "a missing database is empty")
+	assert.Zero(t, missing.Len())
+}
+
+func TestAddRemoveWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	require.NoError(t, os.WriteFile(path, []byte(slitherDB), 0644))
+	db, err := Load(path)
+	require.NoError(t, err)
+
+	custom := parser.Finding{ID: "CUSTOM-ACCESS-1", Source: "custom", Check: "custom-missing-access-control",
+		Severity: parser.SeverityCritical, Fingerprint: "3f9a1c2e", File: "/contracts/Token.sol", Lines: []int{7}, Function: "mint"}
+	added, err := db.Add(custom)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = db.Add(custom)
+	require.NoError(t, err)
+	assert.False(t, added, "already triaged")
+
+	_, err = db.Add(parser.Finding{ID: "OLD-1", Check: "x", Source: "custom"})
+	assert.Error(t, err, "a finding without a fingerprint cannot be triaged")
+
+	require.NoError(t, db.Write(path))
+	reloaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, reloaded.Len())
+	assert.True(t, reloaded.Hidden(custom))
+
+	// Slither's own fields survive the rewrite; solsec entries are Slither results.
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"first_markdown_element": "EtherStore.sol#L10-L11"`)
+	assert.Contains(t, string(data), `"id": "3f9a1c2e"`)
+	assert.Contains(t, string(data), `"impact": "Critical"`)
+
+	assert.True(t, reloaded.Remove(custom))
+	assert.False(t, reloaded.Remove(custom))
+	assert.Equal(t, 1, reloaded.Len())
+}
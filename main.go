@@ -1,7 +1,17 @@
 package main
 
-import "github.com/Zubimendi/solsec/cmd"
+import (
+	"io/fs"
+
+	"github.com/Zubimendi/solsec/cmd"
+)
 
 func main() {
+	if sub, err := fs.Sub(fixtures, "testdata/contracts"); err == nil {
+		cmd.SetFixtures(sub)
+	}
+	if sub, err := fs.Sub(fixtures, "testdata/benchmark"); err == nil {
+		cmd.SetBenchmarkCorpus(sub)
+	}
 	cmd.Execute()
-}
\ No newline at end of file
+}
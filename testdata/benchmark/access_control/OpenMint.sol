@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract OpenMint {
+    mapping(address => uint256) public balanceOf;
+    uint256 public totalSupply;
+
+    // <yes> <report> ACCESS_CONTROL
+    function mint(address to, uint256 amount) public {
+        balanceOf[to] += amount;
+        totalSupply += amount;
+    }
+}
@@ -0,0 +1,9 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.7.6;
+
+contract Suicidal {
+    function kill() external {
+        // <yes> <report> ACCESS_CONTROL
+        selfdestruct(payable(msg.sender));
+    }
+}
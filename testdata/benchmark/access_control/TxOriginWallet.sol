@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract TxOriginWallet {
+    address public owner;
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    function transferTo(address payable dest, uint256 amount) external {
+        // <yes> <report> ACCESS_CONTROL
+        require(tx.origin == owner);
+        dest.transfer(amount);
+    }
+}
@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.7.6;
+
+contract TimeLock {
+    mapping(address => uint256) public balances;
+    mapping(address => uint256) public lockTime;
+
+    function deposit() external payable {
+        balances[msg.sender] += msg.value;
+        lockTime[msg.sender] = block.timestamp + 1 weeks;
+    }
+
+    function increaseLockTime(uint256 secondsToIncrease) external {
+        // <yes> <report> ARITHMETIC
+        lockTime[msg.sender] = lockTime[msg.sender] + secondsToIncrease;
+    }
+}
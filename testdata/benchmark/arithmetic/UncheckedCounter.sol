@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract UncheckedCounter {
+    mapping(address => uint256) public balances;
+
+    function withdraw(uint256 amount) external {
+        unchecked {
+            // <yes> <report> ARITHMETIC
+            balances[msg.sender] = balances[msg.sender] - amount;
+        }
+    }
+}
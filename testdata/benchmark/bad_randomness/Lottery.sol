@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Lottery {
+    address[] public players;
+
+    function enter() external payable {
+        players.push(msg.sender);
+    }
+
+    function pickWinner() external {
+        // <yes> <report> BAD_RANDOMNESS
+        uint256 index = uint256(keccak256(abi.encodePacked(block.prevrandao, block.timestamp))) % players.length;
+        payable(players[index]).transfer(address(this).balance);
+    }
+}
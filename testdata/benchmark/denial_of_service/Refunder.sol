@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Refunder {
+    address payable[] public refundAddresses;
+    mapping(address => uint256) public refunds;
+
+    function refundAll() external {
+        for (uint256 i = 0; i < refundAddresses.length; i++) {
+            // <yes> <report> DENIAL_OF_SERVICE
+            require(refundAddresses[i].send(refunds[refundAddresses[i]]));
+        }
+    }
+}
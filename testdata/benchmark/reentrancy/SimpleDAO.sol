@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract SimpleDAO {
+    mapping(address => uint256) public credit;
+
+    function donate(address to) external payable {
+        credit[to] += msg.value;
+    }
+
+    function withdraw(uint256 amount) external {
+        if (credit[msg.sender] >= amount) {
+            // <yes> <report> REENTRANCY
+            (bool ok, ) = msg.sender.call{value: amount}("");
+            require(ok);
+            credit[msg.sender] -= amount;
+        }
+    }
+}
@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Roulette {
+    uint256 public pastBlockTime;
+
+    function spin() external payable {
+        require(msg.value == 10 ether);
+        require(block.timestamp != pastBlockTime);
+        pastBlockTime = block.timestamp;
+        // <yes> <report> TIME_MANIPULATION
+        if (block.timestamp % 15 == 0) {
+            payable(msg.sender).transfer(address(this).balance);
+        }
+    }
+}
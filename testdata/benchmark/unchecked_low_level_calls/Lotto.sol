@@ -0,0 +1,15 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Lotto {
+    address payable public winner;
+    uint256 public winAmount;
+    bool public payedOut;
+
+    function sendToWinner() external {
+        require(!payedOut);
+        // <yes> <report> UNCHECKED_LL_CALLS
+        winner.send(winAmount);
+        payedOut = true;
+    }
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+interface IERC20 {
+    function balanceOf(address account) external view returns (uint256);
+    function transfer(address to, uint256 amount) external returns (bool);
+}
+
+contract Pair {
+    address public immutable factory;
+    IERC20 public token0;
+    IERC20 public token1;
+    uint112 private reserve0;
+    uint112 private reserve1;
+    uint256 private unlocked = 1;
+
+    modifier lock() {
+        require(unlocked == 1, "locked");
+        unlocked = 0;
+        _;
+        unlocked = 1;
+    }
+
+    constructor() {
+        factory = msg.sender;
+    }
+
+    function swap(uint256 amount0Out, uint256 amount1Out, address to) external lock {
+        (uint112 _reserve0, uint112 _reserve1) = (reserve0, reserve1);
+        require(amount0Out < _reserve0 && amount1Out < _reserve1, "insufficient liquidity");
+        if (amount0Out > 0) token0.transfer(to, amount0Out);
+        if (amount1Out > 0) token1.transfer(to, amount1Out);
+        uint256 balance0 = token0.balanceOf(address(this));
+        uint256 balance1 = token1.balanceOf(address(this));
+        uint256 amount0In = balance0 > _reserve0 - amount0Out ? balance0 - (_reserve0 - amount0Out) : 0;
+        uint256 amount1In = balance1 > _reserve1 - amount1Out ? balance1 - (_reserve1 - amount1Out) : 0;
+        uint256 balance0Adjusted = balance0 * 1000 - amount0In * 3;
+        uint256 balance1Adjusted = balance1 * 1000 - amount1In * 3;
+        require(balance0Adjusted * balance1Adjusted >= uint256(_reserve0) * _reserve1 * 1000 ** 2, "K");
+        _update(balance0, balance1);
+    }
+
+    function skim(address to) external lock {
+        require(msg.sender == factory, "forbidden");
+        token0.transfer(to, token0.balanceOf(address(this)) - reserve0);
+        token1.transfer(to, token1.balanceOf(address(this)) - reserve1);
+    }
+
+    function _update(uint256 balance0, uint256 balance1) private {
+        reserve0 = uint112(balance0);
+        reserve1 = uint112(balance1);
+    }
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+interface IERC20 {
+    function balanceOf(address account) external view returns (uint256);
+    function transfer(address to, uint256 amount) external returns (bool);
+}
+
+contract Pair {
+    IERC20 public token0;
+    IERC20 public token1;
+    uint112 private reserve0;
+    uint112 private reserve1;
+
+    function swap(uint256 amount0Out, uint256 amount1Out, address to) external {
+        require(amount0Out < reserve0 && amount1Out < reserve1, "insufficient liquidity");
+        if (amount0Out > 0) token0.transfer(to, amount0Out);
+        if (amount1Out > 0) token1.transfer(to, amount1Out);
+        reserve0 = uint112(token0.balanceOf(address(this)));
+        reserve1 = uint112(token1.balanceOf(address(this)));
+    }
+
+    function skim(address to) external {
+        token0.transfer(to, token0.balanceOf(address(this)) - reserve0);
+        token1.transfer(to, token1.balanceOf(address(this)) - reserve1);
+    }
+}
+
+contract FeePair {
+    IERC20 public token0;
+    IERC20 public token1;
+    uint112 private reserve0;
+    uint112 private reserve1;
+
+    function swap(uint256 amount0Out, uint256 amount1Out, address to) external {
+        (uint112 _reserve0, uint112 _reserve1) = (reserve0, reserve1);
+        if (amount0Out > 0) token0.transfer(to, amount0Out);
+        if (amount1Out > 0) token1.transfer(to, amount1Out);
+        uint256 balance0 = token0.balanceOf(address(this));
+        uint256 balance1 = token1.balanceOf(address(this));
+        _update(balance0, balance1);
+        uint256 amount0In = balance0 > _reserve0 - amount0Out ? balance0 - (_reserve0 - amount0Out) : 0;
+        uint256 amount1In = balance1 > _reserve1 - amount1Out ? balance1 - (_reserve1 - amount1Out) : 0;
+        uint256 balance0Adjusted = balance0 * 1000 - amount0In * 3;
+        uint256 balance1Adjusted = balance1 * 1000 - amount1In * 3;
+        require(balance0Adjusted * balance1Adjusted >= uint256(reserve0) * reserve1 * 1000 ** 2, "K");
+    }
+
+    function _update(uint256 balance0, uint256 balance1) private {
+        reserve0 = uint112(balance0);
+        reserve1 = uint112(balance1);
+    }
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+interface ICrossDomainMessenger {
+    function xDomainMessageSender() external view returns (address);
+}
+
+contract L2Receiver {
+    ICrossDomainMessenger public immutable messenger;
+    address public immutable l1Bridge;
+    mapping(address => uint256) public balances;
+
+    constructor(ICrossDomainMessenger messenger_, address l1Bridge_) {
+        messenger = messenger_;
+        l1Bridge = l1Bridge_;
+    }
+
+    function finalizeDeposit(address to, uint256 amount) external {
+        require(msg.sender == address(messenger), "not messenger");
+        require(messenger.xDomainMessageSender() == l1Bridge, "not l1 bridge");
+        balances[to] += amount;
+    }
+}
+
+contract LzReceiver {
+    address public immutable endpoint;
+    mapping(uint16 => bytes) public trustedRemoteLookup;
+    mapping(address => uint256) public balances;
+
+    constructor(address endpoint_) {
+        endpoint = endpoint_;
+    }
+
+    function lzReceive(uint16 srcChainId, bytes calldata srcAddress, uint64 nonce, bytes calldata payload) external {
+        require(msg.sender == endpoint, "not endpoint");
+        require(keccak256(srcAddress) == keccak256(trustedRemoteLookup[srcChainId]), "untrusted source");
+        (address to, uint256 amount) = abi.decode(payload, (address, uint256));
+        balances[to] += amount;
+    }
+}
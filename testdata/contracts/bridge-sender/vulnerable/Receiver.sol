@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+interface ICrossDomainMessenger {
+    function xDomainMessageSender() external view returns (address);
+}
+
+contract L2Receiver {
+    ICrossDomainMessenger public immutable messenger;
+    mapping(address => uint256) public balances;
+
+    constructor(ICrossDomainMessenger messenger_) {
+        messenger = messenger_;
+    }
+
+    function finalizeDeposit(address to, uint256 amount) external {
+        require(msg.sender == address(messenger), "not messenger");
+        balances[to] += amount;
+    }
+}
+
+contract LzReceiver {
+    mapping(address => uint256) public balances;
+
+    function lzReceive(uint16 srcChainId, bytes calldata srcAddress, uint64 nonce, bytes calldata payload) external {
+        (address to, uint256 amount) = abi.decode(payload, (address, uint256));
+        balances[to] += amount;
+    }
+}
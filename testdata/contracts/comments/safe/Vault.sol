@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+/// @title Vault
+/// @notice Holds deposits; the balance is checked before every withdrawal.
+contract Vault {
+    mapping(address => uint256) public balances;
+
+    /*
+     * Withdrawals follow checks-effects-interactions.
+     * See the design notes in the repository for details.
+     */
+    function withdraw(uint256 amount) external {
+        require(balances[msg.sender] >= amount, "insufficient");
+        balances[msg.sender] -= amount;
+        payable(msg.sender).transfer(amount);
+    }
+}
@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Vault {
+    mapping(address => uint256) public balances;
+
+    function withdraw(uint256 amount) external {
+        // TODO: check the caller's balance
+        balances[msg.sender] -= amount;
+        payable(msg.sender).transfer(amount); // FIXME emit an event
+    }
+
+    // function emergencyWithdraw() external {
+    //     require(msg.sender == owner, "not owner");
+    //     uint256 amount = address(this).balance;
+    //     payable(owner).transfer(amount);
+    //     emit EmergencyWithdraw(amount);
+    // }
+}
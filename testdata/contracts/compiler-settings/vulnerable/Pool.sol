@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Pool {
+    function swap(uint256 a, uint256 b) external pure returns (uint256 out) {
+        {
+            // scoped to avoid stack too deep
+            out = a + b;
+        }
+    }
+}
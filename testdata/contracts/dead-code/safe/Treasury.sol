@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Treasury {
+    uint256 constant MAX_FEE = 50;
+    uint256 constant FEE_DENOMINATOR = 100;
+
+    address public owner;
+    uint256 public fee;
+    mapping(address => uint256) public balances;
+
+    modifier onlyOwner() {
+        if (msg.sender != owner) {
+            revert("not owner");
+        }
+        _;
+    }
+
+    modifier whenFunded() {
+        require(address(this).balance > 0, "empty");
+        _;
+    }
+
+    function deposit() external payable whenFunded {
+        balances[msg.sender] += msg.value;
+    }
+
+    function setFee(uint256 newFee) external onlyOwner {
+        require(newFee <= MAX_FEE, "fee too high");
+        fee = newFee;
+    }
+
+    function withdraw(uint256 amount) external {
+        if (amount == 0) {
+            return;
+        }
+        balances[msg.sender] -= amount;
+        emit Withdrawn(msg.sender, amount);
+        payable(msg.sender).transfer(amount);
+    }
+
+    function drain() external onlyOwner {
+        while (true) {
+            if (address(this).balance == 0) {
+                break;
+            }
+            payable(owner).transfer(address(this).balance);
+        }
+    }
+
+    event Withdrawn(address indexed account, uint256 amount);
+}
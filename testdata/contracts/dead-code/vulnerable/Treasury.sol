@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Treasury {
+    bool constant DEBUG = false;
+    uint256 constant MAX_FEE = 500;
+    uint256 constant FEE_DENOMINATOR = 100;
+
+    address public owner;
+    uint256 public fee;
+    mapping(address => uint256) public balances;
+
+    modifier onlyOwner() {
+        if (msg.sender == owner) {
+            _;
+        }
+    }
+
+    modifier whenFunded() {
+        require(address(this).balance > 0, "empty");
+    }
+
+    function deposit() external payable whenFunded {
+        balances[msg.sender] += msg.value;
+    }
+
+    function setFee(uint256 newFee) external onlyOwner {
+        require(newFee >= 0, "negative fee");
+        require(MAX_FEE <= FEE_DENOMINATOR, "bad config");
+        fee = newFee;
+    }
+
+    function withdraw(uint256 amount) external {
+        if (DEBUG) {
+            amount = balances[msg.sender];
+        }
+        balances[msg.sender] -= amount;
+        payable(msg.sender).transfer(amount);
+        return;
+        emit Withdrawn(msg.sender, amount);
+    }
+
+    event Withdrawn(address indexed account, uint256 amount);
+}
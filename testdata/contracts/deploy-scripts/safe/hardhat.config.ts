@@ -0,0 +1,11 @@
+import "@nomicfoundation/hardhat-toolbox";
+
+export default {
+  solidity: "0.8.24",
+  networks: {
+    mainnet: {
+      url: process.env.MAINNET_RPC_URL,
+      accounts: [process.env.DEPLOYER_KEY],
+    },
+  },
+};
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Script} from "forge-std/Script.sol";
+import {Vault} from "../Vault.sol";
+
+contract Deploy is Script {
+    function run() external {
+        address asset = vm.envAddress("ASSET");
+        address multisig = vm.envAddress("MULTISIG");
+
+        vm.startBroadcast();
+        Vault vault = new Vault(asset);
+        vault.transferOwnership(multisig);
+        vm.stopBroadcast();
+
+        require(vault.asset() == asset, "asset mismatch");
+        require(vault.owner() == multisig, "owner mismatch");
+    }
+}
@@ -0,0 +1,14 @@
+import { ethers, run } from "hardhat";
+
+const CONFIRMATIONS = 5;
+
+async function main() {
+  const asset = process.env.ASSET!;
+  const vault = await ethers.deployContract("Vault", [asset]);
+  await vault.deploymentTransaction()?.wait(CONFIRMATIONS);
+  await (await vault.transferOwnership(process.env.SAFE_ADDRESS!)).wait(CONFIRMATIONS);
+
+  await run("verify:verify", { address: await vault.getAddress(), constructorArguments: [asset] });
+}
+
+main().catch(console.error);
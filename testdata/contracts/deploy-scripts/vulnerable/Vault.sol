@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Ownable} from "@openzeppelin/contracts/access/Ownable.sol";
+
+contract Vault is Ownable {
+    address public immutable asset;
+
+    constructor(address asset_) Ownable(msg.sender) {
+        asset = asset_;
+    }
+
+    function sweep(address to) external onlyOwner {
+        payable(to).transfer(address(this).balance);
+    }
+}
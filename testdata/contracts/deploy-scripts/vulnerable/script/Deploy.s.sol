@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Script} from "forge-std/Script.sol";
+import {Vault} from "../Vault.sol";
+
+contract Deploy is Script {
+    function run() external {
+        uint256 pk = 0x83130b166fce3f9bf5dfdb3459c56c25b86fbeff8dc163eaef24223eb8f1631e;
+        vm.startBroadcast(pk);
+        new Vault(vm.envAddress("ASSET"));
+        vm.stopBroadcast();
+    }
+}
@@ -0,0 +1,9 @@
+import { ethers } from "hardhat";
+
+async function main() {
+  const vault = await ethers.deployContract("Vault", [process.env.ASSET]);
+  await vault.waitForDeployment();
+  console.log("Vault deployed to", await vault.getAddress());
+}
+
+main().catch(console.error);
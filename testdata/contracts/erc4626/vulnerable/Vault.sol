@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {ERC4626, ERC20, IERC20} from "@openzeppelin/contracts/token/ERC20/extensions/ERC4626.sol";
+import {Pausable} from "@openzeppelin/contracts/utils/Pausable.sol";
+import {Math} from "@openzeppelin/contracts/utils/math/Math.sol";
+
+contract CappedVault is ERC4626, Pausable {
+    using Math for uint256;
+
+    uint256 public depositCap;
+
+    constructor(IERC20 asset_, uint256 cap) ERC20("Capped Vault", "cVLT") ERC4626(asset_) {
+        depositCap = cap;
+    }
+
+    function previewMint(uint256 shares) public view override returns (uint256) {
+        return convertToAssets(shares);
+    }
+
+    function previewRedeem(uint256 shares) public view override returns (uint256) {
+        return shares.mulDiv(totalAssets() + 1, totalSupply() + 1, Math.Rounding.Ceil);
+    }
+
+    function maxDeposit(address) public view override returns (uint256) {
+        return depositCap - totalAssets();
+    }
+
+    function deposit(uint256 assets, address receiver) public override whenNotPaused returns (uint256) {
+        require(totalAssets() + assets <= depositCap, "cap exceeded");
+        return super.deposit(assets, receiver);
+    }
+
+    function withdraw(uint256 assets, address receiver, address owner) public override whenNotPaused returns (uint256) {
+        return super.withdraw(assets, receiver, owner);
+    }
+}
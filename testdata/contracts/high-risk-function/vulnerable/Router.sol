@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Router {
+    address public owner;
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    function execute(address target, bytes calldata data) external payable {
+        (bool ok, ) = target.call{value: msg.value}(data);
+        require(ok, "call failed");
+    }
+
+    function multicall(address target, bytes calldata data) external payable {
+        assembly {
+            calldatacopy(0, data.offset, data.length)
+        }
+        (bool ok, ) = target.call{value: msg.value}(data);
+        require(ok, "call failed");
+    }
+
+    function sweep(address target) external {
+        uint256 size;
+        assembly {
+            size := extcodesize(target)
+        }
+        (bool ok, ) = target.call(abi.encodeWithSignature("sweep()"));
+        require(ok && size > 0, "sweep failed");
+    }
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Modern {
+    uint256 public total;
+
+    function add(uint256 amount) external {
+        total = total + amount;
+    }
+}
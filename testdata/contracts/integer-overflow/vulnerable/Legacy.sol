@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.7.6;
+
+contract Legacy {
+    mapping(address => uint256) public balances;
+
+    function credit(address to, uint256 amount) external {
+        balances[to] = balances[to] + amount;
+    }
+}
@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Unchecked {
+    uint256 public total;
+
+    function add(uint256 amount) external {
+        unchecked {
+            total = total + amount;
+        }
+    }
+}
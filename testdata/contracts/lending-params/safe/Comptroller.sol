@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Comptroller {
+    struct Market {
+        bool listed;
+        uint256 collateralFactor;
+    }
+
+    uint256 internal constant MAX_COLLATERAL_FACTOR = 0.9e18;
+    uint256 internal constant MAX_RATE = 1e18;
+
+    address public owner;
+    mapping(address => Market) public markets;
+    uint256 public liquidationIncentive;
+    uint256 public baseRatePerSecond;
+    uint256 public multiplierPerSecond;
+
+    error InvalidRate();
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "not owner");
+        _;
+    }
+
+    function setCollateralFactor(address asset, uint256 newFactor) external onlyOwner {
+        Market storage market = markets[asset];
+        require(market.listed && newFactor <= MAX_COLLATERAL_FACTOR, "invalid factor");
+        market.collateralFactor = newFactor;
+    }
+
+    function setLiquidationIncentive(uint256 newIncentive) external onlyOwner {
+        require(newIncentive >= 1e18 && newIncentive <= 1.2e18, "invalid incentive");
+        liquidationIncentive = newIncentive;
+    }
+
+    function updateModel(uint256 baseRate, uint256 multiplier) external onlyOwner {
+        if (baseRate > MAX_RATE) revert InvalidRate();
+        _checkRate(multiplier);
+        baseRatePerSecond = baseRate / 365 days;
+        multiplierPerSecond = multiplier / 365 days;
+    }
+
+    function _checkRate(uint256 rate) internal pure {
+        if (rate > MAX_RATE) revert InvalidRate();
+    }
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Comptroller {
+    struct Market {
+        bool listed;
+        uint256 collateralFactor;
+    }
+
+    address public owner;
+    mapping(address => Market) public markets;
+    uint256 public liquidationIncentive;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "not owner");
+        _;
+    }
+
+    function setCollateralFactor(address asset, uint256 newFactor) external onlyOwner {
+        markets[asset].collateralFactor = newFactor;
+    }
+
+    function setLiquidationIncentive(uint256 newIncentive) external onlyOwner {
+        require(newIncentive <= 1.5e18, "incentive too high");
+        liquidationIncentive = newIncentive;
+    }
+}
+
+contract InterestRateModel {
+    address public owner;
+    uint256 public baseRatePerSecond;
+    uint256 public multiplierPerSecond;
+
+    function updateModel(uint256 baseRate, uint256 multiplier) external {
+        require(msg.sender == owner, "not owner");
+        baseRatePerSecond = baseRate / 365 days;
+        multiplierPerSecond = multiplier / 365 days;
+    }
+
+    function borrowRate(uint256 utilization) external view returns (uint256) {
+        return baseRatePerSecond + utilization * multiplierPerSecond / 1e18;
+    }
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {IERC20} from "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+import {MerkleProof} from "@openzeppelin/contracts/utils/cryptography/MerkleProof.sol";
+
+contract MerkleDistributor {
+    IERC20 public immutable token;
+    bytes32 public immutable merkleRoot;
+    mapping(uint256 => uint256) private claimedBitMap;
+
+    constructor(IERC20 token_, bytes32 merkleRoot_) {
+        token = token_;
+        merkleRoot = merkleRoot_;
+    }
+
+    function isClaimed(uint256 index) public view returns (bool) {
+        uint256 word = claimedBitMap[index / 256];
+        uint256 mask = 1 << (index % 256);
+        return word & mask == mask;
+    }
+
+    function _setClaimed(uint256 index) private {
+        claimedBitMap[index / 256] = claimedBitMap[index / 256] | (1 << (index % 256));
+    }
+
+    function claim(uint256 index, address account, uint256 amount, bytes32[] calldata proof) external {
+        require(!isClaimed(index), "already claimed");
+        bytes32 leaf = keccak256(bytes.concat(keccak256(abi.encode(index, account, amount))));
+        require(MerkleProof.verify(proof, merkleRoot, leaf), "invalid proof");
+        _setClaimed(index);
+        token.transfer(account, amount);
+    }
+}
+
+contract DelegatedDistributor {
+    using MerkleProof for bytes32[];
+
+    IERC20 public immutable token;
+    bytes32 public immutable merkleRoot;
+    mapping(address => uint256) public claimedAmount;
+
+    constructor(IERC20 token_, bytes32 merkleRoot_) {
+        token = token_;
+        merkleRoot = merkleRoot_;
+    }
+
+    function claim(address account, address to, uint256 cumulative, bytes32[] calldata proof) external {
+        require(account == msg.sender, "not account");
+        bytes32 leaf = keccak256(bytes.concat(keccak256(abi.encode(account, cumulative))));
+        require(proof.verify(merkleRoot, leaf), "invalid proof");
+        uint256 amount = cumulative - claimedAmount[account];
+        claimedAmount[account] = cumulative;
+        token.transfer(to, amount);
+    }
+}
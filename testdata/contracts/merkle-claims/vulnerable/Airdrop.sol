@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {IERC20} from "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+import {MerkleProof} from "@openzeppelin/contracts/utils/cryptography/MerkleProof.sol";
+
+contract Airdrop {
+    IERC20 public immutable token;
+    bytes32 public immutable merkleRoot;
+
+    constructor(IERC20 token_, bytes32 merkleRoot_) {
+        token = token_;
+        merkleRoot = merkleRoot_;
+    }
+
+    function claim(uint256 amount, bytes32[] calldata proof) external {
+        bytes32 leaf = keccak256(abi.encodePacked(msg.sender, amount));
+        require(MerkleProof.verify(proof, merkleRoot, leaf), "invalid proof");
+        token.transfer(msg.sender, amount);
+    }
+}
+
+contract Distributor {
+    IERC20 public immutable token;
+    bytes32 public immutable merkleRoot;
+    mapping(address => bool) public claimed;
+
+    constructor(IERC20 token_, bytes32 merkleRoot_) {
+        token = token_;
+        merkleRoot = merkleRoot_;
+    }
+
+    function claim(address account, address to, uint256 amount, bytes32[] calldata proof) external {
+        require(!claimed[account], "already claimed");
+        bytes32 leaf = keccak256(bytes.concat(keccak256(abi.encode(account, amount))));
+        require(MerkleProof.verify(proof, merkleRoot, leaf), "invalid proof");
+        claimed[account] = true;
+        token.transfer(to, amount);
+    }
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.6.12;
+
+contract LegacyVault {
+    mapping(address => uint256) public deposits;
+    mapping(address => uint256) public unlockAt;
+
+    function safeAdd(uint256 a, uint256 b) internal pure returns (uint256) {
+        uint256 c = a + b;
+        require(c >= a, "overflow");
+        return c;
+    }
+
+    function deposit(uint256 lockTime) external payable {
+        uint256 current = block.timestamp;
+        deposits[msg.sender] = safeAdd(deposits[msg.sender], msg.value);
+        unlockAt[msg.sender] = current + lockTime;
+    }
+
+    function withdraw(uint256 amount) external {
+        require(block.timestamp >= unlockAt[msg.sender]);
+        deposits[msg.sender] -= amount;
+        msg.sender.transfer(amount);
+    }
+}
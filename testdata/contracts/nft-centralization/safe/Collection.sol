@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {ERC721URIStorage, ERC721} from "@openzeppelin/contracts/token/ERC721/extensions/ERC721URIStorage.sol";
+import {ERC2981} from "@openzeppelin/contracts/token/common/ERC2981.sol";
+import {Ownable} from "@openzeppelin/contracts/access/Ownable.sol";
+
+contract Collection is ERC721URIStorage, ERC2981, Ownable {
+    uint96 public constant MAX_ROYALTY = 1000;
+
+    string private _baseTokenURI;
+    bool public metadataFrozen;
+    uint256 private _nextId;
+
+    constructor() ERC721("Collection", "COL") Ownable(msg.sender) {}
+
+    function setRoyalty(address receiver, uint96 feeNumerator) external onlyOwner {
+        require(feeNumerator <= MAX_ROYALTY, "royalty too high");
+        _setDefaultRoyalty(receiver, feeNumerator);
+    }
+
+    function setBaseURI(string calldata newBaseURI) external onlyOwner {
+        require(!metadataFrozen, "metadata frozen");
+        _baseTokenURI = newBaseURI;
+    }
+
+    function freezeMetadata() external onlyOwner {
+        metadataFrozen = true;
+    }
+
+    function mint(address to, string calldata uri) external onlyOwner {
+        uint256 tokenId = _nextId++;
+        _safeMint(to, tokenId);
+        _setTokenURI(tokenId, uri);
+    }
+
+    function supportsInterface(bytes4 interfaceId) public view override(ERC721URIStorage, ERC2981) returns (bool) {
+        return super.supportsInterface(interfaceId);
+    }
+}
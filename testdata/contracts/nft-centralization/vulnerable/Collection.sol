@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {ERC721URIStorage, ERC721} from "@openzeppelin/contracts/token/ERC721/extensions/ERC721URIStorage.sol";
+import {ERC2981} from "@openzeppelin/contracts/token/common/ERC2981.sol";
+import {Ownable} from "@openzeppelin/contracts/access/Ownable.sol";
+
+interface IRenderer {
+    function render(uint256 tokenId) external view returns (string memory);
+}
+
+contract Collection is ERC721URIStorage, ERC2981, Ownable {
+    string private _baseTokenURI;
+    IRenderer public renderer;
+
+    constructor() ERC721("Collection", "COL") Ownable(msg.sender) {}
+
+    function setRoyalty(address receiver, uint96 feeNumerator) external onlyOwner {
+        _setDefaultRoyalty(receiver, feeNumerator);
+    }
+
+    function setBaseURI(string calldata newBaseURI) external onlyOwner {
+        _baseTokenURI = newBaseURI;
+    }
+
+    function setTokenURI(uint256 tokenId, string calldata newURI) external onlyOwner {
+        _setTokenURI(tokenId, newURI);
+    }
+
+    function setRenderer(IRenderer newRenderer) external onlyOwner {
+        renderer = newRenderer;
+    }
+
+    function tokenURI(uint256 tokenId) public view override returns (string memory) {
+        return renderer.render(tokenId);
+    }
+
+    function supportsInterface(bytes4 interfaceId) public view override(ERC721URIStorage, ERC2981) returns (bool) {
+        return super.supportsInterface(interfaceId);
+    }
+}
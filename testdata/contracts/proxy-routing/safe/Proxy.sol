@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Initializable} from "@openzeppelin/contracts-upgradeable/proxy/utils/Initializable.sol";
+
+contract SimpleProxy {
+    address public implementation;
+
+    constructor(address impl) {
+        implementation = impl;
+    }
+
+    receive() external payable {
+        _fallback();
+    }
+
+    fallback() external payable {
+        _fallback();
+    }
+
+    function _fallback() internal {
+        _delegate(implementation);
+    }
+
+    function _delegate(address impl) internal {
+        assembly {
+            calldatacopy(0, 0, calldatasize())
+            let result := delegatecall(gas(), impl, 0, calldatasize(), 0, 0)
+            returndatacopy(0, 0, returndatasize())
+            switch result
+            case 0 { revert(0, returndatasize()) }
+            default { return(0, returndatasize()) }
+        }
+    }
+}
+
+contract VaultV1 is Initializable {
+    mapping(address => uint256) public deposits;
+
+    function initialize() external initializer {}
+
+    receive() external payable {
+        deposits[msg.sender] += msg.value;
+    }
+}
+
+contract VaultV2 is Initializable {
+    mapping(address => uint256) public deposits;
+
+    function initialize() external initializer {}
+
+    receive() external payable {
+        deposits[msg.sender] += msg.value;
+    }
+
+    function deposit() external payable {
+        deposits[msg.sender] += msg.value;
+    }
+}
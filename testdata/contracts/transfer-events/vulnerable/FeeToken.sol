@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+contract FeeToken {
+    mapping(address => uint256) private _balances;
+    uint256 public totalSupply;
+    address public owner;
+    address public treasury;
+    uint256 public feeBps = 100;
+
+    event Transfer(address indexed from, address indexed to, uint256 value);
+
+    constructor(uint256 supply) {
+        owner = msg.sender;
+        _balances[msg.sender] = supply;
+        totalSupply = supply;
+    }
+
+    function transfer(address to, uint256 amount) external returns (bool) {
+        uint256 fee = amount * feeBps / 10_000;
+        _balances[msg.sender] -= amount;
+        _balances[to] += amount - fee;
+        _balances[treasury] += fee;
+        emit Transfer(msg.sender, to, amount);
+        return true;
+    }
+
+    function burn(uint256 amount) external {
+        _balances[msg.sender] -= amount;
+        totalSupply -= amount;
+    }
+
+    function airdrop(address[] calldata recipients, uint256 amount) external {
+        for (uint256 i = 0; i < recipients.length; i++) {
+            emit Transfer(owner, recipients[i], amount);
+        }
+    }
+
+    function mint(address to, uint256 amount) external {
+        require(msg.sender == owner, "not owner");
+        uint256 minted = amount * 2;
+        totalSupply += minted;
+        _balances[to] += amount;
+        emit Transfer(address(0), to, minted);
+    }
+}
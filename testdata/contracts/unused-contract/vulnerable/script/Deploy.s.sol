@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "../src/Vault.sol";
+
+contract Deploy {
+    function run() external {
+        new Vault();
+    }
+}
@@ -0,0 +1,8 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "./IVault.sol";
+
+contract Vault is IVault {
+    function deposit() external payable {}
+}
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+interface IERC20 {
+    function balanceOf(address account) external view returns (uint256);
+    function transferFrom(address from, address to, uint256 amount) external returns (bool);
+}
+
+contract Vault {
+    uint256 private constant VIRTUAL_SHARES = 1e3;
+
+    IERC20 public immutable asset;
+    uint256 public totalSupply;
+    mapping(address => uint256) public balanceOf;
+
+    constructor(IERC20 asset_) {
+        asset = asset_;
+    }
+
+    function totalAssets() public view returns (uint256) {
+        return asset.balanceOf(address(this));
+    }
+
+    function deposit(uint256 assets) external returns (uint256 shares) {
+        shares = assets * (totalSupply + VIRTUAL_SHARES) / (totalAssets() + 1);
+        require(asset.transferFrom(msg.sender, address(this), assets), "transfer failed");
+        totalSupply += shares;
+        balanceOf[msg.sender] += shares;
+    }
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {IERC20} from "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+
+contract TokenVesting {
+    IERC20 public immutable token;
+    address public owner;
+    address public beneficiary;
+    uint64 public start;
+    uint64 public duration;
+    uint256 public released;
+    bool public revoked;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "not owner");
+        _;
+    }
+
+    constructor(IERC20 token_, address owner_) {
+        token = token_;
+        owner = owner_;
+    }
+
+    function setSchedule(address beneficiary_, uint64 start_, uint64 duration_) external onlyOwner {
+        require(beneficiary == address(0), "schedule already set");
+        beneficiary = beneficiary_;
+        start = start_;
+        duration = duration_;
+    }
+
+    function transferGrant(address newBeneficiary) external {
+        require(msg.sender == beneficiary, "not beneficiary");
+        beneficiary = newBeneficiary;
+    }
+
+    function vestedAmount() public view returns (uint256) {
+        uint256 total = token.balanceOf(address(this)) + released;
+        if (block.timestamp >= start + duration) {
+            return total;
+        }
+        return total * (block.timestamp - start) / duration;
+    }
+
+    function revoke() external onlyOwner {
+        uint256 releasable = vestedAmount() - released;
+        uint256 refund = token.balanceOf(address(this)) - releasable;
+        revoked = true;
+        token.transfer(owner, refund);
+    }
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import {IERC20} from "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+
+contract TokenVesting {
+    IERC20 public immutable token;
+    address public owner;
+    address public beneficiary;
+    uint64 public start;
+    uint64 public duration;
+    uint256 public released;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "not owner");
+        _;
+    }
+
+    constructor(IERC20 token_, address beneficiary_, uint64 start_, uint64 duration_) {
+        token = token_;
+        owner = msg.sender;
+        beneficiary = beneficiary_;
+        start = start_;
+        duration = duration_;
+    }
+
+    function setBeneficiary(address newBeneficiary) external onlyOwner {
+        beneficiary = newBeneficiary;
+    }
+
+    function extendVesting(uint64 newDuration) external onlyOwner {
+        duration = newDuration;
+    }
+
+    function release() external {
+        uint256 total = token.balanceOf(address(this)) + released;
+        uint256 amount = total * (block.timestamp - start) / duration - released;
+        released += amount;
+        token.transfer(beneficiary, amount);
+    }
+
+    function revoke() external onlyOwner {
+        token.transfer(owner, token.balanceOf(address(this)));
+    }
+}